@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/riabininkf/go-modules/cmd"
+	"github.com/riabininkf/go-modules/di"
+	"github.com/spf13/cobra"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+func init() {
+	cmd.RegisterCommand(func(ctn di.Container) *cmd.Command {
+		auditCmd := &cmd.Command{
+			Use:   "audit",
+			Short: "Verify and export the audit trail",
+		}
+
+		auditCmd.AddCommand(
+			auditVerify(ctn),
+			auditExport(ctn),
+			auditVerifyBundle(),
+			auditPublicKey(ctn),
+		)
+
+		return auditCmd
+	})
+}
+
+func auditVerify(ctn di.Container) *cmd.Command {
+	return &cmd.Command{
+		Use:   "verify",
+		Short: "Check that no audit event was edited, removed or reordered and report the first broken link",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var events *repository.AuditEvents
+			if err := ctn.Fill(repository.DefAuditEventsName, &events); err != nil {
+				return err
+			}
+
+			chain := audit.NewChain()
+			if err := events.Each(cmd.Context(), chain.Append); err != nil {
+				return err
+			}
+
+			printChain(cmd, "verified", chain)
+
+			return nil
+		},
+	}
+}
+
+func auditExport(ctn di.Container) *cmd.Command {
+	exportCmd := &cmd.Command{
+		Use:   "export",
+		Short: "Export the audit trail to a JSONL bundle signed with auth.audit.signingKey",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outputPath, err := cmd.Flags().GetString("output")
+			if err != nil {
+				return err
+			}
+
+			var events *repository.AuditEvents
+			if err = ctn.Fill(repository.DefAuditEventsName, &events); err != nil {
+				return err
+			}
+
+			var signer *audit.Signer
+			if err = ctn.Fill(audit.DefSignerName, &signer); err != nil {
+				return err
+			}
+
+			output := cmd.OutOrStdout()
+			var file *os.File
+			if outputPath != stdio {
+				if file, err = os.Create(outputPath); err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+
+				output = file
+			}
+
+			bundle := audit.NewBundleWriter(output, signer)
+			if err = events.Each(cmd.Context(), bundle.Write); err == nil {
+				err = bundle.Close()
+			}
+
+			if file != nil {
+				if closeErr := file.Close(); err == nil {
+					err = closeErr
+				}
+			}
+
+			if err != nil {
+				return err
+			}
+
+			printChain(cmd, "exported", bundle.Chain())
+
+			return nil
+		},
+	}
+
+	exportCmd.Flags().StringP("output", "o", stdio, `path to the file to write, "-" for stdout`)
+
+	return exportCmd
+}
+
+func auditVerifyBundle() *cmd.Command {
+	verifyCmd := &cmd.Command{
+		Use:   "verify-bundle",
+		Short: "Verify an exported bundle offline with the public key of the service",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				err           error
+				inputPath     string
+				publicKeyPath string
+			)
+			if inputPath, err = cmd.Flags().GetString("input"); err != nil {
+				return err
+			}
+
+			if publicKeyPath, err = cmd.Flags().GetString("public-key"); err != nil {
+				return err
+			}
+
+			var publicKeyPEM []byte
+			if publicKeyPEM, err = os.ReadFile(publicKeyPath); err != nil {
+				return fmt.Errorf("failed to read public key: %w", err)
+			}
+
+			publicKey, err := audit.ParsePublicKey(string(publicKeyPEM))
+			if err != nil {
+				return err
+			}
+
+			input := cmd.InOrStdin()
+			if inputPath != stdio {
+				var file *os.File
+				if file, err = os.Open(inputPath); err != nil {
+					return fmt.Errorf("failed to open input file: %w", err)
+				}
+
+				defer func() { _ = file.Close() }()
+				input = file
+			}
+
+			chain, err := audit.VerifyBundle(input, publicKey)
+			if err != nil {
+				return err
+			}
+
+			printChain(cmd, "verified", chain)
+
+			return nil
+		},
+	}
+
+	verifyCmd.Flags().StringP("input", "i", stdio, `path to the bundle, "-" for stdin`)
+	verifyCmd.Flags().StringP("public-key", "k", "", "path to the PEM-encoded public key printed by audit public-key")
+	_ = verifyCmd.MarkFlagRequired("public-key")
+
+	return verifyCmd
+}
+
+func auditPublicKey(ctn di.Container) *cmd.Command {
+	return &cmd.Command{
+		Use:   "public-key",
+		Short: "Print the public key that verifies exported bundles",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var signer *audit.Signer
+			if err := ctn.Fill(audit.DefSignerName, &signer); err != nil {
+				return err
+			}
+
+			publicKeyPEM, err := signer.PublicKeyPEM()
+			if err != nil {
+				return err
+			}
+
+			cmd.Print(string(publicKeyPEM))
+
+			return nil
+		},
+	}
+}
+
+// printChain reports the number of events of the chain and its head, which auditors can compare with earlier exports
+// to make sure that events were not removed from the end of the trail.
+func printChain(cmd *cobra.Command, verb string, chain *audit.Chain) {
+	cmd.PrintErrf("%s %d events, %d saved before hashing, head %s\n", verb, chain.Events(), chain.Unchained(), chain.Head())
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+
+	"github.com/riabininkf/go-modules/cmd"
+	"github.com/riabininkf/go-modules/di"
+	"github.com/spf13/cobra"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+const defaultBloomFalsePositiveRate = 0.001
+
+func init() {
+	cmd.RegisterCommand(func(ctn di.Container) *cmd.Command {
+		breachedCmd := &cmd.Command{
+			Use:   "breached",
+			Short: "Manage the list of breached passwords",
+		}
+
+		breachedCmd.AddCommand(
+			breachedBuild(),
+		)
+
+		return breachedCmd
+	})
+}
+
+func breachedBuild() *cmd.Command {
+	buildCmd := &cmd.Command{
+		Use:   "build",
+		Short: "Build a Bloom filter file from a list of SHA-1 hashes in the HIBP format",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				err               error
+				inputPath         string
+				outputPath        string
+				falsePositiveRate float64
+			)
+			if inputPath, err = cmd.Flags().GetString("input"); err != nil {
+				return err
+			}
+
+			if outputPath, err = cmd.Flags().GetString("output"); err != nil {
+				return err
+			}
+
+			if falsePositiveRate, err = cmd.Flags().GetFloat64("false-positive-rate"); err != nil {
+				return err
+			}
+
+			var input *os.File
+			if input, err = os.Open(inputPath); err != nil {
+				return fmt.Errorf("failed to open hash list: %w", err)
+			}
+
+			defer func() { _ = input.Close() }()
+
+			var filter *password.BloomFilter
+			if filter, err = password.BuildBloomFilter(input, falsePositiveRate); err != nil {
+				return fmt.Errorf("failed to build bloom filter: %w", err)
+			}
+
+			var output *os.File
+			if output, err = os.Create(outputPath); err != nil {
+				return fmt.Errorf("failed to create bloom filter file: %w", err)
+			}
+
+			writer := bufio.NewWriter(output)
+			if _, err = filter.WriteTo(writer); err == nil {
+				err = writer.Flush()
+			}
+
+			if closeErr := output.Close(); err == nil {
+				err = closeErr
+			}
+
+			if err != nil {
+				return fmt.Errorf("failed to write bloom filter: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	buildCmd.Flags().StringP("input", "i", "", "path to the list of SHA-1 hashes, one HASH or HASH:COUNT per line")
+	buildCmd.Flags().StringP("output", "o", "", "path to the Bloom filter file to write")
+	buildCmd.Flags().Float64("false-positive-rate", defaultBloomFalsePositiveRate, "share of unlisted passwords reported as breached")
+	_ = buildCmd.MarkFlagRequired("input")
+	_ = buildCmd.MarkFlagRequired("output")
+
+	return buildCmd
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"context"
 	"errors"
+	"expvar"
 	"net"
 	"net/http"
 	"strconv"
@@ -14,9 +15,11 @@ import (
 	"github.com/riabininkf/go-modules/logger"
 	"github.com/spf13/cobra"
 
+	"github.com/riabininkf/http-auth-example/internal/account"
 	handlers "github.com/riabininkf/http-auth-example/internal/http"
 	"github.com/riabininkf/http-auth-example/internal/http/middleware"
 	"github.com/riabininkf/http-auth-example/internal/jwt"
+	"github.com/riabininkf/http-auth-example/internal/ratelimit"
 )
 
 const (
@@ -24,13 +27,48 @@ const (
 
 	configKeyHttpPort            = "http.port"
 	configKeyHttpShutdownTimeout = "http.shutdownTimeout"
+	configKeyHttpTrustProxy      = "http.trustProxy"
+	configKeyHttpDebugAddr       = "http.debugAddr"
 )
 
 func registerHttpRoutes(mux *http.ServeMux, service *handlers.Service) {
 	mux.HandleFunc("POST /v1/auth/login", service.LoginV1())
 	mux.HandleFunc("POST /v1/auth/refresh", service.RefreshV1())
+	mux.HandleFunc("POST /v1/auth/login/mfa", service.LoginMFAV1())
+	mux.HandleFunc("POST /v1/auth/webauthn/login/begin", service.BeginWebAuthnLoginV1())
+	mux.HandleFunc("POST /v1/auth/webauthn/login/finish", service.FinishWebAuthnLoginV1())
 	mux.HandleFunc("POST /v1/auth/register", service.RegisterV1())
+	mux.HandleFunc("POST /v1/auth/email/verify", service.VerifyEmailV1())
+	mux.HandleFunc("POST /v1/auth/email/verify/resend", service.ResendEmailVerificationV1())
+	mux.HandleFunc("POST /v1/auth/login/email", service.StartEmailLoginV1())
+	mux.HandleFunc("POST /v1/auth/login/email/verify", service.FinishEmailLoginV1())
+	mux.HandleFunc("POST /v1/auth/password/forgot", service.ForgotPasswordV1())
+	mux.HandleFunc("POST /v1/auth/password/reset", service.ResetPasswordV1())
+	mux.HandleFunc("POST /v1/auth/email/change/confirm", service.ConfirmEmailChangeV1())
+	mux.HandleFunc("POST /v1/auth/email/change/undo", service.UndoEmailChangeV1())
+	mux.HandleFunc("GET /v1/user/me", service.GetProfileV1())
+	mux.HandleFunc("PATCH /v1/user/me", service.UpdateProfileV1())
+	mux.HandleFunc("POST /v1/user/email", service.ChangeEmailV1())
 	mux.HandleFunc("POST /v1/user/password", service.UpdatePasswordV1())
+	mux.HandleFunc("POST /v1/user/mfa/totp", service.EnrollTOTPV1())
+	mux.HandleFunc("POST /v1/user/mfa/totp/confirm", service.ConfirmTOTPV1())
+	mux.HandleFunc("POST /v1/user/mfa/recovery-codes", service.RegenerateRecoveryCodesV1())
+	mux.HandleFunc("POST /v1/user/webauthn/register/begin", service.BeginWebAuthnRegistrationV1())
+	mux.HandleFunc("POST /v1/user/webauthn/register/finish", service.FinishWebAuthnRegistrationV1())
+	mux.HandleFunc("GET /v1/user/security-events", service.SecurityEventsV1())
+	mux.HandleFunc("POST /v1/admin/users/unlock", service.UnlockUserV1())
+	mux.HandleFunc("GET /v1/admin/password-hashing", service.PasswordHashingV1())
+	mux.HandleFunc("GET /oauth/authorize", service.Authorize())
+	mux.HandleFunc("POST /oauth/authorize", service.Authorize())
+	mux.HandleFunc("POST /v1/oauth/token", service.TokenV1())
+	mux.HandleFunc("POST /v1/oauth/device/code", service.DeviceCodeV1())
+	mux.HandleFunc("GET /oauth/device", service.DeviceVerification())
+	mux.HandleFunc("POST /oauth/device", service.DeviceVerification())
+}
+
+// registerDebugRoutes registers routes served only on the internal debug listener.
+func registerDebugRoutes(mux *http.ServeMux) {
+	mux.Handle("GET /debug/vars", expvar.Handler())
 }
 
 func init() {
@@ -72,12 +110,24 @@ func init() {
 					return err
 				}
 
+				var passwordReset *account.PasswordReset
+				if err := ctn.Fill(account.DefPasswordResetName, &passwordReset); err != nil {
+					return err
+				}
+
+				var rateLimitPolicy *ratelimit.Policy
+				if err := ctn.Fill(ratelimit.DefPolicyName, &rateLimitPolicy); err != nil {
+					return err
+				}
+
 				server := &http.Server{
 					Addr: net.JoinHostPort("", strconv.Itoa(port)),
 					Handler: middleware.Chain(
 						multiplexer,
 						middleware.Logging(log),
+						middleware.Client(ratelimit.ByIP(cfg.GetBool(configKeyHttpTrustProxy))),
 						middleware.Auth(log, authenticator),
+						middleware.RateLimit(log, rateLimitPolicy),
 					),
 				}
 
@@ -95,12 +145,49 @@ func init() {
 
 				}()
 
+				// runtime metrics are served without authentication, so only on a separate listener
+				// that is not exposed publicly
+				var debugServer *http.Server
+				if debugAddr := cfg.GetString(configKeyHttpDebugAddr); debugAddr != "" {
+					debugMultiplexer := http.NewServeMux()
+
+					registerDebugRoutes(debugMultiplexer)
+
+					debugServer = &http.Server{Addr: debugAddr, Handler: debugMultiplexer}
+
+					go func() {
+						log.Info("debug http server started", logger.String("addr", debugAddr))
+
+						if err := debugServer.ListenAndServe(); err != nil {
+							if errors.Is(err, http.ErrServerClosed) {
+								log.Info("debug http server stopped", logger.String("addr", debugAddr))
+								return
+							}
+
+							log.Error("debug http server stopped with an error", logger.Error(err))
+						}
+					}()
+				}
+
 				<-cmd.Context().Done()
 
 				ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
 				defer cancel()
 
-				return server.Shutdown(ctx)
+				if debugServer != nil {
+					if err := debugServer.Shutdown(ctx); err != nil {
+						log.Error("failed to shut down debug http server", logger.Error(err))
+					}
+				}
+
+				if err := server.Shutdown(ctx); err != nil {
+					return err
+				}
+
+				// password resets are emailed after the response, so the last ones may still be on their way
+				passwordReset.Wait()
+
+				return nil
 			},
 		}
 	})
@@ -0,0 +1,238 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/riabininkf/go-modules/cmd"
+	"github.com/riabininkf/go-modules/di"
+	"github.com/spf13/cobra"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/password"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+const (
+	defaultImportBatchSize = 5000
+
+	// stdio stands for stdin or stdout in place of a file path.
+	stdio = "-"
+)
+
+func init() {
+	cmd.RegisterCommand(func(ctn di.Container) *cmd.Command {
+		usersCmd := &cmd.Command{
+			Use:   "users",
+			Short: "Import and export users",
+		}
+
+		usersCmd.AddCommand(
+			usersImport(ctn),
+			usersExport(ctn),
+		)
+
+		return usersCmd
+	})
+}
+
+func usersImport(ctn di.Container) *cmd.Command {
+	importCmd := &cmd.Command{
+		Use:   "import",
+		Short: "Import users with existing password hashes from a CSV or JSONL file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				err        error
+				inputPath  string
+				format     string
+				reportPath string
+				batchSize  int
+				dryRun     bool
+			)
+			if inputPath, err = cmd.Flags().GetString("input"); err != nil {
+				return err
+			}
+
+			if format, err = recordFormat(cmd, inputPath); err != nil {
+				return err
+			}
+
+			if reportPath, err = cmd.Flags().GetString("report"); err != nil {
+				return err
+			}
+
+			if batchSize, err = cmd.Flags().GetInt("batch-size"); err != nil {
+				return err
+			}
+
+			if batchSize <= 0 {
+				return fmt.Errorf("batch size must be positive, got %d", batchSize)
+			}
+
+			if dryRun, err = cmd.Flags().GetBool("dry-run"); err != nil {
+				return err
+			}
+
+			var usersRep *repository.Users
+			if err = ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+				return err
+			}
+
+			var hasher *password.Hasher
+			if err = ctn.Fill(password.DefHasherName, &hasher); err != nil {
+				return err
+			}
+
+			input := io.Reader(cmd.InOrStdin())
+			if inputPath != stdio {
+				var file *os.File
+				if file, err = os.Open(inputPath); err != nil {
+					return fmt.Errorf("failed to open input file: %w", err)
+				}
+
+				defer func() { _ = file.Close() }()
+				input = file
+			}
+
+			var records account.UserRecordReader
+			if records, err = account.NewUserRecordReader(bufio.NewReader(input), format); err != nil {
+				return err
+			}
+
+			reportOutput := cmd.ErrOrStderr()
+			if reportPath != "" {
+				var file *os.File
+				if file, err = os.Create(reportPath); err != nil {
+					return fmt.Errorf("failed to create report file: %w", err)
+				}
+
+				defer func() { _ = file.Close() }()
+				reportOutput = file
+			}
+
+			// rejected records are reported as CSV rows, so that they can be fixed and imported again
+			report := csv.NewWriter(reportOutput)
+			if err = report.Write([]string{"line", "email", "reason"}); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+
+			var summary account.ImportReport
+			summary, err = account.NewUserImporter(usersRep, hasher, batchSize).
+				Import(cmd.Context(), records, dryRun, func(rejection account.Rejection) {
+					_ = report.Write([]string{strconv.Itoa(rejection.Line), rejection.Email, rejection.Reason})
+				})
+
+			report.Flush()
+			if reportErr := report.Error(); err == nil && reportErr != nil {
+				err = fmt.Errorf("failed to write report: %w", reportErr)
+			}
+
+			verb := "imported"
+			if dryRun {
+				verb = "would import"
+			}
+
+			cmd.Printf("read %d records, %s %d users, rejected %d\n", summary.Read, verb, summary.Imported, summary.Rejected)
+
+			return err
+		},
+	}
+
+	importCmd.Flags().StringP("input", "i", "", `path to the file to import, "-" for stdin`)
+	importCmd.Flags().StringP("format", "f", "", "csv or jsonl, guessed from the file extension by default")
+	importCmd.Flags().StringP("report", "r", "", "path to the CSV report of rejected records, stderr by default")
+	importCmd.Flags().Int("batch-size", defaultImportBatchSize, "number of users inserted with a single COPY")
+	importCmd.Flags().Bool("dry-run", false, "validate the file and check for existing users without inserting anything")
+	_ = importCmd.MarkFlagRequired("input")
+
+	return importCmd
+}
+
+func usersExport(ctn di.Container) *cmd.Command {
+	exportCmd := &cmd.Command{
+		Use:   "export",
+		Short: "Export all users to a CSV or JSONL file",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var (
+				err        error
+				outputPath string
+				format     string
+				withHashes bool
+			)
+			if outputPath, err = cmd.Flags().GetString("output"); err != nil {
+				return err
+			}
+
+			if format, err = recordFormat(cmd, outputPath); err != nil {
+				return err
+			}
+
+			if withHashes, err = cmd.Flags().GetBool("with-password-hashes"); err != nil {
+				return err
+			}
+
+			var usersRep *repository.Users
+			if err = ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+				return err
+			}
+
+			output := cmd.OutOrStdout()
+			var file *os.File
+			if outputPath != stdio {
+				if file, err = os.Create(outputPath); err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+
+				output = file
+			}
+
+			var exported int
+			var records account.UserRecordWriter
+			if records, err = account.NewUserRecordWriter(output, format, withHashes); err == nil {
+				exported, err = account.NewUserExporter(usersRep).Export(cmd.Context(), records)
+			}
+
+			if file != nil {
+				if closeErr := file.Close(); err == nil {
+					err = closeErr
+				}
+			}
+
+			if err != nil {
+				return err
+			}
+
+			cmd.PrintErrf("exported %d users\n", exported)
+
+			return nil
+		},
+	}
+
+	exportCmd.Flags().StringP("output", "o", stdio, `path to the file to write, "-" for stdout`)
+	exportCmd.Flags().StringP("format", "f", "", "csv or jsonl, guessed from the file extension by default")
+	exportCmd.Flags().Bool("with-password-hashes", false, "include password hashes, e.g. to import the users elsewhere")
+
+	return exportCmd
+}
+
+// recordFormat returns the format of user records set with the format flag or guessed from the path.
+// Records are read from stdin and written to stdout as JSONL by default.
+func recordFormat(cmd *cobra.Command, path string) (string, error) {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case format != "":
+		return format, nil
+	case path == stdio:
+		return account.FormatJSONL, nil
+	default:
+		return account.FormatFromPath(path)
+	}
+}
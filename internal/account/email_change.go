@@ -0,0 +1,218 @@
+package account
+
+//go:generate mockery --name EmailChangeCache --output ./mocks --outpkg mocks --filename email_change_cache.go --structname EmailChangeCache
+//go:generate mockery --name EmailChangeUsers --output ./mocks --outpkg mocks --filename email_change_users.go --structname EmailChangeUsers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+// ErrEmailChangeUndoable is returned when the email of a user is changed again while the previous change
+// can still be undone.
+var ErrEmailChangeUndoable = errors.New("previous email change can still be undone")
+
+const emailChangeUndoWindowKeyPrefix = "account:email-change-undo-window:"
+
+// NewEmailChange creates a new *EmailChange instance. Confirmation emails link to confirmURL and undo notices
+// link to undoURL, both with the token in the token query parameter. A change can be undone within undoWindow,
+// which must match the TTL of undoTokens. Only one change per undoWindow is allowed.
+func NewEmailChange(
+	confirmURL string,
+	undoURL string,
+	undoWindow time.Duration,
+	tokens TokenStore,
+	undoTokens TokenStore,
+	cache EmailChangeCache,
+	mailer Mailer,
+	users EmailChangeUsers,
+) *EmailChange {
+	return &EmailChange{
+		confirmURL: confirmURL,
+		undoURL:    undoURL,
+		undoWindow: undoWindow,
+		tokens:     tokens,
+		undoTokens: undoTokens,
+		cache:      cache,
+		mailer:     mailer,
+		users:      users,
+	}
+}
+
+type (
+	// EmailChange moves accounts to new email addresses once the owner of the new address confirms it with a
+	// single-use token, and lets the owner of the previous address undo the change for a while afterwards.
+	// The address cannot be changed again until then, so that whoever changed it cannot move the account
+	// out of reach of the undo by changing it once more.
+	EmailChange struct {
+		confirmURL string
+		undoURL    string
+		undoWindow time.Duration
+		tokens     TokenStore
+		undoTokens TokenStore
+		cache      EmailChangeCache
+		mailer     Mailer
+		users      EmailChangeUsers
+	}
+
+	// EmailChangeCache defines methods for storing values with a TTL and reading them.
+	EmailChangeCache interface {
+		Set(ctx context.Context, key string, value any, ttl time.Duration) error
+		Get(ctx context.Context, key string) (string, error)
+	}
+
+	// EmailChangeUsers defines methods for reading users and changing their email addresses.
+	EmailChangeUsers interface {
+		GetByEmail(ctx context.Context, email string) (domain.User, error)
+		ChangeEmail(ctx context.Context, userID string, oldEmail string, newEmail string) error
+	}
+
+	// EmailChanged describes a confirmed email change.
+	EmailChanged struct {
+		UserID   string `json:"user_id"`
+		OldEmail string `json:"old_email"`
+		NewEmail string `json:"new_email"`
+	}
+)
+
+// Request emails a confirmation token to the new address of the user.
+// Returns ErrEmailChangeUndoable if the previous change of the user can still be undone
+// and domain.ErrEmailBusy if the address already belongs to another user.
+func (c *EmailChange) Request(ctx context.Context, user domain.User, newEmail string) error {
+	_, err := c.cache.Get(ctx, c.undoWindowKey(user.ID()))
+	if err == nil {
+		return ErrEmailChangeUndoable
+	}
+
+	if !errors.Is(err, redis.ErrNotFound) {
+		return fmt.Errorf("failed to get email change undo window: %w", err)
+	}
+
+	if _, err = c.users.GetByEmail(ctx, newEmail); err == nil {
+		return domain.ErrEmailBusy
+	}
+
+	if !errors.Is(err, domain.ErrUserNotFound) {
+		return fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	// the token is bound to the current address, so that it cannot be used once the address has changed otherwise
+	var token string
+	if token, err = c.tokens.Issue(ctx, EmailChanged{
+		UserID:   user.ID(),
+		OldEmail: user.Email(),
+		NewEmail: newEmail,
+	}); err != nil {
+		return fmt.Errorf("failed to issue email change token: %w", err)
+	}
+
+	var link string
+	if link, err = withToken(c.confirmURL, token); err != nil {
+		return err
+	}
+
+	if err = c.mailer.Send(ctx, mail.Message{
+		To:      newEmail,
+		Subject: "Confirm your new email address",
+		Body: "To use this address for your account, open the link below:\n\n" + link +
+			"\n\nIf you did not ask to change your email address, you can ignore this message.\n",
+	}); err != nil {
+		return fmt.Errorf("failed to send email change confirmation: %w", err)
+	}
+
+	return nil
+}
+
+// Confirm redeems the token and moves the user to the address it was sent to.
+// Returns ErrInvalidToken if the token is unknown, expired, already used or the user has changed the address since,
+// and domain.ErrEmailBusy if another user has taken the address in the meantime.
+func (c *EmailChange) Confirm(ctx context.Context, token string) (EmailChanged, error) {
+	var changed EmailChanged
+	if err := c.tokens.Redeem(ctx, token, &changed); err != nil {
+		return EmailChanged{}, err
+	}
+
+	if err := c.changeEmail(ctx, changed.UserID, changed.OldEmail, changed.NewEmail); err != nil {
+		return EmailChanged{}, err
+	}
+
+	return changed, nil
+}
+
+// SendUndo emails the previous address of the user a notice of the change with a token to undo it
+// and keeps the user from changing the address again while the token is valid.
+func (c *EmailChange) SendUndo(ctx context.Context, changed EmailChanged) error {
+	// the window opens before the token is issued, so that there is no valid token without it
+	err := c.cache.Set(ctx, c.undoWindowKey(changed.UserID), changed.OldEmail, c.undoWindow)
+	if err != nil {
+		return fmt.Errorf("failed to open email change undo window: %w", err)
+	}
+
+	var token string
+	if token, err = c.undoTokens.Issue(ctx, changed); err != nil {
+		return fmt.Errorf("failed to issue email change undo token: %w", err)
+	}
+
+	var link string
+	if link, err = withToken(c.undoURL, token); err != nil {
+		return err
+	}
+
+	if err = c.mailer.Send(ctx, mail.Message{
+		To:      changed.OldEmail,
+		Subject: "Your email address was changed",
+		Body: "The email address of your account was changed to " + changed.NewEmail + ".\n\n" +
+			"If it was not you, open the link below before " +
+			time.Now().Add(c.undoWindow).UTC().Format(time.RFC1123) +
+			" to restore this address and sign out everywhere, then reset your password:\n\n" + link + "\n",
+	}); err != nil {
+		return fmt.Errorf("failed to send email change notice: %w", err)
+	}
+
+	return nil
+}
+
+// Undo redeems the undo token and moves the user back to the previous address. Returns the ID of the user.
+// Returns ErrInvalidToken if the token is unknown, expired, already used or the address has changed again since,
+// and domain.ErrEmailBusy if another user has taken the previous address in the meantime.
+func (c *EmailChange) Undo(ctx context.Context, token string) (string, error) {
+	var changed EmailChanged
+	if err := c.undoTokens.Redeem(ctx, token, &changed); err != nil {
+		return "", err
+	}
+
+	if err := c.changeEmail(ctx, changed.UserID, changed.NewEmail, changed.OldEmail); err != nil {
+		return "", err
+	}
+
+	return changed.UserID, nil
+}
+
+// changeEmail moves the user from one address to another, translating a user who no longer has the address
+// into ErrInvalidToken.
+func (c *EmailChange) changeEmail(ctx context.Context, userID string, from string, to string) error {
+	if err := c.users.ChangeEmail(ctx, userID, from, to); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return ErrInvalidToken
+		}
+
+		if errors.Is(err, domain.ErrEmailBusy) {
+			return err
+		}
+
+		return fmt.Errorf("failed to change email: %w", err)
+	}
+
+	return nil
+}
+
+// undoWindowKey returns the cache key that exists while the last email change of the user can be undone.
+func (c *EmailChange) undoWindowKey(userID string) string {
+	return emailChangeUndoWindowKeyPrefix + userID
+}
@@ -0,0 +1,85 @@
+package account
+
+import (
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/mail"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+const (
+	// DefEmailChangeName is the name of the *EmailChange definition.
+	DefEmailChangeName = "account.email-change"
+
+	configKeyEmailChangeTokenTTL   = "auth.emailChange.tokenTTL"
+	configKeyEmailChangeURL        = "auth.emailChange.url"
+	configKeyEmailChangeUndoWindow = "auth.emailChange.undoWindow"
+	configKeyEmailChangeUndoURL    = "auth.emailChange.undoUrl"
+
+	emailChangeKeyPrefix     = "account:email-change:"
+	emailChangeUndoKeyPrefix = "account:email-change-undo:"
+)
+
+func init() {
+	di.Add(
+		di.Def[*EmailChange]{
+			Name: DefEmailChangeName,
+			Build: func(ctn di.Container) (*EmailChange, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var ttl time.Duration
+				if ttl = cfg.GetDuration(configKeyEmailChangeTokenTTL); ttl == 0 {
+					return nil, config.NewErrMissingKey(configKeyEmailChangeTokenTTL)
+				}
+
+				var confirmURL string
+				if confirmURL = cfg.GetString(configKeyEmailChangeURL); confirmURL == "" {
+					return nil, config.NewErrMissingKey(configKeyEmailChangeURL)
+				}
+
+				var undoWindow time.Duration
+				if undoWindow = cfg.GetDuration(configKeyEmailChangeUndoWindow); undoWindow == 0 {
+					return nil, config.NewErrMissingKey(configKeyEmailChangeUndoWindow)
+				}
+
+				var undoURL string
+				if undoURL = cfg.GetString(configKeyEmailChangeUndoURL); undoURL == "" {
+					return nil, config.NewErrMissingKey(configKeyEmailChangeUndoURL)
+				}
+
+				var cache *redis.Client
+				if err := ctn.Fill(redis.DefClientName, &cache); err != nil {
+					return nil, err
+				}
+
+				var mailer mail.Mailer
+				if err := ctn.Fill(mail.DefMailerName, &mailer); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				return NewEmailChange(
+					confirmURL,
+					undoURL,
+					undoWindow,
+					NewTokens(emailChangeKeyPrefix, ttl, cache),
+					NewTokens(emailChangeUndoKeyPrefix, undoWindow, cache),
+					cache,
+					mailer,
+					usersRep,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,291 @@
+package account_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/account/mocks"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+const (
+	emailChangeURL     = "http://localhost:3000/confirm-email-change"
+	emailChangeUndoURL = "http://localhost:3000/undo-email-change"
+)
+
+func TestEmailChange_Request(t *testing.T) {
+	user := domain.NewUser("user_id", "old@example.com", "hashed_password")
+
+	testCases := map[string]struct {
+		onGetUndoWindow func() (string, error)
+		onGetByEmail    func() (domain.User, error)
+		onIssue         func() (string, error)
+		onSend          func() error
+		expSent         bool
+		expErr          error
+	}{
+		"previous change can be undone": {
+			onGetUndoWindow: func() (string, error) { return "older@example.com", nil },
+			expErr:          account.ErrEmailChangeUndoable,
+		},
+		"failed to get undo window": {
+			onGetUndoWindow: func() (string, error) { return "", assert.AnError },
+			expErr:          assert.AnError,
+		},
+		"email is busy": {
+			onGetByEmail: func() (domain.User, error) {
+				return domain.NewUser("other_id", "new@example.com", "hashed_password"), nil
+			},
+			expErr: domain.ErrEmailBusy,
+		},
+		"failed to get user": {
+			onGetByEmail: func() (domain.User, error) { return nil, assert.AnError },
+			expErr:       assert.AnError,
+		},
+		"failed to issue token": {
+			onGetByEmail: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			onIssue:      func() (string, error) { return "", assert.AnError },
+			expErr:       assert.AnError,
+		},
+		"failed to send message": {
+			onGetByEmail: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			onIssue:      func() (string, error) { return "token", nil },
+			onSend:       func() error { return assert.AnError },
+			expErr:       assert.AnError,
+		},
+		"positive case": {
+			onGetByEmail: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			onIssue:      func() (string, error) { return "token", nil },
+			onSend:       func() error { return nil },
+			expSent:      true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewEmailChangeCache(t)
+			if tc.onGetUndoWindow != nil {
+				cache.On("Get", t.Context(), "account:email-change-undo-window:user_id").Return(tc.onGetUndoWindow())
+			} else {
+				cache.On("Get", t.Context(), "account:email-change-undo-window:user_id").Return("", redis.ErrNotFound)
+			}
+
+			users := mocks.NewEmailChangeUsers(t)
+			if tc.onGetByEmail != nil {
+				users.On("GetByEmail", t.Context(), "new@example.com").Return(tc.onGetByEmail())
+			}
+
+			tokens := mocks.NewTokenStore(t)
+			if tc.onIssue != nil {
+				tokens.On("Issue", t.Context(), account.EmailChanged{
+					UserID:   "user_id",
+					OldEmail: "old@example.com",
+					NewEmail: "new@example.com",
+				}).Return(tc.onIssue())
+			}
+
+			var msg mail.Message
+
+			mailer := mocks.NewMailer(t)
+			if tc.onSend != nil {
+				mailer.On("Send", t.Context(), mock.AnythingOfType("mail.Message")).
+					Run(func(args mock.Arguments) { msg = args.Get(1).(mail.Message) }).
+					Return(tc.onSend())
+			}
+
+			err := newEmailChange(tokens, mocks.NewTokenStore(t), cache, mailer, users).
+				Request(t.Context(), user, "new@example.com")
+			assert.ErrorIs(t, err, tc.expErr)
+
+			if tc.expSent {
+				assert.Equal(t, "new@example.com", msg.To)
+				assert.Equal(t, "Confirm your new email address", msg.Subject)
+				assert.Equal(t, "token", verificationLink(t, msg.Body).Query().Get("token"))
+			}
+		})
+	}
+}
+
+func TestEmailChange_ConfirmAndUndo(t *testing.T) {
+	changed := account.EmailChanged{UserID: "user_id", OldEmail: "old@example.com", NewEmail: "new@example.com"}
+
+	payload, err := json.Marshal(changed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		onRedeem      func() error
+		onChangeEmail func() error
+		expErr        error
+	}{
+		"invalid token": {
+			onRedeem: func() error { return account.ErrInvalidToken },
+			expErr:   account.ErrInvalidToken,
+		},
+		"email has changed since": {
+			onRedeem:      func() error { return nil },
+			onChangeEmail: func() error { return domain.ErrUserNotFound },
+			expErr:        account.ErrInvalidToken,
+		},
+		"email is busy": {
+			onRedeem:      func() error { return nil },
+			onChangeEmail: func() error { return domain.ErrEmailBusy },
+			expErr:        domain.ErrEmailBusy,
+		},
+		"failed to change email": {
+			onRedeem:      func() error { return nil },
+			onChangeEmail: func() error { return assert.AnError },
+			expErr:        assert.AnError,
+		},
+		"positive case": {
+			onRedeem:      func() error { return nil },
+			onChangeEmail: func() error { return nil },
+		},
+	}
+
+	// Confirm and Undo differ in the token store they redeem from, passed as tokens, and the direction of the change.
+	methods := map[string]struct {
+		from string
+		to   string
+		call func(t *testing.T, tokens account.TokenStore, users account.EmailChangeUsers) (string, error)
+	}{
+		"Confirm": {
+			from: "old@example.com",
+			to:   "new@example.com",
+			call: func(t *testing.T, tokens account.TokenStore, users account.EmailChangeUsers) (string, error) {
+				result, err := newEmailChange(tokens, mocks.NewTokenStore(t), mocks.NewEmailChangeCache(t), mocks.NewMailer(t), users).
+					Confirm(t.Context(), "token")
+				if err == nil {
+					assert.Equal(t, changed, result)
+				}
+
+				return result.UserID, err
+			},
+		},
+		"Undo": {
+			from: "new@example.com",
+			to:   "old@example.com",
+			call: func(t *testing.T, tokens account.TokenStore, users account.EmailChangeUsers) (string, error) {
+				return newEmailChange(mocks.NewTokenStore(t), tokens, mocks.NewEmailChangeCache(t), mocks.NewMailer(t), users).
+					Undo(t.Context(), "token")
+			},
+		},
+	}
+
+	for method, m := range methods {
+		for name, tc := range testCases {
+			t.Run(method+"/"+name, func(t *testing.T) {
+				tokens := mocks.NewTokenStore(t)
+				tokens.On("Redeem", t.Context(), "token", mock.Anything).
+					Run(func(args mock.Arguments) { assert.NoError(t, json.Unmarshal(payload, args.Get(2))) }).
+					Return(tc.onRedeem())
+
+				users := mocks.NewEmailChangeUsers(t)
+				if tc.onChangeEmail != nil {
+					users.On("ChangeEmail", t.Context(), "user_id", m.from, m.to).Return(tc.onChangeEmail())
+				}
+
+				userID, err := m.call(t, tokens, users)
+				assert.ErrorIs(t, err, tc.expErr)
+
+				if tc.expErr != nil {
+					assert.Empty(t, userID)
+					return
+				}
+
+				assert.Equal(t, "user_id", userID)
+			})
+		}
+	}
+}
+
+func TestEmailChange_SendUndo(t *testing.T) {
+	changed := account.EmailChanged{UserID: "user_id", OldEmail: "old@example.com", NewEmail: "new@example.com"}
+
+	testCases := map[string]struct {
+		onOpenUndoWindow func() error
+		onIssue          func() (string, error)
+		onSend           func() error
+		expSent          bool
+		expErr           error
+	}{
+		"failed to open undo window": {
+			onOpenUndoWindow: func() error { return assert.AnError },
+			expErr:           assert.AnError,
+		},
+		"failed to issue token": {
+			onOpenUndoWindow: func() error { return nil },
+			onIssue:          func() (string, error) { return "", assert.AnError },
+			expErr:           assert.AnError,
+		},
+		"failed to send message": {
+			onOpenUndoWindow: func() error { return nil },
+			onIssue:          func() (string, error) { return "token", nil },
+			onSend:           func() error { return assert.AnError },
+			expErr:           assert.AnError,
+		},
+		"positive case": {
+			onOpenUndoWindow: func() error { return nil },
+			onIssue:          func() (string, error) { return "token", nil },
+			onSend:           func() error { return nil },
+			expSent:          true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewEmailChangeCache(t)
+			cache.On("Set", t.Context(), "account:email-change-undo-window:user_id", "old@example.com", 7*24*time.Hour).
+				Return(tc.onOpenUndoWindow())
+
+			undoTokens := mocks.NewTokenStore(t)
+			if tc.onIssue != nil {
+				undoTokens.On("Issue", t.Context(), changed).Return(tc.onIssue())
+			}
+
+			var msg mail.Message
+
+			mailer := mocks.NewMailer(t)
+			if tc.onSend != nil {
+				mailer.On("Send", t.Context(), mock.AnythingOfType("mail.Message")).
+					Run(func(args mock.Arguments) { msg = args.Get(1).(mail.Message) }).
+					Return(tc.onSend())
+			}
+
+			err := newEmailChange(mocks.NewTokenStore(t), undoTokens, cache, mailer, mocks.NewEmailChangeUsers(t)).
+				SendUndo(t.Context(), changed)
+			assert.ErrorIs(t, err, tc.expErr)
+
+			if tc.expSent {
+				assert.Equal(t, "old@example.com", msg.To)
+				assert.Equal(t, "Your email address was changed", msg.Subject)
+				assert.Contains(t, msg.Body, "new@example.com")
+
+				link := verificationLink(t, msg.Body)
+				assert.Equal(t, "/undo-email-change", link.Path)
+				assert.Equal(t, "token", link.Query().Get("token"))
+			}
+		})
+	}
+}
+
+// newEmailChange creates an *account.EmailChange with the test links and an undo window of a week.
+func newEmailChange(
+	tokens account.TokenStore,
+	undoTokens account.TokenStore,
+	cache account.EmailChangeCache,
+	mailer account.Mailer,
+	users account.EmailChangeUsers,
+) *account.EmailChange {
+	return account.NewEmailChange(
+		emailChangeURL, emailChangeUndoURL, 7*24*time.Hour, tokens, undoTokens, cache, mailer, users,
+	)
+}
@@ -0,0 +1,266 @@
+package account
+
+//go:generate mockery --name EmailLoginCache --output ./mocks --outpkg mocks --filename email_login_cache.go --structname EmailLoginCache
+//go:generate mockery --name EmailLoginUsers --output ./mocks --outpkg mocks --filename email_login_users.go --structname EmailLoginUsers
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+	"github.com/riabininkf/http-auth-example/internal/random"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+var (
+	// ErrTooManyRequests is returned when a login code or a password reset was sent to the address too recently.
+	ErrTooManyRequests = errors.New("too many requests")
+
+	// ErrInvalidCode is returned when neither the code nor the link token matches the login.
+	ErrInvalidCode = errors.New("invalid code")
+)
+
+const (
+	emailLoginKeyPrefix         = "account:email-login:"
+	emailLoginCooldownKeyPrefix = "account:email-login:cooldown:"
+	emailLoginAttemptsKeySuffix = ":attempts"
+	emailLoginCodeDigits        = 6
+)
+
+// NewEmailLogin creates a new *EmailLogin instance. Logins expire after ttl and allow maxAttempts invalid codes;
+// a new login for the same address can be started once per interval. Emails link to linkURL with the link token
+// in the token query parameter.
+func NewEmailLogin(
+	linkURL string,
+	ttl time.Duration,
+	maxAttempts int,
+	interval time.Duration,
+	cache EmailLoginCache,
+	mailer Mailer,
+	users EmailLoginUsers,
+) *EmailLogin {
+	return &EmailLogin{
+		linkURL:     linkURL,
+		ttl:         ttl,
+		maxAttempts: maxAttempts,
+		interval:    interval,
+		cache:       cache,
+		mailer:      mailer,
+		users:       users,
+	}
+}
+
+type (
+	// EmailLogin signs users in without a password by emailing them a one-time link and a code.
+	// Either of them completes the login only together with the login token returned to the client that
+	// started it, so that a leaked email alone cannot be used on another device.
+	EmailLogin struct {
+		linkURL     string
+		ttl         time.Duration
+		maxAttempts int
+		interval    time.Duration
+		cache       EmailLoginCache
+		mailer      Mailer
+		users       EmailLoginUsers
+	}
+
+	// EmailLoginToken is the opaque token returned to the client that started the login.
+	EmailLoginToken struct {
+		Token     string
+		ExpiresIn time.Duration
+	}
+
+	// EmailLoginCache defines methods for storing values with a TTL, reading them, atomically reading and
+	// removing them, storing them only if the key does not exist yet, counting, and removing keys.
+	EmailLoginCache interface {
+		Set(ctx context.Context, key string, value any, ttl time.Duration) error
+		Get(ctx context.Context, key string) (string, error)
+		GetDel(ctx context.Context, key string) (string, error)
+		SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error)
+		Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+		Del(ctx context.Context, keys ...string) error
+	}
+
+	// EmailLoginUsers defines a method for reading users by email.
+	EmailLoginUsers interface {
+		GetByEmail(ctx context.Context, email string) (domain.User, error)
+	}
+
+	// emailLogin is the state of a pending login. Only hashes of the code and the link token are stored.
+	emailLogin struct {
+		UserID string `json:"user_id"`
+		Code   string `json:"code"`
+		Link   string `json:"link"`
+	}
+)
+
+// Start emails a login link and code to the owner of the address and returns the login token to complete it with.
+// A token is returned even if there is no such user, so that the caller cannot tell whether the address is
+// registered. Returns ErrTooManyRequests if a login for the address was started less than an interval ago.
+func (l *EmailLogin) Start(ctx context.Context, email string) (EmailLoginToken, error) {
+	started, err := l.cache.SetNX(ctx, l.cooldownKey(email), "", l.interval)
+	if err != nil {
+		return EmailLoginToken{}, err
+	}
+
+	if !started {
+		return EmailLoginToken{}, ErrTooManyRequests
+	}
+
+	var token string
+	if token, err = random.String(tokenSize); err != nil {
+		return EmailLoginToken{}, fmt.Errorf("failed to generate login token: %w", err)
+	}
+
+	var user domain.User
+	if user, err = l.users.GetByEmail(ctx, email); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return EmailLoginToken{Token: token, ExpiresIn: l.ttl}, nil
+		}
+
+		return EmailLoginToken{}, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	var code string
+	if code, err = random.Digits(emailLoginCodeDigits); err != nil {
+		return EmailLoginToken{}, fmt.Errorf("failed to generate login code: %w", err)
+	}
+
+	var linkToken string
+	if linkToken, err = random.String(tokenSize); err != nil {
+		return EmailLoginToken{}, fmt.Errorf("failed to generate link token: %w", err)
+	}
+
+	var link string
+	if link, err = withToken(l.linkURL, linkToken); err != nil {
+		return EmailLoginToken{}, err
+	}
+
+	if err = l.save(ctx, token, emailLogin{
+		UserID: user.ID(),
+		Code:   l.hash(token, code),
+		Link:   l.hash(token, linkToken),
+	}, l.ttl); err != nil {
+		return EmailLoginToken{}, err
+	}
+
+	if err = l.mailer.Send(ctx, mail.Message{
+		To:      user.Email(),
+		Subject: "Your login code",
+		Body: "Your login code is " + code + ". To sign in, enter it on the login page or open the link below " +
+			"on the same device:\n\n" + link +
+			"\n\nIf you did not try to sign in, you can ignore this message.\n",
+	}); err != nil {
+		return EmailLoginToken{}, fmt.Errorf("failed to send login code: %w", err)
+	}
+
+	return EmailLoginToken{Token: token, ExpiresIn: l.ttl}, nil
+}
+
+// Complete redeems the code or the link token of the login and returns the ID of the user.
+// Returns ErrInvalidToken if the login token is unknown, expired, already used or has run out of attempts,
+// and ErrInvalidCode if the code does not match.
+func (l *EmailLogin) Complete(ctx context.Context, token string, code string) (string, error) {
+	login, err := l.load(ctx, token)
+	if err != nil {
+		if errors.Is(err, redis.ErrNotFound) {
+			return "", ErrInvalidToken
+		}
+
+		return "", err
+	}
+
+	hash := []byte(l.hash(token, code))
+	if subtle.ConstantTimeCompare(hash, []byte(login.Code)) != 1 &&
+		subtle.ConstantTimeCompare(hash, []byte(login.Link)) != 1 {
+		if err = l.fail(ctx, token); err != nil {
+			return "", err
+		}
+
+		return "", ErrInvalidCode
+	}
+
+	// removing the login makes it single-use even if it is completed concurrently
+	if _, err = l.cache.GetDel(ctx, l.key(token)); err != nil {
+		if errors.Is(err, redis.ErrNotFound) {
+			return "", ErrInvalidToken
+		}
+
+		return "", err
+	}
+
+	return login.UserID, nil
+}
+
+// fail records a failed attempt. The login is removed once it runs out of attempts,
+// so that the code cannot be brute-forced. Attempts are counted atomically under a separate key,
+// so that concurrent failures cannot be counted as one.
+func (l *EmailLogin) fail(ctx context.Context, token string) error {
+	key := l.key(token)
+
+	attempts, err := l.cache.Incr(ctx, key+emailLoginAttemptsKeySuffix, l.ttl)
+	if err != nil {
+		return err
+	}
+
+	if attempts < int64(l.maxAttempts) {
+		return nil
+	}
+
+	return l.cache.Del(ctx, key, key+emailLoginAttemptsKeySuffix)
+}
+
+// load reads the login stored under the token.
+func (l *EmailLogin) load(ctx context.Context, token string) (emailLogin, error) {
+	var (
+		err   error
+		value string
+	)
+	if value, err = l.cache.Get(ctx, l.key(token)); err != nil {
+		return emailLogin{}, err
+	}
+
+	var login emailLogin
+	if err = json.Unmarshal([]byte(value), &login); err != nil {
+		return emailLogin{}, fmt.Errorf("failed to unmarshal email login: %w", err)
+	}
+
+	return login, nil
+}
+
+// save writes the login under the token with the given TTL.
+func (l *EmailLogin) save(ctx context.Context, token string, login emailLogin, ttl time.Duration) error {
+	value, err := json.Marshal(login)
+	if err != nil {
+		return fmt.Errorf("failed to marshal email login: %w", err)
+	}
+
+	return l.cache.Set(ctx, l.key(token), string(value), ttl)
+}
+
+// key returns the cache key for the login token. Only the hash is stored.
+func (l *EmailLogin) key(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return emailLoginKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// cooldownKey returns the cache key limiting how often logins for the address can be started.
+func (l *EmailLogin) cooldownKey(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+	return emailLoginCooldownKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// hash binds the secret to the login token, so that the stored hash of a short code cannot be reversed
+// with a precomputed table.
+func (l *EmailLogin) hash(token string, secret string) string {
+	sum := sha256.Sum256([]byte(token + ":" + secret))
+	return hex.EncodeToString(sum[:])
+}
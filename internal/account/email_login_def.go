@@ -0,0 +1,81 @@
+package account
+
+import (
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/mail"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+const (
+	// DefEmailLoginName is the name of the *EmailLogin definition.
+	DefEmailLoginName = "account.email-login"
+
+	configKeyEmailLoginTTL         = "auth.emailLogin.ttl"
+	configKeyEmailLoginMaxAttempts = "auth.emailLogin.maxAttempts"
+	configKeyEmailLoginInterval    = "auth.emailLogin.interval"
+	configKeyEmailLoginURL         = "auth.emailLogin.url"
+)
+
+func init() {
+	di.Add(
+		di.Def[*EmailLogin]{
+			Name: DefEmailLoginName,
+			Build: func(ctn di.Container) (*EmailLogin, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var ttl time.Duration
+				if ttl = cfg.GetDuration(configKeyEmailLoginTTL); ttl == 0 {
+					return nil, config.NewErrMissingKey(configKeyEmailLoginTTL)
+				}
+
+				var maxAttempts int
+				if maxAttempts = cfg.GetInt(configKeyEmailLoginMaxAttempts); maxAttempts == 0 {
+					return nil, config.NewErrMissingKey(configKeyEmailLoginMaxAttempts)
+				}
+
+				var interval time.Duration
+				if interval = cfg.GetDuration(configKeyEmailLoginInterval); interval == 0 {
+					return nil, config.NewErrMissingKey(configKeyEmailLoginInterval)
+				}
+
+				var linkURL string
+				if linkURL = cfg.GetString(configKeyEmailLoginURL); linkURL == "" {
+					return nil, config.NewErrMissingKey(configKeyEmailLoginURL)
+				}
+
+				var cache *redis.Client
+				if err := ctn.Fill(redis.DefClientName, &cache); err != nil {
+					return nil, err
+				}
+
+				var mailer mail.Mailer
+				if err := ctn.Fill(mail.DefMailerName, &mailer); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				return NewEmailLogin(
+					linkURL,
+					ttl,
+					maxAttempts,
+					interval,
+					cache,
+					mailer,
+					usersRep,
+				), nil
+			},
+		},
+	)
+}
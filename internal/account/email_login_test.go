@@ -0,0 +1,256 @@
+package account_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/account/mocks"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+const emailLoginURL = "http://localhost:3000/login/email"
+
+func TestEmailLogin_Start(t *testing.T) {
+	user := domain.NewUser("user_id", "user@example.com", "hashed_password")
+	cooldownKey := "account:email-login:cooldown:" + sha256Hex("user@example.com")
+
+	testCases := map[string]struct {
+		onSetNX      func() (bool, error)
+		onGetByEmail func() (domain.User, error)
+		onSet        func() error
+		onSend       func() error
+		expToken     bool
+		expErr       error
+	}{
+		"failed to check cooldown": {
+			onSetNX: func() (bool, error) { return false, assert.AnError },
+			expErr:  assert.AnError,
+		},
+		"too many requests": {
+			onSetNX: func() (bool, error) { return false, nil },
+			expErr:  account.ErrTooManyRequests,
+		},
+		"user not found": {
+			onSetNX:      func() (bool, error) { return true, nil },
+			onGetByEmail: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			expToken:     true,
+		},
+		"failed to get user": {
+			onSetNX:      func() (bool, error) { return true, nil },
+			onGetByEmail: func() (domain.User, error) { return nil, assert.AnError },
+			expErr:       assert.AnError,
+		},
+		"failed to save login": {
+			onSetNX:      func() (bool, error) { return true, nil },
+			onGetByEmail: func() (domain.User, error) { return user, nil },
+			onSet:        func() error { return assert.AnError },
+			expErr:       assert.AnError,
+		},
+		"failed to send message": {
+			onSetNX:      func() (bool, error) { return true, nil },
+			onGetByEmail: func() (domain.User, error) { return user, nil },
+			onSet:        func() error { return nil },
+			onSend:       func() error { return assert.AnError },
+			expErr:       assert.AnError,
+		},
+		"positive case": {
+			onSetNX:      func() (bool, error) { return true, nil },
+			onGetByEmail: func() (domain.User, error) { return user, nil },
+			onSet:        func() error { return nil },
+			onSend:       func() error { return nil },
+			expToken:     true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewEmailLoginCache(t)
+			cache.On("SetNX", t.Context(), cooldownKey, "", time.Minute).Return(tc.onSetNX())
+
+			users := mocks.NewEmailLoginUsers(t)
+			if tc.onGetByEmail != nil {
+				users.On("GetByEmail", t.Context(), "user@example.com").Return(tc.onGetByEmail())
+			}
+
+			var key, value string
+			if tc.onSet != nil {
+				cache.On("Set", t.Context(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), 10*time.Minute).
+					Run(func(args mock.Arguments) { key, value = args.String(1), args.String(2) }).
+					Return(tc.onSet())
+			}
+
+			var msg mail.Message
+
+			mailer := mocks.NewMailer(t)
+			if tc.onSend != nil {
+				mailer.On("Send", t.Context(), mock.AnythingOfType("mail.Message")).
+					Run(func(args mock.Arguments) { msg = args.Get(1).(mail.Message) }).
+					Return(tc.onSend())
+			}
+
+			token, err := account.NewEmailLogin(emailLoginURL, 10*time.Minute, 3, time.Minute, cache, mailer, users).
+				Start(t.Context(), "user@example.com")
+			assert.ErrorIs(t, err, tc.expErr)
+
+			if !tc.expToken {
+				assert.Empty(t, token)
+				return
+			}
+
+			assert.NotEmpty(t, token.Token)
+			assert.Equal(t, 10*time.Minute, token.ExpiresIn)
+
+			if tc.onSend == nil {
+				return
+			}
+
+			assert.Equal(t, "user@example.com", msg.To)
+			assert.Equal(t, emailLoginKey(token.Token), key)
+
+			code := regexp.MustCompile(`\b\d{6}\b`).FindString(msg.Body)
+			linkToken := verificationLink(t, msg.Body).Query().Get("token")
+
+			var stored map[string]any
+			if err = json.Unmarshal([]byte(value), &stored); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, "user_id", stored["user_id"])
+			assert.Equal(t, emailLoginHash(token.Token, code), stored["code"])
+			assert.Equal(t, emailLoginHash(token.Token, linkToken), stored["link"])
+		})
+	}
+}
+
+func TestEmailLogin_Complete(t *testing.T) {
+	key := emailLoginKey("token")
+	attemptsKey := key + ":attempts"
+
+	stored := fmt.Sprintf(`{"user_id":"user_id","code":"%s","link":"%s"}`,
+		emailLoginHash("token", "123456"), emailLoginHash("token", "link_token"))
+
+	testCases := map[string]struct {
+		code      string
+		onCache   func(cache *mocks.EmailLoginCache)
+		expUserID string
+		expErr    error
+	}{
+		"unknown token": {
+			code: "123456",
+			onCache: func(cache *mocks.EmailLoginCache) {
+				cache.On("Get", t.Context(), key).Return("", redis.ErrNotFound)
+			},
+			expErr: account.ErrInvalidToken,
+		},
+		"failed to get login": {
+			code: "123456",
+			onCache: func(cache *mocks.EmailLoginCache) {
+				cache.On("Get", t.Context(), key).Return("", assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"invalid code": {
+			code: "654321",
+			onCache: func(cache *mocks.EmailLoginCache) {
+				cache.On("Get", t.Context(), key).Return(stored, nil)
+				cache.On("Incr", t.Context(), attemptsKey, 10*time.Minute).Return(int64(1), nil)
+			},
+			expErr: account.ErrInvalidCode,
+		},
+		"failed to record failed attempt": {
+			code: "654321",
+			onCache: func(cache *mocks.EmailLoginCache) {
+				cache.On("Get", t.Context(), key).Return(stored, nil)
+				cache.On("Incr", t.Context(), attemptsKey, 10*time.Minute).Return(int64(0), assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"last attempt": {
+			code: "654321",
+			onCache: func(cache *mocks.EmailLoginCache) {
+				cache.On("Get", t.Context(), key).Return(stored, nil)
+				cache.On("Incr", t.Context(), attemptsKey, 10*time.Minute).Return(int64(3), nil)
+				cache.On("Del", t.Context(), key, attemptsKey).Return(nil)
+			},
+			expErr: account.ErrInvalidCode,
+		},
+		"failed to remove login": {
+			code: "654321",
+			onCache: func(cache *mocks.EmailLoginCache) {
+				cache.On("Get", t.Context(), key).Return(stored, nil)
+				cache.On("Incr", t.Context(), attemptsKey, 10*time.Minute).Return(int64(3), nil)
+				cache.On("Del", t.Context(), key, attemptsKey).Return(assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"completed concurrently": {
+			code: "123456",
+			onCache: func(cache *mocks.EmailLoginCache) {
+				cache.On("Get", t.Context(), key).Return(stored, nil)
+				cache.On("GetDel", t.Context(), key).Return("", redis.ErrNotFound)
+			},
+			expErr: account.ErrInvalidToken,
+		},
+		"positive case with code": {
+			code: "123456",
+			onCache: func(cache *mocks.EmailLoginCache) {
+				cache.On("Get", t.Context(), key).Return(stored, nil)
+				cache.On("GetDel", t.Context(), key).Return(stored, nil)
+			},
+			expUserID: "user_id",
+		},
+		"positive case with link token": {
+			code: "link_token",
+			onCache: func(cache *mocks.EmailLoginCache) {
+				cache.On("Get", t.Context(), key).Return(stored, nil)
+				cache.On("GetDel", t.Context(), key).Return(stored, nil)
+			},
+			expUserID: "user_id",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewEmailLoginCache(t)
+			tc.onCache(cache)
+
+			login := account.NewEmailLogin(
+				emailLoginURL,
+				10*time.Minute,
+				3,
+				time.Minute,
+				cache,
+				mocks.NewMailer(t),
+				mocks.NewEmailLoginUsers(t),
+			)
+
+			userID, err := login.Complete(t.Context(), "token", tc.code)
+			assert.Equal(t, tc.expUserID, userID)
+			assert.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
+
+func emailLoginKey(token string) string {
+	return "account:email-login:" + sha256Hex(token)
+}
+
+func emailLoginHash(token string, secret string) string {
+	return sha256Hex(token + ":" + secret)
+}
+
+func sha256Hex(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
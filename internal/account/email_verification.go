@@ -0,0 +1,141 @@
+package account
+
+//go:generate mockery --name TokenStore --output ./mocks --outpkg mocks --filename token_store.go --structname TokenStore
+//go:generate mockery --name Mailer --output ./mocks --outpkg mocks --filename mailer.go --structname Mailer
+//go:generate mockery --name EmailVerificationUsers --output ./mocks --outpkg mocks --filename email_verification_users.go --structname EmailVerificationUsers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+)
+
+// NewEmailVerification creates a new *EmailVerification instance. Emails link to linkURL with the token
+// in the token query parameter.
+func NewEmailVerification(
+	linkURL string,
+	tokens TokenStore,
+	mailer Mailer,
+	users EmailVerificationUsers,
+) *EmailVerification {
+	return &EmailVerification{
+		linkURL: linkURL,
+		tokens:  tokens,
+		mailer:  mailer,
+		users:   users,
+	}
+}
+
+type (
+	// EmailVerification proves that users own their email addresses by sending them single-use tokens.
+	EmailVerification struct {
+		linkURL string
+		tokens  TokenStore
+		mailer  Mailer
+		users   EmailVerificationUsers
+	}
+
+	// TokenStore defines methods for issuing, inspecting and redeeming single-use tokens.
+	TokenStore interface {
+		Issue(ctx context.Context, payload any) (string, error)
+		Peek(ctx context.Context, token string, payload any) error
+		Redeem(ctx context.Context, token string, payload any) error
+	}
+
+	// Mailer defines a method for sending email messages.
+	Mailer interface {
+		Send(ctx context.Context, msg mail.Message) error
+	}
+
+	// EmailVerificationUsers defines methods for reading users and marking their email addresses as verified.
+	EmailVerificationUsers interface {
+		GetByEmail(ctx context.Context, email string) (domain.User, error)
+		MarkEmailVerified(ctx context.Context, userID string, email string) error
+	}
+
+	// emailVerificationPayload binds a token to the address it was sent to.
+	emailVerificationPayload struct {
+		UserID string `json:"user_id"`
+		Email  string `json:"email"`
+	}
+)
+
+// Send emails a verification token to the user.
+func (v *EmailVerification) Send(ctx context.Context, user domain.User) error {
+	token, err := v.tokens.Issue(ctx, emailVerificationPayload{UserID: user.ID(), Email: user.Email()})
+	if err != nil {
+		return fmt.Errorf("failed to issue email verification token: %w", err)
+	}
+
+	var link string
+	if link, err = withToken(v.linkURL, token); err != nil {
+		return err
+	}
+
+	if err = v.mailer.Send(ctx, mail.Message{
+		To:      user.Email(),
+		Subject: "Verify your email address",
+		Body: "Please confirm your email address by opening the link below:\n\n" + link +
+			"\n\nIf you did not create an account, you can ignore this message.\n",
+	}); err != nil {
+		return fmt.Errorf("failed to send email verification: %w", err)
+	}
+
+	return nil
+}
+
+// Resend emails a new verification token to the owner of the address, unless there is no such user or the address
+// is already verified. Both cases are indistinguishable for the caller, so that it cannot be used to find accounts.
+func (v *EmailVerification) Resend(ctx context.Context, email string) error {
+	user, err := v.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	if user.EmailVerified() {
+		return nil
+	}
+
+	return v.Send(ctx, user)
+}
+
+// Verify redeems the token and marks the address it was sent to as verified. Returns the ID of the user.
+// Returns ErrInvalidToken if the token is unknown, expired, already used or the user has changed the address since.
+func (v *EmailVerification) Verify(ctx context.Context, token string) (string, error) {
+	var payload emailVerificationPayload
+	if err := v.tokens.Redeem(ctx, token, &payload); err != nil {
+		return "", err
+	}
+
+	if err := v.users.MarkEmailVerified(ctx, payload.UserID, payload.Email); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return "", ErrInvalidToken
+		}
+
+		return "", fmt.Errorf("failed to mark email as verified: %w", err)
+	}
+
+	return payload.UserID, nil
+}
+
+// withToken adds the token to the query of the link.
+func withToken(link string, token string) (string, error) {
+	u, err := url.Parse(link)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse link url: %w", err)
+	}
+
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
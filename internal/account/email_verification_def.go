@@ -0,0 +1,68 @@
+package account
+
+import (
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/mail"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+const (
+	// DefEmailVerificationName is the name of the *EmailVerification definition.
+	DefEmailVerificationName = "account.email-verification"
+
+	configKeyEmailVerificationTokenTTL = "auth.emailVerification.tokenTTL"
+	configKeyEmailVerificationURL      = "auth.emailVerification.url"
+
+	emailVerificationKeyPrefix = "account:email-verification:"
+)
+
+func init() {
+	di.Add(
+		di.Def[*EmailVerification]{
+			Name: DefEmailVerificationName,
+			Build: func(ctn di.Container) (*EmailVerification, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var ttl time.Duration
+				if ttl = cfg.GetDuration(configKeyEmailVerificationTokenTTL); ttl == 0 {
+					return nil, config.NewErrMissingKey(configKeyEmailVerificationTokenTTL)
+				}
+
+				var linkURL string
+				if linkURL = cfg.GetString(configKeyEmailVerificationURL); linkURL == "" {
+					return nil, config.NewErrMissingKey(configKeyEmailVerificationURL)
+				}
+
+				var cache *redis.Client
+				if err := ctn.Fill(redis.DefClientName, &cache); err != nil {
+					return nil, err
+				}
+
+				var mailer mail.Mailer
+				if err := ctn.Fill(mail.DefMailerName, &mailer); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				return NewEmailVerification(
+					linkURL,
+					NewTokens(emailVerificationKeyPrefix, ttl, cache),
+					mailer,
+					usersRep,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,182 @@
+package account_test
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/account/mocks"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+)
+
+const verificationURL = "http://localhost:3000/verify-email?source=mail"
+
+func TestEmailVerification_Send(t *testing.T) {
+	user := domain.NewUser("user_id", "user@example.com", "hashed_password")
+
+	t.Run("failed to issue token", func(t *testing.T) {
+		tokens := mocks.NewTokenStore(t)
+		tokens.On("Issue", t.Context(), mock.Anything).Return("", assert.AnError)
+
+		err := account.NewEmailVerification(verificationURL, tokens, mocks.NewMailer(t), mocks.NewEmailVerificationUsers(t)).
+			Send(t.Context(), user)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("failed to send message", func(t *testing.T) {
+		tokens := mocks.NewTokenStore(t)
+		tokens.On("Issue", t.Context(), mock.Anything).Return("token", nil)
+
+		mailer := mocks.NewMailer(t)
+		mailer.On("Send", t.Context(), mock.AnythingOfType("mail.Message")).Return(assert.AnError)
+
+		err := account.NewEmailVerification(verificationURL, tokens, mailer, mocks.NewEmailVerificationUsers(t)).
+			Send(t.Context(), user)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		tokens := mocks.NewTokenStore(t)
+		tokens.On("Issue", t.Context(), mock.Anything).Return("token", nil)
+
+		var msg mail.Message
+
+		mailer := mocks.NewMailer(t)
+		mailer.On("Send", t.Context(), mock.AnythingOfType("mail.Message")).
+			Run(func(args mock.Arguments) { msg = args.Get(1).(mail.Message) }).
+			Return(nil)
+
+		err := account.NewEmailVerification(verificationURL, tokens, mailer, mocks.NewEmailVerificationUsers(t)).
+			Send(t.Context(), user)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, "user@example.com", msg.To)
+		assert.Equal(t, "Verify your email address", msg.Subject)
+
+		link := verificationLink(t, msg.Body)
+		assert.Equal(t, "token", link.Query().Get("token"))
+		assert.Equal(t, "mail", link.Query().Get("source"))
+	})
+}
+
+func TestEmailVerification_Resend(t *testing.T) {
+	testCases := map[string]struct {
+		onGetByEmail func() (domain.User, error)
+		expSent      bool
+		expErr       error
+	}{
+		"user not found": {
+			onGetByEmail: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+		},
+		"failed to get user": {
+			onGetByEmail: func() (domain.User, error) { return nil, assert.AnError },
+			expErr:       assert.AnError,
+		},
+		"email is already verified": {
+			onGetByEmail: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password", domain.WithEmailVerified()), nil
+			},
+		},
+		"positive case": {
+			onGetByEmail: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			expSent: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			users := mocks.NewEmailVerificationUsers(t)
+			users.On("GetByEmail", t.Context(), "user@example.com").Return(tc.onGetByEmail())
+
+			tokens := mocks.NewTokenStore(t)
+			mailer := mocks.NewMailer(t)
+
+			if tc.expSent {
+				tokens.On("Issue", t.Context(), mock.Anything).Return("token", nil)
+				mailer.On("Send", t.Context(), mock.AnythingOfType("mail.Message")).Return(nil)
+			}
+
+			err := account.NewEmailVerification(verificationURL, tokens, mailer, users).
+				Resend(t.Context(), "user@example.com")
+			assert.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestEmailVerification_Verify(t *testing.T) {
+	testCases := map[string]struct {
+		onRedeem            func() error
+		onMarkEmailVerified func() error
+		expUserID           string
+		expErr              error
+	}{
+		"invalid token": {
+			onRedeem: func() error { return account.ErrInvalidToken },
+			expErr:   account.ErrInvalidToken,
+		},
+		"email has changed": {
+			onRedeem:            func() error { return nil },
+			onMarkEmailVerified: func() error { return domain.ErrUserNotFound },
+			expErr:              account.ErrInvalidToken,
+		},
+		"failed to mark email as verified": {
+			onRedeem:            func() error { return nil },
+			onMarkEmailVerified: func() error { return assert.AnError },
+			expErr:              assert.AnError,
+		},
+		"positive case": {
+			onRedeem:            func() error { return nil },
+			onMarkEmailVerified: func() error { return nil },
+			expUserID:           "user_id",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			tokens := mocks.NewTokenStore(t)
+			tokens.On("Redeem", t.Context(), "token", mock.Anything).
+				Run(func(args mock.Arguments) {
+					payload := []byte(`{"user_id":"user_id","email":"user@example.com"}`)
+					assert.NoError(t, json.Unmarshal(payload, args.Get(2)))
+				}).
+				Return(tc.onRedeem())
+
+			users := mocks.NewEmailVerificationUsers(t)
+			if tc.onMarkEmailVerified != nil {
+				users.On("MarkEmailVerified", t.Context(), "user_id", "user@example.com").Return(tc.onMarkEmailVerified())
+			}
+
+			userID, err := account.NewEmailVerification(verificationURL, tokens, mocks.NewMailer(t), users).
+				Verify(t.Context(), "token")
+			assert.Equal(t, tc.expUserID, userID)
+			assert.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
+
+// verificationLink returns the link from the message body.
+func verificationLink(t *testing.T, body string) *url.URL {
+	for _, field := range strings.Fields(body) {
+		if strings.HasPrefix(field, "http") {
+			link, err := url.Parse(field)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return link
+		}
+	}
+
+	t.Fatal("link not found in message body")
+	return nil
+}
@@ -0,0 +1,60 @@
+package account
+
+//go:generate mockery --name ExistingAccountUsers --output ./mocks --outpkg mocks --filename existing_account_users.go --structname ExistingAccountUsers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+)
+
+// NewExistingAccountNotice creates a new *ExistingAccountNotice instance.
+func NewExistingAccountNotice(mailer Mailer, users ExistingAccountUsers) *ExistingAccountNotice {
+	return &ExistingAccountNotice{
+		mailer: mailer,
+		users:  users,
+	}
+}
+
+type (
+	// ExistingAccountNotice tells owners of accounts that someone tried to register again with their email address,
+	// so that registration can answer the same way whether the address is taken or not.
+	ExistingAccountNotice struct {
+		mailer Mailer
+		users  ExistingAccountUsers
+	}
+
+	// ExistingAccountUsers defines a method for reading users.
+	ExistingAccountUsers interface {
+		GetByEmail(ctx context.Context, email string) (domain.User, error)
+	}
+)
+
+// Send emails the notice to the owner of the address. Returns nil if there is no such user, e.g. because
+// the account was deleted in the meantime.
+func (n *ExistingAccountNotice) Send(ctx context.Context, email string) error {
+	user, err := n.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	if err = n.mailer.Send(ctx, mail.Message{
+		To:      user.Email(),
+		Subject: "You already have an account",
+		Body: "Someone tried to register a new account with your email address, " +
+			"but there is an account for it already.\n\n" +
+			"If it was you, log in with your password, or reset it if you forgot it. " +
+			"Otherwise you can ignore this message, your account has not been changed.\n",
+	}); err != nil {
+		return fmt.Errorf("failed to send existing account notice: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package account
+
+import (
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/mail"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+// DefExistingAccountNoticeName is the name of the *ExistingAccountNotice definition.
+const DefExistingAccountNoticeName = "account.existing-account-notice"
+
+func init() {
+	di.Add(
+		di.Def[*ExistingAccountNotice]{
+			Name: DefExistingAccountNoticeName,
+			Build: func(ctn di.Container) (*ExistingAccountNotice, error) {
+				var mailer mail.Mailer
+				if err := ctn.Fill(mail.DefMailerName, &mailer); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				return NewExistingAccountNotice(mailer, usersRep), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,69 @@
+package account_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/account/mocks"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+)
+
+func TestExistingAccountNotice_Send(t *testing.T) {
+	user := domain.NewUser("user_id", "user@example.com", "hashed_password")
+
+	testCases := map[string]struct {
+		onGetByEmail func() (domain.User, error)
+		onSend       func() error
+		expSent      bool
+		expErr       error
+	}{
+		"user not found": {
+			onGetByEmail: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+		},
+		"failed to get user": {
+			onGetByEmail: func() (domain.User, error) { return nil, assert.AnError },
+			expErr:       assert.AnError,
+		},
+		"failed to send message": {
+			onGetByEmail: func() (domain.User, error) { return user, nil },
+			onSend:       func() error { return assert.AnError },
+			expErr:       assert.AnError,
+		},
+		"positive case": {
+			onGetByEmail: func() (domain.User, error) { return user, nil },
+			onSend:       func() error { return nil },
+			expSent:      true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			users := mocks.NewExistingAccountUsers(t)
+			users.On("GetByEmail", t.Context(), "User@Example.com").Return(tc.onGetByEmail())
+
+			var msg mail.Message
+
+			mailer := mocks.NewMailer(t)
+			if tc.onSend != nil {
+				mailer.On("Send", t.Context(), mock.AnythingOfType("mail.Message")).
+					Run(func(args mock.Arguments) { msg = args.Get(1).(mail.Message) }).
+					Return(tc.onSend())
+			}
+
+			err := account.NewExistingAccountNotice(mailer, users).Send(t.Context(), "User@Example.com")
+			assert.ErrorIs(t, err, tc.expErr)
+
+			if !tc.expSent {
+				return
+			}
+
+			// sent to the stored address rather than the one typed into the registration form
+			assert.Equal(t, "user@example.com", msg.To)
+			assert.Equal(t, "You already have an account", msg.Subject)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailChangeUsers is an autogenerated mock type for the EmailChangeUsers type
+type EmailChangeUsers struct {
+	mock.Mock
+}
+
+// ChangeEmail provides a mock function with given fields: ctx, userID, oldEmail, newEmail
+func (_m *EmailChangeUsers) ChangeEmail(ctx context.Context, userID string, oldEmail string, newEmail string) error {
+	ret := _m.Called(ctx, userID, oldEmail, newEmail)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ChangeEmail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
+		r0 = rf(ctx, userID, oldEmail, newEmail)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByEmail provides a mock function with given fields: ctx, email
+func (_m *EmailChangeUsers) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByEmail")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEmailChangeUsers creates a new instance of EmailChangeUsers. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailChangeUsers(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailChangeUsers {
+	mock := &EmailChangeUsers{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
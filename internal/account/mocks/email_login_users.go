@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailLoginUsers is an autogenerated mock type for the EmailLoginUsers type
+type EmailLoginUsers struct {
+	mock.Mock
+}
+
+// GetByEmail provides a mock function with given fields: ctx, email
+func (_m *EmailLoginUsers) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByEmail")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEmailLoginUsers creates a new instance of EmailLoginUsers. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailLoginUsers(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailLoginUsers {
+	mock := &EmailLoginUsers{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,78 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailVerificationUsers is an autogenerated mock type for the EmailVerificationUsers type
+type EmailVerificationUsers struct {
+	mock.Mock
+}
+
+// GetByEmail provides a mock function with given fields: ctx, email
+func (_m *EmailVerificationUsers) GetByEmail(ctx context.Context, email string) (domain.User, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByEmail")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.User, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(ctx, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkEmailVerified provides a mock function with given fields: ctx, userID, email
+func (_m *EmailVerificationUsers) MarkEmailVerified(ctx context.Context, userID string, email string) error {
+	ret := _m.Called(ctx, userID, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkEmailVerified")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEmailVerificationUsers creates a new instance of EmailVerificationUsers. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailVerificationUsers(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailVerificationUsers {
+	mock := &EmailVerificationUsers{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,42 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// HashChecker is an autogenerated mock type for the HashChecker type
+type HashChecker struct {
+	mock.Mock
+}
+
+// Check provides a mock function with given fields: encoded
+func (_m *HashChecker) Check(encoded string) error {
+	ret := _m.Called(encoded)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Check")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(string) error); ok {
+		r0 = rf(encoded)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewHashChecker creates a new instance of HashChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewHashChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *HashChecker {
+	mock := &HashChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
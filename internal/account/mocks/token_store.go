@@ -0,0 +1,92 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TokenStore is an autogenerated mock type for the TokenStore type
+type TokenStore struct {
+	mock.Mock
+}
+
+// Issue provides a mock function with given fields: ctx, payload
+func (_m *TokenStore) Issue(ctx context.Context, payload interface{}) (string, error) {
+	ret := _m.Called(ctx, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Issue")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) (string, error)); ok {
+		return rf(ctx, payload)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, interface{}) string); ok {
+		r0 = rf(ctx, payload)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, interface{}) error); ok {
+		r1 = rf(ctx, payload)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Peek provides a mock function with given fields: ctx, token, payload
+func (_m *TokenStore) Peek(ctx context.Context, token string, payload interface{}) error {
+	ret := _m.Called(ctx, token, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Peek")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
+		r0 = rf(ctx, token, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Redeem provides a mock function with given fields: ctx, token, payload
+func (_m *TokenStore) Redeem(ctx context.Context, token string, payload interface{}) error {
+	ret := _m.Called(ctx, token, payload)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Redeem")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
+		r0 = rf(ctx, token, payload)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTokenStore creates a new instance of TokenStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenStore {
+	mock := &TokenStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
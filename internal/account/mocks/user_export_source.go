@@ -0,0 +1,48 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserExportSource is an autogenerated mock type for the UserExportSource type
+type UserExportSource struct {
+	mock.Mock
+}
+
+// Each provides a mock function with given fields: ctx, fn
+func (_m *UserExportSource) Each(ctx context.Context, fn func(domain.User) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Each")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(domain.User) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewUserExportSource creates a new instance of UserExportSource. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserExportSource(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserExportSource {
+	mock := &UserExportSource{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,97 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// UserImportStore is an autogenerated mock type for the UserImportStore type
+type UserImportStore struct {
+	mock.Mock
+}
+
+// Existing provides a mock function with given fields: ctx, ids, emails
+func (_m *UserImportStore) Existing(ctx context.Context, ids []string, emails []string) ([]string, []string, error) {
+	ret := _m.Called(ctx, ids, emails)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Existing")
+	}
+
+	var r0 []string
+	var r1 []string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []string) ([]string, []string, error)); ok {
+		return rf(ctx, ids, emails)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, []string) []string); ok {
+		r0 = rf(ctx, ids, emails)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, []string) []string); ok {
+		r1 = rf(ctx, ids, emails)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []string, []string) error); ok {
+		r2 = rf(ctx, ids, emails)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Import provides a mock function with given fields: ctx, users
+func (_m *UserImportStore) Import(ctx context.Context, users []domain.User) (int64, error) {
+	ret := _m.Called(ctx, users)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Import")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.User) (int64, error)); ok {
+		return rf(ctx, users)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.User) int64); ok {
+		r0 = rf(ctx, users)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.User) error); ok {
+		r1 = rf(ctx, users)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewUserImportStore creates a new instance of UserImportStore. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewUserImportStore(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *UserImportStore {
+	mock := &UserImportStore{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,53 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+)
+
+// NewNewDeviceNotice creates a new *NewDeviceNotice instance.
+func NewNewDeviceNotice(mailer Mailer) *NewDeviceNotice {
+	return &NewDeviceNotice{
+		mailer: mailer,
+	}
+}
+
+// NewDeviceNotice tells users that their password was used on a device they have not recently signed in from,
+// so that they can change it if it was not them.
+type NewDeviceNotice struct {
+	mailer Mailer
+}
+
+// Send emails the notice to the user with the client stored in ctx.
+func (n *NewDeviceNotice) Send(ctx context.Context, user domain.User) error {
+	client := audit.ClientFromContext(ctx)
+
+	if err := n.mailer.Send(ctx, mail.Message{
+		To:      user.Email(),
+		Subject: "New sign-in to your account",
+		Body: "Your password was just used to sign in to your account from a device you have not used recently:\n\n" +
+			"Time: " + time.Now().UTC().Format(time.RFC1123) + "\n" +
+			"IP address: " + valueOrUnknown(client.IP) + "\n" +
+			"Device: " + valueOrUnknown(client.UserAgent) + "\n\n" +
+			"If it was you, you can ignore this message. " +
+			"Otherwise change your password right away and review the security events of your account.\n",
+	}); err != nil {
+		return fmt.Errorf("failed to send new device notice: %w", err)
+	}
+
+	return nil
+}
+
+// valueOrUnknown returns the value, or "unknown" if it is empty.
+func valueOrUnknown(value string) string {
+	if value == "" {
+		return "unknown"
+	}
+
+	return value
+}
@@ -0,0 +1,26 @@
+package account
+
+import (
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/mail"
+)
+
+// DefNewDeviceNoticeName is the name of the *NewDeviceNotice definition.
+const DefNewDeviceNoticeName = "account.new-device-notice"
+
+func init() {
+	di.Add(
+		di.Def[*NewDeviceNotice]{
+			Name: DefNewDeviceNoticeName,
+			Build: func(ctn di.Container) (*NewDeviceNotice, error) {
+				var mailer mail.Mailer
+				if err := ctn.Fill(mail.DefMailerName, &mailer); err != nil {
+					return nil, err
+				}
+
+				return NewNewDeviceNotice(mailer), nil
+			},
+		},
+	)
+}
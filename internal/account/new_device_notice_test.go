@@ -0,0 +1,68 @@
+package account_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/account/mocks"
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+)
+
+func TestNewDeviceNotice_Send(t *testing.T) {
+	user := domain.NewUser("user_id", "user@example.com", "hashed_password")
+
+	testCases := map[string]struct {
+		client      *audit.Client
+		onSend      func() error
+		expContains []string
+		expErr      error
+	}{
+		"failed to send message": {
+			onSend: func() error { return assert.AnError },
+			expErr: assert.AnError,
+		},
+		"client is described": {
+			client:      &audit.Client{IP: "203.0.113.7", UserAgent: "curl/8.0"},
+			onSend:      func() error { return nil },
+			expContains: []string{"IP address: 203.0.113.7\n", "Device: curl/8.0\n"},
+		},
+		"no client in the context": {
+			onSend:      func() error { return nil },
+			expContains: []string{"IP address: unknown\n", "Device: unknown\n"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ctx := t.Context()
+			if tc.client != nil {
+				ctx = audit.ContextWithClient(ctx, *tc.client)
+			}
+
+			var msg mail.Message
+
+			mailer := mocks.NewMailer(t)
+			mailer.On("Send", ctx, mock.AnythingOfType("mail.Message")).
+				Run(func(args mock.Arguments) { msg = args.Get(1).(mail.Message) }).
+				Return(tc.onSend())
+
+			err := account.NewNewDeviceNotice(mailer).Send(ctx, user)
+			assert.ErrorIs(t, err, tc.expErr)
+
+			if tc.expErr != nil {
+				return
+			}
+
+			assert.Equal(t, "user@example.com", msg.To)
+			assert.Equal(t, "New sign-in to your account", msg.Subject)
+			for _, text := range tc.expContains {
+				assert.Contains(t, msg.Body, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package account
+
+//go:generate mockery --name PasswordResetUsers --output ./mocks --outpkg mocks --filename password_reset_users.go --structname PasswordResetUsers
+//go:generate mockery --name PasswordResetCache --output ./mocks --outpkg mocks --filename password_reset_cache.go --structname PasswordResetCache
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+)
+
+const passwordResetCooldownKeyPrefix = "account:password-reset:cooldown:"
+
+// NewPasswordReset creates a new *PasswordReset instance. A reset for the same address can be requested once
+// per interval. Emails link to linkURL with the token in the token query parameter.
+func NewPasswordReset(
+	log *logger.Logger,
+	linkURL string,
+	interval time.Duration,
+	tokens TokenStore,
+	cache PasswordResetCache,
+	mailer Mailer,
+	users PasswordResetUsers,
+) *PasswordReset {
+	return &PasswordReset{
+		log:      log,
+		linkURL:  linkURL,
+		interval: interval,
+		tokens:   tokens,
+		cache:    cache,
+		mailer:   mailer,
+		users:    users,
+	}
+}
+
+type (
+	// PasswordReset lets users who forgot their password prove that they own the email address of the account
+	// with single-use tokens sent to it.
+	PasswordReset struct {
+		log      *logger.Logger
+		linkURL  string
+		interval time.Duration
+		tokens   TokenStore
+		cache    PasswordResetCache
+		mailer   Mailer
+		users    PasswordResetUsers
+		sending  sync.WaitGroup
+	}
+
+	// PasswordResetCache defines a method for storing values only if the key does not exist yet.
+	PasswordResetCache interface {
+		SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error)
+	}
+
+	// PasswordResetUsers defines methods for reading users.
+	PasswordResetUsers interface {
+		GetByEmail(ctx context.Context, email string) (domain.User, error)
+		GetByID(ctx context.Context, userID string) (domain.User, error)
+	}
+
+	// passwordResetPayload binds a token to the password the user had when it was sent, so that changing
+	// the password invalidates all outstanding tokens.
+	passwordResetPayload struct {
+		UserID   string `json:"user_id"`
+		Password string `json:"password"`
+	}
+)
+
+// Request emails a password reset token to the owner of the address. The user is looked up and the email is sent
+// in the background, so that neither the result nor the time it takes tell whether the address is registered.
+// Returns ErrTooManyRequests if a reset for the address was requested less than an interval ago.
+func (r *PasswordReset) Request(ctx context.Context, email string) error {
+	requested, err := r.cache.SetNX(ctx, r.cooldownKey(email), "", r.interval)
+	if err != nil {
+		return fmt.Errorf("failed to check password reset interval: %w", err)
+	}
+
+	if !requested {
+		return ErrTooManyRequests
+	}
+
+	r.sending.Add(1)
+	go func() {
+		defer r.sending.Done()
+
+		// the request is answered before the email is sent, so its cancellation must not stop the sending
+		if err := r.send(context.WithoutCancel(ctx), email); err != nil {
+			r.log.Error("failed to send password reset", logger.Error(err))
+		}
+	}()
+
+	return nil
+}
+
+// Wait blocks until the password resets requested so far are sent, so that shutting down does not lose them.
+func (r *PasswordReset) Wait() {
+	r.sending.Wait()
+}
+
+// send emails a password reset token to the owner of the address, if there is one.
+func (r *PasswordReset) send(ctx context.Context, email string) error {
+	user, err := r.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	var token string
+	if token, err = r.tokens.Issue(ctx, passwordResetPayload{
+		UserID:   user.ID(),
+		Password: passwordFingerprint(user.HashedPassword()),
+	}); err != nil {
+		return fmt.Errorf("failed to issue password reset token: %w", err)
+	}
+
+	var link string
+	if link, err = withToken(r.linkURL, token); err != nil {
+		return err
+	}
+
+	if err = r.mailer.Send(ctx, mail.Message{
+		To:      user.Email(),
+		Subject: "Reset your password",
+		Body: "To choose a new password, open the link below:\n\n" + link +
+			"\n\nIf you did not ask to reset your password, you can ignore this message.\n",
+	}); err != nil {
+		return fmt.Errorf("failed to send password reset: %w", err)
+	}
+
+	return nil
+}
+
+// Lookup returns the user the token allows to set a new password, without consuming the token.
+// Returns ErrInvalidToken if the token is unknown, expired, already used or the password has changed since.
+func (r *PasswordReset) Lookup(ctx context.Context, token string) (domain.User, error) {
+	var payload passwordResetPayload
+	if err := r.tokens.Peek(ctx, token, &payload); err != nil {
+		return nil, err
+	}
+
+	return r.user(ctx, payload)
+}
+
+// Redeem consumes the token and returns the user allowed to set a new password.
+// Returns ErrInvalidToken if the token is unknown, expired, already used or the password has changed since.
+func (r *PasswordReset) Redeem(ctx context.Context, token string) (domain.User, error) {
+	var payload passwordResetPayload
+	if err := r.tokens.Redeem(ctx, token, &payload); err != nil {
+		return nil, err
+	}
+
+	return r.user(ctx, payload)
+}
+
+// user returns the user the token was sent to, provided the password has not changed since.
+func (r *PasswordReset) user(ctx context.Context, payload passwordResetPayload) (domain.User, error) {
+	user, err := r.users.GetByID(ctx, payload.UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			return nil, ErrInvalidToken
+		}
+
+		return nil, fmt.Errorf("failed to get user by id: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(payload.Password), []byte(passwordFingerprint(user.HashedPassword()))) != 1 {
+		return nil, ErrInvalidToken
+	}
+
+	return user, nil
+}
+
+// cooldownKey returns the cache key limiting how often resets for the address can be requested.
+func (r *PasswordReset) cooldownKey(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(email)))
+	return passwordResetCooldownKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// passwordFingerprint identifies the password hash without storing it next to the token.
+func passwordFingerprint(hashedPassword string) string {
+	sum := sha256.Sum256([]byte(hashedPassword))
+	return hex.EncodeToString(sum[:])
+}
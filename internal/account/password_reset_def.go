@@ -0,0 +1,83 @@
+package account
+
+import (
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/mail"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+const (
+	// DefPasswordResetName is the name of the *PasswordReset definition.
+	DefPasswordResetName = "account.password-reset"
+
+	configKeyPasswordResetTokenTTL = "auth.passwordReset.tokenTTL"
+	configKeyPasswordResetURL      = "auth.passwordReset.url"
+	configKeyPasswordResetInterval = "auth.passwordReset.interval"
+
+	passwordResetKeyPrefix = "account:password-reset:"
+)
+
+func init() {
+	di.Add(
+		di.Def[*PasswordReset]{
+			Name: DefPasswordResetName,
+			Build: func(ctn di.Container) (*PasswordReset, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var ttl time.Duration
+				if ttl = cfg.GetDuration(configKeyPasswordResetTokenTTL); ttl == 0 {
+					return nil, config.NewErrMissingKey(configKeyPasswordResetTokenTTL)
+				}
+
+				var linkURL string
+				if linkURL = cfg.GetString(configKeyPasswordResetURL); linkURL == "" {
+					return nil, config.NewErrMissingKey(configKeyPasswordResetURL)
+				}
+
+				var interval time.Duration
+				if interval = cfg.GetDuration(configKeyPasswordResetInterval); interval == 0 {
+					return nil, config.NewErrMissingKey(configKeyPasswordResetInterval)
+				}
+
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var cache *redis.Client
+				if err := ctn.Fill(redis.DefClientName, &cache); err != nil {
+					return nil, err
+				}
+
+				var mailer mail.Mailer
+				if err := ctn.Fill(mail.DefMailerName, &mailer); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				return NewPasswordReset(
+					log,
+					linkURL,
+					interval,
+					NewTokens(passwordResetKeyPrefix, ttl, cache),
+					cache,
+					mailer,
+					usersRep,
+				), nil
+			},
+		},
+	)
+}
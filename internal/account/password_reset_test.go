@@ -0,0 +1,242 @@
+package account_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/account/mocks"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+)
+
+const passwordResetURL = "http://localhost:3000/reset-password"
+
+func TestPasswordReset_Request(t *testing.T) {
+	user := domain.NewUser("user_id", "user@example.com", "hashed_password")
+	cooldownKey := "account:password-reset:cooldown:" + sha256Hex("user@example.com")
+
+	testCases := map[string]struct {
+		onSetNX      func() (bool, error)
+		onGetByEmail func() (domain.User, error)
+		onIssue      func() (string, error)
+		onSend       func() error
+		expSent      bool
+		expErr       error
+	}{
+		"failed to check interval": {
+			onSetNX: func() (bool, error) { return false, assert.AnError },
+			expErr:  assert.AnError,
+		},
+		"reset requested too recently": {
+			onSetNX: func() (bool, error) { return false, nil },
+			expErr:  account.ErrTooManyRequests,
+		},
+		"user not found": {
+			onSetNX:      func() (bool, error) { return true, nil },
+			onGetByEmail: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+		},
+		// the request is answered before the email is sent, so failures are only logged
+		"failed to get user": {
+			onSetNX:      func() (bool, error) { return true, nil },
+			onGetByEmail: func() (domain.User, error) { return nil, assert.AnError },
+		},
+		"failed to issue token": {
+			onSetNX:      func() (bool, error) { return true, nil },
+			onGetByEmail: func() (domain.User, error) { return user, nil },
+			onIssue:      func() (string, error) { return "", assert.AnError },
+		},
+		"failed to send message": {
+			onSetNX:      func() (bool, error) { return true, nil },
+			onGetByEmail: func() (domain.User, error) { return user, nil },
+			onIssue:      func() (string, error) { return "token", nil },
+			onSend:       func() error { return assert.AnError },
+		},
+		"positive case": {
+			onSetNX:      func() (bool, error) { return true, nil },
+			onGetByEmail: func() (domain.User, error) { return user, nil },
+			onIssue:      func() (string, error) { return "token", nil },
+			onSend:       func() error { return nil },
+			expSent:      true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewPasswordResetCache(t)
+			cache.On("SetNX", t.Context(), cooldownKey, "", time.Minute).Return(tc.onSetNX())
+
+			// the email is sent with a context that outlives the request
+			users := mocks.NewPasswordResetUsers(t)
+			if tc.onGetByEmail != nil {
+				users.On("GetByEmail", mock.Anything, "user@example.com").Return(tc.onGetByEmail())
+			}
+
+			tokens := mocks.NewTokenStore(t)
+			if tc.onIssue != nil {
+				tokens.On("Issue", mock.Anything, mock.Anything).Return(tc.onIssue())
+			}
+
+			var msg mail.Message
+
+			mailer := mocks.NewMailer(t)
+			if tc.onSend != nil {
+				mailer.On("Send", mock.Anything, mock.AnythingOfType("mail.Message")).
+					Run(func(args mock.Arguments) { msg = args.Get(1).(mail.Message) }).
+					Return(tc.onSend())
+			}
+
+			reset := account.NewPasswordReset(zap.NewNop(), passwordResetURL, time.Minute, tokens, cache, mailer, users)
+
+			err := reset.Request(t.Context(), "user@example.com")
+			assert.ErrorIs(t, err, tc.expErr)
+
+			reset.Wait()
+
+			if tc.expSent {
+				assert.Equal(t, "user@example.com", msg.To)
+				assert.Equal(t, "Reset your password", msg.Subject)
+				assert.Equal(t, "token", verificationLink(t, msg.Body).Query().Get("token"))
+			}
+		})
+	}
+}
+
+func TestPasswordReset_LookupAndRedeem(t *testing.T) {
+	// issuedPayload returns the payload of a token sent to a user with the given password hash.
+	issuedPayload := func(t *testing.T, hashedPassword string) []byte {
+		user := domain.NewUser("user_id", "user@example.com", hashedPassword)
+
+		var payload []byte
+
+		cache := mocks.NewPasswordResetCache(t)
+		cache.On("SetNX", t.Context(), mock.Anything, "", time.Minute).Return(true, nil)
+
+		users := mocks.NewPasswordResetUsers(t)
+		users.On("GetByEmail", mock.Anything, "user@example.com").Return(user, nil)
+
+		tokens := mocks.NewTokenStore(t)
+		tokens.On("Issue", mock.Anything, mock.Anything).
+			Run(func(args mock.Arguments) {
+				var err error
+				if payload, err = json.Marshal(args.Get(1)); err != nil {
+					t.Fatal(err)
+				}
+			}).
+			Return("token", nil)
+
+		mailer := mocks.NewMailer(t)
+		mailer.On("Send", mock.Anything, mock.AnythingOfType("mail.Message")).Return(nil)
+
+		reset := account.NewPasswordReset(zap.NewNop(), passwordResetURL, time.Minute, tokens, cache, mailer, users)
+		if err := reset.Request(t.Context(), "user@example.com"); err != nil {
+			t.Fatal(err)
+		}
+
+		reset.Wait()
+
+		return payload
+	}
+
+	testCases := map[string]struct {
+		onRedeem  func() error
+		onGetByID func() (domain.User, error)
+		expUserID string
+		expErr    error
+	}{
+		"invalid token": {
+			onRedeem: func() error { return account.ErrInvalidToken },
+			expErr:   account.ErrInvalidToken,
+		},
+		"user not found": {
+			onRedeem:  func() error { return nil },
+			onGetByID: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			expErr:    account.ErrInvalidToken,
+		},
+		"failed to get user": {
+			onRedeem:  func() error { return nil },
+			onGetByID: func() (domain.User, error) { return nil, assert.AnError },
+			expErr:    assert.AnError,
+		},
+		"password has changed": {
+			onRedeem: func() error { return nil },
+			onGetByID: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "new_hashed_password"), nil
+			},
+			expErr: account.ErrInvalidToken,
+		},
+		"positive case": {
+			onRedeem: func() error { return nil },
+			onGetByID: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			expUserID: "user_id",
+		},
+	}
+
+	// Lookup and Redeem check tokens the same way and differ only in whether the token is consumed.
+	methods := map[string]struct {
+		tokenMethod string
+		call        func(reset *account.PasswordReset, token string) (domain.User, error)
+	}{
+		"Lookup": {
+			tokenMethod: "Peek",
+			call: func(reset *account.PasswordReset, token string) (domain.User, error) {
+				return reset.Lookup(t.Context(), token)
+			},
+		},
+		"Redeem": {
+			tokenMethod: "Redeem",
+			call: func(reset *account.PasswordReset, token string) (domain.User, error) {
+				return reset.Redeem(t.Context(), token)
+			},
+		},
+	}
+
+	for method, m := range methods {
+		for name, tc := range testCases {
+			t.Run(method+"/"+name, func(t *testing.T) {
+				payload := issuedPayload(t, "hashed_password")
+
+				tokens := mocks.NewTokenStore(t)
+				tokens.On(m.tokenMethod, t.Context(), "token", mock.Anything).
+					Run(func(args mock.Arguments) { assert.NoError(t, json.Unmarshal(payload, args.Get(2))) }).
+					Return(tc.onRedeem())
+
+				users := mocks.NewPasswordResetUsers(t)
+				if tc.onGetByID != nil {
+					users.On("GetByID", t.Context(), "user_id").Return(tc.onGetByID())
+				}
+
+				reset := account.NewPasswordReset(
+					zap.NewNop(),
+					passwordResetURL,
+					time.Minute,
+					tokens,
+					mocks.NewPasswordResetCache(t),
+					mocks.NewMailer(t),
+					users,
+				)
+
+				user, err := m.call(reset, "token")
+				assert.ErrorIs(t, err, tc.expErr)
+
+				if tc.expUserID == "" {
+					assert.Nil(t, user)
+					return
+				}
+
+				if !assert.NotNil(t, user) {
+					t.FailNow()
+				}
+
+				assert.Equal(t, tc.expUserID, user.ID())
+			})
+		}
+	}
+}
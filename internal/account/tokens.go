@@ -0,0 +1,112 @@
+package account
+
+//go:generate mockery --name Cache --output ./mocks --outpkg mocks --filename cache.go --structname Cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/random"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+// ErrInvalidToken is returned when a token is unknown, expired or already used.
+var ErrInvalidToken = errors.New("invalid token")
+
+const tokenSize = 32
+
+// NewTokens creates a new *Tokens instance keeping tokens under the key prefix for the given TTL.
+func NewTokens(
+	prefix string,
+	ttl time.Duration,
+	cache Cache,
+) *Tokens {
+	return &Tokens{
+		prefix: prefix,
+		ttl:    ttl,
+		cache:  cache,
+	}
+}
+
+type (
+	// Tokens keeps single-use tokens sent to users by email, each bound to a JSON-encoded payload.
+	// Only hashes of the tokens are stored, so the cache contents cannot be used to take over accounts.
+	Tokens struct {
+		prefix string
+		ttl    time.Duration
+		cache  Cache
+	}
+
+	// Cache defines methods for storing values with a TTL, reading them, and atomically reading and removing them.
+	Cache interface {
+		Set(ctx context.Context, key string, value any, ttl time.Duration) error
+		Get(ctx context.Context, key string) (string, error)
+		GetDel(ctx context.Context, key string) (string, error)
+	}
+)
+
+// Issue returns a new token bound to the payload.
+func (t *Tokens) Issue(ctx context.Context, payload any) (string, error) {
+	value, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token payload: %w", err)
+	}
+
+	var token string
+	if token, err = random.String(tokenSize); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	if err = t.cache.Set(ctx, t.key(token), string(value), t.ttl); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// Peek decodes the payload of the token into the value pointed to by payload without redeeming the token.
+// Returns ErrInvalidToken if the token is unknown, expired or was already redeemed.
+func (t *Tokens) Peek(ctx context.Context, token string, payload any) error {
+	value, err := t.cache.Get(ctx, t.key(token))
+	return t.decode(value, err, payload)
+}
+
+// Redeem removes the token and decodes its payload into the value pointed to by payload.
+// Returns ErrInvalidToken if the token is unknown, expired or was already redeemed.
+func (t *Tokens) Redeem(ctx context.Context, token string, payload any) error {
+	value, err := t.cache.GetDel(ctx, t.key(token))
+	return t.decode(value, err, payload)
+}
+
+// TTL returns the lifetime of issued tokens.
+func (t *Tokens) TTL() time.Duration {
+	return t.ttl
+}
+
+// decode decodes the payload read from the cache, translating a missing key into ErrInvalidToken.
+func (t *Tokens) decode(value string, err error, payload any) error {
+	if err != nil {
+		if errors.Is(err, redis.ErrNotFound) {
+			return ErrInvalidToken
+		}
+
+		return err
+	}
+
+	if err = json.Unmarshal([]byte(value), payload); err != nil {
+		return fmt.Errorf("failed to unmarshal token payload: %w", err)
+	}
+
+	return nil
+}
+
+// key returns the cache key for the token. Only the hash is stored.
+func (t *Tokens) key(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return t.prefix + hex.EncodeToString(sum[:])
+}
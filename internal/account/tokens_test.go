@@ -0,0 +1,132 @@
+package account_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/account/mocks"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+type payload struct {
+	UserID string `json:"user_id"`
+}
+
+func TestTokens_Issue(t *testing.T) {
+	t.Run("failed to save token", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("Set", t.Context(), mock.AnythingOfType("string"), `{"user_id":"user_id"}`, time.Minute).
+			Return(assert.AnError)
+
+		token, err := account.NewTokens("prefix:", time.Minute, cache).Issue(t.Context(), payload{UserID: "user_id"})
+		assert.Empty(t, token)
+		assert.Equal(t, assert.AnError, err)
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		var key string
+
+		cache := mocks.NewCache(t)
+		cache.On("Set", t.Context(), mock.AnythingOfType("string"), `{"user_id":"user_id"}`, time.Minute).
+			Run(func(args mock.Arguments) { key = args.String(1) }).
+			Return(nil)
+
+		token, err := account.NewTokens("prefix:", time.Minute, cache).Issue(t.Context(), payload{UserID: "user_id"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+		assert.Equal(t, tokenKey(token), key)
+	})
+}
+
+func TestTokens_Peek(t *testing.T) {
+	key := tokenKey("token")
+
+	testCases := map[string]struct {
+		onGet      func(cache *mocks.Cache)
+		expPayload payload
+		expErr     error
+	}{
+		"unknown token": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return("", redis.ErrNotFound)
+			},
+			expErr: account.ErrInvalidToken,
+		},
+		"failed to get token": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return("", assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"positive case": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return(`{"user_id":"user_id"}`, nil)
+			},
+			expPayload: payload{UserID: "user_id"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewCache(t)
+			tc.onGet(cache)
+
+			var actual payload
+			err := account.NewTokens("prefix:", time.Minute, cache).Peek(t.Context(), "token", &actual)
+			assert.Equal(t, tc.expErr, err)
+			assert.Equal(t, tc.expPayload, actual)
+		})
+	}
+}
+
+func TestTokens_Redeem(t *testing.T) {
+	key := tokenKey("token")
+
+	testCases := map[string]struct {
+		onGetDel   func(cache *mocks.Cache)
+		expPayload payload
+		expErr     error
+	}{
+		"unknown token": {
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), key).Return("", redis.ErrNotFound)
+			},
+			expErr: account.ErrInvalidToken,
+		},
+		"failed to get token": {
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), key).Return("", assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"positive case": {
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), key).Return(`{"user_id":"user_id"}`, nil)
+			},
+			expPayload: payload{UserID: "user_id"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewCache(t)
+			tc.onGetDel(cache)
+
+			var actual payload
+			err := account.NewTokens("prefix:", time.Minute, cache).Redeem(t.Context(), "token", &actual)
+			assert.Equal(t, tc.expErr, err)
+			assert.Equal(t, tc.expPayload, actual)
+		})
+	}
+}
+
+func tokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "prefix:" + hex.EncodeToString(sum[:])
+}
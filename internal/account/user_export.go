@@ -0,0 +1,58 @@
+package account
+
+//go:generate mockery --name UserExportSource --output ./mocks --outpkg mocks --filename user_export_source.go --structname UserExportSource
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewUserExporter creates a new *UserExporter instance.
+func NewUserExporter(source UserExportSource) *UserExporter {
+	return &UserExporter{
+		source: source,
+	}
+}
+
+type (
+	// UserExporter writes all users as user records.
+	UserExporter struct {
+		source UserExportSource
+	}
+
+	// UserExportSource defines methods for streaming all users.
+	UserExportSource interface {
+		// Each calls fn for every user, stopping at the first error.
+		Each(ctx context.Context, fn func(user domain.User) error) error
+	}
+)
+
+// Export streams all users to the writer and returns how many were written. Whether password hashes
+// are included is up to the writer.
+func (e *UserExporter) Export(ctx context.Context, records UserRecordWriter) (int, error) {
+	var exported int
+	if err := e.source.Each(ctx, func(user domain.User) error {
+		if err := records.Write(UserRecord{
+			ID:            user.ID(),
+			Email:         user.Email(),
+			EmailVerified: user.EmailVerified(),
+			PasswordHash:  user.HashedPassword(),
+		}); err != nil {
+			return fmt.Errorf("failed to write user record: %w", err)
+		}
+
+		exported++
+
+		return nil
+	}); err != nil {
+		return exported, fmt.Errorf("failed to export users: %w", err)
+	}
+
+	if err := records.Flush(); err != nil {
+		return exported, fmt.Errorf("failed to flush user records: %w", err)
+	}
+
+	return exported, nil
+}
@@ -0,0 +1,60 @@
+package account_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/account/mocks"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+func TestUserExporter_Export(t *testing.T) {
+	users := []domain.User{
+		domain.NewUser("user_id_1", "user1@example.com", "hashed_password", domain.WithEmailVerified()),
+		domain.NewUser("user_id_2", "user2@example.com", "hashed_password"),
+	}
+
+	t.Run("failed to stream users", func(t *testing.T) {
+		source := mocks.NewUserExportSource(t)
+		source.On("Each", t.Context(), mock.Anything).Return(assert.AnError)
+
+		writer, err := account.NewUserRecordWriter(&bytes.Buffer{}, account.FormatJSONL, false)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		_, err = account.NewUserExporter(source).Export(t.Context(), writer)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		source := mocks.NewUserExportSource(t)
+		source.On("Each", t.Context(), mock.Anything).Return(func(_ context.Context, fn func(domain.User) error) error {
+			for _, user := range users {
+				if err := fn(user); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+		var output bytes.Buffer
+		writer, err := account.NewUserRecordWriter(&output, account.FormatCSV, true)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		exported, err := account.NewUserExporter(source).Export(t.Context(), writer)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, exported)
+		assert.Equal(t, "id,email,email_verified,password_hash\n"+
+			"user_id_1,user1@example.com,true,hashed_password\n"+
+			"user_id_2,user2@example.com,false,hashed_password\n", output.String())
+	})
+}
@@ -0,0 +1,254 @@
+package account
+
+//go:generate mockery --name UserImportStore --output ./mocks --outpkg mocks --filename user_import_store.go --structname UserImportStore
+//go:generate mockery --name HashChecker --output ./mocks --outpkg mocks --filename hash_checker.go --structname HashChecker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewUserImporter creates a new *UserImporter instance inserting users in batches of batchSize.
+func NewUserImporter(store UserImportStore, hashes HashChecker, batchSize int) *UserImporter {
+	return &UserImporter{
+		store:     store,
+		hashes:    hashes,
+		batchSize: batchSize,
+	}
+}
+
+type (
+	// UserImporter imports users with existing password hashes, e.g. when migrating from another system.
+	// Invalid records and users that already exist are skipped and reported, so an interrupted import
+	// can simply be run again.
+	UserImporter struct {
+		store     UserImportStore
+		hashes    HashChecker
+		batchSize int
+	}
+
+	// UserImportStore defines methods for bulk inserting users.
+	UserImportStore interface {
+		// Existing returns those of the ids and emails that already belong to users.
+		Existing(ctx context.Context, ids []string, emails []string) (existingIDs []string, existingEmails []string, err error)
+		// Import inserts the users and returns how many were inserted.
+		Import(ctx context.Context, users []domain.User) (int64, error)
+	}
+
+	// HashChecker decodes encoded password hashes.
+	HashChecker interface {
+		// Check returns an error describing why the encoded hash cannot be verified, if it cannot.
+		Check(encoded string) error
+	}
+
+	// ImportReport summarizes an import. Imported counts the users that would be inserted in a dry run.
+	ImportReport struct {
+		Read     int
+		Imported int
+		Rejected int
+	}
+
+	// Rejection describes a record that was not imported.
+	Rejection struct {
+		Line   int
+		Email  string
+		Reason string
+	}
+
+	// importBatch holds validated users waiting to be inserted with the lines they were read from.
+	importBatch struct {
+		users []domain.User
+		lines []int
+	}
+)
+
+// Import reads all records and inserts the valid ones, calling reject for every record that is skipped.
+// Nothing is inserted if dryRun is set. Emails are unique regardless of case: the first record wins
+// and later ones are rejected.
+func (i *UserImporter) Import(
+	ctx context.Context,
+	records UserRecordReader,
+	dryRun bool,
+	reject func(Rejection),
+) (ImportReport, error) {
+	var (
+		report ImportReport
+		batch  importBatch
+		// emails and ids seen in the file so far with their lines
+		seenEmails = make(map[string]int)
+		seenIDs    = make(map[string]int)
+	)
+
+	rejectRecord := func(line int, email string, reason string) {
+		report.Rejected++
+		reject(Rejection{Line: line, Email: email, Reason: reason})
+	}
+
+	for {
+		record, err := records.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			var recordErr *RecordError
+			if !errors.As(err, &recordErr) {
+				return report, fmt.Errorf("failed to read record: %w", err)
+			}
+
+			report.Read++
+			rejectRecord(records.Line(), "", recordErr.Error())
+			continue
+		}
+
+		report.Read++
+		line := records.Line()
+
+		if reason := i.validate(&record); reason != "" {
+			rejectRecord(line, record.Email, reason)
+			continue
+		}
+
+		emailKey := strings.ToLower(record.Email)
+		if first, ok := seenEmails[emailKey]; ok {
+			rejectRecord(line, record.Email, fmt.Sprintf("duplicate email, first seen on line %d", first))
+			continue
+		}
+
+		if first, ok := seenIDs[record.ID]; ok {
+			rejectRecord(line, record.Email, fmt.Sprintf("duplicate id, first seen on line %d", first))
+			continue
+		}
+
+		seenEmails[emailKey], seenIDs[record.ID] = line, line
+
+		var opts []domain.UserOption
+		if record.EmailVerified {
+			opts = append(opts, domain.WithEmailVerified())
+		}
+
+		batch.users = append(batch.users, domain.NewUser(record.ID, record.Email, record.PasswordHash, opts...))
+		batch.lines = append(batch.lines, line)
+
+		if len(batch.users) >= i.batchSize {
+			if err = i.flush(ctx, &batch, dryRun, &report, rejectRecord); err != nil {
+				return report, err
+			}
+		}
+	}
+
+	if err := i.flush(ctx, &batch, dryRun, &report, rejectRecord); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+// validate normalizes the record and returns the reason it cannot be imported, if any.
+// Records without an id get a random one.
+func (i *UserImporter) validate(record *UserRecord) string {
+	if record.Email == "" {
+		return "email is missing"
+	}
+
+	if address, err := mail.ParseAddress(record.Email); err != nil || address.Address != record.Email {
+		return "invalid email"
+	}
+
+	if record.PasswordHash == "" {
+		return "password hash is missing"
+	}
+
+	// hashes are fully decoded, so that a truncated hash or one too costly to verify is reported here
+	// instead of failing every login of the user
+	if err := i.hashes.Check(record.PasswordHash); err != nil {
+		return err.Error()
+	}
+
+	if record.ID == "" {
+		record.ID = uuid.NewString()
+		return ""
+	}
+
+	id, err := uuid.Parse(record.ID)
+	if err != nil {
+		return "invalid id"
+	}
+
+	record.ID = id.String()
+
+	return ""
+}
+
+// flush rejects the users of the batch that already exist and inserts the rest, unless dryRun is set.
+func (i *UserImporter) flush(
+	ctx context.Context,
+	batch *importBatch,
+	dryRun bool,
+	report *ImportReport,
+	rejectRecord func(line int, email string, reason string),
+) error {
+	if len(batch.users) == 0 {
+		return nil
+	}
+
+	defer func() {
+		batch.users, batch.lines = batch.users[:0], batch.lines[:0]
+	}()
+
+	ids, emails := make([]string, len(batch.users)), make([]string, len(batch.users))
+	for n, user := range batch.users {
+		ids[n], emails[n] = user.ID(), user.Email()
+	}
+
+	existingIDs, existingEmails, err := i.store.Existing(ctx, ids, emails)
+	if err != nil {
+		return fmt.Errorf("failed to check existing users: %w", err)
+	}
+
+	idTaken, emailTaken := toSet(existingIDs), toSet(existingEmails)
+
+	users := make([]domain.User, 0, len(batch.users))
+	for n, user := range batch.users {
+		switch {
+		case emailTaken[user.Email()]:
+			rejectRecord(batch.lines[n], user.Email(), "email already exists")
+		case idTaken[user.ID()]:
+			rejectRecord(batch.lines[n], user.Email(), "id already exists")
+		default:
+			users = append(users, user)
+		}
+	}
+
+	if dryRun || len(users) == 0 {
+		report.Imported += len(users)
+		return nil
+	}
+
+	var imported int64
+	if imported, err = i.store.Import(ctx, users); err != nil {
+		return fmt.Errorf("failed to import users from lines %d-%d: %w", batch.lines[0], batch.lines[len(batch.lines)-1], err)
+	}
+
+	report.Imported += int(imported)
+
+	return nil
+}
+
+// toSet converts the values to a set.
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, value := range values {
+		set[value] = true
+	}
+
+	return set
+}
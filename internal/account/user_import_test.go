@@ -0,0 +1,158 @@
+package account_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/account/mocks"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+const importInput = "id,email,password_hash,email_verified\n" +
+	"11111111-1111-1111-1111-111111111111,user1@example.com,$2a$10$hash,true\n" +
+	",user2@example.com,$2a$10$hash,\n" +
+	",,$2a$10$hash,\n" +
+	",not an email,$2a$10$hash,\n" +
+	",user5@example.com,,\n" +
+	",user6@example.com,md5hash,\n" +
+	"not-a-uuid,user7@example.com,$2a$10$hash,\n" +
+	",USER1@example.com,$2a$10$hash,\n" +
+	"11111111-1111-1111-1111-111111111111,user9@example.com,$2a$10$hash,\n" +
+	",user10@example.com,$2a$10$hash,yes\n" +
+	",taken@example.com,$2a$10$hash,\n" +
+	",user12@example.com,\"$argon2id$v=19$m=4194304,t=2,p=1$c2FsdA$aGFzaA\",\n"
+
+var expImportRejections = []account.Rejection{
+	{Line: 4, Reason: "email is missing"},
+	{Line: 5, Email: "not an email", Reason: "invalid email"},
+	{Line: 6, Email: "user5@example.com", Reason: "password hash is missing"},
+	{Line: 7, Email: "user6@example.com", Reason: "unknown password hash format"},
+	{Line: 8, Email: "user7@example.com", Reason: "invalid id"},
+	{Line: 9, Email: "USER1@example.com", Reason: "duplicate email, first seen on line 2"},
+	{Line: 10, Email: "user9@example.com", Reason: "duplicate id, first seen on line 2"},
+	{Line: 11, Reason: `invalid email_verified value "yes"`},
+	{Line: 13, Email: "user12@example.com", Reason: "invalid password hash: argon2id cost m=4194304,t=2,p=1 is too high"},
+	{Line: 12, Email: "taken@example.com", Reason: "email already exists"},
+}
+
+func TestUserImporter_Import(t *testing.T) {
+	testCases := map[string]struct {
+		dryRun      bool
+		onExisting  func() ([]string, []string, error)
+		onImport    func() (int64, error)
+		expImported int
+		expErr      error
+	}{
+		"failed to check existing users": {
+			onExisting: func() ([]string, []string, error) { return nil, nil, assert.AnError },
+			expErr:     assert.AnError,
+		},
+		"failed to import users": {
+			onExisting: func() ([]string, []string, error) { return nil, []string{"taken@example.com"}, nil },
+			onImport:   func() (int64, error) { return 0, assert.AnError },
+			expErr:     assert.AnError,
+		},
+		"dry run": {
+			dryRun:      true,
+			onExisting:  func() ([]string, []string, error) { return nil, []string{"taken@example.com"}, nil },
+			expImported: 2,
+		},
+		"positive case": {
+			onExisting:  func() ([]string, []string, error) { return nil, []string{"taken@example.com"}, nil },
+			onImport:    func() (int64, error) { return 2, nil },
+			expImported: 2,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			hashes := mocks.NewHashChecker(t)
+			hashes.On("Check", "$2a$10$hash").Return(nil)
+			hashes.On("Check", "md5hash").Return(password.ErrUnknownHash)
+			hashes.On("Check", "$argon2id$v=19$m=4194304,t=2,p=1$c2FsdA$aGFzaA").
+				Return(fmt.Errorf("%w: argon2id cost m=4194304,t=2,p=1 is too high", password.ErrInvalidHash))
+
+			store := mocks.NewUserImportStore(t)
+			store.On("Existing", t.Context(), mock.AnythingOfType("[]string"), mock.AnythingOfType("[]string")).
+				Return(tc.onExisting())
+
+			var imported []domain.User
+			if tc.onImport != nil {
+				store.On("Import", t.Context(), mock.AnythingOfType("[]domain.User")).
+					Run(func(args mock.Arguments) { imported = args.Get(1).([]domain.User) }).
+					Return(tc.onImport())
+			}
+
+			records, err := account.NewUserRecordReader(strings.NewReader(importInput), account.FormatCSV)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			var rejections []account.Rejection
+			report, err := account.NewUserImporter(store, hashes, 100).
+				Import(t.Context(), records, tc.dryRun, func(rejection account.Rejection) {
+					rejections = append(rejections, rejection)
+				})
+			assert.ErrorIs(t, err, tc.expErr)
+
+			if tc.expErr != nil {
+				return
+			}
+
+			assert.Equal(t, account.ImportReport{Read: 12, Imported: tc.expImported, Rejected: 10}, report)
+			assert.Equal(t, expImportRejections, rejections)
+
+			if tc.dryRun {
+				return
+			}
+
+			if !assert.Len(t, imported, 2) {
+				t.FailNow()
+			}
+
+			assert.Equal(t, "11111111-1111-1111-1111-111111111111", imported[0].ID())
+			assert.Equal(t, "user1@example.com", imported[0].Email())
+			assert.Equal(t, "$2a$10$hash", imported[0].HashedPassword())
+			assert.True(t, imported[0].EmailVerified())
+
+			assert.NotEmpty(t, imported[1].ID(), "users without an id get a random one")
+			assert.Equal(t, "user2@example.com", imported[1].Email())
+			assert.False(t, imported[1].EmailVerified())
+		})
+	}
+
+	t.Run("inserts in batches", func(t *testing.T) {
+		hashes := mocks.NewHashChecker(t)
+		hashes.On("Check", "$2a$10$hash").Return(nil)
+
+		store := mocks.NewUserImportStore(t)
+		store.On("Existing", t.Context(), mock.AnythingOfType("[]string"), mock.AnythingOfType("[]string")).
+			Return(nil, nil, nil).Times(3)
+
+		var batchSizes []int
+		store.On("Import", t.Context(), mock.AnythingOfType("[]domain.User")).
+			Run(func(args mock.Arguments) { batchSizes = append(batchSizes, len(args.Get(1).([]domain.User))) }).
+			Return(int64(0), nil).Times(3)
+
+		input := "email,password_hash\n"
+		for _, name := range []string{"a", "b", "c", "d", "e"} {
+			input += name + "@example.com,$2a$10$hash\n"
+		}
+
+		records, err := account.NewUserRecordReader(strings.NewReader(input), account.FormatCSV)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		_, err = account.NewUserImporter(store, hashes, 2).
+			Import(t.Context(), records, false, func(account.Rejection) { t.Error("unexpected rejection") })
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 2, 1}, batchSizes)
+	})
+}
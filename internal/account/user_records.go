@@ -0,0 +1,277 @@
+package account
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FormatCSV is a CSV file with a header row naming the columns of UserRecord.
+	FormatCSV = "csv"
+	// FormatJSONL is a file with one JSON encoded UserRecord per line.
+	FormatJSONL = "jsonl"
+
+	csvColumnID            = "id"
+	csvColumnEmail         = "email"
+	csvColumnEmailVerified = "email_verified"
+	csvColumnPasswordHash  = "password_hash"
+)
+
+// ErrUnknownFormat is returned for user record formats other than FormatCSV and FormatJSONL.
+var ErrUnknownFormat = errors.New("unknown user record format")
+
+// FormatFromPath guesses the format of a user records file from its extension.
+func FormatFromPath(path string) (string, error) {
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		return FormatCSV, nil
+	case ".jsonl", ".ndjson":
+		return FormatJSONL, nil
+	default:
+		return "", fmt.Errorf("%w: cannot guess the format of %q", ErrUnknownFormat, path)
+	}
+}
+
+type (
+	// UserRecord is a user as it is imported and exported. PasswordHash is an encoded hash of any format
+	// the password hasher can verify and is empty in exports without hashes.
+	UserRecord struct {
+		ID            string `json:"id,omitempty"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		PasswordHash  string `json:"password_hash,omitempty"`
+	}
+
+	// UserRecordReader reads user records one at a time. Read returns io.EOF after the last record.
+	// Line returns the line of the file the last record was read from.
+	UserRecordReader interface {
+		Read() (UserRecord, error)
+		Line() int
+	}
+
+	// UserRecordWriter writes user records one at a time. Flush must be called after the last record.
+	UserRecordWriter interface {
+		Write(record UserRecord) error
+		Flush() error
+	}
+
+	// RecordError is returned by UserRecordReader for a record that cannot be decoded. Reading can go on
+	// with the next record.
+	RecordError struct {
+		Err error
+	}
+
+	csvRecordReader struct {
+		reader  *csv.Reader
+		columns map[string]int
+		line    int
+	}
+
+	jsonlRecordReader struct {
+		scanner *bufio.Scanner
+		line    int
+	}
+
+	csvRecordWriter struct {
+		writer     *csv.Writer
+		withHashes bool
+	}
+
+	jsonlRecordWriter struct {
+		writer     *bufio.Writer
+		encoder    *json.Encoder
+		withHashes bool
+	}
+)
+
+// Error returns the reason the record cannot be decoded.
+func (e *RecordError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// NewUserRecordReader creates a UserRecordReader decoding records of the given format from r.
+// CSV input must start with a header row, in which the id and email_verified columns are optional.
+func NewUserRecordReader(r io.Reader, format string) (UserRecordReader, error) {
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.ReuseRecord = true
+
+		header, err := reader.Read()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read csv header: %w", err)
+		}
+
+		columns := make(map[string]int, len(header))
+		for i, column := range header {
+			columns[strings.ToLower(strings.TrimSpace(column))] = i
+		}
+
+		for _, column := range []string{csvColumnEmail, csvColumnPasswordHash} {
+			if _, ok := columns[column]; !ok {
+				return nil, fmt.Errorf("csv header has no %q column", column)
+			}
+		}
+
+		return &csvRecordReader{reader: reader, columns: columns, line: 1}, nil
+	case FormatJSONL:
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+		return &jsonlRecordReader{scanner: scanner}, nil
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// NewUserRecordWriter creates a UserRecordWriter encoding records of the given format to w.
+// Password hashes are only written if withHashes is set.
+func NewUserRecordWriter(w io.Writer, format string, withHashes bool) (UserRecordWriter, error) {
+	switch format {
+	case FormatCSV:
+		writer := csv.NewWriter(w)
+
+		header := []string{csvColumnID, csvColumnEmail, csvColumnEmailVerified}
+		if withHashes {
+			header = append(header, csvColumnPasswordHash)
+		}
+
+		if err := writer.Write(header); err != nil {
+			return nil, fmt.Errorf("failed to write csv header: %w", err)
+		}
+
+		return &csvRecordWriter{writer: writer, withHashes: withHashes}, nil
+	case FormatJSONL:
+		writer := bufio.NewWriter(w)
+
+		return &jsonlRecordWriter{writer: writer, encoder: json.NewEncoder(writer), withHashes: withHashes}, nil
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnknownFormat, format)
+	}
+}
+
+// Read returns the next record.
+func (r *csvRecordReader) Read() (UserRecord, error) {
+	fields, err := r.reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return UserRecord{}, io.EOF
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			r.line = parseErr.StartLine
+			return UserRecord{}, &RecordError{Err: err}
+		}
+
+		return UserRecord{}, err
+	}
+
+	r.line, _ = r.reader.FieldPos(0)
+
+	field := func(column string) string {
+		if i, ok := r.columns[column]; ok && i < len(fields) {
+			return strings.TrimSpace(fields[i])
+		}
+
+		return ""
+	}
+
+	record := UserRecord{
+		ID:           field(csvColumnID),
+		Email:        field(csvColumnEmail),
+		PasswordHash: field(csvColumnPasswordHash),
+	}
+
+	if verified := field(csvColumnEmailVerified); verified != "" {
+		if record.EmailVerified, err = strconv.ParseBool(verified); err != nil {
+			return UserRecord{}, &RecordError{Err: fmt.Errorf("invalid email_verified value %q", verified)}
+		}
+	}
+
+	return record, nil
+}
+
+// Line returns the line of the file the last record was read from.
+func (r *csvRecordReader) Line() int {
+	return r.line
+}
+
+// Read returns the next record. Blank lines are skipped.
+func (r *jsonlRecordReader) Read() (UserRecord, error) {
+	for r.scanner.Scan() {
+		r.line++
+
+		line := r.scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var record UserRecord
+		if err := json.Unmarshal(line, &record); err != nil {
+			return UserRecord{}, &RecordError{Err: fmt.Errorf("invalid json: %w", err)}
+		}
+
+		record.ID = strings.TrimSpace(record.ID)
+		record.Email = strings.TrimSpace(record.Email)
+		record.PasswordHash = strings.TrimSpace(record.PasswordHash)
+
+		return record, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		r.line++
+		return UserRecord{}, err
+	}
+
+	return UserRecord{}, io.EOF
+}
+
+// Line returns the line of the file the last record was read from.
+func (r *jsonlRecordReader) Line() int {
+	return r.line
+}
+
+// Write writes the record as a CSV row.
+func (w *csvRecordWriter) Write(record UserRecord) error {
+	row := []string{record.ID, record.Email, strconv.FormatBool(record.EmailVerified)}
+	if w.withHashes {
+		row = append(row, record.PasswordHash)
+	}
+
+	return w.writer.Write(row)
+}
+
+// Flush writes any buffered rows.
+func (w *csvRecordWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// Write writes the record as a JSON line.
+func (w *jsonlRecordWriter) Write(record UserRecord) error {
+	if !w.withHashes {
+		record.PasswordHash = ""
+	}
+
+	return w.encoder.Encode(record)
+}
+
+// Flush writes any buffered lines.
+func (w *jsonlRecordWriter) Flush() error {
+	return w.writer.Flush()
+}
@@ -0,0 +1,189 @@
+package account_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+)
+
+func TestFormatFromPath(t *testing.T) {
+	testCases := map[string]struct {
+		path      string
+		expFormat string
+		expErr    error
+	}{
+		"csv":               {path: "users.csv", expFormat: account.FormatCSV},
+		"jsonl":             {path: "/tmp/users.jsonl", expFormat: account.FormatJSONL},
+		"ndjson":            {path: "users.NDJSON", expFormat: account.FormatJSONL},
+		"unknown extension": {path: "users.xlsx", expErr: account.ErrUnknownFormat},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			format, err := account.FormatFromPath(tc.path)
+			assert.ErrorIs(t, err, tc.expErr)
+			assert.Equal(t, tc.expFormat, format)
+		})
+	}
+}
+
+func TestNewUserRecordReader(t *testing.T) {
+	type result struct {
+		line      int
+		record    account.UserRecord
+		recordErr bool
+	}
+
+	testCases := map[string]struct {
+		format     string
+		input      string
+		expResults []result
+		expErr     bool
+	}{
+		"csv": {
+			format: account.FormatCSV,
+			input: "email,password_hash,ID,email_verified\n" +
+				"user1@example.com,$2a$10$hash,11111111-1111-1111-1111-111111111111,true\n" +
+				" user2@example.com , $argon2id$hash ,,\n" +
+				"user3@example.com,hash,,maybe\n" +
+				"\"user4@example.com,hash\n",
+			expResults: []result{
+				{line: 2, record: account.UserRecord{
+					ID:            "11111111-1111-1111-1111-111111111111",
+					Email:         "user1@example.com",
+					EmailVerified: true,
+					PasswordHash:  "$2a$10$hash",
+				}},
+				{line: 3, record: account.UserRecord{Email: "user2@example.com", PasswordHash: "$argon2id$hash"}},
+				{line: 4, recordErr: true},
+				{line: 5, recordErr: true},
+			},
+		},
+		"csv without required column": {
+			format: account.FormatCSV,
+			input:  "id,email\n",
+			expErr: true,
+		},
+		"empty csv": {
+			format: account.FormatCSV,
+			expErr: true,
+		},
+		"jsonl": {
+			format: account.FormatJSONL,
+			input: `{"id":"11111111-1111-1111-1111-111111111111","email":"user1@example.com","email_verified":true,"password_hash":"$2a$10$hash"}` + "\n" +
+				"\n" +
+				`{"email":" user2@example.com ","password_hash":"$argon2id$hash"}` + "\n" +
+				`{"email":` + "\n",
+			expResults: []result{
+				{line: 1, record: account.UserRecord{
+					ID:            "11111111-1111-1111-1111-111111111111",
+					Email:         "user1@example.com",
+					EmailVerified: true,
+					PasswordHash:  "$2a$10$hash",
+				}},
+				{line: 3, record: account.UserRecord{Email: "user2@example.com", PasswordHash: "$argon2id$hash"}},
+				{line: 4, recordErr: true},
+			},
+		},
+		"unknown format": {
+			format: "xml",
+			expErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			reader, err := account.NewUserRecordReader(strings.NewReader(tc.input), tc.format)
+			if tc.expErr {
+				assert.Error(t, err)
+				return
+			}
+
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			for _, exp := range tc.expResults {
+				record, err := reader.Read()
+
+				var recordErr *account.RecordError
+				assert.Equal(t, exp.recordErr, errors.As(err, &recordErr))
+				if !exp.recordErr {
+					assert.NoError(t, err)
+				}
+
+				assert.Equal(t, exp.record, record)
+				assert.Equal(t, exp.line, reader.Line())
+			}
+
+			_, err = reader.Read()
+			assert.ErrorIs(t, err, io.EOF)
+		})
+	}
+}
+
+func TestNewUserRecordWriter(t *testing.T) {
+	records := []account.UserRecord{
+		{ID: "user_id_1", Email: "user1@example.com", EmailVerified: true, PasswordHash: "$2a$10$hash"},
+		{ID: "user_id_2", Email: "user2@example.com", PasswordHash: "$argon2id$hash"},
+	}
+
+	testCases := map[string]struct {
+		format     string
+		withHashes bool
+		expOutput  string
+	}{
+		"csv": {
+			format: account.FormatCSV,
+			expOutput: "id,email,email_verified\n" +
+				"user_id_1,user1@example.com,true\n" +
+				"user_id_2,user2@example.com,false\n",
+		},
+		"csv with hashes": {
+			format:     account.FormatCSV,
+			withHashes: true,
+			expOutput: "id,email,email_verified,password_hash\n" +
+				"user_id_1,user1@example.com,true,$2a$10$hash\n" +
+				"user_id_2,user2@example.com,false,$argon2id$hash\n",
+		},
+		"jsonl": {
+			format: account.FormatJSONL,
+			expOutput: `{"id":"user_id_1","email":"user1@example.com","email_verified":true}` + "\n" +
+				`{"id":"user_id_2","email":"user2@example.com","email_verified":false}` + "\n",
+		},
+		"jsonl with hashes": {
+			format:     account.FormatJSONL,
+			withHashes: true,
+			expOutput: `{"id":"user_id_1","email":"user1@example.com","email_verified":true,"password_hash":"$2a$10$hash"}` + "\n" +
+				`{"id":"user_id_2","email":"user2@example.com","email_verified":false,"password_hash":"$argon2id$hash"}` + "\n",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			var output bytes.Buffer
+			writer, err := account.NewUserRecordWriter(&output, tc.format, tc.withHashes)
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			for _, record := range records {
+				assert.NoError(t, writer.Write(record))
+			}
+
+			assert.NoError(t, writer.Flush())
+			assert.Equal(t, tc.expOutput, output.String())
+		})
+	}
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := account.NewUserRecordWriter(io.Discard, "xml", false)
+		assert.ErrorIs(t, err, account.ErrUnknownFormat)
+	})
+}
@@ -0,0 +1,234 @@
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// signatureAlgorithm is the only algorithm bundles are signed with.
+const signatureAlgorithm = "Ed25519"
+
+var (
+	// ErrBundleNotSigned is returned when a bundle ends without a signature, e.g. because it was truncated.
+	ErrBundleNotSigned = errors.New("audit bundle is not signed")
+
+	// ErrInvalidBundleSignature is returned when the signature does not match the events of a bundle.
+	ErrInvalidBundleSignature = errors.New("audit bundle signature is invalid")
+)
+
+type (
+	// BundleWriter writes audit events as a JSONL bundle: an event per line, followed by a line with
+	// the signature of the SHA-256 of all the event lines.
+	BundleWriter struct {
+		out    io.Writer
+		signer *Signer
+		digest hash.Hash
+		chain  *Chain
+	}
+
+	bundleRecord struct {
+		Event     *bundleEvent     `json:"event,omitempty"`
+		Signature *bundleSignature `json:"signature,omitempty"`
+	}
+
+	bundleEvent struct {
+		ID        int64             `json:"id"`
+		Type      string            `json:"type"`
+		UserID    string            `json:"user_id"`
+		ActorID   string            `json:"actor_id"`
+		IP        string            `json:"ip"`
+		UserAgent string            `json:"user_agent"`
+		SessionID string            `json:"session_id"`
+		Outcome   string            `json:"outcome"`
+		Reason    string            `json:"reason"`
+		Details   map[string]string `json:"details"`
+		CreatedAt time.Time         `json:"created_at"`
+		PrevHash  string            `json:"prev_hash"`
+		Hash      string            `json:"hash"`
+	}
+
+	bundleSignature struct {
+		Algorithm string `json:"algorithm"`
+		Digest    string `json:"digest"`
+		Value     string `json:"value"`
+	}
+)
+
+// NewBundleWriter creates a new *BundleWriter that writes to out and signs the bundle with signer.
+func NewBundleWriter(out io.Writer, signer *Signer) *BundleWriter {
+	return &BundleWriter{
+		out:    out,
+		signer: signer,
+		digest: sha256.New(),
+		chain:  NewChain(),
+	}
+}
+
+// Write writes the event, refusing to write events that break the chain, so that a broken trail is never signed.
+func (w *BundleWriter) Write(event domain.AuditEvent) error {
+	if err := w.chain.Append(event); err != nil {
+		return err
+	}
+
+	line, err := marshalBundleRecord(bundleRecord{Event: &bundleEvent{
+		ID:        event.ID,
+		Type:      event.Type,
+		UserID:    event.UserID,
+		ActorID:   event.ActorID,
+		IP:        event.IP,
+		UserAgent: event.UserAgent,
+		SessionID: event.SessionID,
+		Outcome:   event.Outcome,
+		Reason:    event.Reason,
+		Details:   event.Details,
+		CreatedAt: event.CreatedAt.UTC(),
+		PrevHash:  event.PrevHash,
+		Hash:      event.Hash,
+	}})
+	if err != nil {
+		return err
+	}
+
+	if _, err = w.out.Write(line); err != nil {
+		return err
+	}
+
+	w.digest.Write(line)
+
+	return nil
+}
+
+// Close signs the written events and writes the signature. Nothing can be written after it.
+func (w *BundleWriter) Close() error {
+	digest := w.digest.Sum(nil)
+
+	line, err := marshalBundleRecord(bundleRecord{Signature: &bundleSignature{
+		Algorithm: signatureAlgorithm,
+		Digest:    hex.EncodeToString(digest),
+		Value:     base64.StdEncoding.EncodeToString(w.signer.Sign(digest)),
+	}})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.out.Write(line)
+
+	return err
+}
+
+// Chain returns the chain of the written events.
+func (w *BundleWriter) Chain() *Chain {
+	return w.chain
+}
+
+// VerifyBundle checks that the bundle read from in is signed with the key and that its events form a chain.
+// It needs nothing but the bundle and the public key, so that auditors can verify exports offline.
+func VerifyBundle(in io.Reader, key ed25519.PublicKey) (*Chain, error) {
+	var (
+		reader = bufio.NewReader(in)
+		digest = sha256.New()
+		chain  = NewChain()
+		signed bool
+	)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) && len(line) == 0 {
+			break
+		}
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("failed to read audit bundle: %w", err)
+		}
+
+		if signed {
+			return nil, fmt.Errorf("line %d: unexpected data after the signature", lineNumber)
+		}
+
+		var record bundleRecord
+		if err = json.Unmarshal(line, &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		switch {
+		case record.Event != nil:
+			digest.Write(line)
+
+			if err = chain.Append(record.Event.domainEvent()); err != nil {
+				return nil, err
+			}
+		case record.Signature != nil:
+			if err = verifyBundleSignature(*record.Signature, digest.Sum(nil), key); err != nil {
+				return nil, err
+			}
+
+			signed = true
+		default:
+			return nil, fmt.Errorf("line %d: neither an event nor a signature", lineNumber)
+		}
+	}
+
+	if !signed {
+		return nil, ErrBundleNotSigned
+	}
+
+	return chain, nil
+}
+
+// verifyBundleSignature checks that the signature matches the digest of the event lines.
+func verifyBundleSignature(signature bundleSignature, digest []byte, key ed25519.PublicKey) error {
+	if signature.Algorithm != signatureAlgorithm {
+		return fmt.Errorf("unsupported audit bundle signature algorithm %q", signature.Algorithm)
+	}
+
+	if signature.Digest != hex.EncodeToString(digest) {
+		return ErrInvalidBundleSignature
+	}
+
+	value, err := base64.StdEncoding.DecodeString(signature.Value)
+	if err != nil || !ed25519.Verify(key, digest, value) {
+		return ErrInvalidBundleSignature
+	}
+
+	return nil
+}
+
+// marshalBundleRecord returns the record as a line of the bundle.
+func marshalBundleRecord(record bundleRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(record); err != nil {
+		return nil, fmt.Errorf("failed to marshal audit bundle record: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// domainEvent converts the event read from a bundle back to domain.AuditEvent.
+func (e *bundleEvent) domainEvent() domain.AuditEvent {
+	return domain.AuditEvent{
+		ID:        e.ID,
+		Type:      e.Type,
+		UserID:    e.UserID,
+		ActorID:   e.ActorID,
+		IP:        e.IP,
+		UserAgent: e.UserAgent,
+		SessionID: e.SessionID,
+		Outcome:   e.Outcome,
+		Reason:    e.Reason,
+		Details:   e.Details,
+		CreatedAt: e.CreatedAt,
+		PrevHash:  e.PrevHash,
+		Hash:      e.Hash,
+	}
+}
@@ -0,0 +1,143 @@
+package audit_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+func TestBundleWriter_Write(t *testing.T) {
+	t.Run("broken chain is not written", func(t *testing.T) {
+		key, err := audit.ParsePrivateKey(testPrivateKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		events := testEvents()
+		events[1].Reason = "edited"
+
+		var buf bytes.Buffer
+		bundle := audit.NewBundleWriter(&buf, audit.NewSigner(key))
+
+		assert.NoError(t, bundle.Write(events[0]))
+
+		var brokenLink *audit.BrokenLinkError
+		assert.True(t, errors.As(bundle.Write(events[1]), &brokenLink))
+		assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+	})
+}
+
+func TestVerifyBundle(t *testing.T) {
+	privateKey, err := audit.ParsePrivateKey(testPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := audit.ParsePublicKey(testPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	bundle := audit.NewBundleWriter(&buf, audit.NewSigner(privateKey))
+	for _, event := range append([]domain.AuditEvent{{ID: 1, Type: domain.AuditEventImpersonation}}, testEvents()...) {
+		if err = bundle.Write(event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = bundle.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.SplitAfter(buf.String(), "\n")
+	lines = lines[:len(lines)-1]
+
+	anotherPublicKey, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name   string
+		bundle func() string
+		key    ed25519.PublicKey
+		expErr error
+	}{
+		{
+			name:   "positive case",
+			bundle: func() string { return strings.Join(lines, "") },
+			key:    publicKey,
+		},
+		{
+			name:   "another key",
+			bundle: func() string { return strings.Join(lines, "") },
+			key:    anotherPublicKey,
+			expErr: audit.ErrInvalidBundleSignature,
+		},
+		{
+			name: "legacy event is edited",
+			bundle: func() string {
+				return strings.Replace(strings.Join(lines, ""), `"type":"impersonation"`, `"type":"account_unlocked"`, 1)
+			},
+			key:    publicKey,
+			expErr: audit.ErrInvalidBundleSignature,
+		},
+		{
+			name: "event is edited",
+			bundle: func() string {
+				return strings.Replace(strings.Join(lines, ""), `"reason":"invalid_credentials"`, `"reason":""`, 1)
+			},
+			key:    publicKey,
+			expErr: &audit.BrokenLinkError{EventID: 3, Reason: "hash does not match the event"},
+		},
+		{
+			name:   "last event is removed",
+			bundle: func() string { return strings.Join(lines[:3], "") + lines[4] },
+			key:    publicKey,
+			expErr: audit.ErrInvalidBundleSignature,
+		},
+		{
+			name:   "signature is removed",
+			bundle: func() string { return strings.Join(lines[:4], "") },
+			key:    publicKey,
+			expErr: audit.ErrBundleNotSigned,
+		},
+		{
+			name:   "event after the signature",
+			bundle: func() string { return strings.Join(lines, "") + lines[3] },
+			key:    publicKey,
+			expErr: errors.New("line 6: unexpected data after the signature"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			chain, err := audit.VerifyBundle(strings.NewReader(testCase.bundle()), testCase.key)
+
+			var brokenLink *audit.BrokenLinkError
+			switch {
+			case testCase.expErr == nil:
+				if !assert.NoError(t, err) {
+					t.FailNow()
+				}
+
+				assert.Equal(t, 4, chain.Events())
+				assert.Equal(t, 1, chain.Unchained())
+				assert.Equal(t, testEvents()[2].Hash, chain.Head())
+			case errors.As(testCase.expErr, &brokenLink):
+				assert.Equal(t, testCase.expErr, err)
+			default:
+				assert.EqualError(t, err, testCase.expErr.Error())
+			}
+		})
+	}
+}
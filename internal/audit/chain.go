@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"fmt"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewChain creates a new *Chain instance.
+func NewChain() *Chain {
+	return &Chain{}
+}
+
+type (
+	// Chain checks that audit events, appended in the order they were saved, are linked by their hashes.
+	// Events saved before the hashes were introduced have none and are only accepted before the first hashed one.
+	Chain struct {
+		head      string
+		events    int
+		unchained int
+	}
+
+	// BrokenLinkError is returned for the first event that does not continue the chain.
+	BrokenLinkError struct {
+		EventID int64
+		Reason  string
+	}
+)
+
+// Error implements the error interface.
+func (e *BrokenLinkError) Error() string {
+	return fmt.Sprintf("broken link at audit event %d: %s", e.EventID, e.Reason)
+}
+
+// Append checks that the event continues the chain and makes it the new head.
+func (c *Chain) Append(event domain.AuditEvent) error {
+	if c.head == "" && event.PrevHash == "" && event.Hash == "" {
+		c.events++
+		c.unchained++
+
+		return nil
+	}
+
+	if event.PrevHash != c.head {
+		return &BrokenLinkError{EventID: event.ID, Reason: "previous hash does not match the preceding event"}
+	}
+
+	if event.Hash != event.ChainHash(event.PrevHash) {
+		return &BrokenLinkError{EventID: event.ID, Reason: "hash does not match the event"}
+	}
+
+	c.head = event.Hash
+	c.events++
+
+	return nil
+}
+
+// Head returns the hash of the last appended event, empty if none was hashed.
+func (c *Chain) Head() string {
+	return c.head
+}
+
+// Events returns the number of appended events.
+func (c *Chain) Events() int {
+	return c.events
+}
+
+// Unchained returns the number of appended events saved before the hashes were introduced.
+func (c *Chain) Unchained() int {
+	return c.unchained
+}
@@ -0,0 +1,145 @@
+package audit_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// chainedEvents returns events linked the way the repository saves them.
+func chainedEvents(events ...domain.AuditEvent) []domain.AuditEvent {
+	var prevHash string
+	for i := range events {
+		events[i].PrevHash = prevHash
+		events[i].Hash = events[i].ChainHash(prevHash)
+		prevHash = events[i].Hash
+	}
+
+	return events
+}
+
+func testEvents() []domain.AuditEvent {
+	createdAt := time.Date(2026, 10, 19, 12, 0, 0, 123456000, time.UTC)
+
+	return chainedEvents(
+		domain.AuditEvent{ID: 3, Type: domain.AuditEventLogin, UserID: "user_id", Outcome: domain.AuditOutcomeFailure, Reason: "invalid_credentials", Details: map[string]string{"method": "password"}, CreatedAt: createdAt},
+		domain.AuditEvent{ID: 4, Type: domain.AuditEventLogin, UserID: "user_id", Outcome: domain.AuditOutcomeSuccess, Details: map[string]string{"method": "password"}, CreatedAt: createdAt.Add(time.Second)},
+		domain.AuditEvent{ID: 5, Type: domain.AuditEventPasswordChange, UserID: "user_id", Outcome: domain.AuditOutcomeSuccess, Details: map[string]string{}, CreatedAt: createdAt.Add(time.Minute)},
+	)
+}
+
+func TestChain_Append(t *testing.T) {
+	unchained := []domain.AuditEvent{
+		{ID: 1, Type: domain.AuditEventImpersonation, Outcome: domain.AuditOutcomeSuccess},
+		{ID: 2, Type: domain.AuditEventAccountUnlocked, Outcome: domain.AuditOutcomeSuccess},
+	}
+
+	testCases := []struct {
+		name         string
+		events       func() []domain.AuditEvent
+		expErr       *audit.BrokenLinkError
+		expEvents    int
+		expUnchained int
+	}{
+		{
+			name:      "chained events",
+			events:    testEvents,
+			expEvents: 3,
+		},
+		{
+			name: "events saved before hashing",
+			events: func() []domain.AuditEvent {
+				return append(append([]domain.AuditEvent{}, unchained...), testEvents()...)
+			},
+			expEvents:    5,
+			expUnchained: 2,
+		},
+		{
+			name: "event is edited",
+			events: func() []domain.AuditEvent {
+				events := testEvents()
+				events[0].Outcome = domain.AuditOutcomeSuccess
+				return events
+			},
+			expErr: &audit.BrokenLinkError{EventID: 3, Reason: "hash does not match the event"},
+		},
+		{
+			name: "details are edited",
+			events: func() []domain.AuditEvent {
+				events := testEvents()
+				events[1].Details = map[string]string{"method": "webauthn"}
+				return events
+			},
+			expErr: &audit.BrokenLinkError{EventID: 4, Reason: "hash does not match the event"},
+		},
+		{
+			name: "event is removed",
+			events: func() []domain.AuditEvent {
+				events := testEvents()
+				return append(events[:1], events[2:]...)
+			},
+			expErr: &audit.BrokenLinkError{EventID: 5, Reason: "previous hash does not match the preceding event"},
+		},
+		{
+			name: "events are reordered",
+			events: func() []domain.AuditEvent {
+				events := testEvents()
+				events[1], events[2] = events[2], events[1]
+				return events
+			},
+			expErr: &audit.BrokenLinkError{EventID: 5, Reason: "previous hash does not match the preceding event"},
+		},
+		{
+			name: "event is edited and rehashed",
+			events: func() []domain.AuditEvent {
+				events := testEvents()
+				events[1].UserID = "another_user_id"
+				events[1].Hash = events[1].ChainHash(events[1].PrevHash)
+				return events
+			},
+			expErr: &audit.BrokenLinkError{EventID: 5, Reason: "previous hash does not match the preceding event"},
+		},
+		{
+			name: "hashes of a chained event are removed",
+			events: func() []domain.AuditEvent {
+				events := testEvents()
+				events[1].PrevHash, events[1].Hash = "", ""
+				return events
+			},
+			expErr: &audit.BrokenLinkError{EventID: 4, Reason: "previous hash does not match the preceding event"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			chain := audit.NewChain()
+
+			var err error
+			for _, event := range testCase.events() {
+				if err = chain.Append(event); err != nil {
+					break
+				}
+			}
+
+			if testCase.expErr != nil {
+				var brokenLink *audit.BrokenLinkError
+				if !assert.True(t, errors.As(err, &brokenLink)) {
+					t.FailNow()
+				}
+
+				assert.Equal(t, testCase.expErr, brokenLink)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expEvents, chain.Events())
+			assert.Equal(t, testCase.expUnchained, chain.Unchained())
+			assert.Equal(t, testEvents()[2].Hash, chain.Head())
+		})
+	}
+}
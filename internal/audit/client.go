@@ -0,0 +1,22 @@
+package audit
+
+import "context"
+
+// Client describes the client a request came from.
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+type clientKey struct{}
+
+// ContextWithClient returns a copy of ctx that carries the client, so that events recorded with it describe the client.
+func ContextWithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// ClientFromContext returns the client stored by ContextWithClient, or an empty Client if there is none.
+func ClientFromContext(ctx context.Context) Client {
+	client, _ := ctx.Value(clientKey{}).(Client)
+	return client
+}
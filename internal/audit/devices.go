@@ -0,0 +1,93 @@
+package audit
+
+//go:generate mockery --name ClientHistory --output ./mocks --outpkg mocks --filename client_history.go --structname ClientHistory
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// Prefix lengths of the networks addresses of the same device are expected to stay in,
+// as the IP of a device changes, e.g. when it reconnects.
+const (
+	ipv4DevicePrefix = 24
+	ipv6DevicePrefix = 64
+)
+
+// NewDevices creates a new *Devices instance that compares clients with the sessions of the last window.
+func NewDevices(window time.Duration, history ClientHistory) *Devices {
+	return &Devices{
+		window:  window,
+		history: history,
+	}
+}
+
+type (
+	// Devices recognizes the devices users have recently had sessions from.
+	Devices struct {
+		window  time.Duration
+		history ClientHistory
+	}
+
+	// ClientHistory describes ClientHistory dependency.
+	ClientHistory interface {
+		ClientsByUser(ctx context.Context, userID string, since time.Time) ([]domain.AuditEvent, error)
+	}
+)
+
+// IsKnown reports whether the client stored in ctx is a device one of the recent sessions of the user came from:
+// it has the same user agent and an IP in the same /24 IPv4 or /64 IPv6 network. Users without recent sessions,
+// e.g. right after registration, and requests without client information have nothing to compare with, so their
+// device is considered known.
+func (d *Devices) IsKnown(ctx context.Context, userID string) (bool, error) {
+	client := ClientFromContext(ctx)
+	if client.IP == "" && client.UserAgent == "" {
+		return true, nil
+	}
+
+	sessions, err := d.history.ClientsByUser(ctx, userID, time.Now().Add(-d.window))
+	if err != nil {
+		return false, fmt.Errorf("failed to get recent clients: %w", err)
+	}
+
+	if len(sessions) == 0 {
+		return true, nil
+	}
+
+	// user agents are stored sanitized, so the one of the client is compared in the same form
+	userAgent, network := sanitizeUserAgent(client.UserAgent), deviceNetwork(client.IP)
+	for _, session := range sessions {
+		if session.UserAgent == userAgent && deviceNetwork(session.IP) == network {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// deviceNetwork returns the network of the IP that the same device is expected to stay in.
+// Values that are not IPs are returned as they are.
+func deviceNetwork(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ip
+	}
+
+	addr = addr.Unmap()
+
+	bits := ipv6DevicePrefix
+	if addr.Is4() {
+		bits = ipv4DevicePrefix
+	}
+
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ip
+	}
+
+	return prefix.String()
+}
@@ -0,0 +1,43 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+const (
+	// DefDevicesName is the name of the *Devices definition.
+	DefDevicesName = "audit.devices"
+
+	configKeyNewDeviceWindow = "auth.newDevice.window"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Devices]{
+			Name: DefDevicesName,
+			Build: func(ctn di.Container) (*Devices, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var window time.Duration
+				if window = cfg.GetDuration(configKeyNewDeviceWindow); window == 0 {
+					return nil, config.NewErrMissingKey(configKeyNewDeviceWindow)
+				}
+
+				var events *repository.AuditEvents
+				if err := ctn.Fill(repository.DefAuditEventsName, &events); err != nil {
+					return nil, err
+				}
+
+				return NewDevices(window, events), nil
+			},
+		},
+	)
+}
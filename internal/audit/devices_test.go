@@ -0,0 +1,114 @@
+package audit_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/audit/mocks"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+func TestDevices_IsKnown(t *testing.T) {
+	longUserAgent := "Mozilla/5.0 " + strings.Repeat("a", 600)
+
+	sessions := []domain.AuditEvent{
+		{IP: "203.0.113.7", UserAgent: "Mozilla/5.0"},
+		{IP: "2001:db8:1:2::7", UserAgent: "curl/8.0"},
+		{IP: "192.0.2.7", UserAgent: longUserAgent[:512]},
+	}
+
+	testCases := []struct {
+		name       string
+		client     *audit.Client
+		onClients  func() ([]domain.AuditEvent, error)
+		expIsKnown bool
+		expErr     error
+	}{
+		{
+			name:       "same IP and user agent",
+			client:     &audit.Client{IP: "203.0.113.7", UserAgent: "Mozilla/5.0"},
+			onClients:  func() ([]domain.AuditEvent, error) { return sessions, nil },
+			expIsKnown: true,
+		},
+		{
+			name:       "user agent that was truncated when stored",
+			client:     &audit.Client{IP: "192.0.2.7", UserAgent: longUserAgent},
+			onClients:  func() ([]domain.AuditEvent, error) { return sessions, nil },
+			expIsKnown: true,
+		},
+		{
+			name:       "IPv4 in the same network",
+			client:     &audit.Client{IP: "203.0.113.200", UserAgent: "Mozilla/5.0"},
+			onClients:  func() ([]domain.AuditEvent, error) { return sessions, nil },
+			expIsKnown: true,
+		},
+		{
+			name:       "IPv4-mapped IPv6 in the same network",
+			client:     &audit.Client{IP: "::ffff:203.0.113.8", UserAgent: "Mozilla/5.0"},
+			onClients:  func() ([]domain.AuditEvent, error) { return sessions, nil },
+			expIsKnown: true,
+		},
+		{
+			name:       "IPv6 in the same network",
+			client:     &audit.Client{IP: "2001:db8:1:2:ffff::1", UserAgent: "curl/8.0"},
+			onClients:  func() ([]domain.AuditEvent, error) { return sessions, nil },
+			expIsKnown: true,
+		},
+		{
+			name:      "IPv4 in another network",
+			client:    &audit.Client{IP: "198.51.100.7", UserAgent: "Mozilla/5.0"},
+			onClients: func() ([]domain.AuditEvent, error) { return sessions, nil },
+		},
+		{
+			name:      "IPv6 in another network",
+			client:    &audit.Client{IP: "2001:db8:1:3::7", UserAgent: "curl/8.0"},
+			onClients: func() ([]domain.AuditEvent, error) { return sessions, nil },
+		},
+		{
+			name:      "another user agent",
+			client:    &audit.Client{IP: "203.0.113.7", UserAgent: "curl/8.0"},
+			onClients: func() ([]domain.AuditEvent, error) { return sessions, nil },
+		},
+		{
+			name:       "no recent sessions",
+			client:     &audit.Client{IP: "198.51.100.7", UserAgent: "Mozilla/5.0"},
+			onClients:  func() ([]domain.AuditEvent, error) { return nil, nil },
+			expIsKnown: true,
+		},
+		{
+			name:       "no client in the context",
+			expIsKnown: true,
+		},
+		{
+			name:      "failed to get recent clients",
+			client:    &audit.Client{IP: "203.0.113.7", UserAgent: "Mozilla/5.0"},
+			onClients: func() ([]domain.AuditEvent, error) { return nil, assert.AnError },
+			expErr:    assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.client != nil {
+				ctx = audit.ContextWithClient(ctx, *testCase.client)
+			}
+
+			history := mocks.NewClientHistory(t)
+			if testCase.onClients != nil {
+				history.On("ClientsByUser", ctx, "user_id", mock.MatchedBy(func(since time.Time) bool {
+					return time.Since(since).Round(time.Hour) == 30*24*time.Hour
+				})).Return(testCase.onClients())
+			}
+
+			isKnown, err := audit.NewDevices(30*24*time.Hour, history).IsKnown(ctx, "user_id")
+			assert.ErrorIs(t, err, testCase.expErr)
+			assert.Equal(t, testCase.expIsKnown, isKnown)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ClientHistory is an autogenerated mock type for the ClientHistory type
+type ClientHistory struct {
+	mock.Mock
+}
+
+// ClientsByUser provides a mock function with given fields: ctx, userID, since
+func (_m *ClientHistory) ClientsByUser(ctx context.Context, userID string, since time.Time) ([]domain.AuditEvent, error) {
+	ret := _m.Called(ctx, userID, since)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ClientsByUser")
+	}
+
+	var r0 []domain.AuditEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) ([]domain.AuditEvent, error)); ok {
+		return rf(ctx, userID, since)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) []domain.AuditEvent); ok {
+		r0 = rf(ctx, userID, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuditEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, userID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewClientHistory creates a new instance of ClientHistory. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewClientHistory(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ClientHistory {
+	mock := &ClientHistory{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
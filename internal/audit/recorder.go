@@ -0,0 +1,84 @@
+package audit
+
+//go:generate mockery --name EventSaver --output ./mocks --outpkg mocks --filename event_saver.go --structname EventSaver
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// maxUserAgentLength limits how much of the user agent is stored, as clients can send anything in it.
+const maxUserAgentLength = 512
+
+// NewRecorder creates a new *Recorder instance.
+func NewRecorder(log *logger.Logger, events EventSaver) *Recorder {
+	return &Recorder{
+		log:    log,
+		events: events,
+	}
+}
+
+type (
+	// Recorder records audit events along with the client the request came from.
+	Recorder struct {
+		log    *logger.Logger
+		events EventSaver
+	}
+
+	// EventSaver describes EventSaver dependency.
+	EventSaver interface {
+		Save(ctx context.Context, event domain.AuditEvent) error
+	}
+)
+
+// Save records the event with the client stored in ctx and returns an error if it could not be saved.
+// It is meant for actions that must not happen without a record, e.g. impersonation.
+func (r *Recorder) Save(ctx context.Context, event domain.AuditEvent) error {
+	client := ClientFromContext(ctx)
+
+	if event.IP == "" {
+		event.IP = client.IP
+	}
+
+	if event.UserAgent == "" {
+		event.UserAgent = client.UserAgent
+	}
+
+	event.UserAgent = sanitizeUserAgent(event.UserAgent)
+
+	if event.Outcome == "" {
+		event.Outcome = domain.AuditOutcomeSuccess
+	}
+
+	return r.events.Save(ctx, event)
+}
+
+// Record is like Save, but only logs failures, so that an unavailable audit trail does not stop users
+// from logging in.
+func (r *Recorder) Record(ctx context.Context, event domain.AuditEvent) {
+	if err := r.Save(ctx, event); err != nil {
+		r.log.Error("failed to record audit event", logger.String("type", event.Type), logger.Error(err))
+	}
+}
+
+// sanitizeUserAgent makes the user agent storable in a text column: invalid UTF-8 is replaced, NUL bytes are
+// removed and the result is truncated to maxUserAgentLength bytes without splitting a character. Otherwise
+// a crafted header would make saving the event fail and hide it from the audit trail.
+func sanitizeUserAgent(userAgent string) string {
+	userAgent = strings.ReplaceAll(strings.ToValidUTF8(userAgent, string(utf8.RuneError)), "\x00", "")
+	if len(userAgent) <= maxUserAgentLength {
+		return userAgent
+	}
+
+	end := maxUserAgentLength
+	for end > 0 && !utf8.RuneStart(userAgent[end]) {
+		end--
+	}
+
+	return userAgent[:end]
+}
@@ -0,0 +1,32 @@
+package audit
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+// DefRecorderName is the name of the *Recorder definition.
+const DefRecorderName = "audit.recorder"
+
+func init() {
+	di.Add(
+		di.Def[*Recorder]{
+			Name: DefRecorderName,
+			Build: func(ctn di.Container) (*Recorder, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var events *repository.AuditEvents
+				if err := ctn.Fill(repository.DefAuditEventsName, &events); err != nil {
+					return nil, err
+				}
+
+				return NewRecorder(log, events), nil
+			},
+		},
+	)
+}
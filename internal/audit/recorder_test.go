@@ -0,0 +1,144 @@
+package audit_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/audit/mocks"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+func TestRecorder_Save(t *testing.T) {
+	client := audit.Client{IP: "203.0.113.7", UserAgent: "curl/8.0"}
+
+	testCases := []struct {
+		name     string
+		client   *audit.Client
+		event    domain.AuditEvent
+		expEvent domain.AuditEvent
+		onSave   func() error
+		expErr   error
+	}{
+		{
+			name:   "client is taken from the context",
+			client: &client,
+			event:  domain.AuditEvent{Type: domain.AuditEventLogin, UserID: "user_id"},
+			expEvent: domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserID:    "user_id",
+				IP:        "203.0.113.7",
+				UserAgent: "curl/8.0",
+				Outcome:   domain.AuditOutcomeSuccess,
+			},
+			onSave: func() error { return nil },
+		},
+		{
+			name:   "outcome is kept",
+			client: &client,
+			event:  domain.AuditEvent{Type: domain.AuditEventLogin, Outcome: domain.AuditOutcomeFailure, Reason: "invalid_credentials"},
+			expEvent: domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				IP:        "203.0.113.7",
+				UserAgent: "curl/8.0",
+				Outcome:   domain.AuditOutcomeFailure,
+				Reason:    "invalid_credentials",
+			},
+			onSave: func() error { return nil },
+		},
+		{
+			name:     "no client in the context",
+			event:    domain.AuditEvent{Type: domain.AuditEventLogin},
+			expEvent: domain.AuditEvent{Type: domain.AuditEventLogin, Outcome: domain.AuditOutcomeSuccess},
+			onSave:   func() error { return nil },
+		},
+		{
+			name:   "long user agent is truncated",
+			client: &audit.Client{UserAgent: strings.Repeat("a", 1000)},
+			event:  domain.AuditEvent{Type: domain.AuditEventLogin},
+			expEvent: domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserAgent: strings.Repeat("a", 512),
+				Outcome:   domain.AuditOutcomeSuccess,
+			},
+			onSave: func() error { return nil },
+		},
+		{
+			name:   "long user agent is truncated on a character boundary",
+			client: &audit.Client{UserAgent: strings.Repeat("a", 511) + strings.Repeat("é", 10)},
+			event:  domain.AuditEvent{Type: domain.AuditEventLogin},
+			expEvent: domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserAgent: strings.Repeat("a", 511),
+				Outcome:   domain.AuditOutcomeSuccess,
+			},
+			onSave: func() error { return nil },
+		},
+		{
+			name:   "invalid utf-8 in user agent is replaced",
+			client: &audit.Client{UserAgent: "curl/\xff\xfe8.0"},
+			event:  domain.AuditEvent{Type: domain.AuditEventLogin},
+			expEvent: domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserAgent: "curl/\uFFFD8.0",
+				Outcome:   domain.AuditOutcomeSuccess,
+			},
+			onSave: func() error { return nil },
+		},
+		{
+			name:   "nul bytes in user agent are removed",
+			client: &audit.Client{UserAgent: "curl/\x008.0\x00"},
+			event:  domain.AuditEvent{Type: domain.AuditEventLogin},
+			expEvent: domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserAgent: "curl/8.0",
+				Outcome:   domain.AuditOutcomeSuccess,
+			},
+			onSave: func() error { return nil },
+		},
+		{
+			name:  "user agent of the event is sanitized",
+			event: domain.AuditEvent{Type: domain.AuditEventLogin, UserAgent: "Mozilla/5.0\x00\xc3"},
+			expEvent: domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserAgent: "Mozilla/5.0\uFFFD",
+				Outcome:   domain.AuditOutcomeSuccess,
+			},
+			onSave: func() error { return nil },
+		},
+		{
+			name:     "failed to save event",
+			event:    domain.AuditEvent{Type: domain.AuditEventLogin},
+			expEvent: domain.AuditEvent{Type: domain.AuditEventLogin, Outcome: domain.AuditOutcomeSuccess},
+			onSave:   func() error { return assert.AnError },
+			expErr:   assert.AnError,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.client != nil {
+				ctx = audit.ContextWithClient(ctx, *testCase.client)
+			}
+
+			events := mocks.NewEventSaver(t)
+			events.On("Save", ctx, testCase.expEvent).Return(testCase.onSave())
+
+			err := audit.NewRecorder(zap.NewNop(), events).Save(ctx, testCase.event)
+			assert.ErrorIs(t, err, testCase.expErr)
+		})
+	}
+}
+
+func TestRecorder_Record(t *testing.T) {
+	events := mocks.NewEventSaver(t)
+	events.On("Save", t.Context(), domain.AuditEvent{Type: domain.AuditEventLogin, Outcome: domain.AuditOutcomeSuccess}).
+		Return(assert.AnError)
+
+	// the failure is only logged
+	audit.NewRecorder(zap.NewNop(), events).Record(t.Context(), domain.AuditEvent{Type: domain.AuditEventLogin})
+}
@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+// NewSigner creates a new *Signer instance.
+func NewSigner(key ed25519.PrivateKey) *Signer {
+	return &Signer{
+		key: key,
+	}
+}
+
+// Signer signs exported audit trails, so that they can be verified offline with its public key.
+type Signer struct {
+	key ed25519.PrivateKey
+}
+
+// Sign returns the Ed25519 signature of the message.
+func (s *Signer) Sign(message []byte) []byte {
+	return ed25519.Sign(s.key, message)
+}
+
+// PublicKey returns the public key that verifies the signatures.
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+// PublicKeyPEM returns the public key as a PEM-encoded PKIX block, the format ParsePublicKey accepts.
+func (s *Signer) PublicKeyPEM() ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(s.PublicKey())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal public key: %w", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// ParsePrivateKey parses a PEM-encoded PKCS #8 Ed25519 private key.
+func ParsePrivateKey(data string) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported private key type %T, only Ed25519 is supported", key)
+	}
+
+	return privateKey, nil
+}
+
+// ParsePublicKey parses a PEM-encoded PKIX Ed25519 public key.
+func ParsePublicKey(data string) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("unsupported public key type %T, only Ed25519 is supported", key)
+	}
+
+	return publicKey, nil
+}
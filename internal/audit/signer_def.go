@@ -0,0 +1,41 @@
+package audit
+
+import (
+	"fmt"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+)
+
+const (
+	// DefSignerName is the name of the *Signer definition.
+	DefSignerName = "audit.signer"
+
+	configKeySigningKey = "auth.audit.signingKey"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Signer]{
+			Name: DefSignerName,
+			Build: func(ctn di.Container) (*Signer, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var data string
+				if data = cfg.GetString(configKeySigningKey); data == "" {
+					return nil, config.NewErrMissingKey(configKeySigningKey)
+				}
+
+				key, err := ParsePrivateKey(data)
+				if err != nil {
+					return nil, fmt.Errorf("invalid %s: %w", configKeySigningKey, err)
+				}
+
+				return NewSigner(key), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,24 @@
+package auth
+
+// NewAdmins creates a new *Admins instance from the IDs of users with administrative privileges.
+func NewAdmins(userIDs []string) *Admins {
+	ids := make(map[string]struct{}, len(userIDs))
+	for _, userID := range userIDs {
+		ids[userID] = struct{}{}
+	}
+
+	return &Admins{
+		ids: ids,
+	}
+}
+
+// Admins is a registry of users with administrative privileges.
+type Admins struct {
+	ids map[string]struct{}
+}
+
+// IsAdmin reports whether the user has administrative privileges.
+func (a *Admins) IsAdmin(userID string) bool {
+	_, ok := a.ids[userID]
+	return ok
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+)
+
+const (
+	// DefAdminsName is the name of the *Admins definition.
+	DefAdminsName = "auth.admins"
+
+	configKeyAdmins = "auth.admins"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Admins]{
+			Name: DefAdminsName,
+			Build: func(ctn di.Container) (*Admins, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				return NewAdmins(cfg.GetStringSlice(configKeyAdmins)), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,17 @@
+package auth_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/auth"
+)
+
+func TestAdmins_IsAdmin(t *testing.T) {
+	admins := auth.NewAdmins([]string{"admin_id"})
+
+	assert.True(t, admins.IsAdmin("admin_id"))
+	assert.False(t, admins.IsAdmin("user_id"))
+	assert.False(t, admins.IsAdmin(""))
+}
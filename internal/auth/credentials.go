@@ -0,0 +1,238 @@
+package auth
+
+//go:generate mockery --name UserByEmailProvider --output ./mocks --outpkg mocks --filename user_by_email_provider.go --structname UserByEmailProvider
+//go:generate mockery --name PasswordHasher --output ./mocks --outpkg mocks --filename password_hasher.go --structname PasswordHasher
+//go:generate mockery --name PasswordUpdater --output ./mocks --outpkg mocks --filename password_updater.go --structname PasswordUpdater
+//go:generate mockery --name AccountLockout --output ./mocks --outpkg mocks --filename account_lockout.go --structname AccountLockout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+var (
+	// ErrInvalidCredentials is returned when the email is unknown or the password does not match.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+
+	// ErrEmailNotVerified is returned for valid credentials of a user who has not verified the email address yet,
+	// when verification is required.
+	ErrEmailNotVerified = errors.New("email is not verified")
+)
+
+// dummyPassword is hashed once to have a hash to check passwords of unknown users against.
+const dummyPassword = "dummy password of an unknown user"
+
+// NewCredentials creates a new *Credentials instance. If requireVerifiedEmail is set, users
+// with unverified email addresses cannot log in.
+func NewCredentials(
+	log *logger.Logger,
+	userProvider UserByEmailProvider,
+	hasher PasswordHasher,
+	passwordUpdater PasswordUpdater,
+	lockout AccountLockout,
+	requireVerifiedEmail bool,
+) *Credentials {
+	return &Credentials{
+		log:                  log,
+		userProvider:         userProvider,
+		hasher:               hasher,
+		passwordUpdater:      passwordUpdater,
+		lockout:              lockout,
+		requireVerifiedEmail: requireVerifiedEmail,
+	}
+}
+
+type (
+	// Credentials verifies email and password pairs against stored users.
+	Credentials struct {
+		log                  *logger.Logger
+		userProvider         UserByEmailProvider
+		hasher               PasswordHasher
+		passwordUpdater      PasswordUpdater
+		lockout              AccountLockout
+		requireVerifiedEmail bool
+
+		dummyHashMu sync.Mutex
+		dummyHash   string
+	}
+
+	// UserByEmailProvider describes UserByEmailProvider dependency.
+	UserByEmailProvider interface {
+		GetByEmail(ctx context.Context, email string) (domain.User, error)
+	}
+
+	// PasswordHasher describes PasswordHasher dependency.
+	PasswordHasher interface {
+		Hash(ctx context.Context, password string) (string, error)
+		Verify(ctx context.Context, password string, encoded string) (bool, error)
+		NeedsRehash(encoded string) bool
+	}
+
+	// PasswordUpdater describes PasswordUpdater dependency.
+	PasswordUpdater interface {
+		UpdatePassword(ctx context.Context, userID string, hashedPassword string) error
+	}
+
+	// UserError wraps the error of a login to a registered account, so that the failure can be attributed
+	// to the user without telling the client. It unwraps to the original error.
+	UserError struct {
+		UserID string
+		Err    error
+	}
+
+	// AccountLockout describes AccountLockout dependency.
+	AccountLockout interface {
+		Check(ctx context.Context, userID string) error
+		Fail(ctx context.Context, user domain.User) (time.Time, error)
+		Reset(ctx context.Context, userID string) error
+	}
+)
+
+// Verify returns the user identified by email if the password matches.
+// Returns ErrInvalidCredentials if the user is not found or the password is wrong, *AccountLockedError
+// if the account is locked after too many wrong passwords, and ErrEmailNotVerified if the password matches
+// but the email address must be verified first. Errors for registered users are wrapped in *UserError.
+func (c *Credentials) Verify(ctx context.Context, email string, password string) (domain.User, error) {
+	var (
+		err  error
+		user domain.User
+	)
+	if user, err = c.userProvider.GetByEmail(ctx, email); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			c.log.Warn("invalid email")
+
+			// errors are returned like for a known email, e.g. password.ErrBusy, so that they reveal nothing either
+			if err = c.verifyDummy(ctx, password); err != nil {
+				return nil, err
+			}
+
+			return nil, ErrInvalidCredentials
+		}
+
+		return nil, fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	// a locked account does not even check the password, so that guessing it gets nowhere, but a password is
+	// still hashed, so that the response time does not tell a locked account from a wrong password
+	if err = c.lockout.Check(ctx, user.ID()); err != nil {
+		if errors.Is(err, ErrAccountLocked) {
+			c.log.Warn("account is locked")
+
+			if dummyErr := c.verifyDummy(ctx, password); dummyErr != nil {
+				return nil, dummyErr
+			}
+
+			return nil, &UserError{UserID: user.ID(), Err: err}
+		}
+
+		return nil, fmt.Errorf("failed to check account lockout: %w", err)
+	}
+
+	var ok bool
+	if ok, err = c.hasher.Verify(ctx, password, user.HashedPassword()); err != nil {
+		return nil, fmt.Errorf("failed to compare password: %w", err)
+	}
+
+	if !ok {
+		c.log.Warn("invalid password")
+		return nil, &UserError{UserID: user.ID(), Err: c.fail(ctx, user)}
+	}
+
+	if err = c.lockout.Reset(ctx, user.ID()); err != nil {
+		c.log.Error("failed to reset failed logins", logger.Error(err))
+	}
+
+	if c.hasher.NeedsRehash(user.HashedPassword()) {
+		c.rehash(ctx, user.ID(), password)
+	}
+
+	// checked only after the password, so that the error does not reveal anything to someone without it
+	if c.requireVerifiedEmail && !user.EmailVerified() {
+		c.log.Warn("email is not verified")
+		return nil, &UserError{UserID: user.ID(), Err: ErrEmailNotVerified}
+	}
+
+	return user, nil
+}
+
+// Error returns the message of the wrapped error.
+func (e *UserError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *UserError) Unwrap() error {
+	return e.Err
+}
+
+// fail records the failed login and returns the error for it: *AccountLockedError if it locked the account
+// and ErrInvalidCredentials otherwise. Failures to record it are only logged, so that they do not reveal
+// whether the email is registered.
+func (c *Credentials) fail(ctx context.Context, user domain.User) error {
+	until, err := c.lockout.Fail(ctx, user)
+	if err != nil {
+		c.log.Error("failed to record failed login", logger.Error(err))
+	}
+
+	if !until.IsZero() {
+		c.log.Warn("account is locked after too many failed logins")
+		return &AccountLockedError{Until: until}
+	}
+
+	return ErrInvalidCredentials
+}
+
+// verifyDummy checks the password against a hash that belongs to no user, so that logging in with an unknown email
+// or to a locked account takes as long as with a wrong password and the response time does not reveal which emails
+// are registered.
+// The hash is made with the current algorithm and parameters on first use.
+func (c *Credentials) verifyDummy(ctx context.Context, password string) error {
+	hash, err := c.dummyPasswordHash(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err = c.hasher.Verify(ctx, password, hash); err != nil {
+		return fmt.Errorf("failed to compare dummy password: %w", err)
+	}
+
+	return nil
+}
+
+// dummyPasswordHash returns the hash verifyDummy checks passwords against, making it if there is none yet.
+func (c *Credentials) dummyPasswordHash(ctx context.Context) (string, error) {
+	c.dummyHashMu.Lock()
+	defer c.dummyHashMu.Unlock()
+
+	if c.dummyHash == "" {
+		hash, err := c.hasher.Hash(ctx, dummyPassword)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash dummy password: %w", err)
+		}
+
+		c.dummyHash = hash
+	}
+
+	return c.dummyHash, nil
+}
+
+// rehash replaces the stored hash of the password with one of the current algorithm and parameters.
+// Failures are only logged, so that the user can still log in with the old hash.
+func (c *Credentials) rehash(ctx context.Context, userID string, password string) {
+	hashedPassword, err := c.hasher.Hash(ctx, password)
+	if err != nil {
+		c.log.Error("failed to rehash password", logger.Error(err))
+		return
+	}
+
+	if err = c.passwordUpdater.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		c.log.Error("failed to update rehashed password", logger.Error(err))
+	}
+}
@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+const (
+	// DefCredentialsName is the name of the *Credentials definition.
+	DefCredentialsName = "auth.credentials"
+
+	configKeyRequireVerifiedEmail = "auth.emailVerification.required"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Credentials]{
+			Name: DefCredentialsName,
+			Build: func(ctn di.Container) (*Credentials, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				var hasher *password.Pool
+				if err := ctn.Fill(password.DefPoolName, &hasher); err != nil {
+					return nil, err
+				}
+
+				var lockout *Lockout
+				if err := ctn.Fill(DefLockoutName, &lockout); err != nil {
+					return nil, err
+				}
+
+				return NewCredentials(log, usersRep, hasher, usersRep, lockout, cfg.GetBool(configKeyRequireVerifiedEmail)), nil
+			},
+		},
+	)
+}
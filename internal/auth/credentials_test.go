@@ -0,0 +1,337 @@
+package auth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/riabininkf/http-auth-example/internal/auth"
+	"github.com/riabininkf/http-auth-example/internal/auth/mocks"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestCredentials_Verify(t *testing.T) {
+	generatePasswordHash := func(t *testing.T, password string) string {
+		t.Helper()
+		var (
+			err            error
+			bcryptPassword []byte
+		)
+		if bcryptPassword, err = bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost); err != nil {
+			t.Fatal(err)
+		}
+
+		return string(bcryptPassword)
+	}
+
+	hasher := password.NewPool(password.NewHasher(password.NewBcrypt(bcrypt.DefaultCost)), 1, 0)
+
+	// allowAll returns a lockout that never locks, for the cases about other things
+	allowAll := func(t *testing.T) *mocks.AccountLockout {
+		lockout := mocks.NewAccountLockout(t)
+		lockout.On("Check", t.Context(), mock.Anything).Return(nil).Maybe()
+		lockout.On("Fail", t.Context(), mock.Anything).Return(time.Time{}, nil).Maybe()
+		lockout.On("Reset", t.Context(), mock.Anything).Return(nil).Maybe()
+		return lockout
+	}
+
+	t.Run("user not found", func(t *testing.T) {
+		email, plainPassword := gofakeit.Email(), gofakeit.Name()
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(nil, domain.ErrUserNotFound)
+
+		// the password is still checked, against a hash made once, so that unknown emails take as long
+		passwordHasher := mocks.NewPasswordHasher(t)
+		passwordHasher.On("Hash", t.Context(), mock.AnythingOfType("string")).Return("dummy_hash", nil).Once()
+		passwordHasher.On("Verify", t.Context(), plainPassword, "dummy_hash").Return(false, nil).Twice()
+
+		credentials := auth.NewCredentials(zap.NewNop(), userProvider, passwordHasher, mocks.NewPasswordUpdater(t), allowAll(t), false)
+		for range 2 {
+			user, err := credentials.Verify(t.Context(), email, plainPassword)
+			assert.Nil(t, user)
+			assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+		}
+	})
+
+	t.Run("user not found and failed to hash dummy password", func(t *testing.T) {
+		email := gofakeit.Email()
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(nil, domain.ErrUserNotFound)
+
+		passwordHasher := mocks.NewPasswordHasher(t)
+		passwordHasher.On("Hash", t.Context(), mock.AnythingOfType("string")).Return("", password.ErrBusy).Once()
+		passwordHasher.On("Hash", t.Context(), mock.AnythingOfType("string")).Return("dummy_hash", nil).Once()
+		passwordHasher.On("Verify", t.Context(), mock.AnythingOfType("string"), "dummy_hash").Return(false, nil).Once()
+
+		credentials := auth.NewCredentials(zap.NewNop(), userProvider, passwordHasher, mocks.NewPasswordUpdater(t), allowAll(t), false)
+
+		user, err := credentials.Verify(t.Context(), email, gofakeit.Name())
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, password.ErrBusy)
+
+		// the hash is made again next time
+		user, err = credentials.Verify(t.Context(), email, gofakeit.Name())
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	})
+
+	t.Run("failed to get user by email", func(t *testing.T) {
+		email := gofakeit.Email()
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(nil, assert.AnError)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, mocks.NewPasswordUpdater(t), allowAll(t), false).Verify(t.Context(), email, gofakeit.Name())
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("invalid password", func(t *testing.T) {
+		email, userID := gofakeit.Email(), uuid.NewString()
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).
+			Return(domain.NewUser(userID, email, generatePasswordHash(t, gofakeit.Name())), nil)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, mocks.NewPasswordUpdater(t), allowAll(t), false).Verify(t.Context(), email, gofakeit.Name())
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+
+		var userErr *auth.UserError
+		if assert.ErrorAs(t, err, &userErr) {
+			assert.Equal(t, userID, userErr.UserID)
+		}
+	})
+
+	t.Run("failed to compare password", func(t *testing.T) {
+		email := gofakeit.Email()
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).
+			Return(domain.NewUser(uuid.NewString(), email, "malformed_hash"), nil)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, mocks.NewPasswordUpdater(t), allowAll(t), false).Verify(t.Context(), email, gofakeit.Name())
+		assert.Nil(t, user)
+		assert.Error(t, err)
+		assert.NotErrorIs(t, err, auth.ErrInvalidCredentials)
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		email, plainPassword := gofakeit.Email(), gofakeit.Name()
+		expUser := domain.NewUser(uuid.NewString(), email, generatePasswordHash(t, plainPassword))
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(expUser, nil)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, mocks.NewPasswordUpdater(t), allowAll(t), false).Verify(t.Context(), email, plainPassword)
+		assert.NoError(t, err)
+		assert.Equal(t, expUser, user)
+	})
+
+	t.Run("email is not verified", func(t *testing.T) {
+		email, plainPassword := gofakeit.Email(), gofakeit.Name()
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).
+			Return(domain.NewUser(uuid.NewString(), email, generatePasswordHash(t, plainPassword)), nil)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, mocks.NewPasswordUpdater(t), allowAll(t), true).Verify(t.Context(), email, plainPassword)
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, auth.ErrEmailNotVerified)
+	})
+
+	t.Run("invalid password of unverified user", func(t *testing.T) {
+		email := gofakeit.Email()
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).
+			Return(domain.NewUser(uuid.NewString(), email, generatePasswordHash(t, gofakeit.Name())), nil)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, mocks.NewPasswordUpdater(t), allowAll(t), true).Verify(t.Context(), email, gofakeit.Name())
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	})
+
+	t.Run("positive case with verified email", func(t *testing.T) {
+		email, plainPassword := gofakeit.Email(), gofakeit.Name()
+		expUser := domain.NewUser(uuid.NewString(), email, generatePasswordHash(t, plainPassword), domain.WithEmailVerified())
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(expUser, nil)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, mocks.NewPasswordUpdater(t), allowAll(t), true).Verify(t.Context(), email, plainPassword)
+		assert.NoError(t, err)
+		assert.Equal(t, expUser, user)
+	})
+	t.Run("outdated hash is rehashed", func(t *testing.T) {
+		email, plainPassword := gofakeit.Email(), gofakeit.Name()
+		expUser := domain.NewUser(uuid.NewString(), email, generatePasswordHash(t, plainPassword))
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(expUser, nil)
+
+		argon2id := password.NewArgon2id(64, 1, 1)
+
+		passwordUpdater := mocks.NewPasswordUpdater(t)
+		passwordUpdater.On("UpdatePassword", t.Context(), expUser.ID(), mock.AnythingOfType("string")).
+			Run(func(args mock.Arguments) {
+				ok, err := argon2id.Verify(plainPassword, args.String(2))
+				assert.NoError(t, err)
+				assert.True(t, ok)
+			}).
+			Return(nil)
+
+		hasher := password.NewPool(password.NewHasher(argon2id, password.NewBcrypt(bcrypt.DefaultCost)), 1, 0)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, passwordUpdater, allowAll(t), false).
+			Verify(t.Context(), email, plainPassword)
+		assert.NoError(t, err)
+		assert.Equal(t, expUser, user)
+	})
+
+	t.Run("failed to update rehashed password", func(t *testing.T) {
+		email, plainPassword := gofakeit.Email(), gofakeit.Name()
+		expUser := domain.NewUser(uuid.NewString(), email, generatePasswordHash(t, plainPassword))
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(expUser, nil)
+
+		passwordUpdater := mocks.NewPasswordUpdater(t)
+		passwordUpdater.On("UpdatePassword", t.Context(), expUser.ID(), mock.AnythingOfType("string")).Return(assert.AnError)
+
+		hasher := password.NewPool(password.NewHasher(password.NewArgon2id(64, 1, 1), password.NewBcrypt(bcrypt.DefaultCost)), 1, 0)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, passwordUpdater, allowAll(t), false).
+			Verify(t.Context(), email, plainPassword)
+		assert.NoError(t, err)
+		assert.Equal(t, expUser, user)
+	})
+
+	t.Run("account is locked", func(t *testing.T) {
+		email, plainPassword := gofakeit.Email(), gofakeit.Name()
+		expUser := domain.NewUser(uuid.NewString(), email, generatePasswordHash(t, plainPassword))
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(expUser, nil)
+
+		lockout := mocks.NewAccountLockout(t)
+		lockout.On("Check", t.Context(), expUser.ID()).Return(&auth.AccountLockedError{Until: time.Now().Add(time.Minute)})
+
+		// the password is checked against the dummy hash rather than the real one, which takes as long
+		passwordHasher := mocks.NewPasswordHasher(t)
+		passwordHasher.On("Hash", t.Context(), mock.AnythingOfType("string")).Return("dummy_hash", nil).Once()
+		passwordHasher.On("Verify", t.Context(), plainPassword, "dummy_hash").Return(false, nil).Once()
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, passwordHasher, mocks.NewPasswordUpdater(t), lockout, false).
+			Verify(t.Context(), email, plainPassword)
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, auth.ErrAccountLocked)
+
+		var userErr *auth.UserError
+		if assert.ErrorAs(t, err, &userErr) {
+			assert.Equal(t, expUser.ID(), userErr.UserID)
+		}
+	})
+
+	t.Run("password hashing is saturated for a locked account", func(t *testing.T) {
+		email, plainPassword := gofakeit.Email(), gofakeit.Name()
+		expUser := domain.NewUser(uuid.NewString(), email, generatePasswordHash(t, plainPassword))
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(expUser, nil)
+
+		lockout := mocks.NewAccountLockout(t)
+		lockout.On("Check", t.Context(), expUser.ID()).Return(&auth.AccountLockedError{Until: time.Now().Add(time.Minute)})
+
+		passwordHasher := mocks.NewPasswordHasher(t)
+		passwordHasher.On("Hash", t.Context(), mock.AnythingOfType("string")).Return("", password.ErrBusy).Once()
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, passwordHasher, mocks.NewPasswordUpdater(t), lockout, false).
+			Verify(t.Context(), email, plainPassword)
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, password.ErrBusy)
+	})
+
+	t.Run("failed to check account lockout", func(t *testing.T) {
+		email, plainPassword := gofakeit.Email(), gofakeit.Name()
+		expUser := domain.NewUser(uuid.NewString(), email, generatePasswordHash(t, plainPassword))
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(expUser, nil)
+
+		lockout := mocks.NewAccountLockout(t)
+		lockout.On("Check", t.Context(), expUser.ID()).Return(assert.AnError)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, mocks.NewPasswordUpdater(t), lockout, false).
+			Verify(t.Context(), email, plainPassword)
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("failed login locks account", func(t *testing.T) {
+		email := gofakeit.Email()
+		expUser := domain.NewUser(uuid.NewString(), email, generatePasswordHash(t, gofakeit.Name()))
+		until := time.Now().Add(time.Minute)
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(expUser, nil)
+
+		lockout := mocks.NewAccountLockout(t)
+		lockout.On("Check", t.Context(), expUser.ID()).Return(nil)
+		lockout.On("Fail", t.Context(), expUser).Return(until, nil)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, mocks.NewPasswordUpdater(t), lockout, false).
+			Verify(t.Context(), email, gofakeit.Name())
+		assert.Nil(t, user)
+
+		var lockedErr *auth.AccountLockedError
+		if !assert.ErrorAs(t, err, &lockedErr) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, until, lockedErr.Until)
+	})
+
+	t.Run("failed to record failed login", func(t *testing.T) {
+		email := gofakeit.Email()
+		expUser := domain.NewUser(uuid.NewString(), email, generatePasswordHash(t, gofakeit.Name()))
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(expUser, nil)
+
+		lockout := mocks.NewAccountLockout(t)
+		lockout.On("Check", t.Context(), expUser.ID()).Return(nil)
+		lockout.On("Fail", t.Context(), expUser).Return(time.Time{}, assert.AnError)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, mocks.NewPasswordUpdater(t), lockout, false).
+			Verify(t.Context(), email, gofakeit.Name())
+		assert.Nil(t, user)
+		assert.ErrorIs(t, err, auth.ErrInvalidCredentials)
+	})
+
+	t.Run("successful login resets failed logins", func(t *testing.T) {
+		email, plainPassword := gofakeit.Email(), gofakeit.Name()
+		expUser := domain.NewUser(uuid.NewString(), email, generatePasswordHash(t, plainPassword))
+
+		userProvider := mocks.NewUserByEmailProvider(t)
+		userProvider.On("GetByEmail", t.Context(), email).Return(expUser, nil)
+
+		lockout := mocks.NewAccountLockout(t)
+		lockout.On("Check", t.Context(), expUser.ID()).Return(nil)
+		lockout.On("Reset", t.Context(), expUser.ID()).Return(assert.AnError)
+
+		user, err := auth.NewCredentials(zap.NewNop(), userProvider, hasher, mocks.NewPasswordUpdater(t), lockout, false).
+			Verify(t.Context(), email, plainPassword)
+		assert.NoError(t, err, "failures to reset the counters are only logged")
+		assert.Equal(t, expUser, user)
+	})
+}
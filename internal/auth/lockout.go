@@ -0,0 +1,181 @@
+package auth
+
+//go:generate mockery --name LockoutCache --output ./mocks --outpkg mocks --filename lockout_cache.go --structname LockoutCache
+//go:generate mockery --name Mailer --output ./mocks --outpkg mocks --filename mailer.go --structname Mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+// ErrAccountLocked is returned for accounts locked after too many failed logins, whatever the password.
+var ErrAccountLocked = errors.New("account is locked")
+
+const (
+	lockoutFailuresKeyPrefix = "auth:lockout:failures:"
+	lockoutCountKeyPrefix    = "auth:lockout:count:"
+	lockoutLockedKeyPrefix   = "auth:lockout:locked:"
+)
+
+// NewLockout creates a new *Lockout instance. An account is locked after maxAttempts failed logins in a row,
+// for duration the first time and twice as long every next time, up to maxDuration. Failed logins and lockouts
+// are forgotten after window without any.
+func NewLockout(
+	maxAttempts int,
+	duration time.Duration,
+	maxDuration time.Duration,
+	window time.Duration,
+	cache LockoutCache,
+	mailer Mailer,
+) *Lockout {
+	return &Lockout{
+		maxAttempts: maxAttempts,
+		duration:    duration,
+		maxDuration: maxDuration,
+		window:      window,
+		cache:       cache,
+		mailer:      mailer,
+	}
+}
+
+type (
+	// Lockout locks accounts after repeated failed logins, so that passwords cannot be guessed online.
+	Lockout struct {
+		maxAttempts int
+		duration    time.Duration
+		maxDuration time.Duration
+		window      time.Duration
+		cache       LockoutCache
+		mailer      Mailer
+	}
+
+	// LockoutCache defines methods for counters and values with a TTL.
+	LockoutCache interface {
+		Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+		Set(ctx context.Context, key string, value any, ttl time.Duration) error
+		Get(ctx context.Context, key string) (string, error)
+		Del(ctx context.Context, keys ...string) error
+	}
+
+	// Mailer describes Mailer dependency.
+	Mailer interface {
+		Send(ctx context.Context, msg mail.Message) error
+	}
+
+	// AccountLockedError is returned for a locked account and tells when it can be used again.
+	AccountLockedError struct {
+		Until time.Time
+	}
+)
+
+// Error returns the time the account is locked until.
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("account is locked until %s", e.Until.Format(time.RFC3339))
+}
+
+// Unwrap returns ErrAccountLocked.
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
+// Check returns *AccountLockedError if the account of the user is locked.
+func (l *Lockout) Check(ctx context.Context, userID string) error {
+	value, err := l.cache.Get(ctx, lockoutLockedKeyPrefix+userID)
+	if err != nil {
+		if errors.Is(err, redis.ErrNotFound) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to get lockout: %w", err)
+	}
+
+	var until int64
+	if until, err = strconv.ParseInt(value, 10, 64); err != nil {
+		return fmt.Errorf("failed to parse lockout: %w", err)
+	}
+
+	return &AccountLockedError{Until: time.Unix(until, 0)}
+}
+
+// Fail records a failed login of the user. Returns the time the account is locked until if the failure locked it,
+// in which case the user is notified by email. The account stays locked even if the notification fails.
+func (l *Lockout) Fail(ctx context.Context, user domain.User) (time.Time, error) {
+	failures, err := l.cache.Incr(ctx, lockoutFailuresKeyPrefix+user.ID(), l.window)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to count failed login: %w", err)
+	}
+
+	if failures < int64(l.maxAttempts) {
+		return time.Time{}, nil
+	}
+
+	var lockouts int64
+	if lockouts, err = l.cache.Incr(ctx, lockoutCountKeyPrefix+user.ID(), l.window); err != nil {
+		return time.Time{}, fmt.Errorf("failed to count lockouts: %w", err)
+	}
+
+	duration := l.lockoutDuration(lockouts)
+	until := time.Now().Add(duration).Truncate(time.Second)
+
+	if err = l.cache.Set(ctx, lockoutLockedKeyPrefix+user.ID(), until.Unix(), duration); err != nil {
+		return time.Time{}, fmt.Errorf("failed to lock account: %w", err)
+	}
+
+	// the next lockout takes another maxAttempts failures
+	if err = l.cache.Del(ctx, lockoutFailuresKeyPrefix+user.ID()); err != nil {
+		return until, fmt.Errorf("failed to reset failed logins: %w", err)
+	}
+
+	if err = l.mailer.Send(ctx, mail.Message{
+		To:      user.Email(),
+		Subject: "Your account has been locked",
+		Body: fmt.Sprintf("Someone entered a wrong password for your account %d times in a row, "+
+			"so logging in is blocked until %s.\n\n"+
+			"If it was not you, consider changing your password once the account is unlocked.\n",
+			failures, until.UTC().Format(time.RFC1123)),
+	}); err != nil {
+		return until, fmt.Errorf("failed to send lockout notification: %w", err)
+	}
+
+	return until, nil
+}
+
+// Reset forgets the failed logins of the user, e.g. after a successful one.
+func (l *Lockout) Reset(ctx context.Context, userID string) error {
+	if err := l.cache.Del(ctx, lockoutFailuresKeyPrefix+userID, lockoutCountKeyPrefix+userID); err != nil {
+		return fmt.Errorf("failed to reset failed logins: %w", err)
+	}
+
+	return nil
+}
+
+// Unlock lifts the lockout of the user's account and forgets the failed logins.
+func (l *Lockout) Unlock(ctx context.Context, userID string) error {
+	if err := l.cache.Del(
+		ctx,
+		lockoutLockedKeyPrefix+userID,
+		lockoutFailuresKeyPrefix+userID,
+		lockoutCountKeyPrefix+userID,
+	); err != nil {
+		return fmt.Errorf("failed to unlock account: %w", err)
+	}
+
+	return nil
+}
+
+// lockoutDuration returns the duration of the given lockout in a row, doubling every time up to maxDuration.
+func (l *Lockout) lockoutDuration(lockouts int64) time.Duration {
+	duration := l.duration
+	for i := int64(1); i < lockouts && duration < l.maxDuration; i++ {
+		duration *= 2
+	}
+
+	return min(duration, l.maxDuration)
+}
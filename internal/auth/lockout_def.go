@@ -0,0 +1,67 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/mail"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+const (
+	// DefLockoutName is the name of the *Lockout definition.
+	DefLockoutName = "auth.lockout"
+
+	configKeyLockoutMaxAttempts = "auth.lockout.maxAttempts"
+	configKeyLockoutDuration    = "auth.lockout.duration"
+	configKeyLockoutMaxDuration = "auth.lockout.maxDuration"
+	configKeyLockoutWindow      = "auth.lockout.window"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Lockout]{
+			Name: DefLockoutName,
+			Build: func(ctn di.Container) (*Lockout, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var maxAttempts int
+				if maxAttempts = cfg.GetInt(configKeyLockoutMaxAttempts); maxAttempts == 0 {
+					return nil, config.NewErrMissingKey(configKeyLockoutMaxAttempts)
+				}
+
+				var duration time.Duration
+				if duration = cfg.GetDuration(configKeyLockoutDuration); duration == 0 {
+					return nil, config.NewErrMissingKey(configKeyLockoutDuration)
+				}
+
+				var maxDuration time.Duration
+				if maxDuration = cfg.GetDuration(configKeyLockoutMaxDuration); maxDuration == 0 {
+					return nil, config.NewErrMissingKey(configKeyLockoutMaxDuration)
+				}
+
+				var window time.Duration
+				if window = cfg.GetDuration(configKeyLockoutWindow); window == 0 {
+					return nil, config.NewErrMissingKey(configKeyLockoutWindow)
+				}
+
+				var cache *redis.Client
+				if err := ctn.Fill(redis.DefClientName, &cache); err != nil {
+					return nil, err
+				}
+
+				var mailer mail.Mailer
+				if err := ctn.Fill(mail.DefMailerName, &mailer); err != nil {
+					return nil, err
+				}
+
+				return NewLockout(maxAttempts, duration, maxDuration, window, cache, mailer), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,175 @@
+package auth_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/auth"
+	"github.com/riabininkf/http-auth-example/internal/auth/mocks"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mail"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+const (
+	lockoutMaxAttempts = 5
+	lockoutDuration    = time.Minute
+	lockoutMaxDuration = 10 * time.Minute
+	lockoutWindow      = 24 * time.Hour
+)
+
+func newLockout(cache auth.LockoutCache, mailer auth.Mailer) *auth.Lockout {
+	return auth.NewLockout(lockoutMaxAttempts, lockoutDuration, lockoutMaxDuration, lockoutWindow, cache, mailer)
+}
+
+func TestLockout_Check(t *testing.T) {
+	until := time.Now().Add(time.Minute).Truncate(time.Second)
+
+	testCases := map[string]struct {
+		onGet    func() (string, error)
+		expUntil time.Time
+		expErr   error
+	}{
+		"not locked": {
+			onGet: func() (string, error) { return "", redis.ErrNotFound },
+		},
+		"failed to get lockout": {
+			onGet:  func() (string, error) { return "", assert.AnError },
+			expErr: assert.AnError,
+		},
+		"locked": {
+			onGet:    func() (string, error) { return strconv.FormatInt(until.Unix(), 10), nil },
+			expUntil: until,
+			expErr:   auth.ErrAccountLocked,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewLockoutCache(t)
+			cache.On("Get", t.Context(), "auth:lockout:locked:user_id").Return(tc.onGet())
+
+			err := newLockout(cache, mocks.NewMailer(t)).Check(t.Context(), "user_id")
+			assert.ErrorIs(t, err, tc.expErr)
+
+			if tc.expUntil.IsZero() {
+				return
+			}
+
+			var lockedErr *auth.AccountLockedError
+			if !assert.ErrorAs(t, err, &lockedErr) {
+				t.FailNow()
+			}
+
+			assert.True(t, tc.expUntil.Equal(lockedErr.Until))
+		})
+	}
+}
+
+func TestLockout_Fail(t *testing.T) {
+	user := domain.NewUser("user_id", "user@example.com", "hashed_password")
+
+	t.Run("failed to count failed login", func(t *testing.T) {
+		cache := mocks.NewLockoutCache(t)
+		cache.On("Incr", t.Context(), "auth:lockout:failures:user_id", lockoutWindow).Return(int64(0), assert.AnError)
+
+		until, err := newLockout(cache, mocks.NewMailer(t)).Fail(t.Context(), user)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, until.IsZero())
+	})
+
+	t.Run("below threshold", func(t *testing.T) {
+		cache := mocks.NewLockoutCache(t)
+		cache.On("Incr", t.Context(), "auth:lockout:failures:user_id", lockoutWindow).
+			Return(int64(lockoutMaxAttempts-1), nil)
+
+		until, err := newLockout(cache, mocks.NewMailer(t)).Fail(t.Context(), user)
+		assert.NoError(t, err)
+		assert.True(t, until.IsZero())
+	})
+
+	testCases := map[string]struct {
+		lockouts    int64
+		onSend      error
+		expDuration time.Duration
+		expErr      error
+	}{
+		"first lockout": {
+			lockouts:    1,
+			expDuration: lockoutDuration,
+		},
+		"third lockout is four times longer": {
+			lockouts:    3,
+			expDuration: 4 * lockoutDuration,
+		},
+		"lockout is capped": {
+			lockouts:    100,
+			expDuration: lockoutMaxDuration,
+		},
+		"failed to send notification": {
+			lockouts:    1,
+			onSend:      assert.AnError,
+			expDuration: lockoutDuration,
+			expErr:      assert.AnError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewLockoutCache(t)
+			cache.On("Incr", t.Context(), "auth:lockout:failures:user_id", lockoutWindow).
+				Return(int64(lockoutMaxAttempts), nil)
+			cache.On("Incr", t.Context(), "auth:lockout:count:user_id", lockoutWindow).Return(tc.lockouts, nil)
+			cache.On("Set", t.Context(), "auth:lockout:locked:user_id", mock.AnythingOfType("int64"), tc.expDuration).
+				Return(nil)
+			cache.On("Del", t.Context(), "auth:lockout:failures:user_id").Return(nil)
+
+			var msg mail.Message
+
+			mailer := mocks.NewMailer(t)
+			mailer.On("Send", t.Context(), mock.AnythingOfType("mail.Message")).
+				Run(func(args mock.Arguments) { msg = args.Get(1).(mail.Message) }).
+				Return(tc.onSend)
+
+			until, err := newLockout(cache, mailer).Fail(t.Context(), user)
+			assert.ErrorIs(t, err, tc.expErr)
+			assert.WithinDuration(t, time.Now().Add(tc.expDuration), until, 2*time.Second)
+			assert.Equal(t, "user@example.com", msg.To)
+			assert.Equal(t, "Your account has been locked", msg.Subject)
+		})
+	}
+
+	t.Run("failed to lock account", func(t *testing.T) {
+		cache := mocks.NewLockoutCache(t)
+		cache.On("Incr", t.Context(), "auth:lockout:failures:user_id", lockoutWindow).
+			Return(int64(lockoutMaxAttempts), nil)
+		cache.On("Incr", t.Context(), "auth:lockout:count:user_id", lockoutWindow).Return(int64(1), nil)
+		cache.On("Set", t.Context(), "auth:lockout:locked:user_id", mock.Anything, lockoutDuration).
+			Return(assert.AnError)
+
+		until, err := newLockout(cache, mocks.NewMailer(t)).Fail(t.Context(), user)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.True(t, until.IsZero())
+	})
+}
+
+func TestLockout_Reset(t *testing.T) {
+	cache := mocks.NewLockoutCache(t)
+	cache.On("Del", t.Context(), "auth:lockout:failures:user_id", "auth:lockout:count:user_id").Return(assert.AnError)
+
+	err := newLockout(cache, mocks.NewMailer(t)).Reset(t.Context(), "user_id")
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestLockout_Unlock(t *testing.T) {
+	cache := mocks.NewLockoutCache(t)
+	cache.On("Del", t.Context(),
+		"auth:lockout:locked:user_id", "auth:lockout:failures:user_id", "auth:lockout:count:user_id",
+	).Return(nil)
+
+	assert.NoError(t, newLockout(cache, mocks.NewMailer(t)).Unlock(t.Context(), "user_id"))
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NewServiceAccounts creates a new *ServiceAccounts instance from a map of service account IDs to their public keys.
+func NewServiceAccounts(keys map[string]crypto.PublicKey) *ServiceAccounts {
+	normalized := make(map[string]crypto.PublicKey, len(keys))
+	for id, key := range keys {
+		normalized[strings.ToLower(id)] = key
+	}
+
+	return &ServiceAccounts{
+		keys: normalized,
+	}
+}
+
+// ServiceAccounts is a registry of service accounts and the public keys they sign assertions with.
+// Service account IDs are case-insensitive, the same way as configuration keys.
+type ServiceAccounts struct {
+	keys map[string]crypto.PublicKey
+}
+
+// PublicKey returns the public key registered for the service account.
+func (s *ServiceAccounts) PublicKey(serviceAccountID string) (crypto.PublicKey, bool) {
+	key, ok := s.keys[strings.ToLower(serviceAccountID)]
+	return key, ok
+}
+
+// ParsePublicKey parses a PEM-encoded PKIX public key (RSA, ECDSA or Ed25519).
+func ParsePublicKey(data string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	return key, nil
+}
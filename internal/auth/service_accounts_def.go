@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"crypto"
+	"fmt"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+)
+
+const (
+	// DefServiceAccountsName is the name of the *ServiceAccounts definition.
+	DefServiceAccountsName = "auth.service-accounts"
+
+	configKeyServiceAccountKeys = "auth.serviceAccounts.keys"
+)
+
+func init() {
+	di.Add(
+		di.Def[*ServiceAccounts]{
+			Name: DefServiceAccountsName,
+			Build: func(ctn di.Container) (*ServiceAccounts, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				keys := make(map[string]crypto.PublicKey)
+				for id, data := range cfg.GetStringMapString(configKeyServiceAccountKeys) {
+					key, err := ParsePublicKey(data)
+					if err != nil {
+						return nil, fmt.Errorf("invalid public key of service account %q: %w", id, err)
+					}
+
+					keys[id] = key
+				}
+
+				return NewServiceAccounts(keys), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,52 @@
+package auth_test
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/auth"
+)
+
+const testPublicKey = `-----BEGIN PUBLIC KEY-----
+MCowBQYDK2VwAyEANr3O/dBQVr6Ut2N0QRmGKy2DujSVxb6MIVn9YlXI4Rk=
+-----END PUBLIC KEY-----`
+
+func TestParsePublicKey(t *testing.T) {
+	t.Run("not a PEM block", func(t *testing.T) {
+		key, err := auth.ParsePublicKey("public key")
+		assert.Nil(t, key)
+		assert.Error(t, err)
+	})
+
+	t.Run("not a public key", func(t *testing.T) {
+		key, err := auth.ParsePublicKey("-----BEGIN PUBLIC KEY-----\naGVsbG8=\n-----END PUBLIC KEY-----")
+		assert.Nil(t, key)
+		assert.Error(t, err)
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		key, err := auth.ParsePublicKey(testPublicKey)
+		assert.NoError(t, err)
+		assert.IsType(t, ed25519.PublicKey{}, key)
+	})
+}
+
+func TestServiceAccounts_PublicKey(t *testing.T) {
+	key, err := auth.ParsePublicKey(testPublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	serviceAccounts := auth.NewServiceAccounts(map[string]crypto.PublicKey{"Worker": key})
+
+	found, ok := serviceAccounts.PublicKey("worker")
+	assert.True(t, ok)
+	assert.Equal(t, key, found)
+
+	found, ok = serviceAccounts.PublicKey("another")
+	assert.False(t, ok)
+	assert.Nil(t, found)
+}
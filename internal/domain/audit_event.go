@@ -0,0 +1,135 @@
+package domain
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// Types of audit events.
+const (
+	// AuditEventRegistration is recorded when a user registers.
+	AuditEventRegistration = "registration"
+
+	// AuditEventImpersonation is recorded when a user obtains a token to act as another user.
+	AuditEventImpersonation = "impersonation"
+
+	// AuditEventRecoveryCodeUsed is recorded when a recovery code replaces the second factor on login.
+	AuditEventRecoveryCodeUsed = "recovery_code_used"
+
+	// AuditEventMFAEnabled is recorded when a user confirms their TOTP enrollment and turns on the second factor.
+	AuditEventMFAEnabled = "mfa_enabled"
+
+	// AuditEventPasskeyRegistration is recorded when a user registers a passkey.
+	AuditEventPasskeyRegistration = "passkey_registration"
+
+	// AuditEventRecoveryCodesRegenerated is recorded when a user replaces their recovery codes.
+	AuditEventRecoveryCodesRegenerated = "recovery_codes_regenerated"
+
+	// AuditEventAccountUnlocked is recorded when an admin unlocks an account locked after failed logins.
+	AuditEventAccountUnlocked = "account_unlocked"
+
+	// AuditEventLogin is recorded for every login attempt, whatever the method.
+	AuditEventLogin = "login"
+
+	// AuditEventTokenGrant is recorded when an OAuth grant other than the token exchange issues tokens,
+	// Details tell the grant type and the client.
+	AuditEventTokenGrant = "token_grant"
+
+	// AuditEventTokenRefresh is recorded when a refresh token is exchanged for new tokens.
+	AuditEventTokenRefresh = "token_refresh"
+
+	// AuditEventPasswordChange is recorded when a user changes their password.
+	AuditEventPasswordChange = "password_change"
+
+	// AuditEventPasswordReset is recorded when a password is reset with an emailed token.
+	AuditEventPasswordReset = "password_reset"
+
+	// AuditEventNewDevice is recorded when the password of a user is accepted from a device none of
+	// their recent sessions came from.
+	AuditEventNewDevice = "new_device"
+
+	// AuditEventEmailChange is recorded when a user asks to change their email address and when the new
+	// address is confirmed.
+	AuditEventEmailChange = "email_change"
+
+	// AuditEventEmailChangeUndone is recorded when the owner of the previous address undoes an email change.
+	AuditEventEmailChangeUndone = "email_change_undone"
+)
+
+// Outcomes of audit events.
+const (
+	// AuditOutcomeSuccess means the action was performed.
+	AuditOutcomeSuccess = "success"
+
+	// AuditOutcomeFailure means the action was refused, Reason tells why.
+	AuditOutcomeFailure = "failure"
+
+	// AuditOutcomeChallenged means the first factor of a login was accepted and a second one was asked for.
+	AuditOutcomeChallenged = "challenged"
+)
+
+// AuditEvent is a security-relevant action recorded in the audit trail.
+// UserID is the user the action was performed on, ActorID is set when it was performed by someone else.
+// IP and UserAgent describe the client the request came from. SessionID identifies the refresh token
+// the action issued or used, so that events of the same session can be told apart from others.
+// PrevHash and Hash link the event to the one saved before it, see ChainHash.
+type AuditEvent struct {
+	ID        int64
+	Type      string
+	UserID    string
+	ActorID   string
+	IP        string
+	UserAgent string
+	SessionID string
+	Outcome   string
+	Reason    string
+	Details   map[string]string
+	CreatedAt time.Time
+	PrevHash  string
+	Hash      string
+}
+
+// ChainHash returns the hex-encoded SHA-256 of prevHash followed by the fields of the event, including its ID,
+// so that editing, removing or reordering saved events breaks the chain of hashes.
+func (e AuditEvent) ChainHash(prevHash string) string {
+	details := e.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+
+	// the fields are marshaled in the order of declaration and map keys are sorted,
+	// so the same event always produces the same bytes
+	data, _ := json.Marshal(struct {
+		ID        int64             `json:"id"`
+		Type      string            `json:"type"`
+		UserID    string            `json:"user_id"`
+		ActorID   string            `json:"actor_id"`
+		IP        string            `json:"ip"`
+		UserAgent string            `json:"user_agent"`
+		SessionID string            `json:"session_id"`
+		Outcome   string            `json:"outcome"`
+		Reason    string            `json:"reason"`
+		Details   map[string]string `json:"details"`
+		CreatedAt string            `json:"created_at"`
+	}{
+		ID:        e.ID,
+		Type:      e.Type,
+		UserID:    e.UserID,
+		ActorID:   e.ActorID,
+		IP:        e.IP,
+		UserAgent: e.UserAgent,
+		SessionID: e.SessionID,
+		Outcome:   e.Outcome,
+		Reason:    e.Reason,
+		Details:   details,
+		CreatedAt: e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+
+	hash := sha256.New()
+	hash.Write([]byte(prevHash))
+	hash.Write(data)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrTOTPNotFound is returned when the user has not started TOTP enrollment.
+	ErrTOTPNotFound = errors.New("totp credential not found")
+
+	// ErrTOTPAlreadyConfirmed is returned when the user's TOTP credential is already confirmed and cannot be replaced.
+	ErrTOTPAlreadyConfirmed = errors.New("totp credential is already confirmed")
+)
+
+// TOTPCredential is the TOTP secret of a user. The secret is encrypted, ConfirmedAt is zero until the user
+// proves possession of the secret with a first code. LastUsedStep is the time step of the last accepted code.
+type TOTPCredential struct {
+	UserID          string
+	EncryptedSecret string
+	ConfirmedAt     time.Time
+	LastUsedStep    int64
+}
+
+// IsConfirmed reports whether the credential is confirmed, i.e. two-factor authentication is enabled.
+func (c TOTPCredential) IsConfirmed() bool {
+	return !c.ConfirmedAt.IsZero()
+}
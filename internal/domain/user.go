@@ -1,6 +1,10 @@
 package domain
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
 
 var (
 	// ErrEmailBusy is returned when the email is already in use.
@@ -10,17 +14,46 @@ var (
 	ErrUserNotFound = errors.New("user not found")
 )
 
-// NewUser creates a new User instance with the provided id, email, and hashed password.
+// NewUser creates a new User instance with the provided id, email, hashed password and options.
 func NewUser(
 	id string,
 	email string,
 	hashedPassword string,
+	opts ...UserOption,
 ) User {
-	return &user{
+	u := &user{
 		id:             id,
 		email:          email,
 		hashedPassword: hashedPassword,
 	}
+
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	return u
+}
+
+// WithEmailVerified marks the email address of the user as verified.
+func WithEmailVerified() UserOption {
+	return func(u *user) {
+		u.emailVerified = true
+	}
+}
+
+// WithProfile sets the profile the user manages themselves.
+func WithProfile(profile UserProfile) UserOption {
+	return func(u *user) {
+		u.profile = profile
+	}
+}
+
+// WithTimestamps sets the time the user was created and last updated at.
+func WithTimestamps(createdAt time.Time, updatedAt time.Time) UserOption {
+	return func(u *user) {
+		u.createdAt = createdAt
+		u.updatedAt = updatedAt
+	}
 }
 
 type (
@@ -29,12 +62,42 @@ type (
 		ID() string
 		Email() string
 		HashedPassword() string
+		EmailVerified() bool
+		Profile() UserProfile
+		CreatedAt() time.Time
+		UpdatedAt() time.Time
 	}
 
+	// UserProfile holds the details users manage themselves. Metadata is an arbitrary JSON object
+	// that clients can keep their own settings in.
+	UserProfile struct {
+		DisplayName string
+		Locale      string
+		Timezone    string
+		AvatarURL   string
+		Metadata    json.RawMessage
+	}
+
+	// UserProfileUpdate is a partial update of a UserProfile: nil fields are left as they are.
+	UserProfileUpdate struct {
+		DisplayName *string
+		Locale      *string
+		Timezone    *string
+		AvatarURL   *string
+		Metadata    json.RawMessage
+	}
+
+	// UserOption sets optional fields of a User.
+	UserOption func(u *user)
+
 	user struct {
 		id             string
 		email          string
 		hashedPassword string
+		emailVerified  bool
+		profile        UserProfile
+		createdAt      time.Time
+		updatedAt      time.Time
 	}
 )
 
@@ -52,3 +115,23 @@ func (u *user) Email() string {
 func (u *user) HashedPassword() string {
 	return u.hashedPassword
 }
+
+// EmailVerified reports whether the user has proven ownership of the email address.
+func (u *user) EmailVerified() bool {
+	return u.emailVerified
+}
+
+// Profile returns the profile the user manages themselves.
+func (u *user) Profile() UserProfile {
+	return u.profile
+}
+
+// CreatedAt returns the time the user was created at.
+func (u *user) CreatedAt() time.Time {
+	return u.createdAt
+}
+
+// UpdatedAt returns the time the user was last updated at.
+func (u *user) UpdatedAt() time.Time {
+	return u.updatedAt
+}
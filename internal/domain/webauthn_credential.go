@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	// ErrWebAuthnCredentialNotFound is returned when there is no WebAuthn credential with the given ID.
+	ErrWebAuthnCredentialNotFound = errors.New("webauthn credential not found")
+
+	// ErrWebAuthnCredentialExists is returned when a WebAuthn credential with the same ID is already registered.
+	ErrWebAuthnCredentialExists = errors.New("webauthn credential already exists")
+)
+
+// WebAuthnCredential is a passkey of a user. PublicKey is COSE-encoded, SignCount is the signature counter
+// reported by the authenticator with the last assertion, Transports are hints on how to reach the authenticator.
+type WebAuthnCredential struct {
+	ID         []byte
+	UserID     string
+	PublicKey  []byte
+	SignCount  uint32
+	Transports []string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+}
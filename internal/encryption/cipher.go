@@ -0,0 +1,71 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+)
+
+// KeySize is the size of the AES-256 key.
+const KeySize = 32
+
+// ErrMalformedCiphertext is returned when the ciphertext cannot be decoded or is too short.
+var ErrMalformedCiphertext = errors.New("malformed ciphertext")
+
+// NewCipher creates a new *Cipher with the given AES-256 key.
+func NewCipher(key []byte) (*Cipher, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("key must be %d bytes long, got %d", KeySize, len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var aead cipher.AEAD
+	if aead, err = cipher.NewGCM(block); err != nil {
+		return nil, err
+	}
+
+	return &Cipher{
+		aead: aead,
+	}, nil
+}
+
+// Cipher encrypts secrets stored at rest with AES-GCM.
+type Cipher struct {
+	aead cipher.AEAD
+}
+
+// Encrypt encrypts the plaintext and returns base64 of the random nonce followed by the ciphertext.
+// The associated data is authenticated but not stored: the same value must be passed to Decrypt,
+// which binds the ciphertext to its owner, e.g. a user ID.
+func (c *Cipher) Encrypt(plaintext []byte, associatedData []byte) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(c.aead.Seal(nonce, nonce, plaintext, associatedData)), nil
+}
+
+// Decrypt decrypts the ciphertext produced by Encrypt with the same associated data.
+func (c *Cipher) Decrypt(ciphertext string, associatedData []byte) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil || len(data) < c.aead.NonceSize() {
+		return nil, ErrMalformedCiphertext
+	}
+
+	nonce, sealed := data[:c.aead.NonceSize()], data[c.aead.NonceSize():]
+
+	var plaintext []byte
+	if plaintext, err = c.aead.Open(nil, nonce, sealed, associatedData); err != nil {
+		return nil, fmt.Errorf("failed to decrypt: %w", err)
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,42 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"fmt"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+)
+
+const (
+	// DefCipherName is the name of the *Cipher definition.
+	DefCipherName = "encryption.cipher"
+
+	configKeyEncryptionKey = "auth.mfa.encryptionKey"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Cipher]{
+			Name: DefCipherName,
+			Build: func(ctn di.Container) (*Cipher, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var encodedKey string
+				if encodedKey = cfg.GetString(configKeyEncryptionKey); encodedKey == "" {
+					return nil, config.NewErrMissingKey(configKeyEncryptionKey)
+				}
+
+				key, err := base64.StdEncoding.DecodeString(encodedKey)
+				if err != nil {
+					return nil, fmt.Errorf("%s must be base64-encoded: %w", configKeyEncryptionKey, err)
+				}
+
+				return NewCipher(key)
+			},
+		},
+	)
+}
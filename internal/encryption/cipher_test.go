@@ -0,0 +1,58 @@
+package encryption_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/encryption"
+)
+
+func TestNewCipher(t *testing.T) {
+	cipher, err := encryption.NewCipher([]byte("short"))
+	assert.Nil(t, cipher)
+	assert.Error(t, err)
+}
+
+func TestCipher(t *testing.T) {
+	cipher, err := encryption.NewCipher(bytes.Repeat([]byte{1}, encryption.KeySize))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ciphertext, err := cipher.Encrypt([]byte("secret"), []byte("user_id"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("positive case", func(t *testing.T) {
+		plaintext, err := cipher.Decrypt(ciphertext, []byte("user_id"))
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("secret"), plaintext)
+	})
+
+	t.Run("nonce is random", func(t *testing.T) {
+		another, err := cipher.Encrypt([]byte("secret"), []byte("user_id"))
+		assert.NoError(t, err)
+		assert.NotEqual(t, ciphertext, another)
+	})
+
+	t.Run("another associated data", func(t *testing.T) {
+		plaintext, err := cipher.Decrypt(ciphertext, []byte("another_user_id"))
+		assert.Nil(t, plaintext)
+		assert.Error(t, err)
+	})
+
+	t.Run("malformed ciphertext", func(t *testing.T) {
+		plaintext, err := cipher.Decrypt("not base64!", []byte("user_id"))
+		assert.Nil(t, plaintext)
+		assert.Equal(t, encryption.ErrMalformedCiphertext, err)
+	})
+
+	t.Run("ciphertext is too short", func(t *testing.T) {
+		plaintext, err := cipher.Decrypt("AAAA", []byte("user_id"))
+		assert.Nil(t, plaintext)
+		assert.Equal(t, encryption.ErrMalformedCiphertext, err)
+	})
+}
@@ -0,0 +1,49 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/riabininkf/httpx"
+)
+
+// adaptFormHandlerFunc converts a generic handler into http.HandlerFunc that decodes
+// an application/x-www-form-urlencoded body, as required by OAuth 2.0 endpoints.
+// Form fields are matched against the json tags of the request struct.
+func adaptFormHandlerFunc[T any](
+	log httpx.Logger,
+	handle func(ctx context.Context, req *T) *httpx.Response,
+) http.HandlerFunc {
+	return func(writer http.ResponseWriter, req *http.Request) {
+		// token responses contain credentials and must never be cached
+		writer.Header().Set("Cache-Control", "no-store")
+
+		var resp *httpx.Response
+		if err := req.ParseForm(); err != nil {
+			resp = httpx.BadRequest
+		} else {
+			resp = handle(req.Context(), decodeForm[T](req))
+		}
+
+		if err := httpx.WriteJsonResponse(resp, writer); err != nil {
+			log.Error("failed to write response", err)
+		}
+	}
+}
+
+// decodeForm maps the first value of every form field onto a new T using its json tags.
+func decodeForm[T any](req *http.Request) *T {
+	fields := make(map[string]string, len(req.PostForm))
+	for key := range req.PostForm {
+		fields[key] = req.PostForm.Get(key)
+	}
+
+	decoded := new(T)
+
+	// marshaling a map of strings never fails, and unknown or mistyped fields are ignored like in json bodies
+	body, _ := json.Marshal(fields)
+	_ = json.Unmarshal(body, decoded)
+
+	return decoded
+}
@@ -0,0 +1,106 @@
+package handlers
+
+//go:generate mockery --name AuditRecorder --output ./mocks --outpkg mocks --filename audit_recorder.go --structname AuditRecorder
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/riabininkf/http-auth-example/internal/auth"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// Reasons of failed audit events.
+const (
+	auditReasonInvalidCredentials = "invalid_credentials"
+	auditReasonAccountLocked      = "account_locked"
+	auditReasonEmailNotVerified   = "email_not_verified"
+	auditReasonInvalidCode        = "invalid_code"
+	auditReasonTooManyAttempts    = "too_many_attempts"
+	auditReasonInvalidCredential  = "invalid_credential"
+	auditReasonInvalidToken       = "invalid_token"
+	auditReasonTokenReused        = "token_reused"
+	auditReasonInvalidPassword    = "invalid_password"
+	auditReasonInvalidGrant       = "invalid_grant"
+)
+
+// Login methods stored in the details of login events.
+const (
+	loginMethodPassword     = "password"
+	loginMethodTOTP         = "totp"
+	loginMethodRecoveryCode = "recovery_code"
+	loginMethodEmail        = "email"
+	loginMethodWebAuthn     = "webauthn"
+)
+
+// sessionIDLength is the number of hex characters of the refresh token hash used as the session ID.
+const sessionIDLength = 16
+
+// AuditRecorder records audit events with the client the request came from.
+type AuditRecorder interface {
+	// Save returns an error if the event could not be saved, for actions that must not happen without a record.
+	Save(ctx context.Context, event domain.AuditEvent) error
+	// Record only logs failures, so that an unavailable audit trail does not stop users from logging in.
+	Record(ctx context.Context, event domain.AuditEvent)
+}
+
+// sessionID identifies the session of a refresh token in audit events without storing the token itself.
+func sessionID(refreshToken string) string {
+	sum := sha256.Sum256([]byte(refreshToken))
+	return hex.EncodeToString(sum[:])[:sessionIDLength]
+}
+
+// loginEvent returns an audit event of a login with the given method.
+func loginEvent(userID string, method string, outcome string) domain.AuditEvent {
+	return domain.AuditEvent{
+		Type:    domain.AuditEventLogin,
+		UserID:  userID,
+		Outcome: outcome,
+		Details: map[string]string{"method": method},
+	}
+}
+
+// grantEvent returns an audit event of an OAuth grant requested by the client, if the grant identifies one.
+func grantEvent(grantType string, clientID string, userID string, outcome string) domain.AuditEvent {
+	details := map[string]string{"grant_type": grantType}
+	if clientID != "" {
+		details["client_id"] = clientID
+	}
+
+	return domain.AuditEvent{
+		Type:    domain.AuditEventTokenGrant,
+		UserID:  userID,
+		Outcome: outcome,
+		Details: details,
+	}
+}
+
+// failedLoginEvent returns an audit event of a password login rejected with err, attributed to the user
+// if the email is registered, and false if err is not about the credentials, e.g. an internal error.
+func failedLoginEvent(err error) (domain.AuditEvent, bool) {
+	var reason string
+	switch {
+	case errors.Is(err, auth.ErrInvalidCredentials):
+		reason = auditReasonInvalidCredentials
+	case errors.Is(err, auth.ErrAccountLocked):
+		reason = auditReasonAccountLocked
+	case errors.Is(err, auth.ErrEmailNotVerified):
+		reason = auditReasonEmailNotVerified
+	default:
+		return domain.AuditEvent{}, false
+	}
+
+	var userID string
+
+	var userErr *auth.UserError
+	if errors.As(err, &userErr) {
+		userID = userErr.UserID
+	}
+
+	event := loginEvent(userID, loginMethodPassword, domain.AuditOutcomeFailure)
+	event.Reason = reason
+
+	return event, true
+}
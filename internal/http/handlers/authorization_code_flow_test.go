@@ -0,0 +1,198 @@
+package handlers_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+// TestAuthorizationCodeFlow runs the whole authorization code flow with PKCE against in-memory storages.
+func TestAuthorizationCodeFlow(t *testing.T) {
+	const redirectURI = "http://localhost:3000/callback"
+
+	cache := newMemoryCache()
+
+	credentials := mocks.NewCredentialsVerifier(t)
+	credentials.On("Verify", mock.Anything, "user@example.com", "password").
+		Return(domain.NewUser("user_id", "user@example.com", "hashed_password"), nil)
+
+	mfaStatus := mocks.NewMFAStatusProvider(t)
+	mfaStatus.On("IsEnabled", mock.Anything, "user_id").Return(false, nil)
+
+	codes := oauth.NewCodes(time.Minute, cache)
+
+	auditLog := mocks.NewAuditRecorder(t)
+	auditLog.On("Record", mock.Anything, mock.Anything).Return()
+
+	authorize := handlers.NewAuthorize(
+		zap.NewNop(),
+		credentials,
+		mfaStatus,
+		mocks.NewTOTPVerifier(t),
+		mocks.NewRecoveryCodeConsumer(t),
+		mocks.NewMFAAttempts(t),
+		oauth.NewClients(map[string][]string{"spa": {redirectURI}}),
+		codes,
+		auditLog,
+	)
+
+	token := handlers.NewTokenV1(
+		zap.NewNop(),
+		handlers.NewAuthorizationCodeGrant(
+			zap.NewNop(),
+			jwt.NewIssuer("test_issuer", "test_secret", time.Minute, time.Hour),
+			jwt.NewStorage(time.Hour, cache),
+			codes,
+			auditLog,
+		),
+	)
+
+	form := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {"spa"},
+		"redirect_uri":          {redirectURI},
+		"state":                 {"xyz"},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+		"email":                 {"user@example.com"},
+		"password":              {"password"},
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	recorder := httptest.NewRecorder()
+	authorize.ServeHTTP(recorder, req)
+	if !assert.Equal(t, http.StatusFound, recorder.Code) {
+		t.FailNow()
+	}
+
+	location, err := url.Parse(recorder.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "xyz", location.Query().Get("state"))
+
+	tokenReq := &handlers.TokenV1Request{
+		GrantType:    "authorization_code",
+		ClientID:     "spa",
+		Code:         location.Query().Get("code"),
+		RedirectURI:  redirectURI,
+		CodeVerifier: codeVerifier,
+	}
+
+	resp := token.Handle(t.Context(), tokenReq)
+	if !assert.Equal(t, http.StatusOK, resp.Status()) {
+		t.FailNow()
+	}
+
+	body := resp.Body().(*handlers.TokenV1Response)
+	assert.NotEmpty(t, body.AccessToken)
+	assert.NotEmpty(t, body.RefreshToken)
+	assert.Equal(t, "Bearer", body.TokenType)
+	assert.Equal(t, int64(60), body.ExpiresIn)
+
+	// authorization codes are single-use
+	resp = token.Handle(t.Context(), tokenReq)
+	assert.Equal(t, http.StatusBadRequest, resp.Status())
+	assert.Equal(t, "invalid_grant", resp.Body().(*handlers.OAuthErrorResponse).Error)
+}
+
+// memoryCache is an in-memory replacement of the redis client for tests.
+type memoryCache struct {
+	mu     sync.Mutex
+	values map[string]string
+	sets   map[string][]string
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{values: make(map[string]string), sets: make(map[string][]string)}
+}
+
+func (c *memoryCache) Set(_ context.Context, key string, value any, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.values[key], _ = value.(string)
+	return nil
+}
+
+func (c *memoryCache) Get(_ context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.values[key]
+	if !ok {
+		return "", redis.ErrNotFound
+	}
+
+	return value, nil
+}
+
+func (c *memoryCache) Pop(ctx context.Context, key string) error {
+	_, err := c.GetDel(ctx, key)
+	return err
+}
+
+func (c *memoryCache) GetDel(_ context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.values[key]
+	if !ok {
+		return "", redis.ErrNotFound
+	}
+
+	delete(c.values, key)
+	return value, nil
+}
+
+func (c *memoryCache) AddToSet(_ context.Context, key string, member string, _ time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !slices.Contains(c.sets[key], member) {
+		c.sets[key] = append(c.sets[key], member)
+	}
+
+	return nil
+}
+
+func (c *memoryCache) PopSet(_ context.Context, key string) ([]string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	members := c.sets[key]
+	delete(c.sets, key)
+
+	return members, nil
+}
+
+func (c *memoryCache) Del(_ context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.values, key)
+	}
+
+	return nil
+}
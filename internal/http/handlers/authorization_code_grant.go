@@ -0,0 +1,151 @@
+package handlers
+
+//go:generate mockery --name AuthorizationCodeRedeemer --output ./mocks --outpkg mocks --filename authorization_code_redeemer.go --structname AuthorizationCodeRedeemer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// grantTypeAuthorizationCode is the grant type of the authorization code grant.
+const grantTypeAuthorizationCode = "authorization_code"
+
+// NewAuthorizationCodeGrant creates a new *AuthorizationCodeGrant instance.
+func NewAuthorizationCodeGrant(
+	log *logger.Logger,
+	issuer TokenIssuer,
+	jwtStorage JwtStorage,
+	codes AuthorizationCodeRedeemer,
+	auditLog AuditRecorder,
+) *AuthorizationCodeGrant {
+	return &AuthorizationCodeGrant{
+		log:        log,
+		issuer:     issuer,
+		jwtStorage: jwtStorage,
+		codes:      codes,
+		auditLog:   auditLog,
+	}
+}
+
+type (
+	// AuthorizationCodeGrant exchanges authorization codes issued by Authorize for access and refresh tokens.
+	AuthorizationCodeGrant struct {
+		log        *logger.Logger
+		issuer     TokenIssuer
+		jwtStorage JwtStorage
+		codes      AuthorizationCodeRedeemer
+		auditLog   AuditRecorder
+	}
+
+	// AuthorizationCodeRedeemer describes AuthorizationCodeRedeemer dependency.
+	AuthorizationCodeRedeemer interface {
+		Redeem(ctx context.Context, code string) (oauth.AuthorizationCode, error)
+	}
+)
+
+// GrantType implements TokenGrant.
+func (g *AuthorizationCodeGrant) GrantType() string {
+	return grantTypeAuthorizationCode
+}
+
+// Grant redeems the authorization code, checks that it was issued to the same client, redirect URI and PKCE
+// challenge, and issues access and refresh tokens for the user who authorized it.
+func (g *AuthorizationCodeGrant) Grant(ctx context.Context, req *TokenV1Request) *httpx.Response {
+	if req.Code == "" {
+		g.log.Warn("code is missing")
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "code is required")
+	}
+
+	if req.ClientID == "" {
+		g.log.Warn("client_id is missing")
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "client_id is required")
+	}
+
+	if req.RedirectURI == "" {
+		g.log.Warn("redirect_uri is missing")
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "redirect_uri is required")
+	}
+
+	if req.CodeVerifier == "" {
+		g.log.Warn("code_verifier is missing")
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "code_verifier is required")
+	}
+
+	var (
+		err  error
+		code oauth.AuthorizationCode
+	)
+	if code, err = g.codes.Redeem(ctx, req.Code); err != nil {
+		if errors.Is(err, oauth.ErrInvalidCode) {
+			g.log.Warn("invalid authorization code")
+			return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidGrant, "invalid authorization code")
+		}
+
+		g.log.Error("failed to redeem authorization code", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if code.ClientID != req.ClientID {
+		g.log.Warn("authorization code was issued to another client")
+		g.recordFailure(ctx, req.ClientID, code.UserID)
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidGrant, "invalid authorization code")
+	}
+
+	if code.RedirectURI != req.RedirectURI {
+		g.log.Warn("redirect_uri does not match")
+		g.recordFailure(ctx, req.ClientID, code.UserID)
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidGrant, "redirect_uri does not match")
+	}
+
+	if err = oauth.VerifyCodeVerifier(req.CodeVerifier, code.CodeChallenge); err != nil {
+		g.log.Warn("invalid code verifier", logger.Error(err))
+		g.recordFailure(ctx, req.ClientID, code.UserID)
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidGrant, "invalid code_verifier")
+	}
+
+	var accessToken string
+	if accessToken, err = g.issuer.IssueAccessToken(code.UserID); err != nil {
+		g.log.Error("failed to issue access token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	var refreshToken string
+	if refreshToken, err = g.issuer.IssueRefreshToken(code.UserID); err != nil {
+		g.log.Error("failed to issue refresh token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if err = g.jwtStorage.Save(ctx, code.UserID, refreshToken); err != nil {
+		g.log.Error("failed to save refresh token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	event := grantEvent(grantTypeAuthorizationCode, req.ClientID, code.UserID, domain.AuditOutcomeSuccess)
+	event.SessionID = sessionID(refreshToken)
+	g.auditLog.Record(ctx, event)
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&TokenV1Response{
+			AccessToken:  accessToken,
+			TokenType:    tokenTypeBearer,
+			ExpiresIn:    int64(g.issuer.AccessTokenTTL().Seconds()),
+			RefreshToken: refreshToken,
+		}),
+	)
+}
+
+// recordFailure records a code that was redeemed by the wrong client, for the wrong redirect_uri or without the
+// right code_verifier, which may mean the code was intercepted.
+func (g *AuthorizationCodeGrant) recordFailure(ctx context.Context, clientID string, userID string) {
+	event := grantEvent(grantTypeAuthorizationCode, clientID, userID, domain.AuditOutcomeFailure)
+	event.Reason = auditReasonInvalidGrant
+	g.auditLog.Record(ctx, event)
+}
@@ -0,0 +1,265 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// codeChallenge is BASE64URL(SHA256(codeVerifier)).
+const (
+	codeVerifier  = "dBjftJeZ4CVP-mJ92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	codeChallenge = "ngF5GsXcbwljx6u133FFr3Xht9xooA_DuaX_3QwODtc"
+)
+
+func TestAuthorizationCodeGrant_Grant(t *testing.T) {
+	generateRequest := func() *handlers.TokenV1Request {
+		return &handlers.TokenV1Request{
+			GrantType:    "authorization_code",
+			ClientID:     "client_id",
+			Code:         "code",
+			RedirectURI:  "https://app.example.com/callback",
+			CodeVerifier: codeVerifier,
+		}
+	}
+
+	generateCode := func() (oauth.AuthorizationCode, error) {
+		return oauth.AuthorizationCode{
+			ClientID:      "client_id",
+			RedirectURI:   "https://app.example.com/callback",
+			UserID:        "user_id",
+			CodeChallenge: codeChallenge,
+		}, nil
+	}
+
+	invalidGrant := func(description string) *httpx.Response {
+		return httpx.NewJsonResponse(
+			httpx.WithStatus(http.StatusBadRequest),
+			httpx.WithBody(&handlers.OAuthErrorResponse{Error: "invalid_grant", ErrorDescription: description}),
+		)
+	}
+
+	invalidRequest := func(description string) *httpx.Response {
+		return httpx.NewJsonResponse(
+			httpx.WithStatus(http.StatusBadRequest),
+			httpx.WithBody(&handlers.OAuthErrorResponse{Error: "invalid_request", ErrorDescription: description}),
+		)
+	}
+
+	testCases := []struct {
+		name                string
+		req                 func() *handlers.TokenV1Request
+		onRedeem            func() (oauth.AuthorizationCode, error)
+		onIssueAccessToken  func() (string, error)
+		onIssueRefreshToken func() (string, error)
+		onSaveRefreshToken  func() error
+		expEvent            *domain.AuditEvent
+		expResp             *httpx.Response
+	}{
+		{
+			name: "code is missing",
+			req: func() *handlers.TokenV1Request {
+				req := generateRequest()
+				req.Code = ""
+				return req
+			},
+			expResp: invalidRequest("code is required"),
+		},
+		{
+			name: "client id is missing",
+			req: func() *handlers.TokenV1Request {
+				req := generateRequest()
+				req.ClientID = ""
+				return req
+			},
+			expResp: invalidRequest("client_id is required"),
+		},
+		{
+			name: "redirect uri is missing",
+			req: func() *handlers.TokenV1Request {
+				req := generateRequest()
+				req.RedirectURI = ""
+				return req
+			},
+			expResp: invalidRequest("redirect_uri is required"),
+		},
+		{
+			name: "code verifier is missing",
+			req: func() *handlers.TokenV1Request {
+				req := generateRequest()
+				req.CodeVerifier = ""
+				return req
+			},
+			expResp: invalidRequest("code_verifier is required"),
+		},
+		{
+			name:     "invalid code",
+			req:      generateRequest,
+			onRedeem: func() (oauth.AuthorizationCode, error) { return oauth.AuthorizationCode{}, oauth.ErrInvalidCode },
+			expResp:  invalidGrant("invalid authorization code"),
+		},
+		{
+			name:     "failed to redeem code",
+			req:      generateRequest,
+			onRedeem: func() (oauth.AuthorizationCode, error) { return oauth.AuthorizationCode{}, assert.AnError },
+			expResp:  httpx.InternalServerError,
+		},
+		{
+			name: "code issued to another client",
+			req: func() *handlers.TokenV1Request {
+				req := generateRequest()
+				req.ClientID = "another_client"
+				return req
+			},
+			onRedeem: generateCode,
+			expEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventTokenGrant,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_grant",
+				Details: map[string]string{"grant_type": "authorization_code", "client_id": "another_client"},
+			},
+			expResp: invalidGrant("invalid authorization code"),
+		},
+		{
+			name: "redirect uri does not match",
+			req: func() *handlers.TokenV1Request {
+				req := generateRequest()
+				req.RedirectURI = "https://app.example.com/another"
+				return req
+			},
+			onRedeem: generateCode,
+			expEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventTokenGrant,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_grant",
+				Details: map[string]string{"grant_type": "authorization_code", "client_id": "client_id"},
+			},
+			expResp: invalidGrant("redirect_uri does not match"),
+		},
+		{
+			name: "invalid code verifier",
+			req: func() *handlers.TokenV1Request {
+				req := generateRequest()
+				req.CodeVerifier = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+				return req
+			},
+			onRedeem: generateCode,
+			expEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventTokenGrant,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_grant",
+				Details: map[string]string{"grant_type": "authorization_code", "client_id": "client_id"},
+			},
+			expResp: invalidGrant("invalid code_verifier"),
+		},
+		{
+			name:               "failed to issue access token",
+			req:                generateRequest,
+			onRedeem:           generateCode,
+			onIssueAccessToken: func() (string, error) { return "", assert.AnError },
+			expResp:            httpx.InternalServerError,
+		},
+		{
+			name:                "failed to issue refresh token",
+			req:                 generateRequest,
+			onRedeem:            generateCode,
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "", assert.AnError },
+			expResp:             httpx.InternalServerError,
+		},
+		{
+			name:                "failed to save refresh token",
+			req:                 generateRequest,
+			onRedeem:            generateCode,
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return assert.AnError },
+			expResp:             httpx.InternalServerError,
+		},
+		{
+			name:                "positive case",
+			req:                 generateRequest,
+			onRedeem:            generateCode,
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return nil },
+			expEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventTokenGrant,
+				UserID:    "user_id",
+				SessionID: "6c8a7d4aa21708a4",
+				Outcome:   domain.AuditOutcomeSuccess,
+				Details:   map[string]string{"grant_type": "authorization_code", "client_id": "client_id"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.TokenV1Response{
+					AccessToken:  "access_token",
+					TokenType:    "Bearer",
+					ExpiresIn:    300,
+					RefreshToken: "refresh_token",
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := testCase.req()
+
+			codes := mocks.NewAuthorizationCodeRedeemer(t)
+			if testCase.onRedeem != nil {
+				codes.On("Redeem", t.Context(), req.Code).Return(testCase.onRedeem())
+			}
+
+			issuer := mocks.NewTokenIssuer(t)
+			if testCase.onIssueAccessToken != nil {
+				issuer.On("IssueAccessToken", "user_id").Return(testCase.onIssueAccessToken())
+			}
+
+			var refreshToken string
+			if testCase.onIssueRefreshToken != nil {
+				var err error
+				refreshToken, err = testCase.onIssueRefreshToken()
+
+				issuer.On("IssueRefreshToken", "user_id").Return(refreshToken, err)
+			}
+
+			jwtStorage := mocks.NewJwtStorage(t)
+			if testCase.onSaveRefreshToken != nil {
+				jwtStorage.On("Save", t.Context(), "user_id", refreshToken).Return(testCase.onSaveRefreshToken())
+			}
+
+			if testCase.expResp.Status() == http.StatusOK {
+				issuer.On("AccessTokenTTL").Return(5 * time.Minute)
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expEvent != nil {
+				auditLog.On("Record", t.Context(), *testCase.expEvent).Return()
+			}
+
+			grant := handlers.NewAuthorizationCodeGrant(
+				zap.NewNop(),
+				issuer,
+				jwtStorage,
+				codes,
+				auditLog,
+			)
+
+			assert.Equal(t, "authorization_code", grant.GrantType())
+			assert.Equal(t, testCase.expResp, grant.Grant(t.Context(), req))
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package handlers
+
+//go:generate mockery --name OAuthClients --output ./mocks --outpkg mocks --filename oauth_clients.go --structname OAuthClients
+//go:generate mockery --name AuthorizationCodeIssuer --output ./mocks --outpkg mocks --filename authorization_code_issuer.go --structname AuthorizationCodeIssuer
+
+import (
+	"context"
+	_ "embed"
+	"html/template"
+	"net/http"
+	"net/url"
+
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// responseTypeCode is the only response type supported by the authorization endpoint.
+const responseTypeCode = "code"
+
+//go:embed templates/authorize.html
+var authorizePage string
+
+// NewAuthorize creates a new *Authorize instance.
+func NewAuthorize(
+	log *logger.Logger,
+	credentials CredentialsVerifier,
+	mfaStatus MFAStatusProvider,
+	totp TOTPVerifier,
+	recoveryCodes RecoveryCodeConsumer,
+	attempts MFAAttempts,
+	clients OAuthClients,
+	codes AuthorizationCodeIssuer,
+	auditLog AuditRecorder,
+) *Authorize {
+	return &Authorize{
+		log:         log,
+		credentials: credentials,
+		secondFactor: &secondFactor{
+			log:           log,
+			mfaStatus:     mfaStatus,
+			totp:          totp,
+			recoveryCodes: recoveryCodes,
+			attempts:      attempts,
+			auditLog:      auditLog,
+		},
+		clients:  clients,
+		codes:    codes,
+		page:     template.Must(template.New("authorize").Parse(authorizePage)),
+		auditLog: auditLog,
+	}
+}
+
+type (
+	// Authorize is the OAuth 2.0 authorization endpoint. It renders a login page and, once the user signs in,
+	// redirects back to the client with a single-use authorization code bound to the PKCE challenge.
+	Authorize struct {
+		log          *logger.Logger
+		credentials  CredentialsVerifier
+		secondFactor *secondFactor
+		clients      OAuthClients
+		codes        AuthorizationCodeIssuer
+		page         *template.Template
+		auditLog     AuditRecorder
+	}
+
+	// OAuthClients describes OAuthClients dependency.
+	OAuthClients interface {
+		Exists(clientID string) bool
+		IsRedirectURIAllowed(clientID string, redirectURI string) bool
+	}
+
+	// AuthorizationCodeIssuer describes AuthorizationCodeIssuer dependency.
+	AuthorizationCodeIssuer interface {
+		Issue(ctx context.Context, grant oauth.AuthorizationCode) (string, error)
+	}
+
+	// authorizePageData holds authorization request parameters and the state of the login page.
+	authorizePageData struct {
+		ResponseType        string
+		ClientID            string
+		RedirectURI         string
+		State               string
+		CodeChallenge       string
+		CodeChallengeMethod string
+		Email               string
+		Error               string
+		ShowForm            bool
+	}
+)
+
+// ServeHTTP renders the login page on GET and verifies the submitted credentials on POST.
+func (h *Authorize) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	setPageHeaders(writer)
+
+	if err := req.ParseForm(); err != nil {
+		h.log.Warn("failed to parse authorization request", logger.Error(err))
+		h.render(writer, http.StatusBadRequest, &authorizePageData{Error: "malformed authorization request"})
+		return
+	}
+
+	data := &authorizePageData{
+		ResponseType:        req.Form.Get("response_type"),
+		ClientID:            req.Form.Get("client_id"),
+		RedirectURI:         req.Form.Get("redirect_uri"),
+		State:               req.Form.Get("state"),
+		CodeChallenge:       req.Form.Get("code_challenge"),
+		CodeChallengeMethod: req.Form.Get("code_challenge_method"),
+	}
+
+	// errors about the client itself must not be redirected, otherwise the endpoint becomes an open redirector
+	if !h.clients.IsRedirectURIAllowed(data.ClientID, data.RedirectURI) {
+		h.log.Warn("unknown client or redirect uri", logger.String("client_id", data.ClientID))
+		data.Error = "unknown client or redirect uri"
+		h.render(writer, http.StatusBadRequest, data)
+		return
+	}
+
+	if data.ResponseType != responseTypeCode {
+		h.log.Warn("unsupported response type", logger.String("response_type", data.ResponseType))
+		h.redirect(writer, req, data, url.Values{"error": {"unsupported_response_type"}})
+		return
+	}
+
+	if err := oauth.ValidateCodeChallenge(data.CodeChallenge, data.CodeChallengeMethod); err != nil {
+		h.log.Warn("invalid code challenge", logger.Error(err))
+		h.redirect(writer, req, data, url.Values{
+			"error":             {"invalid_request"},
+			"error_description": {"code_challenge with code_challenge_method S256 is required"},
+		})
+		return
+	}
+
+	// tokens issued to clients carry the full access of the user, so a client must not be told it got less
+	if req.Form.Get("scope") != "" {
+		h.log.Warn("scope is not supported", logger.String("client_id", data.ClientID))
+		h.redirect(writer, req, data, url.Values{
+			"error":             {oauthErrInvalidScope},
+			"error_description": {"scope is not supported"},
+		})
+		return
+	}
+
+	data.ShowForm = true
+
+	if req.Method != http.MethodPost {
+		h.render(writer, http.StatusOK, data)
+		return
+	}
+
+	data.Email = req.PostForm.Get("email")
+	password := req.PostForm.Get("password")
+
+	if data.Email == "" || password == "" {
+		h.log.Warn("email or password is missing")
+		data.Error = "email and password are required"
+		h.render(writer, http.StatusBadRequest, data)
+		return
+	}
+
+	var (
+		err  error
+		user domain.User
+	)
+	if user, err = h.credentials.Verify(req.Context(), data.Email, password); err != nil {
+		status, message := credentialsError(req.Context(), h.log, h.auditLog, err)
+		if status == http.StatusInternalServerError {
+			data = &authorizePageData{}
+		}
+
+		data.Error = message
+		h.render(writer, status, data)
+		return
+	}
+
+	if status, message := h.secondFactor.verify(
+		req.Context(), user.ID(), req.PostForm.Get("otp"), req.PostForm.Get("recovery_code"),
+	); status != 0 {
+		if status == http.StatusInternalServerError {
+			data = &authorizePageData{}
+		}
+
+		data.Error = message
+		h.render(writer, status, data)
+		return
+	}
+
+	event := loginEvent(user.ID(), loginMethodPassword, domain.AuditOutcomeSuccess)
+	event.Details["client_id"] = data.ClientID
+	h.auditLog.Record(req.Context(), event)
+
+	var code string
+	if code, err = h.codes.Issue(req.Context(), oauth.AuthorizationCode{
+		ClientID:      data.ClientID,
+		RedirectURI:   data.RedirectURI,
+		UserID:        user.ID(),
+		CodeChallenge: data.CodeChallenge,
+	}); err != nil {
+		h.log.Error("failed to issue authorization code", logger.Error(err))
+		h.render(writer, http.StatusInternalServerError, &authorizePageData{Error: "internal server error"})
+		return
+	}
+
+	h.redirect(writer, req, data, url.Values{"code": {code}})
+}
+
+// render writes the login page with the given status.
+func (h *Authorize) render(writer http.ResponseWriter, status int, data *authorizePageData) {
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	writer.WriteHeader(status)
+
+	if err := h.page.Execute(writer, data); err != nil {
+		h.log.Error("failed to render authorization page", logger.Error(err))
+	}
+}
+
+// redirect sends the user agent back to the client's redirect URI with the given parameters and the request state.
+func (h *Authorize) redirect(writer http.ResponseWriter, req *http.Request, data *authorizePageData, params url.Values) {
+	var (
+		err         error
+		redirectURI *url.URL
+	)
+	if redirectURI, err = url.Parse(data.RedirectURI); err != nil {
+		h.log.Error("failed to parse registered redirect uri", logger.Error(err))
+		h.render(writer, http.StatusInternalServerError, &authorizePageData{Error: "internal server error"})
+		return
+	}
+
+	query := redirectURI.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+
+	if data.State != "" {
+		query.Set("state", data.State)
+	}
+
+	redirectURI.RawQuery = query.Encode()
+
+	http.Redirect(writer, req, redirectURI.String(), http.StatusFound)
+}
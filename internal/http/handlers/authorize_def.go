@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/auth"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// DefAuthorizeName is the name of the *Authorize definition.
+const DefAuthorizeName = "http.authorize"
+
+func init() {
+	di.Add(
+		di.Def[*Authorize]{
+			Name: DefAuthorizeName,
+			Build: func(ctn di.Container) (*Authorize, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var credentials *auth.Credentials
+				if err := ctn.Fill(auth.DefCredentialsName, &credentials); err != nil {
+					return nil, err
+				}
+
+				var totp *mfa.TOTP
+				if err := ctn.Fill(mfa.DefTOTPName, &totp); err != nil {
+					return nil, err
+				}
+
+				var recoveryCodes *mfa.RecoveryCodes
+				if err := ctn.Fill(mfa.DefRecoveryCodesName, &recoveryCodes); err != nil {
+					return nil, err
+				}
+
+				var attempts *mfa.Attempts
+				if err := ctn.Fill(mfa.DefAttemptsName, &attempts); err != nil {
+					return nil, err
+				}
+
+				var clients *oauth.Clients
+				if err := ctn.Fill(oauth.DefClientsName, &clients); err != nil {
+					return nil, err
+				}
+
+				var codes *oauth.Codes
+				if err := ctn.Fill(oauth.DefCodesName, &codes); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewAuthorize(
+					log,
+					credentials,
+					totp,
+					totp,
+					recoveryCodes,
+					attempts,
+					clients,
+					codes,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,450 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/auth"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+func TestAuthorize_ServeHTTP(t *testing.T) {
+	generateParams := func() url.Values {
+		return url.Values{
+			"response_type":         {"code"},
+			"client_id":             {"client_id"},
+			"redirect_uri":          {"https://app.example.com/callback?tenant=1"},
+			"state":                 {"state"},
+			"code_challenge":        {codeChallenge},
+			"code_challenge_method": {"S256"},
+		}
+	}
+
+	newGetRequest := func(params url.Values) func() *http.Request {
+		return func() *http.Request {
+			return httptest.NewRequest(http.MethodGet, "/oauth/authorize?"+params.Encode(), nil)
+		}
+	}
+
+	newPostRequest := func(email string, password string) func() *http.Request {
+		return func() *http.Request {
+			form := generateParams()
+			form.Set("email", email)
+			form.Set("password", password)
+			form.Set("otp", "123456")
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return req
+		}
+	}
+
+	newRecoveryRequest := func() *http.Request {
+		form := generateParams()
+		form.Set("email", "user@example.com")
+		form.Set("password", "password")
+		form.Set("recovery_code", "AAAA-BBBB-CCCC-DDDD")
+
+		req := httptest.NewRequest(http.MethodPost, "/oauth/authorize", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	withParam := func(key string, value string) url.Values {
+		params := generateParams()
+		params.Set(key, value)
+		return params
+	}
+
+	testCases := []struct {
+		name                string
+		req                 func() *http.Request
+		redirectURIAllowed  bool
+		onVerifyCredentials func() (domain.User, error)
+		onIsMFAEnabled      func() (bool, error)
+		onAddAttempt        func() error
+		onVerifyTOTP        func() error
+		onUseRecoveryCode   func() error
+		onResetAttempts     func() error
+		onIssueCode         func() (string, error)
+		expRecoveryCodeUsed bool
+		expAuditEvent       *domain.AuditEvent
+		expStatus           int
+		expLocation         string
+		expBody             string
+	}{
+		{
+			name:               "unknown client or redirect uri",
+			req:                newGetRequest(generateParams()),
+			redirectURIAllowed: false,
+			expStatus:          http.StatusBadRequest,
+			expBody:            "unknown client or redirect uri",
+		},
+		{
+			name:               "unsupported response type",
+			req:                newGetRequest(withParam("response_type", "token")),
+			redirectURIAllowed: true,
+			expStatus:          http.StatusFound,
+			expLocation:        "https://app.example.com/callback?error=unsupported_response_type&state=state&tenant=1",
+		},
+		{
+			name:               "code challenge is missing",
+			req:                newGetRequest(withParam("code_challenge", "")),
+			redirectURIAllowed: true,
+			expStatus:          http.StatusFound,
+			expLocation: "https://app.example.com/callback?error=invalid_request&" +
+				"error_description=code_challenge+with+code_challenge_method+S256+is+required&state=state&tenant=1",
+		},
+		{
+			name:               "plain code challenge method",
+			req:                newGetRequest(withParam("code_challenge_method", "plain")),
+			redirectURIAllowed: true,
+			expStatus:          http.StatusFound,
+			expLocation: "https://app.example.com/callback?error=invalid_request&" +
+				"error_description=code_challenge+with+code_challenge_method+S256+is+required&state=state&tenant=1",
+		},
+		{
+			name:               "scope is not supported",
+			req:                newGetRequest(withParam("scope", "profile")),
+			redirectURIAllowed: true,
+			expStatus:          http.StatusFound,
+			expLocation: "https://app.example.com/callback?error=invalid_scope&" +
+				"error_description=scope+is+not+supported&state=state&tenant=1",
+		},
+		{
+			name:               "login page",
+			req:                newGetRequest(generateParams()),
+			redirectURIAllowed: true,
+			expStatus:          http.StatusOK,
+			expBody:            `<input type="password" name="password"`,
+		},
+		{
+			name:               "password is missing",
+			req:                newPostRequest("user@example.com", ""),
+			redirectURIAllowed: true,
+			expStatus:          http.StatusBadRequest,
+			expBody:            "email and password are required",
+		},
+		{
+			name:                "invalid credentials",
+			req:                 newPostRequest("user@example.com", "password"),
+			redirectURIAllowed:  true,
+			onVerifyCredentials: func() (domain.User, error) { return nil, auth.ErrInvalidCredentials },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_credentials",
+				Details: map[string]string{"method": "password"},
+			},
+			expStatus: http.StatusUnauthorized,
+			expBody:   "invalid email or password",
+		},
+		{
+			name:                "email is not verified",
+			req:                 newPostRequest("user@example.com", "password"),
+			redirectURIAllowed:  true,
+			onVerifyCredentials: func() (domain.User, error) { return nil, auth.ErrEmailNotVerified },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "email_not_verified",
+				Details: map[string]string{"method": "password"},
+			},
+			expStatus: http.StatusForbidden,
+			expBody:   "email address is not verified",
+		},
+		{
+			name:                "account is locked",
+			req:                 newPostRequest("user@example.com", "password"),
+			redirectURIAllowed:  true,
+			onVerifyCredentials: func() (domain.User, error) { return nil, &auth.AccountLockedError{} },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "account_locked",
+				Details: map[string]string{"method": "password"},
+			},
+			expStatus: http.StatusUnauthorized,
+			expBody:   "invalid email or password",
+		},
+		{
+			name:                "failed to verify credentials",
+			req:                 newPostRequest("user@example.com", "password"),
+			redirectURIAllowed:  true,
+			onVerifyCredentials: func() (domain.User, error) { return nil, assert.AnError },
+			expStatus:           http.StatusInternalServerError,
+			expBody:             "internal server error",
+		},
+		{
+			name:               "failed to check mfa status",
+			req:                newPostRequest("user@example.com", "password"),
+			redirectURIAllowed: true,
+			onVerifyCredentials: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onIsMFAEnabled: func() (bool, error) { return false, assert.AnError },
+			expStatus:      http.StatusInternalServerError,
+			expBody:        "internal server error",
+		},
+		{
+			name:               "invalid authentication code",
+			req:                newPostRequest("user@example.com", "password"),
+			redirectURIAllowed: true,
+			onVerifyCredentials: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onIsMFAEnabled: func() (bool, error) { return true, nil },
+			onAddAttempt:   func() error { return nil },
+			onVerifyTOTP:   func() error { return mfa.ErrInvalidCode },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_code",
+				Details: map[string]string{"method": "totp"},
+			},
+			expStatus: http.StatusUnauthorized,
+			expBody:   "authentication code is missing or invalid",
+		},
+		{
+			name:               "invalid recovery code",
+			req:                newRecoveryRequest,
+			redirectURIAllowed: true,
+			onVerifyCredentials: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onIsMFAEnabled:    func() (bool, error) { return true, nil },
+			onAddAttempt:      func() error { return nil },
+			onUseRecoveryCode: func() error { return mfa.ErrInvalidCode },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_code",
+				Details: map[string]string{"method": "recovery_code"},
+			},
+			expStatus: http.StatusUnauthorized,
+			expBody:   "authentication code is missing or invalid",
+		},
+		{
+			name:               "failed to verify authentication code",
+			req:                newPostRequest("user@example.com", "password"),
+			redirectURIAllowed: true,
+			onVerifyCredentials: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onIsMFAEnabled: func() (bool, error) { return true, nil },
+			onAddAttempt:   func() error { return nil },
+			onVerifyTOTP:   func() error { return assert.AnError },
+			expStatus:      http.StatusInternalServerError,
+			expBody:        "internal server error",
+		},
+		{
+			name:               "too many authentication code attempts",
+			req:                newPostRequest("user@example.com", "password"),
+			redirectURIAllowed: true,
+			onVerifyCredentials: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onIsMFAEnabled: func() (bool, error) { return true, nil },
+			onAddAttempt:   func() error { return mfa.ErrTooManyAttempts },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "too_many_attempts",
+				Details: map[string]string{"method": "totp"},
+			},
+			expStatus: http.StatusTooManyRequests,
+			expBody:   "too many invalid authentication codes",
+		},
+		{
+			name:               "failed to count authentication code attempt",
+			req:                newPostRequest("user@example.com", "password"),
+			redirectURIAllowed: true,
+			onVerifyCredentials: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onIsMFAEnabled: func() (bool, error) { return true, nil },
+			onAddAttempt:   func() error { return assert.AnError },
+			expStatus:      http.StatusInternalServerError,
+			expBody:        "internal server error",
+		},
+		{
+			name:               "failed to reset authentication code attempts",
+			req:                newPostRequest("user@example.com", "password"),
+			redirectURIAllowed: true,
+			onVerifyCredentials: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onIsMFAEnabled:  func() (bool, error) { return true, nil },
+			onAddAttempt:    func() error { return nil },
+			onVerifyTOTP:    func() error { return nil },
+			onResetAttempts: func() error { return assert.AnError },
+			expStatus:       http.StatusInternalServerError,
+			expBody:         "internal server error",
+		},
+		{
+			name:               "failed to issue authorization code",
+			req:                newPostRequest("user@example.com", "password"),
+			redirectURIAllowed: true,
+			onVerifyCredentials: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onIsMFAEnabled: func() (bool, error) { return false, nil },
+			onIssueCode:    func() (string, error) { return "", assert.AnError },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+				Details: map[string]string{"method": "password", "client_id": "client_id"},
+			},
+			expStatus: http.StatusInternalServerError,
+			expBody:   "internal server error",
+		},
+		{
+			name:               "positive case",
+			req:                newPostRequest("user@example.com", "password"),
+			redirectURIAllowed: true,
+			onVerifyCredentials: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onIsMFAEnabled: func() (bool, error) { return false, nil },
+			onIssueCode:    func() (string, error) { return "code", nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+				Details: map[string]string{"method": "password", "client_id": "client_id"},
+			},
+			expStatus:   http.StatusFound,
+			expLocation: "https://app.example.com/callback?code=code&state=state&tenant=1",
+		},
+		{
+			name:               "positive case with mfa",
+			req:                newPostRequest("user@example.com", "password"),
+			redirectURIAllowed: true,
+			onVerifyCredentials: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onIsMFAEnabled:  func() (bool, error) { return true, nil },
+			onAddAttempt:    func() error { return nil },
+			onVerifyTOTP:    func() error { return nil },
+			onResetAttempts: func() error { return nil },
+			onIssueCode:     func() (string, error) { return "code", nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+				Details: map[string]string{"method": "password", "client_id": "client_id"},
+			},
+			expStatus:   http.StatusFound,
+			expLocation: "https://app.example.com/callback?code=code&state=state&tenant=1",
+		},
+		{
+			name:               "positive case with recovery code",
+			req:                newRecoveryRequest,
+			redirectURIAllowed: true,
+			onVerifyCredentials: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onIsMFAEnabled:      func() (bool, error) { return true, nil },
+			onAddAttempt:        func() error { return nil },
+			onUseRecoveryCode:   func() error { return nil },
+			onResetAttempts:     func() error { return nil },
+			onIssueCode:         func() (string, error) { return "code", nil },
+			expRecoveryCodeUsed: true,
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+				Details: map[string]string{"method": "password", "client_id": "client_id"},
+			},
+			expStatus:   http.StatusFound,
+			expLocation: "https://app.example.com/callback?code=code&state=state&tenant=1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			clients := mocks.NewOAuthClients(t)
+			clients.On("IsRedirectURIAllowed", "client_id", "https://app.example.com/callback?tenant=1").
+				Return(testCase.redirectURIAllowed)
+
+			credentials := mocks.NewCredentialsVerifier(t)
+			if testCase.onVerifyCredentials != nil {
+				credentials.On("Verify", mock.Anything, "user@example.com", "password").
+					Return(testCase.onVerifyCredentials())
+			}
+
+			mfaStatus := mocks.NewMFAStatusProvider(t)
+			if testCase.onIsMFAEnabled != nil {
+				mfaStatus.On("IsEnabled", mock.Anything, "user_id").Return(testCase.onIsMFAEnabled())
+			}
+
+			attempts := mocks.NewMFAAttempts(t)
+			if testCase.onAddAttempt != nil {
+				attempts.On("Add", mock.Anything, "user_id").Return(testCase.onAddAttempt())
+			}
+
+			if testCase.onResetAttempts != nil {
+				attempts.On("Reset", mock.Anything, "user_id").Return(testCase.onResetAttempts())
+			}
+
+			totp := mocks.NewTOTPVerifier(t)
+			if testCase.onVerifyTOTP != nil {
+				totp.On("Verify", mock.Anything, "user_id", "123456").Return(testCase.onVerifyTOTP())
+			}
+
+			recoveryCodes := mocks.NewRecoveryCodeConsumer(t)
+			if testCase.onUseRecoveryCode != nil {
+				recoveryCodes.On("Use", mock.Anything, "user_id", "AAAA-BBBB-CCCC-DDDD").Return(testCase.onUseRecoveryCode())
+			}
+
+			codes := mocks.NewAuthorizationCodeIssuer(t)
+			if testCase.onIssueCode != nil {
+				codes.On("Issue", mock.Anything, oauth.AuthorizationCode{
+					ClientID:      "client_id",
+					RedirectURI:   "https://app.example.com/callback?tenant=1",
+					UserID:        "user_id",
+					CodeChallenge: codeChallenge,
+				}).Return(testCase.onIssueCode())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", mock.Anything, *testCase.expAuditEvent).Return()
+			}
+
+			if testCase.expRecoveryCodeUsed {
+				auditLog.On("Record", mock.Anything, domain.AuditEvent{
+					Type:   domain.AuditEventRecoveryCodeUsed,
+					UserID: "user_id",
+				}).Return()
+			}
+
+			handler := handlers.NewAuthorize(
+				zap.NewNop(), credentials, mfaStatus, totp, recoveryCodes, attempts, clients, codes, auditLog,
+			)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, testCase.req())
+
+			assert.Equal(t, testCase.expStatus, recorder.Code)
+			assert.Equal(t, testCase.expLocation, recorder.Header().Get("Location"))
+			assert.Contains(t, recorder.Body.String(), testCase.expBody)
+			assert.Equal(t, "DENY", recorder.Header().Get("X-Frame-Options"))
+		})
+	}
+}
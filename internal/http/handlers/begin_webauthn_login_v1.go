@@ -0,0 +1,59 @@
+package handlers
+
+//go:generate mockery --name WebAuthnLoginStarter --output ./mocks --outpkg mocks --filename webauthn_login_starter.go --structname WebAuthnLoginStarter
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// NewBeginWebAuthnLoginV1 creates a new *BeginWebAuthnLoginV1 instance.
+func NewBeginWebAuthnLoginV1(
+	log *logger.Logger,
+	passkeys WebAuthnLoginStarter,
+) *BeginWebAuthnLoginV1 {
+	return &BeginWebAuthnLoginV1{
+		log:      log,
+		passkeys: passkeys,
+	}
+}
+
+type (
+	// BeginWebAuthnLoginV1 starts a passwordless login with a passkey.
+	BeginWebAuthnLoginV1 struct {
+		log      *logger.Logger
+		passkeys WebAuthnLoginStarter
+	}
+
+	// BeginWebAuthnLoginV1Request represents passkey login request.
+	BeginWebAuthnLoginV1Request struct{}
+
+	// BeginWebAuthnLoginV1Response holds the options to pass to navigator.credentials.get().
+	BeginWebAuthnLoginV1Response struct {
+		PublicKey webauthn.CredentialRequestOptions `json:"publicKey"`
+	}
+
+	// WebAuthnLoginStarter describes WebAuthnLoginStarter dependency.
+	WebAuthnLoginStarter interface {
+		BeginLogin(ctx context.Context) (webauthn.CredentialRequestOptions, error)
+	}
+)
+
+// Handle returns the options of a new authentication ceremony. The ceremony is completed by FinishWebAuthnLoginV1.
+func (h *BeginWebAuthnLoginV1) Handle(ctx context.Context, _ *BeginWebAuthnLoginV1Request) *httpx.Response {
+	options, err := h.passkeys.BeginLogin(ctx)
+	if err != nil {
+		h.log.Error("failed to begin webauthn login", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&BeginWebAuthnLoginV1Response{PublicKey: options}),
+	)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// DefBeginWebAuthnLoginV1Name is the name of the *BeginWebAuthnLoginV1 definition.
+const DefBeginWebAuthnLoginV1Name = "http.begin-webauthn-login-v1"
+
+func init() {
+	di.Add(
+		di.Def[*BeginWebAuthnLoginV1]{
+			Name: DefBeginWebAuthnLoginV1Name,
+			Build: func(ctn di.Container) (*BeginWebAuthnLoginV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var passkeys *webauthn.Passkeys
+				if err := ctn.Fill(webauthn.DefPasskeysName, &passkeys); err != nil {
+					return nil, err
+				}
+
+				return NewBeginWebAuthnLoginV1(
+					log,
+					passkeys,
+				), nil
+			},
+		},
+	)
+}
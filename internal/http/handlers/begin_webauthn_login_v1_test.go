@@ -0,0 +1,51 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+func TestBeginWebAuthnLoginV1_Handle(t *testing.T) {
+	options := webauthn.CredentialRequestOptions{Challenge: "challenge", RPID: "localhost"}
+
+	testCases := []struct {
+		name         string
+		onBeginLogin func() (webauthn.CredentialRequestOptions, error)
+		expResp      *httpx.Response
+	}{
+		{
+			name: "failed to begin login",
+			onBeginLogin: func() (webauthn.CredentialRequestOptions, error) {
+				return webauthn.CredentialRequestOptions{}, assert.AnError
+			},
+			expResp: httpx.InternalServerError,
+		},
+		{
+			name:         "positive case",
+			onBeginLogin: func() (webauthn.CredentialRequestOptions, error) { return options, nil },
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.BeginWebAuthnLoginV1Response{PublicKey: options}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			passkeys := mocks.NewWebAuthnLoginStarter(t)
+			passkeys.On("BeginLogin", t.Context()).Return(testCase.onBeginLogin())
+
+			handler := handlers.NewBeginWebAuthnLoginV1(zap.NewNop(), passkeys)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), &handlers.BeginWebAuthnLoginV1Request{}))
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package handlers
+
+//go:generate mockery --name WebAuthnRegistrationStarter --output ./mocks --outpkg mocks --filename webauthn_registration_starter.go --structname WebAuthnRegistrationStarter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// NewBeginWebAuthnRegistrationV1 creates a new *BeginWebAuthnRegistrationV1 instance.
+func NewBeginWebAuthnRegistrationV1(
+	log *logger.Logger,
+	userProvider UserByIdProvider,
+	passkeys WebAuthnRegistrationStarter,
+) *BeginWebAuthnRegistrationV1 {
+	return &BeginWebAuthnRegistrationV1{
+		log:          log,
+		userProvider: userProvider,
+		passkeys:     passkeys,
+	}
+}
+
+type (
+	// BeginWebAuthnRegistrationV1 starts the registration of a passkey for the authenticated user.
+	BeginWebAuthnRegistrationV1 struct {
+		log          *logger.Logger
+		userProvider UserByIdProvider
+		passkeys     WebAuthnRegistrationStarter
+	}
+
+	// BeginWebAuthnRegistrationV1Request represents passkey registration request.
+	BeginWebAuthnRegistrationV1Request struct{}
+
+	// BeginWebAuthnRegistrationV1Response holds the options to pass to navigator.credentials.create().
+	BeginWebAuthnRegistrationV1Response struct {
+		PublicKey webauthn.CredentialCreationOptions `json:"publicKey"`
+	}
+
+	// WebAuthnRegistrationStarter describes WebAuthnRegistrationStarter dependency.
+	WebAuthnRegistrationStarter interface {
+		BeginRegistration(ctx context.Context, userID string, userName string) (webauthn.CredentialCreationOptions, error)
+	}
+)
+
+// Handle returns the options of a new registration ceremony. The ceremony is completed by FinishWebAuthnRegistrationV1.
+func (h *BeginWebAuthnRegistrationV1) Handle(ctx context.Context, _ *BeginWebAuthnRegistrationV1Request) *httpx.Response {
+	var (
+		ok     bool
+		userID string
+	)
+	if userID, ok = httpx.GetUserID(ctx); !ok {
+		h.log.Warn("user id is missing")
+		return httpx.BadRequest
+	}
+
+	var (
+		err  error
+		user domain.User
+	)
+	if user, err = h.userProvider.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			h.log.Warn("user not found")
+			return httpx.NotFound
+		}
+
+		h.log.Error("failed to get user by id", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	var options webauthn.CredentialCreationOptions
+	if options, err = h.passkeys.BeginRegistration(ctx, userID, user.Email()); err != nil {
+		h.log.Error("failed to begin webauthn registration", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&BeginWebAuthnRegistrationV1Response{PublicKey: options}),
+	)
+}
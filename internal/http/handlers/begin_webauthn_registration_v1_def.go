@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/repository"
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// DefBeginWebAuthnRegistrationV1Name is the name of the *BeginWebAuthnRegistrationV1 definition.
+const DefBeginWebAuthnRegistrationV1Name = "http.begin-webauthn-registration-v1"
+
+func init() {
+	di.Add(
+		di.Def[*BeginWebAuthnRegistrationV1]{
+			Name: DefBeginWebAuthnRegistrationV1Name,
+			Build: func(ctn di.Container) (*BeginWebAuthnRegistrationV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				var passkeys *webauthn.Passkeys
+				if err := ctn.Fill(webauthn.DefPasskeysName, &passkeys); err != nil {
+					return nil, err
+				}
+
+				return NewBeginWebAuthnRegistrationV1(
+					log,
+					usersRep,
+					passkeys,
+				), nil
+			},
+		},
+	)
+}
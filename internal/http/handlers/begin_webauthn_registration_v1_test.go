@@ -0,0 +1,91 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+func TestBeginWebAuthnRegistrationV1_Handle(t *testing.T) {
+	options := webauthn.CredentialCreationOptions{Challenge: "challenge"}
+
+	testCases := []struct {
+		name                string
+		userID              string
+		onGetUserByID       func() (domain.User, error)
+		onBeginRegistration func() (webauthn.CredentialCreationOptions, error)
+		expResp             *httpx.Response
+	}{
+		{
+			name:    "user id is missing",
+			expResp: httpx.BadRequest,
+		},
+		{
+			name:          "user not found",
+			userID:        "user_id",
+			onGetUserByID: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			expResp:       httpx.NotFound,
+		},
+		{
+			name:          "failed to get user",
+			userID:        "user_id",
+			onGetUserByID: func() (domain.User, error) { return nil, assert.AnError },
+			expResp:       httpx.InternalServerError,
+		},
+		{
+			name:   "failed to begin registration",
+			userID: "user_id",
+			onGetUserByID: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onBeginRegistration: func() (webauthn.CredentialCreationOptions, error) {
+				return webauthn.CredentialCreationOptions{}, assert.AnError
+			},
+			expResp: httpx.InternalServerError,
+		},
+		{
+			name:   "positive case",
+			userID: "user_id",
+			onGetUserByID: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onBeginRegistration: func() (webauthn.CredentialCreationOptions, error) { return options, nil },
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.BeginWebAuthnRegistrationV1Response{PublicKey: options}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.userID != "" {
+				ctx = httpx.ContextWithUserID(ctx, testCase.userID)
+			}
+
+			userProvider := mocks.NewUserByIdProvider(t)
+			if testCase.onGetUserByID != nil {
+				userProvider.On("GetByID", ctx, testCase.userID).Return(testCase.onGetUserByID())
+			}
+
+			passkeys := mocks.NewWebAuthnRegistrationStarter(t)
+			if testCase.onBeginRegistration != nil {
+				passkeys.On("BeginRegistration", ctx, testCase.userID, "user@example.com").
+					Return(testCase.onBeginRegistration())
+			}
+
+			handler := handlers.NewBeginWebAuthnRegistrationV1(zap.NewNop(), userProvider, passkeys)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(ctx, &handlers.BeginWebAuthnRegistrationV1Request{}))
+		})
+	}
+}
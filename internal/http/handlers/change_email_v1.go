@@ -0,0 +1,134 @@
+package handlers
+
+//go:generate mockery --name EmailChangeRequester --output ./mocks --outpkg mocks --filename email_change_requester.go --structname EmailChangeRequester
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewChangeEmailV1 creates a new *ChangeEmailV1 instance.
+func NewChangeEmailV1(
+	log *logger.Logger,
+	userProvider UserByIdProvider,
+	passwordHasher PasswordHasher,
+	emailChange EmailChangeRequester,
+	auditLog AuditRecorder,
+) *ChangeEmailV1 {
+	return &ChangeEmailV1{
+		log:            log,
+		userProvider:   userProvider,
+		passwordHasher: passwordHasher,
+		emailChange:    emailChange,
+		auditLog:       auditLog,
+	}
+}
+
+type (
+	// ChangeEmailV1 starts moving a user to a new email address by sending a confirmation token to it.
+	// The email stays the same until the token is redeemed with ConfirmEmailChangeV1.
+	ChangeEmailV1 struct {
+		log            *logger.Logger
+		userProvider   UserByIdProvider
+		passwordHasher PasswordHasher
+		emailChange    EmailChangeRequester
+		auditLog       AuditRecorder
+	}
+
+	// ChangeEmailV1Request represents change email request.
+	ChangeEmailV1Request struct {
+		Password string `json:"password"`
+		NewEmail string `json:"new_email"`
+	}
+
+	// EmailChangeRequester describes EmailChangeRequester dependency.
+	EmailChangeRequester interface {
+		Request(ctx context.Context, user domain.User, newEmail string) error
+	}
+)
+
+// Handle verifies the current password and emails a confirmation token to the new address.
+func (h *ChangeEmailV1) Handle(ctx context.Context, req *ChangeEmailV1Request) *httpx.Response {
+	if req.Password == "" {
+		h.log.Warn("password is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "password is required")
+	}
+
+	if req.NewEmail == "" {
+		h.log.Warn("new email is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "new_email is required")
+	}
+
+	var (
+		ok     bool
+		userID string
+	)
+	if userID, ok = httpx.GetUserID(ctx); !ok {
+		h.log.Warn("user id is missing")
+		return httpx.BadRequest
+	}
+
+	var (
+		err  error
+		user domain.User
+	)
+	if user, err = h.userProvider.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			h.log.Warn("user not found")
+			return httpx.NotFound
+		}
+
+		h.log.Error("failed to get user by id", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if ok, err = h.passwordHasher.Verify(ctx, req.Password, user.HashedPassword()); err != nil {
+		if isHashingBusy(err) {
+			h.log.Warn("password hashing is saturated")
+			return hashingBusyResponse
+		}
+
+		h.log.Error("failed to compare passwords", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if !ok {
+		h.log.Warn("invalid password")
+		h.auditLog.Record(ctx, domain.AuditEvent{
+			Type:    domain.AuditEventEmailChange,
+			UserID:  userID,
+			Outcome: domain.AuditOutcomeFailure,
+			Reason:  auditReasonInvalidPassword,
+		})
+		return httpx.NewErrorResponse(http.StatusBadRequest, "invalid password")
+	}
+
+	if req.NewEmail == user.Email() {
+		h.log.Warn("new email is the current one")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "new_email is the current email")
+	}
+
+	if err = h.emailChange.Request(ctx, user, req.NewEmail); err != nil {
+		if errors.Is(err, domain.ErrEmailBusy) {
+			h.log.Warn("email is busy")
+			return httpx.NewErrorResponse(http.StatusBadRequest, "email is already in use")
+		}
+
+		if errors.Is(err, account.ErrEmailChangeUndoable) {
+			h.log.Warn("previous email change can still be undone")
+			return httpx.NewErrorResponse(http.StatusConflict, "email was changed recently, try again later")
+		}
+
+		h.log.Error("failed to request email change", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	return httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted))
+}
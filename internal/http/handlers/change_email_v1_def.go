@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/password"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+// DefChangeEmailV1Name is the name of the *ChangeEmailV1 definition.
+const DefChangeEmailV1Name = "http.change-email-v1"
+
+func init() {
+	di.Add(
+		di.Def[*ChangeEmailV1]{
+			Name: DefChangeEmailV1Name,
+			Build: func(ctn di.Container) (*ChangeEmailV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				var passwordHasher *password.Pool
+				if err := ctn.Fill(password.DefPoolName, &passwordHasher); err != nil {
+					return nil, err
+				}
+
+				var emailChange *account.EmailChange
+				if err := ctn.Fill(account.DefEmailChangeName, &emailChange); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewChangeEmailV1(
+					log,
+					usersRep,
+					passwordHasher,
+					emailChange,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,169 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestChangeEmailV1_Handle(t *testing.T) {
+	user := domain.NewUser("user_id", "user@example.com", "hashed_password")
+	validReq := &handlers.ChangeEmailV1Request{Password: "password", NewEmail: "new@example.com"}
+
+	testCases := []struct {
+		name             string
+		req              *handlers.ChangeEmailV1Request
+		userID           string
+		onGetUserByID    func() (domain.User, error)
+		onVerifyPassword func() (bool, error)
+		onRequest        func() error
+		expAuditEvent    *domain.AuditEvent
+		expResp          *httpx.Response
+	}{
+		{
+			name:    "password is missing",
+			req:     &handlers.ChangeEmailV1Request{NewEmail: "new@example.com"},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "password is required"),
+		},
+		{
+			name:    "new email is missing",
+			req:     &handlers.ChangeEmailV1Request{Password: "password"},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "new_email is required"),
+		},
+		{
+			name:    "user id is missing",
+			req:     validReq,
+			expResp: httpx.BadRequest,
+		},
+		{
+			name:          "user not found",
+			req:           validReq,
+			userID:        "user_id",
+			onGetUserByID: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			expResp:       httpx.NotFound,
+		},
+		{
+			name:          "failed to get user by id",
+			req:           validReq,
+			userID:        "user_id",
+			onGetUserByID: func() (domain.User, error) { return nil, assert.AnError },
+			expResp:       httpx.InternalServerError,
+		},
+		{
+			name:             "failed to compare passwords",
+			req:              validReq,
+			userID:           "user_id",
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return false, assert.AnError },
+			expResp:          httpx.InternalServerError,
+		},
+		{
+			name:             "password hashing is saturated",
+			req:              validReq,
+			userID:           "user_id",
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return false, password.ErrBusy },
+			expResp:          httpx.NewErrorResponse(http.StatusServiceUnavailable, "server is busy, try again later"),
+		},
+		{
+			name:             "invalid password",
+			req:              validReq,
+			userID:           "user_id",
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return false, nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventEmailChange,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_password",
+			},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "invalid password"),
+		},
+		{
+			name:             "new email is the current one",
+			req:              &handlers.ChangeEmailV1Request{Password: "password", NewEmail: "user@example.com"},
+			userID:           "user_id",
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			expResp:          httpx.NewErrorResponse(http.StatusBadRequest, "new_email is the current email"),
+		},
+		{
+			name:             "email is busy",
+			req:              validReq,
+			userID:           "user_id",
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onRequest:        func() error { return domain.ErrEmailBusy },
+			expResp:          httpx.NewErrorResponse(http.StatusBadRequest, "email is already in use"),
+		},
+		{
+			name:             "previous change can be undone",
+			req:              validReq,
+			userID:           "user_id",
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onRequest:        func() error { return account.ErrEmailChangeUndoable },
+			expResp:          httpx.NewErrorResponse(http.StatusConflict, "email was changed recently, try again later"),
+		},
+		{
+			name:             "failed to request email change",
+			req:              validReq,
+			userID:           "user_id",
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onRequest:        func() error { return assert.AnError },
+			expResp:          httpx.InternalServerError,
+		},
+		{
+			name:             "positive case",
+			req:              validReq,
+			userID:           "user_id",
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onRequest:        func() error { return nil },
+			expResp:          httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted)),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.userID != "" {
+				ctx = httpx.ContextWithUserID(ctx, testCase.userID)
+			}
+
+			userProvider := mocks.NewUserByIdProvider(t)
+			if testCase.onGetUserByID != nil {
+				userProvider.On("GetByID", ctx, testCase.userID).Return(testCase.onGetUserByID())
+			}
+
+			passwordHasher := mocks.NewPasswordHasher(t)
+			if testCase.onVerifyPassword != nil {
+				passwordHasher.On("Verify", ctx, testCase.req.Password, "hashed_password").Return(testCase.onVerifyPassword())
+			}
+
+			emailChange := mocks.NewEmailChangeRequester(t)
+			if testCase.onRequest != nil {
+				emailChange.On("Request", ctx, user, testCase.req.NewEmail).Return(testCase.onRequest())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", ctx, *testCase.expAuditEvent).Return()
+			}
+
+			handler := handlers.NewChangeEmailV1(zap.NewNop(), userProvider, passwordHasher, emailChange, auditLog)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(ctx, testCase.req))
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package handlers
+
+//go:generate mockery --name EmailChangeConfirmer --output ./mocks --outpkg mocks --filename email_change_confirmer.go --structname EmailChangeConfirmer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewConfirmEmailChangeV1 creates a new *ConfirmEmailChangeV1 instance.
+func NewConfirmEmailChangeV1(
+	log *logger.Logger,
+	emailChange EmailChangeConfirmer,
+	auditLog AuditRecorder,
+) *ConfirmEmailChangeV1 {
+	return &ConfirmEmailChangeV1{
+		log:         log,
+		emailChange: emailChange,
+		auditLog:    auditLog,
+	}
+}
+
+type (
+	// ConfirmEmailChangeV1 moves a user to the new email address with the token emailed by ChangeEmailV1
+	// and notifies the previous address.
+	ConfirmEmailChangeV1 struct {
+		log         *logger.Logger
+		emailChange EmailChangeConfirmer
+		auditLog    AuditRecorder
+	}
+
+	// ConfirmEmailChangeV1Request represents email change confirmation request.
+	ConfirmEmailChangeV1Request struct {
+		Token string `json:"token"`
+	}
+
+	// ConfirmEmailChangeV1Response represents successful email change confirmation response.
+	ConfirmEmailChangeV1Response struct {
+		UserID string `json:"user_id"`
+		Email  string `json:"email"`
+	}
+
+	// EmailChangeConfirmer describes EmailChangeConfirmer dependency.
+	EmailChangeConfirmer interface {
+		Confirm(ctx context.Context, token string) (account.EmailChanged, error)
+		SendUndo(ctx context.Context, changed account.EmailChanged) error
+	}
+)
+
+// Handle redeems the confirmation token, changes the email address and emails an undo token to the previous one.
+func (h *ConfirmEmailChangeV1) Handle(ctx context.Context, req *ConfirmEmailChangeV1Request) *httpx.Response {
+	if req.Token == "" {
+		h.log.Warn("token is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "token is required")
+	}
+
+	changed, err := h.emailChange.Confirm(ctx, req.Token)
+	if err != nil {
+		if errors.Is(err, account.ErrInvalidToken) {
+			h.log.Warn("invalid email change token")
+			return httpx.NewErrorResponse(http.StatusBadRequest, "invalid or expired token")
+		}
+
+		if errors.Is(err, domain.ErrEmailBusy) {
+			h.log.Warn("email is busy")
+			return httpx.NewErrorResponse(http.StatusBadRequest, "email is already in use")
+		}
+
+		h.log.Error("failed to confirm email change", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	h.auditLog.Record(ctx, domain.AuditEvent{
+		Type:    domain.AuditEventEmailChange,
+		UserID:  changed.UserID,
+		Outcome: domain.AuditOutcomeSuccess,
+	})
+
+	// the email has already changed, so a failure to send the notice does not fail the request
+	if err = h.emailChange.SendUndo(ctx, changed); err != nil {
+		h.log.Error("failed to send email change notice", logger.Error(err))
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&ConfirmEmailChangeV1Response{UserID: changed.UserID, Email: changed.NewEmail}),
+	)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/audit"
+)
+
+// DefConfirmEmailChangeV1Name is the name of the *ConfirmEmailChangeV1 definition.
+const DefConfirmEmailChangeV1Name = "http.confirm-email-change-v1"
+
+func init() {
+	di.Add(
+		di.Def[*ConfirmEmailChangeV1]{
+			Name: DefConfirmEmailChangeV1Name,
+			Build: func(ctn di.Container) (*ConfirmEmailChangeV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var emailChange *account.EmailChange
+				if err := ctn.Fill(account.DefEmailChangeName, &emailChange); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewConfirmEmailChangeV1(
+					log,
+					emailChange,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,102 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+)
+
+func TestConfirmEmailChangeV1_Handle(t *testing.T) {
+	changed := account.EmailChanged{UserID: "user_id", OldEmail: "old@example.com", NewEmail: "new@example.com"}
+	okResp := httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&handlers.ConfirmEmailChangeV1Response{UserID: "user_id", Email: "new@example.com"}),
+	)
+
+	testCases := []struct {
+		name          string
+		req           *handlers.ConfirmEmailChangeV1Request
+		onConfirm     func() (account.EmailChanged, error)
+		onSendUndo    func() error
+		expAuditEvent *domain.AuditEvent
+		expResp       *httpx.Response
+	}{
+		{
+			name:    "token is missing",
+			req:     &handlers.ConfirmEmailChangeV1Request{},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "token is required"),
+		},
+		{
+			name:      "invalid token",
+			req:       &handlers.ConfirmEmailChangeV1Request{Token: "token"},
+			onConfirm: func() (account.EmailChanged, error) { return account.EmailChanged{}, account.ErrInvalidToken },
+			expResp:   httpx.NewErrorResponse(http.StatusBadRequest, "invalid or expired token"),
+		},
+		{
+			name:      "email is busy",
+			req:       &handlers.ConfirmEmailChangeV1Request{Token: "token"},
+			onConfirm: func() (account.EmailChanged, error) { return account.EmailChanged{}, domain.ErrEmailBusy },
+			expResp:   httpx.NewErrorResponse(http.StatusBadRequest, "email is already in use"),
+		},
+		{
+			name:      "failed to confirm email change",
+			req:       &handlers.ConfirmEmailChangeV1Request{Token: "token"},
+			onConfirm: func() (account.EmailChanged, error) { return account.EmailChanged{}, assert.AnError },
+			expResp:   httpx.InternalServerError,
+		},
+		{
+			name:       "failed to send undo notice",
+			req:        &handlers.ConfirmEmailChangeV1Request{Token: "token"},
+			onConfirm:  func() (account.EmailChanged, error) { return changed, nil },
+			onSendUndo: func() error { return assert.AnError },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventEmailChange,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+			},
+			expResp: okResp,
+		},
+		{
+			name:       "positive case",
+			req:        &handlers.ConfirmEmailChangeV1Request{Token: "token"},
+			onConfirm:  func() (account.EmailChanged, error) { return changed, nil },
+			onSendUndo: func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventEmailChange,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+			},
+			expResp: okResp,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			emailChange := mocks.NewEmailChangeConfirmer(t)
+			if testCase.onConfirm != nil {
+				emailChange.On("Confirm", t.Context(), testCase.req.Token).Return(testCase.onConfirm())
+			}
+
+			if testCase.onSendUndo != nil {
+				emailChange.On("SendUndo", t.Context(), changed).Return(testCase.onSendUndo())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", t.Context(), *testCase.expAuditEvent).Return()
+			}
+
+			handler := handlers.NewConfirmEmailChangeV1(zap.NewNop(), emailChange, auditLog)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), testCase.req))
+		})
+	}
+}
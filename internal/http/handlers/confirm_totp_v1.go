@@ -0,0 +1,122 @@
+package handlers
+
+//go:generate mockery --name TOTPConfirmer --output ./mocks --outpkg mocks --filename totp_confirmer.go --structname TOTPConfirmer
+//go:generate mockery --name RecoveryCodesGenerator --output ./mocks --outpkg mocks --filename recovery_codes_generator.go --structname RecoveryCodesGenerator
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+// NewConfirmTOTPV1 creates a new *ConfirmTOTPV1 instance.
+func NewConfirmTOTPV1(
+	log *logger.Logger,
+	totp TOTPConfirmer,
+	recoveryCodes RecoveryCodesGenerator,
+	auditLog AuditRecorder,
+) *ConfirmTOTPV1 {
+	return &ConfirmTOTPV1{
+		log:           log,
+		totp:          totp,
+		recoveryCodes: recoveryCodes,
+		auditLog:      auditLog,
+	}
+}
+
+type (
+	// ConfirmTOTPV1 enables two-factor authentication once the user proves the enrolled secret works.
+	ConfirmTOTPV1 struct {
+		log           *logger.Logger
+		totp          TOTPConfirmer
+		recoveryCodes RecoveryCodesGenerator
+		auditLog      AuditRecorder
+	}
+
+	// ConfirmTOTPV1Request represents TOTP confirmation request.
+	ConfirmTOTPV1Request struct {
+		Code string `json:"code"`
+	}
+
+	// ConfirmTOTPV1Response holds the recovery codes generated when two-factor authentication is enabled.
+	// They are shown only once.
+	ConfirmTOTPV1Response struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	// TOTPConfirmer describes TOTPConfirmer dependency.
+	TOTPConfirmer interface {
+		Confirm(ctx context.Context, userID string, code string) error
+	}
+
+	// RecoveryCodesGenerator describes RecoveryCodesGenerator dependency.
+	RecoveryCodesGenerator interface {
+		Generate(ctx context.Context, userID string) ([]string, error)
+	}
+)
+
+// Handle checks the first code generated by the authenticator app, enables TOTP for the user
+// and generates recovery codes.
+func (h *ConfirmTOTPV1) Handle(ctx context.Context, req *ConfirmTOTPV1Request) *httpx.Response {
+	if req.Code == "" {
+		h.log.Warn("code is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "code is required")
+	}
+
+	var (
+		ok     bool
+		userID string
+	)
+	if userID, ok = httpx.GetUserID(ctx); !ok {
+		h.log.Warn("user id is missing")
+		return httpx.BadRequest
+	}
+
+	if err := h.totp.Confirm(ctx, userID, req.Code); err != nil {
+		switch {
+		case errors.Is(err, mfa.ErrInvalidCode):
+			h.log.Warn("invalid totp code")
+			h.auditLog.Record(ctx, domain.AuditEvent{
+				Type:    domain.AuditEventMFAEnabled,
+				UserID:  userID,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  auditReasonInvalidCode,
+			})
+			return httpx.NewErrorResponse(http.StatusBadRequest, "invalid code")
+		case errors.Is(err, mfa.ErrNotEnrolled):
+			h.log.Warn("totp is not enrolled")
+			return httpx.NewErrorResponse(http.StatusConflict, "totp enrollment is not started")
+		case errors.Is(err, mfa.ErrAlreadyEnabled):
+			h.log.Warn("totp is already enabled")
+			return httpx.NewErrorResponse(http.StatusConflict, "totp is already enabled")
+		}
+
+		h.log.Error("failed to confirm totp", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	h.auditLog.Record(ctx, domain.AuditEvent{
+		Type:    domain.AuditEventMFAEnabled,
+		UserID:  userID,
+		Outcome: domain.AuditOutcomeSuccess,
+	})
+
+	recoveryCodes, err := h.recoveryCodes.Generate(ctx, userID)
+	if err != nil {
+		h.log.Error("failed to generate recovery codes", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&ConfirmTOTPV1Response{
+			RecoveryCodes: recoveryCodes,
+		}),
+	)
+}
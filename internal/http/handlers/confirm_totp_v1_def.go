@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+// DefConfirmTOTPV1Name is the name of the *ConfirmTOTPV1 definition.
+const DefConfirmTOTPV1Name = "http.confirm-totp-v1"
+
+func init() {
+	di.Add(
+		di.Def[*ConfirmTOTPV1]{
+			Name: DefConfirmTOTPV1Name,
+			Build: func(ctn di.Container) (*ConfirmTOTPV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var totp *mfa.TOTP
+				if err := ctn.Fill(mfa.DefTOTPName, &totp); err != nil {
+					return nil, err
+				}
+
+				var recoveryCodes *mfa.RecoveryCodes
+				if err := ctn.Fill(mfa.DefRecoveryCodesName, &recoveryCodes); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewConfirmTOTPV1(
+					log,
+					totp,
+					recoveryCodes,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
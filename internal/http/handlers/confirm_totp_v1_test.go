@@ -0,0 +1,131 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+func TestConfirmTOTPV1_Handle(t *testing.T) {
+	testCases := []struct {
+		name       string
+		code       string
+		userID     string
+		onConfirm  func() error
+		onGenerate func() ([]string, error)
+		expEvent   *domain.AuditEvent
+		expResp    *httpx.Response
+	}{
+		{
+			name:    "code is missing",
+			userID:  "user_id",
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "code is required"),
+		},
+		{
+			name:    "user id is missing",
+			code:    "123456",
+			expResp: httpx.BadRequest,
+		},
+		{
+			name:      "invalid code",
+			code:      "123456",
+			userID:    "user_id",
+			onConfirm: func() error { return mfa.ErrInvalidCode },
+			expEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventMFAEnabled,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_code",
+			},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "invalid code"),
+		},
+		{
+			name:      "totp is not enrolled",
+			code:      "123456",
+			userID:    "user_id",
+			onConfirm: func() error { return mfa.ErrNotEnrolled },
+			expResp:   httpx.NewErrorResponse(http.StatusConflict, "totp enrollment is not started"),
+		},
+		{
+			name:      "totp is already enabled",
+			code:      "123456",
+			userID:    "user_id",
+			onConfirm: func() error { return mfa.ErrAlreadyEnabled },
+			expResp:   httpx.NewErrorResponse(http.StatusConflict, "totp is already enabled"),
+		},
+		{
+			name:      "failed to confirm",
+			code:      "123456",
+			userID:    "user_id",
+			onConfirm: func() error { return assert.AnError },
+			expResp:   httpx.InternalServerError,
+		},
+		{
+			name:      "failed to generate recovery codes",
+			code:      "123456",
+			userID:    "user_id",
+			onConfirm: func() error { return nil },
+			expEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventMFAEnabled,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+			},
+			onGenerate: func() ([]string, error) { return nil, assert.AnError },
+			expResp:    httpx.InternalServerError,
+		},
+		{
+			name:      "positive case",
+			code:      "123456",
+			userID:    "user_id",
+			onConfirm: func() error { return nil },
+			expEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventMFAEnabled,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+			},
+			onGenerate: func() ([]string, error) { return []string{"AAAA-BBBB-CCCC-DDDD"}, nil },
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.ConfirmTOTPV1Response{
+					RecoveryCodes: []string{"AAAA-BBBB-CCCC-DDDD"},
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.userID != "" {
+				ctx = httpx.ContextWithUserID(ctx, testCase.userID)
+			}
+
+			totp := mocks.NewTOTPConfirmer(t)
+			if testCase.onConfirm != nil {
+				totp.On("Confirm", ctx, testCase.userID, testCase.code).Return(testCase.onConfirm())
+			}
+
+			recoveryCodes := mocks.NewRecoveryCodesGenerator(t)
+			if testCase.onGenerate != nil {
+				recoveryCodes.On("Generate", ctx, testCase.userID).Return(testCase.onGenerate())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expEvent != nil {
+				auditLog.On("Record", ctx, *testCase.expEvent).Return()
+			}
+
+			handler := handlers.NewConfirmTOTPV1(zap.NewNop(), totp, recoveryCodes, auditLog)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(ctx, &handlers.ConfirmTOTPV1Request{Code: testCase.code}))
+		})
+	}
+}
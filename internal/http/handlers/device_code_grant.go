@@ -0,0 +1,142 @@
+package handlers
+
+//go:generate mockery --name DeviceGrantPoller --output ./mocks --outpkg mocks --filename device_grant_poller.go --structname DeviceGrantPoller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// grantTypeDeviceCode is the grant type of the device authorization grant (RFC 8628, section 3.4).
+const grantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+// OAuth error codes defined by RFC 8628, section 3.5.
+const (
+	oauthErrAuthorizationPending = "authorization_pending"
+	oauthErrSlowDown             = "slow_down"
+	oauthErrAccessDenied         = "access_denied"
+	oauthErrExpiredToken         = "expired_token"
+)
+
+// NewDeviceCodeGrant creates a new *DeviceCodeGrant instance.
+func NewDeviceCodeGrant(
+	log *logger.Logger,
+	issuer TokenIssuer,
+	jwtStorage JwtStorage,
+	devices DeviceGrantPoller,
+	auditLog AuditRecorder,
+) *DeviceCodeGrant {
+	return &DeviceCodeGrant{
+		log:        log,
+		issuer:     issuer,
+		jwtStorage: jwtStorage,
+		devices:    devices,
+		auditLog:   auditLog,
+	}
+}
+
+type (
+	// DeviceCodeGrant exchanges device codes issued by DeviceCodeV1 for access and refresh tokens
+	// once the user has approved the device.
+	DeviceCodeGrant struct {
+		log        *logger.Logger
+		issuer     TokenIssuer
+		jwtStorage JwtStorage
+		devices    DeviceGrantPoller
+		auditLog   AuditRecorder
+	}
+
+	// DeviceGrantPoller describes DeviceGrantPoller dependency.
+	DeviceGrantPoller interface {
+		Poll(ctx context.Context, deviceCode string) (oauth.DeviceGrant, error)
+	}
+)
+
+// GrantType implements TokenGrant.
+func (g *DeviceCodeGrant) GrantType() string {
+	return grantTypeDeviceCode
+}
+
+// Grant polls the device grant and issues access and refresh tokens for the user who approved it.
+// Until then the device receives authorization_pending, or slow_down if it polls too often.
+func (g *DeviceCodeGrant) Grant(ctx context.Context, req *TokenV1Request) *httpx.Response {
+	if req.DeviceCode == "" {
+		g.log.Warn("device_code is missing")
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "device_code is required")
+	}
+
+	if req.ClientID == "" {
+		g.log.Warn("client_id is missing")
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "client_id is required")
+	}
+
+	var (
+		err   error
+		grant oauth.DeviceGrant
+	)
+	if grant, err = g.devices.Poll(ctx, req.DeviceCode); err != nil {
+		switch {
+		case errors.Is(err, oauth.ErrAuthorizationPending):
+			return newOAuthErrorResponse(http.StatusBadRequest, oauthErrAuthorizationPending, "")
+		case errors.Is(err, oauth.ErrSlowDown):
+			return newOAuthErrorResponse(http.StatusBadRequest, oauthErrSlowDown, "")
+		case errors.Is(err, oauth.ErrAccessDenied):
+			g.log.Warn("device authorization was denied")
+			return newOAuthErrorResponse(http.StatusBadRequest, oauthErrAccessDenied, "")
+		case errors.Is(err, oauth.ErrExpiredDeviceCode):
+			g.log.Warn("device code is expired")
+			return newOAuthErrorResponse(http.StatusBadRequest, oauthErrExpiredToken, "")
+		}
+
+		g.log.Error("failed to poll device grant", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if grant.ClientID != req.ClientID {
+		g.log.Warn("device code was issued to another client")
+
+		event := grantEvent(grantTypeDeviceCode, req.ClientID, grant.UserID, domain.AuditOutcomeFailure)
+		event.Reason = auditReasonInvalidGrant
+		g.auditLog.Record(ctx, event)
+
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidGrant, "invalid device code")
+	}
+
+	var accessToken string
+	if accessToken, err = g.issuer.IssueAccessToken(grant.UserID); err != nil {
+		g.log.Error("failed to issue access token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	var refreshToken string
+	if refreshToken, err = g.issuer.IssueRefreshToken(grant.UserID); err != nil {
+		g.log.Error("failed to issue refresh token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if err = g.jwtStorage.Save(ctx, grant.UserID, refreshToken); err != nil {
+		g.log.Error("failed to save refresh token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	event := grantEvent(grantTypeDeviceCode, req.ClientID, grant.UserID, domain.AuditOutcomeSuccess)
+	event.SessionID = sessionID(refreshToken)
+	g.auditLog.Record(ctx, event)
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&TokenV1Response{
+			AccessToken:  accessToken,
+			TokenType:    tokenTypeBearer,
+			ExpiresIn:    int64(g.issuer.AccessTokenTTL().Seconds()),
+			RefreshToken: refreshToken,
+		}),
+	)
+}
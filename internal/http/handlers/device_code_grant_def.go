@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// DefDeviceCodeGrantName is the name of the *DeviceCodeGrant definition.
+const DefDeviceCodeGrantName = "http.device-code-grant"
+
+func init() {
+	di.Add(
+		di.Def[*DeviceCodeGrant]{
+			Name: DefDeviceCodeGrantName,
+			Build: func(ctn di.Container) (*DeviceCodeGrant, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var issuer *jwt.Issuer
+				if err := ctn.Fill(jwt.DefIssuerName, &issuer); err != nil {
+					return nil, err
+				}
+
+				var storage *jwt.Storage
+				if err := ctn.Fill(jwt.DefStorageName, &storage); err != nil {
+					return nil, err
+				}
+
+				var devices *oauth.Devices
+				if err := ctn.Fill(oauth.DefDevicesName, &devices); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewDeviceCodeGrant(
+					log,
+					issuer,
+					storage,
+					devices,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,225 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+func TestDeviceCodeGrant_Grant(t *testing.T) {
+	generateRequest := func() *handlers.TokenV1Request {
+		return &handlers.TokenV1Request{
+			GrantType:  "urn:ietf:params:oauth:grant-type:device_code",
+			ClientID:   "client_id",
+			DeviceCode: "device_code",
+		}
+	}
+
+	generateGrant := func() (oauth.DeviceGrant, error) {
+		return oauth.DeviceGrant{
+			ClientID: "client_id",
+			Status:   oauth.DeviceGrantApproved,
+			UserID:   "user_id",
+		}, nil
+	}
+
+	oauthError := func(code string, description string) *httpx.Response {
+		return httpx.NewJsonResponse(
+			httpx.WithStatus(http.StatusBadRequest),
+			httpx.WithBody(&handlers.OAuthErrorResponse{Error: code, ErrorDescription: description}),
+		)
+	}
+
+	pollError := func(err error) func() (oauth.DeviceGrant, error) {
+		return func() (oauth.DeviceGrant, error) { return oauth.DeviceGrant{}, err }
+	}
+
+	testCases := []struct {
+		name                string
+		req                 func() *handlers.TokenV1Request
+		onPoll              func() (oauth.DeviceGrant, error)
+		onIssueAccessToken  func() (string, error)
+		onIssueRefreshToken func() (string, error)
+		onSaveRefreshToken  func() error
+		expEvent            *domain.AuditEvent
+		expResp             *httpx.Response
+	}{
+		{
+			name: "device code is missing",
+			req: func() *handlers.TokenV1Request {
+				req := generateRequest()
+				req.DeviceCode = ""
+				return req
+			},
+			expResp: oauthError("invalid_request", "device_code is required"),
+		},
+		{
+			name: "client id is missing",
+			req: func() *handlers.TokenV1Request {
+				req := generateRequest()
+				req.ClientID = ""
+				return req
+			},
+			expResp: oauthError("invalid_request", "client_id is required"),
+		},
+		{
+			name:    "authorization pending",
+			req:     generateRequest,
+			onPoll:  pollError(oauth.ErrAuthorizationPending),
+			expResp: oauthError("authorization_pending", ""),
+		},
+		{
+			name:    "slow down",
+			req:     generateRequest,
+			onPoll:  pollError(oauth.ErrSlowDown),
+			expResp: oauthError("slow_down", ""),
+		},
+		{
+			name:    "access denied",
+			req:     generateRequest,
+			onPoll:  pollError(oauth.ErrAccessDenied),
+			expResp: oauthError("access_denied", ""),
+		},
+		{
+			name:    "expired token",
+			req:     generateRequest,
+			onPoll:  pollError(oauth.ErrExpiredDeviceCode),
+			expResp: oauthError("expired_token", ""),
+		},
+		{
+			name:    "failed to poll",
+			req:     generateRequest,
+			onPoll:  pollError(assert.AnError),
+			expResp: httpx.InternalServerError,
+		},
+		{
+			name: "device code issued to another client",
+			req: func() *handlers.TokenV1Request {
+				req := generateRequest()
+				req.ClientID = "another_client"
+				return req
+			},
+			onPoll: generateGrant,
+			expEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventTokenGrant,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_grant",
+				Details: map[string]string{
+					"grant_type": "urn:ietf:params:oauth:grant-type:device_code",
+					"client_id":  "another_client",
+				},
+			},
+			expResp: oauthError("invalid_grant", "invalid device code"),
+		},
+		{
+			name:               "failed to issue access token",
+			req:                generateRequest,
+			onPoll:             generateGrant,
+			onIssueAccessToken: func() (string, error) { return "", assert.AnError },
+			expResp:            httpx.InternalServerError,
+		},
+		{
+			name:                "failed to issue refresh token",
+			req:                 generateRequest,
+			onPoll:              generateGrant,
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "", assert.AnError },
+			expResp:             httpx.InternalServerError,
+		},
+		{
+			name:                "failed to save refresh token",
+			req:                 generateRequest,
+			onPoll:              generateGrant,
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return assert.AnError },
+			expResp:             httpx.InternalServerError,
+		},
+		{
+			name:                "positive case",
+			req:                 generateRequest,
+			onPoll:              generateGrant,
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return nil },
+			expEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventTokenGrant,
+				UserID:    "user_id",
+				SessionID: "6c8a7d4aa21708a4",
+				Outcome:   domain.AuditOutcomeSuccess,
+				Details: map[string]string{
+					"grant_type": "urn:ietf:params:oauth:grant-type:device_code",
+					"client_id":  "client_id",
+				},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.TokenV1Response{
+					AccessToken:  "access_token",
+					TokenType:    "Bearer",
+					ExpiresIn:    300,
+					RefreshToken: "refresh_token",
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := testCase.req()
+
+			devices := mocks.NewDeviceGrantPoller(t)
+			if testCase.onPoll != nil {
+				devices.On("Poll", t.Context(), req.DeviceCode).Return(testCase.onPoll())
+			}
+
+			issuer := mocks.NewTokenIssuer(t)
+			if testCase.onIssueAccessToken != nil {
+				issuer.On("IssueAccessToken", "user_id").Return(testCase.onIssueAccessToken())
+			}
+
+			var refreshToken string
+			if testCase.onIssueRefreshToken != nil {
+				var err error
+				refreshToken, err = testCase.onIssueRefreshToken()
+
+				issuer.On("IssueRefreshToken", "user_id").Return(refreshToken, err)
+			}
+
+			jwtStorage := mocks.NewJwtStorage(t)
+			if testCase.onSaveRefreshToken != nil {
+				jwtStorage.On("Save", t.Context(), "user_id", refreshToken).Return(testCase.onSaveRefreshToken())
+			}
+
+			if testCase.expResp.Status() == http.StatusOK {
+				issuer.On("AccessTokenTTL").Return(5 * time.Minute)
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expEvent != nil {
+				auditLog.On("Record", t.Context(), *testCase.expEvent).Return()
+			}
+
+			grant := handlers.NewDeviceCodeGrant(
+				zap.NewNop(),
+				issuer,
+				jwtStorage,
+				devices,
+				auditLog,
+			)
+
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:device_code", grant.GrantType())
+			assert.Equal(t, testCase.expResp, grant.Grant(t.Context(), req))
+		})
+	}
+}
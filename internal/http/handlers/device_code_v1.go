@@ -0,0 +1,101 @@
+package handlers
+
+//go:generate mockery --name DeviceAuthorizationStarter --output ./mocks --outpkg mocks --filename device_authorization_starter.go --structname DeviceAuthorizationStarter
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// NewDeviceCodeV1 creates a new *DeviceCodeV1 instance.
+func NewDeviceCodeV1(
+	log *logger.Logger,
+	clients OAuthClients,
+	devices DeviceAuthorizationStarter,
+	verificationURI string,
+) *DeviceCodeV1 {
+	return &DeviceCodeV1{
+		log:             log,
+		clients:         clients,
+		devices:         devices,
+		verificationURI: verificationURI,
+	}
+}
+
+type (
+	// DeviceCodeV1 is the OAuth 2.0 device authorization endpoint (RFC 8628, section 3.1).
+	DeviceCodeV1 struct {
+		log             *logger.Logger
+		clients         OAuthClients
+		devices         DeviceAuthorizationStarter
+		verificationURI string
+	}
+
+	// DeviceAuthorizationStarter describes DeviceAuthorizationStarter dependency.
+	DeviceAuthorizationStarter interface {
+		Start(ctx context.Context, clientID string) (oauth.DeviceAuthorization, error)
+	}
+
+	// DeviceCodeV1Request represents device authorization request. Scopes are not supported, tokens issued
+	// to devices carry the full access of the user.
+	DeviceCodeV1Request struct {
+		ClientID string `json:"client_id"`
+		Scope    string `json:"scope"`
+	}
+
+	// DeviceCodeV1Response represents device authorization response.
+	DeviceCodeV1Response struct {
+		DeviceCode              string `json:"device_code"`
+		UserCode                string `json:"user_code"`
+		VerificationURI         string `json:"verification_uri"`
+		VerificationURIComplete string `json:"verification_uri_complete"`
+		ExpiresIn               int64  `json:"expires_in"`
+		Interval                int64  `json:"interval"`
+	}
+)
+
+// Handle starts a new device grant for the client. The device shows the user code and verification URI
+// to the user and polls the token endpoint with the device code until the user approves it.
+func (h *DeviceCodeV1) Handle(ctx context.Context, req *DeviceCodeV1Request) *httpx.Response {
+	if req.ClientID == "" {
+		h.log.Warn("client_id is missing")
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "client_id is required")
+	}
+
+	if !h.clients.Exists(req.ClientID) {
+		h.log.Warn("unknown client", logger.String("client_id", req.ClientID))
+		return newOAuthErrorResponse(http.StatusUnauthorized, oauthErrInvalidClient, "unknown client")
+	}
+
+	if req.Scope != "" {
+		h.log.Warn("scope is not supported", logger.String("client_id", req.ClientID))
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidScope, "scope is not supported")
+	}
+
+	var (
+		err  error
+		auth oauth.DeviceAuthorization
+	)
+	if auth, err = h.devices.Start(ctx, req.ClientID); err != nil {
+		h.log.Error("failed to start device authorization", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&DeviceCodeV1Response{
+			DeviceCode:              auth.DeviceCode,
+			UserCode:                auth.UserCode,
+			VerificationURI:         h.verificationURI,
+			VerificationURIComplete: h.verificationURI + "?" + url.Values{"user_code": {auth.UserCode}}.Encode(),
+			ExpiresIn:               int64(auth.ExpiresIn.Seconds()),
+			Interval:                int64(auth.Interval.Seconds()),
+		}),
+	)
+}
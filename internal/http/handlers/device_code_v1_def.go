@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+const (
+	// DefDeviceCodeV1Name is the name of the *DeviceCodeV1 definition.
+	DefDeviceCodeV1Name = "http.device-code-v1"
+
+	configKeyDeviceVerificationURI = "auth.oauth.deviceVerificationURI"
+)
+
+func init() {
+	di.Add(
+		di.Def[*DeviceCodeV1]{
+			Name: DefDeviceCodeV1Name,
+			Build: func(ctn di.Container) (*DeviceCodeV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var verificationURI string
+				if verificationURI = cfg.GetString(configKeyDeviceVerificationURI); verificationURI == "" {
+					return nil, config.NewErrMissingKey(configKeyDeviceVerificationURI)
+				}
+
+				var clients *oauth.Clients
+				if err := ctn.Fill(oauth.DefClientsName, &clients); err != nil {
+					return nil, err
+				}
+
+				var devices *oauth.Devices
+				if err := ctn.Fill(oauth.DefDevicesName, &devices); err != nil {
+					return nil, err
+				}
+
+				return NewDeviceCodeV1(
+					log,
+					clients,
+					devices,
+					verificationURI,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,102 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+func TestDeviceCodeV1_Handle(t *testing.T) {
+	const verificationURI = "https://auth.example.com/oauth/device"
+
+	testCases := []struct {
+		name         string
+		req          *handlers.DeviceCodeV1Request
+		clientExists bool
+		onStart      func() (oauth.DeviceAuthorization, error)
+		expResp      *httpx.Response
+	}{
+		{
+			name: "client id is missing",
+			req:  &handlers.DeviceCodeV1Request{},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusBadRequest),
+				httpx.WithBody(&handlers.OAuthErrorResponse{Error: "invalid_request", ErrorDescription: "client_id is required"}),
+			),
+		},
+		{
+			name:         "unknown client",
+			req:          &handlers.DeviceCodeV1Request{ClientID: "client_id"},
+			clientExists: false,
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusUnauthorized),
+				httpx.WithBody(&handlers.OAuthErrorResponse{Error: "invalid_client", ErrorDescription: "unknown client"}),
+			),
+		},
+		{
+			name:         "scope is not supported",
+			req:          &handlers.DeviceCodeV1Request{ClientID: "client_id", Scope: "profile"},
+			clientExists: true,
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusBadRequest),
+				httpx.WithBody(&handlers.OAuthErrorResponse{Error: "invalid_scope", ErrorDescription: "scope is not supported"}),
+			),
+		},
+		{
+			name:         "failed to start device authorization",
+			req:          &handlers.DeviceCodeV1Request{ClientID: "client_id"},
+			clientExists: true,
+			onStart:      func() (oauth.DeviceAuthorization, error) { return oauth.DeviceAuthorization{}, assert.AnError },
+			expResp:      httpx.InternalServerError,
+		},
+		{
+			name:         "positive case",
+			req:          &handlers.DeviceCodeV1Request{ClientID: "client_id"},
+			clientExists: true,
+			onStart: func() (oauth.DeviceAuthorization, error) {
+				return oauth.DeviceAuthorization{
+					DeviceCode: "device_code",
+					UserCode:   "WDJB-MJHT",
+					ExpiresIn:  10 * time.Minute,
+					Interval:   5 * time.Second,
+				}, nil
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.DeviceCodeV1Response{
+					DeviceCode:              "device_code",
+					UserCode:                "WDJB-MJHT",
+					VerificationURI:         verificationURI,
+					VerificationURIComplete: verificationURI + "?user_code=WDJB-MJHT",
+					ExpiresIn:               600,
+					Interval:                5,
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			clients := mocks.NewOAuthClients(t)
+			if testCase.req.ClientID != "" {
+				clients.On("Exists", testCase.req.ClientID).Return(testCase.clientExists)
+			}
+
+			devices := mocks.NewDeviceAuthorizationStarter(t)
+			if testCase.onStart != nil {
+				devices.On("Start", t.Context(), testCase.req.ClientID).Return(testCase.onStart())
+			}
+
+			handler := handlers.NewDeviceCodeV1(zap.NewNop(), clients, devices, verificationURI)
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), testCase.req))
+		})
+	}
+}
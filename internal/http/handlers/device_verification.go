@@ -0,0 +1,182 @@
+package handlers
+
+//go:generate mockery --name DeviceGrantResolver --output ./mocks --outpkg mocks --filename device_grant_resolver.go --structname DeviceGrantResolver
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// Actions the user can take on the device verification page.
+const (
+	deviceActionApprove = "approve"
+	deviceActionDeny    = "deny"
+)
+
+//go:embed templates/device.html
+var devicePage string
+
+// NewDeviceVerification creates a new *DeviceVerification instance.
+func NewDeviceVerification(
+	log *logger.Logger,
+	credentials CredentialsVerifier,
+	mfaStatus MFAStatusProvider,
+	totp TOTPVerifier,
+	recoveryCodes RecoveryCodeConsumer,
+	attempts MFAAttempts,
+	devices DeviceGrantResolver,
+	auditLog AuditRecorder,
+) *DeviceVerification {
+	return &DeviceVerification{
+		log:         log,
+		credentials: credentials,
+		secondFactor: &secondFactor{
+			log:           log,
+			mfaStatus:     mfaStatus,
+			totp:          totp,
+			recoveryCodes: recoveryCodes,
+			attempts:      attempts,
+			auditLog:      auditLog,
+		},
+		devices:  devices,
+		page:     template.Must(template.New("device").Parse(devicePage)),
+		auditLog: auditLog,
+	}
+}
+
+type (
+	// DeviceVerification is the verification page of the device authorization grant. The user enters
+	// the user code shown on the device, signs in and approves or denies the device.
+	DeviceVerification struct {
+		log          *logger.Logger
+		credentials  CredentialsVerifier
+		secondFactor *secondFactor
+		devices      DeviceGrantResolver
+		page         *template.Template
+		auditLog     AuditRecorder
+	}
+
+	// DeviceGrantResolver describes DeviceGrantResolver dependency.
+	DeviceGrantResolver interface {
+		Approve(ctx context.Context, userCode string, userID string) error
+		Deny(ctx context.Context, userCode string) error
+	}
+
+	// devicePageData holds the state of the device verification page.
+	devicePageData struct {
+		UserCode string
+		Email    string
+		Error    string
+		Message  string
+	}
+)
+
+// ServeHTTP renders the verification page on GET and resolves the device grant on POST.
+func (h *DeviceVerification) ServeHTTP(writer http.ResponseWriter, req *http.Request) {
+	setPageHeaders(writer)
+
+	if err := req.ParseForm(); err != nil {
+		h.log.Warn("failed to parse device verification request", logger.Error(err))
+		h.render(writer, http.StatusBadRequest, &devicePageData{Error: "malformed request"})
+		return
+	}
+
+	data := &devicePageData{
+		UserCode: req.Form.Get("user_code"),
+	}
+
+	if req.Method != http.MethodPost {
+		h.render(writer, http.StatusOK, data)
+		return
+	}
+
+	data.Email = req.PostForm.Get("email")
+	password := req.PostForm.Get("password")
+	action := req.PostForm.Get("action")
+
+	if data.UserCode == "" || data.Email == "" || password == "" {
+		h.log.Warn("user code, email or password is missing")
+		data.Error = "code, email and password are required"
+		h.render(writer, http.StatusBadRequest, data)
+		return
+	}
+
+	if action != deviceActionApprove && action != deviceActionDeny {
+		h.log.Warn("unknown device verification action", logger.String("action", action))
+		data.Error = "unknown action"
+		h.render(writer, http.StatusBadRequest, data)
+		return
+	}
+
+	var (
+		err  error
+		user domain.User
+	)
+	if user, err = h.credentials.Verify(req.Context(), data.Email, password); err != nil {
+		status, message := credentialsError(req.Context(), h.log, h.auditLog, err)
+		if status == http.StatusInternalServerError {
+			data = &devicePageData{}
+		}
+
+		data.Error = message
+		h.render(writer, status, data)
+		return
+	}
+
+	if status, message := h.secondFactor.verify(
+		req.Context(), user.ID(), req.PostForm.Get("otp"), req.PostForm.Get("recovery_code"),
+	); status != 0 {
+		if status == http.StatusInternalServerError {
+			data = &devicePageData{}
+		}
+
+		data.Error = message
+		h.render(writer, status, data)
+		return
+	}
+
+	h.auditLog.Record(req.Context(), loginEvent(user.ID(), loginMethodPassword, domain.AuditOutcomeSuccess))
+
+	if action == deviceActionApprove {
+		err = h.devices.Approve(req.Context(), data.UserCode, user.ID())
+		data.Message = "Device approved. You can return to your device."
+	} else {
+		err = h.devices.Deny(req.Context(), data.UserCode)
+		data.Message = "Device denied. It will not get access to your account."
+	}
+
+	if err != nil {
+		data.Message = ""
+
+		if errors.Is(err, oauth.ErrInvalidUserCode) {
+			h.log.Warn("invalid user code")
+			data.Error = "invalid or expired code"
+			h.render(writer, http.StatusBadRequest, data)
+			return
+		}
+
+		h.log.Error("failed to resolve device grant", logger.Error(err))
+		h.render(writer, http.StatusInternalServerError, &devicePageData{Error: "internal server error"})
+		return
+	}
+
+	h.render(writer, http.StatusOK, data)
+}
+
+// render writes the verification page with the given status.
+func (h *DeviceVerification) render(writer http.ResponseWriter, status int, data *devicePageData) {
+	writer.Header().Set("Content-Type", "text/html; charset=utf-8")
+	writer.WriteHeader(status)
+
+	if err := h.page.Execute(writer, data); err != nil {
+		h.log.Error("failed to render device verification page", logger.Error(err))
+	}
+}
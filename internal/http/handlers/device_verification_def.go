@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/auth"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// DefDeviceVerificationName is the name of the *DeviceVerification definition.
+const DefDeviceVerificationName = "http.device-verification"
+
+func init() {
+	di.Add(
+		di.Def[*DeviceVerification]{
+			Name: DefDeviceVerificationName,
+			Build: func(ctn di.Container) (*DeviceVerification, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var credentials *auth.Credentials
+				if err := ctn.Fill(auth.DefCredentialsName, &credentials); err != nil {
+					return nil, err
+				}
+
+				var totp *mfa.TOTP
+				if err := ctn.Fill(mfa.DefTOTPName, &totp); err != nil {
+					return nil, err
+				}
+
+				var recoveryCodes *mfa.RecoveryCodes
+				if err := ctn.Fill(mfa.DefRecoveryCodesName, &recoveryCodes); err != nil {
+					return nil, err
+				}
+
+				var attempts *mfa.Attempts
+				if err := ctn.Fill(mfa.DefAttemptsName, &attempts); err != nil {
+					return nil, err
+				}
+
+				var devices *oauth.Devices
+				if err := ctn.Fill(oauth.DefDevicesName, &devices); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewDeviceVerification(
+					log,
+					credentials,
+					totp,
+					totp,
+					recoveryCodes,
+					attempts,
+					devices,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
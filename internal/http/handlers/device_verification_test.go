@@ -0,0 +1,364 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/auth"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+func TestDeviceVerification_ServeHTTP(t *testing.T) {
+	newPostRequest := func(password string, action string) func() *http.Request {
+		return func() *http.Request {
+			form := url.Values{
+				"user_code": {"WDJB-MJHT"},
+				"email":     {"user@example.com"},
+				"password":  {password},
+				"action":    {action},
+				"otp":       {"123456"},
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/oauth/device", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return req
+		}
+	}
+
+	newRecoveryRequest := func() *http.Request {
+		form := url.Values{
+			"user_code":     {"WDJB-MJHT"},
+			"email":         {"user@example.com"},
+			"password":      {"password"},
+			"action":        {"approve"},
+			"recovery_code": {"AAAA-BBBB-CCCC-DDDD"},
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/oauth/device", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return req
+	}
+
+	generateUser := func() (domain.User, error) {
+		return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+	}
+
+	testCases := []struct {
+		name                string
+		req                 func() *http.Request
+		onVerifyCredentials func() (domain.User, error)
+		onIsMFAEnabled      func() (bool, error)
+		onAddAttempt        func() error
+		onVerifyTOTP        func() error
+		onUseRecoveryCode   func() error
+		onResetAttempts     func() error
+		onApprove           func() error
+		onDeny              func() error
+		expRecoveryCodeUsed bool
+		expAuditEvent       *domain.AuditEvent
+		expStatus           int
+		expBody             string
+	}{
+		{
+			name: "verification page",
+			req: func() *http.Request {
+				return httptest.NewRequest(http.MethodGet, "/oauth/device?user_code=WDJB-MJHT", nil)
+			},
+			expStatus: http.StatusOK,
+			expBody:   `name="user_code" value="WDJB-MJHT"`,
+		},
+		{
+			name:      "password is missing",
+			req:       newPostRequest("", "approve"),
+			expStatus: http.StatusBadRequest,
+			expBody:   "code, email and password are required",
+		},
+		{
+			name:      "unknown action",
+			req:       newPostRequest("password", "ignore"),
+			expStatus: http.StatusBadRequest,
+			expBody:   "unknown action",
+		},
+		{
+			name:                "invalid credentials",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: func() (domain.User, error) { return nil, auth.ErrInvalidCredentials },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_credentials",
+				Details: map[string]string{"method": "password"},
+			},
+			expStatus: http.StatusUnauthorized,
+			expBody:   "invalid email or password",
+		},
+		{
+			name:                "email is not verified",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: func() (domain.User, error) { return nil, auth.ErrEmailNotVerified },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "email_not_verified",
+				Details: map[string]string{"method": "password"},
+			},
+			expStatus: http.StatusForbidden,
+			expBody:   "email address is not verified",
+		},
+		{
+			name:                "account is locked",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: func() (domain.User, error) { return nil, &auth.AccountLockedError{} },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "account_locked",
+				Details: map[string]string{"method": "password"},
+			},
+			expStatus: http.StatusUnauthorized,
+			expBody:   "invalid email or password",
+		},
+		{
+			name:                "failed to verify credentials",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: func() (domain.User, error) { return nil, assert.AnError },
+			expStatus:           http.StatusInternalServerError,
+			expBody:             "internal server error",
+		},
+		{
+			name:                "failed to check mfa status",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: generateUser,
+			onIsMFAEnabled:      func() (bool, error) { return false, assert.AnError },
+			expStatus:           http.StatusInternalServerError,
+			expBody:             "internal server error",
+		},
+		{
+			name:                "invalid authentication code",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: generateUser,
+			onIsMFAEnabled:      func() (bool, error) { return true, nil },
+			onAddAttempt:        func() error { return nil },
+			onVerifyTOTP:        func() error { return mfa.ErrInvalidCode },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_code",
+				Details: map[string]string{"method": "totp"},
+			},
+			expStatus: http.StatusUnauthorized,
+			expBody:   "authentication code is missing or invalid",
+		},
+		{
+			name:                "too many authentication code attempts",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: generateUser,
+			onIsMFAEnabled:      func() (bool, error) { return true, nil },
+			onAddAttempt:        func() error { return mfa.ErrTooManyAttempts },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "too_many_attempts",
+				Details: map[string]string{"method": "totp"},
+			},
+			expStatus: http.StatusTooManyRequests,
+			expBody:   "too many invalid authentication codes",
+		},
+		{
+			name:                "failed to count authentication code attempt",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: generateUser,
+			onIsMFAEnabled:      func() (bool, error) { return true, nil },
+			onAddAttempt:        func() error { return assert.AnError },
+			expStatus:           http.StatusInternalServerError,
+			expBody:             "internal server error",
+		},
+		{
+			name:                "failed to reset authentication code attempts",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: generateUser,
+			onIsMFAEnabled:      func() (bool, error) { return true, nil },
+			onAddAttempt:        func() error { return nil },
+			onVerifyTOTP:        func() error { return nil },
+			onResetAttempts:     func() error { return assert.AnError },
+			expStatus:           http.StatusInternalServerError,
+			expBody:             "internal server error",
+		},
+		{
+			name:                "device approved with mfa",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: generateUser,
+			onIsMFAEnabled:      func() (bool, error) { return true, nil },
+			onAddAttempt:        func() error { return nil },
+			onVerifyTOTP:        func() error { return nil },
+			onResetAttempts:     func() error { return nil },
+			onApprove:           func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+				Details: map[string]string{"method": "password"},
+			},
+			expStatus: http.StatusOK,
+			expBody:   "Device approved.",
+		},
+		{
+			name:                "device approved with recovery code",
+			req:                 newRecoveryRequest,
+			onVerifyCredentials: generateUser,
+			onIsMFAEnabled:      func() (bool, error) { return true, nil },
+			onAddAttempt:        func() error { return nil },
+			onUseRecoveryCode:   func() error { return nil },
+			onResetAttempts:     func() error { return nil },
+			onApprove:           func() error { return nil },
+			expRecoveryCodeUsed: true,
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+				Details: map[string]string{"method": "password"},
+			},
+			expStatus: http.StatusOK,
+			expBody:   "Device approved.",
+		},
+		{
+			name:                "invalid user code",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: generateUser,
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
+			onApprove:           func() error { return oauth.ErrInvalidUserCode },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+				Details: map[string]string{"method": "password"},
+			},
+			expStatus: http.StatusBadRequest,
+			expBody:   "invalid or expired code",
+		},
+		{
+			name:                "failed to approve",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: generateUser,
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
+			onApprove:           func() error { return assert.AnError },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+				Details: map[string]string{"method": "password"},
+			},
+			expStatus: http.StatusInternalServerError,
+			expBody:   "internal server error",
+		},
+		{
+			name:                "device approved",
+			req:                 newPostRequest("password", "approve"),
+			onVerifyCredentials: generateUser,
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
+			onApprove:           func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+				Details: map[string]string{"method": "password"},
+			},
+			expStatus: http.StatusOK,
+			expBody:   "Device approved.",
+		},
+		{
+			name:                "device denied",
+			req:                 newPostRequest("password", "deny"),
+			onVerifyCredentials: generateUser,
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
+			onDeny:              func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+				Details: map[string]string{"method": "password"},
+			},
+			expStatus: http.StatusOK,
+			expBody:   "Device denied.",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			credentials := mocks.NewCredentialsVerifier(t)
+			if testCase.onVerifyCredentials != nil {
+				credentials.On("Verify", mock.Anything, "user@example.com", "password").
+					Return(testCase.onVerifyCredentials())
+			}
+
+			mfaStatus := mocks.NewMFAStatusProvider(t)
+			if testCase.onIsMFAEnabled != nil {
+				mfaStatus.On("IsEnabled", mock.Anything, "user_id").Return(testCase.onIsMFAEnabled())
+			}
+
+			attempts := mocks.NewMFAAttempts(t)
+			if testCase.onAddAttempt != nil {
+				attempts.On("Add", mock.Anything, "user_id").Return(testCase.onAddAttempt())
+			}
+
+			if testCase.onResetAttempts != nil {
+				attempts.On("Reset", mock.Anything, "user_id").Return(testCase.onResetAttempts())
+			}
+
+			totp := mocks.NewTOTPVerifier(t)
+			if testCase.onVerifyTOTP != nil {
+				totp.On("Verify", mock.Anything, "user_id", "123456").Return(testCase.onVerifyTOTP())
+			}
+
+			recoveryCodes := mocks.NewRecoveryCodeConsumer(t)
+			if testCase.onUseRecoveryCode != nil {
+				recoveryCodes.On("Use", mock.Anything, "user_id", "AAAA-BBBB-CCCC-DDDD").Return(testCase.onUseRecoveryCode())
+			}
+
+			devices := mocks.NewDeviceGrantResolver(t)
+			if testCase.onApprove != nil {
+				devices.On("Approve", mock.Anything, "WDJB-MJHT", "user_id").Return(testCase.onApprove())
+			}
+
+			if testCase.onDeny != nil {
+				devices.On("Deny", mock.Anything, "WDJB-MJHT").Return(testCase.onDeny())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", mock.Anything, *testCase.expAuditEvent).Return()
+			}
+
+			if testCase.expRecoveryCodeUsed {
+				auditLog.On("Record", mock.Anything, domain.AuditEvent{
+					Type:   domain.AuditEventRecoveryCodeUsed,
+					UserID: "user_id",
+				}).Return()
+			}
+
+			handler := handlers.NewDeviceVerification(
+				zap.NewNop(), credentials, mfaStatus, totp, recoveryCodes, attempts, devices, auditLog,
+			)
+
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, testCase.req())
+
+			assert.Equal(t, testCase.expStatus, recorder.Code)
+			assert.Contains(t, recorder.Body.String(), testCase.expBody)
+			assert.Equal(t, "DENY", recorder.Header().Get("X-Frame-Options"))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package handlers
+
+//go:generate mockery --name TOTPEnroller --output ./mocks --outpkg mocks --filename totp_enroller.go --structname TOTPEnroller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+// NewEnrollTOTPV1 creates a new *EnrollTOTPV1 instance.
+func NewEnrollTOTPV1(
+	log *logger.Logger,
+	userProvider UserByIdProvider,
+	totp TOTPEnroller,
+) *EnrollTOTPV1 {
+	return &EnrollTOTPV1{
+		log:          log,
+		userProvider: userProvider,
+		totp:         totp,
+	}
+}
+
+type (
+	// EnrollTOTPV1 starts TOTP enrollment of the authenticated user.
+	EnrollTOTPV1 struct {
+		log          *logger.Logger
+		userProvider UserByIdProvider
+		totp         TOTPEnroller
+	}
+
+	// EnrollTOTPV1Request represents TOTP enrollment request.
+	EnrollTOTPV1Request struct{}
+
+	// EnrollTOTPV1Response holds the new secret. Two-factor authentication is enabled only after
+	// the secret is confirmed with ConfirmTOTPV1.
+	EnrollTOTPV1Response struct {
+		Secret     string `json:"secret"`
+		OTPAuthURI string `json:"otpauth_uri"`
+	}
+
+	// TOTPEnroller describes TOTPEnroller dependency.
+	TOTPEnroller interface {
+		Enroll(ctx context.Context, userID string, accountName string) (mfa.Enrollment, error)
+	}
+)
+
+// Handle generates a new TOTP secret for the user, replacing a previous unconfirmed one.
+func (h *EnrollTOTPV1) Handle(ctx context.Context, _ *EnrollTOTPV1Request) *httpx.Response {
+	var (
+		ok     bool
+		userID string
+	)
+	if userID, ok = httpx.GetUserID(ctx); !ok {
+		h.log.Warn("user id is missing")
+		return httpx.BadRequest
+	}
+
+	var (
+		err  error
+		user domain.User
+	)
+	if user, err = h.userProvider.GetByID(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			h.log.Warn("user not found")
+			return httpx.NotFound
+		}
+
+		h.log.Error("failed to get user by id", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	var enrollment mfa.Enrollment
+	if enrollment, err = h.totp.Enroll(ctx, userID, user.Email()); err != nil {
+		if errors.Is(err, mfa.ErrAlreadyEnabled) {
+			h.log.Warn("totp is already enabled")
+			return httpx.NewErrorResponse(http.StatusConflict, "totp is already enabled")
+		}
+
+		h.log.Error("failed to enroll totp", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&EnrollTOTPV1Response{
+			Secret:     enrollment.Secret,
+			OTPAuthURI: enrollment.URI,
+		}),
+	)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+// DefEnrollTOTPV1Name is the name of the *EnrollTOTPV1 definition.
+const DefEnrollTOTPV1Name = "http.enroll-totp-v1"
+
+func init() {
+	di.Add(
+		di.Def[*EnrollTOTPV1]{
+			Name: DefEnrollTOTPV1Name,
+			Build: func(ctn di.Container) (*EnrollTOTPV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				var totp *mfa.TOTP
+				if err := ctn.Fill(mfa.DefTOTPName, &totp); err != nil {
+					return nil, err
+				}
+
+				return NewEnrollTOTPV1(
+					log,
+					usersRep,
+					totp,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+func TestEnrollTOTPV1_Handle(t *testing.T) {
+	testCases := []struct {
+		name          string
+		userID        string
+		onGetUserByID func() (domain.User, error)
+		onEnroll      func() (mfa.Enrollment, error)
+		expResp       *httpx.Response
+	}{
+		{
+			name:    "user id is missing",
+			expResp: httpx.BadRequest,
+		},
+		{
+			name:          "user not found",
+			userID:        "user_id",
+			onGetUserByID: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			expResp:       httpx.NotFound,
+		},
+		{
+			name:          "failed to get user",
+			userID:        "user_id",
+			onGetUserByID: func() (domain.User, error) { return nil, assert.AnError },
+			expResp:       httpx.InternalServerError,
+		},
+		{
+			name:   "totp is already enabled",
+			userID: "user_id",
+			onGetUserByID: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onEnroll: func() (mfa.Enrollment, error) { return mfa.Enrollment{}, mfa.ErrAlreadyEnabled },
+			expResp:  httpx.NewErrorResponse(http.StatusConflict, "totp is already enabled"),
+		},
+		{
+			name:   "failed to enroll",
+			userID: "user_id",
+			onGetUserByID: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onEnroll: func() (mfa.Enrollment, error) { return mfa.Enrollment{}, assert.AnError },
+			expResp:  httpx.InternalServerError,
+		},
+		{
+			name:   "positive case",
+			userID: "user_id",
+			onGetUserByID: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onEnroll: func() (mfa.Enrollment, error) {
+				return mfa.Enrollment{Secret: "SECRET", URI: "otpauth://totp/issuer:user@example.com"}, nil
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.EnrollTOTPV1Response{
+					Secret:     "SECRET",
+					OTPAuthURI: "otpauth://totp/issuer:user@example.com",
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.userID != "" {
+				ctx = httpx.ContextWithUserID(ctx, testCase.userID)
+			}
+
+			userProvider := mocks.NewUserByIdProvider(t)
+			if testCase.onGetUserByID != nil {
+				userProvider.On("GetByID", ctx, testCase.userID).Return(testCase.onGetUserByID())
+			}
+
+			totp := mocks.NewTOTPEnroller(t)
+			if testCase.onEnroll != nil {
+				totp.On("Enroll", ctx, testCase.userID, "user@example.com").Return(testCase.onEnroll())
+			}
+
+			handler := handlers.NewEnrollTOTPV1(zap.NewNop(), userProvider, totp)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(ctx, &handlers.EnrollTOTPV1Request{}))
+		})
+	}
+}
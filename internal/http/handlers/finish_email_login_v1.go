@@ -0,0 +1,152 @@
+package handlers
+
+//go:generate mockery --name EmailLoginCompleter --output ./mocks --outpkg mocks --filename email_login_completer.go --structname EmailLoginCompleter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+// NewFinishEmailLoginV1 creates a new *FinishEmailLoginV1 instance.
+func NewFinishEmailLoginV1(
+	log *logger.Logger,
+	issuer TokenIssuer,
+	jwtStorage JwtStorage,
+	emailLogin EmailLoginCompleter,
+	mfaStatus MFAStatusProvider,
+	challenges MFAChallengeCreator,
+	auditLog AuditRecorder,
+) *FinishEmailLoginV1 {
+	return &FinishEmailLoginV1{
+		log:        log,
+		issuer:     issuer,
+		jwtStorage: jwtStorage,
+		emailLogin: emailLogin,
+		mfaStatus:  mfaStatus,
+		challenges: challenges,
+		auditLog:   auditLog,
+	}
+}
+
+type (
+	// FinishEmailLoginV1 completes an email login with the emailed code or link token.
+	FinishEmailLoginV1 struct {
+		log        *logger.Logger
+		issuer     TokenIssuer
+		jwtStorage JwtStorage
+		emailLogin EmailLoginCompleter
+		mfaStatus  MFAStatusProvider
+		challenges MFAChallengeCreator
+		auditLog   AuditRecorder
+	}
+
+	// FinishEmailLoginV1Request represents email login completion request.
+	// Code is either the emailed code or the token from the emailed link.
+	FinishEmailLoginV1Request struct {
+		LoginToken string `json:"login_token"`
+		Code       string `json:"code"`
+	}
+
+	// EmailLoginCompleter describes EmailLoginCompleter dependency.
+	EmailLoginCompleter interface {
+		Complete(ctx context.Context, token string, code string) (string, error)
+	}
+)
+
+// Handle redeems the code and returns the same response as LoginV1.
+// Users with two-factor authentication enabled get an MFA challenge instead of tokens.
+func (h *FinishEmailLoginV1) Handle(ctx context.Context, req *FinishEmailLoginV1Request) *httpx.Response {
+	if req.LoginToken == "" {
+		h.log.Warn("login token is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "login_token is required")
+	}
+
+	if req.Code == "" {
+		h.log.Warn("code is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "code is required")
+	}
+
+	userID, err := h.emailLogin.Complete(ctx, req.LoginToken, req.Code)
+	if err != nil {
+		if errors.Is(err, account.ErrInvalidToken) {
+			h.log.Warn("invalid email login token")
+			return httpx.NewErrorResponse(http.StatusUnauthorized, "invalid or expired login token")
+		}
+
+		if errors.Is(err, account.ErrInvalidCode) {
+			h.log.Warn("invalid email login code")
+
+			event := loginEvent("", loginMethodEmail, domain.AuditOutcomeFailure)
+			event.Reason = auditReasonInvalidCode
+			h.auditLog.Record(ctx, event)
+
+			return httpx.NewErrorResponse(http.StatusUnauthorized, "invalid code")
+		}
+
+		h.log.Error("failed to complete email login", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	var mfaEnabled bool
+	if mfaEnabled, err = h.mfaStatus.IsEnabled(ctx, userID); err != nil {
+		h.log.Error("failed to check mfa status", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if mfaEnabled {
+		var challenge mfa.ChallengeToken
+		if challenge, err = h.challenges.Create(ctx, userID); err != nil {
+			h.log.Error("failed to create mfa challenge", logger.Error(err))
+			return httpx.InternalServerError
+		}
+
+		h.auditLog.Record(ctx, loginEvent(userID, loginMethodEmail, domain.AuditOutcomeChallenged))
+
+		return httpx.NewJsonResponse(
+			httpx.WithStatus(http.StatusAccepted),
+			httpx.WithBody(&LoginV1MFAResponse{
+				MFAToken:   challenge.Token,
+				MFAMethods: []string{mfa.MethodTOTP, mfa.MethodRecoveryCode},
+				ExpiresIn:  int64(challenge.ExpiresIn.Seconds()),
+			}),
+		)
+	}
+
+	var accessToken string
+	if accessToken, err = h.issuer.IssueAccessToken(userID); err != nil {
+		h.log.Error("failed to issue access token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	var refreshToken string
+	if refreshToken, err = h.issuer.IssueRefreshToken(userID); err != nil {
+		h.log.Error("failed to issue refresh token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if err = h.jwtStorage.Save(ctx, userID, refreshToken); err != nil {
+		h.log.Error("failed to save refresh token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	event := loginEvent(userID, loginMethodEmail, domain.AuditOutcomeSuccess)
+	event.SessionID = sessionID(refreshToken)
+	h.auditLog.Record(ctx, event)
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&LoginV1Response{
+			UserID:       userID,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+		}),
+	)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+// DefFinishEmailLoginV1Name is the name of the *FinishEmailLoginV1 definition.
+const DefFinishEmailLoginV1Name = "http.finish-email-login-v1"
+
+func init() {
+	di.Add(
+		di.Def[*FinishEmailLoginV1]{
+			Name: DefFinishEmailLoginV1Name,
+			Build: func(ctn di.Container) (*FinishEmailLoginV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var issuer *jwt.Issuer
+				if err := ctn.Fill(jwt.DefIssuerName, &issuer); err != nil {
+					return nil, err
+				}
+
+				var storage *jwt.Storage
+				if err := ctn.Fill(jwt.DefStorageName, &storage); err != nil {
+					return nil, err
+				}
+
+				var emailLogin *account.EmailLogin
+				if err := ctn.Fill(account.DefEmailLoginName, &emailLogin); err != nil {
+					return nil, err
+				}
+
+				var totp *mfa.TOTP
+				if err := ctn.Fill(mfa.DefTOTPName, &totp); err != nil {
+					return nil, err
+				}
+
+				var challenges *mfa.Challenges
+				if err := ctn.Fill(mfa.DefChallengesName, &challenges); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewFinishEmailLoginV1(
+					log,
+					issuer,
+					storage,
+					emailLogin,
+					totp,
+					challenges,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,213 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+func TestFinishEmailLoginV1_Handle(t *testing.T) {
+	validRequest := &handlers.FinishEmailLoginV1Request{LoginToken: "login_token", Code: "123456"}
+
+	testCases := []struct {
+		name                string
+		req                 *handlers.FinishEmailLoginV1Request
+		onComplete          func() (string, error)
+		onIsMFAEnabled      func() (bool, error)
+		onCreateChallenge   func() (mfa.ChallengeToken, error)
+		onIssueAccessToken  func() (string, error)
+		onIssueRefreshToken func() (string, error)
+		onSaveRefreshToken  func() error
+		expAuditEvent       *domain.AuditEvent
+		expResp             *httpx.Response
+	}{
+		{
+			name:    "login token is missing",
+			req:     &handlers.FinishEmailLoginV1Request{Code: "123456"},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "login_token is required"),
+		},
+		{
+			name:    "code is missing",
+			req:     &handlers.FinishEmailLoginV1Request{LoginToken: "login_token"},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "code is required"),
+		},
+		{
+			name:       "invalid login token",
+			req:        validRequest,
+			onComplete: func() (string, error) { return "", account.ErrInvalidToken },
+			expResp:    httpx.NewErrorResponse(http.StatusUnauthorized, "invalid or expired login token"),
+		},
+		{
+			name:       "invalid code",
+			req:        validRequest,
+			onComplete: func() (string, error) { return "", account.ErrInvalidCode },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_code",
+				Details: map[string]string{"method": "email"},
+			},
+			expResp: httpx.NewErrorResponse(http.StatusUnauthorized, "invalid code"),
+		},
+		{
+			name:       "failed to complete email login",
+			req:        validRequest,
+			onComplete: func() (string, error) { return "", assert.AnError },
+			expResp:    httpx.InternalServerError,
+		},
+		{
+			name:           "failed to check mfa status",
+			req:            validRequest,
+			onComplete:     func() (string, error) { return "user_id", nil },
+			onIsMFAEnabled: func() (bool, error) { return false, assert.AnError },
+			expResp:        httpx.InternalServerError,
+		},
+		{
+			name:              "failed to create mfa challenge",
+			req:               validRequest,
+			onComplete:        func() (string, error) { return "user_id", nil },
+			onIsMFAEnabled:    func() (bool, error) { return true, nil },
+			onCreateChallenge: func() (mfa.ChallengeToken, error) { return mfa.ChallengeToken{}, assert.AnError },
+			expResp:           httpx.InternalServerError,
+		},
+		{
+			name:           "mfa is enabled",
+			req:            validRequest,
+			onComplete:     func() (string, error) { return "user_id", nil },
+			onIsMFAEnabled: func() (bool, error) { return true, nil },
+			onCreateChallenge: func() (mfa.ChallengeToken, error) {
+				return mfa.ChallengeToken{Token: "mfa_token", ExpiresIn: 5 * time.Minute}, nil
+			},
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeChallenged,
+				Details: map[string]string{"method": "email"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusAccepted),
+				httpx.WithBody(&handlers.LoginV1MFAResponse{
+					MFAToken:   "mfa_token",
+					MFAMethods: []string{mfa.MethodTOTP, mfa.MethodRecoveryCode},
+					ExpiresIn:  300,
+				}),
+			),
+		},
+		{
+			name:               "failed to issue access token",
+			req:                validRequest,
+			onComplete:         func() (string, error) { return "user_id", nil },
+			onIsMFAEnabled:     func() (bool, error) { return false, nil },
+			onIssueAccessToken: func() (string, error) { return "", assert.AnError },
+			expResp:            httpx.InternalServerError,
+		},
+		{
+			name:                "failed to issue refresh token",
+			req:                 validRequest,
+			onComplete:          func() (string, error) { return "user_id", nil },
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "", assert.AnError },
+			expResp:             httpx.InternalServerError,
+		},
+		{
+			name:                "failed to save refresh token",
+			req:                 validRequest,
+			onComplete:          func() (string, error) { return "user_id", nil },
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return assert.AnError },
+			expResp:             httpx.InternalServerError,
+		},
+		{
+			name:                "positive case",
+			req:                 validRequest,
+			onComplete:          func() (string, error) { return "user_id", nil },
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserID:    "user_id",
+				SessionID: "6c8a7d4aa21708a4",
+				Outcome:   domain.AuditOutcomeSuccess,
+				Details:   map[string]string{"method": "email"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.LoginV1Response{
+					UserID:       "user_id",
+					AccessToken:  "access_token",
+					RefreshToken: "refresh_token",
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			emailLogin := mocks.NewEmailLoginCompleter(t)
+			if testCase.onComplete != nil {
+				emailLogin.On("Complete", t.Context(), testCase.req.LoginToken, testCase.req.Code).
+					Return(testCase.onComplete())
+			}
+
+			mfaStatus := mocks.NewMFAStatusProvider(t)
+			if testCase.onIsMFAEnabled != nil {
+				mfaStatus.On("IsEnabled", t.Context(), "user_id").Return(testCase.onIsMFAEnabled())
+			}
+
+			challenges := mocks.NewMFAChallengeCreator(t)
+			if testCase.onCreateChallenge != nil {
+				challenges.On("Create", t.Context(), "user_id").Return(testCase.onCreateChallenge())
+			}
+
+			tokenIssuer := mocks.NewTokenIssuer(t)
+			if testCase.onIssueAccessToken != nil {
+				tokenIssuer.On("IssueAccessToken", "user_id").Return(testCase.onIssueAccessToken())
+			}
+
+			var refreshToken string
+			if testCase.onIssueRefreshToken != nil {
+				var err error
+				refreshToken, err = testCase.onIssueRefreshToken()
+
+				tokenIssuer.On("IssueRefreshToken", "user_id").Return(refreshToken, err)
+			}
+
+			jwtStorage := mocks.NewJwtStorage(t)
+			if testCase.onSaveRefreshToken != nil {
+				jwtStorage.On("Save", t.Context(), "user_id", refreshToken).Return(testCase.onSaveRefreshToken())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", t.Context(), *testCase.expAuditEvent).Return()
+			}
+
+			handler := handlers.NewFinishEmailLoginV1(
+				zap.NewNop(),
+				tokenIssuer,
+				jwtStorage,
+				emailLogin,
+				mfaStatus,
+				challenges,
+				auditLog,
+			)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), testCase.req))
+		})
+	}
+}
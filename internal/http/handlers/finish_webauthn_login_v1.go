@@ -0,0 +1,108 @@
+package handlers
+
+//go:generate mockery --name WebAuthnLoginFinisher --output ./mocks --outpkg mocks --filename webauthn_login_finisher.go --structname WebAuthnLoginFinisher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// NewFinishWebAuthnLoginV1 creates a new *FinishWebAuthnLoginV1 instance.
+func NewFinishWebAuthnLoginV1(
+	log *logger.Logger,
+	issuer TokenIssuer,
+	jwtStorage JwtStorage,
+	passkeys WebAuthnLoginFinisher,
+	auditLog AuditRecorder,
+) *FinishWebAuthnLoginV1 {
+	return &FinishWebAuthnLoginV1{
+		log:        log,
+		issuer:     issuer,
+		jwtStorage: jwtStorage,
+		passkeys:   passkeys,
+		auditLog:   auditLog,
+	}
+}
+
+type (
+	// FinishWebAuthnLoginV1 completes a passwordless login with a passkey.
+	FinishWebAuthnLoginV1 struct {
+		log        *logger.Logger
+		issuer     TokenIssuer
+		jwtStorage JwtStorage
+		passkeys   WebAuthnLoginFinisher
+		auditLog   AuditRecorder
+	}
+
+	// FinishWebAuthnLoginV1Request is the credential returned by navigator.credentials.get().
+	FinishWebAuthnLoginV1Request struct {
+		webauthn.AuthenticationCredential
+	}
+
+	// WebAuthnLoginFinisher describes WebAuthnLoginFinisher dependency.
+	WebAuthnLoginFinisher interface {
+		FinishLogin(ctx context.Context, credential webauthn.AuthenticationCredential) (string, error)
+	}
+)
+
+// Handle verifies the assertion of the authenticator and issues a token pair, the same way LoginV1 does.
+// The authenticator verifies the user itself, so the passkey counts as both factors and no MFA challenge follows.
+func (h *FinishWebAuthnLoginV1) Handle(ctx context.Context, req *FinishWebAuthnLoginV1Request) *httpx.Response {
+	userID, err := h.passkeys.FinishLogin(ctx, req.AuthenticationCredential)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrInvalidCeremony) {
+			h.log.Warn("invalid webauthn login ceremony")
+			return httpx.NewErrorResponse(http.StatusUnauthorized, "invalid or expired challenge")
+		}
+
+		if errors.Is(err, webauthn.ErrInvalidCredential) {
+			h.log.Warn("invalid webauthn assertion", logger.Error(err))
+
+			event := loginEvent("", loginMethodWebAuthn, domain.AuditOutcomeFailure)
+			event.Reason = auditReasonInvalidCredential
+			h.auditLog.Record(ctx, event)
+
+			return httpx.NewErrorResponse(http.StatusUnauthorized, "invalid credential")
+		}
+
+		h.log.Error("failed to finish webauthn login", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	var accessToken string
+	if accessToken, err = h.issuer.IssueAccessToken(userID); err != nil {
+		h.log.Error("failed to issue access token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	var refreshToken string
+	if refreshToken, err = h.issuer.IssueRefreshToken(userID); err != nil {
+		h.log.Error("failed to issue refresh token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if err = h.jwtStorage.Save(ctx, userID, refreshToken); err != nil {
+		h.log.Error("failed to save refresh token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	event := loginEvent(userID, loginMethodWebAuthn, domain.AuditOutcomeSuccess)
+	event.SessionID = sessionID(refreshToken)
+	h.auditLog.Record(ctx, event)
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&LoginV1Response{
+			UserID:       userID,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+		}),
+	)
+}
@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// DefFinishWebAuthnLoginV1Name is the name of the *FinishWebAuthnLoginV1 definition.
+const DefFinishWebAuthnLoginV1Name = "http.finish-webauthn-login-v1"
+
+func init() {
+	di.Add(
+		di.Def[*FinishWebAuthnLoginV1]{
+			Name: DefFinishWebAuthnLoginV1Name,
+			Build: func(ctn di.Container) (*FinishWebAuthnLoginV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var issuer *jwt.Issuer
+				if err := ctn.Fill(jwt.DefIssuerName, &issuer); err != nil {
+					return nil, err
+				}
+
+				var storage *jwt.Storage
+				if err := ctn.Fill(jwt.DefStorageName, &storage); err != nil {
+					return nil, err
+				}
+
+				var passkeys *webauthn.Passkeys
+				if err := ctn.Fill(webauthn.DefPasskeysName, &passkeys); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewFinishWebAuthnLoginV1(
+					log,
+					issuer,
+					storage,
+					passkeys,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
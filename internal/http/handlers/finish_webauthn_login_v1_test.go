@@ -0,0 +1,130 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+func TestFinishWebAuthnLoginV1_Handle(t *testing.T) {
+	req := &handlers.FinishWebAuthnLoginV1Request{
+		AuthenticationCredential: webauthn.AuthenticationCredential{ID: "AQID", RawID: "AQID", Type: "public-key"},
+	}
+
+	testCases := []struct {
+		name                string
+		onFinishLogin       func() (string, error)
+		onIssueAccessToken  func() (string, error)
+		onIssueRefreshToken func() (string, error)
+		onSaveRefreshToken  func() error
+		expAuditEvent       *domain.AuditEvent
+		expResp             *httpx.Response
+	}{
+		{
+			name:          "invalid ceremony",
+			onFinishLogin: func() (string, error) { return "", webauthn.ErrInvalidCeremony },
+			expResp:       httpx.NewErrorResponse(http.StatusUnauthorized, "invalid or expired challenge"),
+		},
+		{
+			name:          "invalid credential",
+			onFinishLogin: func() (string, error) { return "", webauthn.ErrInvalidCredential },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_credential",
+				Details: map[string]string{"method": "webauthn"},
+			},
+			expResp: httpx.NewErrorResponse(http.StatusUnauthorized, "invalid credential"),
+		},
+		{
+			name:          "failed to finish login",
+			onFinishLogin: func() (string, error) { return "", assert.AnError },
+			expResp:       httpx.InternalServerError,
+		},
+		{
+			name:               "failed to issue access token",
+			onFinishLogin:      func() (string, error) { return "user_id", nil },
+			onIssueAccessToken: func() (string, error) { return "", assert.AnError },
+			expResp:            httpx.InternalServerError,
+		},
+		{
+			name:                "failed to issue refresh token",
+			onFinishLogin:       func() (string, error) { return "user_id", nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "", assert.AnError },
+			expResp:             httpx.InternalServerError,
+		},
+		{
+			name:                "failed to save refresh token",
+			onFinishLogin:       func() (string, error) { return "user_id", nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return assert.AnError },
+			expResp:             httpx.InternalServerError,
+		},
+		{
+			name:                "positive case",
+			onFinishLogin:       func() (string, error) { return "user_id", nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserID:    "user_id",
+				SessionID: "6c8a7d4aa21708a4",
+				Outcome:   domain.AuditOutcomeSuccess,
+				Details:   map[string]string{"method": "webauthn"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.LoginV1Response{
+					UserID:       "user_id",
+					AccessToken:  "access_token",
+					RefreshToken: "refresh_token",
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			passkeys := mocks.NewWebAuthnLoginFinisher(t)
+			passkeys.On("FinishLogin", t.Context(), req.AuthenticationCredential).Return(testCase.onFinishLogin())
+
+			tokenIssuer := mocks.NewTokenIssuer(t)
+			if testCase.onIssueAccessToken != nil {
+				tokenIssuer.On("IssueAccessToken", "user_id").Return(testCase.onIssueAccessToken())
+			}
+
+			var refreshToken string
+			if testCase.onIssueRefreshToken != nil {
+				var err error
+				refreshToken, err = testCase.onIssueRefreshToken()
+
+				tokenIssuer.On("IssueRefreshToken", "user_id").Return(refreshToken, err)
+			}
+
+			jwtStorage := mocks.NewJwtStorage(t)
+			if testCase.onSaveRefreshToken != nil {
+				jwtStorage.On("Save", t.Context(), "user_id", refreshToken).Return(testCase.onSaveRefreshToken())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", t.Context(), *testCase.expAuditEvent).Return()
+			}
+
+			handler := handlers.NewFinishWebAuthnLoginV1(zap.NewNop(), tokenIssuer, jwtStorage, passkeys, auditLog)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), req))
+		})
+	}
+}
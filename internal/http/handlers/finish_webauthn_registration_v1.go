@@ -0,0 +1,112 @@
+package handlers
+
+//go:generate mockery --name WebAuthnRegistrationFinisher --output ./mocks --outpkg mocks --filename webauthn_registration_finisher.go --structname WebAuthnRegistrationFinisher
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// NewFinishWebAuthnRegistrationV1 creates a new *FinishWebAuthnRegistrationV1 instance.
+func NewFinishWebAuthnRegistrationV1(
+	log *logger.Logger,
+	passkeys WebAuthnRegistrationFinisher,
+	auditLog AuditRecorder,
+) *FinishWebAuthnRegistrationV1 {
+	return &FinishWebAuthnRegistrationV1{
+		log:      log,
+		passkeys: passkeys,
+		auditLog: auditLog,
+	}
+}
+
+type (
+	// FinishWebAuthnRegistrationV1 completes the registration of a passkey for the authenticated user.
+	FinishWebAuthnRegistrationV1 struct {
+		log      *logger.Logger
+		passkeys WebAuthnRegistrationFinisher
+		auditLog AuditRecorder
+	}
+
+	// FinishWebAuthnRegistrationV1Request is the credential returned by navigator.credentials.create().
+	FinishWebAuthnRegistrationV1Request struct {
+		webauthn.RegistrationCredential
+	}
+
+	// FinishWebAuthnRegistrationV1Response represents successful passkey registration response.
+	FinishWebAuthnRegistrationV1Response struct {
+		CredentialID string `json:"credential_id"`
+	}
+
+	// WebAuthnRegistrationFinisher describes WebAuthnRegistrationFinisher dependency.
+	WebAuthnRegistrationFinisher interface {
+		FinishRegistration(
+			ctx context.Context,
+			userID string,
+			credential webauthn.RegistrationCredential,
+		) (domain.WebAuthnCredential, error)
+	}
+)
+
+// Handle verifies the response of the authenticator and stores the new passkey.
+func (h *FinishWebAuthnRegistrationV1) Handle(ctx context.Context, req *FinishWebAuthnRegistrationV1Request) *httpx.Response {
+	var (
+		ok     bool
+		userID string
+	)
+	if userID, ok = httpx.GetUserID(ctx); !ok {
+		h.log.Warn("user id is missing")
+		return httpx.BadRequest
+	}
+
+	credential, err := h.passkeys.FinishRegistration(ctx, userID, req.RegistrationCredential)
+	if err != nil {
+		if errors.Is(err, webauthn.ErrInvalidCeremony) {
+			h.log.Warn("invalid webauthn registration ceremony")
+			return httpx.NewErrorResponse(http.StatusBadRequest, "invalid or expired challenge")
+		}
+
+		if errors.Is(err, webauthn.ErrInvalidCredential) {
+			h.log.Warn("invalid webauthn credential", logger.Error(err))
+			h.auditLog.Record(ctx, domain.AuditEvent{
+				Type:    domain.AuditEventPasskeyRegistration,
+				UserID:  userID,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  auditReasonInvalidCredential,
+			})
+			return httpx.NewErrorResponse(http.StatusBadRequest, "invalid credential")
+		}
+
+		if errors.Is(err, domain.ErrWebAuthnCredentialExists) {
+			h.log.Warn("webauthn credential already exists")
+			return httpx.NewErrorResponse(http.StatusConflict, "credential already registered")
+		}
+
+		h.log.Error("failed to finish webauthn registration", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	credentialID := base64.RawURLEncoding.EncodeToString(credential.ID)
+
+	h.auditLog.Record(ctx, domain.AuditEvent{
+		Type:    domain.AuditEventPasskeyRegistration,
+		UserID:  userID,
+		Outcome: domain.AuditOutcomeSuccess,
+		Details: map[string]string{"credential_id": credentialID},
+	})
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusCreated),
+		httpx.WithBody(&FinishWebAuthnRegistrationV1Response{
+			CredentialID: credentialID,
+		}),
+	)
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// DefFinishWebAuthnRegistrationV1Name is the name of the *FinishWebAuthnRegistrationV1 definition.
+const DefFinishWebAuthnRegistrationV1Name = "http.finish-webauthn-registration-v1"
+
+func init() {
+	di.Add(
+		di.Def[*FinishWebAuthnRegistrationV1]{
+			Name: DefFinishWebAuthnRegistrationV1Name,
+			Build: func(ctn di.Container) (*FinishWebAuthnRegistrationV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var passkeys *webauthn.Passkeys
+				if err := ctn.Fill(webauthn.DefPasskeysName, &passkeys); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewFinishWebAuthnRegistrationV1(
+					log,
+					passkeys,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,113 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+func TestFinishWebAuthnRegistrationV1_Handle(t *testing.T) {
+	req := &handlers.FinishWebAuthnRegistrationV1Request{
+		RegistrationCredential: webauthn.RegistrationCredential{ID: "AQID", RawID: "AQID", Type: "public-key"},
+	}
+
+	testCases := []struct {
+		name                 string
+		userID               string
+		onFinishRegistration func() (domain.WebAuthnCredential, error)
+		expEvent             *domain.AuditEvent
+		expResp              *httpx.Response
+	}{
+		{
+			name:    "user id is missing",
+			expResp: httpx.BadRequest,
+		},
+		{
+			name:   "invalid ceremony",
+			userID: "user_id",
+			onFinishRegistration: func() (domain.WebAuthnCredential, error) {
+				return domain.WebAuthnCredential{}, webauthn.ErrInvalidCeremony
+			},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "invalid or expired challenge"),
+		},
+		{
+			name:   "invalid credential",
+			userID: "user_id",
+			onFinishRegistration: func() (domain.WebAuthnCredential, error) {
+				return domain.WebAuthnCredential{}, webauthn.ErrInvalidCredential
+			},
+			expEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventPasskeyRegistration,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_credential",
+			},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "invalid credential"),
+		},
+		{
+			name:   "credential already exists",
+			userID: "user_id",
+			onFinishRegistration: func() (domain.WebAuthnCredential, error) {
+				return domain.WebAuthnCredential{}, domain.ErrWebAuthnCredentialExists
+			},
+			expResp: httpx.NewErrorResponse(http.StatusConflict, "credential already registered"),
+		},
+		{
+			name:   "failed to finish registration",
+			userID: "user_id",
+			onFinishRegistration: func() (domain.WebAuthnCredential, error) {
+				return domain.WebAuthnCredential{}, assert.AnError
+			},
+			expResp: httpx.InternalServerError,
+		},
+		{
+			name:   "positive case",
+			userID: "user_id",
+			onFinishRegistration: func() (domain.WebAuthnCredential, error) {
+				return domain.WebAuthnCredential{ID: []byte{1, 2, 3}, UserID: "user_id"}, nil
+			},
+			expEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventPasskeyRegistration,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+				Details: map[string]string{"credential_id": "AQID"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusCreated),
+				httpx.WithBody(&handlers.FinishWebAuthnRegistrationV1Response{CredentialID: "AQID"}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.userID != "" {
+				ctx = httpx.ContextWithUserID(ctx, testCase.userID)
+			}
+
+			passkeys := mocks.NewWebAuthnRegistrationFinisher(t)
+			if testCase.onFinishRegistration != nil {
+				passkeys.On("FinishRegistration", ctx, testCase.userID, req.RegistrationCredential).
+					Return(testCase.onFinishRegistration())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expEvent != nil {
+				auditLog.On("Record", ctx, *testCase.expEvent).Return()
+			}
+
+			handler := handlers.NewFinishWebAuthnRegistrationV1(zap.NewNop(), passkeys, auditLog)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(ctx, req))
+		})
+	}
+}
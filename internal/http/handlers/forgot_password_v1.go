@@ -0,0 +1,62 @@
+package handlers
+
+//go:generate mockery --name PasswordResetRequester --output ./mocks --outpkg mocks --filename password_reset_requester.go --structname PasswordResetRequester
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+)
+
+// NewForgotPasswordV1 creates a new *ForgotPasswordV1 instance.
+func NewForgotPasswordV1(
+	log *logger.Logger,
+	passwordReset PasswordResetRequester,
+) *ForgotPasswordV1 {
+	return &ForgotPasswordV1{
+		log:           log,
+		passwordReset: passwordReset,
+	}
+}
+
+type (
+	// ForgotPasswordV1 emails a password reset token to the owner of an email address.
+	ForgotPasswordV1 struct {
+		log           *logger.Logger
+		passwordReset PasswordResetRequester
+	}
+
+	// ForgotPasswordV1Request represents forgot password request.
+	ForgotPasswordV1Request struct {
+		Email string `json:"email"`
+	}
+
+	// PasswordResetRequester describes PasswordResetRequester dependency.
+	PasswordResetRequester interface {
+		Request(ctx context.Context, email string) error
+	}
+)
+
+// Handle sends a password reset token. The response is always the same, even when sending fails or a reset
+// was requested too recently, so that the endpoint cannot be used to find accounts.
+func (h *ForgotPasswordV1) Handle(ctx context.Context, req *ForgotPasswordV1Request) *httpx.Response {
+	if req.Email == "" {
+		h.log.Warn("email is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "email is required")
+	}
+
+	if err := h.passwordReset.Request(ctx, req.Email); err != nil {
+		if errors.Is(err, account.ErrTooManyRequests) {
+			h.log.Warn("password reset was requested too recently")
+		} else {
+			h.log.Error("failed to request password reset", logger.Error(err))
+		}
+	}
+
+	return httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted))
+}
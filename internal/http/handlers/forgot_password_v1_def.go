@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+)
+
+// DefForgotPasswordV1Name is the name of the *ForgotPasswordV1 definition.
+const DefForgotPasswordV1Name = "http.forgot-password-v1"
+
+func init() {
+	di.Add(
+		di.Def[*ForgotPasswordV1]{
+			Name: DefForgotPasswordV1Name,
+			Build: func(ctn di.Container) (*ForgotPasswordV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var passwordReset *account.PasswordReset
+				if err := ctn.Fill(account.DefPasswordResetName, &passwordReset); err != nil {
+					return nil, err
+				}
+
+				return NewForgotPasswordV1(
+					log,
+					passwordReset,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,60 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+)
+
+func TestForgotPasswordV1_Handle(t *testing.T) {
+	testCases := []struct {
+		name      string
+		req       *handlers.ForgotPasswordV1Request
+		onRequest func() error
+		expResp   *httpx.Response
+	}{
+		{
+			name:    "email is missing",
+			req:     &handlers.ForgotPasswordV1Request{},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "email is required"),
+		},
+		{
+			name:      "failed to request password reset",
+			req:       &handlers.ForgotPasswordV1Request{Email: "user@example.com"},
+			onRequest: func() error { return assert.AnError },
+			expResp:   httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted)),
+		},
+		{
+			name:      "password reset was requested too recently",
+			req:       &handlers.ForgotPasswordV1Request{Email: "user@example.com"},
+			onRequest: func() error { return account.ErrTooManyRequests },
+			expResp:   httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted)),
+		},
+		{
+			name:      "positive case",
+			req:       &handlers.ForgotPasswordV1Request{Email: "user@example.com"},
+			onRequest: func() error { return nil },
+			expResp:   httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted)),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			passwordReset := mocks.NewPasswordResetRequester(t)
+			if testCase.onRequest != nil {
+				passwordReset.On("Request", t.Context(), testCase.req.Email).Return(testCase.onRequest())
+			}
+
+			handler := handlers.NewForgotPasswordV1(zap.NewNop(), passwordReset)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), testCase.req))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewGetProfileV1 creates a new *GetProfileV1 instance.
+func NewGetProfileV1(
+	log *logger.Logger,
+	userProvider UserByIdProvider,
+) *GetProfileV1 {
+	return &GetProfileV1{
+		log:          log,
+		userProvider: userProvider,
+	}
+}
+
+type (
+	// GetProfileV1 shows the authenticated user their account and profile.
+	GetProfileV1 struct {
+		log          *logger.Logger
+		userProvider UserByIdProvider
+	}
+
+	// GetProfileV1Request represents get profile request.
+	GetProfileV1Request struct{}
+
+	// ProfileV1Response represents the account and profile of a user.
+	ProfileV1Response struct {
+		UserID        string          `json:"user_id"`
+		Email         string          `json:"email"`
+		EmailVerified bool            `json:"email_verified"`
+		DisplayName   string          `json:"display_name"`
+		Locale        string          `json:"locale"`
+		Timezone      string          `json:"timezone"`
+		AvatarURL     string          `json:"avatar_url"`
+		Metadata      json.RawMessage `json:"metadata"`
+		CreatedAt     time.Time       `json:"created_at"`
+		UpdatedAt     time.Time       `json:"updated_at"`
+	}
+)
+
+// Handle returns the account and profile of the authenticated user.
+func (h *GetProfileV1) Handle(ctx context.Context, _ *GetProfileV1Request) *httpx.Response {
+	var (
+		ok     bool
+		userID string
+	)
+	if userID, ok = httpx.GetUserID(ctx); !ok {
+		h.log.Warn("user id is missing")
+		return httpx.BadRequest
+	}
+
+	user, err := h.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			h.log.Warn("user not found")
+			return httpx.NotFound
+		}
+
+		h.log.Error("failed to get user by id", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	return newProfileV1Response(user)
+}
+
+// newProfileV1Response returns 200 OK with the account and profile of the user.
+func newProfileV1Response(user domain.User) *httpx.Response {
+	profile := user.Profile()
+
+	metadata := profile.Metadata
+	if len(metadata) == 0 {
+		metadata = json.RawMessage(`{}`)
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&ProfileV1Response{
+			UserID:        user.ID(),
+			Email:         user.Email(),
+			EmailVerified: user.EmailVerified(),
+			DisplayName:   profile.DisplayName,
+			Locale:        profile.Locale,
+			Timezone:      profile.Timezone,
+			AvatarURL:     profile.AvatarURL,
+			Metadata:      metadata,
+			CreatedAt:     user.CreatedAt(),
+			UpdatedAt:     user.UpdatedAt(),
+		}),
+	)
+}
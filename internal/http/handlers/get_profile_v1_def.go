@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+// DefGetProfileV1Name is the name of the *GetProfileV1 definition.
+const DefGetProfileV1Name = "http.get-profile-v1"
+
+func init() {
+	di.Add(
+		di.Def[*GetProfileV1]{
+			Name: DefGetProfileV1Name,
+			Build: func(ctn di.Container) (*GetProfileV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				return NewGetProfileV1(log, usersRep), nil
+			},
+		},
+	)
+}
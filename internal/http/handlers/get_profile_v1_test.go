@@ -0,0 +1,119 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+)
+
+func TestGetProfileV1_Handle(t *testing.T) {
+	createdAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name      string
+		userID    string
+		onGetByID func() (domain.User, error)
+		expResp   *httpx.Response
+	}{
+		{
+			name:    "user id is missing",
+			expResp: httpx.BadRequest,
+		},
+		{
+			name:      "user not found",
+			userID:    "user_id",
+			onGetByID: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			expResp:   httpx.NotFound,
+		},
+		{
+			name:      "failed to get user",
+			userID:    "user_id",
+			onGetByID: func() (domain.User, error) { return nil, assert.AnError },
+			expResp:   httpx.InternalServerError,
+		},
+		{
+			name:   "empty profile",
+			userID: "user_id",
+			onGetByID: func() (domain.User, error) {
+				return domain.NewUser(
+					"user_id",
+					"user@example.com",
+					"hashed_password",
+					domain.WithTimestamps(createdAt, createdAt),
+				), nil
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.ProfileV1Response{
+					UserID:    "user_id",
+					Email:     "user@example.com",
+					Metadata:  json.RawMessage(`{}`),
+					CreatedAt: createdAt,
+					UpdatedAt: createdAt,
+				}),
+			),
+		},
+		{
+			name:   "positive case",
+			userID: "user_id",
+			onGetByID: func() (domain.User, error) {
+				return domain.NewUser(
+					"user_id",
+					"user@example.com",
+					"hashed_password",
+					domain.WithEmailVerified(),
+					domain.WithProfile(domain.UserProfile{
+						DisplayName: "Jane Doe",
+						Locale:      "en-US",
+						Timezone:    "Europe/Berlin",
+						AvatarURL:   "https://example.com/avatar.png",
+						Metadata:    json.RawMessage(`{"theme": "dark"}`),
+					}),
+					domain.WithTimestamps(createdAt, createdAt.Add(time.Hour)),
+				), nil
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.ProfileV1Response{
+					UserID:        "user_id",
+					Email:         "user@example.com",
+					EmailVerified: true,
+					DisplayName:   "Jane Doe",
+					Locale:        "en-US",
+					Timezone:      "Europe/Berlin",
+					AvatarURL:     "https://example.com/avatar.png",
+					Metadata:      json.RawMessage(`{"theme": "dark"}`),
+					CreatedAt:     createdAt,
+					UpdatedAt:     createdAt.Add(time.Hour),
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.userID != "" {
+				ctx = httpx.ContextWithUserID(ctx, testCase.userID)
+			}
+
+			users := mocks.NewUserByIdProvider(t)
+			if testCase.onGetByID != nil {
+				users.On("GetByID", ctx, testCase.userID).Return(testCase.onGetByID())
+			}
+
+			handler := handlers.NewGetProfileV1(zap.NewNop(), users)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(ctx, &handlers.GetProfileV1Request{}))
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package handlers
+
+//go:generate mockery --name AssertionVerifier --output ./mocks --outpkg mocks --filename assertion_verifier.go --structname AssertionVerifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+)
+
+// grantTypeJwtBearer is the grant type of the JWT bearer assertion grant (RFC 7523, section 2.1).
+const grantTypeJwtBearer = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+
+// NewJwtBearerGrant creates a new *JwtBearerGrant instance.
+func NewJwtBearerGrant(
+	log *logger.Logger,
+	issuer TokenIssuer,
+	assertions AssertionVerifier,
+	auditLog AuditRecorder,
+) *JwtBearerGrant {
+	return &JwtBearerGrant{
+		log:        log,
+		issuer:     issuer,
+		assertions: assertions,
+		auditLog:   auditLog,
+	}
+}
+
+type (
+	// JwtBearerGrant exchanges assertions self-signed by service accounts for access tokens.
+	// No refresh token is issued: the service account signs a new assertion when the access token expires.
+	JwtBearerGrant struct {
+		log        *logger.Logger
+		issuer     TokenIssuer
+		assertions AssertionVerifier
+		auditLog   AuditRecorder
+	}
+
+	// AssertionVerifier describes AssertionVerifier dependency.
+	AssertionVerifier interface {
+		Verify(ctx context.Context, assertion string) (string, error)
+	}
+)
+
+// GrantType implements TokenGrant.
+func (g *JwtBearerGrant) GrantType() string {
+	return grantTypeJwtBearer
+}
+
+// Grant verifies the assertion and issues an access token for the service account that signed it.
+func (g *JwtBearerGrant) Grant(ctx context.Context, req *TokenV1Request) *httpx.Response {
+	if req.Assertion == "" {
+		g.log.Warn("assertion is missing")
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "assertion is required")
+	}
+
+	var (
+		err              error
+		serviceAccountID string
+	)
+	if serviceAccountID, err = g.assertions.Verify(ctx, req.Assertion); err != nil {
+		if errors.Is(err, jwt.ErrInvalidAssertion) || errors.Is(err, jwt.ErrAssertionReplayed) {
+			g.log.Warn("invalid assertion", logger.Error(err))
+
+			// the service account is only known once the assertion is verified
+			event := grantEvent(grantTypeJwtBearer, req.ClientID, "", domain.AuditOutcomeFailure)
+			event.Reason = auditReasonInvalidGrant
+			if errors.Is(err, jwt.ErrAssertionReplayed) {
+				event.Reason = auditReasonTokenReused
+			}
+			g.auditLog.Record(ctx, event)
+
+			return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidGrant, "invalid assertion")
+		}
+
+		g.log.Error("failed to verify assertion", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	var accessToken string
+	if accessToken, err = g.issuer.IssueAccessToken(serviceAccountID); err != nil {
+		g.log.Error("failed to issue access token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	g.auditLog.Record(ctx, grantEvent(grantTypeJwtBearer, req.ClientID, serviceAccountID, domain.AuditOutcomeSuccess))
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&TokenV1Response{
+			AccessToken: accessToken,
+			TokenType:   tokenTypeBearer,
+			ExpiresIn:   int64(g.issuer.AccessTokenTTL().Seconds()),
+		}),
+	)
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+)
+
+// DefJwtBearerGrantName is the name of the *JwtBearerGrant definition.
+const DefJwtBearerGrantName = "http.jwt-bearer-grant"
+
+func init() {
+	di.Add(
+		di.Def[*JwtBearerGrant]{
+			Name: DefJwtBearerGrantName,
+			Build: func(ctn di.Container) (*JwtBearerGrant, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var issuer *jwt.Issuer
+				if err := ctn.Fill(jwt.DefIssuerName, &issuer); err != nil {
+					return nil, err
+				}
+
+				var assertions *jwt.AssertionVerifier
+				if err := ctn.Fill(jwt.DefAssertionVerifierName, &assertions); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewJwtBearerGrant(
+					log,
+					issuer,
+					assertions,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,127 @@
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+)
+
+func TestJwtBearerGrant_Grant(t *testing.T) {
+	invalidGrant := httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusBadRequest),
+		httpx.WithBody(&handlers.OAuthErrorResponse{Error: "invalid_grant", ErrorDescription: "invalid assertion"}),
+	)
+
+	testCases := []struct {
+		name               string
+		req                *handlers.TokenV1Request
+		onVerify           func() (string, error)
+		onIssueAccessToken func() (string, error)
+		expEvent           *domain.AuditEvent
+		expResp            *httpx.Response
+	}{
+		{
+			name: "assertion is missing",
+			req:  &handlers.TokenV1Request{GrantType: "urn:ietf:params:oauth:grant-type:jwt-bearer"},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusBadRequest),
+				httpx.WithBody(&handlers.OAuthErrorResponse{Error: "invalid_request", ErrorDescription: "assertion is required"}),
+			),
+		},
+		{
+			name:     "invalid assertion",
+			req:      &handlers.TokenV1Request{Assertion: "assertion"},
+			onVerify: func() (string, error) { return "", fmt.Errorf("%w: jti is required", jwt.ErrInvalidAssertion) },
+			expEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventTokenGrant,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_grant",
+				Details: map[string]string{"grant_type": "urn:ietf:params:oauth:grant-type:jwt-bearer"},
+			},
+			expResp: invalidGrant,
+		},
+		{
+			name:     "replayed assertion",
+			req:      &handlers.TokenV1Request{Assertion: "assertion"},
+			onVerify: func() (string, error) { return "", jwt.ErrAssertionReplayed },
+			expEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventTokenGrant,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "token_reused",
+				Details: map[string]string{"grant_type": "urn:ietf:params:oauth:grant-type:jwt-bearer"},
+			},
+			expResp: invalidGrant,
+		},
+		{
+			name:     "failed to verify assertion",
+			req:      &handlers.TokenV1Request{Assertion: "assertion"},
+			onVerify: func() (string, error) { return "", assert.AnError },
+			expResp:  httpx.InternalServerError,
+		},
+		{
+			name:               "failed to issue access token",
+			req:                &handlers.TokenV1Request{Assertion: "assertion"},
+			onVerify:           func() (string, error) { return "worker", nil },
+			onIssueAccessToken: func() (string, error) { return "", assert.AnError },
+			expResp:            httpx.InternalServerError,
+		},
+		{
+			name:               "positive case",
+			req:                &handlers.TokenV1Request{Assertion: "assertion"},
+			onVerify:           func() (string, error) { return "worker", nil },
+			onIssueAccessToken: func() (string, error) { return "access_token", nil },
+			expEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventTokenGrant,
+				UserID:  "worker",
+				Outcome: domain.AuditOutcomeSuccess,
+				Details: map[string]string{"grant_type": "urn:ietf:params:oauth:grant-type:jwt-bearer"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.TokenV1Response{
+					AccessToken: "access_token",
+					TokenType:   "Bearer",
+					ExpiresIn:   300,
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assertions := mocks.NewAssertionVerifier(t)
+			if testCase.onVerify != nil {
+				assertions.On("Verify", t.Context(), testCase.req.Assertion).Return(testCase.onVerify())
+			}
+
+			issuer := mocks.NewTokenIssuer(t)
+			if testCase.onIssueAccessToken != nil {
+				issuer.On("IssueAccessToken", "worker").Return(testCase.onIssueAccessToken())
+			}
+
+			if testCase.expResp.Status() == http.StatusOK {
+				issuer.On("AccessTokenTTL").Return(5 * time.Minute)
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expEvent != nil {
+				auditLog.On("Record", t.Context(), *testCase.expEvent).Return()
+			}
+
+			grant := handlers.NewJwtBearerGrant(zap.NewNop(), issuer, assertions, auditLog)
+
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:jwt-bearer", grant.GrantType())
+			assert.Equal(t, testCase.expResp, grant.Grant(t.Context(), testCase.req))
+		})
+	}
+}
@@ -5,6 +5,6 @@ package handlers
 import "context"
 
 type JwtStorage interface {
-	Save(ctx context.Context, token string) error
+	Save(ctx context.Context, userID string, token string) error
 	Pop(ctx context.Context, token string) error
 }
@@ -0,0 +1,214 @@
+package handlers
+
+//go:generate mockery --name MFAChallenges --output ./mocks --outpkg mocks --filename mfa_challenges.go --structname MFAChallenges
+//go:generate mockery --name TOTPVerifier --output ./mocks --outpkg mocks --filename totp_verifier.go --structname TOTPVerifier
+//go:generate mockery --name RecoveryCodeConsumer --output ./mocks --outpkg mocks --filename recovery_code_consumer.go --structname RecoveryCodeConsumer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+// NewLoginMFAV1 creates a new *LoginMFAV1 instance.
+func NewLoginMFAV1(
+	log *logger.Logger,
+	issuer TokenIssuer,
+	jwtStorage JwtStorage,
+	challenges MFAChallenges,
+	totp TOTPVerifier,
+	recoveryCodes RecoveryCodeConsumer,
+	attempts MFAAttempts,
+	auditLog AuditRecorder,
+) *LoginMFAV1 {
+	return &LoginMFAV1{
+		log:           log,
+		issuer:        issuer,
+		jwtStorage:    jwtStorage,
+		challenges:    challenges,
+		totp:          totp,
+		recoveryCodes: recoveryCodes,
+		attempts:      attempts,
+		auditLog:      auditLog,
+	}
+}
+
+type (
+	// LoginMFAV1 completes a login of a user with two-factor authentication enabled.
+	LoginMFAV1 struct {
+		log           *logger.Logger
+		issuer        TokenIssuer
+		jwtStorage    JwtStorage
+		challenges    MFAChallenges
+		totp          TOTPVerifier
+		recoveryCodes RecoveryCodeConsumer
+		attempts      MFAAttempts
+		auditLog      AuditRecorder
+	}
+
+	// LoginMFAV1Request represents the second step of a login. The MFA token is returned by LoginV1.
+	// Either a TOTP code or a recovery code is required.
+	LoginMFAV1Request struct {
+		MFAToken     string `json:"mfa_token"`
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+
+	// MFAChallenges describes MFAChallenges dependency.
+	MFAChallenges interface {
+		Get(ctx context.Context, token string) (mfa.Challenge, error)
+		Fail(ctx context.Context, token string) error
+		Complete(ctx context.Context, token string) error
+	}
+
+	// TOTPVerifier describes TOTPVerifier dependency.
+	TOTPVerifier interface {
+		Verify(ctx context.Context, userID string, code string) error
+	}
+
+	// RecoveryCodeConsumer describes RecoveryCodeConsumer dependency.
+	RecoveryCodeConsumer interface {
+		Use(ctx context.Context, userID string, code string) error
+	}
+)
+
+// Handle verifies the code for the MFA challenge and issues a token pair, the same way LoginV1 does.
+// A used recovery code is recorded in the audit trail before the tokens are issued. Every code counts against
+// the attempts of the user as well as of the challenge, so that codes cannot be guessed by starting new challenges.
+func (h *LoginMFAV1) Handle(ctx context.Context, req *LoginMFAV1Request) *httpx.Response {
+	if req.MFAToken == "" {
+		h.log.Warn("mfa token is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "mfa_token is required")
+	}
+
+	if req.Code == "" && req.RecoveryCode == "" {
+		h.log.Warn("code and recovery code are missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "code or recovery_code is required")
+	}
+
+	var (
+		err       error
+		challenge mfa.Challenge
+	)
+	if challenge, err = h.challenges.Get(ctx, req.MFAToken); err != nil {
+		if errors.Is(err, mfa.ErrInvalidChallenge) {
+			h.log.Warn("invalid mfa challenge")
+			return httpx.NewErrorResponse(http.StatusUnauthorized, "invalid or expired mfa token")
+		}
+
+		h.log.Error("failed to get mfa challenge", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	method := loginMethodTOTP
+	if req.RecoveryCode != "" {
+		method = loginMethodRecoveryCode
+	}
+
+	if err = h.attempts.Add(ctx, challenge.UserID); err != nil {
+		if errors.Is(err, mfa.ErrTooManyAttempts) {
+			h.log.Warn("too many mfa code attempts")
+
+			event := loginEvent(challenge.UserID, method, domain.AuditOutcomeFailure)
+			event.Reason = auditReasonTooManyAttempts
+			h.auditLog.Record(ctx, event)
+
+			return httpx.NewErrorResponse(http.StatusTooManyRequests, "too many invalid codes, try again later")
+		}
+
+		h.log.Error("failed to count mfa attempt", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if req.RecoveryCode != "" {
+		err = h.recoveryCodes.Use(ctx, challenge.UserID, req.RecoveryCode)
+	} else {
+		err = h.totp.Verify(ctx, challenge.UserID, req.Code)
+	}
+
+	if err != nil {
+		if errors.Is(err, mfa.ErrInvalidCode) {
+			h.log.Warn("invalid mfa code")
+
+			event := loginEvent(challenge.UserID, method, domain.AuditOutcomeFailure)
+			event.Reason = auditReasonInvalidCode
+			h.auditLog.Record(ctx, event)
+
+			if err = h.challenges.Fail(ctx, req.MFAToken); err != nil {
+				h.log.Error("failed to record failed mfa attempt", logger.Error(err))
+				return httpx.InternalServerError
+			}
+
+			return httpx.NewErrorResponse(http.StatusUnauthorized, "invalid code")
+		}
+
+		if errors.Is(err, mfa.ErrNotEnrolled) {
+			h.log.Warn("mfa is not enabled for the challenged user")
+			return httpx.NewErrorResponse(http.StatusUnauthorized, "invalid or expired mfa token")
+		}
+
+		h.log.Error("failed to verify mfa code", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if err = h.challenges.Complete(ctx, req.MFAToken); err != nil {
+		if errors.Is(err, mfa.ErrInvalidChallenge) {
+			h.log.Warn("mfa challenge was completed concurrently")
+			return httpx.NewErrorResponse(http.StatusUnauthorized, "invalid or expired mfa token")
+		}
+
+		h.log.Error("failed to complete mfa challenge", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if err = h.attempts.Reset(ctx, challenge.UserID); err != nil {
+		h.log.Error("failed to reset mfa attempts", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if req.RecoveryCode != "" {
+		if err = h.auditLog.Save(ctx, domain.AuditEvent{
+			Type:   domain.AuditEventRecoveryCodeUsed,
+			UserID: challenge.UserID,
+		}); err != nil {
+			h.log.Error("failed to save audit event", logger.Error(err))
+			return httpx.InternalServerError
+		}
+	}
+
+	var accessToken string
+	if accessToken, err = h.issuer.IssueAccessToken(challenge.UserID); err != nil {
+		h.log.Error("failed to issue access token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	var refreshToken string
+	if refreshToken, err = h.issuer.IssueRefreshToken(challenge.UserID); err != nil {
+		h.log.Error("failed to issue refresh token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if err = h.jwtStorage.Save(ctx, challenge.UserID, refreshToken); err != nil {
+		h.log.Error("failed to save refresh token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	event := loginEvent(challenge.UserID, method, domain.AuditOutcomeSuccess)
+	event.SessionID = sessionID(refreshToken)
+	h.auditLog.Record(ctx, event)
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&LoginV1Response{
+			UserID:       challenge.UserID,
+			AccessToken:  accessToken,
+			RefreshToken: refreshToken,
+		}),
+	)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+// DefLoginMFAV1Name is the name of the *LoginMFAV1 definition.
+const DefLoginMFAV1Name = "http.login-mfa-v1"
+
+func init() {
+	di.Add(
+		di.Def[*LoginMFAV1]{
+			Name: DefLoginMFAV1Name,
+			Build: func(ctn di.Container) (*LoginMFAV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var issuer *jwt.Issuer
+				if err := ctn.Fill(jwt.DefIssuerName, &issuer); err != nil {
+					return nil, err
+				}
+
+				var storage *jwt.Storage
+				if err := ctn.Fill(jwt.DefStorageName, &storage); err != nil {
+					return nil, err
+				}
+
+				var challenges *mfa.Challenges
+				if err := ctn.Fill(mfa.DefChallengesName, &challenges); err != nil {
+					return nil, err
+				}
+
+				var totp *mfa.TOTP
+				if err := ctn.Fill(mfa.DefTOTPName, &totp); err != nil {
+					return nil, err
+				}
+
+				var recoveryCodes *mfa.RecoveryCodes
+				if err := ctn.Fill(mfa.DefRecoveryCodesName, &recoveryCodes); err != nil {
+					return nil, err
+				}
+
+				var attempts *mfa.Attempts
+				if err := ctn.Fill(mfa.DefAttemptsName, &attempts); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewLoginMFAV1(
+					log,
+					issuer,
+					storage,
+					challenges,
+					totp,
+					recoveryCodes,
+					attempts,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
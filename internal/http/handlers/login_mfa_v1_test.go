@@ -0,0 +1,364 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+func TestLoginMFAV1_Handle(t *testing.T) {
+	validRequest := func() *handlers.LoginMFAV1Request {
+		return &handlers.LoginMFAV1Request{MFAToken: "mfa_token", Code: "123456"}
+	}
+
+	recoveryRequest := func() *handlers.LoginMFAV1Request {
+		return &handlers.LoginMFAV1Request{MFAToken: "mfa_token", RecoveryCode: "AAAA-BBBB-CCCC-DDDD"}
+	}
+
+	challenge := mfa.Challenge{UserID: "user_id"}
+
+	testCases := []struct {
+		name                string
+		req                 func() *handlers.LoginMFAV1Request
+		onGetChallenge      func() (mfa.Challenge, error)
+		onVerify            func() error
+		onUseRecoveryCode   func() error
+		onAddAttempt        func() error
+		onResetAttempts     func() error
+		onSaveAuditEvent    func() error
+		onFail              func() error
+		onComplete          func() error
+		onIssueAccessToken  func() (string, error)
+		onIssueRefreshToken func() (string, error)
+		onSaveRefreshToken  func() error
+		expAuditEvent       *domain.AuditEvent
+		expResp             *httpx.Response
+	}{
+		{
+			name:    "mfa token is missing",
+			req:     func() *handlers.LoginMFAV1Request { return &handlers.LoginMFAV1Request{Code: "123456"} },
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "mfa_token is required"),
+		},
+		{
+			name:    "code is missing",
+			req:     func() *handlers.LoginMFAV1Request { return &handlers.LoginMFAV1Request{MFAToken: "mfa_token"} },
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "code or recovery_code is required"),
+		},
+		{
+			name:           "invalid challenge",
+			req:            validRequest,
+			onGetChallenge: func() (mfa.Challenge, error) { return mfa.Challenge{}, mfa.ErrInvalidChallenge },
+			expResp:        httpx.NewErrorResponse(http.StatusUnauthorized, "invalid or expired mfa token"),
+		},
+		{
+			name:           "failed to get challenge",
+			req:            validRequest,
+			onGetChallenge: func() (mfa.Challenge, error) { return mfa.Challenge{}, assert.AnError },
+			expResp:        httpx.InternalServerError,
+		},
+		{
+			name:           "too many attempts",
+			req:            validRequest,
+			onGetChallenge: func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:   func() error { return mfa.ErrTooManyAttempts },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "too_many_attempts",
+				Details: map[string]string{"method": "totp"},
+			},
+			expResp: httpx.NewErrorResponse(http.StatusTooManyRequests, "too many invalid codes, try again later"),
+		},
+		{
+			name:           "failed to count attempt",
+			req:            validRequest,
+			onGetChallenge: func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:   func() error { return assert.AnError },
+			expResp:        httpx.InternalServerError,
+		},
+		{
+			name:           "invalid code",
+			req:            validRequest,
+			onGetChallenge: func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:   func() error { return nil },
+			onVerify:       func() error { return mfa.ErrInvalidCode },
+			onFail:         func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_code",
+				Details: map[string]string{"method": "totp"},
+			},
+			expResp: httpx.NewErrorResponse(http.StatusUnauthorized, "invalid code"),
+		},
+		{
+			name:           "failed to record failed attempt",
+			req:            validRequest,
+			onGetChallenge: func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:   func() error { return nil },
+			onVerify:       func() error { return mfa.ErrInvalidCode },
+			onFail:         func() error { return assert.AnError },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_code",
+				Details: map[string]string{"method": "totp"},
+			},
+			expResp: httpx.InternalServerError,
+		},
+		{
+			name:           "mfa is not enrolled",
+			req:            validRequest,
+			onGetChallenge: func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:   func() error { return nil },
+			onVerify:       func() error { return mfa.ErrNotEnrolled },
+			expResp:        httpx.NewErrorResponse(http.StatusUnauthorized, "invalid or expired mfa token"),
+		},
+		{
+			name:           "failed to verify code",
+			req:            validRequest,
+			onGetChallenge: func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:   func() error { return nil },
+			onVerify:       func() error { return assert.AnError },
+			expResp:        httpx.InternalServerError,
+		},
+		{
+			name:           "challenge was completed concurrently",
+			req:            validRequest,
+			onGetChallenge: func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:   func() error { return nil },
+			onVerify:       func() error { return nil },
+			onComplete:     func() error { return mfa.ErrInvalidChallenge },
+			expResp:        httpx.NewErrorResponse(http.StatusUnauthorized, "invalid or expired mfa token"),
+		},
+		{
+			name:           "failed to complete challenge",
+			req:            validRequest,
+			onGetChallenge: func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:   func() error { return nil },
+			onVerify:       func() error { return nil },
+			onComplete:     func() error { return assert.AnError },
+			expResp:        httpx.InternalServerError,
+		},
+		{
+			name:            "failed to reset attempts",
+			req:             validRequest,
+			onGetChallenge:  func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:    func() error { return nil },
+			onVerify:        func() error { return nil },
+			onComplete:      func() error { return nil },
+			onResetAttempts: func() error { return assert.AnError },
+			expResp:         httpx.InternalServerError,
+		},
+		{
+			name:              "invalid recovery code",
+			req:               recoveryRequest,
+			onGetChallenge:    func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:      func() error { return nil },
+			onUseRecoveryCode: func() error { return mfa.ErrInvalidCode },
+			onFail:            func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_code",
+				Details: map[string]string{"method": "recovery_code"},
+			},
+			expResp: httpx.NewErrorResponse(http.StatusUnauthorized, "invalid code"),
+		},
+		{
+			name:              "failed to save audit event",
+			req:               recoveryRequest,
+			onGetChallenge:    func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:      func() error { return nil },
+			onUseRecoveryCode: func() error { return nil },
+			onComplete:        func() error { return nil },
+			onResetAttempts:   func() error { return nil },
+			onSaveAuditEvent:  func() error { return assert.AnError },
+			expResp:           httpx.InternalServerError,
+		},
+		{
+			name:               "failed to issue access token",
+			req:                validRequest,
+			onGetChallenge:     func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:       func() error { return nil },
+			onVerify:           func() error { return nil },
+			onComplete:         func() error { return nil },
+			onResetAttempts:    func() error { return nil },
+			onIssueAccessToken: func() (string, error) { return "", assert.AnError },
+			expResp:            httpx.InternalServerError,
+		},
+		{
+			name:                "failed to issue refresh token",
+			req:                 validRequest,
+			onGetChallenge:      func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:        func() error { return nil },
+			onVerify:            func() error { return nil },
+			onComplete:          func() error { return nil },
+			onResetAttempts:     func() error { return nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "", assert.AnError },
+			expResp:             httpx.InternalServerError,
+		},
+		{
+			name:                "failed to save refresh token",
+			req:                 validRequest,
+			onGetChallenge:      func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:        func() error { return nil },
+			onVerify:            func() error { return nil },
+			onComplete:          func() error { return nil },
+			onResetAttempts:     func() error { return nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return assert.AnError },
+			expResp:             httpx.InternalServerError,
+		},
+		{
+			name:                "positive case",
+			req:                 validRequest,
+			onGetChallenge:      func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:        func() error { return nil },
+			onVerify:            func() error { return nil },
+			onComplete:          func() error { return nil },
+			onResetAttempts:     func() error { return nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserID:    "user_id",
+				SessionID: "6c8a7d4aa21708a4",
+				Outcome:   domain.AuditOutcomeSuccess,
+				Details:   map[string]string{"method": "totp"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.LoginV1Response{
+					UserID:       "user_id",
+					AccessToken:  "access_token",
+					RefreshToken: "refresh_token",
+				}),
+			),
+		},
+		{
+			name:                "positive case with recovery code",
+			req:                 recoveryRequest,
+			onGetChallenge:      func() (mfa.Challenge, error) { return challenge, nil },
+			onAddAttempt:        func() error { return nil },
+			onUseRecoveryCode:   func() error { return nil },
+			onComplete:          func() error { return nil },
+			onResetAttempts:     func() error { return nil },
+			onSaveAuditEvent:    func() error { return nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserID:    "user_id",
+				SessionID: "6c8a7d4aa21708a4",
+				Outcome:   domain.AuditOutcomeSuccess,
+				Details:   map[string]string{"method": "recovery_code"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.LoginV1Response{
+					UserID:       "user_id",
+					AccessToken:  "access_token",
+					RefreshToken: "refresh_token",
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := testCase.req()
+
+			challenges := mocks.NewMFAChallenges(t)
+			if testCase.onGetChallenge != nil {
+				challenges.On("Get", t.Context(), req.MFAToken).Return(testCase.onGetChallenge())
+			}
+
+			if testCase.onFail != nil {
+				challenges.On("Fail", t.Context(), req.MFAToken).Return(testCase.onFail())
+			}
+
+			if testCase.onComplete != nil {
+				challenges.On("Complete", t.Context(), req.MFAToken).Return(testCase.onComplete())
+			}
+
+			totp := mocks.NewTOTPVerifier(t)
+			if testCase.onVerify != nil {
+				totp.On("Verify", t.Context(), "user_id", req.Code).Return(testCase.onVerify())
+			}
+
+			recoveryCodes := mocks.NewRecoveryCodeConsumer(t)
+			if testCase.onUseRecoveryCode != nil {
+				recoveryCodes.On("Use", t.Context(), "user_id", req.RecoveryCode).Return(testCase.onUseRecoveryCode())
+			}
+
+			attempts := mocks.NewMFAAttempts(t)
+			if testCase.onAddAttempt != nil {
+				attempts.On("Add", t.Context(), "user_id").Return(testCase.onAddAttempt())
+			}
+
+			if testCase.onResetAttempts != nil {
+				attempts.On("Reset", t.Context(), "user_id").Return(testCase.onResetAttempts())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.onSaveAuditEvent != nil {
+				auditLog.On("Save", t.Context(), domain.AuditEvent{
+					Type:   domain.AuditEventRecoveryCodeUsed,
+					UserID: "user_id",
+				}).Return(testCase.onSaveAuditEvent())
+			}
+
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", t.Context(), *testCase.expAuditEvent).Return()
+			}
+
+			tokenIssuer := mocks.NewTokenIssuer(t)
+			if testCase.onIssueAccessToken != nil {
+				tokenIssuer.On("IssueAccessToken", "user_id").Return(testCase.onIssueAccessToken())
+			}
+
+			var refreshToken string
+			if testCase.onIssueRefreshToken != nil {
+				var err error
+				refreshToken, err = testCase.onIssueRefreshToken()
+
+				tokenIssuer.On("IssueRefreshToken", "user_id").Return(refreshToken, err)
+			}
+
+			jwtStorage := mocks.NewJwtStorage(t)
+			if testCase.onSaveRefreshToken != nil {
+				jwtStorage.On("Save", t.Context(), "user_id", refreshToken).Return(testCase.onSaveRefreshToken())
+			}
+
+			handler := handlers.NewLoginMFAV1(
+				zap.NewNop(),
+				tokenIssuer,
+				jwtStorage,
+				challenges,
+				totp,
+				recoveryCodes,
+				attempts,
+				auditLog,
+			)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), req))
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package handlers
 
-//go:generate mockery --name UserByEmailProvider --output ./mocks --outpkg mocks --filename user_by_email_provider.go --structname UserByEmailProvider
+//go:generate mockery --name CredentialsVerifier --output ./mocks --outpkg mocks --filename credentials_verifier.go --structname CredentialsVerifier
+//go:generate mockery --name MFAStatusProvider --output ./mocks --outpkg mocks --filename mfa_status_provider.go --structname MFAStatusProvider
+//go:generate mockery --name MFAChallengeCreator --output ./mocks --outpkg mocks --filename mfa_challenge_creator.go --structname MFAChallengeCreator
+//go:generate mockery --name DeviceRecognizer --output ./mocks --outpkg mocks --filename device_recognizer.go --structname DeviceRecognizer
+//go:generate mockery --name NewDeviceNotifier --output ./mocks --outpkg mocks --filename new_device_notifier.go --structname NewDeviceNotifier
 
 import (
 	"context"
@@ -9,33 +13,60 @@ import (
 
 	"github.com/riabininkf/go-modules/logger"
 	"github.com/riabininkf/httpx"
-	"golang.org/x/crypto/bcrypt"
 
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/auth"
 	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
 )
 
-// NewLoginV1 creates a new *LoginV1 instance.
+// stepUpVerificationEmail is the verification a login from a new device is stepped up with.
+const stepUpVerificationEmail = "email"
+
+// NewLoginV1 creates a new *LoginV1 instance. If stepUpNewDevices is set, no tokens are issued to devices
+// the user has not recently had sessions from until the user enters a code emailed to them.
 func NewLoginV1(
 	log *logger.Logger,
 	issuer TokenIssuer,
 	jwtStorage JwtStorage,
-	userProvider UserByEmailProvider,
+	credentials CredentialsVerifier,
+	mfaStatus MFAStatusProvider,
+	challenges MFAChallengeCreator,
+	devices DeviceRecognizer,
+	newDeviceNotice NewDeviceNotifier,
+	emailLogin EmailLoginStarter,
+	auditLog AuditRecorder,
+	stepUpNewDevices bool,
 ) *LoginV1 {
 	return &LoginV1{
-		log:          log,
-		issuer:       issuer,
-		jwtStorage:   jwtStorage,
-		userProvider: userProvider,
+		log:              log,
+		issuer:           issuer,
+		jwtStorage:       jwtStorage,
+		credentials:      credentials,
+		mfaStatus:        mfaStatus,
+		challenges:       challenges,
+		devices:          devices,
+		newDeviceNotice:  newDeviceNotice,
+		emailLogin:       emailLogin,
+		auditLog:         auditLog,
+		stepUpNewDevices: stepUpNewDevices,
 	}
 }
 
 type (
 	// LoginV1 handles login requests.
 	LoginV1 struct {
-		log          *logger.Logger
-		issuer       TokenIssuer
-		jwtStorage   JwtStorage
-		userProvider UserByEmailProvider
+		log              *logger.Logger
+		issuer           TokenIssuer
+		jwtStorage       JwtStorage
+		credentials      CredentialsVerifier
+		mfaStatus        MFAStatusProvider
+		challenges       MFAChallengeCreator
+		devices          DeviceRecognizer
+		newDeviceNotice  NewDeviceNotifier
+		emailLogin       EmailLoginStarter
+		auditLog         AuditRecorder
+		stepUpNewDevices bool
 	}
 
 	// LoginV1Request represents login request.
@@ -51,13 +82,51 @@ type (
 		RefreshToken string `json:"refresh_token"`
 	}
 
-	// UserByEmailProvider describes UserByEmailProvider dependency.
-	UserByEmailProvider interface {
-		GetByEmail(ctx context.Context, email string) (domain.User, error)
+	// LoginV1MFAResponse is returned instead of tokens when the user has two-factor authentication enabled.
+	// The login is completed by LoginMFAV1 with the MFA token and a code.
+	LoginV1MFAResponse struct {
+		MFAToken   string   `json:"mfa_token"`
+		MFAMethods []string `json:"mfa_methods"`
+		ExpiresIn  int64    `json:"expires_in"`
+	}
+
+	// LoginV1StepUpResponse is returned instead of tokens to a new device when the step-up policy is enabled.
+	// A code is emailed to the user, and the login is completed by FinishEmailLoginV1 with the login token and the code.
+	LoginV1StepUpResponse struct {
+		LoginToken   string `json:"login_token"`
+		Verification string `json:"verification"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+
+	// CredentialsVerifier describes CredentialsVerifier dependency.
+	CredentialsVerifier interface {
+		Verify(ctx context.Context, email string, password string) (domain.User, error)
+	}
+
+	// MFAStatusProvider describes MFAStatusProvider dependency.
+	MFAStatusProvider interface {
+		IsEnabled(ctx context.Context, userID string) (bool, error)
+	}
+
+	// MFAChallengeCreator describes MFAChallengeCreator dependency.
+	MFAChallengeCreator interface {
+		Create(ctx context.Context, userID string) (mfa.ChallengeToken, error)
+	}
+
+	// DeviceRecognizer describes DeviceRecognizer dependency.
+	DeviceRecognizer interface {
+		IsKnown(ctx context.Context, userID string) (bool, error)
+	}
+
+	// NewDeviceNotifier describes NewDeviceNotifier dependency.
+	NewDeviceNotifier interface {
+		Send(ctx context.Context, user domain.User) error
 	}
 )
 
 // Handle processes a login request, validates credentials, and returns an appropriate HTTP response.
+// Users with two-factor authentication enabled get an MFA challenge instead of tokens. A login from a device
+// none of the recent sessions of the user came from is recorded and the user is notified by email.
 func (h *LoginV1) Handle(ctx context.Context, req *LoginV1Request) *httpx.Response {
 	if req.Email == "" {
 		h.log.Warn("email is missing")
@@ -73,26 +142,75 @@ func (h *LoginV1) Handle(ctx context.Context, req *LoginV1Request) *httpx.Respon
 		err  error
 		user domain.User
 	)
-	if user, err = h.userProvider.GetByEmail(ctx, req.Email); err != nil {
-		if errors.Is(err, domain.ErrUserNotFound) {
-			h.log.Warn("invalid email")
+	if user, err = h.credentials.Verify(ctx, req.Email, req.Password); err != nil {
+		if event, ok := failedLoginEvent(err); ok {
+			h.auditLog.Record(ctx, event)
+		}
+
+		// a locked account is answered like a wrong password, so that it does not reveal the email is registered;
+		// the owner learns about the lockout from the email sent when it happened
+		if errors.Is(err, auth.ErrInvalidCredentials) || errors.Is(err, auth.ErrAccountLocked) {
 			return httpx.NewErrorResponse(http.StatusUnauthorized, "invalid email or password")
 		}
 
-		h.log.Error("failed to get user by email", logger.Error(err))
+		if errors.Is(err, auth.ErrEmailNotVerified) {
+			return httpx.NewErrorResponse(http.StatusForbidden, "email is not verified")
+		}
+
+		if isHashingBusy(err) {
+			h.log.Warn("password hashing is saturated")
+			return hashingBusyResponse
+		}
+
+		h.log.Error("failed to verify credentials", logger.Error(err))
 		return httpx.InternalServerError
 	}
 
-	if err = bcrypt.CompareHashAndPassword([]byte(user.HashedPassword()), []byte(req.Password)); err != nil {
-		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			h.log.Warn("invalid password")
-			return httpx.NewErrorResponse(http.StatusUnauthorized, "invalid email or password")
+	var knownDevice bool
+	if knownDevice, err = h.devices.IsKnown(ctx, user.ID()); err != nil {
+		h.log.Error("failed to recognize device", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if !knownDevice {
+		h.auditLog.Record(ctx, domain.AuditEvent{Type: domain.AuditEventNewDevice, UserID: user.ID()})
+
+		// the notice is only a heads-up, the user is not locked out of their account if it cannot be sent
+		if err = h.newDeviceNotice.Send(ctx, user); err != nil {
+			h.log.Error("failed to send new device notice", logger.Error(err))
 		}
+	}
 
-		h.log.Error("failed to compare password", logger.Error(err))
+	var mfaEnabled bool
+	if mfaEnabled, err = h.mfaStatus.IsEnabled(ctx, user.ID()); err != nil {
+		h.log.Error("failed to check mfa status", logger.Error(err))
 		return httpx.InternalServerError
 	}
 
+	if mfaEnabled {
+		var challenge mfa.ChallengeToken
+		if challenge, err = h.challenges.Create(ctx, user.ID()); err != nil {
+			h.log.Error("failed to create mfa challenge", logger.Error(err))
+			return httpx.InternalServerError
+		}
+
+		h.auditLog.Record(ctx, loginEvent(user.ID(), loginMethodPassword, domain.AuditOutcomeChallenged))
+
+		return httpx.NewJsonResponse(
+			httpx.WithStatus(http.StatusAccepted),
+			httpx.WithBody(&LoginV1MFAResponse{
+				MFAToken:   challenge.Token,
+				MFAMethods: []string{mfa.MethodTOTP, mfa.MethodRecoveryCode},
+				ExpiresIn:  int64(challenge.ExpiresIn.Seconds()),
+			}),
+		)
+	}
+
+	// the second factor verifies the device already, so the step-up applies only to users without one
+	if !knownDevice && h.stepUpNewDevices {
+		return h.stepUp(ctx, user)
+	}
+
 	var accessToken string
 	if accessToken, err = h.issuer.IssueAccessToken(user.ID()); err != nil {
 		h.log.Error("failed to issue access token", logger.Error(err))
@@ -105,11 +223,15 @@ func (h *LoginV1) Handle(ctx context.Context, req *LoginV1Request) *httpx.Respon
 		return httpx.InternalServerError
 	}
 
-	if err = h.jwtStorage.Save(ctx, refreshToken); err != nil {
+	if err = h.jwtStorage.Save(ctx, user.ID(), refreshToken); err != nil {
 		h.log.Error("failed to save refresh token", logger.Error(err))
 		return httpx.InternalServerError
 	}
 
+	event := loginEvent(user.ID(), loginMethodPassword, domain.AuditOutcomeSuccess)
+	event.SessionID = sessionID(refreshToken)
+	h.auditLog.Record(ctx, event)
+
 	return httpx.NewJsonResponse(
 		httpx.WithStatus(http.StatusOK),
 		httpx.WithBody(&LoginV1Response{
@@ -119,3 +241,28 @@ func (h *LoginV1) Handle(ctx context.Context, req *LoginV1Request) *httpx.Respon
 		}),
 	)
 }
+
+// stepUp emails the user a code that completes the login from a new device instead of issuing tokens.
+func (h *LoginV1) stepUp(ctx context.Context, user domain.User) *httpx.Response {
+	token, err := h.emailLogin.Start(ctx, user.Email())
+	if err != nil {
+		if errors.Is(err, account.ErrTooManyRequests) {
+			h.log.Warn("email login was started too recently")
+			return httpx.NewErrorResponse(http.StatusTooManyRequests, "too many requests")
+		}
+
+		h.log.Error("failed to start email login", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	h.auditLog.Record(ctx, loginEvent(user.ID(), loginMethodPassword, domain.AuditOutcomeChallenged))
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusAccepted),
+		httpx.WithBody(&LoginV1StepUpResponse{
+			LoginToken:   token.Token,
+			Verification: stepUpVerificationEmail,
+			ExpiresIn:    int64(token.ExpiresIn.Seconds()),
+		}),
+	)
+}
@@ -1,21 +1,34 @@
 package handlers
 
 import (
+	"github.com/riabininkf/go-modules/config"
 	"github.com/riabininkf/go-modules/di"
 	"github.com/riabininkf/go-modules/logger"
 
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/auth"
 	"github.com/riabininkf/http-auth-example/internal/jwt"
-	"github.com/riabininkf/http-auth-example/internal/repository"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
 )
 
-// DefLoginV1Name is the name of the *LoginV1 definition.
-const DefLoginV1Name = "http.login-v1"
+const (
+	// DefLoginV1Name is the name of the *LoginV1 definition.
+	DefLoginV1Name = "http.login-v1"
+
+	configKeyNewDeviceStepUp = "auth.newDevice.stepUp"
+)
 
 func init() {
 	di.Add(
 		di.Def[*LoginV1]{
 			Name: DefLoginV1Name,
 			Build: func(ctn di.Container) (*LoginV1, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
 				var log *logger.Logger
 				if err := ctn.Fill(logger.DefName, &log); err != nil {
 					return nil, err
@@ -26,8 +39,8 @@ func init() {
 					return nil, err
 				}
 
-				var usersRep *repository.Users
-				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+				var credentials *auth.Credentials
+				if err := ctn.Fill(auth.DefCredentialsName, &credentials); err != nil {
 					return nil, err
 				}
 
@@ -36,11 +49,48 @@ func init() {
 					return nil, err
 				}
 
+				var totp *mfa.TOTP
+				if err := ctn.Fill(mfa.DefTOTPName, &totp); err != nil {
+					return nil, err
+				}
+
+				var challenges *mfa.Challenges
+				if err := ctn.Fill(mfa.DefChallengesName, &challenges); err != nil {
+					return nil, err
+				}
+
+				var devices *audit.Devices
+				if err := ctn.Fill(audit.DefDevicesName, &devices); err != nil {
+					return nil, err
+				}
+
+				var newDeviceNotice *account.NewDeviceNotice
+				if err := ctn.Fill(account.DefNewDeviceNoticeName, &newDeviceNotice); err != nil {
+					return nil, err
+				}
+
+				var emailLogin *account.EmailLogin
+				if err := ctn.Fill(account.DefEmailLoginName, &emailLogin); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
 				return NewLoginV1(
 					log,
 					issuer,
 					storage,
-					usersRep,
+					credentials,
+					totp,
+					challenges,
+					devices,
+					newDeviceNotice,
+					emailLogin,
+					recorder,
+					cfg.GetBool(configKeyNewDeviceStepUp),
 				), nil
 			},
 		},
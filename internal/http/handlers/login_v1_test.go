@@ -1,35 +1,27 @@
 package handlers_test
 
 import (
+	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/brianvoe/gofakeit/v7"
 	"github.com/google/uuid"
 	"github.com/riabininkf/httpx"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
-	"golang.org/x/crypto/bcrypt"
 
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/auth"
 	"github.com/riabininkf/http-auth-example/internal/domain"
 	"github.com/riabininkf/http-auth-example/internal/http/handlers"
 	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+	"github.com/riabininkf/http-auth-example/internal/password"
 )
 
 func TestLoginV1_Handle(t *testing.T) {
-	generatePasswordHash := func(t *testing.T, password string) string {
-		t.Helper()
-		var (
-			err            error
-			bcryptPassword []byte
-		)
-		if bcryptPassword, err = bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost); err != nil {
-			t.Fatal(err)
-		}
-
-		return string(bcryptPassword)
-	}
-
 	generateRequest := func() *handlers.LoginV1Request {
 		return &handlers.LoginV1Request{Email: gofakeit.Email(), Password: gofakeit.Name()}
 	}
@@ -37,10 +29,18 @@ func TestLoginV1_Handle(t *testing.T) {
 	testCases := []struct {
 		name                string
 		req                 func() *handlers.LoginV1Request
-		onGetByEmail        func(req *handlers.LoginV1Request) (domain.User, error)
+		onVerifyCredentials func(req *handlers.LoginV1Request) (domain.User, error)
+		onIsKnownDevice     func() (bool, error)
+		onSendNotice        func() error
+		onIsMFAEnabled      func() (bool, error)
+		onCreateChallenge   func() (mfa.ChallengeToken, error)
+		onStartEmailLogin   func() (account.EmailLoginToken, error)
+		stepUpNewDevices    bool
 		onIssueAccessToken  func() (string, error)
 		onIssueRefreshToken func() (string, error)
 		onSaveRefreshToken  func() error
+		expNewDevice        bool
+		expAuditEvent       *domain.AuditEvent
 		expResp             *httpx.Response
 	}{
 		{
@@ -54,42 +54,332 @@ func TestLoginV1_Handle(t *testing.T) {
 			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "password is required"),
 		},
 		{
-			name: "user not found",
+			name: "invalid credentials",
 			req:  generateRequest,
-			onGetByEmail: func(req *handlers.LoginV1Request) (domain.User, error) {
-				return nil, domain.ErrUserNotFound
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return nil, auth.ErrInvalidCredentials
+			},
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_credentials",
+				Details: map[string]string{"method": "password"},
 			},
 			expResp: httpx.NewErrorResponse(http.StatusUnauthorized, "invalid email or password"),
 		},
 		{
-			name:         "failed to get user by email",
-			req:          generateRequest,
-			onGetByEmail: func(req *handlers.LoginV1Request) (domain.User, error) { return nil, assert.AnError },
-			expResp:      httpx.InternalServerError,
+			name: "invalid password of a registered user",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return nil, &auth.UserError{UserID: "user_id", Err: auth.ErrInvalidCredentials}
+			},
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_credentials",
+				Details: map[string]string{"method": "password"},
+			},
+			expResp: httpx.NewErrorResponse(http.StatusUnauthorized, "invalid email or password"),
 		},
 		{
-			name: "invalid password",
+			name: "email is not verified",
 			req:  generateRequest,
-			onGetByEmail: func(req *handlers.LoginV1Request) (domain.User, error) {
-				return domain.NewUser(uuid.NewString(), req.Email, generatePasswordHash(t, gofakeit.Name())), nil
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return nil, &auth.UserError{UserID: "user_id", Err: auth.ErrEmailNotVerified}
+			},
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "email_not_verified",
+				Details: map[string]string{"method": "password"},
+			},
+			expResp: httpx.NewErrorResponse(http.StatusForbidden, "email is not verified"),
+		},
+		{
+			name: "account is locked",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return nil, &auth.UserError{
+					UserID: "user_id",
+					Err:    &auth.AccountLockedError{Until: time.Now().Add(time.Minute)},
+				}
+			},
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "account_locked",
+				Details: map[string]string{"method": "password"},
 			},
 			expResp: httpx.NewErrorResponse(http.StatusUnauthorized, "invalid email or password"),
 		},
+		{
+			name: "password hashing is saturated",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return nil, fmt.Errorf("failed to compare password: %w", password.ErrBusy)
+			},
+			expResp: httpx.NewErrorResponse(http.StatusServiceUnavailable, "server is busy, try again later"),
+		},
+		{
+			name:                "failed to verify credentials",
+			req:                 generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) { return nil, assert.AnError },
+			expResp:             httpx.InternalServerError,
+		},
+		{
+			name: "failed to recognize device",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser(uuid.NewString(), req.Email, "hashed_password"), nil
+			},
+			onIsKnownDevice: func() (bool, error) { return false, assert.AnError },
+			expResp:         httpx.InternalServerError,
+		},
+		{
+			name: "new device",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser("user_id", req.Email, "hashed_password"), nil
+			},
+			onIsKnownDevice:     func() (bool, error) { return false, nil },
+			onSendNotice:        func() error { return nil },
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return nil },
+			expNewDevice:        true,
+			expAuditEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserID:    "user_id",
+				SessionID: "6c8a7d4aa21708a4",
+				Outcome:   domain.AuditOutcomeSuccess,
+				Details:   map[string]string{"method": "password"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.LoginV1Response{
+					UserID:       "user_id",
+					AccessToken:  "access_token",
+					RefreshToken: "refresh_token",
+				}),
+			),
+		},
+		{
+			name: "failed to send new device notice",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser("user_id", req.Email, "hashed_password"), nil
+			},
+			onIsKnownDevice:     func() (bool, error) { return false, nil },
+			onSendNotice:        func() error { return assert.AnError },
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return nil },
+			expNewDevice:        true,
+			expAuditEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserID:    "user_id",
+				SessionID: "6c8a7d4aa21708a4",
+				Outcome:   domain.AuditOutcomeSuccess,
+				Details:   map[string]string{"method": "password"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.LoginV1Response{
+					UserID:       "user_id",
+					AccessToken:  "access_token",
+					RefreshToken: "refresh_token",
+				}),
+			),
+		},
+		{
+			name: "new device of a user with mfa enabled",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser("user_id", req.Email, "hashed_password"), nil
+			},
+			onIsKnownDevice: func() (bool, error) { return false, nil },
+			onSendNotice:    func() error { return nil },
+			onIsMFAEnabled:  func() (bool, error) { return true, nil },
+			onCreateChallenge: func() (mfa.ChallengeToken, error) {
+				return mfa.ChallengeToken{Token: "mfa_token", ExpiresIn: 5 * time.Minute}, nil
+			},
+			stepUpNewDevices: true,
+			expNewDevice:     true,
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeChallenged,
+				Details: map[string]string{"method": "password"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusAccepted),
+				httpx.WithBody(&handlers.LoginV1MFAResponse{
+					MFAToken:   "mfa_token",
+					MFAMethods: []string{"totp", "recovery_code"},
+					ExpiresIn:  300,
+				}),
+			),
+		},
+		{
+			name: "new device is stepped up",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser("user_id", req.Email, "hashed_password"), nil
+			},
+			onIsKnownDevice: func() (bool, error) { return false, nil },
+			onSendNotice:    func() error { return nil },
+			onIsMFAEnabled:  func() (bool, error) { return false, nil },
+			onStartEmailLogin: func() (account.EmailLoginToken, error) {
+				return account.EmailLoginToken{Token: "login_token", ExpiresIn: 10 * time.Minute}, nil
+			},
+			stepUpNewDevices: true,
+			expNewDevice:     true,
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeChallenged,
+				Details: map[string]string{"method": "password"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusAccepted),
+				httpx.WithBody(&handlers.LoginV1StepUpResponse{
+					LoginToken:   "login_token",
+					Verification: "email",
+					ExpiresIn:    600,
+				}),
+			),
+		},
+		{
+			name: "step-up email login was started too recently",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser("user_id", req.Email, "hashed_password"), nil
+			},
+			onIsKnownDevice: func() (bool, error) { return false, nil },
+			onSendNotice:    func() error { return nil },
+			onIsMFAEnabled:  func() (bool, error) { return false, nil },
+			onStartEmailLogin: func() (account.EmailLoginToken, error) {
+				return account.EmailLoginToken{}, account.ErrTooManyRequests
+			},
+			stepUpNewDevices: true,
+			expNewDevice:     true,
+			expResp:          httpx.NewErrorResponse(http.StatusTooManyRequests, "too many requests"),
+		},
+		{
+			name: "failed to start step-up email login",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser("user_id", req.Email, "hashed_password"), nil
+			},
+			onIsKnownDevice: func() (bool, error) { return false, nil },
+			onSendNotice:    func() error { return nil },
+			onIsMFAEnabled:  func() (bool, error) { return false, nil },
+			onStartEmailLogin: func() (account.EmailLoginToken, error) {
+				return account.EmailLoginToken{}, assert.AnError
+			},
+			stepUpNewDevices: true,
+			expNewDevice:     true,
+			expResp:          httpx.InternalServerError,
+		},
+		{
+			name: "known device is not stepped up",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser("user_id", req.Email, "hashed_password"), nil
+			},
+			onIsKnownDevice:     func() (bool, error) { return true, nil },
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
+			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:  func() error { return nil },
+			stepUpNewDevices:    true,
+			expAuditEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserID:    "user_id",
+				SessionID: "6c8a7d4aa21708a4",
+				Outcome:   domain.AuditOutcomeSuccess,
+				Details:   map[string]string{"method": "password"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.LoginV1Response{
+					UserID:       "user_id",
+					AccessToken:  "access_token",
+					RefreshToken: "refresh_token",
+				}),
+			),
+		},
+		{
+			name: "failed to check mfa status",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser(uuid.NewString(), req.Email, "hashed_password"), nil
+			},
+			onIsKnownDevice: func() (bool, error) { return true, nil },
+			onIsMFAEnabled:  func() (bool, error) { return false, assert.AnError },
+			expResp:         httpx.InternalServerError,
+		},
+		{
+			name: "failed to create mfa challenge",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser(uuid.NewString(), req.Email, "hashed_password"), nil
+			},
+			onIsKnownDevice:   func() (bool, error) { return true, nil },
+			onIsMFAEnabled:    func() (bool, error) { return true, nil },
+			onCreateChallenge: func() (mfa.ChallengeToken, error) { return mfa.ChallengeToken{}, assert.AnError },
+			expResp:           httpx.InternalServerError,
+		},
+		{
+			name: "mfa is enabled",
+			req:  generateRequest,
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser("user_id", req.Email, "hashed_password"), nil
+			},
+			onIsKnownDevice: func() (bool, error) { return true, nil },
+			onIsMFAEnabled:  func() (bool, error) { return true, nil },
+			onCreateChallenge: func() (mfa.ChallengeToken, error) {
+				return mfa.ChallengeToken{Token: "mfa_token", ExpiresIn: 5 * time.Minute}, nil
+			},
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventLogin,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeChallenged,
+				Details: map[string]string{"method": "password"},
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusAccepted),
+				httpx.WithBody(&handlers.LoginV1MFAResponse{
+					MFAToken:   "mfa_token",
+					MFAMethods: []string{"totp", "recovery_code"},
+					ExpiresIn:  300,
+				}),
+			),
+		},
 		{
 			name: "failed to issue access token",
 			req:  generateRequest,
-			onGetByEmail: func(req *handlers.LoginV1Request) (domain.User, error) {
-				return domain.NewUser(uuid.NewString(), req.Email, generatePasswordHash(t, req.Password)), nil
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser(uuid.NewString(), req.Email, "hashed_password"), nil
 			},
+			onIsKnownDevice:    func() (bool, error) { return true, nil },
+			onIsMFAEnabled:     func() (bool, error) { return false, nil },
 			onIssueAccessToken: func() (string, error) { return "", assert.AnError },
 			expResp:            httpx.InternalServerError,
 		},
 		{
 			name: "failed to issue refresh token",
 			req:  generateRequest,
-			onGetByEmail: func(req *handlers.LoginV1Request) (domain.User, error) {
-				return domain.NewUser(uuid.NewString(), req.Email, generatePasswordHash(t, req.Password)), nil
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser(uuid.NewString(), req.Email, "hashed_password"), nil
 			},
+			onIsKnownDevice:     func() (bool, error) { return true, nil },
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
 			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
 			onIssueRefreshToken: func() (string, error) { return "", assert.AnError },
 			expResp:             httpx.InternalServerError,
@@ -97,9 +387,11 @@ func TestLoginV1_Handle(t *testing.T) {
 		{
 			name: "failed to save refresh token",
 			req:  generateRequest,
-			onGetByEmail: func(req *handlers.LoginV1Request) (domain.User, error) {
-				return domain.NewUser(uuid.NewString(), req.Email, generatePasswordHash(t, req.Password)), nil
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser(uuid.NewString(), req.Email, "hashed_password"), nil
 			},
+			onIsKnownDevice:     func() (bool, error) { return true, nil },
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
 			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
 			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
 			onSaveRefreshToken:  func() error { return assert.AnError },
@@ -108,12 +400,21 @@ func TestLoginV1_Handle(t *testing.T) {
 		{
 			name: "positive case",
 			req:  generateRequest,
-			onGetByEmail: func(req *handlers.LoginV1Request) (domain.User, error) {
-				return domain.NewUser("user_id", req.Email, generatePasswordHash(t, req.Password)), nil
+			onVerifyCredentials: func(req *handlers.LoginV1Request) (domain.User, error) {
+				return domain.NewUser("user_id", req.Email, "hashed_password"), nil
 			},
+			onIsKnownDevice:     func() (bool, error) { return true, nil },
+			onIsMFAEnabled:      func() (bool, error) { return false, nil },
 			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
 			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
 			onSaveRefreshToken:  func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventLogin,
+				UserID:    "user_id",
+				SessionID: "6c8a7d4aa21708a4",
+				Outcome:   domain.AuditOutcomeSuccess,
+				Details:   map[string]string{"method": "password"},
+			},
 			expResp: httpx.NewJsonResponse(
 				httpx.WithStatus(http.StatusOK),
 				httpx.WithBody(&handlers.LoginV1Response{
@@ -129,17 +430,42 @@ func TestLoginV1_Handle(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			req := testCase.req()
 
-			userProvider := mocks.NewUserByEmailProvider(t)
+			credentials := mocks.NewCredentialsVerifier(t)
 
 			var user domain.User
-			if testCase.onGetByEmail != nil {
+			if testCase.onVerifyCredentials != nil {
 				var err error
-				user, err = testCase.onGetByEmail(req)
+				user, err = testCase.onVerifyCredentials(req)
 
-				userProvider.On("GetByEmail", t.Context(), req.Email).
+				credentials.On("Verify", t.Context(), req.Email, req.Password).
 					Return(user, err)
 			}
 
+			mfaStatus := mocks.NewMFAStatusProvider(t)
+			if testCase.onIsMFAEnabled != nil {
+				mfaStatus.On("IsEnabled", t.Context(), user.ID()).Return(testCase.onIsMFAEnabled())
+			}
+
+			challenges := mocks.NewMFAChallengeCreator(t)
+			if testCase.onCreateChallenge != nil {
+				challenges.On("Create", t.Context(), user.ID()).Return(testCase.onCreateChallenge())
+			}
+
+			devices := mocks.NewDeviceRecognizer(t)
+			if testCase.onIsKnownDevice != nil {
+				devices.On("IsKnown", t.Context(), user.ID()).Return(testCase.onIsKnownDevice())
+			}
+
+			newDeviceNotice := mocks.NewNewDeviceNotifier(t)
+			if testCase.onSendNotice != nil {
+				newDeviceNotice.On("Send", t.Context(), user).Return(testCase.onSendNotice())
+			}
+
+			emailLogin := mocks.NewEmailLoginStarter(t)
+			if testCase.onStartEmailLogin != nil {
+				emailLogin.On("Start", t.Context(), user.Email()).Return(testCase.onStartEmailLogin())
+			}
+
 			tokenIssuer := mocks.NewTokenIssuer(t)
 			if testCase.onIssueAccessToken != nil {
 				tokenIssuer.On("IssueAccessToken", user.ID()).Return(testCase.onIssueAccessToken())
@@ -155,14 +481,30 @@ func TestLoginV1_Handle(t *testing.T) {
 
 			jwtStorage := mocks.NewJwtStorage(t)
 			if testCase.onSaveRefreshToken != nil {
-				jwtStorage.On("Save", t.Context(), refreshToken).Return(testCase.onSaveRefreshToken())
+				jwtStorage.On("Save", t.Context(), user.ID(), refreshToken).Return(testCase.onSaveRefreshToken())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expNewDevice {
+				auditLog.On("Record", t.Context(), domain.AuditEvent{Type: domain.AuditEventNewDevice, UserID: user.ID()}).Return()
+			}
+
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", t.Context(), *testCase.expAuditEvent).Return()
 			}
 
 			handler := handlers.NewLoginV1(
 				zap.NewNop(),
 				tokenIssuer,
 				jwtStorage,
-				userProvider,
+				credentials,
+				mfaStatus,
+				challenges,
+				devices,
+				newDeviceNotice,
+				emailLogin,
+				auditLog,
+				testCase.stepUpNewDevices,
 			)
 
 			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), req))
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	jwt "github.com/riabininkf/http-auth-example/internal/jwt"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccessClaimsVerifier is an autogenerated mock type for the AccessClaimsVerifier type
+type AccessClaimsVerifier struct {
+	mock.Mock
+}
+
+// VerifyAccessClaims provides a mock function with given fields: ctx, token
+func (_m *AccessClaimsVerifier) VerifyAccessClaims(ctx context.Context, token string) (jwt.AccessClaims, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for VerifyAccessClaims")
+	}
+
+	var r0 jwt.AccessClaims
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (jwt.AccessClaims, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) jwt.AccessClaims); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Get(0).(jwt.AccessClaims)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAccessClaimsVerifier creates a new instance of AccessClaimsVerifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccessClaimsVerifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccessClaimsVerifier {
+	mock := &AccessClaimsVerifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
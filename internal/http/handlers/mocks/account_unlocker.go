@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AccountUnlocker is an autogenerated mock type for the AccountUnlocker type
+type AccountUnlocker struct {
+	mock.Mock
+}
+
+// Unlock provides a mock function with given fields: ctx, userID
+func (_m *AccountUnlocker) Unlock(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Unlock")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAccountUnlocker creates a new instance of AccountUnlocker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAccountUnlocker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AccountUnlocker {
+	mock := &AccountUnlocker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
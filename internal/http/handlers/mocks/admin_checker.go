@@ -0,0 +1,42 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// AdminChecker is an autogenerated mock type for the AdminChecker type
+type AdminChecker struct {
+	mock.Mock
+}
+
+// IsAdmin provides a mock function with given fields: userID
+func (_m *AdminChecker) IsAdmin(userID string) bool {
+	ret := _m.Called(userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsAdmin")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// NewAdminChecker creates a new instance of AdminChecker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAdminChecker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AdminChecker {
+	mock := &AdminChecker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,56 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AssertionVerifier is an autogenerated mock type for the AssertionVerifier type
+type AssertionVerifier struct {
+	mock.Mock
+}
+
+// Verify provides a mock function with given fields: ctx, assertion
+func (_m *AssertionVerifier) Verify(ctx context.Context, assertion string) (string, error) {
+	ret := _m.Called(ctx, assertion)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, assertion)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, assertion)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, assertion)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAssertionVerifier creates a new instance of AssertionVerifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAssertionVerifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AssertionVerifier {
+	mock := &AssertionVerifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
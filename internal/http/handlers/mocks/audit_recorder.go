@@ -0,0 +1,53 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// AuditRecorder is an autogenerated mock type for the AuditRecorder type
+type AuditRecorder struct {
+	mock.Mock
+}
+
+// Record provides a mock function with given fields: ctx, event
+func (_m *AuditRecorder) Record(ctx context.Context, event domain.AuditEvent) {
+	_m.Called(ctx, event)
+}
+
+// Save provides a mock function with given fields: ctx, event
+func (_m *AuditRecorder) Save(ctx context.Context, event domain.AuditEvent) error {
+	ret := _m.Called(ctx, event)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AuditEvent) error); ok {
+		r0 = rf(ctx, event)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewAuditRecorder creates a new instance of AuditRecorder. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuditRecorder(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuditRecorder {
+	mock := &AuditRecorder{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	oauth "github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// AuthorizationCodeIssuer is an autogenerated mock type for the AuthorizationCodeIssuer type
+type AuthorizationCodeIssuer struct {
+	mock.Mock
+}
+
+// Issue provides a mock function with given fields: ctx, grant
+func (_m *AuthorizationCodeIssuer) Issue(ctx context.Context, grant oauth.AuthorizationCode) (string, error) {
+	ret := _m.Called(ctx, grant)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Issue")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, oauth.AuthorizationCode) (string, error)); ok {
+		return rf(ctx, grant)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, oauth.AuthorizationCode) string); ok {
+		r0 = rf(ctx, grant)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, oauth.AuthorizationCode) error); ok {
+		r1 = rf(ctx, grant)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAuthorizationCodeIssuer creates a new instance of AuthorizationCodeIssuer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthorizationCodeIssuer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuthorizationCodeIssuer {
+	mock := &AuthorizationCodeIssuer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
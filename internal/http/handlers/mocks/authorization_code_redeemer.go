@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	oauth "github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// AuthorizationCodeRedeemer is an autogenerated mock type for the AuthorizationCodeRedeemer type
+type AuthorizationCodeRedeemer struct {
+	mock.Mock
+}
+
+// Redeem provides a mock function with given fields: ctx, code
+func (_m *AuthorizationCodeRedeemer) Redeem(ctx context.Context, code string) (oauth.AuthorizationCode, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Redeem")
+	}
+
+	var r0 oauth.AuthorizationCode
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (oauth.AuthorizationCode, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) oauth.AuthorizationCode); ok {
+		r0 = rf(ctx, code)
+	} else {
+		r0 = ret.Get(0).(oauth.AuthorizationCode)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewAuthorizationCodeRedeemer creates a new instance of AuthorizationCodeRedeemer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewAuthorizationCodeRedeemer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *AuthorizationCodeRedeemer {
+	mock := &AuthorizationCodeRedeemer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	oauth "github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// DeviceAuthorizationStarter is an autogenerated mock type for the DeviceAuthorizationStarter type
+type DeviceAuthorizationStarter struct {
+	mock.Mock
+}
+
+// Start provides a mock function with given fields: ctx, clientID
+func (_m *DeviceAuthorizationStarter) Start(ctx context.Context, clientID string) (oauth.DeviceAuthorization, error) {
+	ret := _m.Called(ctx, clientID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 oauth.DeviceAuthorization
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (oauth.DeviceAuthorization, error)); ok {
+		return rf(ctx, clientID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) oauth.DeviceAuthorization); ok {
+		r0 = rf(ctx, clientID)
+	} else {
+		r0 = ret.Get(0).(oauth.DeviceAuthorization)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, clientID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDeviceAuthorizationStarter creates a new instance of DeviceAuthorizationStarter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeviceAuthorizationStarter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeviceAuthorizationStarter {
+	mock := &DeviceAuthorizationStarter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	oauth "github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// DeviceGrantPoller is an autogenerated mock type for the DeviceGrantPoller type
+type DeviceGrantPoller struct {
+	mock.Mock
+}
+
+// Poll provides a mock function with given fields: ctx, deviceCode
+func (_m *DeviceGrantPoller) Poll(ctx context.Context, deviceCode string) (oauth.DeviceGrant, error) {
+	ret := _m.Called(ctx, deviceCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Poll")
+	}
+
+	var r0 oauth.DeviceGrant
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (oauth.DeviceGrant, error)); ok {
+		return rf(ctx, deviceCode)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) oauth.DeviceGrant); ok {
+		r0 = rf(ctx, deviceCode)
+	} else {
+		r0 = ret.Get(0).(oauth.DeviceGrant)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, deviceCode)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewDeviceGrantPoller creates a new instance of DeviceGrantPoller. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeviceGrantPoller(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeviceGrantPoller {
+	mock := &DeviceGrantPoller{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
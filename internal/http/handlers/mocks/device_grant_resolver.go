@@ -0,0 +1,64 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// DeviceGrantResolver is an autogenerated mock type for the DeviceGrantResolver type
+type DeviceGrantResolver struct {
+	mock.Mock
+}
+
+// Approve provides a mock function with given fields: ctx, userCode, userID
+func (_m *DeviceGrantResolver) Approve(ctx context.Context, userCode string, userID string) error {
+	ret := _m.Called(ctx, userCode, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Approve")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userCode, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Deny provides a mock function with given fields: ctx, userCode
+func (_m *DeviceGrantResolver) Deny(ctx context.Context, userCode string) error {
+	ret := _m.Called(ctx, userCode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Deny")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userCode)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewDeviceGrantResolver creates a new instance of DeviceGrantResolver. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewDeviceGrantResolver(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *DeviceGrantResolver {
+	mock := &DeviceGrantResolver{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
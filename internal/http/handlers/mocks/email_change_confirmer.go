@@ -0,0 +1,76 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	account "github.com/riabininkf/http-auth-example/internal/account"
+
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailChangeConfirmer is an autogenerated mock type for the EmailChangeConfirmer type
+type EmailChangeConfirmer struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: ctx, token
+func (_m *EmailChangeConfirmer) Confirm(ctx context.Context, token string) (account.EmailChanged, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Confirm")
+	}
+
+	var r0 account.EmailChanged
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (account.EmailChanged, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) account.EmailChanged); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Get(0).(account.EmailChanged)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SendUndo provides a mock function with given fields: ctx, changed
+func (_m *EmailChangeConfirmer) SendUndo(ctx context.Context, changed account.EmailChanged) error {
+	ret := _m.Called(ctx, changed)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SendUndo")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, account.EmailChanged) error); ok {
+		r0 = rf(ctx, changed)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEmailChangeConfirmer creates a new instance of EmailChangeConfirmer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailChangeConfirmer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailChangeConfirmer {
+	mock := &EmailChangeConfirmer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
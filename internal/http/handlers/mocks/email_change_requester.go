@@ -0,0 +1,48 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailChangeRequester is an autogenerated mock type for the EmailChangeRequester type
+type EmailChangeRequester struct {
+	mock.Mock
+}
+
+// Request provides a mock function with given fields: ctx, user, newEmail
+func (_m *EmailChangeRequester) Request(ctx context.Context, user domain.User, newEmail string) error {
+	ret := _m.Called(ctx, user, newEmail)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Request")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User, string) error); ok {
+		r0 = rf(ctx, user, newEmail)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEmailChangeRequester creates a new instance of EmailChangeRequester. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailChangeRequester(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailChangeRequester {
+	mock := &EmailChangeRequester{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,56 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailLoginCompleter is an autogenerated mock type for the EmailLoginCompleter type
+type EmailLoginCompleter struct {
+	mock.Mock
+}
+
+// Complete provides a mock function with given fields: ctx, token, code
+func (_m *EmailLoginCompleter) Complete(ctx context.Context, token string, code string) (string, error) {
+	ret := _m.Called(ctx, token, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (string, error)); ok {
+		return rf(ctx, token, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) string); ok {
+		r0 = rf(ctx, token, code)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, token, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEmailLoginCompleter creates a new instance of EmailLoginCompleter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailLoginCompleter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailLoginCompleter {
+	mock := &EmailLoginCompleter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	account "github.com/riabininkf/http-auth-example/internal/account"
+
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailLoginStarter is an autogenerated mock type for the EmailLoginStarter type
+type EmailLoginStarter struct {
+	mock.Mock
+}
+
+// Start provides a mock function with given fields: ctx, email
+func (_m *EmailLoginStarter) Start(ctx context.Context, email string) (account.EmailLoginToken, error) {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Start")
+	}
+
+	var r0 account.EmailLoginToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (account.EmailLoginToken, error)); ok {
+		return rf(ctx, email)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) account.EmailLoginToken); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Get(0).(account.EmailLoginToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewEmailLoginStarter creates a new instance of EmailLoginStarter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailLoginStarter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailLoginStarter {
+	mock := &EmailLoginStarter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// EmailVerificationResender is an autogenerated mock type for the EmailVerificationResender type
+type EmailVerificationResender struct {
+	mock.Mock
+}
+
+// Resend provides a mock function with given fields: ctx, email
+func (_m *EmailVerificationResender) Resend(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resend")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewEmailVerificationResender creates a new instance of EmailVerificationResender. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewEmailVerificationResender(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *EmailVerificationResender {
+	mock := &EmailVerificationResender{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
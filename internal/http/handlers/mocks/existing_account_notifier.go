@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ExistingAccountNotifier is an autogenerated mock type for the ExistingAccountNotifier type
+type ExistingAccountNotifier struct {
+	mock.Mock
+}
+
+// Send provides a mock function with given fields: ctx, email
+func (_m *ExistingAccountNotifier) Send(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewExistingAccountNotifier creates a new instance of ExistingAccountNotifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewExistingAccountNotifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ExistingAccountNotifier {
+	mock := &ExistingAccountNotifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,74 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
+
+// ImpersonationTokenIssuer is an autogenerated mock type for the ImpersonationTokenIssuer type
+type ImpersonationTokenIssuer struct {
+	mock.Mock
+}
+
+// AccessTokenTTL provides a mock function with no fields
+func (_m *ImpersonationTokenIssuer) AccessTokenTTL() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for AccessTokenTTL")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// IssueImpersonationToken provides a mock function with given fields: userID, actorID, scope, ttl
+func (_m *ImpersonationTokenIssuer) IssueImpersonationToken(userID string, actorID string, scope string, ttl time.Duration) (string, error) {
+	ret := _m.Called(userID, actorID, scope, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IssueImpersonationToken")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string, string, time.Duration) (string, error)); ok {
+		return rf(userID, actorID, scope, ttl)
+	}
+	if rf, ok := ret.Get(0).(func(string, string, string, time.Duration) string); ok {
+		r0 = rf(userID, actorID, scope, ttl)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string, string, time.Duration) error); ok {
+		r1 = rf(userID, actorID, scope, ttl)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewImpersonationTokenIssuer creates a new instance of ImpersonationTokenIssuer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewImpersonationTokenIssuer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ImpersonationTokenIssuer {
+	mock := &ImpersonationTokenIssuer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
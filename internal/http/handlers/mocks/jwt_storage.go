@@ -31,17 +31,17 @@ func (_m *JwtStorage) Pop(ctx context.Context, token string) error {
 	return r0
 }
 
-// Save provides a mock function with given fields: ctx, token
-func (_m *JwtStorage) Save(ctx context.Context, token string) error {
-	ret := _m.Called(ctx, token)
+// Save provides a mock function with given fields: ctx, userID, token
+func (_m *JwtStorage) Save(ctx context.Context, userID string, token string) error {
+	ret := _m.Called(ctx, userID, token)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Save")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
-		r0 = rf(ctx, token)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, token)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mfa "github.com/riabininkf/http-auth-example/internal/mfa"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MFAChallengeCreator is an autogenerated mock type for the MFAChallengeCreator type
+type MFAChallengeCreator struct {
+	mock.Mock
+}
+
+// Create provides a mock function with given fields: ctx, userID
+func (_m *MFAChallengeCreator) Create(ctx context.Context, userID string) (mfa.ChallengeToken, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 mfa.ChallengeToken
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (mfa.ChallengeToken, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) mfa.ChallengeToken); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(mfa.ChallengeToken)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMFAChallengeCreator creates a new instance of MFAChallengeCreator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMFAChallengeCreator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MFAChallengeCreator {
+	mock := &MFAChallengeCreator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
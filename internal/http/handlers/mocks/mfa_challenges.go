@@ -0,0 +1,94 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mfa "github.com/riabininkf/http-auth-example/internal/mfa"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// MFAChallenges is an autogenerated mock type for the MFAChallenges type
+type MFAChallenges struct {
+	mock.Mock
+}
+
+// Complete provides a mock function with given fields: ctx, token
+func (_m *MFAChallenges) Complete(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Fail provides a mock function with given fields: ctx, token
+func (_m *MFAChallenges) Fail(ctx context.Context, token string) error {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Fail")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: ctx, token
+func (_m *MFAChallenges) Get(ctx context.Context, token string) (mfa.Challenge, error) {
+	ret := _m.Called(ctx, token)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 mfa.Challenge
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (mfa.Challenge, error)); ok {
+		return rf(ctx, token)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) mfa.Challenge); ok {
+		r0 = rf(ctx, token)
+	} else {
+		r0 = ret.Get(0).(mfa.Challenge)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, token)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewMFAChallenges creates a new instance of MFAChallenges. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMFAChallenges(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MFAChallenges {
+	mock := &MFAChallenges{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,48 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// NewDeviceNotifier is an autogenerated mock type for the NewDeviceNotifier type
+type NewDeviceNotifier struct {
+	mock.Mock
+}
+
+// Send provides a mock function with given fields: ctx, user
+func (_m *NewDeviceNotifier) Send(ctx context.Context, user domain.User) error {
+	ret := _m.Called(ctx, user)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Send")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.User) error); ok {
+		r0 = rf(ctx, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewNewDeviceNotifier creates a new instance of NewDeviceNotifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewNewDeviceNotifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *NewDeviceNotifier {
+	mock := &NewDeviceNotifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// OAuthClients is an autogenerated mock type for the OAuthClients type
+type OAuthClients struct {
+	mock.Mock
+}
+
+// Exists provides a mock function with given fields: clientID
+func (_m *OAuthClients) Exists(clientID string) bool {
+	ret := _m.Called(clientID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Exists")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string) bool); ok {
+		r0 = rf(clientID)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// IsRedirectURIAllowed provides a mock function with given fields: clientID, redirectURI
+func (_m *OAuthClients) IsRedirectURIAllowed(clientID string, redirectURI string) bool {
+	ret := _m.Called(clientID, redirectURI)
+
+	if len(ret) == 0 {
+		panic("no return value specified for IsRedirectURIAllowed")
+	}
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(string, string) bool); ok {
+		r0 = rf(clientID, redirectURI)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// NewOAuthClients creates a new instance of OAuthClients. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewOAuthClients(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *OAuthClients {
+	mock := &OAuthClients{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// PasswordResetRequester is an autogenerated mock type for the PasswordResetRequester type
+type PasswordResetRequester struct {
+	mock.Mock
+}
+
+// Request provides a mock function with given fields: ctx, email
+func (_m *PasswordResetRequester) Request(ctx context.Context, email string) error {
+	ret := _m.Called(ctx, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Request")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, email)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewPasswordResetRequester creates a new instance of PasswordResetRequester. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPasswordResetRequester(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PasswordResetRequester {
+	mock := &PasswordResetRequester{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	password "github.com/riabininkf/http-auth-example/internal/password"
+)
+
+// PasswordValidator is an autogenerated mock type for the PasswordValidator type
+type PasswordValidator struct {
+	mock.Mock
+}
+
+// Validate provides a mock function with given fields: _a0, email
+func (_m *PasswordValidator) Validate(_a0 string, email string) ([]password.Violation, error) {
+	ret := _m.Called(_a0, email)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Validate")
+	}
+
+	var r0 []password.Violation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, string) ([]password.Violation, error)); ok {
+		return rf(_a0, email)
+	}
+	if rf, ok := ret.Get(0).(func(string, string) []password.Violation); ok {
+		r0 = rf(_a0, email)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]password.Violation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, string) error); ok {
+		r1 = rf(_a0, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewPasswordValidator creates a new instance of PasswordValidator. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewPasswordValidator(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *PasswordValidator {
+	mock := &PasswordValidator{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
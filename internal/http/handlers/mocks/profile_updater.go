@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ProfileUpdater is an autogenerated mock type for the ProfileUpdater type
+type ProfileUpdater struct {
+	mock.Mock
+}
+
+// UpdateProfile provides a mock function with given fields: ctx, userID, update
+func (_m *ProfileUpdater) UpdateProfile(ctx context.Context, userID string, update domain.UserProfileUpdate) (domain.User, error) {
+	ret := _m.Called(ctx, userID, update)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateProfile")
+	}
+
+	var r0 domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.UserProfileUpdate) (domain.User, error)); ok {
+		return rf(ctx, userID, update)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.UserProfileUpdate) domain.User); ok {
+		r0 = rf(ctx, userID, update)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.UserProfileUpdate) error); ok {
+		r1 = rf(ctx, userID, update)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewProfileUpdater creates a new instance of ProfileUpdater. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewProfileUpdater(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ProfileUpdater {
+	mock := &ProfileUpdater{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
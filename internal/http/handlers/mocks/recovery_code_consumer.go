@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RecoveryCodeConsumer is an autogenerated mock type for the RecoveryCodeConsumer type
+type RecoveryCodeConsumer struct {
+	mock.Mock
+}
+
+// Use provides a mock function with given fields: ctx, userID, code
+func (_m *RecoveryCodeConsumer) Use(ctx context.Context, userID string, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Use")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewRecoveryCodeConsumer creates a new instance of RecoveryCodeConsumer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRecoveryCodeConsumer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RecoveryCodeConsumer {
+	mock := &RecoveryCodeConsumer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
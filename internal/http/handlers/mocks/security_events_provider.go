@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SecurityEventsProvider is an autogenerated mock type for the SecurityEventsProvider type
+type SecurityEventsProvider struct {
+	mock.Mock
+}
+
+// ListByUser provides a mock function with given fields: ctx, userID, limit
+func (_m *SecurityEventsProvider) ListByUser(ctx context.Context, userID string, limit int) ([]domain.AuditEvent, error) {
+	ret := _m.Called(ctx, userID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListByUser")
+	}
+
+	var r0 []domain.AuditEvent
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]domain.AuditEvent, error)); ok {
+		return rf(ctx, userID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []domain.AuditEvent); ok {
+		r0 = rf(ctx, userID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuditEvent)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, userID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewSecurityEventsProvider creates a new instance of SecurityEventsProvider. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSecurityEventsProvider(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SecurityEventsProvider {
+	mock := &SecurityEventsProvider{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
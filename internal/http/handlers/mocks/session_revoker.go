@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// SessionRevoker is an autogenerated mock type for the SessionRevoker type
+type SessionRevoker struct {
+	mock.Mock
+}
+
+// RevokeAll provides a mock function with given fields: ctx, userID
+func (_m *SessionRevoker) RevokeAll(ctx context.Context, userID string) error {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RevokeAll")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewSessionRevoker creates a new instance of SessionRevoker. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewSessionRevoker(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *SessionRevoker {
+	mock := &SessionRevoker{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
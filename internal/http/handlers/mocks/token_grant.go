@@ -0,0 +1,70 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	handlers "github.com/riabininkf/http-auth-example/internal/http/handlers"
+
+	httpx "github.com/riabininkf/httpx"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TokenGrant is an autogenerated mock type for the TokenGrant type
+type TokenGrant struct {
+	mock.Mock
+}
+
+// Grant provides a mock function with given fields: ctx, req
+func (_m *TokenGrant) Grant(ctx context.Context, req *handlers.TokenV1Request) *httpx.Response {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Grant")
+	}
+
+	var r0 *httpx.Response
+	if rf, ok := ret.Get(0).(func(context.Context, *handlers.TokenV1Request) *httpx.Response); ok {
+		r0 = rf(ctx, req)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*httpx.Response)
+		}
+	}
+
+	return r0
+}
+
+// GrantType provides a mock function with no fields
+func (_m *TokenGrant) GrantType() string {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GrantType")
+	}
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// NewTokenGrant creates a new instance of TokenGrant. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTokenGrant(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TokenGrant {
+	mock := &TokenGrant{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -2,13 +2,35 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+)
 
 // TokenIssuer is an autogenerated mock type for the TokenIssuer type
 type TokenIssuer struct {
 	mock.Mock
 }
 
+// AccessTokenTTL provides a mock function with no fields
+func (_m *TokenIssuer) AccessTokenTTL() time.Duration {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for AccessTokenTTL")
+	}
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
 // IssueAccessToken provides a mock function with given fields: userID
 func (_m *TokenIssuer) IssueAccessToken(userID string) (string, error) {
 	ret := _m.Called(userID)
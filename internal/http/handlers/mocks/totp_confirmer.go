@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TOTPConfirmer is an autogenerated mock type for the TOTPConfirmer type
+type TOTPConfirmer struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: ctx, userID, code
+func (_m *TOTPConfirmer) Confirm(ctx context.Context, userID string, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Confirm")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTOTPConfirmer creates a new instance of TOTPConfirmer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTOTPConfirmer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TOTPConfirmer {
+	mock := &TOTPConfirmer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mfa "github.com/riabininkf/http-auth-example/internal/mfa"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TOTPEnroller is an autogenerated mock type for the TOTPEnroller type
+type TOTPEnroller struct {
+	mock.Mock
+}
+
+// Enroll provides a mock function with given fields: ctx, userID, accountName
+func (_m *TOTPEnroller) Enroll(ctx context.Context, userID string, accountName string) (mfa.Enrollment, error) {
+	ret := _m.Called(ctx, userID, accountName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Enroll")
+	}
+
+	var r0 mfa.Enrollment
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (mfa.Enrollment, error)); ok {
+		return rf(ctx, userID, accountName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) mfa.Enrollment); ok {
+		r0 = rf(ctx, userID, accountName)
+	} else {
+		r0 = ret.Get(0).(mfa.Enrollment)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, accountName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTOTPEnroller creates a new instance of TOTPEnroller. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTOTPEnroller(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TOTPEnroller {
+	mock := &TOTPEnroller{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,46 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TOTPVerifier is an autogenerated mock type for the TOTPVerifier type
+type TOTPVerifier struct {
+	mock.Mock
+}
+
+// Verify provides a mock function with given fields: ctx, userID, code
+func (_m *TOTPVerifier) Verify(ctx context.Context, userID string, code string) error {
+	ret := _m.Called(ctx, userID, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Verify")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, userID, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewTOTPVerifier creates a new instance of TOTPVerifier. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTOTPVerifier(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TOTPVerifier {
+	mock := &TOTPVerifier{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
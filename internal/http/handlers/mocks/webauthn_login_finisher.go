@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	webauthn "github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// WebAuthnLoginFinisher is an autogenerated mock type for the WebAuthnLoginFinisher type
+type WebAuthnLoginFinisher struct {
+	mock.Mock
+}
+
+// FinishLogin provides a mock function with given fields: ctx, credential
+func (_m *WebAuthnLoginFinisher) FinishLogin(ctx context.Context, credential webauthn.AuthenticationCredential) (string, error) {
+	ret := _m.Called(ctx, credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishLogin")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, webauthn.AuthenticationCredential) (string, error)); ok {
+		return rf(ctx, credential)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, webauthn.AuthenticationCredential) string); ok {
+		r0 = rf(ctx, credential)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, webauthn.AuthenticationCredential) error); ok {
+		r1 = rf(ctx, credential)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWebAuthnLoginFinisher creates a new instance of WebAuthnLoginFinisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebAuthnLoginFinisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebAuthnLoginFinisher {
+	mock := &WebAuthnLoginFinisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	webauthn "github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// WebAuthnLoginStarter is an autogenerated mock type for the WebAuthnLoginStarter type
+type WebAuthnLoginStarter struct {
+	mock.Mock
+}
+
+// BeginLogin provides a mock function with given fields: ctx
+func (_m *WebAuthnLoginStarter) BeginLogin(ctx context.Context) (webauthn.CredentialRequestOptions, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginLogin")
+	}
+
+	var r0 webauthn.CredentialRequestOptions
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (webauthn.CredentialRequestOptions, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) webauthn.CredentialRequestOptions); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(webauthn.CredentialRequestOptions)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWebAuthnLoginStarter creates a new instance of WebAuthnLoginStarter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebAuthnLoginStarter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebAuthnLoginStarter {
+	mock := &WebAuthnLoginStarter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
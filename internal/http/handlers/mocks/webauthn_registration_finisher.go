@@ -0,0 +1,60 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+
+	webauthn "github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// WebAuthnRegistrationFinisher is an autogenerated mock type for the WebAuthnRegistrationFinisher type
+type WebAuthnRegistrationFinisher struct {
+	mock.Mock
+}
+
+// FinishRegistration provides a mock function with given fields: ctx, userID, credential
+func (_m *WebAuthnRegistrationFinisher) FinishRegistration(ctx context.Context, userID string, credential webauthn.RegistrationCredential) (domain.WebAuthnCredential, error) {
+	ret := _m.Called(ctx, userID, credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FinishRegistration")
+	}
+
+	var r0 domain.WebAuthnCredential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, webauthn.RegistrationCredential) (domain.WebAuthnCredential, error)); ok {
+		return rf(ctx, userID, credential)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, webauthn.RegistrationCredential) domain.WebAuthnCredential); ok {
+		r0 = rf(ctx, userID, credential)
+	} else {
+		r0 = ret.Get(0).(domain.WebAuthnCredential)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, webauthn.RegistrationCredential) error); ok {
+		r1 = rf(ctx, userID, credential)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWebAuthnRegistrationFinisher creates a new instance of WebAuthnRegistrationFinisher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebAuthnRegistrationFinisher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebAuthnRegistrationFinisher {
+	mock := &WebAuthnRegistrationFinisher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	webauthn "github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// WebAuthnRegistrationStarter is an autogenerated mock type for the WebAuthnRegistrationStarter type
+type WebAuthnRegistrationStarter struct {
+	mock.Mock
+}
+
+// BeginRegistration provides a mock function with given fields: ctx, userID, userName
+func (_m *WebAuthnRegistrationStarter) BeginRegistration(ctx context.Context, userID string, userName string) (webauthn.CredentialCreationOptions, error) {
+	ret := _m.Called(ctx, userID, userName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BeginRegistration")
+	}
+
+	var r0 webauthn.CredentialCreationOptions
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (webauthn.CredentialCreationOptions, error)); ok {
+		return rf(ctx, userID, userName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) webauthn.CredentialCreationOptions); ok {
+		r0 = rf(ctx, userID, userName)
+	} else {
+		r0 = ret.Get(0).(webauthn.CredentialCreationOptions)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, userName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWebAuthnRegistrationStarter creates a new instance of WebAuthnRegistrationStarter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWebAuthnRegistrationStarter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WebAuthnRegistrationStarter {
+	mock := &WebAuthnRegistrationStarter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
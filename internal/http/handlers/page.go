@@ -0,0 +1,133 @@
+package handlers
+
+//go:generate mockery --name MFAAttempts --output ./mocks --outpkg mocks --filename mfa_attempts.go --structname MFAAttempts
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/auth"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+// MFAAttempts describes MFAAttempts dependency.
+type MFAAttempts interface {
+	Add(ctx context.Context, userID string) error
+	Reset(ctx context.Context, userID string) error
+}
+
+// setPageHeaders sets headers shared by the server-rendered pages: they must not be cached or framed,
+// and may only submit forms back to this service.
+func setPageHeaders(writer http.ResponseWriter) {
+	writer.Header().Set("Cache-Control", "no-store")
+	writer.Header().Set("X-Frame-Options", "DENY")
+	writer.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; form-action 'self'")
+}
+
+// credentialsError records the failed login in the audit log and returns the status and the message a page shows
+// for an error of checking the submitted credentials. Unexpected errors are logged and get
+// http.StatusInternalServerError, in which case the page should not show anything the user submitted.
+func credentialsError(ctx context.Context, log *logger.Logger, auditLog AuditRecorder, err error) (int, string) {
+	if event, ok := failedLoginEvent(err); ok {
+		auditLog.Record(ctx, event)
+	}
+
+	switch {
+	// a locked account is shown like a wrong password, so that the page does not reveal the email is registered
+	case errors.Is(err, auth.ErrInvalidCredentials), errors.Is(err, auth.ErrAccountLocked):
+		return http.StatusUnauthorized, "invalid email or password"
+	case errors.Is(err, auth.ErrEmailNotVerified):
+		return http.StatusForbidden, "email address is not verified"
+	case isHashingBusy(err):
+		log.Warn("password hashing is saturated")
+		return http.StatusServiceUnavailable, "server is busy, try again later"
+	default:
+		log.Error("failed to verify credentials", logger.Error(err))
+		return http.StatusInternalServerError, "internal server error"
+	}
+}
+
+// secondFactor checks the second factor of users signing in on a page, so that the pages cannot be used to bypass
+// two-factor authentication. Every submitted code counts against the attempts of the user, so that codes cannot
+// be guessed by submitting a known password over and over.
+type secondFactor struct {
+	log           *logger.Logger
+	mfaStatus     MFAStatusProvider
+	totp          TOTPVerifier
+	recoveryCodes RecoveryCodeConsumer
+	attempts      MFAAttempts
+	auditLog      AuditRecorder
+}
+
+// verify checks the authentication code or, if one is submitted, the recovery code of the user, if the user has
+// two-factor authentication enabled. Returns zero if the user may proceed, otherwise the status and the message
+// the page shows. Unexpected errors are logged and get http.StatusInternalServerError, in which case the page should
+// not show anything the user submitted.
+func (s *secondFactor) verify(ctx context.Context, userID string, code string, recoveryCode string) (int, string) {
+	method := loginMethodTOTP
+	if recoveryCode != "" {
+		method = loginMethodRecoveryCode
+	}
+
+	verified, err := s.check(ctx, userID, code, recoveryCode)
+	switch {
+	case err == nil:
+		if verified && method == loginMethodRecoveryCode {
+			s.auditLog.Record(ctx, domain.AuditEvent{Type: domain.AuditEventRecoveryCodeUsed, UserID: userID})
+		}
+
+		return 0, ""
+	case errors.Is(err, mfa.ErrInvalidCode):
+		s.log.Warn("authentication code is missing or invalid")
+
+		event := loginEvent(userID, method, domain.AuditOutcomeFailure)
+		event.Reason = auditReasonInvalidCode
+		s.auditLog.Record(ctx, event)
+
+		return http.StatusUnauthorized, "authentication code is missing or invalid"
+	case errors.Is(err, mfa.ErrTooManyAttempts):
+		s.log.Warn("too many authentication code attempts")
+
+		event := loginEvent(userID, method, domain.AuditOutcomeFailure)
+		event.Reason = auditReasonTooManyAttempts
+		s.auditLog.Record(ctx, event)
+
+		return http.StatusTooManyRequests, "too many invalid authentication codes, try again later"
+	default:
+		s.log.Error("failed to verify second factor", logger.Error(err))
+		return http.StatusInternalServerError, "internal server error"
+	}
+}
+
+// check reports whether a second factor was verified, which it is not for users without two-factor authentication.
+// Returns mfa.ErrInvalidCode if the code is missing or invalid and mfa.ErrTooManyAttempts if the user is out of them.
+func (s *secondFactor) check(ctx context.Context, userID string, code string, recoveryCode string) (bool, error) {
+	enabled, err := s.mfaStatus.IsEnabled(ctx, userID)
+	if err != nil || !enabled {
+		return false, err
+	}
+
+	if code == "" && recoveryCode == "" {
+		return false, mfa.ErrInvalidCode
+	}
+
+	if err = s.attempts.Add(ctx, userID); err != nil {
+		return false, err
+	}
+
+	if recoveryCode != "" {
+		err = s.recoveryCodes.Use(ctx, userID, recoveryCode)
+	} else {
+		err = s.totp.Verify(ctx, userID, code)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, s.attempts.Reset(ctx, userID)
+}
@@ -0,0 +1,27 @@
+package handlers
+
+//go:generate mockery --name PasswordHasher --output ./mocks --outpkg mocks --filename password_hasher.go --structname PasswordHasher
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+// hashingBusyResponse is returned when password hashing is saturated, so that clients back off and retry later.
+var hashingBusyResponse = httpx.NewErrorResponse(http.StatusServiceUnavailable, "server is busy, try again later")
+
+// PasswordHasher hashes new passwords and verifies passwords against stored hashes.
+type PasswordHasher interface {
+	Hash(ctx context.Context, password string) (string, error)
+	Verify(ctx context.Context, password string, encoded string) (bool, error)
+}
+
+// isHashingBusy reports whether the error is caused by saturated password hashing.
+func isHashingBusy(err error) bool {
+	return errors.Is(err, password.ErrBusy)
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+// NewPasswordHashingV1 creates a new *PasswordHashingV1 instance.
+func NewPasswordHashingV1(
+	log *logger.Logger,
+	admins AdminChecker,
+	calibration password.Calibration,
+) *PasswordHashingV1 {
+	return &PasswordHashingV1{
+		log:         log,
+		admins:      admins,
+		calibration: calibration,
+	}
+}
+
+type (
+	// PasswordHashingV1 shows admins the password hashing parameters this instance chose at startup.
+	PasswordHashingV1 struct {
+		log         *logger.Logger
+		admins      AdminChecker
+		calibration password.Calibration
+	}
+
+	// PasswordHashingV1Request represents password hashing request.
+	PasswordHashingV1Request struct{}
+
+	// PasswordHashingV1Response represents password hashing response. Durations are in milliseconds
+	// and omitted when the parameters are pinned.
+	PasswordHashingV1Response struct {
+		Algorithm       string                  `json:"algorithm"`
+		Pinned          bool                    `json:"pinned"`
+		TargetLatencyMs int64                   `json:"target_latency_ms,omitempty"`
+		HashDurationMs  int64                   `json:"hash_duration_ms,omitempty"`
+		Argon2id        PasswordHashingArgon2id `json:"argon2id"`
+		Bcrypt          PasswordHashingBcrypt   `json:"bcrypt"`
+	}
+
+	// PasswordHashingArgon2id holds argon2id parameters. Memory is in KiB.
+	PasswordHashingArgon2id struct {
+		Memory      uint32 `json:"memory"`
+		Iterations  uint32 `json:"iterations"`
+		Parallelism uint8  `json:"parallelism"`
+	}
+
+	// PasswordHashingBcrypt holds bcrypt parameters.
+	PasswordHashingBcrypt struct {
+		Cost int `json:"cost"`
+	}
+)
+
+// Handle returns the password hashing parameters. Only admins can see them.
+func (h *PasswordHashingV1) Handle(ctx context.Context, _ *PasswordHashingV1Request) *httpx.Response {
+	var (
+		ok     bool
+		userID string
+	)
+	if userID, ok = httpx.GetUserID(ctx); !ok {
+		h.log.Warn("user id is missing")
+		return httpx.BadRequest
+	}
+
+	if !h.admins.IsAdmin(userID) {
+		h.log.Warn("user is not an admin")
+		return httpx.Forbidden
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&PasswordHashingV1Response{
+			Algorithm:       h.calibration.Algorithm,
+			Pinned:          h.calibration.Pinned,
+			TargetLatencyMs: h.calibration.TargetLatency.Milliseconds(),
+			HashDurationMs:  h.calibration.HashDuration.Milliseconds(),
+			Argon2id: PasswordHashingArgon2id{
+				Memory:      h.calibration.Argon2id.Memory,
+				Iterations:  h.calibration.Argon2id.Iterations,
+				Parallelism: h.calibration.Argon2id.Parallelism,
+			},
+			Bcrypt: PasswordHashingBcrypt{
+				Cost: h.calibration.BcryptCost,
+			},
+		}),
+	)
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/auth"
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+// DefPasswordHashingV1Name is the name of the *PasswordHashingV1 definition.
+const DefPasswordHashingV1Name = "http.password-hashing-v1"
+
+func init() {
+	di.Add(
+		di.Def[*PasswordHashingV1]{
+			Name: DefPasswordHashingV1Name,
+			Build: func(ctn di.Container) (*PasswordHashingV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var admins *auth.Admins
+				if err := ctn.Fill(auth.DefAdminsName, &admins); err != nil {
+					return nil, err
+				}
+
+				var calibration *password.Calibration
+				if err := ctn.Fill(password.DefCalibrationName, &calibration); err != nil {
+					return nil, err
+				}
+
+				return NewPasswordHashingV1(log, admins, *calibration), nil
+			},
+		},
+	)
+}
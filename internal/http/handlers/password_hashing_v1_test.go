@@ -0,0 +1,76 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestPasswordHashingV1_Handle(t *testing.T) {
+	calibration := password.Calibration{
+		Algorithm:     "argon2id",
+		TargetLatency: 250 * time.Millisecond,
+		HashDuration:  180 * time.Millisecond,
+		Argon2id:      password.Argon2idParams{Memory: 65536, Iterations: 3, Parallelism: 1},
+		BcryptCost:    10,
+	}
+
+	testCases := []struct {
+		name      string
+		userID    string
+		onIsAdmin func() bool
+		expResp   *httpx.Response
+	}{
+		{
+			name:    "user id is missing",
+			expResp: httpx.BadRequest,
+		},
+		{
+			name:      "user is not an admin",
+			userID:    "user_id",
+			onIsAdmin: func() bool { return false },
+			expResp:   httpx.Forbidden,
+		},
+		{
+			name:      "positive case",
+			userID:    "admin_id",
+			onIsAdmin: func() bool { return true },
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.PasswordHashingV1Response{
+					Algorithm:       "argon2id",
+					TargetLatencyMs: 250,
+					HashDurationMs:  180,
+					Argon2id:        handlers.PasswordHashingArgon2id{Memory: 65536, Iterations: 3, Parallelism: 1},
+					Bcrypt:          handlers.PasswordHashingBcrypt{Cost: 10},
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.userID != "" {
+				ctx = httpx.ContextWithUserID(ctx, testCase.userID)
+			}
+
+			admins := mocks.NewAdminChecker(t)
+			if testCase.onIsAdmin != nil {
+				admins.On("IsAdmin", testCase.userID).Return(testCase.onIsAdmin())
+			}
+
+			handler := handlers.NewPasswordHashingV1(zap.NewNop(), admins, calibration)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(ctx, &handlers.PasswordHashingV1Request{}))
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package handlers
+
+//go:generate mockery --name PasswordValidator --output ./mocks --outpkg mocks --filename password_validator.go --structname PasswordValidator
+
+import (
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+// PasswordValidator checks new passwords against the password policy.
+type PasswordValidator interface {
+	Validate(password string, email string) ([]password.Violation, error)
+}
+
+type (
+	// ValidationErrorResponse represents a request rejected because of invalid fields.
+	ValidationErrorResponse struct {
+		Error ValidationError `json:"error"`
+	}
+
+	// ValidationError lists the problems of every invalid field, so that clients can show them next to the fields.
+	ValidationError struct {
+		Message string       `json:"message"`
+		Details []FieldError `json:"details"`
+	}
+
+	// FieldError describes a problem with a field. Code is stable and Params hold the limits of the rule,
+	// while Message is meant for developers.
+	FieldError struct {
+		Field   string         `json:"field"`
+		Code    string         `json:"code"`
+		Message string         `json:"message"`
+		Params  map[string]int `json:"params,omitempty"`
+	}
+)
+
+// validatePassword checks the password in the field against the policy. Returns the response rejecting
+// the request, or nil if the password meets the policy.
+func validatePassword(
+	log *logger.Logger,
+	policy PasswordValidator,
+	field string,
+	password string,
+	email string,
+) *httpx.Response {
+	violations, err := policy.Validate(password, email)
+	if err != nil {
+		log.Error("failed to validate password", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if len(violations) > 0 {
+		log.Warn("password does not meet the policy", logger.String("field", field))
+		return newPasswordPolicyResponse(field, violations)
+	}
+
+	return nil
+}
+
+// newPasswordPolicyResponse returns 400 Bad Request listing the policy violations of the password in the field.
+func newPasswordPolicyResponse(field string, violations []password.Violation) *httpx.Response {
+	details := make([]FieldError, 0, len(violations))
+	for _, violation := range violations {
+		details = append(details, FieldError{
+			Field:   field,
+			Code:    violation.Code,
+			Message: field + " " + violation.Message,
+			Params:  violation.Params,
+		})
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusBadRequest),
+		httpx.WithBody(&ValidationErrorResponse{
+			Error: ValidationError{
+				Message: "password does not meet the requirements",
+				Details: details,
+			},
+		}),
+	)
+}
@@ -8,6 +8,8 @@ import (
 
 	"github.com/riabininkf/go-modules/logger"
 	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
 )
 
 // NewRefreshV1 creates a new *RefreshV1 instance.
@@ -16,12 +18,14 @@ func NewRefreshV1(
 	issuer TokenIssuer,
 	jwtStorage JwtStorage,
 	verifier RefreshTokenVerifier,
+	auditLog AuditRecorder,
 ) *RefreshV1 {
 	return &RefreshV1{
 		log:        log,
 		issuer:     issuer,
 		jwtStorage: jwtStorage,
 		verifier:   verifier,
+		auditLog:   auditLog,
 	}
 }
 
@@ -32,6 +36,7 @@ type (
 		issuer     TokenIssuer
 		jwtStorage JwtStorage
 		verifier   RefreshTokenVerifier
+		auditLog   AuditRecorder
 	}
 
 	// RefreshV1Request represents refresh request.
@@ -61,6 +66,7 @@ func (h *RefreshV1) Handle(ctx context.Context, req *RefreshV1Request) *httpx.Re
 
 	if err := h.jwtStorage.Pop(ctx, req.RefreshToken); err != nil {
 		h.log.Warn("failed to pop refresh token from the storage", logger.Error(err))
+		h.recordFailure(ctx, req.RefreshToken)
 		return httpx.NewErrorResponse(http.StatusUnauthorized, "invalid refresh token")
 	}
 
@@ -70,6 +76,12 @@ func (h *RefreshV1) Handle(ctx context.Context, req *RefreshV1Request) *httpx.Re
 	)
 	if userID, err = h.verifier.VerifyRefresh(ctx, req.RefreshToken); err != nil {
 		h.log.Warn("failed to verify refresh token", logger.Error(err))
+		h.auditLog.Record(ctx, domain.AuditEvent{
+			Type:      domain.AuditEventTokenRefresh,
+			SessionID: sessionID(req.RefreshToken),
+			Outcome:   domain.AuditOutcomeFailure,
+			Reason:    auditReasonInvalidToken,
+		})
 		return httpx.NewErrorResponse(http.StatusUnauthorized, "invalid refresh token")
 	}
 
@@ -85,11 +97,19 @@ func (h *RefreshV1) Handle(ctx context.Context, req *RefreshV1Request) *httpx.Re
 		return httpx.InternalServerError
 	}
 
-	if err = h.jwtStorage.Save(ctx, refreshToken); err != nil {
+	if err = h.jwtStorage.Save(ctx, userID, refreshToken); err != nil {
 		h.log.Error("failed to save refresh token", logger.Error(err))
 		return httpx.InternalServerError
 	}
 
+	h.auditLog.Record(ctx, domain.AuditEvent{
+		Type:      domain.AuditEventTokenRefresh,
+		UserID:    userID,
+		SessionID: sessionID(refreshToken),
+		Outcome:   domain.AuditOutcomeSuccess,
+		Details:   map[string]string{"previous_session_id": sessionID(req.RefreshToken)},
+	})
+
 	return httpx.NewJsonResponse(
 		httpx.WithStatus(http.StatusOK),
 		httpx.WithBody(&RefreshV1Response{
@@ -99,3 +119,21 @@ func (h *RefreshV1) Handle(ctx context.Context, req *RefreshV1Request) *httpx.Re
 		}),
 	)
 }
+
+// recordFailure records a refresh with a token that is not in the storage. A token that still verifies was issued
+// by the service but already used or revoked, which may mean it was stolen, so the event is attributed to its user.
+func (h *RefreshV1) recordFailure(ctx context.Context, refreshToken string) {
+	event := domain.AuditEvent{
+		Type:      domain.AuditEventTokenRefresh,
+		SessionID: sessionID(refreshToken),
+		Outcome:   domain.AuditOutcomeFailure,
+		Reason:    auditReasonInvalidToken,
+	}
+
+	if userID, err := h.verifier.VerifyRefresh(ctx, refreshToken); err == nil {
+		event.UserID = userID
+		event.Reason = auditReasonTokenReused
+	}
+
+	h.auditLog.Record(ctx, event)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/riabininkf/go-modules/di"
 	"github.com/riabininkf/go-modules/logger"
 
+	"github.com/riabininkf/http-auth-example/internal/audit"
 	"github.com/riabininkf/http-auth-example/internal/jwt"
 )
 
@@ -35,11 +36,17 @@ func init() {
 					return nil, err
 				}
 
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
 				return NewRefreshV1(
 					log,
 					issuer,
 					storage,
 					verifier,
+					recorder,
 				), nil
 			},
 		},
@@ -4,18 +4,18 @@ import (
 	"net/http"
 	"testing"
 
-	"github.com/brianvoe/gofakeit/v7"
 	"github.com/riabininkf/httpx"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap"
 
+	"github.com/riabininkf/http-auth-example/internal/domain"
 	"github.com/riabininkf/http-auth-example/internal/http/handlers"
 	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
 )
 
 func TestRefreshV1_Handle(t *testing.T) {
 	generateRequest := func() *handlers.RefreshV1Request {
-		return &handlers.RefreshV1Request{RefreshToken: gofakeit.Name()}
+		return &handlers.RefreshV1Request{RefreshToken: "old_refresh_token"}
 	}
 
 	testCases := []struct {
@@ -26,6 +26,7 @@ func TestRefreshV1_Handle(t *testing.T) {
 		onIssueAccessToken  func() (string, error)
 		onIssueRefreshToken func() (string, error)
 		onSaveRefreshToken  func() error
+		expAuditEvent       *domain.AuditEvent
 		expResp             *httpx.Response
 	}{
 		{
@@ -34,9 +35,30 @@ func TestRefreshV1_Handle(t *testing.T) {
 			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "refresh_token is required"),
 		},
 		{
-			name:    "failed to pop refresh token from the storage",
-			req:     generateRequest,
-			onPop:   func() error { return assert.AnError },
+			name:            "failed to pop refresh token from the storage",
+			req:             generateRequest,
+			onPop:           func() error { return assert.AnError },
+			onVerifyRefresh: func() (string, error) { return "", assert.AnError },
+			expAuditEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventTokenRefresh,
+				SessionID: "978f9acc5e5c72c4",
+				Outcome:   domain.AuditOutcomeFailure,
+				Reason:    "invalid_token",
+			},
+			expResp: httpx.NewErrorResponse(http.StatusUnauthorized, "invalid refresh token"),
+		},
+		{
+			name:            "refresh token is reused",
+			req:             generateRequest,
+			onPop:           func() error { return assert.AnError },
+			onVerifyRefresh: func() (string, error) { return "user_id", nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventTokenRefresh,
+				UserID:    "user_id",
+				SessionID: "978f9acc5e5c72c4",
+				Outcome:   domain.AuditOutcomeFailure,
+				Reason:    "token_reused",
+			},
 			expResp: httpx.NewErrorResponse(http.StatusUnauthorized, "invalid refresh token"),
 		},
 		{
@@ -44,7 +66,13 @@ func TestRefreshV1_Handle(t *testing.T) {
 			req:             generateRequest,
 			onPop:           func() error { return nil },
 			onVerifyRefresh: func() (string, error) { return "", assert.AnError },
-			expResp:         httpx.NewErrorResponse(http.StatusUnauthorized, "invalid refresh token"),
+			expAuditEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventTokenRefresh,
+				SessionID: "978f9acc5e5c72c4",
+				Outcome:   domain.AuditOutcomeFailure,
+				Reason:    "invalid_token",
+			},
+			expResp: httpx.NewErrorResponse(http.StatusUnauthorized, "invalid refresh token"),
 		},
 		{
 			name:               "failed to issue access token",
@@ -81,6 +109,13 @@ func TestRefreshV1_Handle(t *testing.T) {
 			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
 			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
 			onSaveRefreshToken:  func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:      domain.AuditEventTokenRefresh,
+				UserID:    "user_id",
+				SessionID: "6c8a7d4aa21708a4",
+				Outcome:   domain.AuditOutcomeSuccess,
+				Details:   map[string]string{"previous_session_id": "978f9acc5e5c72c4"},
+			},
 			expResp: httpx.NewJsonResponse(
 				httpx.WithStatus(http.StatusOK),
 				httpx.WithBody(&handlers.RefreshV1Response{
@@ -130,7 +165,12 @@ func TestRefreshV1_Handle(t *testing.T) {
 			}
 
 			if testCase.onSaveRefreshToken != nil {
-				jwtStorage.On("Save", t.Context(), refreshToken).Return(testCase.onSaveRefreshToken())
+				jwtStorage.On("Save", t.Context(), userID, refreshToken).Return(testCase.onSaveRefreshToken())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", t.Context(), *testCase.expAuditEvent).Return()
 			}
 
 			handler := handlers.NewRefreshV1(
@@ -138,6 +178,7 @@ func TestRefreshV1_Handle(t *testing.T) {
 				issuer,
 				jwtStorage,
 				refreshVerifier,
+				auditLog,
 			)
 
 			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), req))
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+)
+
+// NewRegenerateRecoveryCodesV1 creates a new *RegenerateRecoveryCodesV1 instance.
+func NewRegenerateRecoveryCodesV1(
+	log *logger.Logger,
+	userProvider UserByIdProvider,
+	passwordHasher PasswordHasher,
+	mfaStatus MFAStatusProvider,
+	totp TOTPVerifier,
+	attempts MFAAttempts,
+	recoveryCodes RecoveryCodesGenerator,
+	auditLog AuditRecorder,
+) *RegenerateRecoveryCodesV1 {
+	return &RegenerateRecoveryCodesV1{
+		log:            log,
+		userProvider:   userProvider,
+		passwordHasher: passwordHasher,
+		mfaStatus:      mfaStatus,
+		totp:           totp,
+		attempts:       attempts,
+		recoveryCodes:  recoveryCodes,
+		auditLog:       auditLog,
+	}
+}
+
+type (
+	// RegenerateRecoveryCodesV1 replaces the recovery codes of the authenticated user once they confirm it
+	// with their password or a TOTP code, so that a stolen access token alone cannot take over the second factor.
+	RegenerateRecoveryCodesV1 struct {
+		log            *logger.Logger
+		userProvider   UserByIdProvider
+		passwordHasher PasswordHasher
+		mfaStatus      MFAStatusProvider
+		totp           TOTPVerifier
+		attempts       MFAAttempts
+		recoveryCodes  RecoveryCodesGenerator
+		auditLog       AuditRecorder
+	}
+
+	// RegenerateRecoveryCodesV1Request represents recovery codes regeneration request.
+	// Either the current password or a code of the authenticator app is required.
+	RegenerateRecoveryCodesV1Request struct {
+		Password string `json:"password"`
+		Code     string `json:"code"`
+	}
+
+	// RegenerateRecoveryCodesV1Response holds the new recovery codes. They are shown only once.
+	RegenerateRecoveryCodesV1Response struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+)
+
+// Handle checks the password or the TOTP code and generates a new set of recovery codes, invalidating
+// the previous one, used or not.
+func (h *RegenerateRecoveryCodesV1) Handle(ctx context.Context, req *RegenerateRecoveryCodesV1Request) *httpx.Response {
+	if req.Password == "" && req.Code == "" {
+		h.log.Warn("password and code are missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "password or code is required")
+	}
+
+	var (
+		ok     bool
+		userID string
+	)
+	if userID, ok = httpx.GetUserID(ctx); !ok {
+		h.log.Warn("user id is missing")
+		return httpx.BadRequest
+	}
+
+	var (
+		err     error
+		enabled bool
+	)
+	if enabled, err = h.mfaStatus.IsEnabled(ctx, userID); err != nil {
+		h.log.Error("failed to check mfa status", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if !enabled {
+		h.log.Warn("mfa is not enabled")
+		return httpx.NewErrorResponse(http.StatusConflict, "two-factor authentication is not enabled")
+	}
+
+	if resp := h.reauthenticate(ctx, userID, req); resp != nil {
+		return resp
+	}
+
+	var recoveryCodes []string
+	if recoveryCodes, err = h.recoveryCodes.Generate(ctx, userID); err != nil {
+		h.log.Error("failed to generate recovery codes", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	// the old codes are already gone, so the new ones are returned even if the event is lost
+	if err = h.auditLog.Save(ctx, domain.AuditEvent{
+		Type:    domain.AuditEventRecoveryCodesRegenerated,
+		UserID:  userID,
+		Outcome: domain.AuditOutcomeSuccess,
+	}); err != nil {
+		h.log.Error("failed to save audit event", logger.Error(err))
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&RegenerateRecoveryCodesV1Response{
+			RecoveryCodes: recoveryCodes,
+		}),
+	)
+}
+
+// reauthenticate checks the TOTP code if there is one and the password otherwise, and returns the response
+// to reject the request with, or nil if the user proved who they are. Codes count against the attempts of
+// the user like on login, so that they cannot be guessed here instead.
+func (h *RegenerateRecoveryCodesV1) reauthenticate(
+	ctx context.Context,
+	userID string,
+	req *RegenerateRecoveryCodesV1Request,
+) *httpx.Response {
+	if req.Code != "" {
+		return h.checkCode(ctx, userID, req.Code)
+	}
+
+	user, err := h.userProvider.GetByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			h.log.Warn("user not found")
+			return httpx.NotFound
+		}
+
+		h.log.Error("failed to get user by id", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	var ok bool
+	if ok, err = h.passwordHasher.Verify(ctx, req.Password, user.HashedPassword()); err != nil {
+		if isHashingBusy(err) {
+			h.log.Warn("password hashing is saturated")
+			return hashingBusyResponse
+		}
+
+		h.log.Error("failed to compare passwords", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if !ok {
+		h.log.Warn("invalid password")
+		h.recordFailure(ctx, userID, auditReasonInvalidPassword)
+		return httpx.NewErrorResponse(http.StatusBadRequest, "invalid password")
+	}
+
+	return nil
+}
+
+// checkCode verifies the TOTP code of the user, counting it against their attempts.
+func (h *RegenerateRecoveryCodesV1) checkCode(ctx context.Context, userID string, code string) *httpx.Response {
+	if err := h.attempts.Add(ctx, userID); err != nil {
+		if errors.Is(err, mfa.ErrTooManyAttempts) {
+			h.log.Warn("too many mfa code attempts")
+			h.recordFailure(ctx, userID, auditReasonTooManyAttempts)
+			return httpx.NewErrorResponse(http.StatusTooManyRequests, "too many invalid codes, try again later")
+		}
+
+		h.log.Error("failed to count mfa attempt", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if err := h.totp.Verify(ctx, userID, code); err != nil {
+		if errors.Is(err, mfa.ErrInvalidCode) {
+			h.log.Warn("invalid totp code")
+			h.recordFailure(ctx, userID, auditReasonInvalidCode)
+			return httpx.NewErrorResponse(http.StatusBadRequest, "invalid code")
+		}
+
+		h.log.Error("failed to verify totp code", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if err := h.attempts.Reset(ctx, userID); err != nil {
+		h.log.Error("failed to reset mfa attempts", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	return nil
+}
+
+// recordFailure records a regeneration refused for the reason.
+func (h *RegenerateRecoveryCodesV1) recordFailure(ctx context.Context, userID string, reason string) {
+	h.auditLog.Record(ctx, domain.AuditEvent{
+		Type:    domain.AuditEventRecoveryCodesRegenerated,
+		UserID:  userID,
+		Outcome: domain.AuditOutcomeFailure,
+		Reason:  reason,
+	})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+	"github.com/riabininkf/http-auth-example/internal/password"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+// DefRegenerateRecoveryCodesV1Name is the name of the *RegenerateRecoveryCodesV1 definition.
+const DefRegenerateRecoveryCodesV1Name = "http.regenerate-recovery-codes-v1"
+
+func init() {
+	di.Add(
+		di.Def[*RegenerateRecoveryCodesV1]{
+			Name: DefRegenerateRecoveryCodesV1Name,
+			Build: func(ctn di.Container) (*RegenerateRecoveryCodesV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				var passwordHasher *password.Pool
+				if err := ctn.Fill(password.DefPoolName, &passwordHasher); err != nil {
+					return nil, err
+				}
+
+				var totp *mfa.TOTP
+				if err := ctn.Fill(mfa.DefTOTPName, &totp); err != nil {
+					return nil, err
+				}
+
+				var attempts *mfa.Attempts
+				if err := ctn.Fill(mfa.DefAttemptsName, &attempts); err != nil {
+					return nil, err
+				}
+
+				var recoveryCodes *mfa.RecoveryCodes
+				if err := ctn.Fill(mfa.DefRecoveryCodesName, &recoveryCodes); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewRegenerateRecoveryCodesV1(
+					log,
+					usersRep,
+					passwordHasher,
+					totp,
+					totp,
+					attempts,
+					recoveryCodes,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,296 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestRegenerateRecoveryCodesV1_Handle(t *testing.T) {
+	user := domain.NewUser("user_id", "user@example.com", "hashed_password")
+	passwordReq := &handlers.RegenerateRecoveryCodesV1Request{Password: "password"}
+	codeReq := &handlers.RegenerateRecoveryCodesV1Request{Code: "123456"}
+
+	testCases := []struct {
+		name             string
+		req              *handlers.RegenerateRecoveryCodesV1Request
+		userID           string
+		onIsMFAEnabled   func() (bool, error)
+		onGetUserByID    func() (domain.User, error)
+		onVerifyPassword func() (bool, error)
+		onAddAttempt     func() error
+		onVerifyCode     func() error
+		onResetAttempts  func() error
+		onGenerate       func() ([]string, error)
+		onSaveAuditEvent func() error
+		expAuditEvent    *domain.AuditEvent
+		expResp          *httpx.Response
+	}{
+		{
+			name:    "password and code are missing",
+			req:     &handlers.RegenerateRecoveryCodesV1Request{},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "password or code is required"),
+		},
+		{
+			name:    "user id is missing",
+			req:     passwordReq,
+			expResp: httpx.BadRequest,
+		},
+		{
+			name:           "failed to check mfa status",
+			req:            passwordReq,
+			userID:         "user_id",
+			onIsMFAEnabled: func() (bool, error) { return false, assert.AnError },
+			expResp:        httpx.InternalServerError,
+		},
+		{
+			name:           "mfa is not enabled",
+			req:            passwordReq,
+			userID:         "user_id",
+			onIsMFAEnabled: func() (bool, error) { return false, nil },
+			expResp:        httpx.NewErrorResponse(http.StatusConflict, "two-factor authentication is not enabled"),
+		},
+		{
+			name:           "user not found",
+			req:            passwordReq,
+			userID:         "user_id",
+			onIsMFAEnabled: func() (bool, error) { return true, nil },
+			onGetUserByID:  func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			expResp:        httpx.NotFound,
+		},
+		{
+			name:           "failed to get user by id",
+			req:            passwordReq,
+			userID:         "user_id",
+			onIsMFAEnabled: func() (bool, error) { return true, nil },
+			onGetUserByID:  func() (domain.User, error) { return nil, assert.AnError },
+			expResp:        httpx.InternalServerError,
+		},
+		{
+			name:             "failed to compare passwords",
+			req:              passwordReq,
+			userID:           "user_id",
+			onIsMFAEnabled:   func() (bool, error) { return true, nil },
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return false, assert.AnError },
+			expResp:          httpx.InternalServerError,
+		},
+		{
+			name:             "password hashing is saturated",
+			req:              passwordReq,
+			userID:           "user_id",
+			onIsMFAEnabled:   func() (bool, error) { return true, nil },
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return false, password.ErrBusy },
+			expResp:          httpx.NewErrorResponse(http.StatusServiceUnavailable, "server is busy, try again later"),
+		},
+		{
+			name:             "invalid password",
+			req:              passwordReq,
+			userID:           "user_id",
+			onIsMFAEnabled:   func() (bool, error) { return true, nil },
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return false, nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventRecoveryCodesRegenerated,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_password",
+			},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "invalid password"),
+		},
+		{
+			name:           "too many code attempts",
+			req:            codeReq,
+			userID:         "user_id",
+			onIsMFAEnabled: func() (bool, error) { return true, nil },
+			onAddAttempt:   func() error { return mfa.ErrTooManyAttempts },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventRecoveryCodesRegenerated,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "too_many_attempts",
+			},
+			expResp: httpx.NewErrorResponse(http.StatusTooManyRequests, "too many invalid codes, try again later"),
+		},
+		{
+			name:           "failed to count code attempt",
+			req:            codeReq,
+			userID:         "user_id",
+			onIsMFAEnabled: func() (bool, error) { return true, nil },
+			onAddAttempt:   func() error { return assert.AnError },
+			expResp:        httpx.InternalServerError,
+		},
+		{
+			name:           "invalid code",
+			req:            codeReq,
+			userID:         "user_id",
+			onIsMFAEnabled: func() (bool, error) { return true, nil },
+			onAddAttempt:   func() error { return nil },
+			onVerifyCode:   func() error { return mfa.ErrInvalidCode },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventRecoveryCodesRegenerated,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_code",
+			},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "invalid code"),
+		},
+		{
+			name:           "failed to verify code",
+			req:            codeReq,
+			userID:         "user_id",
+			onIsMFAEnabled: func() (bool, error) { return true, nil },
+			onAddAttempt:   func() error { return nil },
+			onVerifyCode:   func() error { return assert.AnError },
+			expResp:        httpx.InternalServerError,
+		},
+		{
+			name:            "failed to reset code attempts",
+			req:             codeReq,
+			userID:          "user_id",
+			onIsMFAEnabled:  func() (bool, error) { return true, nil },
+			onAddAttempt:    func() error { return nil },
+			onVerifyCode:    func() error { return nil },
+			onResetAttempts: func() error { return assert.AnError },
+			expResp:         httpx.InternalServerError,
+		},
+		{
+			name:             "failed to generate recovery codes",
+			req:              passwordReq,
+			userID:           "user_id",
+			onIsMFAEnabled:   func() (bool, error) { return true, nil },
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onGenerate:       func() ([]string, error) { return nil, assert.AnError },
+			expResp:          httpx.InternalServerError,
+		},
+		{
+			name:             "failed to save audit event",
+			req:              passwordReq,
+			userID:           "user_id",
+			onIsMFAEnabled:   func() (bool, error) { return true, nil },
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onGenerate:       func() ([]string, error) { return []string{"AAAA-BBBB-CCCC-DDDD"}, nil },
+			onSaveAuditEvent: func() error { return assert.AnError },
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.RegenerateRecoveryCodesV1Response{
+					RecoveryCodes: []string{"AAAA-BBBB-CCCC-DDDD"},
+				}),
+			),
+		},
+		{
+			name:             "positive case with password",
+			req:              passwordReq,
+			userID:           "user_id",
+			onIsMFAEnabled:   func() (bool, error) { return true, nil },
+			onGetUserByID:    func() (domain.User, error) { return user, nil },
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onGenerate:       func() ([]string, error) { return []string{"AAAA-BBBB-CCCC-DDDD"}, nil },
+			onSaveAuditEvent: func() error { return nil },
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.RegenerateRecoveryCodesV1Response{
+					RecoveryCodes: []string{"AAAA-BBBB-CCCC-DDDD"},
+				}),
+			),
+		},
+		{
+			name:             "positive case with code",
+			req:              codeReq,
+			userID:           "user_id",
+			onIsMFAEnabled:   func() (bool, error) { return true, nil },
+			onAddAttempt:     func() error { return nil },
+			onVerifyCode:     func() error { return nil },
+			onResetAttempts:  func() error { return nil },
+			onGenerate:       func() ([]string, error) { return []string{"AAAA-BBBB-CCCC-DDDD"}, nil },
+			onSaveAuditEvent: func() error { return nil },
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.RegenerateRecoveryCodesV1Response{
+					RecoveryCodes: []string{"AAAA-BBBB-CCCC-DDDD"},
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.userID != "" {
+				ctx = httpx.ContextWithUserID(ctx, testCase.userID)
+			}
+
+			mfaStatus := mocks.NewMFAStatusProvider(t)
+			if testCase.onIsMFAEnabled != nil {
+				mfaStatus.On("IsEnabled", ctx, testCase.userID).Return(testCase.onIsMFAEnabled())
+			}
+
+			userProvider := mocks.NewUserByIdProvider(t)
+			if testCase.onGetUserByID != nil {
+				userProvider.On("GetByID", ctx, testCase.userID).Return(testCase.onGetUserByID())
+			}
+
+			passwordHasher := mocks.NewPasswordHasher(t)
+			if testCase.onVerifyPassword != nil {
+				passwordHasher.On("Verify", ctx, testCase.req.Password, "hashed_password").Return(testCase.onVerifyPassword())
+			}
+
+			attempts := mocks.NewMFAAttempts(t)
+			if testCase.onAddAttempt != nil {
+				attempts.On("Add", ctx, testCase.userID).Return(testCase.onAddAttempt())
+			}
+
+			if testCase.onResetAttempts != nil {
+				attempts.On("Reset", ctx, testCase.userID).Return(testCase.onResetAttempts())
+			}
+
+			totp := mocks.NewTOTPVerifier(t)
+			if testCase.onVerifyCode != nil {
+				totp.On("Verify", ctx, testCase.userID, testCase.req.Code).Return(testCase.onVerifyCode())
+			}
+
+			recoveryCodes := mocks.NewRecoveryCodesGenerator(t)
+			if testCase.onGenerate != nil {
+				recoveryCodes.On("Generate", ctx, testCase.userID).Return(testCase.onGenerate())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.onSaveAuditEvent != nil {
+				auditLog.On("Save", ctx, domain.AuditEvent{
+					Type:    domain.AuditEventRecoveryCodesRegenerated,
+					UserID:  testCase.userID,
+					Outcome: domain.AuditOutcomeSuccess,
+				}).Return(testCase.onSaveAuditEvent())
+			}
+
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", ctx, *testCase.expAuditEvent).Return()
+			}
+
+			handler := handlers.NewRegenerateRecoveryCodesV1(
+				zap.NewNop(),
+				userProvider,
+				passwordHasher,
+				mfaStatus,
+				totp,
+				attempts,
+				recoveryCodes,
+				auditLog,
+			)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(ctx, testCase.req))
+		})
+	}
+}
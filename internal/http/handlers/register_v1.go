@@ -1,6 +1,8 @@
 package handlers
 
 //go:generate mockery --name UserRegistrar --output ./mocks --outpkg mocks --filename user_registrar.go --structname UserRegistrar
+//go:generate mockery --name EmailVerificationSender --output ./mocks --outpkg mocks --filename email_verification_sender.go --structname EmailVerificationSender
+//go:generate mockery --name ExistingAccountNotifier --output ./mocks --outpkg mocks --filename existing_account_notifier.go --structname ExistingAccountNotifier
 
 import (
 	"context"
@@ -10,33 +12,55 @@ import (
 	"github.com/google/uuid"
 	"github.com/riabininkf/go-modules/logger"
 	"github.com/riabininkf/httpx"
-	"golang.org/x/crypto/bcrypt"
 
 	"github.com/riabininkf/http-auth-example/internal/domain"
 )
 
-// NewRegisterV1 creates a new *RegisterV1 instance.
+// NewRegisterV1 creates a new *RegisterV1 instance. If requireVerifiedEmail is set, no tokens are issued
+// until the user verifies the email address. If concealExisting is set, registration answers 202 Accepted without
+// tokens whether the email address is taken or not, and the owner of a taken address is notified by email instead.
 func NewRegisterV1(
 	log *logger.Logger,
 	issuer TokenIssuer,
 	jwtStorage JwtStorage,
 	registrar UserRegistrar,
+	passwordPolicy PasswordValidator,
+	passwordHasher PasswordHasher,
+	emailVerification EmailVerificationSender,
+	existingAccountNotice ExistingAccountNotifier,
+	auditLog AuditRecorder,
+	requireVerifiedEmail bool,
+	concealExisting bool,
 ) *RegisterV1 {
 	return &RegisterV1{
-		log:        log,
-		issuer:     issuer,
-		jwtStorage: jwtStorage,
-		registrar:  registrar,
+		log:                   log,
+		issuer:                issuer,
+		jwtStorage:            jwtStorage,
+		registrar:             registrar,
+		passwordPolicy:        passwordPolicy,
+		passwordHasher:        passwordHasher,
+		emailVerification:     emailVerification,
+		existingAccountNotice: existingAccountNotice,
+		auditLog:              auditLog,
+		requireVerifiedEmail:  requireVerifiedEmail,
+		concealExisting:       concealExisting,
 	}
 }
 
 type (
 	// RegisterV1 registers a new user and issues access and refresh tokens.
 	RegisterV1 struct {
-		log        *logger.Logger
-		issuer     TokenIssuer
-		jwtStorage JwtStorage
-		registrar  UserRegistrar
+		log                   *logger.Logger
+		issuer                TokenIssuer
+		jwtStorage            JwtStorage
+		registrar             UserRegistrar
+		passwordPolicy        PasswordValidator
+		passwordHasher        PasswordHasher
+		emailVerification     EmailVerificationSender
+		existingAccountNotice ExistingAccountNotifier
+		auditLog              AuditRecorder
+		requireVerifiedEmail  bool
+		concealExisting       bool
 	}
 
 	// RegisterV1Request represents register request.
@@ -46,19 +70,32 @@ type (
 	}
 
 	// RegisterV1Response represents successful register response.
+	// Tokens are omitted when the email address must be verified before logging in.
 	RegisterV1Response struct {
 		UserID       string `json:"user_id"`
-		AccessToken  string `json:"access_token"`
-		RefreshToken string `json:"refresh_token"`
+		AccessToken  string `json:"access_token,omitempty"`
+		RefreshToken string `json:"refresh_token,omitempty"`
 	}
 
 	// UserRegistrar describes UserRegistrar dependency.
 	UserRegistrar interface {
 		Save(ctx context.Context, user domain.User) error
 	}
+
+	// EmailVerificationSender describes EmailVerificationSender dependency.
+	EmailVerificationSender interface {
+		Send(ctx context.Context, user domain.User) error
+	}
+
+	// ExistingAccountNotifier describes ExistingAccountNotifier dependency.
+	ExistingAccountNotifier interface {
+		Send(ctx context.Context, email string) error
+	}
 )
 
-// Handle processes the registration request, validates input, creates a user, and issues access and refresh tokens.
+// Handle processes the registration request, validates input, creates a user, emails a verification token,
+// and issues access and refresh tokens. In the concealing mode tokens are not issued, so that the user logs in
+// like the owner of a taken address would have to.
 func (h *RegisterV1) Handle(ctx context.Context, req *RegisterV1Request) *httpx.Response {
 	if req.Email == "" {
 		h.log.Warn("email is missing")
@@ -70,13 +107,20 @@ func (h *RegisterV1) Handle(ctx context.Context, req *RegisterV1Request) *httpx.
 		return httpx.NewErrorResponse(http.StatusBadRequest, "password is required")
 	}
 
-	// validation is skipped for simplicity
+	if resp := validatePassword(h.log, h.passwordPolicy, "password", req.Password, req.Email); resp != nil {
+		return resp
+	}
 
 	var (
 		err            error
-		hashedPassword []byte
+		hashedPassword string
 	)
-	if hashedPassword, err = bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost); err != nil {
+	if hashedPassword, err = h.passwordHasher.Hash(ctx, req.Password); err != nil {
+		if isHashingBusy(err) {
+			h.log.Warn("password hashing is saturated")
+			return hashingBusyResponse
+		}
+
 		h.log.Error("failed to generate password hash", logger.Error(err))
 		return httpx.InternalServerError
 	}
@@ -84,12 +128,22 @@ func (h *RegisterV1) Handle(ctx context.Context, req *RegisterV1Request) *httpx.
 	user := domain.NewUser(
 		uuid.NewString(),
 		req.Email,
-		string(hashedPassword),
+		hashedPassword,
 	)
 
 	if err = h.registrar.Save(ctx, user); err != nil {
 		if errors.Is(err, domain.ErrEmailBusy) {
 			h.log.Warn("user already exists")
+
+			if h.concealExisting {
+				// the owner gets an email just like a new user does, so the response reveals nothing
+				if err = h.existingAccountNotice.Send(ctx, req.Email); err != nil {
+					h.log.Error("failed to send existing account notice", logger.Error(err))
+				}
+
+				return httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted))
+			}
+
 			return httpx.NewErrorResponse(http.StatusBadRequest, "user already exists")
 		}
 
@@ -97,6 +151,28 @@ func (h *RegisterV1) Handle(ctx context.Context, req *RegisterV1Request) *httpx.
 		return httpx.InternalServerError
 	}
 
+	h.auditLog.Record(ctx, domain.AuditEvent{
+		Type:    domain.AuditEventRegistration,
+		UserID:  user.ID(),
+		Outcome: domain.AuditOutcomeSuccess,
+	})
+
+	// the user is already registered and can request another email, so a failure does not fail the registration
+	if err = h.emailVerification.Send(ctx, user); err != nil {
+		h.log.Error("failed to send email verification", logger.Error(err))
+	}
+
+	if h.concealExisting {
+		return httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted))
+	}
+
+	if h.requireVerifiedEmail {
+		return httpx.NewJsonResponse(
+			httpx.WithStatus(http.StatusCreated),
+			httpx.WithBody(&RegisterV1Response{UserID: user.ID()}),
+		)
+	}
+
 	var accessToken string
 	if accessToken, err = h.issuer.IssueAccessToken(user.ID()); err != nil {
 		h.log.Error("failed to issue access token", logger.Error(err))
@@ -109,7 +185,7 @@ func (h *RegisterV1) Handle(ctx context.Context, req *RegisterV1Request) *httpx.
 		return httpx.InternalServerError
 	}
 
-	if err = h.jwtStorage.Save(ctx, refreshToken); err != nil {
+	if err = h.jwtStorage.Save(ctx, user.ID(), refreshToken); err != nil {
 		h.log.Error("failed to save refresh token", logger.Error(err))
 		return httpx.InternalServerError
 	}
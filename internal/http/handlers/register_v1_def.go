@@ -1,21 +1,35 @@
 package handlers
 
 import (
+	"github.com/riabininkf/go-modules/config"
 	"github.com/riabininkf/go-modules/di"
 	"github.com/riabininkf/go-modules/logger"
 
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/audit"
 	"github.com/riabininkf/http-auth-example/internal/jwt"
+	"github.com/riabininkf/http-auth-example/internal/password"
 	"github.com/riabininkf/http-auth-example/internal/repository"
 )
 
-// DefRegisterV1Name is the name of the *RegisterV1 definition.
-const DefRegisterV1Name = "http.register-v1"
+const (
+	// DefRegisterV1Name is the name of the *RegisterV1 definition.
+	DefRegisterV1Name = "http.register-v1"
+
+	configKeyRequireVerifiedEmail        = "auth.emailVerification.required"
+	configKeyRegistrationConcealExisting = "auth.registration.concealExisting"
+)
 
 func init() {
 	di.Add(
 		di.Def[*RegisterV1]{
 			Name: DefRegisterV1Name,
 			Build: func(ctn di.Container) (*RegisterV1, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
 				var log *logger.Logger
 				if err := ctn.Fill(logger.DefName, &log); err != nil {
 					return nil, err
@@ -36,11 +50,43 @@ func init() {
 					return nil, err
 				}
 
+				var passwordPolicy *password.Policy
+				if err := ctn.Fill(password.DefPolicyName, &passwordPolicy); err != nil {
+					return nil, err
+				}
+
+				var passwordHasher *password.Pool
+				if err := ctn.Fill(password.DefPoolName, &passwordHasher); err != nil {
+					return nil, err
+				}
+
+				var emailVerification *account.EmailVerification
+				if err := ctn.Fill(account.DefEmailVerificationName, &emailVerification); err != nil {
+					return nil, err
+				}
+
+				var existingAccountNotice *account.ExistingAccountNotice
+				if err := ctn.Fill(account.DefExistingAccountNoticeName, &existingAccountNotice); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
 				return NewRegisterV1(
 					log,
 					issuer,
 					storage,
 					usersRep,
+					passwordPolicy,
+					passwordHasher,
+					emailVerification,
+					existingAccountNotice,
+					recorder,
+					cfg.GetBool(configKeyRequireVerifiedEmail),
+					cfg.GetBool(configKeyRegistrationConcealExisting),
 				), nil
 			},
 		},
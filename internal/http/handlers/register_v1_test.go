@@ -13,6 +13,7 @@ import (
 	"github.com/riabininkf/http-auth-example/internal/domain"
 	"github.com/riabininkf/http-auth-example/internal/http/handlers"
 	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/password"
 )
 
 func TestRegisterV1_Handle(t *testing.T) {
@@ -21,13 +22,20 @@ func TestRegisterV1_Handle(t *testing.T) {
 	}
 
 	testCases := []struct {
-		name                string
-		req                 func() *handlers.RegisterV1Request
-		onSaveUser          func() error
-		expResp             *httpx.Response
-		onIssueAccessToken  func() (string, error)
-		onIssueRefreshToken func() (string, error)
-		onSaveRefreshToken  func() error
+		name                 string
+		req                  func() *handlers.RegisterV1Request
+		requireVerifiedEmail bool
+		concealExisting      bool
+		onValidatePassword   func() ([]password.Violation, error)
+		onHashPassword       func() (string, error)
+		onSaveUser           func() error
+		onSendVerification   func() error
+		onSendNotice         func() error
+		expRegistrationEvent bool
+		expResp              *httpx.Response
+		onIssueAccessToken   func() (string, error)
+		onIssueRefreshToken  func() (string, error)
+		onSaveRefreshToken   func() error
 	}{
 		{
 			name:    "email is missing",
@@ -40,48 +48,109 @@ func TestRegisterV1_Handle(t *testing.T) {
 			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "password is required"),
 		},
 		{
-			name:       "user already exists",
-			req:        generateRequest,
-			onSaveUser: func() error { return domain.ErrEmailBusy },
-			expResp:    httpx.NewErrorResponse(http.StatusBadRequest, "user already exists"),
+			name:               "failed to validate password",
+			req:                generateRequest,
+			onValidatePassword: func() ([]password.Violation, error) { return nil, assert.AnError },
+			expResp:            httpx.InternalServerError,
 		},
 		{
-			name:       "failed to save user",
-			req:        generateRequest,
-			onSaveUser: func() error { return assert.AnError },
-			expResp:    httpx.InternalServerError,
+			name:               "failed to hash password",
+			req:                generateRequest,
+			onValidatePassword: func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:     func() (string, error) { return "", assert.AnError },
+			expResp:            httpx.InternalServerError,
 		},
 		{
-			name:               "failed to issue access token",
+			name:               "password hashing is saturated",
 			req:                generateRequest,
-			onSaveUser:         func() error { return nil },
-			onIssueAccessToken: func() (string, error) { return "", assert.AnError },
+			onValidatePassword: func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:     func() (string, error) { return "", password.ErrBusy },
+			expResp:            httpx.NewErrorResponse(http.StatusServiceUnavailable, "server is busy, try again later"),
+		},
+		{
+			name: "password does not meet the policy",
+			req:  generateRequest,
+			onValidatePassword: func() ([]password.Violation, error) {
+				return []password.Violation{{Code: password.ViolationTooShort, Message: "must be at least 8 characters long", Params: map[string]int{"min": 8}}}, nil
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusBadRequest),
+				httpx.WithBody(&handlers.ValidationErrorResponse{
+					Error: handlers.ValidationError{
+						Message: "password does not meet the requirements",
+						Details: []handlers.FieldError{{
+							Field:   "password",
+							Code:    password.ViolationTooShort,
+							Message: "password must be at least 8 characters long",
+							Params:  map[string]int{"min": 8},
+						}},
+					},
+				}),
+			),
+		},
+		{
+			name:               "user already exists",
+			req:                generateRequest,
+			onValidatePassword: func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:     func() (string, error) { return "hashed_password", nil },
+			onSaveUser:         func() error { return domain.ErrEmailBusy },
+			expResp:            httpx.NewErrorResponse(http.StatusBadRequest, "user already exists"),
+		},
+		{
+			name:               "failed to save user",
+			req:                generateRequest,
+			onValidatePassword: func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:     func() (string, error) { return "hashed_password", nil },
+			onSaveUser:         func() error { return assert.AnError },
 			expResp:            httpx.InternalServerError,
 		},
 		{
-			name:                "failed to issue refresh token",
-			req:                 generateRequest,
-			onSaveUser:          func() error { return nil },
-			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
-			onIssueRefreshToken: func() (string, error) { return "", assert.AnError },
-			expResp:             httpx.InternalServerError,
+			name:                 "failed to issue access token",
+			req:                  generateRequest,
+			onValidatePassword:   func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:       func() (string, error) { return "hashed_password", nil },
+			onSaveUser:           func() error { return nil },
+			expRegistrationEvent: true,
+			onSendVerification:   func() error { return nil },
+			onIssueAccessToken:   func() (string, error) { return "", assert.AnError },
+			expResp:              httpx.InternalServerError,
+		},
+		{
+			name:                 "failed to issue refresh token",
+			req:                  generateRequest,
+			onValidatePassword:   func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:       func() (string, error) { return "hashed_password", nil },
+			onSaveUser:           func() error { return nil },
+			expRegistrationEvent: true,
+			onSendVerification:   func() error { return nil },
+			onIssueAccessToken:   func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken:  func() (string, error) { return "", assert.AnError },
+			expResp:              httpx.InternalServerError,
 		},
 		{
-			name:                "failed to save refresh token",
-			req:                 generateRequest,
-			onSaveUser:          func() error { return nil },
-			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
-			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
-			onSaveRefreshToken:  func() error { return assert.AnError },
-			expResp:             httpx.InternalServerError,
+			name:                 "failed to save refresh token",
+			req:                  generateRequest,
+			onValidatePassword:   func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:       func() (string, error) { return "hashed_password", nil },
+			onSaveUser:           func() error { return nil },
+			expRegistrationEvent: true,
+			onSendVerification:   func() error { return nil },
+			onIssueAccessToken:   func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken:  func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:   func() error { return assert.AnError },
+			expResp:              httpx.InternalServerError,
 		},
 		{
-			name:                "positive case",
-			req:                 generateRequest,
-			onSaveUser:          func() error { return nil },
-			onIssueAccessToken:  func() (string, error) { return "access_token", nil },
-			onIssueRefreshToken: func() (string, error) { return "refresh_token", nil },
-			onSaveRefreshToken:  func() error { return nil },
+			name:                 "positive case",
+			req:                  generateRequest,
+			onValidatePassword:   func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:       func() (string, error) { return "hashed_password", nil },
+			onSaveUser:           func() error { return nil },
+			expRegistrationEvent: true,
+			onSendVerification:   func() error { return nil },
+			onIssueAccessToken:   func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken:  func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:   func() error { return nil },
 			expResp: httpx.NewJsonResponse(
 				httpx.WithStatus(http.StatusCreated),
 				httpx.WithBody(&handlers.RegisterV1Response{
@@ -90,15 +159,110 @@ func TestRegisterV1_Handle(t *testing.T) {
 				}),
 			),
 		},
+		{
+			name:                 "failed to send email verification",
+			req:                  generateRequest,
+			onValidatePassword:   func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:       func() (string, error) { return "hashed_password", nil },
+			onSaveUser:           func() error { return nil },
+			expRegistrationEvent: true,
+			onSendVerification:   func() error { return assert.AnError },
+			onIssueAccessToken:   func() (string, error) { return "access_token", nil },
+			onIssueRefreshToken:  func() (string, error) { return "refresh_token", nil },
+			onSaveRefreshToken:   func() error { return nil },
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusCreated),
+				httpx.WithBody(&handlers.RegisterV1Response{
+					AccessToken:  "access_token",
+					RefreshToken: "refresh_token",
+				}),
+			),
+		},
+		{
+			name:                 "verified email is required",
+			req:                  generateRequest,
+			requireVerifiedEmail: true,
+			onValidatePassword:   func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:       func() (string, error) { return "hashed_password", nil },
+			onSaveUser:           func() error { return nil },
+			expRegistrationEvent: true,
+			onSendVerification:   func() error { return nil },
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusCreated),
+				httpx.WithBody(&handlers.RegisterV1Response{}),
+			),
+		},
+		{
+			name:               "existing user is concealed",
+			req:                generateRequest,
+			concealExisting:    true,
+			onValidatePassword: func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:     func() (string, error) { return "hashed_password", nil },
+			onSaveUser:         func() error { return domain.ErrEmailBusy },
+			onSendNotice:       func() error { return nil },
+			expResp:            httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted)),
+		},
+		{
+			name:               "failed to send existing account notice",
+			req:                generateRequest,
+			concealExisting:    true,
+			onValidatePassword: func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:     func() (string, error) { return "hashed_password", nil },
+			onSaveUser:         func() error { return domain.ErrEmailBusy },
+			onSendNotice:       func() error { return assert.AnError },
+			expResp:            httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted)),
+		},
+		{
+			name:                 "new user is registered without tokens when existing users are concealed",
+			req:                  generateRequest,
+			concealExisting:      true,
+			onValidatePassword:   func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:       func() (string, error) { return "hashed_password", nil },
+			onSaveUser:           func() error { return nil },
+			expRegistrationEvent: true,
+			onSendVerification:   func() error { return nil },
+			expResp:              httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted)),
+		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
 			req := testCase.req()
 
+			passwordPolicy := mocks.NewPasswordValidator(t)
+			if testCase.onValidatePassword != nil {
+				passwordPolicy.On("Validate", req.Password, req.Email).Return(testCase.onValidatePassword())
+			}
+
+			passwordHasher := mocks.NewPasswordHasher(t)
+			if testCase.onHashPassword != nil {
+				passwordHasher.On("Hash", t.Context(), req.Password).Return(testCase.onHashPassword())
+			}
+
 			registrar := mocks.NewUserRegistrar(t)
 			if testCase.onSaveUser != nil {
-				registrar.On("Save", t.Context(), mock.AnythingOfType("*domain.user")).Return(testCase.onSaveUser())
+				registrar.On("Save", t.Context(), mock.MatchedBy(func(user domain.User) bool {
+					return user.Email() == req.Email && user.HashedPassword() == "hashed_password"
+				})).Return(testCase.onSaveUser())
+			}
+
+			emailVerification := mocks.NewEmailVerificationSender(t)
+			if testCase.onSendVerification != nil {
+				emailVerification.On("Send", t.Context(), mock.AnythingOfType("*domain.user")).
+					Return(testCase.onSendVerification())
+			}
+
+			existingAccountNotice := mocks.NewExistingAccountNotifier(t)
+			if testCase.onSendNotice != nil {
+				existingAccountNotice.On("Send", t.Context(), req.Email).Return(testCase.onSendNotice())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expRegistrationEvent {
+				auditLog.On("Record", t.Context(), mock.MatchedBy(func(event domain.AuditEvent) bool {
+					return event.Type == domain.AuditEventRegistration && event.UserID != "" &&
+						event.Outcome == domain.AuditOutcomeSuccess
+				})).Return()
 			}
 
 			issuer := mocks.NewTokenIssuer(t)
@@ -116,7 +280,7 @@ func TestRegisterV1_Handle(t *testing.T) {
 
 			jwtStorage := mocks.NewJwtStorage(t)
 			if testCase.onSaveRefreshToken != nil {
-				jwtStorage.On("Save", t.Context(), refreshToken).Return(testCase.onSaveRefreshToken())
+				jwtStorage.On("Save", t.Context(), mock.AnythingOfType("string"), refreshToken).Return(testCase.onSaveRefreshToken())
 			}
 
 			handler := handlers.NewRegisterV1(
@@ -124,6 +288,13 @@ func TestRegisterV1_Handle(t *testing.T) {
 				issuer,
 				jwtStorage,
 				registrar,
+				passwordPolicy,
+				passwordHasher,
+				emailVerification,
+				existingAccountNotice,
+				auditLog,
+				testCase.requireVerifiedEmail,
+				testCase.concealExisting,
 			)
 
 			resp := handler.Handle(t.Context(), req)
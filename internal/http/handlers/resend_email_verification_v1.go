@@ -0,0 +1,56 @@
+package handlers
+
+//go:generate mockery --name EmailVerificationResender --output ./mocks --outpkg mocks --filename email_verification_resender.go --structname EmailVerificationResender
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+)
+
+// NewResendEmailVerificationV1 creates a new *ResendEmailVerificationV1 instance.
+func NewResendEmailVerificationV1(
+	log *logger.Logger,
+	emailVerification EmailVerificationResender,
+) *ResendEmailVerificationV1 {
+	return &ResendEmailVerificationV1{
+		log:               log,
+		emailVerification: emailVerification,
+	}
+}
+
+type (
+	// ResendEmailVerificationV1 sends a new verification token to an unverified email address.
+	ResendEmailVerificationV1 struct {
+		log               *logger.Logger
+		emailVerification EmailVerificationResender
+	}
+
+	// ResendEmailVerificationV1Request represents email verification resend request.
+	ResendEmailVerificationV1Request struct {
+		Email string `json:"email"`
+	}
+
+	// EmailVerificationResender describes EmailVerificationResender dependency.
+	EmailVerificationResender interface {
+		Resend(ctx context.Context, email string) error
+	}
+)
+
+// Handle sends a new verification token. The response is the same whether the address is registered,
+// unverified or not, so that the endpoint cannot be used to find accounts.
+func (h *ResendEmailVerificationV1) Handle(ctx context.Context, req *ResendEmailVerificationV1Request) *httpx.Response {
+	if req.Email == "" {
+		h.log.Warn("email is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "email is required")
+	}
+
+	if err := h.emailVerification.Resend(ctx, req.Email); err != nil {
+		h.log.Error("failed to resend email verification", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	return httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted))
+}
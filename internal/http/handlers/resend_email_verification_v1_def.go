@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+)
+
+// DefResendEmailVerificationV1Name is the name of the *ResendEmailVerificationV1 definition.
+const DefResendEmailVerificationV1Name = "http.resend-email-verification-v1"
+
+func init() {
+	di.Add(
+		di.Def[*ResendEmailVerificationV1]{
+			Name: DefResendEmailVerificationV1Name,
+			Build: func(ctn di.Container) (*ResendEmailVerificationV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var emailVerification *account.EmailVerification
+				if err := ctn.Fill(account.DefEmailVerificationName, &emailVerification); err != nil {
+					return nil, err
+				}
+
+				return NewResendEmailVerificationV1(
+					log,
+					emailVerification,
+				), nil
+			},
+		},
+	)
+}
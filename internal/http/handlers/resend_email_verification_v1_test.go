@@ -0,0 +1,53 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+)
+
+func TestResendEmailVerificationV1_Handle(t *testing.T) {
+	testCases := []struct {
+		name     string
+		req      *handlers.ResendEmailVerificationV1Request
+		onResend func() error
+		expResp  *httpx.Response
+	}{
+		{
+			name:    "email is missing",
+			req:     &handlers.ResendEmailVerificationV1Request{},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "email is required"),
+		},
+		{
+			name:     "failed to resend email verification",
+			req:      &handlers.ResendEmailVerificationV1Request{Email: "user@example.com"},
+			onResend: func() error { return assert.AnError },
+			expResp:  httpx.InternalServerError,
+		},
+		{
+			name:     "positive case",
+			req:      &handlers.ResendEmailVerificationV1Request{Email: "user@example.com"},
+			onResend: func() error { return nil },
+			expResp:  httpx.NewJsonResponse(httpx.WithStatus(http.StatusAccepted)),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			emailVerification := mocks.NewEmailVerificationResender(t)
+			if testCase.onResend != nil {
+				emailVerification.On("Resend", t.Context(), testCase.req.Email).Return(testCase.onResend())
+			}
+
+			handler := handlers.NewResendEmailVerificationV1(zap.NewNop(), emailVerification)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), testCase.req))
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package handlers
+
+//go:generate mockery --name PasswordResetRedeemer --output ./mocks --outpkg mocks --filename password_reset_redeemer.go --structname PasswordResetRedeemer
+//go:generate mockery --name SessionRevoker --output ./mocks --outpkg mocks --filename session_revoker.go --structname SessionRevoker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewResetPasswordV1 creates a new *ResetPasswordV1 instance.
+func NewResetPasswordV1(
+	log *logger.Logger,
+	passwordReset PasswordResetRedeemer,
+	passwordUpdater PasswordUpdater,
+	sessions SessionRevoker,
+	passwordPolicy PasswordValidator,
+	passwordHasher PasswordHasher,
+	auditLog AuditRecorder,
+) *ResetPasswordV1 {
+	return &ResetPasswordV1{
+		log:             log,
+		passwordReset:   passwordReset,
+		passwordUpdater: passwordUpdater,
+		sessions:        sessions,
+		passwordPolicy:  passwordPolicy,
+		passwordHasher:  passwordHasher,
+		auditLog:        auditLog,
+	}
+}
+
+type (
+	// ResetPasswordV1 sets a new password with the token emailed by ForgotPasswordV1 and signs the user out everywhere.
+	ResetPasswordV1 struct {
+		log             *logger.Logger
+		passwordReset   PasswordResetRedeemer
+		passwordUpdater PasswordUpdater
+		sessions        SessionRevoker
+		passwordPolicy  PasswordValidator
+		passwordHasher  PasswordHasher
+		auditLog        AuditRecorder
+	}
+
+	// ResetPasswordV1Request represents reset password request.
+	ResetPasswordV1Request struct {
+		Token       string `json:"token"`
+		NewPassword string `json:"new_password"`
+	}
+
+	// PasswordResetRedeemer describes PasswordResetRedeemer dependency.
+	PasswordResetRedeemer interface {
+		Lookup(ctx context.Context, token string) (domain.User, error)
+		Redeem(ctx context.Context, token string) (domain.User, error)
+	}
+
+	// SessionRevoker describes SessionRevoker dependency.
+	SessionRevoker interface {
+		RevokeAll(ctx context.Context, userID string) error
+	}
+)
+
+// Handle checks the new password against the policy, hashes it, redeems the reset token, updates the password and revokes all refresh tokens of the user.
+func (h *ResetPasswordV1) Handle(ctx context.Context, req *ResetPasswordV1Request) *httpx.Response {
+	if req.Token == "" {
+		h.log.Warn("token is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "token is required")
+	}
+
+	if req.NewPassword == "" {
+		h.log.Warn("new password is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "new_password is required")
+	}
+
+	// the token is looked up without consuming it, so that a password rejected by the policy
+	// does not burn the emailed link
+	user, err := h.passwordReset.Lookup(ctx, req.Token)
+	if err != nil {
+		return h.tokenErrorResponse(err)
+	}
+
+	if resp := validatePassword(h.log, h.passwordPolicy, "new_password", req.NewPassword, user.Email()); resp != nil {
+		return resp
+	}
+
+	// the password is hashed before the token is redeemed, so that busy hashing does not burn the link either
+	var hashedPassword string
+	if hashedPassword, err = h.passwordHasher.Hash(ctx, req.NewPassword); err != nil {
+		if isHashingBusy(err) {
+			h.log.Warn("password hashing is saturated")
+			return hashingBusyResponse
+		}
+
+		h.log.Error("failed to generate password hash", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if user, err = h.passwordReset.Redeem(ctx, req.Token); err != nil {
+		return h.tokenErrorResponse(err)
+	}
+
+	if err = h.passwordUpdater.UpdatePassword(ctx, user.ID(), hashedPassword); err != nil {
+		h.log.Error("failed to update password", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if err = h.sessions.RevokeAll(ctx, user.ID()); err != nil {
+		h.log.Error("failed to revoke sessions", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	h.auditLog.Record(ctx, domain.AuditEvent{
+		Type:    domain.AuditEventPasswordReset,
+		UserID:  user.ID(),
+		Outcome: domain.AuditOutcomeSuccess,
+	})
+
+	return httpx.NewJsonResponse(httpx.WithStatus(http.StatusOK))
+}
+
+// tokenErrorResponse maps errors of looking up and redeeming the reset token to a response.
+func (h *ResetPasswordV1) tokenErrorResponse(err error) *httpx.Response {
+	if errors.Is(err, account.ErrInvalidToken) {
+		h.log.Warn("invalid password reset token")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "invalid or expired token")
+	}
+
+	h.log.Error("failed to check password reset token", logger.Error(err))
+	return httpx.InternalServerError
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+	"github.com/riabininkf/http-auth-example/internal/password"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+// DefResetPasswordV1Name is the name of the *ResetPasswordV1 definition.
+const DefResetPasswordV1Name = "http.reset-password-v1"
+
+func init() {
+	di.Add(
+		di.Def[*ResetPasswordV1]{
+			Name: DefResetPasswordV1Name,
+			Build: func(ctn di.Container) (*ResetPasswordV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var passwordReset *account.PasswordReset
+				if err := ctn.Fill(account.DefPasswordResetName, &passwordReset); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				var storage *jwt.Storage
+				if err := ctn.Fill(jwt.DefStorageName, &storage); err != nil {
+					return nil, err
+				}
+
+				var passwordPolicy *password.Policy
+				if err := ctn.Fill(password.DefPolicyName, &passwordPolicy); err != nil {
+					return nil, err
+				}
+
+				var passwordHasher *password.Pool
+				if err := ctn.Fill(password.DefPoolName, &passwordHasher); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewResetPasswordV1(
+					log,
+					passwordReset,
+					usersRep,
+					storage,
+					passwordPolicy,
+					passwordHasher,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
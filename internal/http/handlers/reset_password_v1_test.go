@@ -0,0 +1,211 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestResetPasswordV1_Handle(t *testing.T) {
+	validRequest := &handlers.ResetPasswordV1Request{Token: "token", NewPassword: "new_password"}
+	user := domain.NewUser("user_id", "user@example.com", "hashed_password")
+
+	testCases := []struct {
+		name             string
+		req              *handlers.ResetPasswordV1Request
+		onLookup         func() (domain.User, error)
+		onValidate       func() ([]password.Violation, error)
+		onRedeem         func() (domain.User, error)
+		onHashPassword   func() (string, error)
+		onUpdatePassword func() error
+		onRevokeAll      func() error
+		expAuditEvent    *domain.AuditEvent
+		expResp          *httpx.Response
+	}{
+		{
+			name:    "token is missing",
+			req:     &handlers.ResetPasswordV1Request{NewPassword: "new_password"},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "token is required"),
+		},
+		{
+			name:    "new password is missing",
+			req:     &handlers.ResetPasswordV1Request{Token: "token"},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "new_password is required"),
+		},
+		{
+			name:     "invalid token",
+			req:      validRequest,
+			onLookup: func() (domain.User, error) { return nil, account.ErrInvalidToken },
+			expResp:  httpx.NewErrorResponse(http.StatusBadRequest, "invalid or expired token"),
+		},
+		{
+			name:     "failed to look up token",
+			req:      validRequest,
+			onLookup: func() (domain.User, error) { return nil, assert.AnError },
+			expResp:  httpx.InternalServerError,
+		},
+		{
+			name:       "failed to validate new password",
+			req:        validRequest,
+			onLookup:   func() (domain.User, error) { return user, nil },
+			onValidate: func() ([]password.Violation, error) { return nil, assert.AnError },
+			expResp:    httpx.InternalServerError,
+		},
+		{
+			name:     "new password does not meet the policy",
+			req:      validRequest,
+			onLookup: func() (domain.User, error) { return user, nil },
+			onValidate: func() ([]password.Violation, error) {
+				return []password.Violation{{
+					Code:    password.ViolationTooShort,
+					Message: "must be at least 20 characters long",
+					Params:  map[string]int{"min": 20},
+				}}, nil
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusBadRequest),
+				httpx.WithBody(&handlers.ValidationErrorResponse{
+					Error: handlers.ValidationError{
+						Message: "password does not meet the requirements",
+						Details: []handlers.FieldError{{
+							Field:   "new_password",
+							Code:    password.ViolationTooShort,
+							Message: "new_password must be at least 20 characters long",
+							Params:  map[string]int{"min": 20},
+						}},
+					},
+				}),
+			),
+		},
+		{
+			name:           "password hashing is saturated",
+			req:            validRequest,
+			onLookup:       func() (domain.User, error) { return user, nil },
+			onValidate:     func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword: func() (string, error) { return "", password.ErrBusy },
+			expResp:        httpx.NewErrorResponse(http.StatusServiceUnavailable, "server is busy, try again later"),
+		},
+		{
+			name:           "failed to hash password",
+			req:            validRequest,
+			onLookup:       func() (domain.User, error) { return user, nil },
+			onValidate:     func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword: func() (string, error) { return "", assert.AnError },
+			expResp:        httpx.InternalServerError,
+		},
+		{
+			name:           "token redeemed concurrently",
+			req:            validRequest,
+			onLookup:       func() (domain.User, error) { return user, nil },
+			onValidate:     func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword: func() (string, error) { return "hashed_password", nil },
+			onRedeem:       func() (domain.User, error) { return nil, account.ErrInvalidToken },
+			expResp:        httpx.NewErrorResponse(http.StatusBadRequest, "invalid or expired token"),
+		},
+		{
+			name:           "failed to redeem token",
+			req:            validRequest,
+			onLookup:       func() (domain.User, error) { return user, nil },
+			onValidate:     func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword: func() (string, error) { return "hashed_password", nil },
+			onRedeem:       func() (domain.User, error) { return nil, assert.AnError },
+			expResp:        httpx.InternalServerError,
+		},
+		{
+			name:             "failed to update password",
+			req:              validRequest,
+			onLookup:         func() (domain.User, error) { return user, nil },
+			onValidate:       func() ([]password.Violation, error) { return nil, nil },
+			onRedeem:         func() (domain.User, error) { return user, nil },
+			onHashPassword:   func() (string, error) { return "hashed_password", nil },
+			onUpdatePassword: func() error { return assert.AnError },
+			expResp:          httpx.InternalServerError,
+		},
+		{
+			name:             "failed to revoke sessions",
+			req:              validRequest,
+			onLookup:         func() (domain.User, error) { return user, nil },
+			onValidate:       func() ([]password.Violation, error) { return nil, nil },
+			onRedeem:         func() (domain.User, error) { return user, nil },
+			onHashPassword:   func() (string, error) { return "hashed_password", nil },
+			onUpdatePassword: func() error { return nil },
+			onRevokeAll:      func() error { return assert.AnError },
+			expResp:          httpx.InternalServerError,
+		},
+		{
+			name:             "positive case",
+			req:              validRequest,
+			onLookup:         func() (domain.User, error) { return user, nil },
+			onValidate:       func() ([]password.Violation, error) { return nil, nil },
+			onRedeem:         func() (domain.User, error) { return user, nil },
+			onHashPassword:   func() (string, error) { return "hashed_password", nil },
+			onUpdatePassword: func() error { return nil },
+			onRevokeAll:      func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventPasswordReset,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+			},
+			expResp: httpx.NewJsonResponse(httpx.WithStatus(http.StatusOK)),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			passwordReset := mocks.NewPasswordResetRedeemer(t)
+			if testCase.onLookup != nil {
+				passwordReset.On("Lookup", t.Context(), testCase.req.Token).Return(testCase.onLookup())
+			}
+
+			if testCase.onRedeem != nil {
+				passwordReset.On("Redeem", t.Context(), testCase.req.Token).Return(testCase.onRedeem())
+			}
+
+			passwordPolicy := mocks.NewPasswordValidator(t)
+			if testCase.onValidate != nil {
+				passwordPolicy.On("Validate", testCase.req.NewPassword, "user@example.com").Return(testCase.onValidate())
+			}
+
+			passwordHasher := mocks.NewPasswordHasher(t)
+			if testCase.onHashPassword != nil {
+				passwordHasher.On("Hash", t.Context(), testCase.req.NewPassword).Return(testCase.onHashPassword())
+			}
+
+			passwordUpdater := mocks.NewPasswordUpdater(t)
+			if testCase.onUpdatePassword != nil {
+				passwordUpdater.On("UpdatePassword", t.Context(), "user_id", "hashed_password").Return(testCase.onUpdatePassword())
+			}
+
+			sessions := mocks.NewSessionRevoker(t)
+			if testCase.onRevokeAll != nil {
+				sessions.On("RevokeAll", t.Context(), "user_id").Return(testCase.onRevokeAll())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", t.Context(), *testCase.expAuditEvent).Return()
+			}
+
+			handler := handlers.NewResetPasswordV1(
+				zap.NewNop(),
+				passwordReset,
+				passwordUpdater,
+				sessions,
+				passwordPolicy,
+				passwordHasher,
+				auditLog,
+			)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), testCase.req))
+		})
+	}
+}
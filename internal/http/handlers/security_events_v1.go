@@ -0,0 +1,98 @@
+package handlers
+
+//go:generate mockery --name SecurityEventsProvider --output ./mocks --outpkg mocks --filename security_events_provider.go --structname SecurityEventsProvider
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewSecurityEventsV1 creates a new *SecurityEventsV1 instance. At most limit of the latest events are returned.
+func NewSecurityEventsV1(
+	log *logger.Logger,
+	events SecurityEventsProvider,
+	limit int,
+) *SecurityEventsV1 {
+	return &SecurityEventsV1{
+		log:    log,
+		events: events,
+		limit:  limit,
+	}
+}
+
+type (
+	// SecurityEventsV1 shows the authenticated user the audit trail of their account, e.g. logins and password changes.
+	SecurityEventsV1 struct {
+		log    *logger.Logger
+		events SecurityEventsProvider
+		limit  int
+	}
+
+	// SecurityEventsV1Request represents security events request.
+	SecurityEventsV1Request struct{}
+
+	// SecurityEventsV1Response holds the latest security events of the user, newest first.
+	SecurityEventsV1Response struct {
+		Events []SecurityEvent `json:"events"`
+	}
+
+	// SecurityEvent is an audit event as shown to the user it is about.
+	SecurityEvent struct {
+		Type      string            `json:"type"`
+		Outcome   string            `json:"outcome"`
+		Reason    string            `json:"reason,omitempty"`
+		IP        string            `json:"ip,omitempty"`
+		UserAgent string            `json:"user_agent,omitempty"`
+		SessionID string            `json:"session_id,omitempty"`
+		Details   map[string]string `json:"details,omitempty"`
+		CreatedAt time.Time         `json:"created_at"`
+	}
+
+	// SecurityEventsProvider describes SecurityEventsProvider dependency.
+	SecurityEventsProvider interface {
+		ListByUser(ctx context.Context, userID string, limit int) ([]domain.AuditEvent, error)
+	}
+)
+
+// Handle returns the latest security events of the authenticated user.
+func (h *SecurityEventsV1) Handle(ctx context.Context, _ *SecurityEventsV1Request) *httpx.Response {
+	var (
+		ok     bool
+		userID string
+	)
+	if userID, ok = httpx.GetUserID(ctx); !ok {
+		h.log.Warn("user id is missing")
+		return httpx.BadRequest
+	}
+
+	events, err := h.events.ListByUser(ctx, userID, h.limit)
+	if err != nil {
+		h.log.Error("failed to list security events", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	resp := &SecurityEventsV1Response{Events: make([]SecurityEvent, 0, len(events))}
+	for _, event := range events {
+		resp.Events = append(resp.Events, SecurityEvent{
+			Type:      event.Type,
+			Outcome:   event.Outcome,
+			Reason:    event.Reason,
+			IP:        event.IP,
+			UserAgent: event.UserAgent,
+			SessionID: event.SessionID,
+			Details:   event.Details,
+			CreatedAt: event.CreatedAt,
+		})
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(resp),
+	)
+}
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+const (
+	// DefSecurityEventsV1Name is the name of the *SecurityEventsV1 definition.
+	DefSecurityEventsV1Name = "http.security-events-v1"
+
+	configKeySecurityEventsLimit = "auth.audit.securityEventsLimit"
+)
+
+func init() {
+	di.Add(
+		di.Def[*SecurityEventsV1]{
+			Name: DefSecurityEventsV1Name,
+			Build: func(ctn di.Container) (*SecurityEventsV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var limit int
+				if limit = cfg.GetInt(configKeySecurityEventsLimit); limit == 0 {
+					return nil, config.NewErrMissingKey(configKeySecurityEventsLimit)
+				}
+
+				var auditEventsRep *repository.AuditEvents
+				if err := ctn.Fill(repository.DefAuditEventsName, &auditEventsRep); err != nil {
+					return nil, err
+				}
+
+				return NewSecurityEventsV1(log, auditEventsRep, limit), nil
+			},
+		},
+	)
+}
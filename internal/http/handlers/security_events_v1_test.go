@@ -0,0 +1,115 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+)
+
+func TestSecurityEventsV1_Handle(t *testing.T) {
+	createdAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		userID       string
+		onListByUser func() ([]domain.AuditEvent, error)
+		expResp      *httpx.Response
+	}{
+		{
+			name:    "user id is missing",
+			expResp: httpx.BadRequest,
+		},
+		{
+			name:         "failed to list events",
+			userID:       "user_id",
+			onListByUser: func() ([]domain.AuditEvent, error) { return nil, assert.AnError },
+			expResp:      httpx.InternalServerError,
+		},
+		{
+			name:         "no events",
+			userID:       "user_id",
+			onListByUser: func() ([]domain.AuditEvent, error) { return nil, nil },
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.SecurityEventsV1Response{Events: []handlers.SecurityEvent{}}),
+			),
+		},
+		{
+			name:   "positive case",
+			userID: "user_id",
+			onListByUser: func() ([]domain.AuditEvent, error) {
+				return []domain.AuditEvent{
+					{
+						ID:        2,
+						Type:      domain.AuditEventLogin,
+						UserID:    "user_id",
+						IP:        "203.0.113.7",
+						UserAgent: "curl/8.0",
+						SessionID: "6c8a7d4aa21708a4",
+						Outcome:   domain.AuditOutcomeSuccess,
+						Details:   map[string]string{"method": "password"},
+						CreatedAt: createdAt,
+					},
+					{
+						ID:        1,
+						Type:      domain.AuditEventLogin,
+						UserID:    "user_id",
+						IP:        "198.51.100.1",
+						Outcome:   domain.AuditOutcomeFailure,
+						Reason:    "invalid_credentials",
+						CreatedAt: createdAt.Add(-time.Minute),
+					},
+				}, nil
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.SecurityEventsV1Response{
+					Events: []handlers.SecurityEvent{
+						{
+							Type:      "login",
+							Outcome:   "success",
+							IP:        "203.0.113.7",
+							UserAgent: "curl/8.0",
+							SessionID: "6c8a7d4aa21708a4",
+							Details:   map[string]string{"method": "password"},
+							CreatedAt: createdAt,
+						},
+						{
+							Type:      "login",
+							Outcome:   "failure",
+							Reason:    "invalid_credentials",
+							IP:        "198.51.100.1",
+							CreatedAt: createdAt.Add(-time.Minute),
+						},
+					},
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.userID != "" {
+				ctx = httpx.ContextWithUserID(ctx, testCase.userID)
+			}
+
+			events := mocks.NewSecurityEventsProvider(t)
+			if testCase.onListByUser != nil {
+				events.On("ListByUser", ctx, testCase.userID, 50).Return(testCase.onListByUser())
+			}
+
+			handler := handlers.NewSecurityEventsV1(zap.NewNop(), events, 50)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(ctx, &handlers.SecurityEventsV1Request{}))
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package handlers
+
+//go:generate mockery --name EmailLoginStarter --output ./mocks --outpkg mocks --filename email_login_starter.go --structname EmailLoginStarter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+)
+
+// NewStartEmailLoginV1 creates a new *StartEmailLoginV1 instance.
+func NewStartEmailLoginV1(
+	log *logger.Logger,
+	emailLogin EmailLoginStarter,
+) *StartEmailLoginV1 {
+	return &StartEmailLoginV1{
+		log:        log,
+		emailLogin: emailLogin,
+	}
+}
+
+type (
+	// StartEmailLoginV1 emails a one-time login link and code to the owner of an email address.
+	StartEmailLoginV1 struct {
+		log        *logger.Logger
+		emailLogin EmailLoginStarter
+	}
+
+	// StartEmailLoginV1Request represents email login request.
+	StartEmailLoginV1Request struct {
+		Email string `json:"email"`
+	}
+
+	// StartEmailLoginV1Response represents started email login response. The login token has to be kept
+	// by the client and sent with the code or the link token to FinishEmailLoginV1.
+	StartEmailLoginV1Response struct {
+		LoginToken string `json:"login_token"`
+		ExpiresIn  int64  `json:"expires_in"`
+	}
+
+	// EmailLoginStarter describes EmailLoginStarter dependency.
+	EmailLoginStarter interface {
+		Start(ctx context.Context, email string) (account.EmailLoginToken, error)
+	}
+)
+
+// Handle starts an email login. The response is the same whether the address is registered or not,
+// so that the endpoint cannot be used to find accounts.
+func (h *StartEmailLoginV1) Handle(ctx context.Context, req *StartEmailLoginV1Request) *httpx.Response {
+	if req.Email == "" {
+		h.log.Warn("email is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "email is required")
+	}
+
+	token, err := h.emailLogin.Start(ctx, req.Email)
+	if err != nil {
+		if errors.Is(err, account.ErrTooManyRequests) {
+			h.log.Warn("email login was started too recently")
+			return httpx.NewErrorResponse(http.StatusTooManyRequests, "too many requests")
+		}
+
+		h.log.Error("failed to start email login", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusAccepted),
+		httpx.WithBody(&StartEmailLoginV1Response{
+			LoginToken: token.Token,
+			ExpiresIn:  int64(token.ExpiresIn.Seconds()),
+		}),
+	)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+)
+
+// DefStartEmailLoginV1Name is the name of the *StartEmailLoginV1 definition.
+const DefStartEmailLoginV1Name = "http.start-email-login-v1"
+
+func init() {
+	di.Add(
+		di.Def[*StartEmailLoginV1]{
+			Name: DefStartEmailLoginV1Name,
+			Build: func(ctn di.Container) (*StartEmailLoginV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var emailLogin *account.EmailLogin
+				if err := ctn.Fill(account.DefEmailLoginName, &emailLogin); err != nil {
+					return nil, err
+				}
+
+				return NewStartEmailLoginV1(
+					log,
+					emailLogin,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,66 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+)
+
+func TestStartEmailLoginV1_Handle(t *testing.T) {
+	testCases := []struct {
+		name    string
+		req     *handlers.StartEmailLoginV1Request
+		onStart func() (account.EmailLoginToken, error)
+		expResp *httpx.Response
+	}{
+		{
+			name:    "email is missing",
+			req:     &handlers.StartEmailLoginV1Request{},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "email is required"),
+		},
+		{
+			name:    "too many requests",
+			req:     &handlers.StartEmailLoginV1Request{Email: "user@example.com"},
+			onStart: func() (account.EmailLoginToken, error) { return account.EmailLoginToken{}, account.ErrTooManyRequests },
+			expResp: httpx.NewErrorResponse(http.StatusTooManyRequests, "too many requests"),
+		},
+		{
+			name:    "failed to start email login",
+			req:     &handlers.StartEmailLoginV1Request{Email: "user@example.com"},
+			onStart: func() (account.EmailLoginToken, error) { return account.EmailLoginToken{}, assert.AnError },
+			expResp: httpx.InternalServerError,
+		},
+		{
+			name: "positive case",
+			req:  &handlers.StartEmailLoginV1Request{Email: "user@example.com"},
+			onStart: func() (account.EmailLoginToken, error) {
+				return account.EmailLoginToken{Token: "login_token", ExpiresIn: 10 * time.Minute}, nil
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusAccepted),
+				httpx.WithBody(&handlers.StartEmailLoginV1Response{LoginToken: "login_token", ExpiresIn: 600}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			emailLogin := mocks.NewEmailLoginStarter(t)
+			if testCase.onStart != nil {
+				emailLogin.On("Start", t.Context(), testCase.req.Email).Return(testCase.onStart())
+			}
+
+			handler := handlers.NewStartEmailLoginV1(zap.NewNop(), emailLogin)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), testCase.req))
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package handlers
+
+//go:generate mockery --name AccessClaimsVerifier --output ./mocks --outpkg mocks --filename access_claims_verifier.go --structname AccessClaimsVerifier
+//go:generate mockery --name AdminChecker --output ./mocks --outpkg mocks --filename admin_checker.go --structname AdminChecker
+//go:generate mockery --name ImpersonationTokenIssuer --output ./mocks --outpkg mocks --filename impersonation_token_issuer.go --structname ImpersonationTokenIssuer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+)
+
+// grantTypeTokenExchange is the grant type of the token exchange grant (RFC 8693, section 2.1).
+const grantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+
+// tokenTypeAccessTokenURN is the only token type accepted and issued by the token exchange grant.
+const tokenTypeAccessTokenURN = "urn:ietf:params:oauth:token-type:access_token"
+
+// NewTokenExchangeGrant creates a new *TokenExchangeGrant instance.
+// Impersonation tokens live for ttl, but never longer than regular access tokens, and may carry only the given scopes.
+func NewTokenExchangeGrant(
+	log *logger.Logger,
+	verifier AccessClaimsVerifier,
+	admins AdminChecker,
+	userProvider UserByIdProvider,
+	issuer ImpersonationTokenIssuer,
+	auditLog AuditRecorder,
+	ttl time.Duration,
+	scopes []string,
+) *TokenExchangeGrant {
+	return &TokenExchangeGrant{
+		log:          log,
+		verifier:     verifier,
+		admins:       admins,
+		userProvider: userProvider,
+		issuer:       issuer,
+		auditLog:     auditLog,
+		ttl:          ttl,
+		scopes:       scopes,
+	}
+}
+
+type (
+	// TokenExchangeGrant lets admins exchange their access token for a short-lived token of another user.
+	// The issued token carries an act claim naming the admin, so that impersonated requests can be told apart.
+	TokenExchangeGrant struct {
+		log          *logger.Logger
+		verifier     AccessClaimsVerifier
+		admins       AdminChecker
+		userProvider UserByIdProvider
+		issuer       ImpersonationTokenIssuer
+		auditLog     AuditRecorder
+		ttl          time.Duration
+		scopes       []string
+	}
+
+	// AccessClaimsVerifier describes AccessClaimsVerifier dependency.
+	AccessClaimsVerifier interface {
+		VerifyAccessClaims(ctx context.Context, token string) (jwt.AccessClaims, error)
+	}
+
+	// AdminChecker describes AdminChecker dependency.
+	AdminChecker interface {
+		IsAdmin(userID string) bool
+	}
+
+	// ImpersonationTokenIssuer describes ImpersonationTokenIssuer dependency.
+	ImpersonationTokenIssuer interface {
+		IssueImpersonationToken(userID string, actorID string, scope string, ttl time.Duration) (string, error)
+		AccessTokenTTL() time.Duration
+	}
+)
+
+// GrantType implements TokenGrant.
+func (g *TokenExchangeGrant) GrantType() string {
+	return grantTypeTokenExchange
+}
+
+// Grant verifies that the subject token belongs to an admin and issues an impersonation token
+// for the requested subject. Every impersonation is written to the audit trail before the token is issued.
+func (g *TokenExchangeGrant) Grant(ctx context.Context, req *TokenV1Request) *httpx.Response {
+	if req.SubjectToken == "" {
+		g.log.Warn("subject_token is missing")
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "subject_token is required")
+	}
+
+	if req.SubjectTokenType != tokenTypeAccessTokenURN {
+		g.log.Warn("unsupported subject token type", logger.String("subject_token_type", req.SubjectTokenType))
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "subject_token_type must be "+tokenTypeAccessTokenURN)
+	}
+
+	if req.RequestedTokenType != "" && req.RequestedTokenType != tokenTypeAccessTokenURN {
+		g.log.Warn("unsupported requested token type", logger.String("requested_token_type", req.RequestedTokenType))
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "requested_token_type must be "+tokenTypeAccessTokenURN)
+	}
+
+	if req.RequestedSubject == "" {
+		g.log.Warn("requested_subject is missing")
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "requested_subject is required")
+	}
+
+	if _, err := uuid.Parse(req.RequestedSubject); err != nil {
+		g.log.Warn("invalid requested subject", logger.Error(err))
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "invalid requested_subject")
+	}
+
+	var (
+		err    error
+		claims jwt.AccessClaims
+	)
+	if claims, err = g.verifier.VerifyAccessClaims(ctx, req.SubjectToken); err != nil {
+		g.log.Warn("invalid subject token", logger.Error(err))
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidGrant, "invalid subject_token")
+	}
+
+	// an impersonation token must not be used to start another impersonation
+	if claims.ActorID != "" {
+		g.log.Warn("subject token is an impersonation token", logger.String("actor_id", claims.ActorID))
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidGrant, "impersonation tokens cannot be exchanged")
+	}
+
+	if !g.admins.IsAdmin(claims.Subject) {
+		g.log.Warn("user is not allowed to impersonate", logger.String("user_id", claims.Subject))
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidGrant, "not allowed to impersonate users")
+	}
+
+	if req.RequestedSubject == claims.Subject || g.admins.IsAdmin(req.RequestedSubject) {
+		g.log.Warn("requested subject cannot be impersonated", logger.String("requested_subject", req.RequestedSubject))
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidGrant, "requested_subject cannot be impersonated")
+	}
+
+	if _, err = g.userProvider.GetByID(ctx, req.RequestedSubject); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			g.log.Warn("requested subject not found")
+			return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidGrant, "unknown requested_subject")
+		}
+
+		g.log.Error("failed to get user by id", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	scope, ok := g.resolveScope(req.Scope)
+	if !ok {
+		g.log.Warn("requested scope is not allowed for impersonation", logger.String("scope", req.Scope))
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidScope, "")
+	}
+
+	ttl := min(g.ttl, g.issuer.AccessTokenTTL())
+
+	if err = g.auditLog.Save(ctx, domain.AuditEvent{
+		Type:    domain.AuditEventImpersonation,
+		UserID:  req.RequestedSubject,
+		ActorID: claims.Subject,
+		Details: map[string]string{
+			"scope": scope,
+			"ttl":   ttl.String(),
+		},
+	}); err != nil {
+		g.log.Error("failed to save audit event", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	var accessToken string
+	if accessToken, err = g.issuer.IssueImpersonationToken(req.RequestedSubject, claims.Subject, scope, ttl); err != nil {
+		g.log.Error("failed to issue impersonation token", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	g.log.Info("impersonation token issued",
+		logger.String("actor_id", claims.Subject),
+		logger.String("user_id", req.RequestedSubject),
+	)
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&TokenV1Response{
+			AccessToken:     accessToken,
+			IssuedTokenType: tokenTypeAccessTokenURN,
+			TokenType:       tokenTypeBearer,
+			ExpiresIn:       int64(ttl.Seconds()),
+			Scope:           scope,
+		}),
+	)
+}
+
+// resolveScope returns the scope of the impersonation token. Without a requested scope all allowed scopes
+// are granted, otherwise every requested scope must be allowed.
+func (g *TokenExchangeGrant) resolveScope(requested string) (string, bool) {
+	if requested == "" {
+		return strings.Join(g.scopes, " "), true
+	}
+
+	for _, scope := range strings.Fields(requested) {
+		if !slices.Contains(g.scopes, scope) {
+			return "", false
+		}
+	}
+
+	return strings.Join(strings.Fields(requested), " "), true
+}
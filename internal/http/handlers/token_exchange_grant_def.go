@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/auth"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+const (
+	// DefTokenExchangeGrantName is the name of the *TokenExchangeGrant definition.
+	DefTokenExchangeGrantName = "http.token-exchange-grant"
+
+	configKeyImpersonationTokenTTL = "auth.impersonation.tokenTTL"
+	configKeyImpersonationScopes   = "auth.impersonation.scopes"
+)
+
+func init() {
+	di.Add(
+		di.Def[*TokenExchangeGrant]{
+			Name: DefTokenExchangeGrantName,
+			Build: func(ctn di.Container) (*TokenExchangeGrant, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var ttl time.Duration
+				if ttl = cfg.GetDuration(configKeyImpersonationTokenTTL); ttl == 0 {
+					return nil, config.NewErrMissingKey(configKeyImpersonationTokenTTL)
+				}
+
+				var verifier *jwt.Verifier
+				if err := ctn.Fill(jwt.DefVerifierName, &verifier); err != nil {
+					return nil, err
+				}
+
+				var admins *auth.Admins
+				if err := ctn.Fill(auth.DefAdminsName, &admins); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				var issuer *jwt.Issuer
+				if err := ctn.Fill(jwt.DefIssuerName, &issuer); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewTokenExchangeGrant(
+					log,
+					verifier,
+					admins,
+					usersRep,
+					issuer,
+					recorder,
+					ttl,
+					cfg.GetStringSlice(configKeyImpersonationScopes),
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,261 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+)
+
+func TestTokenExchangeGrant_Grant(t *testing.T) {
+	const (
+		adminID = "7f0c3f4e-8c55-4f43-9d0a-0f6f5d2a4b11"
+		userID  = "2b1e6a57-2c1f-4c6b-a1a8-52d8d4a7e3c9"
+	)
+
+	generateRequest := func() *handlers.TokenV1Request {
+		return &handlers.TokenV1Request{
+			GrantType:        "urn:ietf:params:oauth:grant-type:token-exchange",
+			SubjectToken:     "admin_token",
+			SubjectTokenType: "urn:ietf:params:oauth:token-type:access_token",
+			RequestedSubject: userID,
+		}
+	}
+
+	withRequest := func(modify func(req *handlers.TokenV1Request)) func() *handlers.TokenV1Request {
+		return func() *handlers.TokenV1Request {
+			req := generateRequest()
+			modify(req)
+			return req
+		}
+	}
+
+	adminClaims := func() (jwt.AccessClaims, error) {
+		return jwt.AccessClaims{Subject: adminID}, nil
+	}
+
+	oauthError := func(code string, description string) *httpx.Response {
+		return httpx.NewJsonResponse(
+			httpx.WithStatus(http.StatusBadRequest),
+			httpx.WithBody(&handlers.OAuthErrorResponse{Error: code, ErrorDescription: description}),
+		)
+	}
+
+	testCases := []struct {
+		name         string
+		req          func() *handlers.TokenV1Request
+		onVerify     func() (jwt.AccessClaims, error)
+		admins       []string
+		onGetUser    func() (domain.User, error)
+		onSaveEvent  func() error
+		onIssueToken func() (string, error)
+		expScope     string
+		expResp      *httpx.Response
+	}{
+		{
+			name:    "subject token is missing",
+			req:     withRequest(func(req *handlers.TokenV1Request) { req.SubjectToken = "" }),
+			expResp: oauthError("invalid_request", "subject_token is required"),
+		},
+		{
+			name:    "unsupported subject token type",
+			req:     withRequest(func(req *handlers.TokenV1Request) { req.SubjectTokenType = "urn:ietf:params:oauth:token-type:jwt" }),
+			expResp: oauthError("invalid_request", "subject_token_type must be urn:ietf:params:oauth:token-type:access_token"),
+		},
+		{
+			name: "unsupported requested token type",
+			req: withRequest(func(req *handlers.TokenV1Request) {
+				req.RequestedTokenType = "urn:ietf:params:oauth:token-type:refresh_token"
+			}),
+			expResp: oauthError("invalid_request", "requested_token_type must be urn:ietf:params:oauth:token-type:access_token"),
+		},
+		{
+			name:    "requested subject is missing",
+			req:     withRequest(func(req *handlers.TokenV1Request) { req.RequestedSubject = "" }),
+			expResp: oauthError("invalid_request", "requested_subject is required"),
+		},
+		{
+			name:    "requested subject is not a user id",
+			req:     withRequest(func(req *handlers.TokenV1Request) { req.RequestedSubject = "user@example.com" }),
+			expResp: oauthError("invalid_request", "invalid requested_subject"),
+		},
+		{
+			name:     "invalid subject token",
+			req:      generateRequest,
+			onVerify: func() (jwt.AccessClaims, error) { return jwt.AccessClaims{}, assert.AnError },
+			expResp:  oauthError("invalid_grant", "invalid subject_token"),
+		},
+		{
+			name: "subject token is an impersonation token",
+			req:  generateRequest,
+			onVerify: func() (jwt.AccessClaims, error) {
+				return jwt.AccessClaims{Subject: adminID, ActorID: "another_admin"}, nil
+			},
+			admins:  []string{adminID},
+			expResp: oauthError("invalid_grant", "impersonation tokens cannot be exchanged"),
+		},
+		{
+			name:     "subject is not an admin",
+			req:      generateRequest,
+			onVerify: adminClaims,
+			expResp:  oauthError("invalid_grant", "not allowed to impersonate users"),
+		},
+		{
+			name:     "admin impersonates themselves",
+			req:      withRequest(func(req *handlers.TokenV1Request) { req.RequestedSubject = adminID }),
+			onVerify: adminClaims,
+			admins:   []string{adminID},
+			expResp:  oauthError("invalid_grant", "requested_subject cannot be impersonated"),
+		},
+		{
+			name:     "requested subject is an admin",
+			req:      generateRequest,
+			onVerify: adminClaims,
+			admins:   []string{adminID, userID},
+			expResp:  oauthError("invalid_grant", "requested_subject cannot be impersonated"),
+		},
+		{
+			name:      "requested subject not found",
+			req:       generateRequest,
+			onVerify:  adminClaims,
+			admins:    []string{adminID},
+			onGetUser: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			expResp:   oauthError("invalid_grant", "unknown requested_subject"),
+		},
+		{
+			name:      "failed to get requested subject",
+			req:       generateRequest,
+			onVerify:  adminClaims,
+			admins:    []string{adminID},
+			onGetUser: func() (domain.User, error) { return nil, assert.AnError },
+			expResp:   httpx.InternalServerError,
+		},
+		{
+			name:     "requested scope is not allowed",
+			req:      withRequest(func(req *handlers.TokenV1Request) { req.Scope = "read write" }),
+			onVerify: adminClaims,
+			admins:   []string{adminID},
+			onGetUser: func() (domain.User, error) {
+				return domain.NewUser(userID, "user@example.com", "hashed_password"), nil
+			},
+			expResp: oauthError("invalid_scope", ""),
+		},
+		{
+			name:     "failed to save audit event",
+			req:      generateRequest,
+			onVerify: adminClaims,
+			admins:   []string{adminID},
+			onGetUser: func() (domain.User, error) {
+				return domain.NewUser(userID, "user@example.com", "hashed_password"), nil
+			},
+			onSaveEvent: func() error { return assert.AnError },
+			expScope:    "read",
+			expResp:     httpx.InternalServerError,
+		},
+		{
+			name:     "failed to issue token",
+			req:      generateRequest,
+			onVerify: adminClaims,
+			admins:   []string{adminID},
+			onGetUser: func() (domain.User, error) {
+				return domain.NewUser(userID, "user@example.com", "hashed_password"), nil
+			},
+			onSaveEvent:  func() error { return nil },
+			onIssueToken: func() (string, error) { return "", assert.AnError },
+			expScope:     "read",
+			expResp:      httpx.InternalServerError,
+		},
+		{
+			name:     "positive case",
+			req:      withRequest(func(req *handlers.TokenV1Request) { req.Scope = "read" }),
+			onVerify: adminClaims,
+			admins:   []string{adminID},
+			onGetUser: func() (domain.User, error) {
+				return domain.NewUser(userID, "user@example.com", "hashed_password"), nil
+			},
+			onSaveEvent:  func() error { return nil },
+			onIssueToken: func() (string, error) { return "impersonation_token", nil },
+			expScope:     "read",
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.TokenV1Response{
+					AccessToken:     "impersonation_token",
+					IssuedTokenType: "urn:ietf:params:oauth:token-type:access_token",
+					TokenType:       "Bearer",
+					ExpiresIn:       60,
+					Scope:           "read",
+				}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := testCase.req()
+
+			verifier := mocks.NewAccessClaimsVerifier(t)
+			if testCase.onVerify != nil {
+				verifier.On("VerifyAccessClaims", t.Context(), req.SubjectToken).Return(testCase.onVerify())
+			}
+
+			admins := mocks.NewAdminChecker(t)
+			admins.On("IsAdmin", mock.AnythingOfType("string")).Maybe().Return(func(userID string) bool {
+				for _, admin := range testCase.admins {
+					if admin == userID {
+						return true
+					}
+				}
+
+				return false
+			})
+
+			userProvider := mocks.NewUserByIdProvider(t)
+			if testCase.onGetUser != nil {
+				userProvider.On("GetByID", t.Context(), req.RequestedSubject).Return(testCase.onGetUser())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.onSaveEvent != nil {
+				auditLog.On("Save", t.Context(), domain.AuditEvent{
+					Type:    domain.AuditEventImpersonation,
+					UserID:  userID,
+					ActorID: adminID,
+					Details: map[string]string{"scope": testCase.expScope, "ttl": "1m0s"},
+				}).Return(testCase.onSaveEvent())
+			}
+
+			issuer := mocks.NewImpersonationTokenIssuer(t)
+			if testCase.onSaveEvent != nil {
+				issuer.On("AccessTokenTTL").Return(time.Hour)
+			}
+
+			if testCase.onIssueToken != nil {
+				issuer.On("IssueImpersonationToken", userID, adminID, testCase.expScope, time.Minute).
+					Return(testCase.onIssueToken())
+			}
+
+			grant := handlers.NewTokenExchangeGrant(
+				zap.NewNop(),
+				verifier,
+				admins,
+				userProvider,
+				issuer,
+				auditLog,
+				time.Minute,
+				[]string{"read"},
+			)
+
+			assert.Equal(t, "urn:ietf:params:oauth:grant-type:token-exchange", grant.GrantType())
+			assert.Equal(t, testCase.expResp, grant.Grant(t.Context(), req))
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package handlers
+
+//go:generate mockery --name TokenGrant --output ./mocks --outpkg mocks --filename token_grant.go --structname TokenGrant
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+)
+
+// OAuth error codes defined by RFC 6749, section 5.2.
+const (
+	oauthErrInvalidRequest       = "invalid_request"
+	oauthErrInvalidClient        = "invalid_client"
+	oauthErrInvalidGrant         = "invalid_grant"
+	oauthErrUnsupportedGrantType = "unsupported_grant_type"
+	oauthErrInvalidScope         = "invalid_scope"
+)
+
+// tokenTypeBearer is the only token type issued by the token endpoint.
+const tokenTypeBearer = "Bearer"
+
+// NewTokenV1 creates a new *TokenV1 instance that dispatches requests to the given grants by their grant type.
+func NewTokenV1(
+	log *logger.Logger,
+	grants ...TokenGrant,
+) *TokenV1 {
+	byType := make(map[string]TokenGrant, len(grants))
+	for _, grant := range grants {
+		byType[grant.GrantType()] = grant
+	}
+
+	return &TokenV1{
+		log:    log,
+		grants: byType,
+	}
+}
+
+type (
+	// TokenV1 is the OAuth 2.0 token endpoint.
+	TokenV1 struct {
+		log    *logger.Logger
+		grants map[string]TokenGrant
+	}
+
+	// TokenV1Request represents token request. Fields are shared by all grant types.
+	TokenV1Request struct {
+		GrantType          string `json:"grant_type"`
+		ClientID           string `json:"client_id"`
+		Code               string `json:"code"`
+		RedirectURI        string `json:"redirect_uri"`
+		CodeVerifier       string `json:"code_verifier"`
+		DeviceCode         string `json:"device_code"`
+		Scope              string `json:"scope"`
+		Assertion          string `json:"assertion"`
+		SubjectToken       string `json:"subject_token"`
+		SubjectTokenType   string `json:"subject_token_type"`
+		RequestedSubject   string `json:"requested_subject"`
+		RequestedTokenType string `json:"requested_token_type"`
+	}
+
+	// TokenV1Response represents successful token response.
+	TokenV1Response struct {
+		AccessToken     string `json:"access_token"`
+		IssuedTokenType string `json:"issued_token_type,omitempty"`
+		TokenType       string `json:"token_type"`
+		ExpiresIn       int64  `json:"expires_in"`
+		RefreshToken    string `json:"refresh_token,omitempty"`
+		Scope           string `json:"scope,omitempty"`
+	}
+
+	// OAuthErrorResponse represents an OAuth 2.0 error response.
+	OAuthErrorResponse struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description,omitempty"`
+	}
+
+	// TokenGrant handles token requests of a single grant type.
+	TokenGrant interface {
+		GrantType() string
+		Grant(ctx context.Context, req *TokenV1Request) *httpx.Response
+	}
+)
+
+// Handle validates the grant type and passes the request to the matching grant.
+func (h *TokenV1) Handle(ctx context.Context, req *TokenV1Request) *httpx.Response {
+	if req.GrantType == "" {
+		h.log.Warn("grant_type is missing")
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrInvalidRequest, "grant_type is required")
+	}
+
+	grant, ok := h.grants[req.GrantType]
+	if !ok {
+		h.log.Warn("unsupported grant type", logger.String("grant_type", req.GrantType))
+		return newOAuthErrorResponse(http.StatusBadRequest, oauthErrUnsupportedGrantType, "")
+	}
+
+	return grant.Grant(ctx, req)
+}
+
+// newOAuthErrorResponse creates an OAuth 2.0 error response with the given status, error code and description.
+func newOAuthErrorResponse(status int, code string, description string) *httpx.Response {
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(status),
+		httpx.WithBody(&OAuthErrorResponse{
+			Error:            code,
+			ErrorDescription: description,
+		}),
+	)
+}
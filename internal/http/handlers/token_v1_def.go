@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+)
+
+// DefTokenV1Name is the name of the *TokenV1 definition.
+const DefTokenV1Name = "http.token-v1"
+
+func init() {
+	di.Add(
+		di.Def[*TokenV1]{
+			Name: DefTokenV1Name,
+			Build: func(ctn di.Container) (*TokenV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var authorizationCodeGrant *AuthorizationCodeGrant
+				if err := ctn.Fill(DefAuthorizationCodeGrantName, &authorizationCodeGrant); err != nil {
+					return nil, err
+				}
+
+				var deviceCodeGrant *DeviceCodeGrant
+				if err := ctn.Fill(DefDeviceCodeGrantName, &deviceCodeGrant); err != nil {
+					return nil, err
+				}
+
+				var tokenExchangeGrant *TokenExchangeGrant
+				if err := ctn.Fill(DefTokenExchangeGrantName, &tokenExchangeGrant); err != nil {
+					return nil, err
+				}
+
+				var jwtBearerGrant *JwtBearerGrant
+				if err := ctn.Fill(DefJwtBearerGrantName, &jwtBearerGrant); err != nil {
+					return nil, err
+				}
+
+				return NewTokenV1(
+					log,
+					authorizationCodeGrant,
+					deviceCodeGrant,
+					tokenExchangeGrant,
+					jwtBearerGrant,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,63 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+)
+
+func TestTokenV1_Handle(t *testing.T) {
+	testCases := []struct {
+		name    string
+		req     *handlers.TokenV1Request
+		onGrant func() *httpx.Response
+		expResp *httpx.Response
+	}{
+		{
+			name: "grant type is missing",
+			req:  &handlers.TokenV1Request{},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusBadRequest),
+				httpx.WithBody(&handlers.OAuthErrorResponse{
+					Error:            "invalid_request",
+					ErrorDescription: "grant_type is required",
+				}),
+			),
+		},
+		{
+			name: "unsupported grant type",
+			req:  &handlers.TokenV1Request{GrantType: "password"},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusBadRequest),
+				httpx.WithBody(&handlers.OAuthErrorResponse{Error: "unsupported_grant_type"}),
+			),
+		},
+		{
+			name:    "positive case",
+			req:     &handlers.TokenV1Request{GrantType: "test_grant"},
+			onGrant: func() *httpx.Response { return httpx.NewJsonResponse(httpx.WithStatus(http.StatusOK)) },
+			expResp: httpx.NewJsonResponse(httpx.WithStatus(http.StatusOK)),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			grant := mocks.NewTokenGrant(t)
+			grant.On("GrantType").Return("test_grant")
+
+			if testCase.onGrant != nil {
+				grant.On("Grant", t.Context(), testCase.req).Return(testCase.onGrant())
+			}
+
+			handler := handlers.NewTokenV1(zap.NewNop(), grant)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), testCase.req))
+		})
+	}
+}
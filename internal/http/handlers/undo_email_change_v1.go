@@ -0,0 +1,96 @@
+package handlers
+
+//go:generate mockery --name EmailChangeUndoer --output ./mocks --outpkg mocks --filename email_change_undoer.go --structname EmailChangeUndoer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewUndoEmailChangeV1 creates a new *UndoEmailChangeV1 instance.
+func NewUndoEmailChangeV1(
+	log *logger.Logger,
+	emailChange EmailChangeUndoer,
+	sessions SessionRevoker,
+	auditLog AuditRecorder,
+) *UndoEmailChangeV1 {
+	return &UndoEmailChangeV1{
+		log:         log,
+		emailChange: emailChange,
+		sessions:    sessions,
+		auditLog:    auditLog,
+	}
+}
+
+type (
+	// UndoEmailChangeV1 moves a user back to the previous email address with the token emailed to it by
+	// ConfirmEmailChangeV1 and signs the user out everywhere, as the change may have been made by someone else.
+	UndoEmailChangeV1 struct {
+		log         *logger.Logger
+		emailChange EmailChangeUndoer
+		sessions    SessionRevoker
+		auditLog    AuditRecorder
+	}
+
+	// UndoEmailChangeV1Request represents undo email change request.
+	UndoEmailChangeV1Request struct {
+		Token string `json:"token"`
+	}
+
+	// UndoEmailChangeV1Response represents successful undo email change response.
+	UndoEmailChangeV1Response struct {
+		UserID string `json:"user_id"`
+	}
+
+	// EmailChangeUndoer describes EmailChangeUndoer dependency.
+	EmailChangeUndoer interface {
+		Undo(ctx context.Context, token string) (string, error)
+	}
+)
+
+// Handle redeems the undo token, restores the previous email address and revokes all refresh tokens of the user.
+func (h *UndoEmailChangeV1) Handle(ctx context.Context, req *UndoEmailChangeV1Request) *httpx.Response {
+	if req.Token == "" {
+		h.log.Warn("token is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "token is required")
+	}
+
+	userID, err := h.emailChange.Undo(ctx, req.Token)
+	if err != nil {
+		if errors.Is(err, account.ErrInvalidToken) {
+			h.log.Warn("invalid email change undo token")
+			return httpx.NewErrorResponse(http.StatusBadRequest, "invalid or expired token")
+		}
+
+		if errors.Is(err, domain.ErrEmailBusy) {
+			h.log.Warn("email is busy")
+			return httpx.NewErrorResponse(http.StatusBadRequest, "email is already in use")
+		}
+
+		h.log.Error("failed to undo email change", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if err = h.sessions.RevokeAll(ctx, userID); err != nil {
+		h.log.Error("failed to revoke sessions", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	h.auditLog.Record(ctx, domain.AuditEvent{
+		Type:    domain.AuditEventEmailChangeUndone,
+		UserID:  userID,
+		Outcome: domain.AuditOutcomeSuccess,
+	})
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&UndoEmailChangeV1Response{UserID: userID}),
+	)
+}
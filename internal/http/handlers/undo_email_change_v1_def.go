@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+)
+
+// DefUndoEmailChangeV1Name is the name of the *UndoEmailChangeV1 definition.
+const DefUndoEmailChangeV1Name = "http.undo-email-change-v1"
+
+func init() {
+	di.Add(
+		di.Def[*UndoEmailChangeV1]{
+			Name: DefUndoEmailChangeV1Name,
+			Build: func(ctn di.Container) (*UndoEmailChangeV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var emailChange *account.EmailChange
+				if err := ctn.Fill(account.DefEmailChangeName, &emailChange); err != nil {
+					return nil, err
+				}
+
+				var storage *jwt.Storage
+				if err := ctn.Fill(jwt.DefStorageName, &storage); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewUndoEmailChangeV1(
+					log,
+					emailChange,
+					storage,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
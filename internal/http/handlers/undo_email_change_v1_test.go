@@ -0,0 +1,95 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+)
+
+func TestUndoEmailChangeV1_Handle(t *testing.T) {
+	testCases := []struct {
+		name          string
+		req           *handlers.UndoEmailChangeV1Request
+		onUndo        func() (string, error)
+		onRevokeAll   func() error
+		expAuditEvent *domain.AuditEvent
+		expResp       *httpx.Response
+	}{
+		{
+			name:    "token is missing",
+			req:     &handlers.UndoEmailChangeV1Request{},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "token is required"),
+		},
+		{
+			name:    "invalid token",
+			req:     &handlers.UndoEmailChangeV1Request{Token: "token"},
+			onUndo:  func() (string, error) { return "", account.ErrInvalidToken },
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "invalid or expired token"),
+		},
+		{
+			name:    "email is busy",
+			req:     &handlers.UndoEmailChangeV1Request{Token: "token"},
+			onUndo:  func() (string, error) { return "", domain.ErrEmailBusy },
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "email is already in use"),
+		},
+		{
+			name:    "failed to undo email change",
+			req:     &handlers.UndoEmailChangeV1Request{Token: "token"},
+			onUndo:  func() (string, error) { return "", assert.AnError },
+			expResp: httpx.InternalServerError,
+		},
+		{
+			name:        "failed to revoke sessions",
+			req:         &handlers.UndoEmailChangeV1Request{Token: "token"},
+			onUndo:      func() (string, error) { return "user_id", nil },
+			onRevokeAll: func() error { return assert.AnError },
+			expResp:     httpx.InternalServerError,
+		},
+		{
+			name:        "positive case",
+			req:         &handlers.UndoEmailChangeV1Request{Token: "token"},
+			onUndo:      func() (string, error) { return "user_id", nil },
+			onRevokeAll: func() error { return nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventEmailChangeUndone,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.UndoEmailChangeV1Response{UserID: "user_id"}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			emailChange := mocks.NewEmailChangeUndoer(t)
+			if testCase.onUndo != nil {
+				emailChange.On("Undo", t.Context(), testCase.req.Token).Return(testCase.onUndo())
+			}
+
+			sessions := mocks.NewSessionRevoker(t)
+			if testCase.onRevokeAll != nil {
+				sessions.On("RevokeAll", t.Context(), "user_id").Return(testCase.onRevokeAll())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", t.Context(), *testCase.expAuditEvent).Return()
+			}
+
+			handler := handlers.NewUndoEmailChangeV1(zap.NewNop(), emailChange, sessions, auditLog)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), testCase.req))
+		})
+	}
+}
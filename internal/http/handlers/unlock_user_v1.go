@@ -0,0 +1,100 @@
+package handlers
+
+//go:generate mockery --name AccountUnlocker --output ./mocks --outpkg mocks --filename account_unlocker.go --structname AccountUnlocker
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewUnlockUserV1 creates a new *UnlockUserV1 instance.
+func NewUnlockUserV1(
+	log *logger.Logger,
+	admins AdminChecker,
+	userProvider UserByIdProvider,
+	lockout AccountUnlocker,
+	auditLog AuditRecorder,
+) *UnlockUserV1 {
+	return &UnlockUserV1{
+		log:          log,
+		admins:       admins,
+		userProvider: userProvider,
+		lockout:      lockout,
+		auditLog:     auditLog,
+	}
+}
+
+type (
+	// UnlockUserV1 lets admins lift the lockout of an account locked after too many failed logins.
+	UnlockUserV1 struct {
+		log          *logger.Logger
+		admins       AdminChecker
+		userProvider UserByIdProvider
+		lockout      AccountUnlocker
+		auditLog     AuditRecorder
+	}
+
+	// UnlockUserV1Request represents unlock user request.
+	UnlockUserV1Request struct {
+		UserID string `json:"user_id"`
+	}
+
+	// AccountUnlocker describes AccountUnlocker dependency.
+	AccountUnlocker interface {
+		Unlock(ctx context.Context, userID string) error
+	}
+)
+
+// Handle unlocks the account of the requested user and resets its failed logins. Only admins can unlock accounts.
+func (h *UnlockUserV1) Handle(ctx context.Context, req *UnlockUserV1Request) *httpx.Response {
+	var (
+		ok      bool
+		adminID string
+	)
+	if adminID, ok = httpx.GetUserID(ctx); !ok {
+		h.log.Warn("user id is missing")
+		return httpx.BadRequest
+	}
+
+	if !h.admins.IsAdmin(adminID) {
+		h.log.Warn("user is not an admin")
+		return httpx.Forbidden
+	}
+
+	if req.UserID == "" {
+		h.log.Warn("user id to unlock is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "user_id is required")
+	}
+
+	if _, err := h.userProvider.GetByID(ctx, req.UserID); err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			h.log.Warn("user not found")
+			return httpx.NotFound
+		}
+
+		h.log.Error("failed to get user by id", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	if err := h.lockout.Unlock(ctx, req.UserID); err != nil {
+		h.log.Error("failed to unlock account", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	// the account is already unlocked, so the response does not depend on the event
+	if err := h.auditLog.Save(ctx, domain.AuditEvent{
+		Type:    domain.AuditEventAccountUnlocked,
+		UserID:  req.UserID,
+		ActorID: adminID,
+	}); err != nil {
+		h.log.Error("failed to save audit event", logger.Error(err))
+	}
+
+	return httpx.NewJsonResponse(httpx.WithStatus(http.StatusOK))
+}
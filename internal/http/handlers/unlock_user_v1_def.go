@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/auth"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+// DefUnlockUserV1Name is the name of the *UnlockUserV1 definition.
+const DefUnlockUserV1Name = "http.unlock-user-v1"
+
+func init() {
+	di.Add(
+		di.Def[*UnlockUserV1]{
+			Name: DefUnlockUserV1Name,
+			Build: func(ctn di.Container) (*UnlockUserV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var admins *auth.Admins
+				if err := ctn.Fill(auth.DefAdminsName, &admins); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				var lockout *auth.Lockout
+				if err := ctn.Fill(auth.DefLockoutName, &lockout); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
+				return NewUnlockUserV1(
+					log,
+					admins,
+					usersRep,
+					lockout,
+					recorder,
+				), nil
+			},
+		},
+	)
+}
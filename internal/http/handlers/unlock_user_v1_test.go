@@ -0,0 +1,135 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+)
+
+func TestUnlockUserV1_Handle(t *testing.T) {
+	testCases := []struct {
+		name             string
+		adminID          string
+		req              *handlers.UnlockUserV1Request
+		onIsAdmin        func() bool
+		onGetByID        func() (domain.User, error)
+		onUnlock         func() error
+		onSaveAuditEvent func() error
+		expResp          *httpx.Response
+	}{
+		{
+			name:    "user id is missing",
+			req:     &handlers.UnlockUserV1Request{UserID: "user_id"},
+			expResp: httpx.BadRequest,
+		},
+		{
+			name:      "user is not an admin",
+			adminID:   "admin_id",
+			req:       &handlers.UnlockUserV1Request{UserID: "user_id"},
+			onIsAdmin: func() bool { return false },
+			expResp:   httpx.Forbidden,
+		},
+		{
+			name:      "user id to unlock is missing",
+			adminID:   "admin_id",
+			req:       &handlers.UnlockUserV1Request{},
+			onIsAdmin: func() bool { return true },
+			expResp:   httpx.NewErrorResponse(http.StatusBadRequest, "user_id is required"),
+		},
+		{
+			name:      "user not found",
+			adminID:   "admin_id",
+			req:       &handlers.UnlockUserV1Request{UserID: "user_id"},
+			onIsAdmin: func() bool { return true },
+			onGetByID: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			expResp:   httpx.NotFound,
+		},
+		{
+			name:      "failed to get user by id",
+			adminID:   "admin_id",
+			req:       &handlers.UnlockUserV1Request{UserID: "user_id"},
+			onIsAdmin: func() bool { return true },
+			onGetByID: func() (domain.User, error) { return nil, assert.AnError },
+			expResp:   httpx.InternalServerError,
+		},
+		{
+			name:      "failed to unlock account",
+			adminID:   "admin_id",
+			req:       &handlers.UnlockUserV1Request{UserID: "user_id"},
+			onIsAdmin: func() bool { return true },
+			onGetByID: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onUnlock: func() error { return assert.AnError },
+			expResp:  httpx.InternalServerError,
+		},
+		{
+			name:      "failed to save audit event",
+			adminID:   "admin_id",
+			req:       &handlers.UnlockUserV1Request{UserID: "user_id"},
+			onIsAdmin: func() bool { return true },
+			onGetByID: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onUnlock:         func() error { return nil },
+			onSaveAuditEvent: func() error { return assert.AnError },
+			expResp:          httpx.NewJsonResponse(httpx.WithStatus(http.StatusOK)),
+		},
+		{
+			name:      "positive case",
+			adminID:   "admin_id",
+			req:       &handlers.UnlockUserV1Request{UserID: "user_id"},
+			onIsAdmin: func() bool { return true },
+			onGetByID: func() (domain.User, error) {
+				return domain.NewUser("user_id", "user@example.com", "hashed_password"), nil
+			},
+			onUnlock:         func() error { return nil },
+			onSaveAuditEvent: func() error { return nil },
+			expResp:          httpx.NewJsonResponse(httpx.WithStatus(http.StatusOK)),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.adminID != "" {
+				ctx = httpx.ContextWithUserID(ctx, testCase.adminID)
+			}
+
+			admins := mocks.NewAdminChecker(t)
+			if testCase.onIsAdmin != nil {
+				admins.On("IsAdmin", testCase.adminID).Return(testCase.onIsAdmin())
+			}
+
+			userProvider := mocks.NewUserByIdProvider(t)
+			if testCase.onGetByID != nil {
+				userProvider.On("GetByID", ctx, testCase.req.UserID).Return(testCase.onGetByID())
+			}
+
+			lockout := mocks.NewAccountUnlocker(t)
+			if testCase.onUnlock != nil {
+				lockout.On("Unlock", ctx, testCase.req.UserID).Return(testCase.onUnlock())
+			}
+
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.onSaveAuditEvent != nil {
+				auditLog.On("Save", ctx, domain.AuditEvent{
+					Type:    domain.AuditEventAccountUnlocked,
+					UserID:  testCase.req.UserID,
+					ActorID: testCase.adminID,
+				}).Return(testCase.onSaveAuditEvent())
+			}
+
+			handler := handlers.NewUnlockUserV1(zap.NewNop(), admins, userProvider, lockout, auditLog)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(ctx, testCase.req))
+		})
+	}
+}
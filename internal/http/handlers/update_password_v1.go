@@ -10,7 +10,6 @@ import (
 
 	"github.com/riabininkf/go-modules/logger"
 	"github.com/riabininkf/httpx"
-	"golang.org/x/crypto/bcrypt"
 
 	"github.com/riabininkf/http-auth-example/internal/domain"
 )
@@ -20,11 +19,17 @@ func NewUpdatePasswordV1(
 	log *logger.Logger,
 	userProvider UserByIdProvider,
 	passwordUpdater PasswordUpdater,
+	passwordPolicy PasswordValidator,
+	passwordHasher PasswordHasher,
+	auditLog AuditRecorder,
 ) *UpdatePasswordV1 {
 	return &UpdatePasswordV1{
 		log:             log,
 		userProvider:    userProvider,
 		passwordUpdater: passwordUpdater,
+		passwordPolicy:  passwordPolicy,
+		passwordHasher:  passwordHasher,
+		auditLog:        auditLog,
 	}
 }
 
@@ -34,6 +39,9 @@ type (
 		log             *logger.Logger
 		userProvider    UserByIdProvider
 		passwordUpdater PasswordUpdater
+		passwordPolicy  PasswordValidator
+		passwordHasher  PasswordHasher
+		auditLog        AuditRecorder
 	}
 
 	// UpdatePasswordV1Request represents update password request.
@@ -70,8 +78,6 @@ func (h *UpdatePasswordV1) Handle(ctx context.Context, req *UpdatePasswordV1Requ
 		return httpx.NewJsonResponse(httpx.WithStatus(http.StatusOK))
 	}
 
-	// validation is skipped for simplicity
-
 	var (
 		ok     bool
 		userID string
@@ -95,26 +101,52 @@ func (h *UpdatePasswordV1) Handle(ctx context.Context, req *UpdatePasswordV1Requ
 		return httpx.InternalServerError
 	}
 
-	if err = bcrypt.CompareHashAndPassword([]byte(user.HashedPassword()), []byte(req.OldPassword)); err != nil {
-		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
-			h.log.Warn("invalid password")
-			return httpx.NewErrorResponse(http.StatusBadRequest, "invalid old password")
+	if ok, err = h.passwordHasher.Verify(ctx, req.OldPassword, user.HashedPassword()); err != nil {
+		if isHashingBusy(err) {
+			h.log.Warn("password hashing is saturated")
+			return hashingBusyResponse
 		}
 
 		h.log.Error("failed to compare passwords", logger.Error(err))
 		return httpx.InternalServerError
 	}
 
-	var hashedPassword []byte
-	if hashedPassword, err = bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost); err != nil {
+	if !ok {
+		h.log.Warn("invalid password")
+		h.auditLog.Record(ctx, domain.AuditEvent{
+			Type:    domain.AuditEventPasswordChange,
+			UserID:  userID,
+			Outcome: domain.AuditOutcomeFailure,
+			Reason:  auditReasonInvalidPassword,
+		})
+		return httpx.NewErrorResponse(http.StatusBadRequest, "invalid old password")
+	}
+
+	if resp := validatePassword(h.log, h.passwordPolicy, "new_password", req.NewPassword, user.Email()); resp != nil {
+		return resp
+	}
+
+	var hashedPassword string
+	if hashedPassword, err = h.passwordHasher.Hash(ctx, req.NewPassword); err != nil {
+		if isHashingBusy(err) {
+			h.log.Warn("password hashing is saturated")
+			return hashingBusyResponse
+		}
+
 		h.log.Error("failed to generate password hash", logger.Error(err))
 		return httpx.InternalServerError
 	}
 
-	if err = h.passwordUpdater.UpdatePassword(ctx, userID, string(hashedPassword)); err != nil {
+	if err = h.passwordUpdater.UpdatePassword(ctx, userID, hashedPassword); err != nil {
 		h.log.Error("failed to update password", logger.Error(err))
 		return httpx.InternalServerError
 	}
 
+	h.auditLog.Record(ctx, domain.AuditEvent{
+		Type:    domain.AuditEventPasswordChange,
+		UserID:  userID,
+		Outcome: domain.AuditOutcomeSuccess,
+	})
+
 	return httpx.NewJsonResponse(httpx.WithStatus(http.StatusOK))
 }
@@ -4,6 +4,8 @@ import (
 	"github.com/riabininkf/go-modules/di"
 	"github.com/riabininkf/go-modules/logger"
 
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/password"
 	"github.com/riabininkf/http-auth-example/internal/repository"
 )
 
@@ -25,10 +27,28 @@ func init() {
 					return nil, err
 				}
 
+				var passwordPolicy *password.Policy
+				if err := ctn.Fill(password.DefPolicyName, &passwordPolicy); err != nil {
+					return nil, err
+				}
+
+				var passwordHasher *password.Pool
+				if err := ctn.Fill(password.DefPoolName, &passwordHasher); err != nil {
+					return nil, err
+				}
+
+				var recorder *audit.Recorder
+				if err := ctn.Fill(audit.DefRecorderName, &recorder); err != nil {
+					return nil, err
+				}
+
 				return NewUpdatePasswordV1(
 					log,
 					usersRep,
 					usersRep,
+					passwordPolicy,
+					passwordHasher,
+					recorder,
 				), nil
 			},
 		},
@@ -8,29 +8,15 @@ import (
 	"github.com/google/uuid"
 	"github.com/riabininkf/httpx"
 	"github.com/stretchr/testify/assert"
-	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
-	"golang.org/x/crypto/bcrypt"
 
 	"github.com/riabininkf/http-auth-example/internal/domain"
 	"github.com/riabininkf/http-auth-example/internal/http/handlers"
 	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+	"github.com/riabininkf/http-auth-example/internal/password"
 )
 
 func TestNewUpdatePasswordV1(t *testing.T) {
-	generatePasswordHash := func(t *testing.T, password string) string {
-		t.Helper()
-		var (
-			err            error
-			bcryptPassword []byte
-		)
-		if bcryptPassword, err = bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost); err != nil {
-			t.Fatal(err)
-		}
-
-		return string(bcryptPassword)
-	}
-
 	generateRequest := func() *handlers.UpdatePasswordV1Request {
 		return &handlers.UpdatePasswordV1Request{
 			OldPassword: "old_password",
@@ -43,7 +29,11 @@ func TestNewUpdatePasswordV1(t *testing.T) {
 		req              func() *handlers.UpdatePasswordV1Request
 		userID           string
 		onGetUserByID    func() (domain.User, error)
+		onVerifyPassword func() (bool, error)
+		onValidate       func() ([]password.Violation, error)
+		onHashPassword   func() (string, error)
 		onUpdatePassword func() error
+		expAuditEvent    *domain.AuditEvent
 		expResp          *httpx.Response
 	}{
 		{
@@ -98,17 +88,107 @@ func TestNewUpdatePasswordV1(t *testing.T) {
 			req:    generateRequest,
 			userID: "user_id",
 			onGetUserByID: func() (domain.User, error) {
-				return domain.NewUser(uuid.NewString(), gofakeit.Email(), generatePasswordHash(t, "incorrect")), nil
+				return domain.NewUser(uuid.NewString(), gofakeit.Email(), "hashed_password"), nil
+			},
+			onVerifyPassword: func() (bool, error) { return false, nil },
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventPasswordChange,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeFailure,
+				Reason:  "invalid_password",
 			},
 			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "invalid old password"),
 		},
+		{
+			name:   "failed to compare passwords",
+			req:    generateRequest,
+			userID: "user_id",
+			onGetUserByID: func() (domain.User, error) {
+				return domain.NewUser(uuid.NewString(), gofakeit.Email(), "hashed_password"), nil
+			},
+			onVerifyPassword: func() (bool, error) { return false, assert.AnError },
+			expResp:          httpx.InternalServerError,
+		},
+		{
+			name:   "password hashing is saturated when comparing",
+			req:    generateRequest,
+			userID: "user_id",
+			onGetUserByID: func() (domain.User, error) {
+				return domain.NewUser(uuid.NewString(), gofakeit.Email(), "hashed_password"), nil
+			},
+			onVerifyPassword: func() (bool, error) { return false, password.ErrBusy },
+			expResp:          httpx.NewErrorResponse(http.StatusServiceUnavailable, "server is busy, try again later"),
+		},
+		{
+			name:   "failed to validate new password",
+			req:    generateRequest,
+			userID: "user_id",
+			onGetUserByID: func() (domain.User, error) {
+				return domain.NewUser(uuid.NewString(), "user@example.com", "hashed_password"), nil
+			},
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onValidate:       func() ([]password.Violation, error) { return nil, assert.AnError },
+			expResp:          httpx.InternalServerError,
+		},
+		{
+			name:   "new password does not meet the policy",
+			req:    generateRequest,
+			userID: "user_id",
+			onGetUserByID: func() (domain.User, error) {
+				return domain.NewUser(uuid.NewString(), "user@example.com", "hashed_password"), nil
+			},
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onValidate: func() ([]password.Violation, error) {
+				return []password.Violation{{Code: password.ViolationContainsEmail, Message: "must not contain the email address"}}, nil
+			},
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusBadRequest),
+				httpx.WithBody(&handlers.ValidationErrorResponse{
+					Error: handlers.ValidationError{
+						Message: "password does not meet the requirements",
+						Details: []handlers.FieldError{{
+							Field:   "new_password",
+							Code:    password.ViolationContainsEmail,
+							Message: "new_password must not contain the email address",
+						}},
+					},
+				}),
+			),
+		},
+		{
+			name:   "failed to hash new password",
+			req:    generateRequest,
+			userID: "user_id",
+			onGetUserByID: func() (domain.User, error) {
+				return domain.NewUser(uuid.NewString(), "user@example.com", "hashed_password"), nil
+			},
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onValidate:       func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:   func() (string, error) { return "", assert.AnError },
+			expResp:          httpx.InternalServerError,
+		},
+		{
+			name:   "password hashing is saturated when hashing",
+			req:    generateRequest,
+			userID: "user_id",
+			onGetUserByID: func() (domain.User, error) {
+				return domain.NewUser(uuid.NewString(), "user@example.com", "hashed_password"), nil
+			},
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onValidate:       func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:   func() (string, error) { return "", password.ErrBusy },
+			expResp:          httpx.NewErrorResponse(http.StatusServiceUnavailable, "server is busy, try again later"),
+		},
 		{
 			name:   "failed to update password",
 			req:    generateRequest,
 			userID: "user_id",
 			onGetUserByID: func() (domain.User, error) {
-				return domain.NewUser(uuid.NewString(), gofakeit.Email(), generatePasswordHash(t, "old_password")), nil
+				return domain.NewUser(uuid.NewString(), "user@example.com", "hashed_password"), nil
 			},
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onValidate:       func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:   func() (string, error) { return "new_hashed_password", nil },
 			onUpdatePassword: func() error { return assert.AnError },
 			expResp:          httpx.InternalServerError,
 		},
@@ -117,10 +197,18 @@ func TestNewUpdatePasswordV1(t *testing.T) {
 			req:    generateRequest,
 			userID: "user_id",
 			onGetUserByID: func() (domain.User, error) {
-				return domain.NewUser(uuid.NewString(), gofakeit.Email(), generatePasswordHash(t, "old_password")), nil
+				return domain.NewUser(uuid.NewString(), "user@example.com", "hashed_password"), nil
 			},
+			onVerifyPassword: func() (bool, error) { return true, nil },
+			onValidate:       func() ([]password.Violation, error) { return nil, nil },
+			onHashPassword:   func() (string, error) { return "new_hashed_password", nil },
 			onUpdatePassword: func() error { return nil },
-			expResp:          httpx.NewJsonResponse(httpx.WithStatus(http.StatusOK)),
+			expAuditEvent: &domain.AuditEvent{
+				Type:    domain.AuditEventPasswordChange,
+				UserID:  "user_id",
+				Outcome: domain.AuditOutcomeSuccess,
+			},
+			expResp: httpx.NewJsonResponse(httpx.WithStatus(http.StatusOK)),
 		},
 	}
 
@@ -138,16 +226,38 @@ func TestNewUpdatePasswordV1(t *testing.T) {
 				userProvider.On("GetByID", ctx, testCase.userID).Return(testCase.onGetUserByID())
 			}
 
+			passwordHasher := mocks.NewPasswordHasher(t)
+			if testCase.onVerifyPassword != nil {
+				passwordHasher.On("Verify", ctx, req.OldPassword, "hashed_password").Return(testCase.onVerifyPassword())
+			}
+
+			if testCase.onHashPassword != nil {
+				passwordHasher.On("Hash", ctx, req.NewPassword).Return(testCase.onHashPassword())
+			}
+
+			passwordPolicy := mocks.NewPasswordValidator(t)
+			if testCase.onValidate != nil {
+				passwordPolicy.On("Validate", req.NewPassword, "user@example.com").Return(testCase.onValidate())
+			}
+
 			passwordUpdater := mocks.NewPasswordUpdater(t)
 			if testCase.onUpdatePassword != nil {
-				passwordUpdater.On("UpdatePassword", ctx, testCase.userID, mock.AnythingOfType("string")).
+				passwordUpdater.On("UpdatePassword", ctx, testCase.userID, "new_hashed_password").
 					Return(testCase.onUpdatePassword())
 			}
 
+			auditLog := mocks.NewAuditRecorder(t)
+			if testCase.expAuditEvent != nil {
+				auditLog.On("Record", ctx, *testCase.expAuditEvent).Return()
+			}
+
 			handler := handlers.NewUpdatePasswordV1(
 				zap.NewNop(),
 				userProvider,
 				passwordUpdater,
+				passwordPolicy,
+				passwordHasher,
+				auditLog,
 			)
 
 			assert.Equal(t, testCase.expResp, handler.Handle(ctx, req))
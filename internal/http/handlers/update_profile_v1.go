@@ -0,0 +1,218 @@
+package handlers
+
+//go:generate mockery --name ProfileUpdater --output ./mocks --outpkg mocks --filename profile_updater.go --structname ProfileUpdater
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	// timezones are validated against the embedded database, as the runtime image may not have one
+	_ "time/tzdata"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// Limits of profile fields.
+const (
+	maxDisplayNameLength = 100
+	maxLocaleLength      = 35
+	maxAvatarURLLength   = 2048
+	maxMetadataBytes     = 4096
+)
+
+// Codes of profile validation errors.
+const (
+	profileErrorTooLong = "too_long"
+	profileErrorInvalid = "invalid"
+)
+
+// localePattern matches BCP 47 language tags such as "en", "pt-BR" or "zh-Hant-TW".
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{1,8})*$`)
+
+// NewUpdateProfileV1 creates a new *UpdateProfileV1 instance.
+func NewUpdateProfileV1(
+	log *logger.Logger,
+	profileUpdater ProfileUpdater,
+) *UpdateProfileV1 {
+	return &UpdateProfileV1{
+		log:            log,
+		profileUpdater: profileUpdater,
+	}
+}
+
+type (
+	// UpdateProfileV1 lets the authenticated user change their profile.
+	UpdateProfileV1 struct {
+		log            *logger.Logger
+		profileUpdater ProfileUpdater
+	}
+
+	// UpdateProfileV1Request represents update profile request. Only the fields present in the request are changed,
+	// an empty string clears a field. Metadata replaces the stored object as a whole, null clears it.
+	UpdateProfileV1Request struct {
+		DisplayName *string         `json:"display_name"`
+		Locale      *string         `json:"locale"`
+		Timezone    *string         `json:"timezone"`
+		AvatarURL   *string         `json:"avatar_url"`
+		Metadata    json.RawMessage `json:"metadata"`
+	}
+
+	// ProfileUpdater describes ProfileUpdater dependency.
+	ProfileUpdater interface {
+		UpdateProfile(ctx context.Context, userID string, update domain.UserProfileUpdate) (domain.User, error)
+	}
+)
+
+// Handle validates the changed fields, updates the profile of the authenticated user and returns it
+// the same way GetProfileV1 does.
+func (h *UpdateProfileV1) Handle(ctx context.Context, req *UpdateProfileV1Request) *httpx.Response {
+	var (
+		ok     bool
+		userID string
+	)
+	if userID, ok = httpx.GetUserID(ctx); !ok {
+		h.log.Warn("user id is missing")
+		return httpx.BadRequest
+	}
+
+	update, fieldErrors := newProfileUpdate(req)
+	if len(fieldErrors) > 0 {
+		h.log.Warn("invalid profile")
+		return httpx.NewJsonResponse(
+			httpx.WithStatus(http.StatusBadRequest),
+			httpx.WithBody(&ValidationErrorResponse{
+				Error: ValidationError{
+					Message: "profile is invalid",
+					Details: fieldErrors,
+				},
+			}),
+		)
+	}
+
+	user, err := h.profileUpdater.UpdateProfile(ctx, userID, update)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserNotFound) {
+			h.log.Warn("user not found")
+			return httpx.NotFound
+		}
+
+		h.log.Error("failed to update profile", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	return newProfileV1Response(user)
+}
+
+// newProfileUpdate converts the request into a profile update, or returns the problems of its invalid fields.
+func newProfileUpdate(req *UpdateProfileV1Request) (domain.UserProfileUpdate, []FieldError) {
+	var (
+		update      domain.UserProfileUpdate
+		fieldErrors []FieldError
+	)
+
+	if req.DisplayName != nil {
+		displayName := strings.TrimSpace(*req.DisplayName)
+		switch {
+		case utf8.RuneCountInString(displayName) > maxDisplayNameLength:
+			fieldErrors = append(fieldErrors, tooLongFieldError("display_name", maxDisplayNameLength, "characters"))
+		case strings.ContainsFunc(displayName, unicode.IsControl):
+			fieldErrors = append(fieldErrors, invalidFieldError("display_name", "must not contain control characters"))
+		default:
+			update.DisplayName = &displayName
+		}
+	}
+
+	if req.Locale != nil {
+		switch locale := *req.Locale; {
+		case len(locale) > maxLocaleLength:
+			fieldErrors = append(fieldErrors, tooLongFieldError("locale", maxLocaleLength, "characters"))
+		case locale != "" && !localePattern.MatchString(locale):
+			fieldErrors = append(fieldErrors, invalidFieldError("locale", "must be a BCP 47 language tag, e.g. en-US"))
+		default:
+			update.Locale = &locale
+		}
+	}
+
+	if req.Timezone != nil {
+		if timezone := *req.Timezone; timezone == "" || isTimezone(timezone) {
+			update.Timezone = &timezone
+		} else {
+			fieldErrors = append(fieldErrors, invalidFieldError("timezone", "must be an IANA time zone, e.g. Europe/Berlin"))
+		}
+	}
+
+	if req.AvatarURL != nil {
+		switch avatarURL := *req.AvatarURL; {
+		case len(avatarURL) > maxAvatarURLLength:
+			fieldErrors = append(fieldErrors, tooLongFieldError("avatar_url", maxAvatarURLLength, "characters"))
+		case avatarURL != "" && !isHTTPSURL(avatarURL):
+			fieldErrors = append(fieldErrors, invalidFieldError("avatar_url", "must be an absolute https URL"))
+		default:
+			update.AvatarURL = &avatarURL
+		}
+	}
+
+	if req.Metadata != nil {
+		metadata := bytes.TrimSpace(req.Metadata)
+		switch {
+		case bytes.Equal(metadata, []byte("null")):
+			update.Metadata = json.RawMessage(`{}`)
+		case len(metadata) > maxMetadataBytes:
+			fieldErrors = append(fieldErrors, tooLongFieldError("metadata", maxMetadataBytes, "bytes"))
+		case !bytes.HasPrefix(metadata, []byte("{")):
+			fieldErrors = append(fieldErrors, invalidFieldError("metadata", "must be a JSON object"))
+		default:
+			update.Metadata = json.RawMessage(metadata)
+		}
+	}
+
+	return update, fieldErrors
+}
+
+// tooLongFieldError returns the error of a field longer than limit units, e.g. characters.
+func tooLongFieldError(field string, limit int, units string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    profileErrorTooLong,
+		Message: field + " must be at most " + strconv.Itoa(limit) + " " + units,
+		Params:  map[string]int{"max": limit},
+	}
+}
+
+// invalidFieldError returns the error of a field with an invalid value.
+func invalidFieldError(field string, message string) FieldError {
+	return FieldError{
+		Field:   field,
+		Code:    profileErrorInvalid,
+		Message: field + " " + message,
+	}
+}
+
+// isTimezone reports whether the name is a time zone of the IANA database. "Local" is not one,
+// as it depends on the server.
+func isTimezone(name string) bool {
+	if name == "Local" {
+		return false
+	}
+
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// isHTTPSURL reports whether the value is an absolute https URL.
+func isHTTPSURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && parsed.Scheme == "https" && parsed.Host != ""
+}
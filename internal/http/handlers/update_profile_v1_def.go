@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+// DefUpdateProfileV1Name is the name of the *UpdateProfileV1 definition.
+const DefUpdateProfileV1Name = "http.update-profile-v1"
+
+func init() {
+	di.Add(
+		di.Def[*UpdateProfileV1]{
+			Name: DefUpdateProfileV1Name,
+			Build: func(ctn di.Container) (*UpdateProfileV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var usersRep *repository.Users
+				if err := ctn.Fill(repository.DefUsersName, &usersRep); err != nil {
+					return nil, err
+				}
+
+				return NewUpdateProfileV1(log, usersRep), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,226 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+)
+
+func TestUpdateProfileV1_Handle(t *testing.T) {
+	createdAt := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+
+	updatedUser := domain.NewUser(
+		"user_id",
+		"user@example.com",
+		"hashed_password",
+		domain.WithProfile(domain.UserProfile{
+			DisplayName: "Jane Doe",
+			Timezone:    "Europe/Berlin",
+			Metadata:    json.RawMessage(`{"theme": "dark"}`),
+		}),
+		domain.WithTimestamps(createdAt, createdAt.Add(time.Hour)),
+	)
+
+	updatedResp := httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&handlers.ProfileV1Response{
+			UserID:      "user_id",
+			Email:       "user@example.com",
+			DisplayName: "Jane Doe",
+			Timezone:    "Europe/Berlin",
+			Metadata:    json.RawMessage(`{"theme": "dark"}`),
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt.Add(time.Hour),
+		}),
+	)
+
+	invalidResp := func(details ...handlers.FieldError) *httpx.Response {
+		return httpx.NewJsonResponse(
+			httpx.WithStatus(http.StatusBadRequest),
+			httpx.WithBody(&handlers.ValidationErrorResponse{
+				Error: handlers.ValidationError{
+					Message: "profile is invalid",
+					Details: details,
+				},
+			}),
+		)
+	}
+
+	testCases := []struct {
+		name            string
+		userID          string
+		req             *handlers.UpdateProfileV1Request
+		expUpdate       *domain.UserProfileUpdate
+		onUpdateProfile func() (domain.User, error)
+		expResp         *httpx.Response
+	}{
+		{
+			name:    "user id is missing",
+			req:     &handlers.UpdateProfileV1Request{},
+			expResp: httpx.BadRequest,
+		},
+		{
+			name:   "display name is too long",
+			userID: "user_id",
+			req:    &handlers.UpdateProfileV1Request{DisplayName: ptr(strings.Repeat("й", 101))},
+			expResp: invalidResp(handlers.FieldError{
+				Field:   "display_name",
+				Code:    "too_long",
+				Message: "display_name must be at most 100 characters",
+				Params:  map[string]int{"max": 100},
+			}),
+		},
+		{
+			name:   "display name has control characters",
+			userID: "user_id",
+			req:    &handlers.UpdateProfileV1Request{DisplayName: ptr("Jane\nDoe")},
+			expResp: invalidResp(handlers.FieldError{
+				Field:   "display_name",
+				Code:    "invalid",
+				Message: "display_name must not contain control characters",
+			}),
+		},
+		{
+			name:   "every field is invalid",
+			userID: "user_id",
+			req: &handlers.UpdateProfileV1Request{
+				Locale:    ptr("english"),
+				Timezone:  ptr("Local"),
+				AvatarURL: ptr("http://example.com/avatar.png"),
+				Metadata:  json.RawMessage(`["theme"]`),
+			},
+			expResp: invalidResp(
+				handlers.FieldError{
+					Field:   "locale",
+					Code:    "invalid",
+					Message: "locale must be a BCP 47 language tag, e.g. en-US",
+				},
+				handlers.FieldError{
+					Field:   "timezone",
+					Code:    "invalid",
+					Message: "timezone must be an IANA time zone, e.g. Europe/Berlin",
+				},
+				handlers.FieldError{
+					Field:   "avatar_url",
+					Code:    "invalid",
+					Message: "avatar_url must be an absolute https URL",
+				},
+				handlers.FieldError{
+					Field:   "metadata",
+					Code:    "invalid",
+					Message: "metadata must be a JSON object",
+				},
+			),
+		},
+		{
+			name:   "unknown timezone",
+			userID: "user_id",
+			req:    &handlers.UpdateProfileV1Request{Timezone: ptr("Mars/Olympus_Mons")},
+			expResp: invalidResp(handlers.FieldError{
+				Field:   "timezone",
+				Code:    "invalid",
+				Message: "timezone must be an IANA time zone, e.g. Europe/Berlin",
+			}),
+		},
+		{
+			name:   "metadata is too large",
+			userID: "user_id",
+			req: &handlers.UpdateProfileV1Request{
+				Metadata: json.RawMessage(`{"notes": "` + strings.Repeat("a", 4096) + `"}`),
+			},
+			expResp: invalidResp(handlers.FieldError{
+				Field:   "metadata",
+				Code:    "too_long",
+				Message: "metadata must be at most 4096 bytes",
+				Params:  map[string]int{"max": 4096},
+			}),
+		},
+		{
+			name:            "user not found",
+			userID:          "user_id",
+			req:             &handlers.UpdateProfileV1Request{},
+			expUpdate:       &domain.UserProfileUpdate{},
+			onUpdateProfile: func() (domain.User, error) { return nil, domain.ErrUserNotFound },
+			expResp:         httpx.NotFound,
+		},
+		{
+			name:            "failed to update profile",
+			userID:          "user_id",
+			req:             &handlers.UpdateProfileV1Request{},
+			expUpdate:       &domain.UserProfileUpdate{},
+			onUpdateProfile: func() (domain.User, error) { return nil, assert.AnError },
+			expResp:         httpx.InternalServerError,
+		},
+		{
+			name:   "fields are cleared",
+			userID: "user_id",
+			req: &handlers.UpdateProfileV1Request{
+				Locale:    ptr(""),
+				Timezone:  ptr(""),
+				AvatarURL: ptr(""),
+				Metadata:  json.RawMessage(`null`),
+			},
+			expUpdate: &domain.UserProfileUpdate{
+				Locale:    ptr(""),
+				Timezone:  ptr(""),
+				AvatarURL: ptr(""),
+				Metadata:  json.RawMessage(`{}`),
+			},
+			onUpdateProfile: func() (domain.User, error) { return updatedUser, nil },
+			expResp:         updatedResp,
+		},
+		{
+			name:   "positive case",
+			userID: "user_id",
+			req: &handlers.UpdateProfileV1Request{
+				DisplayName: ptr("  Jane Doe "),
+				Locale:      ptr("zh-Hant-TW"),
+				Timezone:    ptr("Europe/Berlin"),
+				AvatarURL:   ptr("https://example.com/avatar.png"),
+				Metadata:    json.RawMessage(`{"theme": "dark"}`),
+			},
+			expUpdate: &domain.UserProfileUpdate{
+				DisplayName: ptr("Jane Doe"),
+				Locale:      ptr("zh-Hant-TW"),
+				Timezone:    ptr("Europe/Berlin"),
+				AvatarURL:   ptr("https://example.com/avatar.png"),
+				Metadata:    json.RawMessage(`{"theme": "dark"}`),
+			},
+			onUpdateProfile: func() (domain.User, error) { return updatedUser, nil },
+			expResp:         updatedResp,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			ctx := t.Context()
+			if testCase.userID != "" {
+				ctx = httpx.ContextWithUserID(ctx, testCase.userID)
+			}
+
+			users := mocks.NewProfileUpdater(t)
+			if testCase.expUpdate != nil {
+				users.On("UpdateProfile", ctx, testCase.userID, *testCase.expUpdate).
+					Return(testCase.onUpdateProfile())
+			}
+
+			handler := handlers.NewUpdateProfileV1(zap.NewNop(), users)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(ctx, testCase.req))
+		})
+	}
+}
+
+func ptr(value string) *string {
+	return &value
+}
@@ -0,0 +1,72 @@
+package handlers
+
+//go:generate mockery --name EmailVerifier --output ./mocks --outpkg mocks --filename email_verifier.go --structname EmailVerifier
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+)
+
+// NewVerifyEmailV1 creates a new *VerifyEmailV1 instance.
+func NewVerifyEmailV1(
+	log *logger.Logger,
+	emailVerification EmailVerifier,
+) *VerifyEmailV1 {
+	return &VerifyEmailV1{
+		log:               log,
+		emailVerification: emailVerification,
+	}
+}
+
+type (
+	// VerifyEmailV1 confirms the email address of a user with the token sent to it.
+	VerifyEmailV1 struct {
+		log               *logger.Logger
+		emailVerification EmailVerifier
+	}
+
+	// VerifyEmailV1Request represents email verification request.
+	VerifyEmailV1Request struct {
+		Token string `json:"token"`
+	}
+
+	// VerifyEmailV1Response represents successful email verification response.
+	VerifyEmailV1Response struct {
+		UserID string `json:"user_id"`
+	}
+
+	// EmailVerifier describes EmailVerifier dependency.
+	EmailVerifier interface {
+		Verify(ctx context.Context, token string) (string, error)
+	}
+)
+
+// Handle redeems the verification token and marks the email address as verified.
+func (h *VerifyEmailV1) Handle(ctx context.Context, req *VerifyEmailV1Request) *httpx.Response {
+	if req.Token == "" {
+		h.log.Warn("token is missing")
+		return httpx.NewErrorResponse(http.StatusBadRequest, "token is required")
+	}
+
+	userID, err := h.emailVerification.Verify(ctx, req.Token)
+	if err != nil {
+		if errors.Is(err, account.ErrInvalidToken) {
+			h.log.Warn("invalid email verification token")
+			return httpx.NewErrorResponse(http.StatusBadRequest, "invalid or expired token")
+		}
+
+		h.log.Error("failed to verify email", logger.Error(err))
+		return httpx.InternalServerError
+	}
+
+	return httpx.NewJsonResponse(
+		httpx.WithStatus(http.StatusOK),
+		httpx.WithBody(&VerifyEmailV1Response{UserID: userID}),
+	)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+)
+
+// DefVerifyEmailV1Name is the name of the *VerifyEmailV1 definition.
+const DefVerifyEmailV1Name = "http.verify-email-v1"
+
+func init() {
+	di.Add(
+		di.Def[*VerifyEmailV1]{
+			Name: DefVerifyEmailV1Name,
+			Build: func(ctn di.Container) (*VerifyEmailV1, error) {
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				var emailVerification *account.EmailVerification
+				if err := ctn.Fill(account.DefEmailVerificationName, &emailVerification); err != nil {
+					return nil, err
+				}
+
+				return NewVerifyEmailV1(
+					log,
+					emailVerification,
+				), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,63 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/account"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers"
+	"github.com/riabininkf/http-auth-example/internal/http/handlers/mocks"
+)
+
+func TestVerifyEmailV1_Handle(t *testing.T) {
+	testCases := []struct {
+		name     string
+		req      *handlers.VerifyEmailV1Request
+		onVerify func() (string, error)
+		expResp  *httpx.Response
+	}{
+		{
+			name:    "token is missing",
+			req:     &handlers.VerifyEmailV1Request{},
+			expResp: httpx.NewErrorResponse(http.StatusBadRequest, "token is required"),
+		},
+		{
+			name:     "invalid token",
+			req:      &handlers.VerifyEmailV1Request{Token: "token"},
+			onVerify: func() (string, error) { return "", account.ErrInvalidToken },
+			expResp:  httpx.NewErrorResponse(http.StatusBadRequest, "invalid or expired token"),
+		},
+		{
+			name:     "failed to verify email",
+			req:      &handlers.VerifyEmailV1Request{Token: "token"},
+			onVerify: func() (string, error) { return "", assert.AnError },
+			expResp:  httpx.InternalServerError,
+		},
+		{
+			name:     "positive case",
+			req:      &handlers.VerifyEmailV1Request{Token: "token"},
+			onVerify: func() (string, error) { return "user_id", nil },
+			expResp: httpx.NewJsonResponse(
+				httpx.WithStatus(http.StatusOK),
+				httpx.WithBody(&handlers.VerifyEmailV1Response{UserID: "user_id"}),
+			),
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			emailVerification := mocks.NewEmailVerifier(t)
+			if testCase.onVerify != nil {
+				emailVerification.On("Verify", t.Context(), testCase.req.Token).Return(testCase.onVerify())
+			}
+
+			handler := handlers.NewVerifyEmailV1(zap.NewNop(), emailVerification)
+
+			assert.Equal(t, testCase.expResp, handler.Handle(t.Context(), testCase.req))
+		})
+	}
+}
@@ -2,10 +2,13 @@ package middleware
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"github.com/riabininkf/go-modules/logger"
 	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/jwt"
 )
 
 // Authenticator defines the contract for validating authentication and retrieving user identifiers from HTTP requests.
@@ -15,6 +18,7 @@ type Authenticator interface {
 
 // Auth returns a middleware that handles authentication based on the provided Authenticator and logger.
 // It validates requests, logs warnings for unauthenticated users, and enriches the request context with user ID.
+// Tokens whose scope does not allow the request are rejected with 403 Forbidden.
 func Auth(log *logger.Logger, verifier Authenticator) Middleware {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
@@ -23,9 +27,15 @@ func Auth(log *logger.Logger, verifier Authenticator) Middleware {
 				userID string
 			)
 			if userID, err = verifier.Authenticate(req.Context(), req); err != nil {
-				log.Warn("user is not authenticated", logger.Error(err))
+				resp := httpx.Unauthorized
+				if errors.Is(err, jwt.ErrInsufficientScope) {
+					log.Warn("token scope does not allow the request", logger.Error(err))
+					resp = httpx.Forbidden
+				} else {
+					log.Warn("user is not authenticated", logger.Error(err))
+				}
 
-				if err = httpx.WriteJsonResponse(httpx.Unauthorized, writer); err != nil {
+				if err = httpx.WriteJsonResponse(resp, writer); err != nil {
 					log.Error("failed to write error response", logger.Error(err))
 				}
 
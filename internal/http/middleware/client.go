@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+)
+
+// Client returns a middleware that stores the IP and user agent of the client in the request context,
+// so that audit events recorded by handlers describe where the request came from.
+func Client(clientIP func(req *http.Request) (string, bool)) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+			ip, _ := clientIP(req)
+
+			next.ServeHTTP(writer, req.WithContext(
+				audit.ContextWithClient(req.Context(), audit.Client{IP: ip, UserAgent: req.UserAgent()}),
+			))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/riabininkf/go-modules/logger"
+	"github.com/riabininkf/httpx"
+
+	"github.com/riabininkf/http-auth-example/internal/ratelimit"
+)
+
+// RateLimiter defines the contract for counting requests against rate limits.
+type RateLimiter interface {
+	Check(req *http.Request) (ratelimit.Result, bool, error)
+}
+
+// RateLimit returns a middleware that rejects requests exceeding the rate limits with 429 Too Many Requests.
+// It reports the state of the limit in the RateLimit-* headers and lets requests through if the limiter fails,
+// so that an unavailable Redis does not take the whole service down.
+func RateLimit(log *logger.Logger, limiter RateLimiter) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, req *http.Request) {
+			result, applied, err := limiter.Check(req)
+			if err != nil {
+				log.Error("failed to check rate limit", logger.Error(err))
+				next.ServeHTTP(writer, req)
+				return
+			}
+
+			if !applied {
+				next.ServeHTTP(writer, req)
+				return
+			}
+
+			writer.Header().Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+			writer.Header().Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			writer.Header().Set("RateLimit-Reset", seconds(result.Reset))
+
+			if result.Allowed {
+				next.ServeHTTP(writer, req)
+				return
+			}
+
+			log.Warn("rate limit exceeded",
+				logger.String("method", req.Method),
+				logger.String("path", req.URL.Path),
+			)
+
+			writer.Header().Set("Retry-After", seconds(result.RetryAfter))
+
+			if err = httpx.WriteJsonResponse(
+				httpx.NewErrorResponse(http.StatusTooManyRequests, "too many requests"),
+				writer,
+			); err != nil {
+				log.Error("failed to write error response", logger.Error(err))
+			}
+		})
+	}
+}
+
+// seconds formats the duration as a whole number of seconds, rounded up so that clients do not retry too early.
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
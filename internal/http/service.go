@@ -16,23 +16,101 @@ func NewService(
 	refreshV1 *handlers.RefreshV1,
 	registerV1 *handlers.RegisterV1,
 	updatePasswordV1 *handlers.UpdatePasswordV1,
+	authorize *handlers.Authorize,
+	tokenV1 *handlers.TokenV1,
+	deviceCodeV1 *handlers.DeviceCodeV1,
+	deviceVerification *handlers.DeviceVerification,
+	loginMFAV1 *handlers.LoginMFAV1,
+	enrollTOTPV1 *handlers.EnrollTOTPV1,
+	confirmTOTPV1 *handlers.ConfirmTOTPV1,
+	regenerateRecoveryCodesV1 *handlers.RegenerateRecoveryCodesV1,
+	beginWebAuthnRegistrationV1 *handlers.BeginWebAuthnRegistrationV1,
+	finishWebAuthnRegistrationV1 *handlers.FinishWebAuthnRegistrationV1,
+	beginWebAuthnLoginV1 *handlers.BeginWebAuthnLoginV1,
+	finishWebAuthnLoginV1 *handlers.FinishWebAuthnLoginV1,
+	verifyEmailV1 *handlers.VerifyEmailV1,
+	resendEmailVerificationV1 *handlers.ResendEmailVerificationV1,
+	forgotPasswordV1 *handlers.ForgotPasswordV1,
+	resetPasswordV1 *handlers.ResetPasswordV1,
+	startEmailLoginV1 *handlers.StartEmailLoginV1,
+	finishEmailLoginV1 *handlers.FinishEmailLoginV1,
+	unlockUserV1 *handlers.UnlockUserV1,
+	passwordHashingV1 *handlers.PasswordHashingV1,
+	securityEventsV1 *handlers.SecurityEventsV1,
+	getProfileV1 *handlers.GetProfileV1,
+	updateProfileV1 *handlers.UpdateProfileV1,
+	changeEmailV1 *handlers.ChangeEmailV1,
+	confirmEmailChangeV1 *handlers.ConfirmEmailChangeV1,
+	undoEmailChangeV1 *handlers.UndoEmailChangeV1,
 ) *Service {
 	return &Service{
-		log:              log,
-		loginV1:          loginV1,
-		refreshV1:        refreshV1,
-		registerV1:       registerV1,
-		updatePasswordV1: updatePasswordV1,
+		log:                          log,
+		loginV1:                      loginV1,
+		refreshV1:                    refreshV1,
+		registerV1:                   registerV1,
+		updatePasswordV1:             updatePasswordV1,
+		authorize:                    authorize,
+		tokenV1:                      tokenV1,
+		deviceCodeV1:                 deviceCodeV1,
+		deviceVerification:           deviceVerification,
+		loginMFAV1:                   loginMFAV1,
+		enrollTOTPV1:                 enrollTOTPV1,
+		confirmTOTPV1:                confirmTOTPV1,
+		regenerateRecoveryCodesV1:    regenerateRecoveryCodesV1,
+		beginWebAuthnRegistrationV1:  beginWebAuthnRegistrationV1,
+		finishWebAuthnRegistrationV1: finishWebAuthnRegistrationV1,
+		beginWebAuthnLoginV1:         beginWebAuthnLoginV1,
+		finishWebAuthnLoginV1:        finishWebAuthnLoginV1,
+		verifyEmailV1:                verifyEmailV1,
+		resendEmailVerificationV1:    resendEmailVerificationV1,
+		forgotPasswordV1:             forgotPasswordV1,
+		resetPasswordV1:              resetPasswordV1,
+		startEmailLoginV1:            startEmailLoginV1,
+		finishEmailLoginV1:           finishEmailLoginV1,
+		unlockUserV1:                 unlockUserV1,
+		passwordHashingV1:            passwordHashingV1,
+		securityEventsV1:             securityEventsV1,
+		getProfileV1:                 getProfileV1,
+		updateProfileV1:              updateProfileV1,
+		changeEmailV1:                changeEmailV1,
+		confirmEmailChangeV1:         confirmEmailChangeV1,
+		undoEmailChangeV1:            undoEmailChangeV1,
 	}
 }
 
 // Service is a facade for http handlers that represents generic handlers as http.HandlerFunc
 type Service struct {
-	log              *logger.Logger
-	loginV1          *handlers.LoginV1
-	refreshV1        *handlers.RefreshV1
-	registerV1       *handlers.RegisterV1
-	updatePasswordV1 *handlers.UpdatePasswordV1
+	log                          *logger.Logger
+	loginV1                      *handlers.LoginV1
+	refreshV1                    *handlers.RefreshV1
+	registerV1                   *handlers.RegisterV1
+	updatePasswordV1             *handlers.UpdatePasswordV1
+	authorize                    *handlers.Authorize
+	tokenV1                      *handlers.TokenV1
+	deviceCodeV1                 *handlers.DeviceCodeV1
+	deviceVerification           *handlers.DeviceVerification
+	loginMFAV1                   *handlers.LoginMFAV1
+	enrollTOTPV1                 *handlers.EnrollTOTPV1
+	confirmTOTPV1                *handlers.ConfirmTOTPV1
+	regenerateRecoveryCodesV1    *handlers.RegenerateRecoveryCodesV1
+	beginWebAuthnRegistrationV1  *handlers.BeginWebAuthnRegistrationV1
+	finishWebAuthnRegistrationV1 *handlers.FinishWebAuthnRegistrationV1
+	beginWebAuthnLoginV1         *handlers.BeginWebAuthnLoginV1
+	finishWebAuthnLoginV1        *handlers.FinishWebAuthnLoginV1
+	verifyEmailV1                *handlers.VerifyEmailV1
+	resendEmailVerificationV1    *handlers.ResendEmailVerificationV1
+	forgotPasswordV1             *handlers.ForgotPasswordV1
+	resetPasswordV1              *handlers.ResetPasswordV1
+	startEmailLoginV1            *handlers.StartEmailLoginV1
+	finishEmailLoginV1           *handlers.FinishEmailLoginV1
+	unlockUserV1                 *handlers.UnlockUserV1
+	passwordHashingV1            *handlers.PasswordHashingV1
+	securityEventsV1             *handlers.SecurityEventsV1
+	getProfileV1                 *handlers.GetProfileV1
+	updateProfileV1              *handlers.UpdateProfileV1
+	changeEmailV1                *handlers.ChangeEmailV1
+	confirmEmailChangeV1         *handlers.ConfirmEmailChangeV1
+	undoEmailChangeV1            *handlers.UndoEmailChangeV1
 }
 
 // LoginV1 returns http.HandlerFunc for LoginV1 handler
@@ -55,3 +133,133 @@ func (s *Service) RegisterV1() http.HandlerFunc {
 func (s *Service) UpdatePasswordV1() http.HandlerFunc {
 	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.updatePasswordV1.Handle)
 }
+
+// Authorize returns http.HandlerFunc for Authorize handler
+func (s *Service) Authorize() http.HandlerFunc {
+	return s.authorize.ServeHTTP
+}
+
+// TokenV1 returns http.HandlerFunc for TokenV1 handler
+func (s *Service) TokenV1() http.HandlerFunc {
+	return adaptFormHandlerFunc(newErrorLogger(s.log), s.tokenV1.Handle)
+}
+
+// DeviceCodeV1 returns http.HandlerFunc for DeviceCodeV1 handler
+func (s *Service) DeviceCodeV1() http.HandlerFunc {
+	return adaptFormHandlerFunc(newErrorLogger(s.log), s.deviceCodeV1.Handle)
+}
+
+// DeviceVerification returns http.HandlerFunc for DeviceVerification handler
+func (s *Service) DeviceVerification() http.HandlerFunc {
+	return s.deviceVerification.ServeHTTP
+}
+
+// LoginMFAV1 returns http.HandlerFunc for LoginMFAV1 handler
+func (s *Service) LoginMFAV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.loginMFAV1.Handle)
+}
+
+// EnrollTOTPV1 returns http.HandlerFunc for EnrollTOTPV1 handler
+func (s *Service) EnrollTOTPV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.enrollTOTPV1.Handle)
+}
+
+// ConfirmTOTPV1 returns http.HandlerFunc for ConfirmTOTPV1 handler
+func (s *Service) ConfirmTOTPV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.confirmTOTPV1.Handle)
+}
+
+// RegenerateRecoveryCodesV1 returns http.HandlerFunc for RegenerateRecoveryCodesV1 handler
+func (s *Service) RegenerateRecoveryCodesV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.regenerateRecoveryCodesV1.Handle)
+}
+
+// BeginWebAuthnRegistrationV1 returns http.HandlerFunc for BeginWebAuthnRegistrationV1 handler
+func (s *Service) BeginWebAuthnRegistrationV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.beginWebAuthnRegistrationV1.Handle)
+}
+
+// FinishWebAuthnRegistrationV1 returns http.HandlerFunc for FinishWebAuthnRegistrationV1 handler
+func (s *Service) FinishWebAuthnRegistrationV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.finishWebAuthnRegistrationV1.Handle)
+}
+
+// BeginWebAuthnLoginV1 returns http.HandlerFunc for BeginWebAuthnLoginV1 handler
+func (s *Service) BeginWebAuthnLoginV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.beginWebAuthnLoginV1.Handle)
+}
+
+// FinishWebAuthnLoginV1 returns http.HandlerFunc for FinishWebAuthnLoginV1 handler
+func (s *Service) FinishWebAuthnLoginV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.finishWebAuthnLoginV1.Handle)
+}
+
+// VerifyEmailV1 returns http.HandlerFunc for VerifyEmailV1 handler
+func (s *Service) VerifyEmailV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.verifyEmailV1.Handle)
+}
+
+// ResendEmailVerificationV1 returns http.HandlerFunc for ResendEmailVerificationV1 handler
+func (s *Service) ResendEmailVerificationV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.resendEmailVerificationV1.Handle)
+}
+
+// ForgotPasswordV1 returns http.HandlerFunc for ForgotPasswordV1 handler
+func (s *Service) ForgotPasswordV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.forgotPasswordV1.Handle)
+}
+
+// ResetPasswordV1 returns http.HandlerFunc for ResetPasswordV1 handler
+func (s *Service) ResetPasswordV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.resetPasswordV1.Handle)
+}
+
+// StartEmailLoginV1 returns http.HandlerFunc for StartEmailLoginV1 handler
+func (s *Service) StartEmailLoginV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.startEmailLoginV1.Handle)
+}
+
+// FinishEmailLoginV1 returns http.HandlerFunc for FinishEmailLoginV1 handler
+func (s *Service) FinishEmailLoginV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.finishEmailLoginV1.Handle)
+}
+
+// UnlockUserV1 returns http.HandlerFunc for UnlockUserV1 handler
+func (s *Service) UnlockUserV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.unlockUserV1.Handle)
+}
+
+// PasswordHashingV1 returns http.HandlerFunc for PasswordHashingV1 handler
+func (s *Service) PasswordHashingV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.passwordHashingV1.Handle)
+}
+
+// SecurityEventsV1 returns http.HandlerFunc for SecurityEventsV1 handler
+func (s *Service) SecurityEventsV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.securityEventsV1.Handle)
+}
+
+// GetProfileV1 returns http.HandlerFunc for GetProfileV1 handler
+func (s *Service) GetProfileV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.getProfileV1.Handle)
+}
+
+// UpdateProfileV1 returns http.HandlerFunc for UpdateProfileV1 handler
+func (s *Service) UpdateProfileV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.updateProfileV1.Handle)
+}
+
+// ChangeEmailV1 returns http.HandlerFunc for ChangeEmailV1 handler
+func (s *Service) ChangeEmailV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.changeEmailV1.Handle)
+}
+
+// ConfirmEmailChangeV1 returns http.HandlerFunc for ConfirmEmailChangeV1 handler
+func (s *Service) ConfirmEmailChangeV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.confirmEmailChangeV1.Handle)
+}
+
+// UndoEmailChangeV1 returns http.HandlerFunc for UndoEmailChangeV1 handler
+func (s *Service) UndoEmailChangeV1() http.HandlerFunc {
+	return httpx.AdaptHandlerFunc(newErrorLogger(s.log), s.undoEmailChangeV1.Handle)
+}
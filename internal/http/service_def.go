@@ -40,12 +40,168 @@ func init() {
 					return nil, err
 				}
 
+				var authorize *handlers.Authorize
+				if err := ctn.Fill(handlers.DefAuthorizeName, &authorize); err != nil {
+					return nil, err
+				}
+
+				var tokenV1 *handlers.TokenV1
+				if err := ctn.Fill(handlers.DefTokenV1Name, &tokenV1); err != nil {
+					return nil, err
+				}
+
+				var deviceCodeV1 *handlers.DeviceCodeV1
+				if err := ctn.Fill(handlers.DefDeviceCodeV1Name, &deviceCodeV1); err != nil {
+					return nil, err
+				}
+
+				var deviceVerification *handlers.DeviceVerification
+				if err := ctn.Fill(handlers.DefDeviceVerificationName, &deviceVerification); err != nil {
+					return nil, err
+				}
+
+				var loginMFAV1 *handlers.LoginMFAV1
+				if err := ctn.Fill(handlers.DefLoginMFAV1Name, &loginMFAV1); err != nil {
+					return nil, err
+				}
+
+				var enrollTOTPV1 *handlers.EnrollTOTPV1
+				if err := ctn.Fill(handlers.DefEnrollTOTPV1Name, &enrollTOTPV1); err != nil {
+					return nil, err
+				}
+
+				var confirmTOTPV1 *handlers.ConfirmTOTPV1
+				if err := ctn.Fill(handlers.DefConfirmTOTPV1Name, &confirmTOTPV1); err != nil {
+					return nil, err
+				}
+
+				var regenerateRecoveryCodesV1 *handlers.RegenerateRecoveryCodesV1
+				if err := ctn.Fill(handlers.DefRegenerateRecoveryCodesV1Name, &regenerateRecoveryCodesV1); err != nil {
+					return nil, err
+				}
+
+				var beginWebAuthnRegistrationV1 *handlers.BeginWebAuthnRegistrationV1
+				if err := ctn.Fill(handlers.DefBeginWebAuthnRegistrationV1Name, &beginWebAuthnRegistrationV1); err != nil {
+					return nil, err
+				}
+
+				var finishWebAuthnRegistrationV1 *handlers.FinishWebAuthnRegistrationV1
+				if err := ctn.Fill(handlers.DefFinishWebAuthnRegistrationV1Name, &finishWebAuthnRegistrationV1); err != nil {
+					return nil, err
+				}
+
+				var beginWebAuthnLoginV1 *handlers.BeginWebAuthnLoginV1
+				if err := ctn.Fill(handlers.DefBeginWebAuthnLoginV1Name, &beginWebAuthnLoginV1); err != nil {
+					return nil, err
+				}
+
+				var finishWebAuthnLoginV1 *handlers.FinishWebAuthnLoginV1
+				if err := ctn.Fill(handlers.DefFinishWebAuthnLoginV1Name, &finishWebAuthnLoginV1); err != nil {
+					return nil, err
+				}
+
+				var verifyEmailV1 *handlers.VerifyEmailV1
+				if err := ctn.Fill(handlers.DefVerifyEmailV1Name, &verifyEmailV1); err != nil {
+					return nil, err
+				}
+
+				var resendEmailVerificationV1 *handlers.ResendEmailVerificationV1
+				if err := ctn.Fill(handlers.DefResendEmailVerificationV1Name, &resendEmailVerificationV1); err != nil {
+					return nil, err
+				}
+
+				var forgotPasswordV1 *handlers.ForgotPasswordV1
+				if err := ctn.Fill(handlers.DefForgotPasswordV1Name, &forgotPasswordV1); err != nil {
+					return nil, err
+				}
+
+				var resetPasswordV1 *handlers.ResetPasswordV1
+				if err := ctn.Fill(handlers.DefResetPasswordV1Name, &resetPasswordV1); err != nil {
+					return nil, err
+				}
+
+				var startEmailLoginV1 *handlers.StartEmailLoginV1
+				if err := ctn.Fill(handlers.DefStartEmailLoginV1Name, &startEmailLoginV1); err != nil {
+					return nil, err
+				}
+
+				var finishEmailLoginV1 *handlers.FinishEmailLoginV1
+				if err := ctn.Fill(handlers.DefFinishEmailLoginV1Name, &finishEmailLoginV1); err != nil {
+					return nil, err
+				}
+
+				var unlockUserV1 *handlers.UnlockUserV1
+				if err := ctn.Fill(handlers.DefUnlockUserV1Name, &unlockUserV1); err != nil {
+					return nil, err
+				}
+
+				var passwordHashingV1 *handlers.PasswordHashingV1
+				if err := ctn.Fill(handlers.DefPasswordHashingV1Name, &passwordHashingV1); err != nil {
+					return nil, err
+				}
+
+				var securityEventsV1 *handlers.SecurityEventsV1
+				if err := ctn.Fill(handlers.DefSecurityEventsV1Name, &securityEventsV1); err != nil {
+					return nil, err
+				}
+
+				var getProfileV1 *handlers.GetProfileV1
+				if err := ctn.Fill(handlers.DefGetProfileV1Name, &getProfileV1); err != nil {
+					return nil, err
+				}
+
+				var updateProfileV1 *handlers.UpdateProfileV1
+				if err := ctn.Fill(handlers.DefUpdateProfileV1Name, &updateProfileV1); err != nil {
+					return nil, err
+				}
+
+				var changeEmailV1 *handlers.ChangeEmailV1
+				if err := ctn.Fill(handlers.DefChangeEmailV1Name, &changeEmailV1); err != nil {
+					return nil, err
+				}
+
+				var confirmEmailChangeV1 *handlers.ConfirmEmailChangeV1
+				if err := ctn.Fill(handlers.DefConfirmEmailChangeV1Name, &confirmEmailChangeV1); err != nil {
+					return nil, err
+				}
+
+				var undoEmailChangeV1 *handlers.UndoEmailChangeV1
+				if err := ctn.Fill(handlers.DefUndoEmailChangeV1Name, &undoEmailChangeV1); err != nil {
+					return nil, err
+				}
+
 				return NewService(
 					log,
 					loginV1,
 					refreshV1,
 					registerV1,
 					updatePasswordV1,
+					authorize,
+					tokenV1,
+					deviceCodeV1,
+					deviceVerification,
+					loginMFAV1,
+					enrollTOTPV1,
+					confirmTOTPV1,
+					regenerateRecoveryCodesV1,
+					beginWebAuthnRegistrationV1,
+					finishWebAuthnRegistrationV1,
+					beginWebAuthnLoginV1,
+					finishWebAuthnLoginV1,
+					verifyEmailV1,
+					resendEmailVerificationV1,
+					forgotPasswordV1,
+					resetPasswordV1,
+					startEmailLoginV1,
+					finishEmailLoginV1,
+					unlockUserV1,
+					passwordHashingV1,
+					securityEventsV1,
+					getProfileV1,
+					updateProfileV1,
+					changeEmailV1,
+					confirmEmailChangeV1,
+					undoEmailChangeV1,
 				), nil
 			},
 		},
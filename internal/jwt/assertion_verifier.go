@@ -0,0 +1,129 @@
+package jwt
+
+//go:generate mockery --name ServiceAccountKeys --output ./mocks --outpkg mocks --filename service_account_keys.go --structname ServiceAccountKeys
+//go:generate mockery --name ReplayCache --output ./mocks --outpkg mocks --filename replay_cache.go --structname ReplayCache
+
+import (
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	// ErrInvalidAssertion is returned when the assertion is malformed, badly signed, expired or violates
+	// any of the checks of RFC 7523, section 3.
+	ErrInvalidAssertion = errors.New("invalid assertion")
+
+	// ErrAssertionReplayed is returned when an assertion with the same jti has already been used.
+	ErrAssertionReplayed = errors.New("assertion has already been used")
+)
+
+// assertionJtiKeyPrefix is the prefix of cache keys for the jti of used assertions.
+const assertionJtiKeyPrefix = "jwt:assertion-jti:"
+
+// NewAssertionVerifier creates a new *AssertionVerifier. Assertions living longer than maxLifetime are rejected.
+func NewAssertionVerifier(
+	maxLifetime time.Duration,
+	parser Parser,
+	keys ServiceAccountKeys,
+	cache ReplayCache,
+) *AssertionVerifier {
+	return &AssertionVerifier{
+		maxLifetime: maxLifetime,
+		parser:      parser,
+		keys:        keys,
+		cache:       cache,
+	}
+}
+
+type (
+	// AssertionVerifier verifies self-signed JWT assertions of service accounts (RFC 7523).
+	AssertionVerifier struct {
+		maxLifetime time.Duration
+		parser      Parser
+		keys        ServiceAccountKeys
+		cache       ReplayCache
+	}
+
+	// ServiceAccountKeys provides public keys of registered service accounts.
+	ServiceAccountKeys interface {
+		PublicKey(serviceAccountID string) (crypto.PublicKey, bool)
+	}
+
+	// ReplayCache stores a key only if it does not exist yet, reporting whether it was stored.
+	ReplayCache interface {
+		SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error)
+	}
+)
+
+// Verify validates the assertion signature with the issuer's public key, checks that the issuer and subject
+// are the same service account, that the assertion is short-lived and that its jti has not been used before.
+// Returns the service account ID on success. Validation failures wrap ErrInvalidAssertion.
+func (v *AssertionVerifier) Verify(ctx context.Context, assertion string) (string, error) {
+	var (
+		err         error
+		claims      jwt.RegisteredClaims
+		parsedToken *jwt.Token
+	)
+	if parsedToken, err = v.parser.ParseWithClaims(assertion, &claims, func(token *jwt.Token) (any, error) {
+		// shared-secret algorithms would let anyone holding the public key forge assertions
+		switch token.Method.(type) {
+		case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS, *jwt.SigningMethodECDSA, *jwt.SigningMethodEd25519:
+		default:
+			return nil, jwt.ErrSignatureInvalid
+		}
+
+		key, ok := v.keys.PublicKey(claims.Issuer)
+		if !ok {
+			return nil, fmt.Errorf("unknown service account %q", claims.Issuer)
+		}
+
+		return key, nil
+	}); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidAssertion, err)
+	}
+
+	if !parsedToken.Valid {
+		return "", ErrInvalidAssertion
+	}
+
+	if claims.Subject == "" || claims.Subject != claims.Issuer {
+		return "", fmt.Errorf("%w: sub must be equal to iss", ErrInvalidAssertion)
+	}
+
+	if claims.ExpiresAt == nil {
+		return "", fmt.Errorf("%w: exp is required", ErrInvalidAssertion)
+	}
+
+	if time.Until(claims.ExpiresAt.Time) > v.maxLifetime {
+		return "", fmt.Errorf("%w: exp is too far in the future", ErrInvalidAssertion)
+	}
+
+	if claims.ID == "" {
+		return "", fmt.Errorf("%w: jti is required", ErrInvalidAssertion)
+	}
+
+	// the jti is remembered until the assertion expires, after that the assertion is rejected anyway
+	var stored bool
+	if stored, err = v.cache.SetNX(ctx, v.jtiKey(claims.Issuer, claims.ID), "", time.Until(claims.ExpiresAt.Time)); err != nil {
+		return "", fmt.Errorf("failed to store jti: %w", err)
+	}
+
+	if !stored {
+		return "", ErrAssertionReplayed
+	}
+
+	return claims.Subject, nil
+}
+
+// jtiKey returns the cache key for the jti. A jti is unique per issuer only.
+func (v *AssertionVerifier) jtiKey(issuer string, jti string) string {
+	sum := sha256.Sum256([]byte(issuer + "\x00" + jti))
+	return assertionJtiKeyPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,71 @@
+package jwt
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/auth"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+const (
+	// DefAssertionVerifierName is the name of the *AssertionVerifier definition.
+	DefAssertionVerifierName = "auth.assertion-verifier"
+
+	configKeyAssertionAudience    = "auth.serviceAccounts.audience"
+	configKeyAssertionMaxLifetime = "auth.serviceAccounts.maxAssertionLifetime"
+)
+
+func init() {
+	di.Add(
+		di.Def[*AssertionVerifier]{
+			Name: DefAssertionVerifierName,
+			Build: func(ctn di.Container) (*AssertionVerifier, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var audience string
+				if audience = cfg.GetString(configKeyAssertionAudience); audience == "" {
+					return nil, config.NewErrMissingKey(configKeyAssertionAudience)
+				}
+
+				var maxLifetime time.Duration
+				if maxLifetime = cfg.GetDuration(configKeyAssertionMaxLifetime); maxLifetime == 0 {
+					return nil, config.NewErrMissingKey(configKeyAssertionMaxLifetime)
+				}
+
+				var serviceAccounts *auth.ServiceAccounts
+				if err := ctn.Fill(auth.DefServiceAccountsName, &serviceAccounts); err != nil {
+					return nil, err
+				}
+
+				var cache *redis.Client
+				if err := ctn.Fill(redis.DefClientName, &cache); err != nil {
+					return nil, err
+				}
+
+				return NewAssertionVerifier(
+					maxLifetime,
+					jwt.NewParser(
+						jwt.WithValidMethods([]string{
+							jwt.SigningMethodRS256.Name,
+							jwt.SigningMethodPS256.Name,
+							jwt.SigningMethodES256.Name,
+							jwt.SigningMethodEdDSA.Alg(),
+						}),
+						jwt.WithAudience(audience),
+						jwt.WithExpirationRequired(),
+						jwt.WithIssuedAt(),
+					),
+					serviceAccounts,
+					cache,
+				), nil
+			},
+		},
+	)
+}
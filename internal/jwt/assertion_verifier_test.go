@@ -0,0 +1,188 @@
+package jwt_test
+
+import (
+	"context"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	gojwt "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/jwt"
+	"github.com/riabininkf/http-auth-example/internal/jwt/mocks"
+)
+
+func TestAssertionVerifier_Verify(t *testing.T) {
+	const audience = "https://auth.example.com/v1/oauth/token"
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	knownKey := func() (crypto.PublicKey, bool) {
+		return publicKey, true
+	}
+
+	generateClaims := func() gojwt.RegisteredClaims {
+		now := time.Now()
+
+		return gojwt.RegisteredClaims{
+			Issuer:    "worker",
+			Subject:   "worker",
+			Audience:  gojwt.ClaimStrings{audience},
+			IssuedAt:  gojwt.NewNumericDate(now),
+			ExpiresAt: gojwt.NewNumericDate(now.Add(time.Minute)),
+			ID:        "jti",
+		}
+	}
+
+	sign := func(claims gojwt.RegisteredClaims) string {
+		assertion, signErr := gojwt.NewWithClaims(gojwt.SigningMethodEdDSA, claims).SignedString(privateKey)
+		if signErr != nil {
+			t.Fatal(signErr)
+		}
+
+		return assertion
+	}
+
+	withClaims := func(modify func(claims *gojwt.RegisteredClaims)) func() string {
+		return func() string {
+			claims := generateClaims()
+			modify(&claims)
+			return sign(claims)
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		assertion   func() string
+		onPublicKey func() (crypto.PublicKey, bool)
+		onSetNX     func() (bool, error)
+		expSubject  string
+		expErr      error
+	}{
+		{
+			name: "signed with a shared secret",
+			assertion: func() string {
+				assertion, signErr := gojwt.NewWithClaims(gojwt.SigningMethodHS256, generateClaims()).SignedString([]byte("secret"))
+				if signErr != nil {
+					t.Fatal(signErr)
+				}
+
+				return assertion
+			},
+			expErr: jwt.ErrInvalidAssertion,
+		},
+		{
+			name:        "unknown service account",
+			assertion:   withClaims(func(*gojwt.RegisteredClaims) {}),
+			onPublicKey: func() (crypto.PublicKey, bool) { return nil, false },
+			expErr:      jwt.ErrInvalidAssertion,
+		},
+		{
+			name:      "signed with another key",
+			assertion: withClaims(func(*gojwt.RegisteredClaims) {}),
+			onPublicKey: func() (crypto.PublicKey, bool) {
+				anotherKey, _, _ := ed25519.GenerateKey(rand.Reader)
+				return anotherKey, true
+			},
+			expErr: jwt.ErrInvalidAssertion,
+		},
+		{
+			name:        "wrong audience",
+			assertion:   withClaims(func(claims *gojwt.RegisteredClaims) { claims.Audience = gojwt.ClaimStrings{"another"} }),
+			onPublicKey: knownKey,
+			expErr:      jwt.ErrInvalidAssertion,
+		},
+		{
+			name: "expired",
+			assertion: withClaims(func(claims *gojwt.RegisteredClaims) {
+				claims.ExpiresAt = gojwt.NewNumericDate(time.Now().Add(-time.Minute))
+			}),
+			onPublicKey: knownKey,
+			expErr:      jwt.ErrInvalidAssertion,
+		},
+		{
+			name:        "exp is missing",
+			assertion:   withClaims(func(claims *gojwt.RegisteredClaims) { claims.ExpiresAt = nil }),
+			onPublicKey: knownKey,
+			expErr:      jwt.ErrInvalidAssertion,
+		},
+		{
+			name: "exp is too far in the future",
+			assertion: withClaims(func(claims *gojwt.RegisteredClaims) {
+				claims.ExpiresAt = gojwt.NewNumericDate(time.Now().Add(time.Hour))
+			}),
+			onPublicKey: knownKey,
+			expErr:      jwt.ErrInvalidAssertion,
+		},
+		{
+			name:        "subject differs from issuer",
+			assertion:   withClaims(func(claims *gojwt.RegisteredClaims) { claims.Subject = "another" }),
+			onPublicKey: knownKey,
+			expErr:      jwt.ErrInvalidAssertion,
+		},
+		{
+			name:        "jti is missing",
+			assertion:   withClaims(func(claims *gojwt.RegisteredClaims) { claims.ID = "" }),
+			onPublicKey: knownKey,
+			expErr:      jwt.ErrInvalidAssertion,
+		},
+		{
+			name:        "failed to store jti",
+			assertion:   withClaims(func(*gojwt.RegisteredClaims) {}),
+			onPublicKey: knownKey,
+			onSetNX:     func() (bool, error) { return false, assert.AnError },
+			expErr:      assert.AnError,
+		},
+		{
+			name:        "replayed assertion",
+			assertion:   withClaims(func(*gojwt.RegisteredClaims) {}),
+			onPublicKey: knownKey,
+			onSetNX:     func() (bool, error) { return false, nil },
+			expErr:      jwt.ErrAssertionReplayed,
+		},
+		{
+			name:        "positive case",
+			assertion:   withClaims(func(*gojwt.RegisteredClaims) {}),
+			onPublicKey: knownKey,
+			onSetNX:     func() (bool, error) { return true, nil },
+			expSubject:  "worker",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			keys := mocks.NewServiceAccountKeys(t)
+			if testCase.onPublicKey != nil {
+				keys.On("PublicKey", "worker").Return(testCase.onPublicKey())
+			}
+
+			cache := mocks.NewReplayCache(t)
+			if testCase.onSetNX != nil {
+				cache.On("SetNX", context.Background(), mock.AnythingOfType("string"), "", mock.AnythingOfType("time.Duration")).
+					Return(testCase.onSetNX())
+			}
+
+			verifier := jwt.NewAssertionVerifier(
+				5*time.Minute,
+				gojwt.NewParser(
+					gojwt.WithValidMethods([]string{gojwt.SigningMethodEdDSA.Alg(), gojwt.SigningMethodHS256.Name}),
+					gojwt.WithAudience(audience),
+					gojwt.WithIssuedAt(),
+				),
+				keys,
+				cache,
+			)
+
+			subject, err := verifier.Verify(context.Background(), testCase.assertion())
+			assert.Equal(t, testCase.expSubject, subject)
+			assert.ErrorIs(t, err, testCase.expErr)
+		})
+	}
+}
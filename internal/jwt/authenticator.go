@@ -10,8 +10,13 @@ import (
 	"strings"
 )
 
-// ErrTokenMissing indicates that a required JWT token is missing from the request.
-var ErrTokenMissing = errors.New("jwt token is missing")
+var (
+	// ErrTokenMissing indicates that a required JWT token is missing from the request.
+	ErrTokenMissing = errors.New("jwt token is missing")
+
+	// ErrInsufficientScope indicates that the scope of the JWT token does not allow the request.
+	ErrInsufficientScope = errors.New("jwt token scope is insufficient")
+)
 
 // NewAuthenticator initializes and returns a new instance of Authenticator with the provided verifier and no-auth routes.
 func NewAuthenticator(
@@ -36,15 +41,16 @@ type (
 		noAuthRoutes map[string]struct{}
 	}
 
-	// AccessTokenVerifier defines a method to verify access tokens and return associated user information.
+	// AccessTokenVerifier defines a method to verify access tokens and return their claims.
 	AccessTokenVerifier interface {
-		VerifyAccess(ctx context.Context, token string) (string, error)
+		VerifyAccessClaims(ctx context.Context, token string) (AccessClaims, error)
 	}
 )
 
 // Authenticate validates the Authorization header from the HTTP request and extracts the authenticated user ID if valid.
 // It uses the provided context and an internal AccessTokenVerifier for token verification.
 // Returns the user ID on successful authentication or an error if authentication fails or a token is missing when required.
+// Returns ErrInsufficientScope if the scope of the token does not allow the request, see AccessClaims.Allows.
 func (a *Authenticator) Authenticate(ctx context.Context, req *http.Request) (string, error) {
 	var header string
 	if header = req.Header.Get("Authorization"); header == "" || !strings.HasPrefix(header, "Bearer ") {
@@ -66,9 +72,9 @@ func (a *Authenticator) Authenticate(ctx context.Context, req *http.Request) (st
 
 	var (
 		err    error
-		userID string
+		claims AccessClaims
 	)
-	if userID, err = a.verifier.VerifyAccess(ctx, token); err != nil {
+	if claims, err = a.verifier.VerifyAccessClaims(ctx, token); err != nil {
 		if a.isAuthRequired(req) {
 			return "", err
 		}
@@ -76,7 +82,15 @@ func (a *Authenticator) Authenticate(ctx context.Context, req *http.Request) (st
 		return "", nil
 	}
 
-	return userID, nil
+	if !claims.Allows(req.Method) {
+		if a.isAuthRequired(req) {
+			return "", ErrInsufficientScope
+		}
+
+		return "", nil
+	}
+
+	return claims.Subject, nil
 }
 
 // isAuthRequired checks if authentication is necessary for the given HTTP request based on its method and URL path.
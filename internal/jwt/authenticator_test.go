@@ -3,6 +3,7 @@ package jwt_test
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -16,7 +17,7 @@ func TestAuthenticator_Authenticate(t *testing.T) {
 		name           string
 		req            func() *http.Request
 		noAuthUrls     []string
-		onVerifyAccess func() (string, error)
+		onVerifyAccess func() (jwt.AccessClaims, error)
 		expUserID      string
 		expError       error
 	}{
@@ -61,7 +62,7 @@ func TestAuthenticator_Authenticate(t *testing.T) {
 				req.Header.Set("Authorization", "Bearer test-token")
 				return req
 			},
-			onVerifyAccess: func() (string, error) { return "", assert.AnError },
+			onVerifyAccess: func() (jwt.AccessClaims, error) { return jwt.AccessClaims{}, assert.AnError },
 			expError:       assert.AnError,
 		},
 		{
@@ -71,7 +72,7 @@ func TestAuthenticator_Authenticate(t *testing.T) {
 				req.Header.Set("Authorization", "Bearer test-token")
 				return req
 			},
-			onVerifyAccess: func() (string, error) { return "", assert.AnError },
+			onVerifyAccess: func() (jwt.AccessClaims, error) { return jwt.AccessClaims{}, assert.AnError },
 			noAuthUrls:     []string{"GET /test"},
 			expError:       nil,
 		},
@@ -82,7 +83,7 @@ func TestAuthenticator_Authenticate(t *testing.T) {
 				req.Header.Set("Authorization", "Bearer test-token")
 				return req
 			},
-			onVerifyAccess: func() (string, error) { return "user_id", nil },
+			onVerifyAccess: func() (jwt.AccessClaims, error) { return jwt.AccessClaims{Subject: "user_id"}, nil },
 			expUserID:      "user_id",
 			expError:       nil,
 		},
@@ -92,7 +93,7 @@ func TestAuthenticator_Authenticate(t *testing.T) {
 		t.Run(testCase.name, func(t *testing.T) {
 			verifier := mocks.NewAccessTokenVerifier(t)
 			if testCase.onVerifyAccess != nil {
-				verifier.On("VerifyAccess", t.Context(), "test-token").Return(testCase.onVerifyAccess())
+				verifier.On("VerifyAccessClaims", t.Context(), "test-token").Return(testCase.onVerifyAccess())
 			}
 
 			authenticator := jwt.NewAuthenticator(verifier, testCase.noAuthUrls)
@@ -103,3 +104,89 @@ func TestAuthenticator_Authenticate(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthenticator_Authenticate_Scope(t *testing.T) {
+	impersonation := jwt.AccessClaims{Subject: "user_id", ActorID: "admin_id", Scope: jwt.ScopeRead}
+
+	// impersonation tokens must not be able to take over the account of the customer
+	forbiddenRoutes := []string{
+		"PATCH /v1/user/me",
+		"POST /v1/user/email",
+		"POST /v1/user/password",
+		"POST /v1/user/mfa/totp",
+		"POST /v1/user/mfa/totp/confirm",
+		"POST /v1/user/mfa/recovery-codes",
+		"POST /v1/user/webauthn/register/begin",
+		"POST /v1/user/webauthn/register/finish",
+	}
+
+	for _, route := range forbiddenRoutes {
+		t.Run(route, func(t *testing.T) {
+			method, path, _ := strings.Cut(route, " ")
+
+			req := httptest.NewRequest(method, path, nil)
+			req.Header.Set("Authorization", "Bearer test-token")
+
+			verifier := mocks.NewAccessTokenVerifier(t)
+			verifier.On("VerifyAccessClaims", t.Context(), "test-token").Return(impersonation, nil)
+
+			userID, err := jwt.NewAuthenticator(verifier, nil).Authenticate(t.Context(), req)
+			assert.Empty(t, userID)
+			assert.ErrorIs(t, err, jwt.ErrInsufficientScope)
+		})
+	}
+
+	testCases := []struct {
+		name       string
+		method     string
+		claims     jwt.AccessClaims
+		noAuthUrls []string
+		expUserID  string
+		expError   error
+	}{
+		{
+			name:      "read scope allows safe methods",
+			method:    http.MethodGet,
+			claims:    impersonation,
+			expUserID: "user_id",
+		},
+		{
+			name:     "impersonation token without scope",
+			method:   http.MethodGet,
+			claims:   jwt.AccessClaims{Subject: "user_id", ActorID: "admin_id"},
+			expError: jwt.ErrInsufficientScope,
+		},
+		{
+			name:     "unknown scope",
+			method:   http.MethodGet,
+			claims:   jwt.AccessClaims{Subject: "user_id", Scope: "write"},
+			expError: jwt.ErrInsufficientScope,
+		},
+		{
+			name:       "insufficient scope (no auth required)",
+			method:     http.MethodPost,
+			claims:     impersonation,
+			noAuthUrls: []string{"POST /test"},
+		},
+		{
+			name:      "token without scope",
+			method:    http.MethodPost,
+			claims:    jwt.AccessClaims{Subject: "user_id"},
+			expUserID: "user_id",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			req := httptest.NewRequest(testCase.method, "/test", nil)
+			req.Header.Set("Authorization", "Bearer test-token")
+
+			verifier := mocks.NewAccessTokenVerifier(t)
+			verifier.On("VerifyAccessClaims", t.Context(), "test-token").Return(testCase.claims, nil)
+
+			userID, err := jwt.NewAuthenticator(verifier, testCase.noAuthUrls).Authenticate(t.Context(), req)
+			assert.Equal(t, testCase.expUserID, userID)
+			assert.ErrorIs(t, err, testCase.expError)
+		})
+	}
+}
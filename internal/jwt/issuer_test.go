@@ -71,3 +71,29 @@ func TestIssuer_IssueRefreshToken(t *testing.T) {
 	assert.Equal(t, "test_user", payload.Get("sub").String())
 	assert.Equal(t, "refresh_token", payload.Get("typ").String())
 }
+
+func TestIssuer_IssueImpersonationToken(t *testing.T) {
+	issuer := jwt.NewIssuer(
+		"test_issuer",
+		"test_secret",
+		time.Hour,
+		time.Hour,
+	)
+
+	accessToken, err := issuer.IssueImpersonationToken("test_user", "test_admin", "read", time.Minute)
+	assert.NoError(t, err)
+
+	parts := strings.Split(accessToken, ".")
+	assert.Len(t, parts, 3)
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(parts[1])
+	assert.NoError(t, err)
+
+	payload := gjson.ParseBytes(payloadBytes)
+	assert.Equal(t, "test_issuer", payload.Get("iss").String())
+	assert.Equal(t, "test_user", payload.Get("sub").String())
+	assert.Equal(t, "access_token", payload.Get("typ").String())
+	assert.Equal(t, "test_admin", payload.Get("act.sub").String())
+	assert.Equal(t, "read", payload.Get("scope").String())
+	assert.Equal(t, int64(60), payload.Get("exp").Int()-payload.Get("iat").Int())
+}
@@ -5,6 +5,8 @@ package mocks
 import (
 	context "context"
 
+	jwt "github.com/riabininkf/http-auth-example/internal/jwt"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -13,23 +15,23 @@ type AccessTokenVerifier struct {
 	mock.Mock
 }
 
-// VerifyAccess provides a mock function with given fields: ctx, token
-func (_m *AccessTokenVerifier) VerifyAccess(ctx context.Context, token string) (string, error) {
+// VerifyAccessClaims provides a mock function with given fields: ctx, token
+func (_m *AccessTokenVerifier) VerifyAccessClaims(ctx context.Context, token string) (jwt.AccessClaims, error) {
 	ret := _m.Called(ctx, token)
 
 	if len(ret) == 0 {
-		panic("no return value specified for VerifyAccess")
+		panic("no return value specified for VerifyAccessClaims")
 	}
 
-	var r0 string
+	var r0 jwt.AccessClaims
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) (jwt.AccessClaims, error)); ok {
 		return rf(ctx, token)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+	if rf, ok := ret.Get(0).(func(context.Context, string) jwt.AccessClaims); ok {
 		r0 = rf(ctx, token)
 	} else {
-		r0 = ret.Get(0).(string)
+		r0 = ret.Get(0).(jwt.AccessClaims)
 	}
 
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
@@ -15,6 +15,49 @@ type Cache struct {
 	mock.Mock
 }
 
+// AddToSet provides a mock function with given fields: ctx, key, member, ttl
+func (_m *Cache) AddToSet(ctx context.Context, key string, member string, ttl time.Duration) error {
+	ret := _m.Called(ctx, key, member, ttl)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddToSet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, time.Duration) error); ok {
+		r0 = rf(ctx, key, member, ttl)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Del provides a mock function with given fields: ctx, keys
+func (_m *Cache) Del(ctx context.Context, keys ...string) error {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Del")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) error); ok {
+		r0 = rf(ctx, keys...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Pop provides a mock function with given fields: ctx, key
 func (_m *Cache) Pop(ctx context.Context, key string) error {
 	ret := _m.Called(ctx, key)
@@ -33,6 +76,36 @@ func (_m *Cache) Pop(ctx context.Context, key string) error {
 	return r0
 }
 
+// PopSet provides a mock function with given fields: ctx, key
+func (_m *Cache) PopSet(ctx context.Context, key string) ([]string, error) {
+	ret := _m.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PopSet")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = rf(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Set provides a mock function with given fields: ctx, key, value, ttl
 func (_m *Cache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
 	ret := _m.Called(ctx, key, value, ttl)
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	crypto "crypto"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// ServiceAccountKeys is an autogenerated mock type for the ServiceAccountKeys type
+type ServiceAccountKeys struct {
+	mock.Mock
+}
+
+// PublicKey provides a mock function with given fields: serviceAccountID
+func (_m *ServiceAccountKeys) PublicKey(serviceAccountID string) (crypto.PublicKey, bool) {
+	ret := _m.Called(serviceAccountID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PublicKey")
+	}
+
+	var r0 crypto.PublicKey
+	var r1 bool
+	if rf, ok := ret.Get(0).(func(string) (crypto.PublicKey, bool)); ok {
+		return rf(serviceAccountID)
+	}
+	if rf, ok := ret.Get(0).(func(string) crypto.PublicKey); ok {
+		r0 = rf(serviceAccountID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(crypto.PublicKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string) bool); ok {
+		r1 = rf(serviceAccountID)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	return r0, r1
+}
+
+// NewServiceAccountKeys creates a new instance of ServiceAccountKeys. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewServiceAccountKeys(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ServiceAccountKeys {
+	mock := &ServiceAccountKeys{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -29,15 +29,31 @@ type (
 	// Cache defines methods for managing a key-value store with optional context and TTL (time-to-live) functionality.
 	// Set stores a value for a key in the cache with a specified TTL, returning an error if the operation fails.
 	// Pop removes a key and its associated value from the cache, returning an error if the operation fails.
+	// AddToSet adds a member to the set stored at a key and resets the TTL of the set.
+	// PopSet removes the set stored at a key and returns its members.
+	// Del removes the given keys from the cache.
 	Cache interface {
 		Set(ctx context.Context, key string, value any, ttl time.Duration) error
 		Pop(ctx context.Context, key string) error
+		AddToSet(ctx context.Context, key string, member string, ttl time.Duration) error
+		PopSet(ctx context.Context, key string) ([]string, error)
+		Del(ctx context.Context, keys ...string) error
 	}
 )
 
-// Save stores the given token in the cache with a configured TTL. Returns an error if the operation fails.
-func (s *Storage) Save(ctx context.Context, token string) error {
-	return s.cache.Set(ctx, s.hash(token), "", s.refreshTokenTTL)
+// sessionsKeyPrefix prefixes the keys of per-user sets of refresh token hashes.
+const sessionsKeyPrefix = "jwt:sessions:"
+
+// Save stores the given token of the user in the cache with a configured TTL and indexes it under the user,
+// so that it can be revoked by RevokeAll. Returns an error if the operation fails.
+func (s *Storage) Save(ctx context.Context, userID string, token string) error {
+	key := s.hash(token)
+
+	if err := s.cache.Set(ctx, key, "", s.refreshTokenTTL); err != nil {
+		return err
+	}
+
+	return s.cache.AddToSet(ctx, sessionsKeyPrefix+userID, key, s.refreshTokenTTL)
 }
 
 // Pop removes the specified token from the cache using its hashed value and the provided context.
@@ -46,6 +62,21 @@ func (s *Storage) Pop(ctx context.Context, token string) error {
 	return s.cache.Pop(ctx, s.hash(token))
 }
 
+// RevokeAll removes all refresh tokens of the user from the cache, ending all of the user's sessions.
+// Hashes of tokens that were already popped are left in the index until it expires and are ignored here.
+func (s *Storage) RevokeAll(ctx context.Context, userID string) error {
+	keys, err := s.cache.PopSet(ctx, sessionsKeyPrefix+userID)
+	if err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		return nil
+	}
+
+	return s.cache.Del(ctx, keys...)
+}
+
 // hash generates a SHA-256 hash of the provided token and returns it as a string.
 func (s *Storage) hash(token string) string {
 	sum := sha256.Sum256([]byte(token))
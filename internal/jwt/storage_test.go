@@ -17,15 +17,27 @@ func TestStorage_Save(t *testing.T) {
 		cache.On("Set", t.Context(), hashStorageKey("test_key"), "", time.Second*5).Return(assert.AnError)
 
 		storage := jwt.NewStorage(time.Second*5, cache)
-		assert.Equal(t, assert.AnError, storage.Save(t.Context(), "test_key"))
+		assert.Equal(t, assert.AnError, storage.Save(t.Context(), "user_id", "test_key"))
+	})
+
+	t.Run("failed to index token", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("Set", t.Context(), hashStorageKey("test_key"), "", time.Second*5).Return(nil)
+		cache.On("AddToSet", t.Context(), "jwt:sessions:user_id", hashStorageKey("test_key"), time.Second*5).
+			Return(assert.AnError)
+
+		storage := jwt.NewStorage(time.Second*5, cache)
+		assert.Equal(t, assert.AnError, storage.Save(t.Context(), "user_id", "test_key"))
 	})
 
 	t.Run("positive case", func(t *testing.T) {
 		cache := mocks.NewCache(t)
 		cache.On("Set", t.Context(), hashStorageKey("test_key"), "", time.Second*5).Return(nil)
+		cache.On("AddToSet", t.Context(), "jwt:sessions:user_id", hashStorageKey("test_key"), time.Second*5).
+			Return(nil)
 
 		storage := jwt.NewStorage(time.Second*5, cache)
-		assert.NoError(t, storage.Save(t.Context(), "test_key"))
+		assert.NoError(t, storage.Save(t.Context(), "user_id", "test_key"))
 	})
 }
 
@@ -47,6 +59,44 @@ func TestStorage_Pop(t *testing.T) {
 	})
 }
 
+func TestStorage_RevokeAll(t *testing.T) {
+	keys := []string{hashStorageKey("token_1"), hashStorageKey("token_2")}
+
+	t.Run("failed to pop index", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("PopSet", t.Context(), "jwt:sessions:user_id").Return(nil, assert.AnError)
+
+		storage := jwt.NewStorage(time.Second*5, cache)
+		assert.Equal(t, assert.AnError, storage.RevokeAll(t.Context(), "user_id"))
+	})
+
+	t.Run("no sessions", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("PopSet", t.Context(), "jwt:sessions:user_id").Return([]string{}, nil)
+
+		storage := jwt.NewStorage(time.Second*5, cache)
+		assert.NoError(t, storage.RevokeAll(t.Context(), "user_id"))
+	})
+
+	t.Run("failed to delete tokens", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("PopSet", t.Context(), "jwt:sessions:user_id").Return(keys, nil)
+		cache.On("Del", t.Context(), keys[0], keys[1]).Return(assert.AnError)
+
+		storage := jwt.NewStorage(time.Second*5, cache)
+		assert.Equal(t, assert.AnError, storage.RevokeAll(t.Context(), "user_id"))
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("PopSet", t.Context(), "jwt:sessions:user_id").Return(keys, nil)
+		cache.On("Del", t.Context(), keys[0], keys[1]).Return(nil)
+
+		storage := jwt.NewStorage(time.Second*5, cache)
+		assert.NoError(t, storage.RevokeAll(t.Context(), "user_id"))
+	})
+}
+
 func hashStorageKey(token string) string {
 	sum := sha256.Sum256([]byte(token))
 	return string(sum[:])
@@ -5,10 +5,15 @@ package jwt
 import (
 	"context"
 	"errors"
+	"net/http"
+	"strings"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// ScopeRead is the scope of access tokens that may only be used to read data.
+const ScopeRead = "read"
+
 // NewVerifier creates and returns a new instance of Verifier with the specified secret and Parser implementation.
 func NewVerifier(
 	secret string,
@@ -27,6 +32,14 @@ type (
 		parser Parser
 	}
 
+	// AccessClaims holds the claims of a verified access token.
+	// ActorID is set only for impersonation tokens and names the user acting as the subject.
+	AccessClaims struct {
+		Subject string
+		ActorID string
+		Scope   string
+	}
+
 	// Parser defines an interface for parsing JWT tokens with claims and a key function.
 	Parser interface {
 		ParseWithClaims(tokenString string, claims jwt.Claims, keyFunc jwt.Keyfunc) (*jwt.Token, error)
@@ -35,16 +48,63 @@ type (
 
 // VerifyAccess validates an access token and returns the subject if the token is valid, or an error if it is invalid.
 func (v *Verifier) VerifyAccess(ctx context.Context, token string) (string, error) {
-	return v.verify(ctx, token, tokenTypeAccessToken)
+	claims, err := v.verify(ctx, token, tokenTypeAccessToken)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.Subject, nil
+}
+
+// VerifyAccessClaims validates an access token and returns its claims if the token is valid, or an error if it is invalid.
+func (v *Verifier) VerifyAccessClaims(ctx context.Context, token string) (AccessClaims, error) {
+	claims, err := v.verify(ctx, token, tokenTypeAccessToken)
+	if err != nil {
+		return AccessClaims{}, err
+	}
+
+	accessClaims := AccessClaims{
+		Subject: claims.Subject,
+		Scope:   claims.Scope,
+	}
+
+	if claims.Actor != nil {
+		accessClaims.ActorID = claims.Actor.Subject
+	}
+
+	return accessClaims, nil
+}
+
+// Allows reports whether the token may be used for a request with the given method. Tokens of users acting as
+// themselves carry no scope and allow everything. Scoped tokens and impersonation tokens allow only what their scope
+// grants: the read scope allows safe methods, and other scopes allow nothing, so that a token never grants more
+// than its scope explicitly says.
+func (c AccessClaims) Allows(method string) bool {
+	if c.Scope == "" && c.ActorID == "" {
+		return true
+	}
+
+	for _, scope := range strings.Fields(c.Scope) {
+		if scope == ScopeRead && isSafeMethod(method) {
+			return true
+		}
+	}
+
+	return false
 }
 
 // VerifyRefresh validates a given refresh token and returns the subject if valid, or an error otherwise.
 func (v *Verifier) VerifyRefresh(ctx context.Context, token string) (string, error) {
-	return v.verify(ctx, token, tokenTypeRefreshToken)
+	claims, err := v.verify(ctx, token, tokenTypeRefreshToken)
+	if err != nil {
+		return "", err
+	}
+
+	return claims.Subject, nil
 }
 
-// verify validates a token's signature, claims, and type, and returns the subject if valid or an error otherwise.
-func (v *Verifier) verify(_ context.Context, token string, tokenType string) (string, error) {
+// verify validates a token's signature, claims, and type, and returns the claims if valid or an error otherwise.
+func (v *Verifier) verify(_ context.Context, token string, tokenType string) (*claimsWithType, error) {
 	var (
 		err         error
 		claims      claimsWithType
@@ -57,20 +117,29 @@ func (v *Verifier) verify(_ context.Context, token string, tokenType string) (st
 
 		return []byte(v.secret), nil
 	}); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	if !parsedToken.Valid {
-		return "", errors.New("invalid token")
+		return nil, errors.New("invalid token")
 	}
 
 	if claims.Type != tokenType {
-		return "", jwt.ErrTokenInvalidClaims
+		return nil, jwt.ErrTokenInvalidClaims
 	}
 
 	if claims.Subject == "" {
-		return "", jwt.ErrTokenInvalidClaims
+		return nil, jwt.ErrTokenInvalidClaims
 	}
 
-	return claims.Subject, nil
+	if claims.Actor != nil && claims.Actor.Subject == "" {
+		return nil, jwt.ErrTokenInvalidClaims
+	}
+
+	return &claims, nil
+}
+
+// isSafeMethod reports whether the method is not meant to change the state of the server (RFC 9110, section 9.2.1).
+func isSafeMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"reflect"
 	"testing"
+	"time"
 
 	gojwt "github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
@@ -213,3 +214,49 @@ func TestVerifier_VerifyRefresh(t *testing.T) {
 		assert.Equal(t, "user_456", subject)
 	})
 }
+
+func TestVerifier_VerifyAccessClaims(t *testing.T) {
+	issuer := jwt.NewIssuer("test_issuer", "secret", time.Minute, time.Minute)
+	verifier := jwt.NewVerifier("secret", gojwt.NewParser(
+		gojwt.WithValidMethods([]string{gojwt.SigningMethodHS256.Name}),
+		gojwt.WithIssuer("test_issuer"),
+	))
+
+	t.Run("refresh token", func(t *testing.T) {
+		token, err := issuer.IssueRefreshToken("user_id")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		claims, err := verifier.VerifyAccessClaims(context.Background(), token)
+		assert.Empty(t, claims)
+		assert.ErrorIs(t, err, gojwt.ErrTokenInvalidClaims)
+	})
+
+	t.Run("access token", func(t *testing.T) {
+		token, err := issuer.IssueAccessToken("user_id")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		claims, err := verifier.VerifyAccessClaims(context.Background(), token)
+		assert.NoError(t, err)
+		assert.Equal(t, jwt.AccessClaims{Subject: "user_id"}, claims)
+	})
+
+	t.Run("impersonation token", func(t *testing.T) {
+		token, err := issuer.IssueImpersonationToken("user_id", "admin_id", "read", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		claims, err := verifier.VerifyAccessClaims(context.Background(), token)
+		assert.NoError(t, err)
+		assert.Equal(t, jwt.AccessClaims{Subject: "user_id", ActorID: "admin_id", Scope: "read"}, claims)
+
+		// impersonation tokens are still valid access tokens for the subject
+		subject, err := verifier.VerifyAccess(context.Background(), token)
+		assert.NoError(t, err)
+		assert.Equal(t, "user_id", subject)
+	})
+}
@@ -0,0 +1,108 @@
+package mail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/riabininkf/go-modules/logger"
+
+	"github.com/riabininkf/http-auth-example/internal/random"
+)
+
+// NewFile creates a new *File instance writing messages into the given directory.
+func NewFile(log *logger.Logger, dir string) *File {
+	return &File{
+		log: log,
+		dir: dir,
+	}
+}
+
+// File is a stand-in for a mail server for local runs and tests: every message is written into the directory
+// as a JSON file and logged, without its body, as it may carry secrets.
+type File struct {
+	log *logger.Logger
+	dir string
+}
+
+// Send writes the message into a new file. File names sort in the order messages were sent.
+// Returns ErrInvalidMessage if the message is malformed.
+func (f *File) Send(_ context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mail message: %w", err)
+	}
+
+	if err = os.MkdirAll(f.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create mail directory: %w", err)
+	}
+
+	var suffix string
+	if suffix, err = random.String(6); err != nil {
+		return fmt.Errorf("failed to generate mail file name: %w", err)
+	}
+
+	path := filepath.Join(f.dir, fmt.Sprintf("%020d-%s.json", time.Now().UnixNano(), suffix))
+	if err = os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write mail message: %w", err)
+	}
+
+	f.log.Info(
+		"mail sent",
+		logger.String("to", msg.To),
+		logger.String("subject", msg.Subject),
+		logger.String("path", path),
+	)
+
+	return nil
+}
+
+// ReadFiles returns the messages written by File into the directory, oldest first.
+// A missing directory means no messages were sent yet.
+func ReadFiles(dir string) ([]Message, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".json") {
+			names = append(names, entry.Name())
+		}
+	}
+
+	sort.Strings(names)
+
+	messages := make([]Message, 0, len(names))
+	for _, name := range names {
+		var data []byte
+		if data, err = os.ReadFile(filepath.Join(dir, name)); err != nil {
+			return nil, err
+		}
+
+		var msg Message
+		if err = json.Unmarshal(data, &msg); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal mail message %s: %w", name, err)
+		}
+
+		messages = append(messages, msg)
+	}
+
+	return messages, nil
+}
@@ -0,0 +1,80 @@
+package mail_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/riabininkf/http-auth-example/internal/mail"
+)
+
+func TestFile_Send(t *testing.T) {
+	testCases := map[string]struct {
+		msg    mail.Message
+		expErr error
+	}{
+		"recipient is missing": {
+			msg:    mail.Message{Subject: "subject", Body: "body"},
+			expErr: mail.ErrInvalidMessage,
+		},
+		"line break in recipient": {
+			msg:    mail.Message{To: "user@example.com\r\nBcc: other@example.com", Subject: "subject"},
+			expErr: mail.ErrInvalidMessage,
+		},
+		"line break in subject": {
+			msg:    mail.Message{To: "user@example.com", Subject: "subject\nBcc: other@example.com"},
+			expErr: mail.ErrInvalidMessage,
+		},
+		"positive case": {
+			msg: mail.Message{To: "user@example.com", Subject: "subject", Body: "first line\nsecond line"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "mail")
+
+			err := mail.NewFile(zap.NewNop(), dir).Send(t.Context(), tc.msg)
+			assert.Equal(t, tc.expErr, err)
+
+			if tc.expErr != nil {
+				return
+			}
+
+			messages, err := mail.ReadFiles(dir)
+			assert.NoError(t, err)
+			assert.Equal(t, []mail.Message{tc.msg}, messages)
+		})
+	}
+
+	t.Run("messages are read in the order they were sent", func(t *testing.T) {
+		dir := t.TempDir()
+		mailer := mail.NewFile(zap.NewNop(), dir)
+
+		expected := []mail.Message{
+			{To: "first@example.com", Subject: "first"},
+			{To: "second@example.com", Subject: "second"},
+			{To: "third@example.com", Subject: "third"},
+		}
+
+		for _, msg := range expected {
+			if err := mailer.Send(t.Context(), msg); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		messages, err := mail.ReadFiles(dir)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, messages)
+	})
+}
+
+func TestReadFiles(t *testing.T) {
+	t.Run("no messages sent yet", func(t *testing.T) {
+		messages, err := mail.ReadFiles(filepath.Join(t.TempDir(), "mail"))
+		assert.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+}
@@ -0,0 +1,37 @@
+package mail
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidMessage is returned for messages without a recipient or with line breaks in the headers.
+var ErrInvalidMessage = errors.New("invalid mail message")
+
+type (
+	// Mailer sends plain text email messages.
+	Mailer interface {
+		Send(ctx context.Context, msg Message) error
+	}
+
+	// Message is a plain text email message.
+	Message struct {
+		To      string `json:"to"`
+		Subject string `json:"subject"`
+		Body    string `json:"body"`
+	}
+)
+
+// validate checks that the message has a recipient and that none of the header values can inject other headers.
+func (m Message) validate() error {
+	if m.To == "" {
+		return ErrInvalidMessage
+	}
+
+	if strings.ContainsAny(m.To, "\r\n") || strings.ContainsAny(m.Subject, "\r\n") {
+		return ErrInvalidMessage
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package mail
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+)
+
+const (
+	// DefMailerName is the name of the Mailer definition.
+	DefMailerName = "mail.mailer"
+
+	configKeyDriver       = "mail.driver"
+	configKeyFrom         = "mail.from"
+	configKeySMTPHost     = "mail.smtp.host"
+	configKeySMTPPort     = "mail.smtp.port"
+	configKeySMTPUsername = "mail.smtp.username"
+	configKeySMTPPassword = "mail.smtp.password"
+	configKeyFileDir      = "mail.file.dir"
+
+	driverSMTP = "smtp"
+	driverFile = "file"
+)
+
+func init() {
+	di.Add(
+		di.Def[Mailer]{
+			Name: DefMailerName,
+			Build: func(ctn di.Container) (Mailer, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				switch driver := cfg.GetString(configKeyDriver); driver {
+				case driverSMTP:
+					var from string
+					if from = cfg.GetString(configKeyFrom); from == "" {
+						return nil, config.NewErrMissingKey(configKeyFrom)
+					}
+
+					var host string
+					if host = cfg.GetString(configKeySMTPHost); host == "" {
+						return nil, config.NewErrMissingKey(configKeySMTPHost)
+					}
+
+					var port string
+					if port = cfg.GetString(configKeySMTPPort); port == "" {
+						return nil, config.NewErrMissingKey(configKeySMTPPort)
+					}
+
+					return NewSMTP(
+						net.JoinHostPort(host, port),
+						from,
+						cfg.GetString(configKeySMTPUsername),
+						cfg.GetString(configKeySMTPPassword),
+					), nil
+				case driverFile:
+					var log *logger.Logger
+					if err := ctn.Fill(logger.DefName, &log); err != nil {
+						return nil, err
+					}
+
+					var dir string
+					if dir = cfg.GetString(configKeyFileDir); dir == "" {
+						return nil, config.NewErrMissingKey(configKeyFileDir)
+					}
+
+					return NewFile(log, dir), nil
+				case "":
+					return nil, config.NewErrMissingKey(configKeyDriver)
+				default:
+					return nil, fmt.Errorf("unknown mail driver %q", driver)
+				}
+			},
+		},
+	)
+}
@@ -0,0 +1,69 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// NewSMTP creates a new *SMTP instance sending messages from the given address through the SMTP server at addr.
+// Messages are sent without authentication if the username is empty.
+func NewSMTP(
+	addr string,
+	from string,
+	username string,
+	password string,
+) *SMTP {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := strings.Cut(addr, ":")
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTP{
+		addr: addr,
+		from: from,
+		auth: auth,
+	}
+}
+
+// SMTP sends messages through an SMTP server. STARTTLS is used whenever the server supports it.
+type SMTP struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// Send delivers the message to the SMTP server. Returns ErrInvalidMessage if the message is malformed.
+func (s *SMTP) Send(_ context.Context, msg Message) error {
+	if err := msg.validate(); err != nil {
+		return err
+	}
+
+	if err := smtp.SendMail(s.addr, s.auth, s.from, []string{msg.To}, s.format(msg)); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
+
+// format renders the message in the Internet Message Format (RFC 5322).
+func (s *SMTP) format(msg Message) []byte {
+	var buf bytes.Buffer
+
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	buf.WriteString("\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(msg.Body, "\r\n", "\n"), "\n", "\r\n"))
+
+	return buf.Bytes()
+}
@@ -0,0 +1,25 @@
+package mail_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/mail"
+)
+
+func TestSMTP_Send(t *testing.T) {
+	// invalid messages are rejected before connecting, so no server is needed
+	testCases := map[string]mail.Message{
+		"recipient is missing":    {Subject: "subject"},
+		"line break in recipient": {To: "user@example.com\nBcc: other@example.com", Subject: "subject"},
+		"line break in subject":   {To: "user@example.com", Subject: "subject\r\nBcc: other@example.com"},
+	}
+
+	for name, msg := range testCases {
+		t.Run(name, func(t *testing.T) {
+			err := mail.NewSMTP("127.0.0.1:0", "noreply@example.com", "", "").Send(t.Context(), msg)
+			assert.Equal(t, mail.ErrInvalidMessage, err)
+		})
+	}
+}
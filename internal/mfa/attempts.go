@@ -0,0 +1,65 @@
+package mfa
+
+//go:generate mockery --name AttemptsCache --output ./mocks --outpkg mocks --filename attempts_cache.go --structname AttemptsCache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrTooManyAttempts is returned once a user has entered too many authentication codes in a row.
+var ErrTooManyAttempts = errors.New("too many authentication code attempts")
+
+const attemptsKeyPrefix = "mfa:attempts:"
+
+// NewAttempts creates a new *Attempts instance that allows maxAttempts codes per user
+// until one of them is valid or window passes.
+func NewAttempts(window time.Duration, maxAttempts int, cache AttemptsCache) *Attempts {
+	return &Attempts{
+		window:      window,
+		maxAttempts: maxAttempts,
+		cache:       cache,
+	}
+}
+
+type (
+	// Attempts limits the authentication codes a user can enter where there is no MFA challenge to count them,
+	// e.g. on the server-rendered pages that check the password and the code at once.
+	Attempts struct {
+		window      time.Duration
+		maxAttempts int
+		cache       AttemptsCache
+	}
+
+	// AttemptsCache defines methods for counters with a TTL.
+	AttemptsCache interface {
+		Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+		Del(ctx context.Context, keys ...string) error
+	}
+)
+
+// Add records an attempt of the user to enter a code and returns ErrTooManyAttempts if there are none left.
+// Attempts are counted before the code is checked, so that concurrent requests cannot exceed the limit.
+func (a *Attempts) Add(ctx context.Context, userID string) error {
+	attempts, err := a.cache.Incr(ctx, attemptsKeyPrefix+userID, a.window)
+	if err != nil {
+		return fmt.Errorf("failed to count code attempt: %w", err)
+	}
+
+	if attempts > int64(a.maxAttempts) {
+		return ErrTooManyAttempts
+	}
+
+	return nil
+}
+
+// Reset forgets the attempts of the user, e.g. after a valid code.
+func (a *Attempts) Reset(ctx context.Context, userID string) error {
+	if err := a.cache.Del(ctx, attemptsKeyPrefix+userID); err != nil {
+		return fmt.Errorf("failed to reset code attempts: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package mfa
+
+import (
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+// DefAttemptsName is the name of the *Attempts definition.
+const DefAttemptsName = "mfa.attempts"
+
+func init() {
+	di.Add(
+		di.Def[*Attempts]{
+			Name: DefAttemptsName,
+			Build: func(ctn di.Container) (*Attempts, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				// a user gets as many codes in a row as a single MFA token allows, for as long as it lives
+				var window time.Duration
+				if window = cfg.GetDuration(configKeyChallengeTTL); window == 0 {
+					return nil, config.NewErrMissingKey(configKeyChallengeTTL)
+				}
+
+				var maxAttempts int
+				if maxAttempts = cfg.GetInt(configKeyMaxChallengeAttempts); maxAttempts == 0 {
+					return nil, config.NewErrMissingKey(configKeyMaxChallengeAttempts)
+				}
+
+				var cache *redis.Client
+				if err := ctn.Fill(redis.DefClientName, &cache); err != nil {
+					return nil, err
+				}
+
+				return NewAttempts(window, maxAttempts, cache), nil
+			},
+		},
+	)
+}
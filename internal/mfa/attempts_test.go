@@ -0,0 +1,57 @@
+package mfa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+	"github.com/riabininkf/http-auth-example/internal/mfa/mocks"
+)
+
+func TestAttempts_Add(t *testing.T) {
+	testCases := map[string]struct {
+		onIncr func() (int64, error)
+		expErr error
+	}{
+		"failed to count attempt": {
+			onIncr: func() (int64, error) { return 0, assert.AnError },
+			expErr: assert.AnError,
+		},
+		"attempts left": {
+			onIncr: func() (int64, error) { return 3, nil },
+		},
+		"out of attempts": {
+			onIncr: func() (int64, error) { return 4, nil },
+			expErr: mfa.ErrTooManyAttempts,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewAttemptsCache(t)
+			cache.On("Incr", t.Context(), "mfa:attempts:user_id", time.Minute).Return(tc.onIncr())
+
+			err := mfa.NewAttempts(time.Minute, 3, cache).Add(t.Context(), "user_id")
+			assert.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestAttempts_Reset(t *testing.T) {
+	t.Run("failed to reset attempts", func(t *testing.T) {
+		cache := mocks.NewAttemptsCache(t)
+		cache.On("Del", t.Context(), "mfa:attempts:user_id").Return(assert.AnError)
+
+		err := mfa.NewAttempts(time.Minute, 3, cache).Reset(t.Context(), "user_id")
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		cache := mocks.NewAttemptsCache(t)
+		cache.On("Del", t.Context(), "mfa:attempts:user_id").Return(nil)
+
+		assert.NoError(t, mfa.NewAttempts(time.Minute, 3, cache).Reset(t.Context(), "user_id"))
+	})
+}
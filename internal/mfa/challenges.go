@@ -0,0 +1,171 @@
+package mfa
+
+//go:generate mockery --name Cache --output ./mocks --outpkg mocks --filename cache.go --structname Cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/random"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+// ErrInvalidChallenge is returned when the challenge token is unknown, expired, already completed
+// or has run out of attempts.
+var ErrInvalidChallenge = errors.New("invalid mfa challenge")
+
+const (
+	challengeKeyPrefix         = "mfa:challenge:"
+	challengeAttemptsKeySuffix = ":attempts"
+	challengeTokenSize         = 32
+)
+
+// NewChallenges creates a new *Challenges instance with the provided challenge TTL, the number of allowed
+// attempts and cache implementation.
+func NewChallenges(
+	ttl time.Duration,
+	maxAttempts int,
+	cache Cache,
+) *Challenges {
+	return &Challenges{
+		ttl:         ttl,
+		maxAttempts: maxAttempts,
+		cache:       cache,
+	}
+}
+
+type (
+	// Challenges keeps short-lived MFA challenges of logins that passed the password check
+	// and wait for the second factor.
+	Challenges struct {
+		ttl         time.Duration
+		maxAttempts int
+		cache       Cache
+	}
+
+	// Challenge is the state of a pending second factor check.
+	Challenge struct {
+		UserID string `json:"user_id"`
+	}
+
+	// ChallengeToken is the opaque token returned to the client to complete the login.
+	ChallengeToken struct {
+		Token     string
+		ExpiresIn time.Duration
+	}
+
+	// Cache defines methods for storing values with a TTL, reading them, atomically reading and removing them,
+	// counting, and removing keys.
+	Cache interface {
+		Set(ctx context.Context, key string, value any, ttl time.Duration) error
+		Get(ctx context.Context, key string) (string, error)
+		GetDel(ctx context.Context, key string) (string, error)
+		Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+		Del(ctx context.Context, keys ...string) error
+	}
+)
+
+// Create starts a new challenge for the user.
+func (c *Challenges) Create(ctx context.Context, userID string) (ChallengeToken, error) {
+	var (
+		err   error
+		token string
+	)
+	if token, err = random.String(challengeTokenSize); err != nil {
+		return ChallengeToken{}, fmt.Errorf("failed to generate challenge token: %w", err)
+	}
+
+	if err = c.save(ctx, c.key(token), Challenge{UserID: userID}, c.ttl); err != nil {
+		return ChallengeToken{}, err
+	}
+
+	return ChallengeToken{
+		Token:     token,
+		ExpiresIn: c.ttl,
+	}, nil
+}
+
+// Get returns the pending challenge. Returns ErrInvalidChallenge if the token is unknown or expired.
+func (c *Challenges) Get(ctx context.Context, token string) (Challenge, error) {
+	challenge, err := c.load(ctx, c.key(token))
+	if err != nil {
+		if errors.Is(err, redis.ErrNotFound) {
+			return Challenge{}, ErrInvalidChallenge
+		}
+
+		return Challenge{}, err
+	}
+
+	return challenge, nil
+}
+
+// Fail records a failed attempt. The challenge is removed once it runs out of attempts,
+// so that the code cannot be brute-forced with a single password check. Attempts are counted atomically
+// under a separate key, so that concurrent failures cannot be counted as one.
+func (c *Challenges) Fail(ctx context.Context, token string) error {
+	key := c.key(token)
+
+	attempts, err := c.cache.Incr(ctx, key+challengeAttemptsKeySuffix, c.ttl)
+	if err != nil {
+		return err
+	}
+
+	if attempts < int64(c.maxAttempts) {
+		return nil
+	}
+
+	return c.cache.Del(ctx, key, key+challengeAttemptsKeySuffix)
+}
+
+// Complete removes the challenge once the second factor is verified, so that it can be completed only once.
+// Returns ErrInvalidChallenge if it was already completed or expired.
+func (c *Challenges) Complete(ctx context.Context, token string) error {
+	if _, err := c.cache.GetDel(ctx, c.key(token)); err != nil {
+		if errors.Is(err, redis.ErrNotFound) {
+			return ErrInvalidChallenge
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// load reads the challenge stored under the key.
+func (c *Challenges) load(ctx context.Context, key string) (Challenge, error) {
+	var (
+		err   error
+		value string
+	)
+	if value, err = c.cache.Get(ctx, key); err != nil {
+		return Challenge{}, err
+	}
+
+	var challenge Challenge
+	if err = json.Unmarshal([]byte(value), &challenge); err != nil {
+		return Challenge{}, fmt.Errorf("failed to unmarshal mfa challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// save writes the challenge under the key with the given TTL.
+func (c *Challenges) save(ctx context.Context, key string, challenge Challenge, ttl time.Duration) error {
+	value, err := json.Marshal(challenge)
+	if err != nil {
+		return fmt.Errorf("failed to marshal mfa challenge: %w", err)
+	}
+
+	return c.cache.Set(ctx, key, string(value), ttl)
+}
+
+// key returns the cache key for the challenge token. Only the hash is stored.
+func (c *Challenges) key(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return challengeKeyPrefix + hex.EncodeToString(sum[:])
+}
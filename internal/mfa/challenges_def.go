@@ -0,0 +1,49 @@
+package mfa
+
+import (
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+const (
+	// DefChallengesName is the name of the *Challenges definition.
+	DefChallengesName = "mfa.challenges"
+
+	configKeyChallengeTTL         = "auth.mfa.challengeTTL"
+	configKeyMaxChallengeAttempts = "auth.mfa.maxChallengeAttempts"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Challenges]{
+			Name: DefChallengesName,
+			Build: func(ctn di.Container) (*Challenges, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var ttl time.Duration
+				if ttl = cfg.GetDuration(configKeyChallengeTTL); ttl == 0 {
+					return nil, config.NewErrMissingKey(configKeyChallengeTTL)
+				}
+
+				var maxAttempts int
+				if maxAttempts = cfg.GetInt(configKeyMaxChallengeAttempts); maxAttempts == 0 {
+					return nil, config.NewErrMissingKey(configKeyMaxChallengeAttempts)
+				}
+
+				var cache *redis.Client
+				if err := ctn.Fill(redis.DefClientName, &cache); err != nil {
+					return nil, err
+				}
+
+				return NewChallenges(ttl, maxAttempts, cache), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,176 @@
+package mfa_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+	"github.com/riabininkf/http-auth-example/internal/mfa/mocks"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+func TestChallenges_Create(t *testing.T) {
+	t.Run("failed to save challenge", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("Set", t.Context(), mock.AnythingOfType("string"), `{"user_id":"user_id"}`, time.Minute).
+			Return(assert.AnError)
+
+		token, err := mfa.NewChallenges(time.Minute, 3, cache).Create(t.Context(), "user_id")
+		assert.Empty(t, token)
+		assert.Equal(t, assert.AnError, err)
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		var key string
+
+		cache := mocks.NewCache(t)
+		cache.On("Set", t.Context(), mock.AnythingOfType("string"), `{"user_id":"user_id"}`, time.Minute).
+			Run(func(args mock.Arguments) { key = args.String(1) }).
+			Return(nil)
+
+		token, err := mfa.NewChallenges(time.Minute, 3, cache).Create(t.Context(), "user_id")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token.Token)
+		assert.Equal(t, time.Minute, token.ExpiresIn)
+		assert.Equal(t, challengeKey(token.Token), key)
+	})
+}
+
+func TestChallenges_Get(t *testing.T) {
+	key := challengeKey("token")
+
+	testCases := map[string]struct {
+		onGet        func(cache *mocks.Cache)
+		expChallenge mfa.Challenge
+		expErr       error
+	}{
+		"unknown token": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return("", redis.ErrNotFound)
+			},
+			expErr: mfa.ErrInvalidChallenge,
+		},
+		"failed to get challenge": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return("", assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"positive case": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return(`{"user_id":"user_id"}`, nil)
+			},
+			expChallenge: mfa.Challenge{UserID: "user_id"},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewCache(t)
+			tc.onGet(cache)
+
+			challenge, err := mfa.NewChallenges(time.Minute, 3, cache).Get(t.Context(), "token")
+			assert.Equal(t, tc.expChallenge, challenge)
+			assert.Equal(t, tc.expErr, err)
+		})
+	}
+}
+
+func TestChallenges_Fail(t *testing.T) {
+	key := challengeKey("token")
+	attemptsKey := key + ":attempts"
+
+	testCases := map[string]struct {
+		onCache func(cache *mocks.Cache)
+		expErr  error
+	}{
+		"failed to count attempt": {
+			onCache: func(cache *mocks.Cache) {
+				cache.On("Incr", t.Context(), attemptsKey, time.Minute).Return(int64(0), assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"attempts left": {
+			onCache: func(cache *mocks.Cache) {
+				cache.On("Incr", t.Context(), attemptsKey, time.Minute).Return(int64(2), nil)
+			},
+		},
+		"out of attempts": {
+			onCache: func(cache *mocks.Cache) {
+				cache.On("Incr", t.Context(), attemptsKey, time.Minute).Return(int64(3), nil)
+				cache.On("Del", t.Context(), key, attemptsKey).Return(nil)
+			},
+		},
+		"concurrent attempts over the limit": {
+			onCache: func(cache *mocks.Cache) {
+				cache.On("Incr", t.Context(), attemptsKey, time.Minute).Return(int64(5), nil)
+				cache.On("Del", t.Context(), key, attemptsKey).Return(nil)
+			},
+		},
+		"failed to remove challenge": {
+			onCache: func(cache *mocks.Cache) {
+				cache.On("Incr", t.Context(), attemptsKey, time.Minute).Return(int64(3), nil)
+				cache.On("Del", t.Context(), key, attemptsKey).Return(assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewCache(t)
+			tc.onCache(cache)
+
+			err := mfa.NewChallenges(time.Minute, 3, cache).Fail(t.Context(), "token")
+			assert.Equal(t, tc.expErr, err)
+		})
+	}
+}
+
+func TestChallenges_Complete(t *testing.T) {
+	key := challengeKey("token")
+
+	testCases := map[string]struct {
+		onGetDel func(cache *mocks.Cache)
+		expErr   error
+	}{
+		"already completed": {
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), key).Return("", redis.ErrNotFound)
+			},
+			expErr: mfa.ErrInvalidChallenge,
+		},
+		"failed to remove challenge": {
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), key).Return("", assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"positive case": {
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), key).Return(`{"user_id":"user_id"}`, nil)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewCache(t)
+			tc.onGetDel(cache)
+
+			err := mfa.NewChallenges(time.Minute, 3, cache).Complete(t.Context(), "token")
+			assert.Equal(t, tc.expErr, err)
+		})
+	}
+}
+
+// challengeKey returns the cache key under which the challenge token is stored.
+func challengeKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "mfa:challenge:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,82 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import mock "github.com/stretchr/testify/mock"
+
+// Cipher is an autogenerated mock type for the Cipher type
+type Cipher struct {
+	mock.Mock
+}
+
+// Decrypt provides a mock function with given fields: ciphertext, associatedData
+func (_m *Cipher) Decrypt(ciphertext string, associatedData []byte) ([]byte, error) {
+	ret := _m.Called(ciphertext, associatedData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Decrypt")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(string, []byte) ([]byte, error)); ok {
+		return rf(ciphertext, associatedData)
+	}
+	if rf, ok := ret.Get(0).(func(string, []byte) []byte); ok {
+		r0 = rf(ciphertext, associatedData)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(string, []byte) error); ok {
+		r1 = rf(ciphertext, associatedData)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Encrypt provides a mock function with given fields: plaintext, associatedData
+func (_m *Cipher) Encrypt(plaintext []byte, associatedData []byte) (string, error) {
+	ret := _m.Called(plaintext, associatedData)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Encrypt")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func([]byte, []byte) (string, error)); ok {
+		return rf(plaintext, associatedData)
+	}
+	if rf, ok := ret.Get(0).(func([]byte, []byte) string); ok {
+		r0 = rf(plaintext, associatedData)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func([]byte, []byte) error); ok {
+		r1 = rf(plaintext, associatedData)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewCipher creates a new instance of Cipher. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCipher(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *Cipher {
+	mock := &Cipher{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
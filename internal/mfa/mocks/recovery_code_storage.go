@@ -0,0 +1,74 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// RecoveryCodeStorage is an autogenerated mock type for the RecoveryCodeStorage type
+type RecoveryCodeStorage struct {
+	mock.Mock
+}
+
+// Replace provides a mock function with given fields: ctx, userID, codeHashes
+func (_m *RecoveryCodeStorage) Replace(ctx context.Context, userID string, codeHashes []string) error {
+	ret := _m.Called(ctx, userID, codeHashes)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Replace")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = rf(ctx, userID, codeHashes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Use provides a mock function with given fields: ctx, userID, codeHash
+func (_m *RecoveryCodeStorage) Use(ctx context.Context, userID string, codeHash string) (bool, error) {
+	ret := _m.Called(ctx, userID, codeHash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Use")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (bool, error)); ok {
+		return rf(ctx, userID, codeHash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(ctx, userID, codeHash)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, userID, codeHash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewRecoveryCodeStorage creates a new instance of RecoveryCodeStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewRecoveryCodeStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *RecoveryCodeStorage {
+	mock := &RecoveryCodeStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
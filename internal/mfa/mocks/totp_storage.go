@@ -0,0 +1,122 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// TOTPStorage is an autogenerated mock type for the TOTPStorage type
+type TOTPStorage struct {
+	mock.Mock
+}
+
+// Confirm provides a mock function with given fields: ctx, userID, step
+func (_m *TOTPStorage) Confirm(ctx context.Context, userID string, step int64) error {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Confirm")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetByUserID provides a mock function with given fields: ctx, userID
+func (_m *TOTPStorage) GetByUserID(ctx context.Context, userID string) (domain.TOTPCredential, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByUserID")
+	}
+
+	var r0 domain.TOTPCredential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.TOTPCredential, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.TOTPCredential); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		r0 = ret.Get(0).(domain.TOTPCredential)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, credential
+func (_m *TOTPStorage) Save(ctx context.Context, credential domain.TOTPCredential) error {
+	ret := _m.Called(ctx, credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TOTPCredential) error); ok {
+		r0 = rf(ctx, credential)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseStep provides a mock function with given fields: ctx, userID, step
+func (_m *TOTPStorage) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	ret := _m.Called(ctx, userID, step)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UseStep")
+	}
+
+	var r0 bool
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) (bool, error)); ok {
+		return rf(ctx, userID, step)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) bool); ok {
+		r0 = rf(ctx, userID, step)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int64) error); ok {
+		r1 = rf(ctx, userID, step)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewTOTPStorage creates a new instance of TOTPStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewTOTPStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *TOTPStorage {
+	mock := &TOTPStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,118 @@
+package mfa
+
+//go:generate mockery --name RecoveryCodeStorage --output ./mocks --outpkg mocks --filename recovery_code_storage.go --structname RecoveryCodeStorage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// MethodRecoveryCode is the name of the recovery code method reported to clients.
+const MethodRecoveryCode = "recovery_code"
+
+const (
+	// RecoveryCodesCount is the number of codes in a set.
+	RecoveryCodesCount = 10
+
+	// recoveryCodeSize gives 80 bits of entropy per code, enough to store codes as plain SHA-256 hashes
+	// instead of a slow password hash, which would have to be computed for every code of the user.
+	recoveryCodeSize = 10
+
+	// recoveryCodeGroup is the length of dash-separated groups in formatted codes.
+	recoveryCodeGroup = 4
+)
+
+// NewRecoveryCodes creates a new *RecoveryCodes instance.
+func NewRecoveryCodes(storage RecoveryCodeStorage) *RecoveryCodes {
+	return &RecoveryCodes{
+		storage: storage,
+	}
+}
+
+type (
+	// RecoveryCodes manages single-use recovery codes, which replace the second factor when users lose their device.
+	// Only the hashes of the codes are stored.
+	RecoveryCodes struct {
+		storage RecoveryCodeStorage
+	}
+
+	// RecoveryCodeStorage describes RecoveryCodeStorage dependency.
+	RecoveryCodeStorage interface {
+		Replace(ctx context.Context, userID string, codeHashes []string) error
+		Use(ctx context.Context, userID string, codeHash string) (bool, error)
+	}
+)
+
+// Generate creates a new set of codes for the user, invalidating the previous one.
+// The codes are returned only once and cannot be recovered later.
+func (r *RecoveryCodes) Generate(ctx context.Context, userID string) ([]string, error) {
+	var (
+		codes  = make([]string, RecoveryCodesCount)
+		hashes = make([]string, RecoveryCodesCount)
+	)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+
+		codes[i] = code
+		hashes[i] = hashRecoveryCode(code)
+	}
+
+	if err := r.storage.Replace(ctx, userID, hashes); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+// Use consumes the recovery code of the user. Returns ErrInvalidCode if the code is unknown or was already used.
+func (r *RecoveryCodes) Use(ctx context.Context, userID string, code string) error {
+	ok, err := r.storage.Use(ctx, userID, hashRecoveryCode(code))
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return ErrInvalidCode
+	}
+
+	return nil
+}
+
+// generateRecoveryCode returns a random code formatted in groups for readability, e.g. ABCD-EFGH-IJKL-MNOP.
+func generateRecoveryCode() (string, error) {
+	buf := make([]byte, recoveryCodeSize)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(buf)
+
+	groups := make([]string, 0, len(encoded)/recoveryCodeGroup)
+	for i := 0; i < len(encoded); i += recoveryCodeGroup {
+		groups = append(groups, encoded[i:i+recoveryCodeGroup])
+	}
+
+	return strings.Join(groups, "-"), nil
+}
+
+// hashRecoveryCode returns the hash of the code, ignoring case, dashes and spaces the user may type.
+func hashRecoveryCode(code string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, strings.ToUpper(code))
+
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,26 @@
+package mfa
+
+import (
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+// DefRecoveryCodesName is the name of the *RecoveryCodes definition.
+const DefRecoveryCodesName = "mfa.recovery-codes"
+
+func init() {
+	di.Add(
+		di.Def[*RecoveryCodes]{
+			Name: DefRecoveryCodesName,
+			Build: func(ctn di.Container) (*RecoveryCodes, error) {
+				var storage *repository.RecoveryCodes
+				if err := ctn.Fill(repository.DefRecoveryCodesName, &storage); err != nil {
+					return nil, err
+				}
+
+				return NewRecoveryCodes(storage), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,96 @@
+package mfa_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+	"github.com/riabininkf/http-auth-example/internal/mfa/mocks"
+)
+
+func TestRecoveryCodes_Generate(t *testing.T) {
+	t.Run("failed to replace codes", func(t *testing.T) {
+		storage := mocks.NewRecoveryCodeStorage(t)
+		storage.On("Replace", t.Context(), "user_id", mock.Anything).Return(assert.AnError)
+
+		codes, err := mfa.NewRecoveryCodes(storage).Generate(t.Context(), "user_id")
+		assert.Nil(t, codes)
+		assert.Equal(t, assert.AnError, err)
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		var hashes []string
+
+		storage := mocks.NewRecoveryCodeStorage(t)
+		storage.On("Replace", t.Context(), "user_id", mock.Anything).
+			Run(func(args mock.Arguments) { hashes = args.Get(2).([]string) }).
+			Return(nil)
+
+		codes, err := mfa.NewRecoveryCodes(storage).Generate(t.Context(), "user_id")
+		assert.NoError(t, err)
+		assert.Len(t, codes, mfa.RecoveryCodesCount)
+
+		for i, code := range codes {
+			assert.Regexp(t, regexp.MustCompile(`^[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}-[A-Z2-7]{4}$`), code)
+			assert.Equal(t, recoveryCodeHash(code[0:4]+code[5:9]+code[10:14]+code[15:19]), hashes[i])
+		}
+	})
+}
+
+func TestRecoveryCodes_Use(t *testing.T) {
+	hash := recoveryCodeHash("ABCDEFGHIJKLMNOP")
+
+	testCases := map[string]struct {
+		code   string
+		onUse  func(storage *mocks.RecoveryCodeStorage)
+		expErr error
+	}{
+		"failed to use code": {
+			code: "ABCD-EFGH-IJKL-MNOP",
+			onUse: func(storage *mocks.RecoveryCodeStorage) {
+				storage.On("Use", t.Context(), "user_id", hash).Return(false, assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"unknown or used code": {
+			code: "ABCD-EFGH-IJKL-MNOP",
+			onUse: func(storage *mocks.RecoveryCodeStorage) {
+				storage.On("Use", t.Context(), "user_id", hash).Return(false, nil)
+			},
+			expErr: mfa.ErrInvalidCode,
+		},
+		"positive case": {
+			code: "ABCD-EFGH-IJKL-MNOP",
+			onUse: func(storage *mocks.RecoveryCodeStorage) {
+				storage.On("Use", t.Context(), "user_id", hash).Return(true, nil)
+			},
+		},
+		"code is normalized": {
+			code: "abcd efgh-ijkl mnop",
+			onUse: func(storage *mocks.RecoveryCodeStorage) {
+				storage.On("Use", t.Context(), "user_id", hash).Return(true, nil)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			storage := mocks.NewRecoveryCodeStorage(t)
+			tc.onUse(storage)
+
+			err := mfa.NewRecoveryCodes(storage).Use(t.Context(), "user_id", tc.code)
+			assert.Equal(t, tc.expErr, err)
+		})
+	}
+}
+
+// recoveryCodeHash returns the stored hash of a normalized recovery code.
+func recoveryCodeHash(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,215 @@
+package mfa
+
+//go:generate mockery --name TOTPStorage --output ./mocks --outpkg mocks --filename totp_storage.go --structname TOTPStorage
+//go:generate mockery --name Cipher --output ./mocks --outpkg mocks --filename cipher.go --structname Cipher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/totp"
+)
+
+// MethodTOTP is the name of the TOTP method reported to clients.
+const MethodTOTP = "totp"
+
+// skew is the number of time steps before and after the current one in which codes are still accepted.
+const skew = 1
+
+var (
+	// ErrInvalidCode is returned when the code does not match or was already used.
+	ErrInvalidCode = errors.New("invalid code")
+
+	// ErrNotEnrolled is returned when the user has not enrolled a TOTP secret or has not confirmed it yet.
+	ErrNotEnrolled = errors.New("totp is not enrolled")
+
+	// ErrAlreadyEnabled is returned on enrollment when TOTP is already enabled for the user.
+	ErrAlreadyEnabled = errors.New("totp is already enabled")
+)
+
+// NewTOTP creates a new *TOTP instance with the issuer shown in authenticator apps, a cipher for secrets and a storage.
+func NewTOTP(
+	issuer string,
+	cipher Cipher,
+	storage TOTPStorage,
+) *TOTP {
+	return &TOTP{
+		issuer:  issuer,
+		cipher:  cipher,
+		storage: storage,
+	}
+}
+
+type (
+	// TOTP manages TOTP (RFC 6238) credentials of users: enrollment, confirmation and verification of codes.
+	// Secrets are encrypted before they are stored.
+	TOTP struct {
+		issuer  string
+		cipher  Cipher
+		storage TOTPStorage
+	}
+
+	// Enrollment is a new TOTP secret to be added to an authenticator app.
+	Enrollment struct {
+		Secret string
+		URI    string
+	}
+
+	// TOTPStorage describes TOTPStorage dependency.
+	TOTPStorage interface {
+		Save(ctx context.Context, credential domain.TOTPCredential) error
+		GetByUserID(ctx context.Context, userID string) (domain.TOTPCredential, error)
+		Confirm(ctx context.Context, userID string, step int64) error
+		UseStep(ctx context.Context, userID string, step int64) (bool, error)
+	}
+
+	// Cipher describes Cipher dependency.
+	Cipher interface {
+		Encrypt(plaintext []byte, associatedData []byte) (string, error)
+		Decrypt(ciphertext string, associatedData []byte) ([]byte, error)
+	}
+)
+
+// Enroll generates a new secret for the user and stores it unconfirmed, replacing a previous unconfirmed one.
+// Returns ErrAlreadyEnabled if the user has already confirmed a secret.
+func (t *TOTP) Enroll(ctx context.Context, userID string, accountName string) (Enrollment, error) {
+	var (
+		err    error
+		secret []byte
+	)
+	if secret, err = totp.GenerateSecret(); err != nil {
+		return Enrollment{}, fmt.Errorf("failed to generate secret: %w", err)
+	}
+
+	var encrypted string
+	if encrypted, err = t.cipher.Encrypt(secret, []byte(userID)); err != nil {
+		return Enrollment{}, fmt.Errorf("failed to encrypt secret: %w", err)
+	}
+
+	if err = t.storage.Save(ctx, domain.TOTPCredential{
+		UserID:          userID,
+		EncryptedSecret: encrypted,
+	}); err != nil {
+		if errors.Is(err, domain.ErrTOTPAlreadyConfirmed) {
+			return Enrollment{}, ErrAlreadyEnabled
+		}
+
+		return Enrollment{}, err
+	}
+
+	return Enrollment{
+		Secret: totp.EncodeSecret(secret),
+		URI:    totp.URI(t.issuer, accountName, secret),
+	}, nil
+}
+
+// Confirm enables TOTP for the user once they submit a valid code for the enrolled secret.
+// Returns ErrNotEnrolled if there is no secret, ErrAlreadyEnabled if it is confirmed
+// and ErrInvalidCode if the code does not match.
+func (t *TOTP) Confirm(ctx context.Context, userID string, code string) error {
+	var (
+		err        error
+		credential domain.TOTPCredential
+	)
+	if credential, err = t.storage.GetByUserID(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrTOTPNotFound) {
+			return ErrNotEnrolled
+		}
+
+		return err
+	}
+
+	if credential.IsConfirmed() {
+		return ErrAlreadyEnabled
+	}
+
+	var step int64
+	if step, err = t.validate(credential, code); err != nil {
+		return err
+	}
+
+	if err = t.storage.Confirm(ctx, userID, step); err != nil {
+		if errors.Is(err, domain.ErrTOTPNotFound) {
+			// confirmed concurrently
+			return ErrAlreadyEnabled
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// IsEnabled reports whether the user has a confirmed TOTP secret.
+func (t *TOTP) IsEnabled(ctx context.Context, userID string) (bool, error) {
+	credential, err := t.storage.GetByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrTOTPNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return credential.IsConfirmed(), nil
+}
+
+// Verify checks the code against the user's confirmed secret. Each code is accepted only once: a code whose
+// time step is not later than the step of the last accepted code is rejected with ErrInvalidCode.
+// Returns ErrNotEnrolled if TOTP is not enabled for the user.
+func (t *TOTP) Verify(ctx context.Context, userID string, code string) error {
+	var (
+		err        error
+		credential domain.TOTPCredential
+	)
+	if credential, err = t.storage.GetByUserID(ctx, userID); err != nil {
+		if errors.Is(err, domain.ErrTOTPNotFound) {
+			return ErrNotEnrolled
+		}
+
+		return err
+	}
+
+	if !credential.IsConfirmed() {
+		return ErrNotEnrolled
+	}
+
+	var step int64
+	if step, err = t.validate(credential, code); err != nil {
+		return err
+	}
+
+	if step <= credential.LastUsedStep {
+		return ErrInvalidCode
+	}
+
+	var ok bool
+	if ok, err = t.storage.UseStep(ctx, userID, step); err != nil {
+		return err
+	}
+
+	if !ok {
+		// the code was used by a concurrent request
+		return ErrInvalidCode
+	}
+
+	return nil
+}
+
+// validate decrypts the secret and checks the code, returning its time step.
+func (t *TOTP) validate(credential domain.TOTPCredential, code string) (int64, error) {
+	secret, err := t.cipher.Decrypt(credential.EncryptedSecret, []byte(credential.UserID))
+	if err != nil {
+		return 0, fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+
+	step, ok := totp.Validate(secret, code, time.Now(), skew)
+	if !ok {
+		return 0, ErrInvalidCode
+	}
+
+	return step, nil
+}
@@ -0,0 +1,47 @@
+package mfa
+
+import (
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/encryption"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+const (
+	// DefTOTPName is the name of the *TOTP definition.
+	DefTOTPName = "mfa.totp"
+
+	configKeyIssuer = "auth.mfa.issuer"
+)
+
+func init() {
+	di.Add(
+		di.Def[*TOTP]{
+			Name: DefTOTPName,
+			Build: func(ctn di.Container) (*TOTP, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var issuer string
+				if issuer = cfg.GetString(configKeyIssuer); issuer == "" {
+					return nil, config.NewErrMissingKey(configKeyIssuer)
+				}
+
+				var cipher *encryption.Cipher
+				if err := ctn.Fill(encryption.DefCipherName, &cipher); err != nil {
+					return nil, err
+				}
+
+				var storage *repository.TOTPCredentials
+				if err := ctn.Fill(repository.DefTOTPCredentialsName, &storage); err != nil {
+					return nil, err
+				}
+
+				return NewTOTP(issuer, cipher, storage), nil
+			},
+		},
+	)
+}
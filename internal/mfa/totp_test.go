@@ -0,0 +1,361 @@
+package mfa_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/mfa"
+	"github.com/riabininkf/http-auth-example/internal/mfa/mocks"
+	"github.com/riabininkf/http-auth-example/internal/totp"
+)
+
+var totpSecret = []byte("12345678901234567890")
+
+func TestTOTP_Enroll(t *testing.T) {
+	testCases := map[string]struct {
+		onEncrypt func(cipher *mocks.Cipher)
+		onSave    func(storage *mocks.TOTPStorage)
+		expErr    error
+	}{
+		"failed to encrypt secret": {
+			onEncrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Encrypt", mock.Anything, []byte("user_id")).Return("", assert.AnError)
+			},
+			onSave: func(storage *mocks.TOTPStorage) {},
+			expErr: assert.AnError,
+		},
+		"already enabled": {
+			onEncrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Encrypt", mock.Anything, []byte("user_id")).Return("encrypted", nil)
+			},
+			onSave: func(storage *mocks.TOTPStorage) {
+				storage.On("Save", t.Context(), domain.TOTPCredential{UserID: "user_id", EncryptedSecret: "encrypted"}).
+					Return(domain.ErrTOTPAlreadyConfirmed)
+			},
+			expErr: mfa.ErrAlreadyEnabled,
+		},
+		"failed to save": {
+			onEncrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Encrypt", mock.Anything, []byte("user_id")).Return("encrypted", nil)
+			},
+			onSave: func(storage *mocks.TOTPStorage) {
+				storage.On("Save", t.Context(), domain.TOTPCredential{UserID: "user_id", EncryptedSecret: "encrypted"}).
+					Return(assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cipher := mocks.NewCipher(t)
+			tc.onEncrypt(cipher)
+
+			storage := mocks.NewTOTPStorage(t)
+			tc.onSave(storage)
+
+			enrollment, err := mfa.NewTOTP("issuer", cipher, storage).Enroll(t.Context(), "user_id", "user@example.com")
+			assert.Empty(t, enrollment)
+			assert.ErrorIs(t, err, tc.expErr)
+		})
+	}
+
+	t.Run("positive case", func(t *testing.T) {
+		var secret []byte
+
+		cipher := mocks.NewCipher(t)
+		cipher.On("Encrypt", mock.Anything, []byte("user_id")).
+			Run(func(args mock.Arguments) { secret = args.Get(0).([]byte) }).
+			Return("encrypted", nil)
+
+		storage := mocks.NewTOTPStorage(t)
+		storage.On("Save", t.Context(), domain.TOTPCredential{UserID: "user_id", EncryptedSecret: "encrypted"}).
+			Return(nil)
+
+		enrollment, err := mfa.NewTOTP("issuer", cipher, storage).Enroll(t.Context(), "user_id", "user@example.com")
+		assert.NoError(t, err)
+		assert.Len(t, secret, 20)
+		assert.Equal(t, totp.EncodeSecret(secret), enrollment.Secret)
+		assert.Equal(t, totp.URI("issuer", "user@example.com", secret), enrollment.URI)
+	})
+}
+
+func TestTOTP_Confirm(t *testing.T) {
+	unconfirmed := domain.TOTPCredential{UserID: "user_id", EncryptedSecret: "encrypted"}
+	step := totp.Step(time.Now())
+
+	testCases := map[string]struct {
+		code      string
+		onGet     func(storage *mocks.TOTPStorage)
+		onDecrypt func(cipher *mocks.Cipher)
+		onConfirm func(storage *mocks.TOTPStorage)
+		expErr    error
+	}{
+		"not enrolled": {
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(domain.TOTPCredential{}, domain.ErrTOTPNotFound)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {},
+			onConfirm: func(storage *mocks.TOTPStorage) {},
+			expErr:    mfa.ErrNotEnrolled,
+		},
+		"failed to get credential": {
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(domain.TOTPCredential{}, assert.AnError)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {},
+			onConfirm: func(storage *mocks.TOTPStorage) {},
+			expErr:    assert.AnError,
+		},
+		"already enabled": {
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").
+					Return(domain.TOTPCredential{UserID: "user_id", ConfirmedAt: time.Now()}, nil)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {},
+			onConfirm: func(storage *mocks.TOTPStorage) {},
+			expErr:    mfa.ErrAlreadyEnabled,
+		},
+		"failed to decrypt secret": {
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(unconfirmed, nil)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Decrypt", "encrypted", []byte("user_id")).Return(nil, assert.AnError)
+			},
+			onConfirm: func(storage *mocks.TOTPStorage) {},
+			expErr:    assert.AnError,
+		},
+		"invalid code": {
+			code: "invalid",
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(unconfirmed, nil)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Decrypt", "encrypted", []byte("user_id")).Return(totpSecret, nil)
+			},
+			onConfirm: func(storage *mocks.TOTPStorage) {},
+			expErr:    mfa.ErrInvalidCode,
+		},
+		"confirmed concurrently": {
+			code: totp.Code(totpSecret, step),
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(unconfirmed, nil)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Decrypt", "encrypted", []byte("user_id")).Return(totpSecret, nil)
+			},
+			onConfirm: func(storage *mocks.TOTPStorage) {
+				storage.On("Confirm", t.Context(), "user_id", step).Return(domain.ErrTOTPNotFound)
+			},
+			expErr: mfa.ErrAlreadyEnabled,
+		},
+		"failed to confirm": {
+			code: totp.Code(totpSecret, step),
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(unconfirmed, nil)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Decrypt", "encrypted", []byte("user_id")).Return(totpSecret, nil)
+			},
+			onConfirm: func(storage *mocks.TOTPStorage) {
+				storage.On("Confirm", t.Context(), "user_id", step).Return(assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"positive case": {
+			code: totp.Code(totpSecret, step),
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(unconfirmed, nil)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Decrypt", "encrypted", []byte("user_id")).Return(totpSecret, nil)
+			},
+			onConfirm: func(storage *mocks.TOTPStorage) {
+				storage.On("Confirm", t.Context(), "user_id", step).Return(nil)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			storage := mocks.NewTOTPStorage(t)
+			tc.onGet(storage)
+			tc.onConfirm(storage)
+
+			cipher := mocks.NewCipher(t)
+			tc.onDecrypt(cipher)
+
+			err := mfa.NewTOTP("issuer", cipher, storage).Confirm(t.Context(), "user_id", tc.code)
+			assert.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestTOTP_IsEnabled(t *testing.T) {
+	testCases := map[string]struct {
+		onGet  func(storage *mocks.TOTPStorage)
+		expOk  bool
+		expErr error
+	}{
+		"not enrolled": {
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(domain.TOTPCredential{}, domain.ErrTOTPNotFound)
+			},
+		},
+		"failed to get credential": {
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(domain.TOTPCredential{}, assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"not confirmed": {
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(domain.TOTPCredential{UserID: "user_id"}, nil)
+			},
+		},
+		"enabled": {
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").
+					Return(domain.TOTPCredential{UserID: "user_id", ConfirmedAt: time.Now()}, nil)
+			},
+			expOk: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			storage := mocks.NewTOTPStorage(t)
+			tc.onGet(storage)
+
+			ok, err := mfa.NewTOTP("issuer", mocks.NewCipher(t), storage).IsEnabled(t.Context(), "user_id")
+			assert.Equal(t, tc.expOk, ok)
+			assert.Equal(t, tc.expErr, err)
+		})
+	}
+}
+
+func TestTOTP_Verify(t *testing.T) {
+	step := totp.Step(time.Now())
+	confirmed := domain.TOTPCredential{
+		UserID:          "user_id",
+		EncryptedSecret: "encrypted",
+		ConfirmedAt:     time.Now(),
+		LastUsedStep:    step - 10,
+	}
+
+	testCases := map[string]struct {
+		code      string
+		onGet     func(storage *mocks.TOTPStorage)
+		onDecrypt func(cipher *mocks.Cipher)
+		onUseStep func(storage *mocks.TOTPStorage)
+		expErr    error
+	}{
+		"not enrolled": {
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(domain.TOTPCredential{}, domain.ErrTOTPNotFound)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {},
+			onUseStep: func(storage *mocks.TOTPStorage) {},
+			expErr:    mfa.ErrNotEnrolled,
+		},
+		"not confirmed": {
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(domain.TOTPCredential{UserID: "user_id"}, nil)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {},
+			onUseStep: func(storage *mocks.TOTPStorage) {},
+			expErr:    mfa.ErrNotEnrolled,
+		},
+		"failed to get credential": {
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(domain.TOTPCredential{}, assert.AnError)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {},
+			onUseStep: func(storage *mocks.TOTPStorage) {},
+			expErr:    assert.AnError,
+		},
+		"invalid code": {
+			code: strings.Repeat("0", totp.Digits+1),
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(confirmed, nil)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Decrypt", "encrypted", []byte("user_id")).Return(totpSecret, nil)
+			},
+			onUseStep: func(storage *mocks.TOTPStorage) {},
+			expErr:    mfa.ErrInvalidCode,
+		},
+		"code was already used": {
+			code: totp.Code(totpSecret, step),
+			onGet: func(storage *mocks.TOTPStorage) {
+				used := confirmed
+				used.LastUsedStep = step
+
+				storage.On("GetByUserID", t.Context(), "user_id").Return(used, nil)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Decrypt", "encrypted", []byte("user_id")).Return(totpSecret, nil)
+			},
+			onUseStep: func(storage *mocks.TOTPStorage) {},
+			expErr:    mfa.ErrInvalidCode,
+		},
+		"code was used concurrently": {
+			code: totp.Code(totpSecret, step),
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(confirmed, nil)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Decrypt", "encrypted", []byte("user_id")).Return(totpSecret, nil)
+			},
+			onUseStep: func(storage *mocks.TOTPStorage) {
+				storage.On("UseStep", t.Context(), "user_id", step).Return(false, nil)
+			},
+			expErr: mfa.ErrInvalidCode,
+		},
+		"failed to use step": {
+			code: totp.Code(totpSecret, step),
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(confirmed, nil)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Decrypt", "encrypted", []byte("user_id")).Return(totpSecret, nil)
+			},
+			onUseStep: func(storage *mocks.TOTPStorage) {
+				storage.On("UseStep", t.Context(), "user_id", step).Return(false, assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"positive case": {
+			code: totp.Code(totpSecret, step),
+			onGet: func(storage *mocks.TOTPStorage) {
+				storage.On("GetByUserID", t.Context(), "user_id").Return(confirmed, nil)
+			},
+			onDecrypt: func(cipher *mocks.Cipher) {
+				cipher.On("Decrypt", "encrypted", []byte("user_id")).Return(totpSecret, nil)
+			},
+			onUseStep: func(storage *mocks.TOTPStorage) {
+				storage.On("UseStep", t.Context(), "user_id", step).Return(true, nil)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			storage := mocks.NewTOTPStorage(t)
+			tc.onGet(storage)
+			tc.onUseStep(storage)
+
+			cipher := mocks.NewCipher(t)
+			tc.onDecrypt(cipher)
+
+			err := mfa.NewTOTP("issuer", cipher, storage).Verify(t.Context(), "user_id", tc.code)
+			assert.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
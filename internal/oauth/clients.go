@@ -0,0 +1,38 @@
+package oauth
+
+// NewClients creates a new *Clients instance from a map of client IDs to their registered redirect URIs.
+func NewClients(redirectURIs map[string][]string) *Clients {
+	clients := make(map[string]map[string]struct{}, len(redirectURIs))
+	for clientID, uris := range redirectURIs {
+		clients[clientID] = make(map[string]struct{}, len(uris))
+		for _, uri := range uris {
+			clients[clientID][uri] = struct{}{}
+		}
+	}
+
+	return &Clients{
+		clients: clients,
+	}
+}
+
+// Clients is a registry of OAuth clients and the redirect URIs registered for each of them.
+type Clients struct {
+	clients map[string]map[string]struct{}
+}
+
+// Exists reports whether the client is registered.
+func (c *Clients) Exists(clientID string) bool {
+	_, ok := c.clients[clientID]
+	return ok
+}
+
+// IsRedirectURIAllowed reports whether the client exists and the redirect URI exactly matches one of its registered URIs.
+func (c *Clients) IsRedirectURIAllowed(clientID string, redirectURI string) bool {
+	uris, ok := c.clients[clientID]
+	if !ok {
+		return false
+	}
+
+	_, ok = uris[redirectURI]
+	return ok
+}
@@ -0,0 +1,29 @@
+package oauth
+
+import (
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+)
+
+const (
+	// DefClientsName is the name of the *Clients definition.
+	DefClientsName = "oauth.clients"
+
+	configKeyClients = "auth.oauth.clients"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Clients]{
+			Name: DefClientsName,
+			Build: func(ctn di.Container) (*Clients, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				return NewClients(cfg.GetStringMapStringSlice(configKeyClients)), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,64 @@
+package oauth_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+func TestClients_IsRedirectURIAllowed(t *testing.T) {
+	clients := oauth.NewClients(map[string][]string{
+		"spa": {"https://app.example.com/callback", "http://localhost:3000/callback"},
+	})
+
+	testCases := []struct {
+		name        string
+		clientID    string
+		redirectURI string
+		expResult   bool
+	}{
+		{
+			name:        "unknown client",
+			clientID:    "unknown",
+			redirectURI: "https://app.example.com/callback",
+			expResult:   false,
+		},
+		{
+			name:        "unregistered redirect uri",
+			clientID:    "spa",
+			redirectURI: "https://evil.example.com/callback",
+			expResult:   false,
+		},
+		{
+			name:        "redirect uri prefix is not enough",
+			clientID:    "spa",
+			redirectURI: "https://app.example.com/callback/extra",
+			expResult:   false,
+		},
+		{
+			name:        "positive case",
+			clientID:    "spa",
+			redirectURI: "http://localhost:3000/callback",
+			expResult:   true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expResult, clients.IsRedirectURIAllowed(testCase.clientID, testCase.redirectURI))
+		})
+	}
+}
+
+func TestClients_Exists(t *testing.T) {
+	clients := oauth.NewClients(map[string][]string{
+		"spa": {"https://app.example.com/callback"},
+		"cli": {},
+	})
+
+	assert.True(t, clients.Exists("spa"))
+	assert.True(t, clients.Exists("cli"), "clients without redirect uris are used by the device flow")
+	assert.False(t, clients.Exists("unknown"))
+}
@@ -0,0 +1,109 @@
+package oauth
+
+//go:generate mockery --name Cache --output ./mocks --outpkg mocks --filename cache.go --structname Cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/random"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+// ErrInvalidCode is returned when the authorization code is unknown, expired or already redeemed.
+var ErrInvalidCode = errors.New("invalid authorization code")
+
+const (
+	codeKeyPrefix = "oauth:code:"
+	codeSize      = 32
+)
+
+// NewCodes creates a new *Codes instance with the provided authorization code TTL and cache implementation.
+func NewCodes(
+	ttl time.Duration,
+	cache Cache,
+) *Codes {
+	return &Codes{
+		ttl:   ttl,
+		cache: cache,
+	}
+}
+
+type (
+	// Codes issues short-lived single-use authorization codes and keeps their grants in the cache.
+	Codes struct {
+		ttl   time.Duration
+		cache Cache
+	}
+
+	// AuthorizationCode is the grant bound to an issued authorization code.
+	AuthorizationCode struct {
+		ClientID      string `json:"client_id"`
+		RedirectURI   string `json:"redirect_uri"`
+		UserID        string `json:"user_id"`
+		CodeChallenge string `json:"code_challenge"`
+	}
+
+	// Cache defines methods for storing values with a TTL, reading them, and atomically reading and removing them.
+	Cache interface {
+		Set(ctx context.Context, key string, value any, ttl time.Duration) error
+		Get(ctx context.Context, key string) (string, error)
+		GetDel(ctx context.Context, key string) (string, error)
+	}
+)
+
+// Issue generates a new authorization code for the given grant and stores the grant under the code's hash.
+func (c *Codes) Issue(ctx context.Context, grant AuthorizationCode) (string, error) {
+	var (
+		err  error
+		code string
+	)
+	if code, err = random.String(codeSize); err != nil {
+		return "", fmt.Errorf("failed to generate authorization code: %w", err)
+	}
+
+	var value []byte
+	if value, err = json.Marshal(grant); err != nil {
+		return "", fmt.Errorf("failed to marshal authorization code: %w", err)
+	}
+
+	if err = c.cache.Set(ctx, c.key(code), string(value), c.ttl); err != nil {
+		return "", err
+	}
+
+	return code, nil
+}
+
+// Redeem returns the grant bound to the code and removes it, so that every code can be redeemed only once.
+// Returns ErrInvalidCode if the code is unknown, expired or already redeemed.
+func (c *Codes) Redeem(ctx context.Context, code string) (AuthorizationCode, error) {
+	var (
+		err   error
+		value string
+	)
+	if value, err = c.cache.GetDel(ctx, c.key(code)); err != nil {
+		if errors.Is(err, redis.ErrNotFound) {
+			return AuthorizationCode{}, ErrInvalidCode
+		}
+
+		return AuthorizationCode{}, err
+	}
+
+	var grant AuthorizationCode
+	if err = json.Unmarshal([]byte(value), &grant); err != nil {
+		return AuthorizationCode{}, fmt.Errorf("failed to unmarshal authorization code: %w", err)
+	}
+
+	return grant, nil
+}
+
+// key returns the cache key for the code. Only the hash is stored, so a cache dump does not leak usable codes.
+func (c *Codes) key(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return codeKeyPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,43 @@
+package oauth
+
+import (
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+const (
+	// DefCodesName is the name of the *Codes definition.
+	DefCodesName = "oauth.codes"
+
+	configKeyAuthorizationCodeTTL = "auth.oauth.authorizationCodeTTL"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Codes]{
+			Name: DefCodesName,
+			Build: func(ctn di.Container) (*Codes, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var ttl time.Duration
+				if ttl = cfg.GetDuration(configKeyAuthorizationCodeTTL); ttl == 0 {
+					return nil, config.NewErrMissingKey(configKeyAuthorizationCodeTTL)
+				}
+
+				var cache *redis.Client
+				if err := ctn.Fill(redis.DefClientName, &cache); err != nil {
+					return nil, err
+				}
+
+				return NewCodes(ttl, cache), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,97 @@
+package oauth_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+	"github.com/riabininkf/http-auth-example/internal/oauth/mocks"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+func TestCodes_Issue(t *testing.T) {
+	grant := oauth.AuthorizationCode{
+		ClientID:      "client_id",
+		RedirectURI:   "https://app.example.com/callback",
+		UserID:        "user_id",
+		CodeChallenge: testCodeChallenge,
+	}
+
+	t.Run("failed to save into cache", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("Set", t.Context(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), time.Minute).
+			Return(assert.AnError)
+
+		code, err := oauth.NewCodes(time.Minute, cache).Issue(t.Context(), grant)
+		assert.Empty(t, code)
+		assert.Equal(t, assert.AnError, err)
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		var savedKey string
+
+		cache := mocks.NewCache(t)
+		cache.On("Set", t.Context(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), time.Minute).
+			Run(func(args mock.Arguments) { savedKey = args.String(1) }).
+			Return(nil)
+
+		code, err := oauth.NewCodes(time.Minute, cache).Issue(t.Context(), grant)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, code)
+		assert.Equal(t, hashCodeKey(code), savedKey, "the code must be stored hashed")
+	})
+}
+
+func TestCodes_Redeem(t *testing.T) {
+	t.Run("code not found", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("GetDel", t.Context(), hashCodeKey("code")).Return("", redis.ErrNotFound)
+
+		grant, err := oauth.NewCodes(time.Minute, cache).Redeem(t.Context(), "code")
+		assert.Empty(t, grant)
+		assert.Equal(t, oauth.ErrInvalidCode, err)
+	})
+
+	t.Run("failed to pop from cache", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("GetDel", t.Context(), hashCodeKey("code")).Return("", assert.AnError)
+
+		grant, err := oauth.NewCodes(time.Minute, cache).Redeem(t.Context(), "code")
+		assert.Empty(t, grant)
+		assert.Equal(t, assert.AnError, err)
+	})
+
+	t.Run("malformed value", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("GetDel", t.Context(), hashCodeKey("code")).Return("{", nil)
+
+		grant, err := oauth.NewCodes(time.Minute, cache).Redeem(t.Context(), "code")
+		assert.Empty(t, grant)
+		assert.Error(t, err)
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("GetDel", t.Context(), hashCodeKey("code")).
+			Return(`{"client_id":"client_id","redirect_uri":"https://app.example.com/callback","user_id":"user_id","code_challenge":"challenge"}`, nil)
+
+		grant, err := oauth.NewCodes(time.Minute, cache).Redeem(t.Context(), "code")
+		assert.NoError(t, err)
+		assert.Equal(t, oauth.AuthorizationCode{
+			ClientID:      "client_id",
+			RedirectURI:   "https://app.example.com/callback",
+			UserID:        "user_id",
+			CodeChallenge: "challenge",
+		}, grant)
+	})
+}
+
+func hashCodeKey(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return "oauth:code:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,340 @@
+package oauth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/random"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+// Errors returned while polling a device grant. They map one-to-one to the error codes of RFC 8628, section 3.5.
+var (
+	ErrAuthorizationPending = errors.New("authorization pending")
+	ErrSlowDown             = errors.New("slow down")
+	ErrAccessDenied         = errors.New("access denied")
+	ErrExpiredDeviceCode    = errors.New("device code is expired")
+)
+
+// ErrInvalidUserCode is returned when the user code is unknown, expired or already used.
+var ErrInvalidUserCode = errors.New("invalid user code")
+
+// Statuses of a device grant.
+const (
+	DeviceGrantPending  = "pending"
+	DeviceGrantApproved = "approved"
+	DeviceGrantDenied   = "denied"
+)
+
+const (
+	deviceKeyPrefix        = "oauth:device:"
+	devicePollingKeyPrefix = "oauth:device-polling:"
+	userCodeKeyPrefix      = "oauth:device-user-code:"
+	deviceCodeSize         = 32
+
+	// userCodeAlphabet contains no vowels and no characters that are easily confused, as suggested by RFC 8628.
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+
+	// slowDownStep is added to the polling interval each time a client polls too often.
+	slowDownStep = 5 * time.Second
+)
+
+// NewDevices creates a new *Devices instance with the provided grant TTL, minimal polling interval and cache.
+func NewDevices(
+	ttl time.Duration,
+	interval time.Duration,
+	cache Cache,
+) *Devices {
+	return &Devices{
+		ttl:      ttl,
+		interval: interval,
+		cache:    cache,
+	}
+}
+
+type (
+	// Devices keeps pending device authorization grants (RFC 8628) in the cache until they are approved,
+	// denied or expire.
+	Devices struct {
+		ttl      time.Duration
+		interval time.Duration
+		cache    Cache
+	}
+
+	// DeviceAuthorization is returned to the device when a new grant is started.
+	DeviceAuthorization struct {
+		DeviceCode string
+		UserCode   string
+		ExpiresIn  time.Duration
+		Interval   time.Duration
+	}
+
+	// DeviceGrant is the state of a device authorization grant.
+	DeviceGrant struct {
+		ClientID string `json:"client_id"`
+		Status   string `json:"status"`
+		UserID   string `json:"user_id,omitempty"`
+	}
+
+	// devicePolling is the polling state of a device grant. It is kept apart from the grant, so that
+	// saving it cannot overwrite a concurrent approval or denial.
+	devicePolling struct {
+		Interval     time.Duration `json:"interval"`
+		LastPolledAt time.Time     `json:"last_polled_at"`
+	}
+)
+
+// Start creates a new pending grant for the client and returns the device code to poll with
+// and the user code to enter on the verification page.
+func (d *Devices) Start(ctx context.Context, clientID string) (DeviceAuthorization, error) {
+	var (
+		err        error
+		deviceCode string
+	)
+	if deviceCode, err = random.String(deviceCodeSize); err != nil {
+		return DeviceAuthorization{}, fmt.Errorf("failed to generate device code: %w", err)
+	}
+
+	var userCode string
+	if userCode, err = generateUserCode(); err != nil {
+		return DeviceAuthorization{}, fmt.Errorf("failed to generate user code: %w", err)
+	}
+
+	grant := DeviceGrant{
+		ClientID: clientID,
+		Status:   DeviceGrantPending,
+	}
+
+	if err = d.save(ctx, d.deviceKey(deviceCode), grant, d.ttl); err != nil {
+		return DeviceAuthorization{}, err
+	}
+
+	if err = d.cache.Set(ctx, d.userCodeKey(userCode), d.deviceKey(deviceCode), d.ttl); err != nil {
+		return DeviceAuthorization{}, err
+	}
+
+	return DeviceAuthorization{
+		DeviceCode: deviceCode,
+		UserCode:   formatUserCode(userCode),
+		ExpiresIn:  d.ttl,
+		Interval:   d.interval,
+	}, nil
+}
+
+// Approve marks the grant identified by the user code as approved by the user.
+// Returns ErrInvalidUserCode if the user code is unknown, expired or already used.
+func (d *Devices) Approve(ctx context.Context, userCode string, userID string) error {
+	return d.resolve(ctx, userCode, DeviceGrantApproved, userID)
+}
+
+// Deny marks the grant identified by the user code as denied by the user.
+// Returns ErrInvalidUserCode if the user code is unknown, expired or already used.
+func (d *Devices) Deny(ctx context.Context, userCode string) error {
+	return d.resolve(ctx, userCode, DeviceGrantDenied, "")
+}
+
+// Poll returns the approved grant for the device code and removes it, so that tokens are issued only once.
+// While the grant is pending it returns ErrAuthorizationPending, or ErrSlowDown if the device polls faster than
+// the allowed interval. Unknown device codes are reported as ErrExpiredDeviceCode, since an expired grant
+// cannot be told apart from one that never existed.
+func (d *Devices) Poll(ctx context.Context, deviceCode string) (DeviceGrant, error) {
+	key := d.deviceKey(deviceCode)
+
+	var (
+		err   error
+		grant DeviceGrant
+	)
+	if grant, err = d.load(ctx, key); err != nil {
+		if errors.Is(err, redis.ErrNotFound) {
+			return DeviceGrant{}, ErrExpiredDeviceCode
+		}
+
+		return DeviceGrant{}, err
+	}
+
+	switch grant.Status {
+	case DeviceGrantApproved:
+		if _, err = d.cache.GetDel(ctx, key); err != nil {
+			if errors.Is(err, redis.ErrNotFound) {
+				// a concurrent poll has already redeemed the grant
+				return DeviceGrant{}, ErrExpiredDeviceCode
+			}
+
+			return DeviceGrant{}, err
+		}
+
+		return grant, nil
+	case DeviceGrantDenied:
+		if _, err = d.cache.GetDel(ctx, key); err != nil && !errors.Is(err, redis.ErrNotFound) {
+			return DeviceGrant{}, err
+		}
+
+		return DeviceGrant{}, ErrAccessDenied
+	}
+
+	return DeviceGrant{}, d.poll(ctx, deviceCode)
+}
+
+// poll records a poll of a pending grant and returns ErrSlowDown if it came sooner than the interval after
+// the previous one, raising the interval, or ErrAuthorizationPending otherwise.
+func (d *Devices) poll(ctx context.Context, deviceCode string) error {
+	key := d.pollingKey(deviceCode)
+
+	polling := devicePolling{Interval: d.interval}
+
+	value, err := d.cache.Get(ctx, key)
+	if err != nil && !errors.Is(err, redis.ErrNotFound) {
+		return err
+	}
+
+	if err == nil {
+		if err = json.Unmarshal([]byte(value), &polling); err != nil {
+			return fmt.Errorf("failed to unmarshal device polling: %w", err)
+		}
+	}
+
+	now := time.Now()
+	tooFast := now.Sub(polling.LastPolledAt) < polling.Interval
+	if tooFast {
+		polling.Interval += slowDownStep
+	}
+
+	polling.LastPolledAt = now
+
+	var encoded []byte
+	if encoded, err = json.Marshal(polling); err != nil {
+		return fmt.Errorf("failed to marshal device polling: %w", err)
+	}
+
+	// the polling state may outlive the grant a little, which is harmless as polls load the grant first
+	if err = d.cache.Set(ctx, key, string(encoded), d.ttl); err != nil {
+		return err
+	}
+
+	if tooFast {
+		return ErrSlowDown
+	}
+
+	return ErrAuthorizationPending
+}
+
+// resolve moves a pending grant into the given status. The user code is removed, so it can be used only once.
+func (d *Devices) resolve(ctx context.Context, userCode string, status string, userID string) error {
+	var (
+		err error
+		key string
+	)
+	if key, err = d.cache.GetDel(ctx, d.userCodeKey(userCode)); err != nil {
+		if errors.Is(err, redis.ErrNotFound) {
+			return ErrInvalidUserCode
+		}
+
+		return err
+	}
+
+	var grant DeviceGrant
+	if grant, err = d.load(ctx, key); err != nil {
+		if errors.Is(err, redis.ErrNotFound) {
+			return ErrInvalidUserCode
+		}
+
+		return err
+	}
+
+	if grant.Status != DeviceGrantPending {
+		return ErrInvalidUserCode
+	}
+
+	grant.Status = status
+	grant.UserID = userID
+
+	return d.save(ctx, key, grant, redis.KeepTTL)
+}
+
+// load reads the grant stored under the key.
+func (d *Devices) load(ctx context.Context, key string) (DeviceGrant, error) {
+	var (
+		err   error
+		value string
+	)
+	if value, err = d.cache.Get(ctx, key); err != nil {
+		return DeviceGrant{}, err
+	}
+
+	var grant DeviceGrant
+	if err = json.Unmarshal([]byte(value), &grant); err != nil {
+		return DeviceGrant{}, fmt.Errorf("failed to unmarshal device grant: %w", err)
+	}
+
+	return grant, nil
+}
+
+// save writes the grant under the key with the given TTL.
+func (d *Devices) save(ctx context.Context, key string, grant DeviceGrant, ttl time.Duration) error {
+	value, err := json.Marshal(grant)
+	if err != nil {
+		return fmt.Errorf("failed to marshal device grant: %w", err)
+	}
+
+	return d.cache.Set(ctx, key, string(value), ttl)
+}
+
+// deviceKey returns the cache key for the device code. Only the hash is stored.
+func (d *Devices) deviceKey(deviceCode string) string {
+	sum := sha256.Sum256([]byte(deviceCode))
+	return deviceKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// pollingKey returns the cache key for the polling state of the device code. Only the hash is stored.
+func (d *Devices) pollingKey(deviceCode string) string {
+	sum := sha256.Sum256([]byte(deviceCode))
+	return devicePollingKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// userCodeKey returns the cache key for the user code, ignoring case, dashes and spaces the user may type.
+func (d *Devices) userCodeKey(userCode string) string {
+	return userCodeKeyPrefix + normalizeUserCode(userCode)
+}
+
+// generateUserCode returns a random user code without formatting.
+func generateUserCode() (string, error) {
+	var (
+		code = make([]byte, userCodeLength)
+		max  = big.NewInt(int64(len(userCodeAlphabet)))
+	)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+
+		code[i] = userCodeAlphabet[n.Int64()]
+	}
+
+	return string(code), nil
+}
+
+// formatUserCode splits the user code into two halves for readability, e.g. WDJB-MJHT.
+func formatUserCode(userCode string) string {
+	return userCode[:userCodeLength/2] + "-" + userCode[userCodeLength/2:]
+}
+
+// normalizeUserCode upper-cases the user code and strips separators.
+func normalizeUserCode(userCode string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+
+		return r
+	}, strings.ToUpper(userCode))
+}
@@ -0,0 +1,49 @@
+package oauth
+
+import (
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+const (
+	// DefDevicesName is the name of the *Devices definition.
+	DefDevicesName = "oauth.devices"
+
+	configKeyDeviceCodeTTL         = "auth.oauth.deviceCodeTTL"
+	configKeyDevicePollingInterval = "auth.oauth.devicePollingInterval"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Devices]{
+			Name: DefDevicesName,
+			Build: func(ctn di.Container) (*Devices, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var ttl time.Duration
+				if ttl = cfg.GetDuration(configKeyDeviceCodeTTL); ttl == 0 {
+					return nil, config.NewErrMissingKey(configKeyDeviceCodeTTL)
+				}
+
+				var interval time.Duration
+				if interval = cfg.GetDuration(configKeyDevicePollingInterval); interval == 0 {
+					return nil, config.NewErrMissingKey(configKeyDevicePollingInterval)
+				}
+
+				var cache *redis.Client
+				if err := ctn.Fill(redis.DefClientName, &cache); err != nil {
+					return nil, err
+				}
+
+				return NewDevices(ttl, interval, cache), nil
+			},
+		},
+	)
+}
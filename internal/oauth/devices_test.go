@@ -0,0 +1,308 @@
+package oauth_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+	"github.com/riabininkf/http-auth-example/internal/oauth/mocks"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+func TestDevices_Start(t *testing.T) {
+	t.Run("failed to save grant", func(t *testing.T) {
+		cache := mocks.NewCache(t)
+		cache.On("Set", t.Context(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), time.Minute).
+			Return(assert.AnError)
+
+		auth, err := oauth.NewDevices(time.Minute, 5*time.Second, cache).Start(t.Context(), "client_id")
+		assert.Empty(t, auth)
+		assert.Equal(t, assert.AnError, err)
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		saved := make(map[string]string)
+
+		cache := mocks.NewCache(t)
+		cache.On("Set", t.Context(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), time.Minute).
+			Run(func(args mock.Arguments) { saved[args.String(1)] = args.String(2) }).
+			Return(nil).
+			Twice()
+
+		auth, err := oauth.NewDevices(time.Minute, 5*time.Second, cache).Start(t.Context(), "client_id")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, auth.DeviceCode)
+		assert.Regexp(t, regexp.MustCompile(`^[BCDFGHJKLMNPQRSTVWXZ]{4}-[BCDFGHJKLMNPQRSTVWXZ]{4}$`), auth.UserCode)
+		assert.Equal(t, time.Minute, auth.ExpiresIn)
+		assert.Equal(t, 5*time.Second, auth.Interval)
+
+		userCodeKey := "oauth:device-user-code:" + auth.UserCode[:4] + auth.UserCode[5:]
+		assert.Equal(t, deviceKey(auth.DeviceCode), saved[userCodeKey])
+
+		var grant oauth.DeviceGrant
+		if err = json.Unmarshal([]byte(saved[deviceKey(auth.DeviceCode)]), &grant); err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Equal(t, oauth.DeviceGrant{
+			ClientID: "client_id",
+			Status:   oauth.DeviceGrantPending,
+		}, grant)
+	})
+}
+
+func TestDevices_Approve(t *testing.T) {
+	const userCodeKey = "oauth:device-user-code:WDJBMJHT"
+
+	pending := marshalDeviceGrant(t, oauth.DeviceGrant{ClientID: "client_id", Status: oauth.DeviceGrantPending})
+
+	testCases := map[string]struct {
+		onGetDel func(cache *mocks.Cache)
+		onGet    func(cache *mocks.Cache)
+		onSet    func(cache *mocks.Cache)
+		expErr   error
+	}{
+		"unknown user code": {
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), userCodeKey).Return("", redis.ErrNotFound)
+			},
+			expErr: oauth.ErrInvalidUserCode,
+		},
+		"failed to pop user code": {
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), userCodeKey).Return("", assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"grant expired": {
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), userCodeKey).Return("device_key", nil)
+			},
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), "device_key").Return("", redis.ErrNotFound)
+			},
+			expErr: oauth.ErrInvalidUserCode,
+		},
+		"grant already resolved": {
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), userCodeKey).Return("device_key", nil)
+			},
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), "device_key").
+					Return(marshalDeviceGrant(t, oauth.DeviceGrant{Status: oauth.DeviceGrantDenied}), nil)
+			},
+			expErr: oauth.ErrInvalidUserCode,
+		},
+		"positive case": {
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), userCodeKey).Return("device_key", nil)
+			},
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), "device_key").Return(pending, nil)
+			},
+			onSet: func(cache *mocks.Cache) {
+				cache.On("Set", t.Context(), "device_key", marshalDeviceGrant(t, oauth.DeviceGrant{
+					ClientID: "client_id",
+					Status:   oauth.DeviceGrantApproved,
+					UserID:   "user_id",
+				}), redis.KeepTTL).Return(nil)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewCache(t)
+
+			if tc.onGetDel != nil {
+				tc.onGetDel(cache)
+			}
+
+			if tc.onGet != nil {
+				tc.onGet(cache)
+			}
+
+			if tc.onSet != nil {
+				tc.onSet(cache)
+			}
+
+			// the user code is matched regardless of case and separators
+			err := oauth.NewDevices(time.Minute, 5*time.Second, cache).Approve(t.Context(), "wdjb-mjht", "user_id")
+			assert.Equal(t, tc.expErr, err)
+		})
+	}
+}
+
+func TestDevices_Poll(t *testing.T) {
+	key := deviceKey("device_code")
+	pollingKey := "oauth:device-polling:" + strings.TrimPrefix(key, "oauth:device:")
+
+	// polls of a pending grant only save the polling state, so that they cannot overwrite a concurrent approval
+	pending := marshalDeviceGrant(t, oauth.DeviceGrant{ClientID: "client_id", Status: oauth.DeviceGrantPending})
+
+	testCases := map[string]struct {
+		onGet    func(cache *mocks.Cache)
+		onGetDel func(cache *mocks.Cache)
+		onSet    func(cache *mocks.Cache)
+		expGrant oauth.DeviceGrant
+		expErr   error
+	}{
+		"unknown device code": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return("", redis.ErrNotFound)
+			},
+			expErr: oauth.ErrExpiredDeviceCode,
+		},
+		"failed to get grant": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return("", assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"first poll": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return(pending, nil)
+				cache.On("Get", t.Context(), pollingKey).Return("", redis.ErrNotFound)
+			},
+			onSet: func(cache *mocks.Cache) {
+				cache.On("Set", t.Context(), pollingKey, pollingWithInterval(5*time.Second), time.Minute).Return(nil)
+			},
+			expErr: oauth.ErrAuthorizationPending,
+		},
+		"authorization pending": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return(pending, nil)
+				cache.On("Get", t.Context(), pollingKey).
+					Return(fmt.Sprintf(`{"interval":%d,"last_polled_at":"%s"}`,
+						5*time.Second, time.Now().Add(-time.Minute).Format(time.RFC3339Nano)), nil)
+			},
+			onSet: func(cache *mocks.Cache) {
+				cache.On("Set", t.Context(), pollingKey, pollingWithInterval(5*time.Second), time.Minute).Return(nil)
+			},
+			expErr: oauth.ErrAuthorizationPending,
+		},
+		"slow down": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return(pending, nil)
+				cache.On("Get", t.Context(), pollingKey).
+					Return(fmt.Sprintf(`{"interval":%d,"last_polled_at":"%s"}`,
+						5*time.Second, time.Now().Format(time.RFC3339Nano)), nil)
+			},
+			onSet: func(cache *mocks.Cache) {
+				cache.On("Set", t.Context(), pollingKey, pollingWithInterval(10*time.Second), time.Minute).Return(nil)
+			},
+			expErr: oauth.ErrSlowDown,
+		},
+		"failed to get polling state": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return(pending, nil)
+				cache.On("Get", t.Context(), pollingKey).Return("", assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"failed to save polling state": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return(pending, nil)
+				cache.On("Get", t.Context(), pollingKey).Return("", redis.ErrNotFound)
+			},
+			onSet: func(cache *mocks.Cache) {
+				cache.On("Set", t.Context(), pollingKey, pollingWithInterval(5*time.Second), time.Minute).
+					Return(assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"access denied": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).
+					Return(marshalDeviceGrant(t, oauth.DeviceGrant{Status: oauth.DeviceGrantDenied}), nil)
+			},
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), key).Return("", nil)
+			},
+			expErr: oauth.ErrAccessDenied,
+		},
+		"approved grant redeemed concurrently": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).
+					Return(marshalDeviceGrant(t, oauth.DeviceGrant{Status: oauth.DeviceGrantApproved}), nil)
+			},
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), key).Return("", redis.ErrNotFound)
+			},
+			expErr: oauth.ErrExpiredDeviceCode,
+		},
+		"positive case": {
+			onGet: func(cache *mocks.Cache) {
+				cache.On("Get", t.Context(), key).Return(marshalDeviceGrant(t, oauth.DeviceGrant{
+					ClientID: "client_id",
+					Status:   oauth.DeviceGrantApproved,
+					UserID:   "user_id",
+				}), nil)
+			},
+			onGetDel: func(cache *mocks.Cache) {
+				cache.On("GetDel", t.Context(), key).Return("", nil)
+			},
+			expGrant: oauth.DeviceGrant{
+				ClientID: "client_id",
+				Status:   oauth.DeviceGrantApproved,
+				UserID:   "user_id",
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			cache := mocks.NewCache(t)
+
+			if tc.onGet != nil {
+				tc.onGet(cache)
+			}
+
+			if tc.onGetDel != nil {
+				tc.onGetDel(cache)
+			}
+
+			if tc.onSet != nil {
+				tc.onSet(cache)
+			}
+
+			grant, err := oauth.NewDevices(time.Minute, 5*time.Second, cache).Poll(t.Context(), "device_code")
+			assert.Equal(t, tc.expGrant, grant)
+			assert.Equal(t, tc.expErr, err)
+		})
+	}
+}
+
+func deviceKey(deviceCode string) string {
+	sum := sha256.Sum256([]byte(deviceCode))
+	return "oauth:device:" + hex.EncodeToString(sum[:])
+}
+
+// pollingWithInterval matches a saved polling state with the given interval.
+func pollingWithInterval(interval time.Duration) any {
+	return mock.MatchedBy(func(value string) bool {
+		var polling struct {
+			Interval time.Duration `json:"interval"`
+		}
+
+		return json.Unmarshal([]byte(value), &polling) == nil && polling.Interval == interval
+	})
+}
+
+func marshalDeviceGrant(t *testing.T, grant oauth.DeviceGrant) string {
+	value, err := json.Marshal(grant)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(value)
+}
@@ -0,0 +1,61 @@
+package oauth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+)
+
+// CodeChallengeMethodS256 is the only PKCE code challenge method accepted by the service.
+const CodeChallengeMethodS256 = "S256"
+
+var (
+	// ErrInvalidCodeChallenge is returned when the PKCE code challenge or its method is missing or malformed.
+	ErrInvalidCodeChallenge = errors.New("invalid code challenge")
+
+	// ErrInvalidCodeVerifier is returned when the PKCE code verifier is malformed or does not match the challenge.
+	ErrInvalidCodeVerifier = errors.New("invalid code verifier")
+)
+
+// ValidateCodeChallenge checks that the code challenge is a base64url-encoded SHA-256 digest sent with the S256 method.
+// The plain method is rejected as recommended by RFC 9700.
+func ValidateCodeChallenge(challenge string, method string) error {
+	if method != CodeChallengeMethodS256 {
+		return ErrInvalidCodeChallenge
+	}
+
+	if decoded, err := base64.RawURLEncoding.DecodeString(challenge); err != nil || len(decoded) != sha256.Size {
+		return ErrInvalidCodeChallenge
+	}
+
+	return nil
+}
+
+// VerifyCodeVerifier checks that the code verifier conforms to RFC 7636 and hashes to the given S256 challenge.
+func VerifyCodeVerifier(verifier string, challenge string) error {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return ErrInvalidCodeVerifier
+	}
+
+	for _, c := range verifier {
+		if !isUnreserved(c) {
+			return ErrInvalidCodeVerifier
+		}
+	}
+
+	sum := sha256.Sum256([]byte(verifier))
+	if subtle.ConstantTimeCompare([]byte(base64.RawURLEncoding.EncodeToString(sum[:])), []byte(challenge)) != 1 {
+		return ErrInvalidCodeVerifier
+	}
+
+	return nil
+}
+
+// isUnreserved reports whether c belongs to the unreserved character set allowed in code verifiers.
+func isUnreserved(c rune) bool {
+	return c >= 'a' && c <= 'z' ||
+		c >= 'A' && c <= 'Z' ||
+		c >= '0' && c <= '9' ||
+		c == '-' || c == '.' || c == '_' || c == '~'
+}
@@ -0,0 +1,98 @@
+package oauth_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/oauth"
+)
+
+// testCodeChallenge is BASE64URL(SHA256(testCodeVerifier)).
+const (
+	testCodeVerifier  = "dBjftJeZ4CVP-mJ92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	testCodeChallenge = "ngF5GsXcbwljx6u133FFr3Xht9xooA_DuaX_3QwODtc"
+)
+
+func TestValidateCodeChallenge(t *testing.T) {
+	testCases := []struct {
+		name      string
+		challenge string
+		method    string
+		expError  error
+	}{
+		{
+			name:      "method is missing",
+			challenge: testCodeChallenge,
+			expError:  oauth.ErrInvalidCodeChallenge,
+		},
+		{
+			name:      "plain method",
+			challenge: testCodeChallenge,
+			method:    "plain",
+			expError:  oauth.ErrInvalidCodeChallenge,
+		},
+		{
+			name:     "challenge is missing",
+			method:   oauth.CodeChallengeMethodS256,
+			expError: oauth.ErrInvalidCodeChallenge,
+		},
+		{
+			name:      "challenge is not a sha-256 digest",
+			challenge: "c2hvcnQ",
+			method:    oauth.CodeChallengeMethodS256,
+			expError:  oauth.ErrInvalidCodeChallenge,
+		},
+		{
+			name:      "positive case",
+			challenge: testCodeChallenge,
+			method:    oauth.CodeChallengeMethodS256,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expError, oauth.ValidateCodeChallenge(testCase.challenge, testCase.method))
+		})
+	}
+}
+
+func TestVerifyCodeVerifier(t *testing.T) {
+	testCases := []struct {
+		name     string
+		verifier string
+		expError error
+	}{
+		{
+			name:     "verifier is too short",
+			verifier: testCodeVerifier[:42],
+			expError: oauth.ErrInvalidCodeVerifier,
+		},
+		{
+			name:     "verifier is too long",
+			verifier: strings.Repeat("a", 129),
+			expError: oauth.ErrInvalidCodeVerifier,
+		},
+		{
+			name:     "verifier contains reserved characters",
+			verifier: testCodeVerifier[:42] + "+",
+			expError: oauth.ErrInvalidCodeVerifier,
+		},
+		{
+			name:     "verifier does not match",
+			verifier: strings.Repeat("a", 43),
+			expError: oauth.ErrInvalidCodeVerifier,
+		},
+		{
+			name:     "positive case",
+			verifier: testCodeVerifier,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expError, oauth.VerifyCodeVerifier(testCase.verifier, testCodeChallenge))
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	argon2idPrefix = "$argon2id$"
+
+	argon2idSaltLength = 16
+	argon2idKeyLength  = 32
+
+	// maxStoredArgon2idMemory caps the memory cost of stored argon2id hashes in KiB, so that a corrupted
+	// or imported hash cannot make a single verification allocate gigabytes.
+	maxStoredArgon2idMemory = 256 * 1024
+)
+
+// ErrInvalidHash is returned for encoded hashes that are recognized but cannot be parsed.
+var ErrInvalidHash = errors.New("invalid password hash")
+
+// NewArgon2id creates a new *Argon2id instance. Memory is in KiB.
+func NewArgon2id(memory uint32, iterations uint32, parallelism uint8) *Argon2id {
+	return &Argon2id{
+		memory:      memory,
+		iterations:  iterations,
+		parallelism: parallelism,
+	}
+}
+
+type (
+	// Argon2id hashes passwords with argon2id, the memory-hard algorithm recommended by RFC 9106.
+	Argon2id struct {
+		memory      uint32
+		iterations  uint32
+		parallelism uint8
+	}
+
+	// argon2idHash is a decoded argon2id hash.
+	argon2idHash struct {
+		version     int
+		memory      uint32
+		iterations  uint32
+		parallelism uint8
+		salt        []byte
+		key         []byte
+	}
+)
+
+// Identify reports whether the encoded hash is an argon2id hash.
+func (a *Argon2id) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, argon2idPrefix)
+}
+
+// Hash returns the argon2id hash of the password with a random salt in the PHC string format.
+func (a *Argon2id) Hash(password string) (string, error) {
+	salt := make([]byte, argon2idSaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	key := argon2.IDKey([]byte(password), salt, a.iterations, a.memory, a.parallelism, argon2idKeyLength)
+
+	return fmt.Sprintf(
+		"%sv=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2idPrefix,
+		argon2.Version,
+		a.memory,
+		a.iterations,
+		a.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Check returns ErrInvalidHash if the argon2id hash cannot be decoded or its cost is too high.
+func (a *Argon2id) Check(encoded string) error {
+	_, err := decodeArgon2id(encoded)
+	return err
+}
+
+// Verify reports whether the password matches the argon2id hash. The hash is computed with the parameters
+// stored in it, so hashes with outdated parameters can still be verified.
+func (a *Argon2id) Verify(password string, encoded string) (bool, error) {
+	hash, err := decodeArgon2id(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	key := argon2.IDKey([]byte(password), hash.salt, hash.iterations, hash.memory, hash.parallelism, uint32(len(hash.key)))
+
+	return subtle.ConstantTimeCompare(key, hash.key) == 1, nil
+}
+
+// NeedsRehash reports whether the argon2id hash uses less memory, fewer iterations or a shorter salt or key
+// than a. Stronger hashes are kept, so that instances calibrated to different parameters do not keep rehashing
+// each other's hashes. Parallelism changes how the work is spread rather than how much there is, so it is ignored.
+func (a *Argon2id) NeedsRehash(encoded string) bool {
+	hash, err := decodeArgon2id(encoded)
+	if err != nil {
+		return true
+	}
+
+	return hash.version != argon2.Version ||
+		hash.memory < a.memory ||
+		hash.iterations < a.iterations ||
+		len(hash.salt) < argon2idSaltLength ||
+		len(hash.key) < argon2idKeyLength
+}
+
+// decodeArgon2id parses an argon2id hash in the PHC string format.
+func decodeArgon2id(encoded string) (argon2idHash, error) {
+	var hash argon2idHash
+
+	// "", "argon2id", "v=19", "m=19456,t=2,p=1", salt, key
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return hash, fmt.Errorf("%w: malformed argon2id hash", ErrInvalidHash)
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &hash.version); err != nil {
+		return hash, fmt.Errorf("%w: invalid argon2id version: %w", ErrInvalidHash, err)
+	}
+
+	if hash.version != argon2.Version {
+		return hash, fmt.Errorf("%w: unsupported argon2id version %d", ErrInvalidHash, hash.version)
+	}
+
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &hash.memory, &hash.iterations, &hash.parallelism); err != nil {
+		return hash, fmt.Errorf("%w: invalid argon2id parameters: %w", ErrInvalidHash, err)
+	}
+
+	if hash.memory == 0 || hash.iterations == 0 || hash.parallelism == 0 {
+		return hash, fmt.Errorf("%w: invalid argon2id parameters", ErrInvalidHash)
+	}
+
+	if hash.memory > maxStoredArgon2idMemory || hash.iterations > maxArgon2idIterations {
+		return hash, fmt.Errorf("%w: argon2id cost m=%d,t=%d is too high", ErrInvalidHash, hash.memory, hash.iterations)
+	}
+
+	var err error
+	if hash.salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return hash, fmt.Errorf("%w: invalid argon2id salt: %w", ErrInvalidHash, err)
+	}
+
+	if hash.key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(hash.key) == 0 {
+		return hash, fmt.Errorf("%w: invalid argon2id key", ErrInvalidHash)
+	}
+
+	return hash, nil
+}
@@ -0,0 +1,145 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+// argon2idVector is the argon2id hash of "password" with the salt "somesalt", generated by the reference
+// implementation.
+const argon2idVector = "$argon2id$v=19$m=64,t=2,p=2$c29tZXNhbHQ$NQrDciL0Nsy1wJcvHr079rlYvyBxhBNi"
+
+func TestArgon2id_Hash(t *testing.T) {
+	argon2id := password.NewArgon2id(64, 1, 1)
+
+	encoded, err := argon2id.Hash("password")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.True(t, strings.HasPrefix(encoded, "$argon2id$v=19$m=64,t=1,p=1$"), encoded)
+	assert.True(t, argon2id.Identify(encoded))
+	assert.False(t, argon2id.NeedsRehash(encoded))
+
+	another, err := argon2id.Hash("password")
+	assert.NoError(t, err)
+	assert.NotEqual(t, encoded, another, "salt must be random")
+
+	ok, err := argon2id.Verify("password", encoded)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = argon2id.Verify("Password", encoded)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestArgon2id_Verify(t *testing.T) {
+	testCases := map[string]struct {
+		password string
+		encoded  string
+		expOK    bool
+		expErr   error
+	}{
+		"known answer": {
+			password: "password",
+			encoded:  argon2idVector,
+			expOK:    true,
+		},
+		"wrong password": {
+			password: "passwore",
+			encoded:  argon2idVector,
+		},
+		"malformed hash": {
+			password: "password",
+			encoded:  "$argon2id$v=19$m=64,t=2,p=2$c29tZXNhbHQ",
+			expErr:   password.ErrInvalidHash,
+		},
+		"unsupported version": {
+			password: "password",
+			encoded:  "$argon2id$v=16$m=64,t=2,p=2$c29tZXNhbHQ$NQrDciL0Nsy1wJcvHr079rlYvyBxhBNi",
+			expErr:   password.ErrInvalidHash,
+		},
+		"invalid parameters": {
+			password: "password",
+			encoded:  "$argon2id$v=19$m=0,t=2,p=2$c29tZXNhbHQ$NQrDciL0Nsy1wJcvHr079rlYvyBxhBNi",
+			expErr:   password.ErrInvalidHash,
+		},
+		"memory cost is too high": {
+			password: "password",
+			encoded:  "$argon2id$v=19$m=4194304,t=2,p=2$c29tZXNhbHQ$NQrDciL0Nsy1wJcvHr079rlYvyBxhBNi",
+			expErr:   password.ErrInvalidHash,
+		},
+		"iterations are too high": {
+			password: "password",
+			encoded:  "$argon2id$v=19$m=64,t=1000,p=2$c29tZXNhbHQ$NQrDciL0Nsy1wJcvHr079rlYvyBxhBNi",
+			expErr:   password.ErrInvalidHash,
+		},
+		"invalid salt": {
+			password: "password",
+			encoded:  "$argon2id$v=19$m=64,t=2,p=2$!!!$NQrDciL0Nsy1wJcvHr079rlYvyBxhBNi",
+			expErr:   password.ErrInvalidHash,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			argon2id := password.NewArgon2id(64, 1, 1)
+			assert.ErrorIs(t, argon2id.Check(tc.encoded), tc.expErr)
+
+			ok, err := argon2id.Verify(tc.password, tc.encoded)
+			assert.Equal(t, tc.expOK, ok)
+			assert.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
+
+func TestArgon2id_NeedsRehash(t *testing.T) {
+	salt, key := "c29tZXNhbHRzb21lc2FsdA", strings.Repeat("A", 43)
+
+	testCases := map[string]struct {
+		encoded   string
+		expRehash bool
+	}{
+		"same parameters": {
+			encoded: "$argon2id$v=19$m=64,t=2,p=2$" + salt + "$" + key,
+		},
+		"more memory": {
+			encoded: "$argon2id$v=19$m=128,t=2,p=2$" + salt + "$" + key,
+		},
+		"more iterations": {
+			encoded: "$argon2id$v=19$m=64,t=3,p=2$" + salt + "$" + key,
+		},
+		"other parallelism": {
+			encoded: "$argon2id$v=19$m=64,t=2,p=1$" + salt + "$" + key,
+		},
+		"less memory": {
+			encoded:   "$argon2id$v=19$m=32,t=2,p=2$" + salt + "$" + key,
+			expRehash: true,
+		},
+		"fewer iterations": {
+			encoded:   "$argon2id$v=19$m=64,t=1,p=2$" + salt + "$" + key,
+			expRehash: true,
+		},
+		"key is shorter than 32 bytes": {
+			encoded:   argon2idVector,
+			expRehash: true,
+		},
+		"malformed hash": {
+			encoded:   "$argon2id$broken",
+			expRehash: true,
+		},
+	}
+
+	argon2id := password.NewArgon2id(64, 2, 2)
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expRehash, argon2id.NeedsRehash(tc.encoded))
+		})
+	}
+}
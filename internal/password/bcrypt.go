@@ -0,0 +1,101 @@
+package password
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// bcryptHashLength is the length of an encoded bcrypt hash: "$2b$", the cost, "$" and 53 characters
+	// holding the 16 byte salt and the 23 byte hash.
+	bcryptHashLength = 60
+	// bcryptHeaderLength is the length of the version and the cost, e.g. "$2b$10$".
+	bcryptHeaderLength = 7
+	// bcryptSaltLength is the number of characters encoding the salt, right after the header.
+	bcryptSaltLength = 22
+)
+
+var (
+	// bcryptPrefixes are the versions of the modular crypt format bcrypt hashes are encoded in.
+	bcryptPrefixes = []string{"$2a$", "$2b$", "$2y$"}
+
+	// bcryptBase64 is the base64 variant of bcrypt, with its own alphabet and no padding.
+	bcryptBase64 = base64.NewEncoding("./ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789").
+			WithPadding(base64.NoPadding)
+)
+
+// NewBcrypt creates a new *Bcrypt instance.
+func NewBcrypt(cost int) *Bcrypt {
+	return &Bcrypt{cost: cost}
+}
+
+// Bcrypt hashes passwords with bcrypt. Passwords longer than MaxBytes cannot be hashed.
+type Bcrypt struct {
+	cost int
+}
+
+// Identify reports whether the encoded hash is a bcrypt hash.
+func (b *Bcrypt) Identify(encoded string) bool {
+	for _, prefix := range bcryptPrefixes {
+		if strings.HasPrefix(encoded, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Hash returns the bcrypt hash of the password with a random salt.
+func (b *Bcrypt) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), b.cost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// Check returns ErrInvalidHash if the bcrypt hash cannot be decoded.
+func (b *Bcrypt) Check(encoded string) error {
+	if !b.Identify(encoded) || len(encoded) != bcryptHashLength {
+		return fmt.Errorf("%w: malformed bcrypt hash", ErrInvalidHash)
+	}
+
+	if _, err := bcrypt.Cost([]byte(encoded)); err != nil {
+		return fmt.Errorf("%w: invalid bcrypt cost: %w", ErrInvalidHash, err)
+	}
+
+	salt, hash := encoded[bcryptHeaderLength:bcryptHeaderLength+bcryptSaltLength], encoded[bcryptHeaderLength+bcryptSaltLength:]
+	if _, err := bcryptBase64.DecodeString(salt); err != nil {
+		return fmt.Errorf("%w: invalid bcrypt salt: %w", ErrInvalidHash, err)
+	}
+
+	if _, err := bcryptBase64.DecodeString(hash); err != nil {
+		return fmt.Errorf("%w: invalid bcrypt hash: %w", ErrInvalidHash, err)
+	}
+
+	return nil
+}
+
+// Verify reports whether the password matches the bcrypt hash.
+func (b *Bcrypt) Verify(password string, encoded string) (bool, error) {
+	if err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("%w: %w", ErrInvalidHash, err)
+	}
+
+	return true, nil
+}
+
+// NeedsRehash reports whether the bcrypt hash uses a lower cost than b. Hashes of a higher cost are kept.
+func (b *Bcrypt) NeedsRehash(encoded string) bool {
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return err != nil || cost < b.cost
+}
@@ -0,0 +1,42 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestBcrypt(t *testing.T) {
+	scheme := password.NewBcrypt(4)
+
+	encoded, err := scheme.Hash("password")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.True(t, scheme.Identify(encoded))
+	assert.NoError(t, scheme.Check(encoded))
+	assert.ErrorIs(t, scheme.Check(encoded[:len(encoded)-1]), password.ErrInvalidHash)
+	assert.ErrorIs(t, scheme.Check(encoded[:len(encoded)-1]+"!"), password.ErrInvalidHash)
+	assert.ErrorIs(t, scheme.Check("$2a$xx$"+encoded[7:]), password.ErrInvalidHash)
+	assert.False(t, scheme.NeedsRehash(encoded))
+	assert.True(t, password.NewBcrypt(5).NeedsRehash(encoded))
+	assert.False(t, password.NewBcrypt(3).NeedsRehash(encoded), "hashes of a higher cost are kept")
+
+	ok, err := scheme.Verify("password", encoded)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = scheme.Verify("Password", encoded)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, err = scheme.Verify("password", "$2a$04$short")
+	assert.ErrorIs(t, err, password.ErrInvalidHash)
+
+	_, err = scheme.Hash(strings.Repeat("a", password.MaxBytes+1))
+	assert.Error(t, err)
+}
@@ -0,0 +1,165 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// bloomMagic starts every Bloom filter file and identifies its format version.
+const bloomMagic = "PWBLOOM1"
+
+// maxBloomHashes caps the number of bit positions per password, which only grows for very low false positive rates.
+const maxBloomHashes = 32
+
+// ErrInvalidBloomFilter is returned when a Bloom filter file is truncated or has an unknown format.
+var ErrInvalidBloomFilter = errors.New("invalid bloom filter")
+
+// NewBloomFilter creates an empty *BloomFilter sized for n SHA-1 hashes with the given false positive rate.
+func NewBloomFilter(n uint64, falsePositiveRate float64) *BloomFilter {
+	if n == 0 {
+		n = 1
+	}
+
+	bits := uint64(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if bits < 64 {
+		bits = 64
+	}
+
+	hashes := uint32(math.Round(float64(bits) / float64(n) * math.Ln2))
+	hashes = min(max(hashes, 1), maxBloomHashes)
+
+	return &BloomFilter{
+		hashes: hashes,
+		bits:   bits,
+		data:   make([]byte, (bits+7)/8),
+	}
+}
+
+// BloomFilter is a compact set of SHA-1 hashes of breached passwords. It never misses a password it contains,
+// but reports passwords it does not contain with a small false positive rate.
+type BloomFilter struct {
+	hashes uint32
+	bits   uint64
+	data   []byte
+}
+
+// BuildBloomFilter builds a *BloomFilter from a list of SHA-1 hashes, one per line, in the HIBP format
+// "HASH" or "HASH:COUNT". The list is read twice: once to count the hashes and once to add them.
+func BuildBloomFilter(list io.ReadSeeker, falsePositiveRate float64) (*BloomFilter, error) {
+	if falsePositiveRate <= 0 || falsePositiveRate >= 1 {
+		return nil, fmt.Errorf("false positive rate must be between 0 and 1, got %v", falsePositiveRate)
+	}
+
+	var n uint64
+	if err := scanHashList(list, func([sha1.Size]byte) { n++ }); err != nil {
+		return nil, err
+	}
+
+	if _, err := list.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind hash list: %w", err)
+	}
+
+	filter := NewBloomFilter(n, falsePositiveRate)
+	if err := scanHashList(list, filter.AddHash); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// LoadBloomFilter reads a *BloomFilter from the file written by WriteTo.
+func LoadBloomFilter(path string) (*BloomFilter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bloom filter: %w", err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	return ReadBloomFilter(bufio.NewReader(file))
+}
+
+// ReadBloomFilter reads a *BloomFilter written by WriteTo.
+func ReadBloomFilter(r io.Reader) (*BloomFilter, error) {
+	header := make([]byte, len(bloomMagic)+4+8)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: failed to read header: %w", ErrInvalidBloomFilter, err)
+	}
+
+	if string(header[:len(bloomMagic)]) != bloomMagic {
+		return nil, fmt.Errorf("%w: unknown format", ErrInvalidBloomFilter)
+	}
+
+	hashes := binary.BigEndian.Uint32(header[len(bloomMagic):])
+	bits := binary.BigEndian.Uint64(header[len(bloomMagic)+4:])
+	if hashes == 0 || hashes > maxBloomHashes || bits == 0 {
+		return nil, fmt.Errorf("%w: %d hashes over %d bits", ErrInvalidBloomFilter, hashes, bits)
+	}
+
+	data := make([]byte, (bits+7)/8)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, fmt.Errorf("%w: failed to read bits: %w", ErrInvalidBloomFilter, err)
+	}
+
+	return &BloomFilter{hashes: hashes, bits: bits, data: data}, nil
+}
+
+// WriteTo writes the filter in a format read by ReadBloomFilter.
+func (f *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	header := make([]byte, 0, len(bloomMagic)+4+8)
+	header = append(header, bloomMagic...)
+	header = binary.BigEndian.AppendUint32(header, f.hashes)
+	header = binary.BigEndian.AppendUint64(header, f.bits)
+
+	n, err := w.Write(header)
+	if err != nil {
+		return int64(n), err
+	}
+
+	var m int
+	m, err = w.Write(f.data)
+	return int64(n + m), err
+}
+
+// AddHash adds the SHA-1 hash of a password.
+func (f *BloomFilter) AddHash(sum [sha1.Size]byte) {
+	f.positions(sum, func(bit uint64) bool {
+		f.data[bit/8] |= 1 << (bit % 8)
+		return true
+	})
+}
+
+// ContainsHash reports whether the SHA-1 hash of a password may have been added.
+func (f *BloomFilter) ContainsHash(sum [sha1.Size]byte) bool {
+	contains := true
+	f.positions(sum, func(bit uint64) bool {
+		contains = f.data[bit/8]&(1<<(bit%8)) != 0
+		return contains
+	})
+
+	return contains
+}
+
+// Contains reports whether the password may be breached. The error is always nil.
+func (f *BloomFilter) Contains(password string) (bool, error) {
+	return f.ContainsHash(sha1.Sum([]byte(password))), nil
+}
+
+// positions calls fn with every bit position of the hash until fn returns false. SHA-1 output is uniformly
+// distributed, so the positions are derived from the hash itself with double hashing.
+func (f *BloomFilter) positions(sum [sha1.Size]byte, fn func(bit uint64) bool) {
+	h1 := binary.BigEndian.Uint64(sum[0:8])
+	h2 := binary.BigEndian.Uint64(sum[8:16]) | 1
+
+	for i := uint64(0); i < uint64(f.hashes); i++ {
+		if !fn((h1 + i*h2) % f.bits) {
+			return
+		}
+	}
+}
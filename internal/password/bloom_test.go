@@ -0,0 +1,104 @@
+package password_test
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestBuildBloomFilter(t *testing.T) {
+	t.Run("invalid false positive rate", func(t *testing.T) {
+		_, err := password.BuildBloomFilter(strings.NewReader(""), 1)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid hash", func(t *testing.T) {
+		_, err := password.BuildBloomFilter(strings.NewReader(sha1Hex("password")+":10\nnot a hash:1\n"), 0.01)
+		assert.ErrorContains(t, err, "line 2")
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		list := strings.Join([]string{
+			strings.ToUpper(sha1Hex("password")) + ":9545824",
+			"",
+			sha1Hex("123456") + ":37359195\r",
+			strings.ToUpper(sha1Hex("qwerty")),
+		}, "\n")
+
+		filter, err := password.BuildBloomFilter(strings.NewReader(list), 0.001)
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		for _, breached := range []string{"password", "123456", "qwerty"} {
+			contains, err := filter.Contains(breached)
+			assert.NoError(t, err)
+			assert.True(t, contains, breached)
+		}
+
+		contains, err := filter.Contains("correct horse battery staple")
+		assert.NoError(t, err)
+		assert.False(t, contains)
+	})
+}
+
+func TestBloomFilter_FalsePositiveRate(t *testing.T) {
+	filter := password.NewBloomFilter(10000, 0.01)
+	for i := range 10000 {
+		filter.AddHash(sha1.Sum([]byte(fmt.Sprintf("breached-%d", i))))
+	}
+
+	var falsePositives int
+	for i := range 10000 {
+		if filter.ContainsHash(sha1.Sum([]byte(fmt.Sprintf("unique-%d", i)))) {
+			falsePositives++
+		}
+	}
+
+	assert.Less(t, falsePositives, 200)
+}
+
+func TestReadBloomFilter(t *testing.T) {
+	filter := password.NewBloomFilter(100, 0.01)
+	filter.AddHash(sha1.Sum([]byte("password")))
+
+	var buf bytes.Buffer
+	n, err := filter.WriteTo(&buf)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, int64(buf.Len()), n)
+	encoded := buf.Bytes()
+
+	t.Run("round trip", func(t *testing.T) {
+		read, err := password.ReadBloomFilter(bytes.NewReader(encoded))
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, filter, read)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := password.ReadBloomFilter(strings.NewReader("NOTBLOOM" + string(encoded[8:])))
+		assert.ErrorIs(t, err, password.ErrInvalidBloomFilter)
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		_, err := password.ReadBloomFilter(bytes.NewReader(encoded[:len(encoded)-1]))
+		assert.ErrorIs(t, err, password.ErrInvalidBloomFilter)
+	})
+}
+
+func sha1Hex(value string) string {
+	sum := sha1.Sum([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,147 @@
+package password
+
+import (
+	"fmt"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// calibrationRounds is how many times every set of parameters is measured. The fastest run is taken,
+	// as slower ones are slowed down by something else running at the same time.
+	calibrationRounds = 3
+
+	// calibrationPassword is hashed to measure parameters, its value does not affect the duration.
+	calibrationPassword = "calibration password"
+
+	// maxArgon2idIterations stops calibration on hardware so fast that the target would take absurd iterations.
+	// Stored hashes with more iterations are rejected.
+	maxArgon2idIterations = 64
+)
+
+type (
+	// Calibration describes the parameters chosen for the current algorithm and how they were chosen.
+	Calibration struct {
+		// Algorithm is the algorithm new passwords are hashed with.
+		Algorithm string
+		// Pinned reports whether the parameters were taken from the config as they are, without measuring.
+		Pinned bool
+		// TargetLatency is the longest a hash may take, zero if pinned.
+		TargetLatency time.Duration
+		// HashDuration is the measured duration of a hash with the chosen parameters, zero if pinned.
+		HashDuration time.Duration
+		// Argon2id holds the argon2id parameters, calibrated if argon2id is the current algorithm.
+		Argon2id Argon2idParams
+		// BcryptCost is the bcrypt cost, calibrated if bcrypt is the current algorithm.
+		BcryptCost int
+	}
+
+	// Argon2idParams are the parameters of argon2id. Memory is in KiB.
+	Argon2idParams struct {
+		Memory      uint32
+		Iterations  uint32
+		Parallelism uint8
+	}
+)
+
+// NewCalibrator creates a new *Calibrator instance choosing parameters that hash in at most target.
+// Argon2id memory is not raised above maxArgon2idMemory KiB, as every hash running at the same time takes that much,
+// nor above what stored hashes may use.
+func NewCalibrator(target time.Duration, maxArgon2idMemory uint32) *Calibrator {
+	return &Calibrator{
+		target:            target,
+		maxArgon2idMemory: min(maxArgon2idMemory, maxStoredArgon2idMemory),
+	}
+}
+
+// Calibrator picks the strongest hashing parameters that keep a hash under a target latency on this machine,
+// so that hashes get stronger as hardware gets faster without slowing down logins.
+type Calibrator struct {
+	target            time.Duration
+	maxArgon2idMemory uint32
+}
+
+// Bcrypt returns the highest cost from minCost up whose hash takes at most the target, and the duration of the hash.
+// minCost is returned even if it takes longer, so that calibration never weakens the configured parameters.
+func (c *Calibrator) Bcrypt(minCost int) (int, time.Duration, error) {
+	cost := minCost
+
+	took, err := measure(NewBcrypt(cost))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// every step of the cost doubles the work, so a step that would surely overshoot is not even measured
+	for cost < bcrypt.MaxCost && 2*took <= c.target {
+		var next time.Duration
+		if next, err = measure(NewBcrypt(cost + 1)); err != nil {
+			return 0, 0, err
+		}
+
+		if next > c.target {
+			break
+		}
+
+		cost, took = cost+1, next
+	}
+
+	return cost, took, nil
+}
+
+// Argon2id returns the strongest parameters from minParams up whose hash takes at most the target, and the duration
+// of the hash. Memory is doubled up to the maximum first, as it is what makes argon2id costly to attack with GPUs,
+// and iterations are added after that. Parallelism is kept. minParams are returned even if they take longer,
+// so that calibration never weakens the configured parameters.
+func (c *Calibrator) Argon2id(minParams Argon2idParams) (Argon2idParams, time.Duration, error) {
+	params := minParams
+
+	took, err := measure(NewArgon2id(params.Memory, params.Iterations, params.Parallelism))
+	if err != nil {
+		return Argon2idParams{}, 0, err
+	}
+
+	for params.Iterations < maxArgon2idIterations {
+		next, estimate := params, 2*took
+		if params.Memory <= c.maxArgon2idMemory/2 {
+			next.Memory *= 2
+		} else {
+			next.Iterations++
+			estimate = took * time.Duration(next.Iterations) / time.Duration(params.Iterations)
+		}
+
+		if estimate > c.target {
+			break
+		}
+
+		var nextTook time.Duration
+		if nextTook, err = measure(NewArgon2id(next.Memory, next.Iterations, next.Parallelism)); err != nil {
+			return Argon2idParams{}, 0, err
+		}
+
+		if nextTook > c.target {
+			break
+		}
+
+		params, took = next, nextTook
+	}
+
+	return params, took, nil
+}
+
+// measure returns the fastest of a few hashes made with the scheme.
+func measure(scheme Scheme) (time.Duration, error) {
+	var fastest time.Duration
+	for i := range calibrationRounds {
+		start := time.Now()
+		if _, err := scheme.Hash(calibrationPassword); err != nil {
+			return 0, fmt.Errorf("failed to measure hashing: %w", err)
+		}
+
+		if took := time.Since(start); i == 0 || took < fastest {
+			fastest = took
+		}
+	}
+
+	return fastest, nil
+}
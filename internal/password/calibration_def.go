@@ -0,0 +1,128 @@
+package password
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+	"github.com/riabininkf/go-modules/logger"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// DefCalibrationName is the name of the *Calibration definition.
+	DefCalibrationName = "password.calibration"
+
+	configKeyAlgorithm                    = "auth.passwordHashing.algorithm"
+	configKeyArgon2idMemory               = "auth.passwordHashing.argon2id.memory"
+	configKeyArgon2idIterations           = "auth.passwordHashing.argon2id.iterations"
+	configKeyArgon2idParallelism          = "auth.passwordHashing.argon2id.parallelism"
+	configKeyBcryptCost                   = "auth.passwordHashing.bcrypt.cost"
+	configKeyCalibrationPin               = "auth.passwordHashing.calibration.pin"
+	configKeyCalibrationTargetLatency     = "auth.passwordHashing.calibration.targetLatency"
+	configKeyCalibrationArgon2idMaxMemory = "auth.passwordHashing.calibration.argon2idMaxMemory"
+
+	algorithmArgon2id = "argon2id"
+	algorithmBcrypt   = "bcrypt"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Calibration]{
+			Name: DefCalibrationName,
+			Build: func(ctn di.Container) (*Calibration, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var log *logger.Logger
+				if err := ctn.Fill(logger.DefName, &log); err != nil {
+					return nil, err
+				}
+
+				calibration := &Calibration{Pinned: true}
+
+				if calibration.Argon2id.Memory = cfg.GetUint32(configKeyArgon2idMemory); calibration.Argon2id.Memory == 0 {
+					return nil, config.NewErrMissingKey(configKeyArgon2idMemory)
+				}
+
+				if calibration.Argon2id.Iterations = cfg.GetUint32(configKeyArgon2idIterations); calibration.Argon2id.Iterations == 0 {
+					return nil, config.NewErrMissingKey(configKeyArgon2idIterations)
+				}
+
+				if calibration.Argon2id.Parallelism = uint8(cfg.GetUint(configKeyArgon2idParallelism)); calibration.Argon2id.Parallelism == 0 {
+					return nil, config.NewErrMissingKey(configKeyArgon2idParallelism)
+				}
+
+				if calibration.BcryptCost = cfg.GetInt(configKeyBcryptCost); calibration.BcryptCost == 0 {
+					return nil, config.NewErrMissingKey(configKeyBcryptCost)
+				}
+
+				if calibration.BcryptCost < bcrypt.MinCost || calibration.BcryptCost > bcrypt.MaxCost {
+					return nil, fmt.Errorf("bcrypt cost must be between %d and %d, got %d",
+						bcrypt.MinCost, bcrypt.MaxCost, calibration.BcryptCost)
+				}
+
+				switch calibration.Algorithm = cfg.GetString(configKeyAlgorithm); calibration.Algorithm {
+				case algorithmArgon2id, algorithmBcrypt:
+				case "":
+					return nil, config.NewErrMissingKey(configKeyAlgorithm)
+				default:
+					return nil, fmt.Errorf("unknown password hashing algorithm %q", calibration.Algorithm)
+				}
+
+				// pinned parameters are the same on every instance, which mixed hardware would not agree on
+				target := cfg.GetDuration(configKeyCalibrationTargetLatency)
+				if cfg.GetBool(configKeyCalibrationPin) || target == 0 {
+					log.Info("password hashing parameters are pinned",
+						logger.String("algorithm", calibration.Algorithm),
+						logger.Int("argon2idMemory", int(calibration.Argon2id.Memory)),
+						logger.Int("argon2idIterations", int(calibration.Argon2id.Iterations)),
+						logger.Int("bcryptCost", calibration.BcryptCost),
+					)
+
+					return calibration, nil
+				}
+
+				maxMemory := calibration.Argon2id.Memory
+				if cfg.IsSet(configKeyCalibrationArgon2idMaxMemory) {
+					maxMemory = max(cfg.GetUint32(configKeyCalibrationArgon2idMaxMemory), maxMemory)
+				}
+
+				calibrator := NewCalibrator(target, maxMemory)
+
+				calibration.Pinned = false
+				calibration.TargetLatency = target
+
+				var err error
+				switch calibration.Algorithm {
+				case algorithmArgon2id:
+					calibration.Argon2id, calibration.HashDuration, err = calibrator.Argon2id(calibration.Argon2id)
+				case algorithmBcrypt:
+					calibration.BcryptCost, calibration.HashDuration, err = calibrator.Bcrypt(calibration.BcryptCost)
+				}
+
+				if err != nil {
+					return nil, fmt.Errorf("failed to calibrate password hashing: %w", err)
+				}
+
+				log.Info("password hashing parameters are calibrated",
+					logger.String("algorithm", calibration.Algorithm),
+					logger.Int("argon2idMemory", int(calibration.Argon2id.Memory)),
+					logger.Int("argon2idIterations", int(calibration.Argon2id.Iterations)),
+					logger.Int("bcryptCost", calibration.BcryptCost),
+					logger.String("hashDuration", calibration.HashDuration.Round(time.Millisecond).String()),
+					logger.String("targetLatency", target.String()),
+				)
+
+				if calibration.HashDuration > target {
+					log.Warn("configured password hashing parameters are slower than the target latency")
+				}
+
+				return calibration, nil
+			},
+		},
+	)
+}
@@ -0,0 +1,51 @@
+package password_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestCalibrator_Bcrypt(t *testing.T) {
+	t.Run("configured cost is kept even if slower than target", func(t *testing.T) {
+		cost, took, err := password.NewCalibrator(time.Nanosecond, 0).Bcrypt(bcrypt.MinCost)
+		assert.NoError(t, err)
+		assert.Equal(t, bcrypt.MinCost, cost)
+		assert.Positive(t, took)
+	})
+
+	t.Run("cost is raised up to target", func(t *testing.T) {
+		target := 50 * time.Millisecond
+
+		cost, took, err := password.NewCalibrator(target, 0).Bcrypt(bcrypt.MinCost)
+		assert.NoError(t, err)
+		assert.Greater(t, cost, bcrypt.MinCost)
+		assert.LessOrEqual(t, took, target)
+	})
+}
+
+func TestCalibrator_Argon2id(t *testing.T) {
+	minParams := password.Argon2idParams{Memory: 64, Iterations: 1, Parallelism: 1}
+
+	t.Run("configured parameters are kept even if slower than target", func(t *testing.T) {
+		params, took, err := password.NewCalibrator(time.Nanosecond, 1024).Argon2id(minParams)
+		assert.NoError(t, err)
+		assert.Equal(t, minParams, params)
+		assert.Positive(t, took)
+	})
+
+	t.Run("memory is raised first up to the maximum", func(t *testing.T) {
+		target := 10 * time.Millisecond
+
+		params, took, err := password.NewCalibrator(target, 1024).Argon2id(minParams)
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(1024), params.Memory)
+		assert.Greater(t, params.Iterations, minParams.Iterations)
+		assert.Equal(t, minParams.Parallelism, params.Parallelism)
+		assert.LessOrEqual(t, took, target)
+	})
+}
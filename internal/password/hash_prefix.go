@@ -0,0 +1,91 @@
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// hashPrefixLength is the number of hex characters of the SHA-1 hash that name a range file,
+// as in the k-anonymity range API of Have I Been Pwned.
+const hashPrefixLength = 5
+
+// NewHashPrefixDataset creates a new *HashPrefixDataset reading range files from dir.
+func NewHashPrefixDataset(dir string) *HashPrefixDataset {
+	return &HashPrefixDataset{dir: dir}
+}
+
+// HashPrefixDataset is a local copy of the Have I Been Pwned range files. Every file is named after the first
+// five hex characters of the SHA-1 hashes it lists, e.g. "21BD1.txt", and holds the rest of each hash as
+// "SUFFIX:COUNT" lines. Only the range of the password is read, so the dataset does not have to fit in memory.
+type HashPrefixDataset struct {
+	dir string
+}
+
+// Contains reports whether the password is listed in the dataset. A missing range file means the range is empty.
+func (d *HashPrefixDataset) Contains(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	file, err := os.Open(filepath.Join(d.dir, hash[:hashPrefixLength]+".txt"))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to open range file: %w", err)
+	}
+
+	defer func() { _ = file.Close() }()
+
+	suffix := hash[hashPrefixLength:]
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), ":")
+		if strings.EqualFold(strings.TrimSpace(line), suffix) {
+			return true, nil
+		}
+	}
+
+	if err = scanner.Err(); err != nil {
+		return false, fmt.Errorf("failed to read range file: %w", err)
+	}
+
+	return false, nil
+}
+
+// scanHashList calls fn with every hash of a list of SHA-1 hashes in the HIBP format "HASH" or "HASH:COUNT".
+// Empty lines are skipped.
+func scanHashList(list io.Reader, fn func(sum [sha1.Size]byte)) error {
+	scanner := bufio.NewScanner(list)
+	for line := 1; scanner.Scan(); line++ {
+		hash, _, _ := strings.Cut(scanner.Text(), ":")
+		if hash = strings.TrimSpace(hash); hash == "" {
+			continue
+		}
+
+		var sum [sha1.Size]byte
+		if len(hash) != hex.EncodedLen(sha1.Size) {
+			return fmt.Errorf("line %d: %q is not a SHA-1 hash", line, hash)
+		}
+
+		if _, err := hex.Decode(sum[:], []byte(hash)); err != nil {
+			return fmt.Errorf("line %d: %q is not a SHA-1 hash", line, hash)
+		}
+
+		fn(sum)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read hash list: %w", err)
+	}
+
+	return nil
+}
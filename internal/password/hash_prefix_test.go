@@ -0,0 +1,54 @@
+package password_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestHashPrefixDataset_Contains(t *testing.T) {
+	hash := strings.ToUpper(sha1Hex("password"))
+
+	testCases := map[string]struct {
+		rangeFile   string
+		password    string
+		expContains bool
+	}{
+		"listed": {
+			rangeFile:   "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n" + hash[5:] + ":9545824\r\n",
+			password:    "password",
+			expContains: true,
+		},
+		"listed in lowercase": {
+			rangeFile:   strings.ToLower(hash[5:]) + ":9545824\n",
+			password:    "password",
+			expContains: true,
+		},
+		"not listed in the range": {
+			rangeFile: "0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n",
+			password:  "password",
+		},
+		"range file is missing": {
+			rangeFile: hash[5:] + ":9545824\r\n",
+			password:  "correct horse battery staple",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, hash[:5]+".txt"), []byte(tc.rangeFile), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			contains, err := password.NewHashPrefixDataset(dir).Contains(tc.password)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expContains, contains)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownHash is returned for encoded hashes that none of the verifiers of a Hasher recognizes.
+var ErrUnknownHash = errors.New("unknown password hash format")
+
+// NewHasher creates a new *Hasher instance hashing new passwords with current. Stored hashes are verified
+// with current or any of the others, so that they keep working after the algorithm is changed.
+func NewHasher(current Scheme, others ...Verifier) *Hasher {
+	return &Hasher{
+		current:   current,
+		verifiers: append([]Verifier{current}, others...),
+	}
+}
+
+type (
+	// Hasher hashes passwords with the current scheme and verifies hashes of all known algorithms.
+	Hasher struct {
+		current   Scheme
+		verifiers []Verifier
+	}
+
+	// Verifier verifies passwords against hashes of one algorithm, whatever parameters they were made with.
+	Verifier interface {
+		// Identify reports whether the encoded hash was produced by the algorithm of the verifier.
+		Identify(encoded string) bool
+		// Check fully decodes the encoded hash and returns ErrInvalidHash if it cannot be verified,
+		// e.g. because it is truncated or its cost is above what the verifier accepts.
+		Check(encoded string) error
+		// Verify reports whether the password matches the encoded hash, comparing in constant time.
+		Verify(password string, encoded string) (bool, error)
+	}
+
+	// Scheme is a password hashing algorithm with fixed parameters. Hashes are encoded in the PHC string format,
+	// e.g. "$argon2id$v=19$m=19456,t=2,p=1$salt$hash", or in the modular crypt format bcrypt has always used.
+	Scheme interface {
+		Verifier
+		// Hash returns the encoded hash of the password with a random salt.
+		Hash(password string) (string, error)
+		// NeedsRehash reports whether the encoded hash uses weaker parameters than those of the scheme.
+		NeedsRehash(encoded string) bool
+	}
+)
+
+// Hash returns the encoded hash of the password produced by the current scheme.
+func (h *Hasher) Hash(password string) (string, error) {
+	encoded, err := h.current.Hash(password)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	return encoded, nil
+}
+
+// Verify reports whether the password matches the encoded hash.
+// Returns ErrUnknownHash if no verifier recognizes the hash.
+func (h *Hasher) Verify(password string, encoded string) (bool, error) {
+	verifier, err := h.verifier(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	return verifier.Verify(password, encoded)
+}
+
+// Identify reports whether any of the verifiers recognizes the encoded hash.
+func (h *Hasher) Identify(encoded string) bool {
+	_, err := h.verifier(encoded)
+	return err == nil
+}
+
+// Check returns ErrUnknownHash if no verifier recognizes the encoded hash and ErrInvalidHash
+// if the verifier that does cannot decode it.
+func (h *Hasher) Check(encoded string) error {
+	verifier, err := h.verifier(encoded)
+	if err != nil {
+		return err
+	}
+
+	return verifier.Check(encoded)
+}
+
+// NeedsRehash reports whether the encoded hash was produced by another algorithm or with weaker parameters,
+// so that it should be replaced with a hash of the current scheme once the password is known.
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	if !h.current.Identify(encoded) {
+		return true
+	}
+
+	return h.current.NeedsRehash(encoded)
+}
+
+// verifier returns the verifier of the algorithm that produced the encoded hash.
+func (h *Hasher) verifier(encoded string) (Verifier, error) {
+	for _, verifier := range h.verifiers {
+		if verifier.Identify(encoded) {
+			return verifier, nil
+		}
+	}
+
+	return nil, ErrUnknownHash
+}
@@ -0,0 +1,42 @@
+package password
+
+import (
+	"github.com/riabininkf/go-modules/di"
+)
+
+const (
+	// DefHasherName is the name of the *Hasher definition.
+	DefHasherName = "password.hasher"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Hasher]{
+			Name: DefHasherName,
+			Build: func(ctn di.Container) (*Hasher, error) {
+				var calibration *Calibration
+				if err := ctn.Fill(DefCalibrationName, &calibration); err != nil {
+					return nil, err
+				}
+
+				argon2id := NewArgon2id(
+					calibration.Argon2id.Memory,
+					calibration.Argon2id.Iterations,
+					calibration.Argon2id.Parallelism,
+				)
+				bcryptScheme := NewBcrypt(calibration.BcryptCost)
+
+				// hashes imported from other systems are verified and replaced on the first login
+				legacy := []Verifier{NewPBKDF2SHA256(), NewScrypt(), NewSHACrypt()}
+
+				// the calibrated parameters are the current ones, so hashes made with weaker ones are rehashed on login
+				switch calibration.Algorithm {
+				case algorithmArgon2id:
+					return NewHasher(argon2id, append([]Verifier{bcryptScheme}, legacy...)...), nil
+				default:
+					return NewHasher(bcryptScheme, append([]Verifier{argon2id}, legacy...)...), nil
+				}
+			},
+		},
+	)
+}
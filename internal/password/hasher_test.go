@@ -0,0 +1,78 @@
+package password_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestHasher(t *testing.T) {
+	argon2id := password.NewArgon2id(64, 1, 1)
+	bcrypt := password.NewBcrypt(4)
+
+	bcryptHash, err := bcrypt.Hash("password")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	hasher := password.NewHasher(argon2id, bcrypt)
+
+	encoded, err := hasher.Hash("password")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	assert.True(t, argon2id.Identify(encoded), "new passwords are hashed with the current scheme")
+	assert.False(t, hasher.NeedsRehash(encoded))
+
+	t.Run("verifies hashes of every scheme", func(t *testing.T) {
+		for _, hash := range []string{encoded, bcryptHash} {
+			ok, err := hasher.Verify("password", hash)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = hasher.Verify("wrong", hash)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+		}
+	})
+
+	t.Run("hashes of other schemes need rehash", func(t *testing.T) {
+		assert.True(t, hasher.NeedsRehash(bcryptHash))
+	})
+
+	t.Run("hashes with outdated parameters need rehash", func(t *testing.T) {
+		assert.True(t, password.NewHasher(password.NewArgon2id(128, 1, 1)).NeedsRehash(encoded))
+	})
+
+	t.Run("legacy hashes are verified and need rehash", func(t *testing.T) {
+		legacyHash := "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5"
+		hasher := password.NewHasher(argon2id, password.NewSHACrypt())
+
+		ok, err := hasher.Verify("Hello world!", legacyHash)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, hasher.NeedsRehash(legacyHash))
+	})
+
+	t.Run("identifies hashes of every scheme", func(t *testing.T) {
+		assert.True(t, hasher.Identify(encoded))
+		assert.True(t, hasher.Identify(bcryptHash))
+		assert.False(t, hasher.Identify("5f4dcc3b5aa765d61d8327deb882cf99"))
+	})
+
+	t.Run("checks hashes of every scheme", func(t *testing.T) {
+		assert.NoError(t, hasher.Check(encoded))
+		assert.NoError(t, hasher.Check(bcryptHash))
+		assert.ErrorIs(t, hasher.Check(encoded[:len(encoded)-10]+"$"), password.ErrInvalidHash)
+		assert.ErrorIs(t, hasher.Check("5f4dcc3b5aa765d61d8327deb882cf99"), password.ErrUnknownHash)
+	})
+
+	t.Run("unknown hash", func(t *testing.T) {
+		ok, err := hasher.Verify("password", "5f4dcc3b5aa765d61d8327deb882cf99")
+		assert.False(t, ok)
+		assert.ErrorIs(t, err, password.ErrUnknownHash)
+	})
+}
@@ -0,0 +1,116 @@
+package password
+
+import (
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	pbkdf2PasslibPrefix = "$pbkdf2-sha256$"
+	pbkdf2DjangoPrefix  = "pbkdf2_sha256$"
+
+	// maxPBKDF2Iterations caps the cost of stored hashes, so that a corrupted or imported hash cannot tie up
+	// a hashing slot for minutes. It is well above what passlib and Django use.
+	maxPBKDF2Iterations = 10_000_000
+)
+
+// adaptedBase64 is the base64 variant of passlib: the standard alphabet with "." instead of "+" and no padding.
+var adaptedBase64 = base64.NewEncoding("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789./").
+	WithPadding(base64.NoPadding)
+
+// NewPBKDF2SHA256 creates a new *PBKDF2SHA256 instance.
+func NewPBKDF2SHA256() *PBKDF2SHA256 {
+	return &PBKDF2SHA256{}
+}
+
+type (
+	// PBKDF2SHA256 verifies legacy PBKDF2-HMAC-SHA256 hashes in the passlib format
+	// "$pbkdf2-sha256$29000$salt$checksum" and in the Django format "pbkdf2_sha256$260000$salt$checksum".
+	PBKDF2SHA256 struct{}
+
+	// pbkdf2Hash is a decoded PBKDF2-SHA256 hash.
+	pbkdf2Hash struct {
+		iterations int
+		salt       []byte
+		checksum   []byte
+	}
+)
+
+// Identify reports whether the encoded hash is a PBKDF2-SHA256 hash.
+func (p *PBKDF2SHA256) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, pbkdf2PasslibPrefix) || strings.HasPrefix(encoded, pbkdf2DjangoPrefix)
+}
+
+// Check returns ErrInvalidHash if the PBKDF2-SHA256 hash cannot be decoded.
+func (p *PBKDF2SHA256) Check(encoded string) error {
+	_, err := decodePBKDF2(encoded)
+	return err
+}
+
+// Verify reports whether the password matches the PBKDF2-SHA256 hash.
+func (p *PBKDF2SHA256) Verify(password string, encoded string) (bool, error) {
+	hash, err := decodePBKDF2(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	var key []byte
+	if key, err = pbkdf2.Key(sha256.New, password, hash.salt, hash.iterations, len(hash.checksum)); err != nil {
+		return false, fmt.Errorf("failed to derive pbkdf2 key: %w", err)
+	}
+
+	return subtle.ConstantTimeCompare(key, hash.checksum) == 1, nil
+}
+
+// decodePBKDF2 parses a PBKDF2-SHA256 hash in the passlib or the Django format.
+func decodePBKDF2(encoded string) (pbkdf2Hash, error) {
+	var (
+		hash     pbkdf2Hash
+		fields   []string
+		encoding *base64.Encoding
+	)
+
+	switch {
+	case strings.HasPrefix(encoded, pbkdf2PasslibPrefix):
+		// passlib encodes the salt in the same base64 variant as the checksum
+		fields, encoding = strings.Split(strings.TrimPrefix(encoded, pbkdf2PasslibPrefix), "$"), adaptedBase64
+		if len(fields) == 3 {
+			var err error
+			if hash.salt, err = encoding.DecodeString(fields[1]); err != nil {
+				return hash, fmt.Errorf("%w: invalid pbkdf2 salt: %w", ErrInvalidHash, err)
+			}
+		}
+	case strings.HasPrefix(encoded, pbkdf2DjangoPrefix):
+		// Django uses the salt as is
+		fields, encoding = strings.Split(strings.TrimPrefix(encoded, pbkdf2DjangoPrefix), "$"), base64.StdEncoding
+		if len(fields) == 3 {
+			hash.salt = []byte(fields[1])
+		}
+	default:
+		return hash, fmt.Errorf("%w: not a pbkdf2-sha256 hash", ErrInvalidHash)
+	}
+
+	if len(fields) != 3 {
+		return hash, fmt.Errorf("%w: malformed pbkdf2 hash", ErrInvalidHash)
+	}
+
+	var err error
+	if hash.iterations, err = strconv.Atoi(fields[0]); err != nil || hash.iterations <= 0 {
+		return hash, fmt.Errorf("%w: invalid pbkdf2 iterations %q", ErrInvalidHash, fields[0])
+	}
+
+	if hash.iterations > maxPBKDF2Iterations {
+		return hash, fmt.Errorf("%w: pbkdf2 iterations %d are too many", ErrInvalidHash, hash.iterations)
+	}
+
+	if hash.checksum, err = encoding.DecodeString(fields[2]); err != nil || len(hash.checksum) == 0 {
+		return hash, fmt.Errorf("%w: invalid pbkdf2 checksum", ErrInvalidHash)
+	}
+
+	return hash, nil
+}
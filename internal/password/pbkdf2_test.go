@@ -0,0 +1,75 @@
+package password_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestPBKDF2SHA256_Verify(t *testing.T) {
+	// known answers computed with Python's hashlib.pbkdf2_hmac and encoded as passlib and Django do
+	const (
+		passlibHash = "$pbkdf2-sha256$29000$c2FsdHNhbHRzYWx0c2FsdA$7xwbY5rCP.qJhnvJ80W3FI7hSRg8wNnl3S9rczjVuCk"
+		djangoHash  = "pbkdf2_sha256$260000$seasalt$ftMWvEdczZQK5azuap2CQYKRjHLa1wOuMrfMiYEswYQ="
+	)
+
+	testCases := map[string]struct {
+		password string
+		encoded  string
+		expOK    bool
+		expErr   error
+	}{
+		"passlib format": {
+			password: "password",
+			encoded:  passlibHash,
+			expOK:    true,
+		},
+		"passlib format with wrong password": {
+			password: "Password",
+			encoded:  passlibHash,
+		},
+		"django format": {
+			password: "password",
+			encoded:  djangoHash,
+			expOK:    true,
+		},
+		"django format with wrong password": {
+			password: "password1",
+			encoded:  djangoHash,
+		},
+		"malformed hash": {
+			password: "password",
+			encoded:  "$pbkdf2-sha256$29000$c2FsdHNhbHRzYWx0c2FsdA",
+			expErr:   password.ErrInvalidHash,
+		},
+		"invalid iterations": {
+			password: "password",
+			encoded:  "pbkdf2_sha256$0$seasalt$ftMWvEdczZQK5azuap2CQYKRjHLa1wOuMrfMiYEswYQ=",
+			expErr:   password.ErrInvalidHash,
+		},
+		"too many iterations": {
+			password: "password",
+			encoded:  "pbkdf2_sha256$1000000000$seasalt$ftMWvEdczZQK5azuap2CQYKRjHLa1wOuMrfMiYEswYQ=",
+			expErr:   password.ErrInvalidHash,
+		},
+		"invalid checksum": {
+			password: "password",
+			encoded:  "pbkdf2_sha256$260000$seasalt$!!!",
+			expErr:   password.ErrInvalidHash,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			verifier := password.NewPBKDF2SHA256()
+			assert.True(t, verifier.Identify(tc.encoded))
+			assert.ErrorIs(t, verifier.Check(tc.encoded), tc.expErr)
+
+			ok, err := verifier.Verify(tc.password, tc.encoded)
+			assert.Equal(t, tc.expOK, ok)
+			assert.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package password
+
+//go:generate mockery --name BreachList --output ./mocks --outpkg mocks --filename breach_list.go --structname BreachList
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxBytes is the longest password bcrypt can hash. Longer passwords are rejected instead of being truncated.
+const MaxBytes = 72
+
+// Codes of policy violations.
+const (
+	ViolationTooShort         = "too_short"
+	ViolationTooLong          = "too_long"
+	ViolationMissingLowercase = "missing_lowercase"
+	ViolationMissingUppercase = "missing_uppercase"
+	ViolationMissingDigit     = "missing_digit"
+	ViolationMissingSymbol    = "missing_symbol"
+	ViolationContainsEmail    = "contains_email"
+	ViolationTooWeak          = "too_weak"
+	ViolationBreached         = "breached"
+)
+
+// minEmailPartLength is the shortest local part of an email address that passwords must not contain,
+// so that short local parts like "jo" do not ban every password with these letters.
+const minEmailPartLength = 3
+
+// NewPolicy creates a new *Policy instance enforcing the rules. Passwords found in breaches are rejected
+// unless breaches is nil.
+func NewPolicy(rules Rules, breaches BreachList) *Policy {
+	return &Policy{rules: rules, breaches: breaches}
+}
+
+type (
+	// Policy checks new passwords against configurable rules.
+	Policy struct {
+		rules    Rules
+		breaches BreachList
+	}
+
+	// BreachList defines methods for checking whether a password is known from data breaches.
+	BreachList interface {
+		Contains(password string) (bool, error)
+	}
+
+	// Rules are the requirements of a Policy. Zero values disable the corresponding check.
+	// Length is measured in characters; passwords longer than MaxBytes bytes are always rejected.
+	Rules struct {
+		MinLength        int
+		MaxLength        int
+		RequireLowercase bool
+		RequireUppercase bool
+		RequireDigit     bool
+		RequireSymbol    bool
+		ForbidEmail      bool
+		MinStrength      Score
+	}
+
+	// Violation describes a rule the password does not satisfy. Params hold the limits of the rule,
+	// so that clients can render their own messages.
+	Violation struct {
+		Code    string
+		Message string
+		Params  map[string]int
+	}
+)
+
+// Validate returns the rules the password of the user with the given email violates, or nil if there are none.
+// Returns an error if the breach list cannot be read.
+func (p *Policy) Validate(password string, email string) ([]Violation, error) {
+	var violations []Violation
+
+	length := utf8.RuneCountInString(password)
+
+	if length < p.rules.MinLength {
+		violations = append(violations, Violation{
+			Code:    ViolationTooShort,
+			Message: fmt.Sprintf("must be at least %d characters long", p.rules.MinLength),
+			Params:  map[string]int{"min": p.rules.MinLength},
+		})
+	}
+
+	switch {
+	case p.rules.MaxLength > 0 && length > p.rules.MaxLength:
+		violations = append(violations, Violation{
+			Code:    ViolationTooLong,
+			Message: fmt.Sprintf("must be at most %d characters long", p.rules.MaxLength),
+			Params:  map[string]int{"max": p.rules.MaxLength},
+		})
+	case len(password) > MaxBytes:
+		violations = append(violations, Violation{
+			Code:    ViolationTooLong,
+			Message: fmt.Sprintf("must be at most %d bytes long", MaxBytes),
+			Params:  map[string]int{"max_bytes": MaxBytes},
+		})
+	}
+
+	classes := classesOf(password)
+
+	if p.rules.RequireLowercase && !classes.lower {
+		violations = append(violations, Violation{Code: ViolationMissingLowercase, Message: "must contain a lowercase letter"})
+	}
+
+	if p.rules.RequireUppercase && !classes.upper {
+		violations = append(violations, Violation{Code: ViolationMissingUppercase, Message: "must contain an uppercase letter"})
+	}
+
+	if p.rules.RequireDigit && !classes.digit {
+		violations = append(violations, Violation{Code: ViolationMissingDigit, Message: "must contain a digit"})
+	}
+
+	if p.rules.RequireSymbol && !classes.symbol {
+		violations = append(violations, Violation{Code: ViolationMissingSymbol, Message: "must contain a symbol"})
+	}
+
+	if p.rules.ForbidEmail && containsEmail(password, email) {
+		violations = append(violations, Violation{Code: ViolationContainsEmail, Message: "must not contain the email address"})
+	}
+
+	if p.rules.MinStrength > 0 {
+		if score := Strength(password); score < p.rules.MinStrength {
+			violations = append(violations, Violation{
+				Code:    ViolationTooWeak,
+				Message: "is too easy to guess",
+				Params:  map[string]int{"score": int(score), "min_score": int(p.rules.MinStrength)},
+			})
+		}
+	}
+
+	if p.breaches != nil {
+		breached, err := p.breaches.Contains(password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check breached passwords: %w", err)
+		}
+
+		if breached {
+			violations = append(violations, Violation{Code: ViolationBreached, Message: "has appeared in a data breach"})
+		}
+	}
+
+	return violations, nil
+}
+
+// characterClasses reports which classes of characters a password contains.
+type characterClasses struct {
+	lower  bool
+	upper  bool
+	digit  bool
+	symbol bool
+	other  bool
+}
+
+// classesOf returns the classes of characters of the password. Letters without case, e.g. CJK, count as other.
+func classesOf(password string) characterClasses {
+	var classes characterClasses
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			classes.lower = true
+		case unicode.IsUpper(r):
+			classes.upper = true
+		case unicode.IsDigit(r):
+			classes.digit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			classes.symbol = true
+		default:
+			classes.other = true
+		}
+	}
+
+	return classes
+}
+
+// containsEmail reports whether the password contains the email address or its local part, ignoring case.
+func containsEmail(password string, email string) bool {
+	if email == "" {
+		return false
+	}
+
+	password, email = strings.ToLower(password), strings.ToLower(email)
+	if strings.Contains(password, email) {
+		return true
+	}
+
+	local, _, _ := strings.Cut(email, "@")
+	return utf8.RuneCountInString(local) >= minEmailPartLength && strings.Contains(password, local)
+}
@@ -0,0 +1,90 @@
+package password
+
+import (
+	"fmt"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+)
+
+const (
+	// DefPolicyName is the name of the *Policy definition.
+	DefPolicyName = "password.policy"
+
+	configKeyMinLength        = "auth.passwordPolicy.minLength"
+	configKeyMaxLength        = "auth.passwordPolicy.maxLength"
+	configKeyRequireLowercase = "auth.passwordPolicy.requireLowercase"
+	configKeyRequireUppercase = "auth.passwordPolicy.requireUppercase"
+	configKeyRequireDigit     = "auth.passwordPolicy.requireDigit"
+	configKeyRequireSymbol    = "auth.passwordPolicy.requireSymbol"
+	configKeyForbidEmail      = "auth.passwordPolicy.forbidEmail"
+	configKeyMinStrength      = "auth.passwordPolicy.minStrength"
+	configKeyBreachedDriver   = "auth.passwordPolicy.breached.driver"
+	configKeyBreachedBloom    = "auth.passwordPolicy.breached.bloom.path"
+	configKeyBreachedPrefix   = "auth.passwordPolicy.breached.prefix.dir"
+
+	breachedDriverBloom  = "bloom"
+	breachedDriverPrefix = "prefix"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Policy]{
+			Name: DefPolicyName,
+			Build: func(ctn di.Container) (*Policy, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var minLength int
+				if minLength = cfg.GetInt(configKeyMinLength); minLength == 0 {
+					return nil, config.NewErrMissingKey(configKeyMinLength)
+				}
+
+				var maxLength int
+				if maxLength = cfg.GetInt(configKeyMaxLength); maxLength == 0 {
+					return nil, config.NewErrMissingKey(configKeyMaxLength)
+				}
+
+				var breaches BreachList
+				switch driver := cfg.GetString(configKeyBreachedDriver); driver {
+				case breachedDriverBloom:
+					var path string
+					if path = cfg.GetString(configKeyBreachedBloom); path == "" {
+						return nil, config.NewErrMissingKey(configKeyBreachedBloom)
+					}
+
+					filter, err := LoadBloomFilter(path)
+					if err != nil {
+						return nil, err
+					}
+
+					breaches = filter
+				case breachedDriverPrefix:
+					var dir string
+					if dir = cfg.GetString(configKeyBreachedPrefix); dir == "" {
+						return nil, config.NewErrMissingKey(configKeyBreachedPrefix)
+					}
+
+					breaches = NewHashPrefixDataset(dir)
+				case "":
+					// breached passwords are not checked
+				default:
+					return nil, fmt.Errorf("unknown breached passwords driver %q", driver)
+				}
+
+				return NewPolicy(Rules{
+					MinLength:        minLength,
+					MaxLength:        maxLength,
+					RequireLowercase: cfg.GetBool(configKeyRequireLowercase),
+					RequireUppercase: cfg.GetBool(configKeyRequireUppercase),
+					RequireDigit:     cfg.GetBool(configKeyRequireDigit),
+					RequireSymbol:    cfg.GetBool(configKeyRequireSymbol),
+					ForbidEmail:      cfg.GetBool(configKeyForbidEmail),
+					MinStrength:      Score(cfg.GetInt(configKeyMinStrength)),
+				}, breaches), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,185 @@
+package password_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+	"github.com/riabininkf/http-auth-example/internal/password/mocks"
+)
+
+func TestPolicy_Validate(t *testing.T) {
+	testCases := map[string]struct {
+		rules    password.Rules
+		password string
+		email    string
+		expCodes []string
+	}{
+		"no violations": {
+			rules:    password.Rules{MinLength: 8, MaxLength: 64},
+			password: "long enough",
+		},
+		"too short": {
+			rules:    password.Rules{MinLength: 8, MaxLength: 64},
+			password: "short",
+			expCodes: []string{password.ViolationTooShort},
+		},
+		"length is counted in characters": {
+			rules:    password.Rules{MinLength: 4, MaxLength: 4},
+			password: "пароль"[:8],
+		},
+		"too long": {
+			rules:    password.Rules{MinLength: 8, MaxLength: 10},
+			password: "eleven chars",
+			expCodes: []string{password.ViolationTooLong},
+		},
+		"longer than bcrypt allows": {
+			rules:    password.Rules{MinLength: 8, MaxLength: 100},
+			password: strings.Repeat("ж", 40),
+			expCodes: []string{password.ViolationTooLong},
+		},
+		"missing character classes": {
+			rules: password.Rules{
+				MinLength:        8,
+				MaxLength:        64,
+				RequireLowercase: true,
+				RequireUppercase: true,
+				RequireDigit:     true,
+				RequireSymbol:    true,
+			},
+			password: "ALLUPPERCASE",
+			expCodes: []string{
+				password.ViolationMissingLowercase,
+				password.ViolationMissingDigit,
+				password.ViolationMissingSymbol,
+			},
+		},
+		"all character classes": {
+			rules: password.Rules{
+				MinLength:        8,
+				MaxLength:        64,
+				RequireLowercase: true,
+				RequireUppercase: true,
+				RequireDigit:     true,
+				RequireSymbol:    true,
+			},
+			password: "Abcdef1!",
+		},
+		"contains email": {
+			rules:    password.Rules{MinLength: 8, MaxLength: 64, ForbidEmail: true},
+			password: "my User@Example.com password",
+			email:    "user@example.com",
+			expCodes: []string{password.ViolationContainsEmail},
+		},
+		"contains local part of email": {
+			rules:    password.Rules{MinLength: 8, MaxLength: 64, ForbidEmail: true},
+			password: "john.doe-2024",
+			email:    "John.Doe@example.com",
+			expCodes: []string{password.ViolationContainsEmail},
+		},
+		"short local part of email is allowed": {
+			rules:    password.Rules{MinLength: 8, MaxLength: 64, ForbidEmail: true},
+			password: "jolly good fellow",
+			email:    "jo@example.com",
+		},
+		"email is allowed": {
+			rules:    password.Rules{MinLength: 8, MaxLength: 64},
+			password: "user@example.com",
+			email:    "user@example.com",
+		},
+		"too weak": {
+			rules:    password.Rules{MinLength: 8, MaxLength: 64, MinStrength: password.ScoreFair},
+			password: "password",
+			expCodes: []string{password.ViolationTooWeak},
+		},
+		"strong enough": {
+			rules:    password.Rules{MinLength: 8, MaxLength: 64, MinStrength: password.ScoreFair},
+			password: "Tr0ub4dor&3",
+		},
+		"several violations": {
+			rules:    password.Rules{MinLength: 8, MaxLength: 64, RequireDigit: true, MinStrength: password.ScoreFair},
+			password: "aaaa",
+			expCodes: []string{password.ViolationTooShort, password.ViolationMissingDigit, password.ViolationTooWeak},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			violations, err := password.NewPolicy(tc.rules, nil).Validate(tc.password, tc.email)
+			assert.NoError(t, err)
+
+			var codes []string
+			for _, violation := range violations {
+				codes = append(codes, violation.Code)
+				assert.NotEmpty(t, violation.Message)
+			}
+
+			assert.Equal(t, tc.expCodes, codes)
+		})
+	}
+}
+
+func TestPolicy_Validate_Params(t *testing.T) {
+	policy := password.NewPolicy(password.Rules{MinLength: 8, MaxLength: 64, MinStrength: password.ScoreStrong}, nil)
+
+	violations, err := policy.Validate("abc", "")
+	assert.NoError(t, err)
+
+	if !assert.Len(t, violations, 2) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, map[string]int{"min": 8}, violations[0].Params)
+	assert.Equal(t, map[string]int{"score": int(password.ScoreVeryWeak), "min_score": int(password.ScoreStrong)}, violations[1].Params)
+
+	violations, err = policy.Validate(strings.Repeat("ab1!", 19), "")
+	assert.NoError(t, err)
+
+	if !assert.Len(t, violations, 1) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, password.ViolationTooLong, violations[0].Code)
+	assert.Equal(t, map[string]int{"max": 64}, violations[0].Params)
+}
+
+func TestPolicy_Validate_Breaches(t *testing.T) {
+	rules := password.Rules{MinLength: 8, MaxLength: 64}
+
+	testCases := map[string]struct {
+		onContains func() (bool, error)
+		expCodes   []string
+		expErr     error
+	}{
+		"failed to check breaches": {
+			onContains: func() (bool, error) { return false, assert.AnError },
+			expErr:     assert.AnError,
+		},
+		"not breached": {
+			onContains: func() (bool, error) { return false, nil },
+		},
+		"breached": {
+			onContains: func() (bool, error) { return true, nil },
+			expCodes:   []string{password.ViolationBreached},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			breaches := mocks.NewBreachList(t)
+			breaches.On("Contains", "long enough").Return(tc.onContains())
+
+			violations, err := password.NewPolicy(rules, breaches).Validate("long enough", "")
+			assert.ErrorIs(t, err, tc.expErr)
+
+			var codes []string
+			for _, violation := range violations {
+				codes = append(codes, violation.Code)
+			}
+
+			assert.Equal(t, tc.expCodes, codes)
+		})
+	}
+}
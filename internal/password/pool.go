@@ -0,0 +1,140 @@
+package password
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrBusy is returned when all hashing slots are taken and the queue is full, so that the caller can shed load
+// instead of piling up requests that would all time out.
+var ErrBusy = errors.New("password hashing is saturated")
+
+// NewPool creates a new *Pool instance running at most concurrency hashes at a time. Up to queueDepth more
+// callers wait for a free slot, any others get ErrBusy right away.
+func NewPool(hasher *Hasher, concurrency int, queueDepth int) *Pool {
+	return &Pool{
+		hasher:     hasher,
+		slots:      make(chan struct{}, concurrency),
+		queueDepth: int64(queueDepth),
+	}
+}
+
+type (
+	// Pool bounds the CPU spent on password hashing. Hashes are slow on purpose, so without a bound a flood of
+	// logins would take every CPU and starve all other requests.
+	Pool struct {
+		hasher     *Hasher
+		slots      chan struct{}
+		queueDepth int64
+
+		queued    atomic.Int64
+		running   atomic.Int64
+		rejected  atomic.Uint64
+		completed atomic.Uint64
+		queueWait atomic.Int64
+		hashTime  atomic.Int64
+	}
+
+	// PoolStats is a snapshot of the load of a Pool. Durations are totals, so that rates and averages can be
+	// derived from two snapshots.
+	PoolStats struct {
+		Concurrency      int     `json:"concurrency"`
+		QueueDepth       int     `json:"queue_depth"`
+		Running          int64   `json:"running"`
+		Queued           int64   `json:"queued"`
+		RejectedTotal    uint64  `json:"rejected_total"`
+		CompletedTotal   uint64  `json:"completed_total"`
+		QueueWaitSeconds float64 `json:"queue_wait_seconds_total"`
+		HashSeconds      float64 `json:"hash_seconds_total"`
+	}
+)
+
+// Hash returns the encoded hash of the password produced by the current scheme.
+// Returns ErrBusy if the pool is saturated and the error of ctx if it is done while waiting in the queue.
+func (p *Pool) Hash(ctx context.Context, password string) (string, error) {
+	var (
+		encoded string
+		err     error
+	)
+	if runErr := p.run(ctx, func() { encoded, err = p.hasher.Hash(password) }); runErr != nil {
+		return "", runErr
+	}
+
+	return encoded, err
+}
+
+// Verify reports whether the password matches the encoded hash.
+// Returns ErrBusy if the pool is saturated, the error of ctx if it is done while waiting in the queue
+// and ErrUnknownHash if no verifier recognizes the hash.
+func (p *Pool) Verify(ctx context.Context, password string, encoded string) (bool, error) {
+	var (
+		ok  bool
+		err error
+	)
+	if runErr := p.run(ctx, func() { ok, err = p.hasher.Verify(password, encoded) }); runErr != nil {
+		return false, runErr
+	}
+
+	return ok, err
+}
+
+// NeedsRehash reports whether the encoded hash should be replaced with a hash of the current scheme.
+// It does not hash anything, so it does not take a slot.
+func (p *Pool) NeedsRehash(encoded string) bool {
+	return p.hasher.NeedsRehash(encoded)
+}
+
+// Stats returns the current load of the pool.
+func (p *Pool) Stats() PoolStats {
+	return PoolStats{
+		Concurrency:      cap(p.slots),
+		QueueDepth:       int(p.queueDepth),
+		Running:          p.running.Load(),
+		Queued:           p.queued.Load(),
+		RejectedTotal:    p.rejected.Load(),
+		CompletedTotal:   p.completed.Load(),
+		QueueWaitSeconds: time.Duration(p.queueWait.Load()).Seconds(),
+		HashSeconds:      time.Duration(p.hashTime.Load()).Seconds(),
+	}
+}
+
+// run calls fn once a slot is free, waiting in the queue if there is room in it. Callers whose ctx is done
+// leave the queue, so that requests the client gave up on do not take slots.
+func (p *Pool) run(ctx context.Context, fn func()) error {
+	start := time.Now()
+
+	select {
+	case p.slots <- struct{}{}:
+	default:
+		if p.queued.Add(1) > p.queueDepth {
+			p.queued.Add(-1)
+			p.rejected.Add(1)
+			return ErrBusy
+		}
+
+		select {
+		case p.slots <- struct{}{}:
+			p.queued.Add(-1)
+		case <-ctx.Done():
+			p.queued.Add(-1)
+			return ctx.Err()
+		}
+	}
+
+	p.queueWait.Add(int64(time.Since(start)))
+	p.running.Add(1)
+
+	defer func() {
+		p.running.Add(-1)
+		<-p.slots
+	}()
+
+	hashStart := time.Now()
+	fn()
+	p.hashTime.Add(int64(time.Since(hashStart)))
+	p.completed.Add(1)
+
+	return nil
+}
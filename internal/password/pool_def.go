@@ -0,0 +1,63 @@
+package password
+
+import (
+	"expvar"
+	"fmt"
+	"runtime"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+)
+
+const (
+	// DefPoolName is the name of the *Pool definition.
+	DefPoolName = "password.pool"
+
+	configKeyPoolConcurrency = "auth.passwordHashing.pool.concurrency"
+	configKeyPoolQueueDepth  = "auth.passwordHashing.pool.queueDepth"
+
+	// poolMetricsName is the name the pool stats are published under in /debug/vars.
+	poolMetricsName = "password_hashing"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Pool]{
+			Name: DefPoolName,
+			Build: func(ctn di.Container) (*Pool, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var hasher *Hasher
+				if err := ctn.Fill(DefHasherName, &hasher); err != nil {
+					return nil, err
+				}
+
+				concurrency := runtime.NumCPU()
+				if cfg.IsSet(configKeyPoolConcurrency) {
+					concurrency = cfg.GetInt(configKeyPoolConcurrency)
+				}
+
+				if concurrency < 1 {
+					return nil, fmt.Errorf("password hashing concurrency must be positive, got %d", concurrency)
+				}
+
+				var queueDepth int
+				if queueDepth = cfg.GetInt(configKeyPoolQueueDepth); queueDepth < 0 {
+					return nil, fmt.Errorf("password hashing queue depth must not be negative, got %d", queueDepth)
+				}
+
+				pool := NewPool(hasher, concurrency, queueDepth)
+
+				// published once per process, the definition is a singleton
+				if expvar.Get(poolMetricsName) == nil {
+					expvar.Publish(poolMetricsName, expvar.Func(func() any { return pool.Stats() }))
+				}
+
+				return pool, nil
+			},
+		},
+	)
+}
@@ -0,0 +1,129 @@
+package password_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+// blockingScheme hashes only once release is closed, so that tests can fill the pool.
+type blockingScheme struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingScheme) Identify(string) bool { return true }
+
+func (s *blockingScheme) Check(string) error { return nil }
+
+func (s *blockingScheme) Verify(string, string) (bool, error) {
+	s.started <- struct{}{}
+	<-s.release
+	return true, nil
+}
+
+func (s *blockingScheme) Hash(string) (string, error) {
+	s.started <- struct{}{}
+	<-s.release
+	return "hash", nil
+}
+
+func (s *blockingScheme) NeedsRehash(string) bool { return false }
+
+func TestPool(t *testing.T) {
+	t.Run("hashes and verifies", func(t *testing.T) {
+		pool := password.NewPool(password.NewHasher(password.NewBcrypt(bcrypt.MinCost)), 1, 0)
+
+		encoded, err := pool.Hash(t.Context(), "password")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		ok, err := pool.Verify(t.Context(), "password", encoded)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.False(t, pool.NeedsRehash(encoded))
+
+		stats := pool.Stats()
+		assert.Equal(t, uint64(2), stats.CompletedTotal)
+		assert.Positive(t, stats.HashSeconds)
+		assert.Zero(t, stats.Running)
+	})
+
+	t.Run("rejects when saturated", func(t *testing.T) {
+		scheme := &blockingScheme{started: make(chan struct{}), release: make(chan struct{})}
+		pool := password.NewPool(password.NewHasher(scheme), 2, 1)
+
+		var wg sync.WaitGroup
+		for range 3 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := pool.Hash(t.Context(), "password")
+				assert.NoError(t, err)
+			}()
+		}
+
+		// two hashes run, the third waits in the queue
+		<-scheme.started
+		<-scheme.started
+		assert.Eventually(t, func() bool { return pool.Stats().Queued == 1 }, time.Second, time.Millisecond)
+
+		_, err := pool.Verify(t.Context(), "password", "hash")
+		assert.ErrorIs(t, err, password.ErrBusy)
+
+		stats := pool.Stats()
+		assert.Equal(t, int64(2), stats.Running)
+		assert.Equal(t, uint64(1), stats.RejectedTotal)
+
+		close(scheme.release)
+		<-scheme.started
+		wg.Wait()
+
+		stats = pool.Stats()
+		assert.Equal(t, uint64(3), stats.CompletedTotal)
+		assert.Zero(t, stats.Queued)
+		assert.Zero(t, stats.Running)
+		assert.Positive(t, stats.QueueWaitSeconds)
+	})
+	t.Run("leaves the queue when the context is done", func(t *testing.T) {
+		scheme := &blockingScheme{started: make(chan struct{}), release: make(chan struct{})}
+		pool := password.NewPool(password.NewHasher(scheme), 1, 1)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			_, err := pool.Hash(t.Context(), "password")
+			assert.NoError(t, err)
+		}()
+
+		<-scheme.started
+
+		ctx, cancel := context.WithCancel(t.Context())
+
+		errs := make(chan error)
+		go func() {
+			_, err := pool.Verify(ctx, "password", "hash")
+			errs <- err
+		}()
+
+		assert.Eventually(t, func() bool { return pool.Stats().Queued == 1 }, time.Second, time.Millisecond)
+
+		cancel()
+		assert.ErrorIs(t, <-errs, context.Canceled)
+		assert.Zero(t, pool.Stats().Queued)
+
+		close(scheme.release)
+		<-done
+
+		stats := pool.Stats()
+		assert.Equal(t, uint64(1), stats.CompletedTotal)
+		assert.Zero(t, stats.Running)
+	})
+}
@@ -0,0 +1,101 @@
+package password
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const scryptPrefix = "$scrypt$"
+
+const (
+	// maxScryptLogN and maxScryptMemory cap the CPU/memory cost of stored scrypt hashes, so that a corrupted
+	// or imported hash cannot make a single verification allocate gigabytes. scrypt takes 128*N*r bytes.
+	maxScryptLogN   = 20
+	maxScryptMemory = 1 << 30
+
+	// maxScryptParallelism caps p, as every unit of it repeats the whole computation.
+	maxScryptParallelism = 16
+)
+
+// NewScrypt creates a new *Scrypt instance.
+func NewScrypt() *Scrypt {
+	return &Scrypt{}
+}
+
+type (
+	// Scrypt verifies legacy scrypt hashes in the passlib format "$scrypt$ln=14,r=8,p=1$salt$checksum",
+	// where the cost parameter N is 2^ln.
+	Scrypt struct{}
+
+	// scryptHash is a decoded scrypt hash.
+	scryptHash struct {
+		logN     int
+		r        int
+		p        int
+		salt     []byte
+		checksum []byte
+	}
+)
+
+// Identify reports whether the encoded hash is a scrypt hash.
+func (s *Scrypt) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, scryptPrefix)
+}
+
+// Check returns ErrInvalidHash if the scrypt hash cannot be decoded or its cost is too high.
+func (s *Scrypt) Check(encoded string) error {
+	_, err := decodeScrypt(encoded)
+	return err
+}
+
+// Verify reports whether the password matches the scrypt hash.
+func (s *Scrypt) Verify(password string, encoded string) (bool, error) {
+	hash, err := decodeScrypt(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	var key []byte
+	if key, err = scrypt.Key([]byte(password), hash.salt, 1<<hash.logN, hash.r, hash.p, len(hash.checksum)); err != nil {
+		return false, fmt.Errorf("failed to derive scrypt key: %w", err)
+	}
+
+	return subtle.ConstantTimeCompare(key, hash.checksum) == 1, nil
+}
+
+// decodeScrypt parses a scrypt hash in the passlib format.
+func decodeScrypt(encoded string) (scryptHash, error) {
+	var hash scryptHash
+
+	// "", "scrypt", "ln=14,r=8,p=1", salt, checksum
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 || parts[1] != "scrypt" {
+		return hash, fmt.Errorf("%w: malformed scrypt hash", ErrInvalidHash)
+	}
+
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &hash.logN, &hash.r, &hash.p); err != nil {
+		return hash, fmt.Errorf("%w: invalid scrypt parameters: %w", ErrInvalidHash, err)
+	}
+
+	if hash.logN <= 0 || hash.r <= 0 || hash.p <= 0 {
+		return hash, fmt.Errorf("%w: invalid scrypt parameters %q", ErrInvalidHash, parts[2])
+	}
+
+	if hash.logN > maxScryptLogN || hash.r > maxScryptMemory/(128<<hash.logN) || hash.p > maxScryptParallelism {
+		return hash, fmt.Errorf("%w: scrypt cost %q is too high", ErrInvalidHash, parts[2])
+	}
+
+	var err error
+	if hash.salt, err = adaptedBase64.DecodeString(parts[3]); err != nil {
+		return hash, fmt.Errorf("%w: invalid scrypt salt: %w", ErrInvalidHash, err)
+	}
+
+	if hash.checksum, err = adaptedBase64.DecodeString(parts[4]); err != nil || len(hash.checksum) == 0 {
+		return hash, fmt.Errorf("%w: invalid scrypt checksum", ErrInvalidHash)
+	}
+
+	return hash, nil
+}
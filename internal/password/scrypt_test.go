@@ -0,0 +1,77 @@
+package password_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestScrypt_Verify(t *testing.T) {
+	const (
+		// the test vector of RFC 7914 with N=1024, r=8, p=16 in the passlib format
+		rfcHash = "$scrypt$ln=10,r=8,p=16$TmFDbA$/bq.HJ00cgB4VucZDQHp/nxq18vII3gw53N2Y0s3MWIurzDZLiKjiG/xCSedmDDaxyevuUqD7m2DYMvfoswGQA"
+		// computed with Python's hashlib.scrypt
+		passlibHash = "$scrypt$ln=14,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$GM/8plVTNY2Jr5.H.TMEUW0SMD0/pCcA0XgAgW7i7jw"
+	)
+
+	testCases := map[string]struct {
+		password string
+		encoded  string
+		expOK    bool
+		expErr   error
+	}{
+		"rfc 7914 vector": {
+			password: "password",
+			encoded:  rfcHash,
+			expOK:    true,
+		},
+		"known answer": {
+			password: "password",
+			encoded:  passlibHash,
+			expOK:    true,
+		},
+		"wrong password": {
+			password: "passw0rd",
+			encoded:  passlibHash,
+		},
+		"malformed hash": {
+			password: "password",
+			encoded:  "$scrypt$ln=14,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA",
+			expErr:   password.ErrInvalidHash,
+		},
+		"cost is too high": {
+			password: "password",
+			encoded:  "$scrypt$ln=30,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$GM/8plVTNY2Jr5.H.TMEUW0SMD0/pCcA0XgAgW7i7jw",
+			expErr:   password.ErrInvalidHash,
+		},
+		"block size is too high": {
+			password: "password",
+			encoded:  "$scrypt$ln=20,r=4194304,p=1$c2FsdHNhbHRzYWx0c2FsdA$GM/8plVTNY2Jr5.H.TMEUW0SMD0/pCcA0XgAgW7i7jw",
+			expErr:   password.ErrInvalidHash,
+		},
+		"parallelism is too high": {
+			password: "password",
+			encoded:  "$scrypt$ln=14,r=8,p=1024$c2FsdHNhbHRzYWx0c2FsdA$GM/8plVTNY2Jr5.H.TMEUW0SMD0/pCcA0XgAgW7i7jw",
+			expErr:   password.ErrInvalidHash,
+		},
+		"invalid salt": {
+			password: "password",
+			encoded:  "$scrypt$ln=14,r=8,p=1$!!!$GM/8plVTNY2Jr5.H.TMEUW0SMD0/pCcA0XgAgW7i7jw",
+			expErr:   password.ErrInvalidHash,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			verifier := password.NewScrypt()
+			assert.True(t, verifier.Identify(tc.encoded))
+			assert.ErrorIs(t, verifier.Check(tc.encoded), tc.expErr)
+
+			ok, err := verifier.Verify(tc.password, tc.encoded)
+			assert.Equal(t, tc.expOK, ok)
+			assert.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
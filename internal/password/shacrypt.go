@@ -0,0 +1,238 @@
+package password
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+const (
+	shaCrypt256Prefix = "$5$"
+	shaCrypt512Prefix = "$6$"
+
+	shaCryptRoundsPrefix  = "rounds="
+	shaCryptDefaultRounds = 5000
+	shaCryptMinRounds     = 1000
+	shaCryptMaxSalt       = 16
+
+	// shaCryptMaxRounds caps the cost of stored hashes. crypt(3) accepts up to 999999999 rounds, which take
+	// minutes to verify, so hashes with more than this are refused instead.
+	shaCryptMaxRounds = 10_000_000
+
+	// Lengths of the encoded checksums, four characters for every three bytes of the digest.
+	shaCrypt256ChecksumLength = 43
+	shaCrypt512ChecksumLength = 86
+
+	// shaCryptAlphabet is the base64 alphabet of crypt(3).
+	shaCryptAlphabet = "./0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// Orders in which SHA-crypt encodes the bytes of the digest, three at a time.
+var (
+	shaCrypt256Order = []int{
+		0, 10, 20, 21, 1, 11, 12, 22, 2, 3, 13, 23, 24, 4, 14,
+		15, 25, 5, 6, 16, 26, 27, 7, 17, 18, 28, 8, 9, 19, 29,
+		-1, 31, 30,
+	}
+	shaCrypt512Order = []int{
+		0, 21, 42, 22, 43, 1, 44, 2, 23, 3, 24, 45, 25, 46, 4,
+		47, 5, 26, 6, 27, 48, 28, 49, 7, 50, 8, 29, 9, 30, 51,
+		31, 52, 10, 53, 11, 32, 12, 33, 54, 34, 55, 13, 56, 14, 35,
+		15, 36, 57, 37, 58, 16, 59, 17, 38, 18, 39, 60, 40, 61, 19,
+		62, 20, 41, -1, -1, 63,
+	}
+)
+
+// NewSHACrypt creates a new *SHACrypt instance.
+func NewSHACrypt() *SHACrypt {
+	return &SHACrypt{}
+}
+
+type (
+	// SHACrypt verifies legacy SHA-256 and SHA-512 crypt(3) hashes like "$5$rounds=5000$salt$checksum"
+	// and "$6$salt$checksum", as specified by Ulrich Drepper and used by glibc.
+	SHACrypt struct{}
+
+	// shaCryptHash is a decoded SHA-crypt hash.
+	shaCryptHash struct {
+		newHash  func() hash.Hash
+		order    []int
+		rounds   int
+		salt     string
+		checksum string
+	}
+)
+
+// Identify reports whether the encoded hash is a SHA-crypt hash.
+func (s *SHACrypt) Identify(encoded string) bool {
+	return strings.HasPrefix(encoded, shaCrypt256Prefix) || strings.HasPrefix(encoded, shaCrypt512Prefix)
+}
+
+// Check returns ErrInvalidHash if the SHA-crypt hash cannot be decoded.
+func (s *SHACrypt) Check(encoded string) error {
+	_, err := decodeSHACrypt(encoded)
+	return err
+}
+
+// Verify reports whether the password matches the SHA-crypt hash.
+func (s *SHACrypt) Verify(password string, encoded string) (bool, error) {
+	hash, err := decodeSHACrypt(encoded)
+	if err != nil {
+		return false, err
+	}
+
+	digest := shaCrypt(hash.newHash, []byte(password), []byte(hash.salt), hash.rounds)
+
+	return subtle.ConstantTimeCompare([]byte(encodeSHACrypt(digest, hash.order)), []byte(hash.checksum)) == 1, nil
+}
+
+// decodeSHACrypt parses a SHA-crypt hash. Too few rounds are raised to the minimum and long salts are truncated,
+// as crypt(3) does.
+func decodeSHACrypt(encoded string) (shaCryptHash, error) {
+	hash := shaCryptHash{rounds: shaCryptDefaultRounds}
+
+	var checksumLength int
+	switch {
+	case strings.HasPrefix(encoded, shaCrypt256Prefix):
+		hash.newHash, hash.order, checksumLength = sha256.New, shaCrypt256Order, shaCrypt256ChecksumLength
+	case strings.HasPrefix(encoded, shaCrypt512Prefix):
+		hash.newHash, hash.order, checksumLength = sha512.New, shaCrypt512Order, shaCrypt512ChecksumLength
+	default:
+		return hash, fmt.Errorf("%w: not a sha-crypt hash", ErrInvalidHash)
+	}
+
+	// "rounds=5000", salt, checksum or salt, checksum
+	fields := strings.Split(encoded[len(shaCrypt256Prefix):], "$")
+
+	if len(fields) == 3 && strings.HasPrefix(fields[0], shaCryptRoundsPrefix) {
+		var err error
+		if hash.rounds, err = strconv.Atoi(strings.TrimPrefix(fields[0], shaCryptRoundsPrefix)); err != nil {
+			return hash, fmt.Errorf("%w: invalid sha-crypt rounds: %w", ErrInvalidHash, err)
+		}
+
+		if hash.rounds > shaCryptMaxRounds {
+			return hash, fmt.Errorf("%w: sha-crypt rounds %d are too many", ErrInvalidHash, hash.rounds)
+		}
+
+		hash.rounds = max(hash.rounds, shaCryptMinRounds)
+		fields = fields[1:]
+	}
+
+	if len(fields) != 2 {
+		return hash, fmt.Errorf("%w: malformed sha-crypt hash", ErrInvalidHash)
+	}
+
+	hash.salt, hash.checksum = fields[0], fields[1]
+	if len(hash.salt) > shaCryptMaxSalt {
+		hash.salt = hash.salt[:shaCryptMaxSalt]
+	}
+
+	if len(hash.checksum) != checksumLength || strings.Trim(hash.checksum, shaCryptAlphabet) != "" {
+		return hash, fmt.Errorf("%w: invalid sha-crypt checksum", ErrInvalidHash)
+	}
+
+	return hash, nil
+}
+
+// shaCrypt computes the SHA-crypt digest of the password.
+func shaCrypt(newHash func() hash.Hash, password []byte, salt []byte, rounds int) []byte {
+	h := newHash()
+	h.Write(password)
+	h.Write(salt)
+	h.Write(password)
+	alternate := h.Sum(nil)
+
+	h.Reset()
+	h.Write(password)
+	h.Write(salt)
+	h.Write(repeatTo(alternate, len(password)))
+	for n := len(password); n > 0; n >>= 1 {
+		if n&1 != 0 {
+			h.Write(alternate)
+		} else {
+			h.Write(password)
+		}
+	}
+	digest := h.Sum(nil)
+
+	h.Reset()
+	for range password {
+		h.Write(password)
+	}
+	passwordSequence := repeatTo(h.Sum(nil), len(password))
+
+	h.Reset()
+	for range 16 + int(digest[0]) {
+		h.Write(salt)
+	}
+	saltSequence := repeatTo(h.Sum(nil), len(salt))
+
+	for i := range rounds {
+		h.Reset()
+
+		if i%2 != 0 {
+			h.Write(passwordSequence)
+		} else {
+			h.Write(digest)
+		}
+
+		if i%3 != 0 {
+			h.Write(saltSequence)
+		}
+
+		if i%7 != 0 {
+			h.Write(passwordSequence)
+		}
+
+		if i%2 != 0 {
+			h.Write(digest)
+		} else {
+			h.Write(passwordSequence)
+		}
+
+		digest = h.Sum(digest[:0])
+	}
+
+	return digest
+}
+
+// repeatTo repeats the bytes of sequence until they are length bytes long.
+func repeatTo(sequence []byte, length int) []byte {
+	result := make([]byte, 0, length)
+	for len(result) < length {
+		result = append(result, sequence[:min(len(sequence), length-len(result))]...)
+	}
+
+	return result
+}
+
+// encodeSHACrypt encodes the digest with the crypt(3) base64 alphabet, taking its bytes three at a time in the order
+// given. -1 stands for a zero byte and shortens the group to as many characters as its bytes need.
+func encodeSHACrypt(digest []byte, order []int) string {
+	var sb strings.Builder
+	for i := 0; i < len(order); i += 3 {
+		var (
+			value uint32
+			bits  int
+		)
+
+		for _, index := range order[i : i+3] {
+			value <<= 8
+			if index >= 0 {
+				value |= uint32(digest[index])
+				bits += 8
+			}
+		}
+
+		for chars := (bits + 5) / 6; chars > 0; chars-- {
+			sb.WriteByte(shaCryptAlphabet[value&0x3f])
+			value >>= 6
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,91 @@
+package password_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestSHACrypt_Verify(t *testing.T) {
+	// test vectors of the SHA-crypt specification, also produced by glibc crypt(3)
+	testCases := map[string]struct {
+		password string
+		encoded  string
+		expOK    bool
+		expErr   error
+	}{
+		"sha-256 with default rounds": {
+			password: "Hello world!",
+			encoded:  "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5",
+			expOK:    true,
+		},
+		"sha-256 with custom rounds and long salt": {
+			password: "Hello world!",
+			encoded:  "$5$rounds=10000$saltstringsaltst$3xv.VbSHBb41AL9AvLeujZkZRBAwqFMz2.opqey6IcA",
+			expOK:    true,
+		},
+		"sha-256 with truncated salt": {
+			password: "This is just a test",
+			encoded:  "$5$rounds=5000$toolongsaltstrin$Un/5jzAHMgOGZ5.mWJpuVolil07guHPvOW8mGRcvxa5",
+			expOK:    true,
+		},
+		"sha-512 with default rounds": {
+			password: "Hello world!",
+			encoded:  "$6$saltstring$svn8UoSVapNtMuq1ukKS4tPQd8iKwSMHWjl/O817G3uBnIFNjnQJuesI68u4OTLiBFdcbYEdFCoEOfaS35inz1",
+			expOK:    true,
+		},
+		"sha-512 with custom rounds": {
+			password: "Hello world!",
+			encoded:  "$6$rounds=10000$saltstringsaltst$OW1/O6BYHV6BcXZu8QVeXbDWra3Oeqh0sbHbbMCVNSnCM/UrjmM0Dp8vOuZeHBy/YTBmSK6H9qs/y3RnOaw5v.",
+			expOK:    true,
+		},
+		"sha-512 with long password": {
+			password: "a very much longer text to encrypt.  This one even stretches over morethan one line.",
+			encoded:  "$6$rounds=1400$anotherlongsalts$POfYwTEok97VWcjxIiSOjiykti.o/pQs.wPvMxQ6Fm7I6IoYN3CmLs66x9t0oSwbtEW7o7UmJEiDwGqd8p4ur1",
+			expOK:    true,
+		},
+		"sha-512 with short salt": {
+			password: "we have a short salt string but not a short password",
+			encoded:  "$6$rounds=77777$short$WuQyW2YR.hBNpjjRhpYD/ifIw05xdfeEyQoMxIXbkvr0gge1a1x3yRULJ5CCaUeOxFmtlcGZelFl5CxtgfiAc0",
+			expOK:    true,
+		},
+		"wrong password": {
+			password: "Hello world?",
+			encoded:  "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5",
+		},
+		"malformed hash": {
+			password: "Hello world!",
+			encoded:  "$6$saltstring",
+			expErr:   password.ErrInvalidHash,
+		},
+		"truncated checksum": {
+			password: "Hello world!",
+			encoded:  "$5$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc",
+			expErr:   password.ErrInvalidHash,
+		},
+		"too many rounds": {
+			password: "Hello world!",
+			encoded:  "$5$rounds=999999999$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5",
+			expErr:   password.ErrInvalidHash,
+		},
+		"invalid rounds": {
+			password: "Hello world!",
+			encoded:  "$5$rounds=many$saltstring$5B8vYYiY.CVt1RlTTf8KbXBH3hsxY/GNooZaBBGWEc5",
+			expErr:   password.ErrInvalidHash,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			verifier := password.NewSHACrypt()
+			assert.True(t, verifier.Identify(tc.encoded))
+			assert.ErrorIs(t, verifier.Check(tc.encoded), tc.expErr)
+
+			ok, err := verifier.Verify(tc.password, tc.encoded)
+			assert.Equal(t, tc.expOK, ok)
+			assert.ErrorIs(t, err, tc.expErr)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package password
+
+import (
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// Score estimates how hard a password is to guess, from ScoreVeryWeak to ScoreVeryStrong.
+type Score int
+
+// Scores returned by Strength.
+const (
+	ScoreVeryWeak Score = iota
+	ScoreWeak
+	ScoreFair
+	ScoreStrong
+	ScoreVeryStrong
+)
+
+// Minimal estimated entropy in bits of each score above ScoreVeryWeak.
+var scoreThresholds = [...]float64{
+	ScoreWeak:       28,
+	ScoreFair:       36,
+	ScoreStrong:     60,
+	ScoreVeryStrong: 80,
+}
+
+// Sizes of the alphabets the classes of characters are drawn from.
+const (
+	lowerAlphabet  = 26
+	upperAlphabet  = 26
+	digitAlphabet  = 10
+	symbolAlphabet = 33
+	otherAlphabet  = 100
+)
+
+// Strength estimates the strength of the password. The estimate is the entropy of a random string of
+// the password's alphabet and length, where characters repeating the previous one or continuing a sequence
+// like "abc" or "321" do not count, and common passwords are very weak regardless of it.
+func Strength(password string) Score {
+	if _, ok := commonPasswords[strings.ToLower(password)]; ok {
+		return ScoreVeryWeak
+	}
+
+	classes := classesOf(password)
+
+	var alphabet int
+	for _, class := range []struct {
+		present bool
+		size    int
+	}{
+		{classes.lower, lowerAlphabet},
+		{classes.upper, upperAlphabet},
+		{classes.digit, digitAlphabet},
+		{classes.symbol, symbolAlphabet},
+		{classes.other, otherAlphabet},
+	} {
+		if class.present {
+			alphabet += class.size
+		}
+	}
+
+	if alphabet == 0 {
+		return ScoreVeryWeak
+	}
+
+	entropy := float64(effectiveLength(password)) * math.Log2(float64(alphabet))
+
+	score := ScoreVeryWeak
+	for s := ScoreWeak; s <= ScoreVeryStrong; s++ {
+		if entropy >= scoreThresholds[s] {
+			score = s
+		}
+	}
+
+	return score
+}
+
+// effectiveLength counts the characters of the password that neither repeat the previous character
+// nor continue a sequence of consecutive code points.
+func effectiveLength(password string) int {
+	runes := make([]rune, 0, utf8.RuneCountInString(password))
+	for _, r := range strings.ToLower(password) {
+		runes = append(runes, r)
+	}
+
+	length := 0
+	for i, r := range runes {
+		switch {
+		case i > 0 && r == runes[i-1]:
+		case i > 1 && r-runes[i-1] == runes[i-1]-runes[i-2] && (r-runes[i-1] == 1 || r-runes[i-1] == -1):
+		default:
+			length++
+		}
+	}
+
+	return length
+}
+
+// commonPasswords are passwords from the top of public breach corpora. They are guessed first,
+// so they are very weak whatever their length and alphabet.
+var commonPasswords = map[string]struct{}{
+	"123456": {}, "123456789": {}, "12345678": {}, "password": {}, "qwerty": {}, "qwerty123": {},
+	"1q2w3e": {}, "1q2w3e4r": {}, "1q2w3e4r5t": {}, "12345": {}, "1234567": {}, "1234567890": {},
+	"111111": {}, "123123": {}, "000000": {}, "abc123": {}, "password1": {}, "password123": {},
+	"iloveyou": {}, "qwertyuiop": {}, "123321": {}, "654321": {}, "666666": {}, "987654321": {},
+	"dragon": {}, "monkey": {}, "letmein": {}, "football": {}, "baseball": {}, "welcome": {},
+	"welcome1": {}, "admin": {}, "admin123": {}, "login": {}, "princess": {}, "sunshine": {},
+	"master": {}, "shadow": {}, "superman": {}, "trustno1": {}, "passw0rd": {}, "p@ssw0rd": {},
+	"p@ssword": {}, "zaq12wsx": {}, "1qaz2wsx": {}, "qazwsx": {}, "asdfghjkl": {}, "asdfgh": {},
+	"zxcvbnm": {}, "michael": {}, "jennifer": {}, "charlie": {}, "starwars": {}, "whatever": {},
+	"freedom": {}, "hello123": {}, "changeme": {}, "secret": {}, "mustang": {}, "access": {},
+	"computer": {}, "internet": {}, "pokemon": {}, "soccer": {}, "hockey": {}, "killer": {},
+	"ashley": {}, "daniel": {}, "jordan23": {}, "michelle": {}, "liverpool": {}, "chelsea": {},
+	"qwerty1": {}, "aa123456": {}, "1234qwer": {}, "q1w2e3r4": {}, "q1w2e3r4t5y6": {}, "password!": {},
+	"correcthorsebatterystaple": {},
+}
@@ -0,0 +1,36 @@
+package password_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/password"
+)
+
+func TestStrength(t *testing.T) {
+	testCases := map[string]struct {
+		password string
+		expScore password.Score
+	}{
+		"empty":                     {password: "", expScore: password.ScoreVeryWeak},
+		"common password":           {password: "Password1", expScore: password.ScoreVeryWeak},
+		"long common password":      {password: "correcthorsebatterystaple", expScore: password.ScoreVeryWeak},
+		"repeated character":        {password: "aaaaaaaaaaaaaaaa", expScore: password.ScoreVeryWeak},
+		"sequence":                  {password: "abcdefghijklmnop", expScore: password.ScoreVeryWeak},
+		"descending digits":         {password: "9876543210", expScore: password.ScoreVeryWeak},
+		"short lowercase":           {password: "grumpy", expScore: password.ScoreWeak},
+		"lowercase words":           {password: "purple kitten", expScore: password.ScoreStrong},
+		"mixed classes":             {password: "Tr0ub4dor&3", expScore: password.ScoreStrong},
+		"long passphrase":           {password: "staple battery horse correct", expScore: password.ScoreVeryStrong},
+		"non-latin passphrase":      {password: "хорошая погода сегодня", expScore: password.ScoreVeryStrong},
+		"random with all classes":   {password: "x7!Kq_2zP@9mLw.e", expScore: password.ScoreVeryStrong},
+		"sequence with random tail": {password: "abcdefg9K!", expScore: password.ScoreWeak},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.expScore, password.Strength(tc.password))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package random
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"math/big"
+)
+
+// String returns a URL-safe base64 string encoding size cryptographically random bytes.
+func String(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// Digits returns a string of n cryptographically random decimal digits.
+func Digits(n int) (string, error) {
+	buf := make([]byte, n)
+	for i := range buf {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+
+		buf[i] = byte('0' + digit.Int64())
+	}
+
+	return string(buf), nil
+}
@@ -0,0 +1,108 @@
+package ratelimit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/riabininkf/httpx"
+)
+
+// maxFieldBodySize limits how much of the body is read to find a field, so that large bodies are not buffered twice.
+const maxFieldBodySize = 1 << 20
+
+// KeyFunc returns the key a request is counted under, or false if the rule does not apply to the request.
+type KeyFunc func(req *http.Request) (string, bool)
+
+// ByIP counts requests per client IP. With trustProxy the last address in X-Forwarded-For is used, which is
+// the one added by the proxy in front of the service. Enable it only behind a proxy, as clients can set the header.
+func ByIP(trustProxy bool) KeyFunc {
+	return func(req *http.Request) (string, bool) {
+		if trustProxy {
+			if header := req.Header.Get("X-Forwarded-For"); header != "" {
+				addrs := strings.Split(header, ",")
+				if addr := strings.TrimSpace(addrs[len(addrs)-1]); addr != "" {
+					return addr, true
+				}
+			}
+		}
+
+		host, _, err := net.SplitHostPort(req.RemoteAddr)
+		if err != nil {
+			return req.RemoteAddr, req.RemoteAddr != ""
+		}
+
+		return host, true
+	}
+}
+
+// ByUser counts requests per authenticated user. It does not apply to anonymous requests.
+func ByUser() KeyFunc {
+	return func(req *http.Request) (string, bool) {
+		userID, ok := httpx.GetUserID(req.Context())
+		return userID, ok && userID != ""
+	}
+}
+
+// ByField counts requests per value of a string field of the JSON or form body, e.g. the email of a login,
+// compared case-insensitively. It does not apply to requests without the field. The body is left intact
+// for the handler.
+func ByField(name string) KeyFunc {
+	return func(req *http.Request) (string, bool) {
+		if req.Body == nil || req.Body == http.NoBody {
+			return "", false
+		}
+
+		body, err := io.ReadAll(io.LimitReader(req.Body, maxFieldBodySize))
+		req.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(body), req.Body), Closer: req.Body}
+		if err != nil {
+			return "", false
+		}
+
+		var value string
+		if value, err = fieldValue(req.Header.Get("Content-Type"), body, name); err != nil {
+			return "", false
+		}
+
+		if value = strings.ToLower(strings.TrimSpace(value)); value == "" {
+			return "", false
+		}
+
+		return value, true
+	}
+}
+
+// fieldValue returns the value of the named field of a form body, or of a JSON body for any other content type.
+func fieldValue(contentType string, body []byte, name string) (string, error) {
+	if mediaType, _, _ := mime.ParseMediaType(contentType); mediaType == "application/x-www-form-urlencoded" {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return "", err
+		}
+
+		return form.Get(name), nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return "", err
+	}
+
+	var value string
+	if err := json.Unmarshal(fields[name], &value); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
+
+// readCloser reads a body that was partially read already and closes the original one.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,120 @@
+package ratelimit_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/riabininkf/httpx"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/ratelimit"
+)
+
+func TestByIP(t *testing.T) {
+	testCases := map[string]struct {
+		trustProxy   bool
+		forwardedFor string
+		expKey       string
+	}{
+		"remote address": {
+			expKey: "192.0.2.1",
+		},
+		"forwarded for is ignored without proxy": {
+			forwardedFor: "203.0.113.7",
+			expKey:       "192.0.2.1",
+		},
+		"last forwarded for behind proxy": {
+			trustProxy:   true,
+			forwardedFor: "198.51.100.3, 203.0.113.7",
+			expKey:       "203.0.113.7",
+		},
+		"remote address behind proxy without forwarded for": {
+			trustProxy: true,
+			expKey:     "192.0.2.1",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/auth/login", nil)
+			if tc.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tc.forwardedFor)
+			}
+
+			key, ok := ratelimit.ByIP(tc.trustProxy)(req)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expKey, key)
+		})
+	}
+}
+
+func TestByUser(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/v1/user/password", nil)
+
+	_, ok := ratelimit.ByUser()(req)
+	assert.False(t, ok)
+
+	key, ok := ratelimit.ByUser()(req.WithContext(httpx.ContextWithUserID(req.Context(), "user_id")))
+	assert.True(t, ok)
+	assert.Equal(t, "user_id", key)
+}
+
+func TestByField(t *testing.T) {
+	testCases := map[string]struct {
+		contentType string
+		body        string
+		expKey      string
+		expOk       bool
+	}{
+		"form field": {
+			contentType: "application/x-www-form-urlencoded",
+			body:        "email=+User%40Example.com+&password=password",
+			expKey:      "user@example.com",
+			expOk:       true,
+		},
+		"missing form field": {
+			contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			body:        "password=password",
+		},
+		"invalid form": {
+			contentType: "application/x-www-form-urlencoded",
+			body:        "email=%zz",
+		},
+		"field": {
+			body:   `{"email":" User@Example.com ","password":"password"}`,
+			expKey: "user@example.com",
+			expOk:  true,
+		},
+		"missing field": {
+			body: `{"password":"password"}`,
+		},
+		"empty field": {
+			body: `{"email":""}`,
+		},
+		"not a string": {
+			body: `{"email":42}`,
+		},
+		"invalid json": {
+			body: `{"email":`,
+		},
+		"empty body": {},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/auth/login", strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+
+			key, ok := ratelimit.ByField("email")(req)
+			assert.Equal(t, tc.expOk, ok)
+			assert.Equal(t, tc.expKey, key)
+
+			body, err := io.ReadAll(req.Body)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.body, string(body))
+		})
+	}
+}
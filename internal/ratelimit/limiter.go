@@ -0,0 +1,66 @@
+package ratelimit
+
+//go:generate mockery --name Limiter --output ./mocks --outpkg mocks --filename limiter.go --structname Limiter
+//go:generate mockery --name ScriptRunner --output ./mocks --outpkg mocks --filename script_runner.go --structname ScriptRunner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+type (
+	// Limiter counts requests made under a key and tells whether one more is allowed.
+	Limiter interface {
+		Allow(ctx context.Context, key string) (Result, error)
+	}
+
+	// Result is the state of a limit after a request was counted against it.
+	Result struct {
+		// Allowed reports whether the request is within the limit. Denied requests are not counted.
+		Allowed bool
+		// Limit is the number of requests allowed per period.
+		Limit int
+		// Remaining is the number of requests that are still allowed right now.
+		Remaining int
+		// RetryAfter is how long to wait before the next request is allowed, zero if it is allowed right away.
+		RetryAfter time.Duration
+		// Reset is how long it takes for the whole limit to become available again.
+		Reset time.Duration
+	}
+
+	// ScriptRunner defines methods for running Lua scripts in Redis, so that limits hold across replicas.
+	ScriptRunner interface {
+		RunScript(ctx context.Context, script *redis.Script, keys []string, args ...any) ([]int64, error)
+	}
+)
+
+// runLimitScript runs a limiter script, which returns allowed (0 or 1), remaining, retry after and reset
+// in milliseconds, and converts its output to a Result.
+func runLimitScript(
+	ctx context.Context,
+	scripts ScriptRunner,
+	script *redis.Script,
+	limit int,
+	key string,
+	args ...any,
+) (Result, error) {
+	values, err := scripts.RunScript(ctx, script, []string{key}, args...)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to run rate limit script: %w", err)
+	}
+
+	if len(values) != 4 {
+		return Result{}, fmt.Errorf("rate limit script returned %d values instead of 4", len(values))
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(max(values[1], 0)),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+		Reset:      time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -0,0 +1,110 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/ratelimit"
+	"github.com/riabininkf/http-auth-example/internal/ratelimit/mocks"
+)
+
+func TestLimiters_Allow(t *testing.T) {
+	limiters := map[string]struct {
+		newLimiter func(scripts ratelimit.ScriptRunner) ratelimit.Limiter
+		args       []any
+	}{
+		"sliding window": {
+			newLimiter: func(scripts ratelimit.ScriptRunner) ratelimit.Limiter {
+				return ratelimit.NewSlidingWindow(10, time.Minute, scripts)
+			},
+			args: []any{10, int64(60000), mock.AnythingOfType("string")},
+		},
+		"token bucket": {
+			newLimiter: func(scripts ratelimit.ScriptRunner) ratelimit.Limiter {
+				return ratelimit.NewTokenBucket(10, time.Minute, scripts)
+			},
+			args: []any{10, int64(60000)},
+		},
+	}
+
+	testCases := map[string]struct {
+		onRun     []int64
+		onRunErr  error
+		expResult ratelimit.Result
+		expErr    error
+	}{
+		"failed to run script": {
+			onRunErr: assert.AnError,
+			expErr:   assert.AnError,
+		},
+		"allowed": {
+			onRun: []int64{1, 9, 0, 6000},
+			expResult: ratelimit.Result{
+				Allowed:   true,
+				Limit:     10,
+				Remaining: 9,
+				Reset:     6 * time.Second,
+			},
+		},
+		"denied": {
+			onRun: []int64{0, 0, 1500, 60000},
+			expResult: ratelimit.Result{
+				Limit:      10,
+				RetryAfter: 1500 * time.Millisecond,
+				Reset:      time.Minute,
+			},
+		},
+	}
+
+	for limiterName, lc := range limiters {
+		for name, tc := range testCases {
+			t.Run(limiterName+"/"+name, func(t *testing.T) {
+				scripts := mocks.NewScriptRunner(t)
+				scripts.On("RunScript", append(
+					[]any{t.Context(), mock.Anything, []string{"key"}},
+					lc.args...,
+				)...).Return(tc.onRun, tc.onRunErr)
+
+				result, err := lc.newLimiter(scripts).Allow(t.Context(), "key")
+				assert.ErrorIs(t, err, tc.expErr)
+				assert.Equal(t, tc.expResult, result)
+			})
+		}
+
+		t.Run(limiterName+"/unexpected script output", func(t *testing.T) {
+			scripts := mocks.NewScriptRunner(t)
+			scripts.On("RunScript", append(
+				[]any{t.Context(), mock.Anything, []string{"key"}},
+				lc.args...,
+			)...).Return([]int64{1, 9}, nil)
+
+			_, err := lc.newLimiter(scripts).Allow(t.Context(), "key")
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSlidingWindow_Allow_UniqueMembers(t *testing.T) {
+	var nonces []string
+
+	scripts := mocks.NewScriptRunner(t)
+	scripts.On("RunScript", t.Context(), mock.Anything, []string{"key"}, 10, int64(60000), mock.AnythingOfType("string")).
+		Run(func(args mock.Arguments) { nonces = append(nonces, args.String(5)) }).
+		Return([]int64{1, 9, 0, 60000}, nil)
+
+	limiter := ratelimit.NewSlidingWindow(10, time.Minute, scripts)
+	for range 2 {
+		if _, err := limiter.Allow(t.Context(), "key"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !assert.Len(t, nonces, 2) {
+		t.FailNow()
+	}
+
+	assert.NotEqual(t, nonces[0], nonces[1])
+}
@@ -0,0 +1,63 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+// ScriptRunner is an autogenerated mock type for the ScriptRunner type
+type ScriptRunner struct {
+	mock.Mock
+}
+
+// RunScript provides a mock function with given fields: ctx, script, keys, args
+func (_m *ScriptRunner) RunScript(ctx context.Context, script *redis.Script, keys []string, args ...interface{}) ([]int64, error) {
+	var _ca []interface{}
+	_ca = append(_ca, ctx, script, keys)
+	_ca = append(_ca, args...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RunScript")
+	}
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, *redis.Script, []string, ...interface{}) ([]int64, error)); ok {
+		return rf(ctx, script, keys, args...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, *redis.Script, []string, ...interface{}) []int64); ok {
+		r0 = rf(ctx, script, keys, args...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, *redis.Script, []string, ...interface{}) error); ok {
+		r1 = rf(ctx, script, keys, args...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewScriptRunner creates a new instance of ScriptRunner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewScriptRunner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *ScriptRunner {
+	mock := &ScriptRunner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+)
+
+const keyPrefix = "ratelimit:"
+
+type (
+	// Class is a set of rules applied together to a group of routes, e.g. to all the login endpoints.
+	Class struct {
+		Name string
+		// Routes are matched as "METHOD /path".
+		Routes []string
+		Rules  []Rule
+	}
+
+	// Rule limits the requests counted under the key returned by Key.
+	Rule struct {
+		Name    string
+		Key     KeyFunc
+		Limiter Limiter
+	}
+)
+
+// NewPolicy creates a new *Policy instance applying the classes to their routes.
+func NewPolicy(classes []Class) *Policy {
+	routes := make(map[string]Class)
+	for _, class := range classes {
+		for _, route := range class.Routes {
+			routes[route] = class
+		}
+	}
+
+	return &Policy{routes: routes}
+}
+
+// Policy decides which rules a request is subject to and checks all of them.
+type Policy struct {
+	routes map[string]Class
+}
+
+// Check counts the request against every rule of its route class. Returns false if no rule applies to the request.
+// The result is the first limit the request exceeds, or the limit closest to being exceeded if it exceeds none.
+func (p *Policy) Check(req *http.Request) (Result, bool, error) {
+	class, ok := p.routes[fmt.Sprintf("%s %s", req.Method, req.URL.Path)]
+	if !ok {
+		return Result{}, false, nil
+	}
+
+	var (
+		result  Result
+		applied bool
+	)
+	for _, rule := range class.Rules {
+		var key string
+		if key, ok = rule.Key(req); !ok {
+			continue
+		}
+
+		// keys may be emails or other personal data, which should not be stored in plain text
+		hash := sha256.Sum256([]byte(key))
+
+		ruleResult, err := rule.Limiter.Allow(
+			req.Context(),
+			keyPrefix+class.Name+":"+rule.Name+":"+hex.EncodeToString(hash[:]),
+		)
+		if err != nil {
+			return Result{}, false, fmt.Errorf("failed to check rule %s of class %s: %w", rule.Name, class.Name, err)
+		}
+
+		if !ruleResult.Allowed {
+			return ruleResult, true, nil
+		}
+
+		if !applied || ruleResult.Remaining < result.Remaining {
+			result = ruleResult
+		}
+
+		applied = true
+	}
+
+	return result, applied, nil
+}
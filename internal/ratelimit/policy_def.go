@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+const (
+	// DefPolicyName is the name of the *Policy definition.
+	DefPolicyName = "ratelimit.policy"
+
+	configKeyTrustProxy = "http.trustProxy"
+	configKeyClasses    = "http.rateLimit.classes"
+
+	algorithmSlidingWindow = "slidingWindow"
+	algorithmTokenBucket   = "tokenBucket"
+
+	keyIP          = "ip"
+	keyUser        = "user"
+	keyFieldPrefix = "field:"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Policy]{
+			Name: DefPolicyName,
+			Build: func(ctn di.Container) (*Policy, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var scripts *redis.Client
+				if err := ctn.Fill(redis.DefClientName, &scripts); err != nil {
+					return nil, err
+				}
+
+				trustProxy := cfg.GetBool(configKeyTrustProxy)
+
+				classNames := sortedKeys(cfg.GetStringMap(configKeyClasses))
+				classes := make([]Class, 0, len(classNames))
+				for _, className := range classNames {
+					classKey := configKeyClasses + "." + className
+
+					class := Class{Name: className}
+					if class.Routes = cfg.GetStringSlice(classKey + ".routes"); len(class.Routes) == 0 {
+						return nil, config.NewErrMissingKey(classKey + ".routes")
+					}
+
+					for _, ruleName := range sortedKeys(cfg.GetStringMap(classKey + ".rules")) {
+						rule, err := buildRule(cfg, classKey+".rules."+ruleName, ruleName, trustProxy, scripts)
+						if err != nil {
+							return nil, err
+						}
+
+						class.Rules = append(class.Rules, rule)
+					}
+
+					if len(class.Rules) == 0 {
+						return nil, config.NewErrMissingKey(classKey + ".rules")
+					}
+
+					classes = append(classes, class)
+				}
+
+				return NewPolicy(classes), nil
+			},
+		},
+	)
+}
+
+func buildRule(cfg *config.Config, ruleKey string, name string, trustProxy bool, scripts ScriptRunner) (Rule, error) {
+	rule := Rule{Name: name}
+
+	var key string
+	if key = cfg.GetString(ruleKey + ".key"); key == "" {
+		return Rule{}, config.NewErrMissingKey(ruleKey + ".key")
+	}
+
+	switch {
+	case key == keyIP:
+		rule.Key = ByIP(trustProxy)
+	case key == keyUser:
+		rule.Key = ByUser()
+	case strings.HasPrefix(key, keyFieldPrefix) && len(key) > len(keyFieldPrefix):
+		rule.Key = ByField(strings.TrimPrefix(key, keyFieldPrefix))
+	default:
+		return Rule{}, fmt.Errorf("unknown rate limit key %q in %s", key, ruleKey)
+	}
+
+	var limit int
+	if limit = cfg.GetInt(ruleKey + ".limit"); limit == 0 {
+		return Rule{}, config.NewErrMissingKey(ruleKey + ".limit")
+	}
+
+	var period time.Duration
+	if period = cfg.GetDuration(ruleKey + ".period"); period == 0 {
+		return Rule{}, config.NewErrMissingKey(ruleKey + ".period")
+	}
+
+	switch algorithm := cfg.GetString(ruleKey + ".algorithm"); algorithm {
+	case algorithmSlidingWindow, "":
+		rule.Limiter = NewSlidingWindow(limit, period, scripts)
+	case algorithmTokenBucket:
+		rule.Limiter = NewTokenBucket(limit, period, scripts)
+	default:
+		return Rule{}, fmt.Errorf("unknown rate limit algorithm %q in %s", algorithm, ruleKey)
+	}
+
+	return rule, nil
+}
+
+// sortedKeys returns the keys of the map in a stable order, so that rules are checked the same way on every start.
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
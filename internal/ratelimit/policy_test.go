@@ -0,0 +1,117 @@
+package ratelimit_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/ratelimit"
+	"github.com/riabininkf/http-auth-example/internal/ratelimit/mocks"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	allowed := ratelimit.Result{Allowed: true, Limit: 100, Remaining: 50, Reset: time.Minute}
+	scarce := ratelimit.Result{Allowed: true, Limit: 10, Remaining: 2, Reset: time.Minute}
+	denied := ratelimit.Result{Limit: 10, RetryAfter: time.Second, Reset: time.Minute}
+
+	testCases := map[string]struct {
+		method     string
+		path       string
+		body       string
+		onIP       *ratelimit.Result
+		onIPErr    error
+		onEmail    *ratelimit.Result
+		expResult  ratelimit.Result
+		expApplied bool
+		expErr     error
+	}{
+		"route without class": {
+			method: http.MethodPost,
+			path:   "/v1/auth/register",
+		},
+		"other method": {
+			method: http.MethodGet,
+			path:   "/v1/auth/login",
+		},
+		"failed to check rule": {
+			method:  http.MethodPost,
+			path:    "/v1/auth/login",
+			onIPErr: assert.AnError,
+			expErr:  assert.AnError,
+		},
+		"only rules with a key apply": {
+			method:     http.MethodPost,
+			path:       "/v1/auth/login",
+			body:       `{}`,
+			onIP:       &allowed,
+			expResult:  allowed,
+			expApplied: true,
+		},
+		"closest limit is reported": {
+			method:     http.MethodPost,
+			path:       "/v1/auth/login",
+			body:       `{"email":"user@example.com"}`,
+			onIP:       &allowed,
+			onEmail:    &scarce,
+			expResult:  scarce,
+			expApplied: true,
+		},
+		"denied by any rule": {
+			method:     http.MethodPost,
+			path:       "/v1/auth/login",
+			body:       `{"email":"user@example.com"}`,
+			onIP:       &allowed,
+			onEmail:    &denied,
+			expResult:  denied,
+			expApplied: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			ipLimiter := mocks.NewLimiter(t)
+			if tc.onIP != nil || tc.onIPErr != nil {
+				var result ratelimit.Result
+				if tc.onIP != nil {
+					result = *tc.onIP
+				}
+
+				ipLimiter.On("Allow", mock.Anything, mock.MatchedBy(func(key string) bool {
+					return strings.HasPrefix(key, "ratelimit:login:ip:") &&
+						!strings.Contains(key, "192.0.2.1")
+				})).Return(result, tc.onIPErr)
+			}
+
+			emailLimiter := mocks.NewLimiter(t)
+			if tc.onEmail != nil {
+				emailLimiter.On("Allow", mock.Anything, mock.MatchedBy(func(key string) bool {
+					return strings.HasPrefix(key, "ratelimit:login:email:") &&
+						!strings.Contains(key, "user@example.com")
+				})).Return(*tc.onEmail, nil)
+			}
+
+			policy := ratelimit.NewPolicy([]ratelimit.Class{
+				{
+					Name:   "login",
+					Routes: []string{"POST /v1/auth/login"},
+					Rules: []ratelimit.Rule{
+						{Name: "ip", Key: ratelimit.ByIP(false), Limiter: ipLimiter},
+						{Name: "email", Key: ratelimit.ByField("email"), Limiter: emailLimiter},
+					},
+				},
+			})
+
+			req := httptest.NewRequest(tc.method, tc.path, strings.NewReader(tc.body))
+
+			result, applied, err := policy.Check(req)
+			assert.ErrorIs(t, err, tc.expErr)
+			assert.Equal(t, tc.expApplied, applied)
+			assert.Equal(t, tc.expResult, result)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/random"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+// slidingWindowScript keeps the times of the requests allowed within the window in a sorted set.
+// The time comes from Redis, so that replicas with skewed clocks count the same way.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, now .. ':' .. ARGV[3])
+	count = count + 1
+	allowed = 1
+end
+
+redis.call('PEXPIRE', key, window)
+
+-- a request is allowed again once the oldest one leaves the window, and all of them once the newest one does
+local retryAfter = 0
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if allowed == 0 and oldest[2] then
+	retryAfter = tonumber(oldest[2]) + window - now
+end
+
+local reset = 0
+local newest = redis.call('ZRANGE', key, -1, -1, 'WITHSCORES')
+if newest[2] then
+	reset = tonumber(newest[2]) + window - now
+end
+
+return {allowed, limit - count, retryAfter, reset}
+`)
+
+// NewSlidingWindow creates a new *SlidingWindow instance allowing limit requests in any window of the given length.
+func NewSlidingWindow(limit int, window time.Duration, scripts ScriptRunner) *SlidingWindow {
+	return &SlidingWindow{
+		limit:   limit,
+		window:  window,
+		scripts: scripts,
+	}
+}
+
+// SlidingWindow limits the number of requests within any window of a fixed length. It is exact, at the cost
+// of storing the time of every allowed request until it leaves the window, so it suits low limits.
+type SlidingWindow struct {
+	limit   int
+	window  time.Duration
+	scripts ScriptRunner
+}
+
+// Allow counts a request under the key if it is within the limit.
+func (s *SlidingWindow) Allow(ctx context.Context, key string) (Result, error) {
+	// requests made in the same millisecond need distinct members of the set
+	nonce, err := random.String(8)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to generate request id: %w", err)
+	}
+
+	return runLimitScript(ctx, s.scripts, slidingWindowScript, s.limit, key, s.limit, s.window.Milliseconds(), nonce)
+}
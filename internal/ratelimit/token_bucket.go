@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+// tokenBucketScript keeps the number of tokens in the bucket and the time it was last updated in a hash.
+// The time comes from Redis, so that replicas with skewed clocks count the same way.
+var tokenBucketScript = redis.NewScript(`
+local key = KEYS[1]
+local capacity = tonumber(ARGV[1])
+local period = tonumber(ARGV[2])
+local rate = capacity / period
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', key, 'tokens', 'updated')
+local tokens = tonumber(state[1]) or capacity
+local updated = tonumber(state[2]) or now
+tokens = math.min(capacity, tokens + math.max(now - updated, 0) * rate)
+
+local allowed = 0
+local retryAfter = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retryAfter = math.ceil((1 - tokens) / rate)
+end
+
+redis.call('HSET', key, 'tokens', tostring(tokens), 'updated', now)
+
+-- a full bucket is the same as no bucket at all
+local reset = math.ceil((capacity - tokens) / rate)
+redis.call('PEXPIRE', key, math.max(reset, 1))
+
+return {allowed, math.floor(tokens), retryAfter, reset}
+`)
+
+// NewTokenBucket creates a new *TokenBucket instance allowing bursts of up to limit requests, refilled at the rate
+// of limit requests per period.
+func NewTokenBucket(limit int, period time.Duration, scripts ScriptRunner) *TokenBucket {
+	return &TokenBucket{
+		limit:   limit,
+		period:  period,
+		scripts: scripts,
+	}
+}
+
+// TokenBucket limits the average rate of requests while allowing bursts. Every request takes a token from
+// a bucket of limit tokens, which is refilled continuously, so it takes constant memory whatever the limit.
+type TokenBucket struct {
+	limit   int
+	period  time.Duration
+	scripts ScriptRunner
+}
+
+// Allow counts a request under the key if there is a token left for it.
+func (b *TokenBucket) Allow(ctx context.Context, key string) (Result, error) {
+	return runLimitScript(ctx, b.scripts, tokenBucketScript, b.limit, key, b.limit, b.period.Milliseconds())
+}
@@ -2,11 +2,26 @@ package redis
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+// ErrNotFound is returned when the requested key does not exist.
+var ErrNotFound = errors.New("key not found")
+
+// KeepTTL can be passed to Set to overwrite the value without changing the key's time-to-live.
+const KeepTTL time.Duration = redis.KeepTTL
+
+// Script is a Lua script that Redis runs atomically.
+type Script = redis.Script
+
+// NewScript creates a Script from Lua source code.
+func NewScript(src string) *Script {
+	return redis.NewScript(src)
+}
+
 // NewClient initializes and returns a new Client instance using the provided redis.Client.
 func NewClient(c *redis.Client) *Client {
 	return &Client{
@@ -25,7 +40,85 @@ func (c *Client) Set(ctx context.Context, key string, value any, ttl time.Durati
 	return err
 }
 
+// Get returns the value stored at the specified key. Returns ErrNotFound if the key does not exist.
+func (c *Client) Get(ctx context.Context, key string) (string, error) {
+	value, err := c.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNotFound
+	}
+
+	return value, err
+}
+
 // Pop removes the value stored at the specified key in the Redis database and returns an error if the operation fails.
 func (c *Client) Pop(ctx context.Context, key string) error {
 	return c.client.GetDel(ctx, key).Err()
 }
+
+// GetDel removes the value stored at the specified key and returns it. Returns ErrNotFound if the key does not exist.
+func (c *Client) GetDel(ctx context.Context, key string) (string, error) {
+	value, err := c.client.GetDel(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNotFound
+	}
+
+	return value, err
+}
+
+// SetNX stores a key-value pair with the specified time-to-live only if the key does not exist yet.
+// Returns false if the key already exists.
+func (c *Client) SetNX(ctx context.Context, key string, value any, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+// Incr increments the counter stored at the specified key and resets its time-to-live.
+// A key that does not exist is treated as zero. Returns the new value.
+func (c *Client) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	var value *redis.IntCmd
+	if _, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		value = pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	return value.Val(), nil
+}
+
+// AddToSet adds a member to the set stored at the specified key and resets the set's time-to-live.
+func (c *Client) AddToSet(ctx context.Context, key string, member string, ttl time.Duration) error {
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, key, member)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+
+	return err
+}
+
+// PopSet removes the set stored at the specified key and returns its members.
+// Returns an empty slice if the key does not exist.
+func (c *Client) PopSet(ctx context.Context, key string) ([]string, error) {
+	var members *redis.StringSliceCmd
+	if _, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		members = pipe.SMembers(ctx, key)
+		pipe.Del(ctx, key)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return members.Val(), nil
+}
+
+// Del removes the specified keys from the Redis database. Keys that do not exist are ignored.
+func (c *Client) Del(ctx context.Context, keys ...string) error {
+	return c.client.Del(ctx, keys...).Err()
+}
+
+// RunScript runs the script with the specified keys and arguments and returns its result, an array of integers.
+// The script is sent to Redis only if Redis does not have it cached yet.
+func (c *Client) RunScript(ctx context.Context, script *Script, keys []string, args ...any) ([]int64, error) {
+	return script.Run(ctx, c.client, keys, args...).Int64Slice()
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewAuditEvents creates a new instance of AuditEvents using the provided Conn interface for database operations.
+func NewAuditEvents(conn Conn) *AuditEvents {
+	return &AuditEvents{
+		conn: conn,
+	}
+}
+
+// AuditEvents provides methods to interact with the audit_events table in the database.
+type AuditEvents struct {
+	conn Conn
+}
+
+// Save inserts a new audit event into the database and links it to the previously saved one with a hash,
+// see domain.AuditEvent.ChainHash. ID and CreatedAt are ignored, the database sets them.
+// Events without an outcome are saved as successful.
+func (a *AuditEvents) Save(ctx context.Context, event domain.AuditEvent) (err error) {
+	details := event.Details
+	if details == nil {
+		details = map[string]string{}
+	}
+
+	var detailsJSON []byte
+	if detailsJSON, err = json.Marshal(details); err != nil {
+		return fmt.Errorf("failed to marshal audit event details: %w", err)
+	}
+
+	var tx pgx.Tx
+	if tx, err = a.conn.Begin(ctx); err != nil {
+		return err
+	}
+
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback(ctx)
+		}
+	}()
+
+	// concurrent inserts are serialized by a transaction-level advisory lock, so that every event is linked
+	// to the one saved right before it, while reads and other writes of the table are not blocked
+	if _, err = tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('public.audit_events'))`); err != nil {
+		return err
+	}
+
+	if err = tx.QueryRow(
+		ctx,
+		`SELECT hash FROM public.audit_events ORDER BY id DESC LIMIT 1`,
+	).Scan(&event.PrevHash); err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	// the ID and the creation time are taken before the insert, so that the event is saved along with its hash.
+	// The hash is calculated from the values as stored, e.g. user IDs in the canonical form of UUIDs.
+	if err = tx.QueryRow(
+		ctx,
+		`SELECT nextval(pg_get_serial_sequence('public.audit_events', 'id')), NOW(),
+			COALESCE(NULLIF($1, '')::UUID::TEXT, ''), COALESCE(NULLIF($2, '')::UUID::TEXT, ''),
+			COALESCE(NULLIF($3, ''), 'success')`,
+		event.UserID,
+		event.ActorID,
+		event.Outcome,
+	).Scan(&event.ID, &event.CreatedAt, &event.UserID, &event.ActorID, &event.Outcome); err != nil {
+		return err
+	}
+
+	event.Details = details
+
+	query := `INSERT INTO public.audit_events (id, type, user_id, actor_id, ip, user_agent, session_id, outcome, reason, details, created_at, prev_hash, hash)
+		VALUES ($1, $2, NULLIF($3, '')::UUID, NULLIF($4, '')::UUID, $5, $6, $7, $8, $9, $10, $11, $12, $13)`
+
+	if _, err = tx.Exec(
+		ctx,
+		query,
+		event.ID,
+		event.Type,
+		event.UserID,
+		event.ActorID,
+		event.IP,
+		event.UserAgent,
+		event.SessionID,
+		event.Outcome,
+		event.Reason,
+		detailsJSON,
+		event.CreatedAt,
+		event.PrevHash,
+		event.ChainHash(event.PrevHash),
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// ListByUser returns up to limit of the latest events of the user, newest first.
+func (a *AuditEvents) ListByUser(ctx context.Context, userID string, limit int) ([]domain.AuditEvent, error) {
+	query := `SELECT id, type, COALESCE(actor_id::TEXT, ''), ip, user_agent, session_id, outcome, reason, details, created_at
+		FROM public.audit_events WHERE user_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2`
+
+	rows, err := a.conn.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var events []domain.AuditEvent
+	for rows.Next() {
+		var (
+			event       = domain.AuditEvent{UserID: userID}
+			detailsJSON []byte
+		)
+		if err = rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.ActorID,
+			&event.IP,
+			&event.UserAgent,
+			&event.SessionID,
+			&event.Outcome,
+			&event.Reason,
+			&detailsJSON,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(detailsJSON, &event.Details); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal audit event details: %w", err)
+		}
+
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// ClientsByUser returns the latest session event of every client the user had a session with since the given time,
+// that is successful events that issued or used a refresh token. Only IP, UserAgent and CreatedAt are set.
+func (a *AuditEvents) ClientsByUser(ctx context.Context, userID string, since time.Time) ([]domain.AuditEvent, error) {
+	query := `SELECT DISTINCT ON (ip, user_agent) ip, user_agent, created_at
+		FROM public.audit_events
+		WHERE user_id = $1 AND outcome = 'success' AND session_id <> '' AND created_at >= $2
+		ORDER BY ip, user_agent, created_at DESC`
+
+	rows, err := a.conn.Query(ctx, query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var events []domain.AuditEvent
+	for rows.Next() {
+		event := domain.AuditEvent{UserID: userID}
+		if err = rows.Scan(&event.IP, &event.UserAgent, &event.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// Each streams all events in the order they were saved and calls fn for every one, stopping at the first error.
+func (a *AuditEvents) Each(ctx context.Context, fn func(event domain.AuditEvent) error) error {
+	query := `SELECT id, type, COALESCE(user_id::TEXT, ''), COALESCE(actor_id::TEXT, ''), ip, user_agent, session_id,
+			outcome, reason, details, created_at, prev_hash, hash
+		FROM public.audit_events ORDER BY id`
+
+	rows, err := a.conn.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			event       domain.AuditEvent
+			detailsJSON []byte
+		)
+		if err = rows.Scan(
+			&event.ID,
+			&event.Type,
+			&event.UserID,
+			&event.ActorID,
+			&event.IP,
+			&event.UserAgent,
+			&event.SessionID,
+			&event.Outcome,
+			&event.Reason,
+			&detailsJSON,
+			&event.CreatedAt,
+			&event.PrevHash,
+			&event.Hash,
+		); err != nil {
+			return err
+		}
+
+		if err = json.Unmarshal(detailsJSON, &event.Details); err != nil {
+			return fmt.Errorf("failed to unmarshal audit event details: %w", err)
+		}
+
+		if err = fn(event); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riabininkf/go-modules/db"
+	"github.com/riabininkf/go-modules/di"
+)
+
+// DefAuditEventsName is the name of the *AuditEvents definition.
+const DefAuditEventsName = "repository.audit-events"
+
+func init() {
+	di.Add(
+		di.Def[*AuditEvents]{
+			Name: DefAuditEventsName,
+			Build: func(ctn di.Container) (*AuditEvents, error) {
+				var conn *pgxpool.Pool
+				if err := ctn.Fill(db.DefPostgresName, &conn); err != nil {
+					return nil, err
+				}
+
+				return NewAuditEvents(conn), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,42 @@
+package repository
+
+import "context"
+
+// NewRecoveryCodes creates a new instance of RecoveryCodes using the provided Conn interface for database operations.
+func NewRecoveryCodes(conn Conn) *RecoveryCodes {
+	return &RecoveryCodes{
+		conn: conn,
+	}
+}
+
+// RecoveryCodes provides methods to interact with the recovery_codes table in the database.
+type RecoveryCodes struct {
+	conn Conn
+}
+
+// Replace removes all recovery codes of the user, used or not, and stores the given code hashes instead.
+// Both happen in a single statement, so the old set never coexists with the new one.
+func (r *RecoveryCodes) Replace(ctx context.Context, userID string, codeHashes []string) error {
+	query := `WITH deleted AS (DELETE FROM public.recovery_codes WHERE user_id = $1)
+		INSERT INTO public.recovery_codes (user_id, code_hash) SELECT $1, UNNEST($2::VARCHAR[])`
+
+	if _, err := r.conn.Exec(ctx, query, userID, codeHashes); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Use marks the unused recovery code of the user with the given hash as used.
+// Returns false if there is no such code or it was already used.
+func (r *RecoveryCodes) Use(ctx context.Context, userID string, codeHash string) (bool, error) {
+	query := `UPDATE public.recovery_codes SET used_at = NOW()
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`
+
+	tag, err := r.conn.Exec(ctx, query, userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
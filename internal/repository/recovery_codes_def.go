@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riabininkf/go-modules/db"
+	"github.com/riabininkf/go-modules/di"
+)
+
+// DefRecoveryCodesName is the name of the *RecoveryCodes definition.
+const DefRecoveryCodesName = "repository.recovery-codes"
+
+func init() {
+	di.Add(
+		di.Def[*RecoveryCodes]{
+			Name: DefRecoveryCodesName,
+			Build: func(ctn di.Container) (*RecoveryCodes, error) {
+				var conn *pgxpool.Pool
+				if err := ctn.Fill(db.DefPostgresName, &conn); err != nil {
+					return nil, err
+				}
+
+				return NewRecoveryCodes(conn), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewTOTPCredentials creates a new instance of TOTPCredentials using the provided Conn interface for database operations.
+func NewTOTPCredentials(conn Conn) *TOTPCredentials {
+	return &TOTPCredentials{
+		conn: conn,
+	}
+}
+
+// TOTPCredentials provides methods to interact with the user_totp table in the database.
+type TOTPCredentials struct {
+	conn Conn
+}
+
+// Save stores a new unconfirmed credential, replacing a previous unconfirmed one of the same user.
+// Returns domain.ErrTOTPAlreadyConfirmed if the user's credential is already confirmed.
+func (t *TOTPCredentials) Save(ctx context.Context, credential domain.TOTPCredential) error {
+	query := `INSERT INTO public.user_totp (user_id, secret_encrypted) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+			SET secret_encrypted = EXCLUDED.secret_encrypted, last_used_step = 0, created_at = NOW()
+			WHERE public.user_totp.confirmed_at IS NULL`
+
+	tag, err := t.conn.Exec(ctx, query, credential.UserID, credential.EncryptedSecret)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrTOTPAlreadyConfirmed
+	}
+
+	return nil
+}
+
+// GetByUserID retrieves the credential of the user. Returns domain.ErrTOTPNotFound if the user has none.
+func (t *TOTPCredentials) GetByUserID(ctx context.Context, userID string) (domain.TOTPCredential, error) {
+	query := `SELECT secret_encrypted, confirmed_at, last_used_step FROM public.user_totp WHERE user_id = $1`
+
+	var (
+		credential  = domain.TOTPCredential{UserID: userID}
+		confirmedAt sql.NullTime
+	)
+	if err := t.conn.QueryRow(ctx, query, userID).Scan(
+		&credential.EncryptedSecret,
+		&confirmedAt,
+		&credential.LastUsedStep,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.TOTPCredential{}, domain.ErrTOTPNotFound
+		}
+
+		return domain.TOTPCredential{}, err
+	}
+
+	credential.ConfirmedAt = confirmedAt.Time
+
+	return credential, nil
+}
+
+// Confirm marks the unconfirmed credential of the user as confirmed and records the time step of the code
+// used to confirm it. Returns domain.ErrTOTPNotFound if there is no unconfirmed credential.
+func (t *TOTPCredentials) Confirm(ctx context.Context, userID string, step int64) error {
+	query := `UPDATE public.user_totp SET confirmed_at = NOW(), last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NULL`
+
+	tag, err := t.conn.Exec(ctx, query, userID, step)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrTOTPNotFound
+	}
+
+	return nil
+}
+
+// UseStep records the time step of an accepted code. The update is conditional, so that concurrent requests
+// cannot use the same code twice. Returns false if the step or a later one was already used.
+func (t *TOTPCredentials) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	query := `UPDATE public.user_totp SET last_used_step = $2
+		WHERE user_id = $1 AND confirmed_at IS NOT NULL AND last_used_step < $2`
+
+	tag, err := t.conn.Exec(ctx, query, userID, step)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riabininkf/go-modules/db"
+	"github.com/riabininkf/go-modules/di"
+)
+
+// DefTOTPCredentialsName is the name of the *TOTPCredentials definition.
+const DefTOTPCredentialsName = "repository.totp-credentials"
+
+func init() {
+	di.Add(
+		di.Def[*TOTPCredentials]{
+			Name: DefTOTPCredentialsName,
+			Build: func(ctn di.Container) (*TOTPCredentials, error) {
+				var conn *pgxpool.Pool
+				if err := ctn.Fill(db.DefPostgresName, &conn); err != nil {
+					return nil, err
+				}
+
+				return NewTOTPCredentials(conn), nil
+			},
+		},
+	)
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 
@@ -38,36 +39,231 @@ func (u *Users) Save(ctx context.Context, user domain.User) error {
 
 // GetByEmail retrieves a user by their email address from the database. Returns a User and error if applicable.
 func (u *Users) GetByEmail(ctx context.Context, email string) (domain.User, error) {
-	query := `SELECT id, password FROM public.users WHERE email = $1`
+	query := `SELECT ` + userColumns + ` FROM public.users WHERE email = $1`
 
-	var (
-		userID         string
-		hashedPassword string
+	return scanUser(u.conn.QueryRow(ctx, query, email))
+}
+
+// GetByID retrieves a user by their unique identifier from the database, returning a domain.User or an error.
+func (u *Users) GetByID(ctx context.Context, userID string) (domain.User, error) {
+	query := `SELECT ` + userColumns + ` FROM public.users WHERE id = $1`
+
+	return scanUser(u.conn.QueryRow(ctx, query, userID))
+}
+
+// UpdatePassword updates the password of a user identified by userID with the provided hashed password in the database.
+func (u *Users) UpdatePassword(ctx context.Context, userID string, hashedPassword string) error {
+	query := `UPDATE public.users SET password = $1, updated_at = NOW() WHERE id = $2`
+	if _, err := u.conn.Exec(ctx, query, hashedPassword, userID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpdateProfile changes the fields of the profile set in the update, leaving the others as they are,
+// and returns the updated user. Returns domain.ErrUserNotFound if there is no such user.
+func (u *Users) UpdateProfile(ctx context.Context, userID string, update domain.UserProfileUpdate) (domain.User, error) {
+	query := `UPDATE public.users SET
+			display_name = COALESCE($2, display_name),
+			locale = COALESCE($3, locale),
+			timezone = COALESCE($4, timezone),
+			avatar_url = COALESCE($5, avatar_url),
+			metadata = COALESCE($6::JSONB, metadata),
+			updated_at = NOW()
+		WHERE id = $1
+		RETURNING ` + userColumns
+
+	var metadata *string
+	if update.Metadata != nil {
+		value := string(update.Metadata)
+		metadata = &value
+	}
+
+	return scanUser(u.conn.QueryRow(
+		ctx,
+		query,
+		userID,
+		update.DisplayName,
+		update.Locale,
+		update.Timezone,
+		update.AvatarURL,
+		metadata,
+	))
+}
+
+// MarkEmailVerified marks the email address of the user as verified, provided the user still has this address.
+// Returns domain.ErrUserNotFound if there is no such user or the email has changed since the verification was sent.
+func (u *Users) MarkEmailVerified(ctx context.Context, userID string, email string) error {
+	query := `UPDATE public.users SET email_verified_at = COALESCE(email_verified_at, NOW()), updated_at = NOW()
+		WHERE id = $1 AND email = $2`
+
+	tag, err := u.conn.Exec(ctx, query, userID, email)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// ChangeEmail moves the user from oldEmail to newEmail and marks it as verified, provided the user still has oldEmail.
+// Returns domain.ErrEmailBusy if newEmail belongs to another user, or domain.ErrUserNotFound if there is no such user
+// or the email has changed since.
+func (u *Users) ChangeEmail(ctx context.Context, userID string, oldEmail string, newEmail string) error {
+	query := `UPDATE public.users SET email = $3, email_verified_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND email = $2`
+
+	tag, err := u.conn.Exec(ctx, query, userID, oldEmail, newEmail)
+	if err != nil {
+		if isUniqueConstraintViolation(err) {
+			return domain.ErrEmailBusy
+		}
+
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Existing returns those of the ids and emails that already belong to users.
+func (u *Users) Existing(ctx context.Context, ids []string, emails []string) ([]string, []string, error) {
+	query := `SELECT id::TEXT, email FROM public.users WHERE id = ANY($1::UUID[]) OR email = ANY($2)`
+
+	rows, err := u.conn.Query(ctx, query, ids, emails)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer rows.Close()
+
+	idSet, emailSet := make(map[string]bool), make(map[string]bool)
+	for _, id := range ids {
+		idSet[id] = true
+	}
+
+	for _, email := range emails {
+		emailSet[email] = true
+	}
+
+	var existingIDs, existingEmails []string
+	for rows.Next() {
+		var id, email string
+		if err = rows.Scan(&id, &email); err != nil {
+			return nil, nil, err
+		}
+
+		// a row matches by id, by email or both
+		if idSet[id] {
+			existingIDs = append(existingIDs, id)
+		}
+
+		if emailSet[email] {
+			existingEmails = append(existingEmails, email)
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return existingIDs, existingEmails, nil
+}
+
+// Import inserts the users with a single COPY, which is much faster than inserting them one by one.
+// Either all users are inserted or none. Returns domain.ErrEmailBusy if any email or id is already in use.
+func (u *Users) Import(ctx context.Context, users []domain.User) (int64, error) {
+	now := time.Now()
+
+	imported, err := u.conn.CopyFrom(
+		ctx,
+		pgx.Identifier{"public", "users"},
+		[]string{"id", "email", "password", "email_verified_at"},
+		pgx.CopyFromSlice(len(users), func(i int) ([]any, error) {
+			var emailVerifiedAt *time.Time
+			if users[i].EmailVerified() {
+				emailVerifiedAt = &now
+			}
+
+			return []any{users[i].ID(), users[i].Email(), users[i].HashedPassword(), emailVerifiedAt}, nil
+		}),
 	)
-	if err := u.conn.QueryRow(ctx, query, email).Scan(&userID, &hashedPassword); err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, domain.ErrUserNotFound
+	if err != nil {
+		if isUniqueConstraintViolation(err) {
+			return 0, domain.ErrEmailBusy
 		}
 
-		return nil, err
+		return 0, err
 	}
 
-	return domain.NewUser(
-		userID,
-		email,
-		hashedPassword,
-	), nil
+	return imported, nil
 }
 
-// GetByID retrieves a user by their unique identifier from the database, returning a domain.User or an error.
-func (u *Users) GetByID(ctx context.Context, userID string) (domain.User, error) {
-	query := `SELECT email, password FROM public.users WHERE id = $1`
+// Each streams all users ordered by creation time and calls fn for every one, stopping at the first error.
+func (u *Users) Each(ctx context.Context, fn func(user domain.User) error) error {
+	query := `SELECT id, email, password, email_verified_at IS NOT NULL FROM public.users ORDER BY created_at, id`
+
+	rows, err := u.conn.Query(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			userID         string
+			email          string
+			hashedPassword string
+			emailVerified  bool
+		)
+		if err = rows.Scan(&userID, &email, &hashedPassword, &emailVerified); err != nil {
+			return err
+		}
+
+		if err = fn(domain.NewUser(userID, email, hashedPassword, userOptions(emailVerified)...)); err != nil {
+			return err
+		}
+	}
 
+	return rows.Err()
+}
+
+// userColumns are the columns of the users table scanUser reads, in its order.
+const userColumns = `id, email, password, email_verified_at IS NOT NULL, display_name, locale, timezone, avatar_url,
+	metadata, created_at, updated_at`
+
+// scanUser reads a user selected with userColumns. Returns domain.ErrUserNotFound if there is no row.
+func scanUser(row pgx.Row) (domain.User, error) {
 	var (
+		userID         string
 		email          string
 		hashedPassword string
+		emailVerified  bool
+		profile        domain.UserProfile
+		createdAt      time.Time
+		updatedAt      time.Time
 	)
-	if err := u.conn.QueryRow(ctx, query, userID).Scan(&email, &hashedPassword); err != nil {
+	if err := row.Scan(
+		&userID,
+		&email,
+		&hashedPassword,
+		&emailVerified,
+		&profile.DisplayName,
+		&profile.Locale,
+		&profile.Timezone,
+		&profile.AvatarURL,
+		&profile.Metadata,
+		&createdAt,
+		&updatedAt,
+	); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrUserNotFound
 		}
@@ -75,19 +271,21 @@ func (u *Users) GetByID(ctx context.Context, userID string) (domain.User, error)
 		return nil, err
 	}
 
-	return domain.NewUser(
-		userID,
-		email,
-		hashedPassword,
-	), nil
+	opts := append(
+		userOptions(emailVerified),
+		domain.WithProfile(profile),
+		domain.WithTimestamps(createdAt, updatedAt),
+	)
+
+	return domain.NewUser(userID, email, hashedPassword, opts...), nil
 }
 
-// UpdatePassword updates the password of a user identified by userID with the provided hashed password in the database.
-func (u *Users) UpdatePassword(ctx context.Context, userID string, hashedPassword string) error {
-	query := `UPDATE public.users SET password = $1 WHERE id = $2`
-	if _, err := u.conn.Exec(ctx, query, hashedPassword, userID); err != nil {
-		return err
+// userOptions converts optional columns of a users row into domain.UserOption values.
+func userOptions(emailVerified bool) []domain.UserOption {
+	var opts []domain.UserOption
+	if emailVerified {
+		opts = append(opts, domain.WithEmailVerified())
 	}
 
-	return nil
+	return opts
 }
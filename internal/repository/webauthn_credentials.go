@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+)
+
+// NewWebAuthnCredentials creates a new instance of WebAuthnCredentials using the provided Conn interface for database operations.
+func NewWebAuthnCredentials(conn Conn) *WebAuthnCredentials {
+	return &WebAuthnCredentials{
+		conn: conn,
+	}
+}
+
+// WebAuthnCredentials provides methods to interact with the webauthn_credentials table in the database.
+type WebAuthnCredentials struct {
+	conn Conn
+}
+
+// Save stores a new credential. Returns domain.ErrWebAuthnCredentialExists if a credential
+// with the same ID is already registered.
+func (w *WebAuthnCredentials) Save(ctx context.Context, credential domain.WebAuthnCredential) error {
+	transports := credential.Transports
+	if transports == nil {
+		transports = []string{}
+	}
+
+	query := `INSERT INTO public.webauthn_credentials (id, user_id, public_key, sign_count, transports)
+		VALUES ($1, $2, $3, $4, $5)`
+
+	if _, err := w.conn.Exec(
+		ctx,
+		query,
+		credential.ID,
+		credential.UserID,
+		credential.PublicKey,
+		int64(credential.SignCount),
+		transports,
+	); err != nil {
+		if isUniqueConstraintViolation(err) {
+			return domain.ErrWebAuthnCredentialExists
+		}
+
+		return err
+	}
+
+	return nil
+}
+
+// GetByID retrieves the credential with the given ID. Returns domain.ErrWebAuthnCredentialNotFound if there is none.
+func (w *WebAuthnCredentials) GetByID(ctx context.Context, id []byte) (domain.WebAuthnCredential, error) {
+	query := `SELECT user_id, public_key, sign_count, transports, created_at, last_used_at
+		FROM public.webauthn_credentials WHERE id = $1`
+
+	var (
+		credential = domain.WebAuthnCredential{ID: id}
+		signCount  int64
+		lastUsedAt sql.NullTime
+	)
+	if err := w.conn.QueryRow(ctx, query, id).Scan(
+		&credential.UserID,
+		&credential.PublicKey,
+		&signCount,
+		&credential.Transports,
+		&credential.CreatedAt,
+		&lastUsedAt,
+	); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.WebAuthnCredential{}, domain.ErrWebAuthnCredentialNotFound
+		}
+
+		return domain.WebAuthnCredential{}, err
+	}
+
+	credential.SignCount = uint32(signCount)
+	credential.LastUsedAt = lastUsedAt.Time
+
+	return credential, nil
+}
+
+// ListIDsByUserID returns the IDs of all credentials of the user.
+func (w *WebAuthnCredentials) ListIDsByUserID(ctx context.Context, userID string) ([][]byte, error) {
+	query := `SELECT COALESCE(array_agg(id), '{}') FROM public.webauthn_credentials WHERE user_id = $1`
+
+	var ids [][]byte
+	if err := w.conn.QueryRow(ctx, query, userID).Scan(&ids); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// UpdateSignCount stores the sign counter reported with the last assertion and marks the credential as used.
+// Returns domain.ErrWebAuthnCredentialNotFound if there is no credential with the given ID.
+func (w *WebAuthnCredentials) UpdateSignCount(ctx context.Context, id []byte, signCount uint32) error {
+	query := `UPDATE public.webauthn_credentials SET sign_count = $2, last_used_at = NOW() WHERE id = $1`
+
+	tag, err := w.conn.Exec(ctx, query, id, int64(signCount))
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return domain.ErrWebAuthnCredentialNotFound
+	}
+
+	return nil
+}
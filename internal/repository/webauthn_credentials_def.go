@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/riabininkf/go-modules/db"
+	"github.com/riabininkf/go-modules/di"
+)
+
+// DefWebAuthnCredentialsName is the name of the *WebAuthnCredentials definition.
+const DefWebAuthnCredentialsName = "repository.webauthn-credentials"
+
+func init() {
+	di.Add(
+		di.Def[*WebAuthnCredentials]{
+			Name: DefWebAuthnCredentialsName,
+			Build: func(ctn di.Container) (*WebAuthnCredentials, error) {
+				var conn *pgxpool.Pool
+				if err := ctn.Fill(db.DefPostgresName, &conn); err != nil {
+					return nil, err
+				}
+
+				return NewWebAuthnCredentials(conn), nil
+			},
+		},
+	)
+}
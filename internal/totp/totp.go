@@ -0,0 +1,101 @@
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// Parameters of generated codes. They are the defaults of RFC 6238 and the only ones most authenticator apps support.
+const (
+	Digits = 6
+	Period = 30 * time.Second
+
+	secretSize = 20
+)
+
+// encoding is the base32 encoding used by authenticator apps: upper case and without padding.
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random secret of 160 bits, the size recommended by RFC 4226 for HMAC-SHA1.
+func GenerateSecret() ([]byte, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return secret, nil
+}
+
+// EncodeSecret returns the secret in the base32 form users type into authenticator apps.
+func EncodeSecret(secret []byte) string {
+	return encoding.EncodeToString(secret)
+}
+
+// URI returns the otpauth:// URI of the secret, usually rendered as a QR code for authenticator apps.
+func URI(issuer string, accountName string, secret []byte) string {
+	params := url.Values{
+		"secret":    {EncodeSecret(secret)},
+		"issuer":    {issuer},
+		"algorithm": {"SHA1"},
+		"digits":    {fmt.Sprint(Digits)},
+		"period":    {fmt.Sprint(int(Period.Seconds()))},
+	}
+
+	label := url.PathEscape(issuer) + ":" + url.PathEscape(accountName)
+
+	return "otpauth://totp/" + label + "?" + params.Encode()
+}
+
+// Step returns the time step of RFC 6238 the given time falls into.
+func Step(now time.Time) int64 {
+	return now.Unix() / int64(Period.Seconds())
+}
+
+// Code returns the code for the given time step.
+func Code(secret []byte, step int64) string {
+	return hotp(secret, step, Digits)
+}
+
+// Validate checks the code against the current time step and skew steps before and after it,
+// to tolerate clock drift. Returns the matched time step, so that callers can reject codes that were already used.
+func Validate(secret []byte, code string, now time.Time, skew int64) (int64, bool) {
+	if len(code) != Digits {
+		return 0, false
+	}
+
+	current := Step(now)
+	for step := current - skew; step <= current+skew; step++ {
+		if subtle.ConstantTimeCompare([]byte(Code(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// hotp computes an HOTP value (RFC 4226, section 5.3) of the given number of digits.
+func hotp(secret []byte, counter int64, digits int) string {
+	var message [8]byte
+	binary.BigEndian.PutUint64(message[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(message[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulo := uint32(1)
+	for range digits {
+		modulo *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%modulo)
+}
@@ -0,0 +1,82 @@
+package totp_test
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/totp"
+)
+
+// rfcSecret is the SHA1 seed of the test vectors in RFC 6238, appendix B.
+var rfcSecret = []byte("12345678901234567890")
+
+func TestCode(t *testing.T) {
+	// RFC 6238, appendix B, SHA1 column; 6-digit codes are the tails of the 8-digit test vectors
+	testCases := []struct {
+		unix    int64
+		expCode string
+	}{
+		{unix: 59, expCode: "287082"},          // 94287082
+		{unix: 1111111109, expCode: "081804"},  // 07081804
+		{unix: 1111111111, expCode: "050471"},  // 14050471
+		{unix: 1234567890, expCode: "005924"},  // 89005924
+		{unix: 2000000000, expCode: "279037"},  // 69279037
+		{unix: 20000000000, expCode: "353130"}, // 65353130
+	}
+
+	for _, testCase := range testCases {
+		step := totp.Step(time.Unix(testCase.unix, 0))
+		assert.Equal(t, testCase.expCode, totp.Code(rfcSecret, step), "time %d", testCase.unix)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := totp.Step(now)
+
+	testCases := []struct {
+		name    string
+		code    string
+		expStep int64
+		expOK   bool
+	}{
+		{name: "current step", code: totp.Code(rfcSecret, current), expStep: current, expOK: true},
+		{name: "previous step", code: totp.Code(rfcSecret, current-1), expStep: current - 1, expOK: true},
+		{name: "next step", code: totp.Code(rfcSecret, current+1), expStep: current + 1, expOK: true},
+		{name: "outside of skew", code: totp.Code(rfcSecret, current-2)},
+		{name: "wrong length", code: "12345"},
+		{name: "wrong code", code: "000000"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			step, ok := totp.Validate(rfcSecret, testCase.code, now, 1)
+			assert.Equal(t, testCase.expOK, ok)
+			assert.Equal(t, testCase.expStep, step)
+		})
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(totp.URI("Auth Service", "user@example.com", rfcSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "otpauth", uri.Scheme)
+	assert.Equal(t, "totp", uri.Host)
+	assert.Equal(t, "/Auth Service:user@example.com", uri.Path)
+	assert.Equal(t, "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", uri.Query().Get("secret"))
+	assert.Equal(t, "Auth Service", uri.Query().Get("issuer"))
+	assert.Equal(t, "6", uri.Query().Get("digits"))
+	assert.Equal(t, "30", uri.Query().Get("period"))
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	assert.NoError(t, err)
+	assert.Len(t, secret, 20)
+}
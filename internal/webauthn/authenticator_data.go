@@ -0,0 +1,100 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Flags of the authenticator data (WebAuthn Level 2, section 6.1).
+const (
+	flagUserPresent            = 1 << 0
+	flagUserVerified           = 1 << 2
+	flagAttestedCredentialData = 1 << 6
+	flagExtensionData          = 1 << 7
+)
+
+const (
+	rpIDHashSize     = 32
+	aaguidSize       = 16
+	authDataMinSize  = rpIDHashSize + 1 + 4
+	maxCredentialIDs = 1023
+)
+
+var errMalformedAuthenticatorData = errors.New("malformed authenticator data")
+
+// authenticatorData is the parsed authenticator data of a registration or an assertion.
+type authenticatorData struct {
+	rpIDHash     []byte
+	flags        byte
+	signCount    uint32
+	credentialID []byte
+	publicKey    []byte
+}
+
+// parseAuthenticatorData parses the authenticator data. Attested credential data is present only on registration.
+func parseAuthenticatorData(data []byte) (authenticatorData, error) {
+	if len(data) < authDataMinSize {
+		return authenticatorData{}, fmt.Errorf("%w: too short", errMalformedAuthenticatorData)
+	}
+
+	parsed := authenticatorData{
+		rpIDHash:  data[:rpIDHashSize],
+		flags:     data[rpIDHashSize],
+		signCount: binary.BigEndian.Uint32(data[rpIDHashSize+1:]),
+	}
+
+	rest := data[authDataMinSize:]
+
+	if parsed.flags&flagAttestedCredentialData != 0 {
+		if len(rest) < aaguidSize+2 {
+			return authenticatorData{}, fmt.Errorf("%w: attested credential data is too short", errMalformedAuthenticatorData)
+		}
+
+		rest = rest[aaguidSize:]
+
+		idLength := int(binary.BigEndian.Uint16(rest))
+		rest = rest[2:]
+
+		if idLength == 0 || idLength > maxCredentialIDs || len(rest) < idLength {
+			return authenticatorData{}, fmt.Errorf("%w: invalid credential id length", errMalformedAuthenticatorData)
+		}
+
+		parsed.credentialID = rest[:idLength]
+		rest = rest[idLength:]
+
+		// the public key is followed by extensions, so its length is known only after decoding it
+		_, n, err := decodeCBOR(rest)
+		if err != nil {
+			return authenticatorData{}, fmt.Errorf("%w: invalid credential public key: %w", errMalformedAuthenticatorData, err)
+		}
+
+		parsed.publicKey = rest[:n]
+		rest = rest[n:]
+	}
+
+	if parsed.flags&flagExtensionData != 0 {
+		_, n, err := decodeCBOR(rest)
+		if err != nil {
+			return authenticatorData{}, fmt.Errorf("%w: invalid extensions: %w", errMalformedAuthenticatorData, err)
+		}
+
+		rest = rest[n:]
+	}
+
+	if len(rest) != 0 {
+		return authenticatorData{}, fmt.Errorf("%w: trailing data", errMalformedAuthenticatorData)
+	}
+
+	return parsed, nil
+}
+
+// userPresent reports whether the user was present during the ceremony.
+func (a authenticatorData) userPresent() bool {
+	return a.flags&flagUserPresent != 0
+}
+
+// userVerified reports whether the authenticator verified the user, e.g. with a PIN or biometrics.
+func (a authenticatorData) userVerified() bool {
+	return a.flags&flagUserVerified != 0
+}
@@ -0,0 +1,195 @@
+package webauthn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// CBOR major types (RFC 8949, section 3.1).
+const (
+	cborUnsigned   = 0
+	cborNegative   = 1
+	cborByteString = 2
+	cborTextString = 3
+	cborArray      = 4
+	cborMap        = 5
+	cborSimple     = 7
+)
+
+// cborMaxDepth limits nesting of decoded items. WebAuthn structures are at most a few levels deep.
+const cborMaxDepth = 16
+
+var errMalformedCBOR = errors.New("malformed cbor")
+
+// decodeCBOR decodes a single CBOR data item and returns it with the number of bytes consumed.
+// Only the subset used by WebAuthn is supported: integers, byte and text strings, arrays, maps, booleans and null,
+// all with definite lengths. Integers are decoded as int64, maps as map[any]any.
+func decodeCBOR(data []byte) (any, int, error) {
+	d := &cborDecoder{data: data}
+
+	value, err := d.decode(0)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return value, d.pos, nil
+}
+
+// cborDecoder reads CBOR data items from a buffer.
+type cborDecoder struct {
+	data []byte
+	pos  int
+}
+
+// decode reads the next data item.
+func (d *cborDecoder) decode(depth int) (any, error) {
+	if depth > cborMaxDepth {
+		return nil, fmt.Errorf("%w: nesting is too deep", errMalformedCBOR)
+	}
+
+	major, arg, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case cborUnsigned:
+		if arg > 1<<63-1 {
+			return nil, fmt.Errorf("%w: integer overflow", errMalformedCBOR)
+		}
+
+		return int64(arg), nil
+	case cborNegative:
+		if arg > 1<<63-1 {
+			return nil, fmt.Errorf("%w: integer overflow", errMalformedCBOR)
+		}
+
+		return -1 - int64(arg), nil
+	case cborByteString:
+		return d.readBytes(arg)
+	case cborTextString:
+		b, err := d.readBytes(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		return string(b), nil
+	case cborArray:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, fmt.Errorf("%w: unexpected end of data", errMalformedCBOR)
+		}
+
+		items := make([]any, 0, arg)
+		for range arg {
+			item, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, item)
+		}
+
+		return items, nil
+	case cborMap:
+		if arg > uint64(len(d.data)-d.pos) {
+			return nil, fmt.Errorf("%w: unexpected end of data", errMalformedCBOR)
+		}
+
+		items := make(map[any]any, arg)
+		for range arg {
+			key, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, fmt.Errorf("%w: unsupported map key type %T", errMalformedCBOR, key)
+			}
+
+			if _, ok := items[key]; ok {
+				return nil, fmt.Errorf("%w: duplicate map key %v", errMalformedCBOR, key)
+			}
+
+			if items[key], err = d.decode(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+
+		return items, nil
+	case cborSimple:
+		switch arg {
+		case 20:
+			return false, nil
+		case 21:
+			return true, nil
+		case 22:
+			return nil, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unsupported major type %d", errMalformedCBOR, major)
+}
+
+// readHead reads the initial byte of a data item and its argument.
+func (d *cborDecoder) readHead() (byte, uint64, error) {
+	if d.pos >= len(d.data) {
+		return 0, 0, fmt.Errorf("%w: unexpected end of data", errMalformedCBOR)
+	}
+
+	initial := d.data[d.pos]
+	d.pos++
+
+	major, info := initial>>5, initial&0x1f
+
+	var size int
+	switch {
+	case info < 24:
+		return major, uint64(info), nil
+	case info == 24:
+		size = 1
+	case info == 25:
+		size = 2
+	case info == 26:
+		size = 4
+	case info == 27:
+		size = 8
+	default:
+		// indefinite lengths and reserved values are not used by WebAuthn
+		return 0, 0, fmt.Errorf("%w: unsupported additional information %d", errMalformedCBOR, info)
+	}
+
+	if len(d.data)-d.pos < size {
+		return 0, 0, fmt.Errorf("%w: unexpected end of data", errMalformedCBOR)
+	}
+
+	var arg uint64
+	switch size {
+	case 1:
+		arg = uint64(d.data[d.pos])
+	case 2:
+		arg = uint64(binary.BigEndian.Uint16(d.data[d.pos:]))
+	case 4:
+		arg = uint64(binary.BigEndian.Uint32(d.data[d.pos:]))
+	case 8:
+		arg = binary.BigEndian.Uint64(d.data[d.pos:])
+	}
+
+	d.pos += size
+
+	return major, arg, nil
+}
+
+// readBytes reads the content of a string of the given length.
+func (d *cborDecoder) readBytes(length uint64) ([]byte, error) {
+	if length > uint64(len(d.data)-d.pos) {
+		return nil, fmt.Errorf("%w: unexpected end of data", errMalformedCBOR)
+	}
+
+	b := d.data[d.pos : d.pos+int(length)]
+	d.pos += int(length)
+
+	return b, nil
+}
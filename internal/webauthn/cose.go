@@ -0,0 +1,139 @@
+package webauthn
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// COSE algorithm identifiers supported for credentials (RFC 9053).
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+// Labels and values of COSE_Key parameters (RFC 9052, section 7 and RFC 9053, section 7).
+const (
+	coseKeyType   = 1
+	coseAlgorithm = 3
+
+	coseCurve = -1
+	coseX     = -2
+	coseY     = -3
+	coseN     = -1
+	coseE     = -2
+
+	coseKeyTypeOKP = 1
+	coseKeyTypeEC2 = 2
+	coseKeyTypeRSA = 3
+
+	coseCurveP256    = 1
+	coseCurveEd25519 = 6
+
+	// minRSAKeySize rejects weak RSA keys.
+	minRSAKeySize = 2048
+)
+
+// ErrUnsupportedAlgorithm is returned for credential public keys of algorithms other than ES256, EdDSA and RS256.
+var ErrUnsupportedAlgorithm = errors.New("unsupported credential algorithm")
+
+// SupportedAlgorithms lists the algorithms offered to authenticators, in the order of preference.
+var SupportedAlgorithms = []int64{AlgES256, AlgEdDSA, AlgRS256}
+
+// publicKey is a parsed COSE_Key.
+type publicKey struct {
+	alg int64
+	key crypto.PublicKey
+}
+
+// parsePublicKey parses a CBOR-encoded COSE_Key.
+func parsePublicKey(data []byte) (publicKey, error) {
+	value, n, err := decodeCBOR(data)
+	if err != nil {
+		return publicKey{}, err
+	}
+
+	if n != len(data) {
+		return publicKey{}, fmt.Errorf("%w: trailing data after public key", errMalformedCBOR)
+	}
+
+	params, ok := value.(map[any]any)
+	if !ok {
+		return publicKey{}, fmt.Errorf("%w: public key is not a map", errMalformedCBOR)
+	}
+
+	return parseCOSEKey(params)
+}
+
+// parseCOSEKey converts COSE_Key parameters into a public key of the algorithm they declare.
+func parseCOSEKey(params map[any]any) (publicKey, error) {
+	kty, _ := params[int64(coseKeyType)].(int64)
+	alg, _ := params[int64(coseAlgorithm)].(int64)
+
+	switch {
+	case alg == AlgES256 && kty == coseKeyTypeEC2:
+		crv, _ := params[int64(coseCurve)].(int64)
+		x, _ := params[int64(coseX)].([]byte)
+		y, _ := params[int64(coseY)].([]byte)
+
+		if crv != coseCurveP256 || len(x) != 32 || len(y) != 32 {
+			return publicKey{}, errors.New("invalid ES256 public key")
+		}
+
+		// ecdsa.ParseUncompressedPublicKey checks that the point is on the curve
+		key, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), append(append([]byte{4}, x...), y...))
+		if err != nil {
+			return publicKey{}, fmt.Errorf("invalid ES256 public key: %w", err)
+		}
+
+		return publicKey{alg: alg, key: key}, nil
+	case alg == AlgEdDSA && kty == coseKeyTypeOKP:
+		crv, _ := params[int64(coseCurve)].(int64)
+		x, _ := params[int64(coseX)].([]byte)
+
+		if crv != coseCurveEd25519 || len(x) != ed25519.PublicKeySize {
+			return publicKey{}, errors.New("invalid EdDSA public key")
+		}
+
+		return publicKey{alg: alg, key: ed25519.PublicKey(x)}, nil
+	case alg == AlgRS256 && kty == coseKeyTypeRSA:
+		n, _ := params[int64(coseN)].([]byte)
+		e, _ := params[int64(coseE)].([]byte)
+
+		if len(e) == 0 || len(e) > 4 {
+			return publicKey{}, errors.New("invalid RS256 public key")
+		}
+
+		key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		if key.N.BitLen() < minRSAKeySize || key.E < 3 {
+			return publicKey{}, errors.New("invalid RS256 public key")
+		}
+
+		return publicKey{alg: alg, key: key}, nil
+	}
+
+	return publicKey{}, fmt.Errorf("%w: kty %d, alg %d", ErrUnsupportedAlgorithm, kty, alg)
+}
+
+// verify checks the signature of the message.
+func (k publicKey) verify(message []byte, signature []byte) bool {
+	switch key := k.key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(message)
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, message, signature)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(message)
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature) == nil
+	}
+
+	return false
+}
@@ -0,0 +1,124 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	domain "github.com/riabininkf/http-auth-example/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// CredentialStorage is an autogenerated mock type for the CredentialStorage type
+type CredentialStorage struct {
+	mock.Mock
+}
+
+// GetByID provides a mock function with given fields: ctx, id
+func (_m *CredentialStorage) GetByID(ctx context.Context, id []byte) (domain.WebAuthnCredential, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 domain.WebAuthnCredential
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) (domain.WebAuthnCredential, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte) domain.WebAuthnCredential); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(domain.WebAuthnCredential)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListIDsByUserID provides a mock function with given fields: ctx, userID
+func (_m *CredentialStorage) ListIDsByUserID(ctx context.Context, userID string) ([][]byte, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListIDsByUserID")
+	}
+
+	var r0 [][]byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([][]byte, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) [][]byte); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([][]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Save provides a mock function with given fields: ctx, credential
+func (_m *CredentialStorage) Save(ctx context.Context, credential domain.WebAuthnCredential) error {
+	ret := _m.Called(ctx, credential)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Save")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.WebAuthnCredential) error); ok {
+		r0 = rf(ctx, credential)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSignCount provides a mock function with given fields: ctx, id, signCount
+func (_m *CredentialStorage) UpdateSignCount(ctx context.Context, id []byte, signCount uint32) error {
+	ret := _m.Called(ctx, id, signCount)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateSignCount")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, uint32) error); ok {
+		r0 = rf(ctx, id, signCount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewCredentialStorage creates a new instance of CredentialStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewCredentialStorage(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *CredentialStorage {
+	mock := &CredentialStorage{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
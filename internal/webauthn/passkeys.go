@@ -0,0 +1,231 @@
+package webauthn
+
+//go:generate mockery --name Cache --output ./mocks --outpkg mocks --filename cache.go --structname Cache
+//go:generate mockery --name CredentialStorage --output ./mocks --outpkg mocks --filename credential_storage.go --structname CredentialStorage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+)
+
+// ErrInvalidCeremony is returned when the challenge of a response is unknown, expired, already used
+// or was issued for another ceremony or user.
+var ErrInvalidCeremony = errors.New("invalid webauthn ceremony")
+
+const (
+	ceremonyKeyPrefix = "webauthn:ceremony:"
+	challengeSize     = 32
+
+	ceremonyRegistration = "registration"
+	ceremonyLogin        = "login"
+)
+
+// NewPasskeys creates a new *Passkeys instance with the provided relying party, cache and credential storage.
+func NewPasskeys(
+	rp *RelyingParty,
+	cache Cache,
+	storage CredentialStorage,
+) *Passkeys {
+	return &Passkeys{
+		rp:      rp,
+		cache:   cache,
+		storage: storage,
+	}
+}
+
+type (
+	// Passkeys runs WebAuthn registration and login ceremonies. The challenge of a started ceremony is kept
+	// in the cache for the ceremony timeout and can be answered only once.
+	Passkeys struct {
+		rp      *RelyingParty
+		cache   Cache
+		storage CredentialStorage
+	}
+
+	// Cache defines methods for storing values with a TTL and atomically reading and removing them.
+	Cache interface {
+		Set(ctx context.Context, key string, value any, ttl time.Duration) error
+		GetDel(ctx context.Context, key string) (string, error)
+	}
+
+	// CredentialStorage defines methods for persisting WebAuthn credentials.
+	CredentialStorage interface {
+		Save(ctx context.Context, credential domain.WebAuthnCredential) error
+		GetByID(ctx context.Context, id []byte) (domain.WebAuthnCredential, error)
+		ListIDsByUserID(ctx context.Context, userID string) ([][]byte, error)
+		UpdateSignCount(ctx context.Context, id []byte, signCount uint32) error
+	}
+
+	// ceremony is the state of a started ceremony. UserID is empty for logins, as the user is not known
+	// until the authenticator picks a credential.
+	ceremony struct {
+		Type   string `json:"type"`
+		UserID string `json:"user_id"`
+	}
+)
+
+// BeginRegistration starts the registration of a new passkey for the user.
+func (p *Passkeys) BeginRegistration(
+	ctx context.Context,
+	userID string,
+	userName string,
+) (CredentialCreationOptions, error) {
+	excludeIDs, err := p.storage.ListIDsByUserID(ctx, userID)
+	if err != nil {
+		return CredentialCreationOptions{}, fmt.Errorf("failed to list webauthn credentials: %w", err)
+	}
+
+	var challenge []byte
+	if challenge, err = p.begin(ctx, ceremony{Type: ceremonyRegistration, UserID: userID}); err != nil {
+		return CredentialCreationOptions{}, err
+	}
+
+	return p.rp.CreationOptions(challenge, []byte(userID), userName, excludeIDs), nil
+}
+
+// FinishRegistration verifies the response of the authenticator and stores the new credential of the user.
+// Returns ErrInvalidCeremony if the registration was not started by the user, ErrInvalidCredential
+// if the response fails verification and domain.ErrWebAuthnCredentialExists if the credential is already registered.
+func (p *Passkeys) FinishRegistration(
+	ctx context.Context,
+	userID string,
+	credential RegistrationCredential,
+) (domain.WebAuthnCredential, error) {
+	challenge, err := p.finish(ctx, credential.Response.ClientDataJSON, ceremonyRegistration, userID)
+	if err != nil {
+		return domain.WebAuthnCredential{}, err
+	}
+
+	var verified Credential
+	if verified, err = p.rp.VerifyRegistration(challenge, credential); err != nil {
+		return domain.WebAuthnCredential{}, err
+	}
+
+	stored := domain.WebAuthnCredential{
+		ID:         verified.ID,
+		UserID:     userID,
+		PublicKey:  verified.PublicKey,
+		SignCount:  verified.SignCount,
+		Transports: verified.Transports,
+	}
+
+	if err = p.storage.Save(ctx, stored); err != nil {
+		return domain.WebAuthnCredential{}, err
+	}
+
+	return stored, nil
+}
+
+// BeginLogin starts a passwordless login with a passkey.
+func (p *Passkeys) BeginLogin(ctx context.Context) (CredentialRequestOptions, error) {
+	challenge, err := p.begin(ctx, ceremony{Type: ceremonyLogin})
+	if err != nil {
+		return CredentialRequestOptions{}, err
+	}
+
+	return p.rp.RequestOptions(challenge), nil
+}
+
+// FinishLogin verifies the assertion of the authenticator and returns the ID of the user owning the credential.
+// Returns ErrInvalidCeremony if the login was not started or ErrInvalidCredential if the credential
+// is unknown or the assertion fails verification.
+func (p *Passkeys) FinishLogin(ctx context.Context, credential AuthenticationCredential) (string, error) {
+	challenge, err := p.finish(ctx, credential.Response.ClientDataJSON, ceremonyLogin, "")
+	if err != nil {
+		return "", err
+	}
+
+	var id []byte
+	if id, err = encoding.DecodeString(credential.RawID); err != nil {
+		return "", fmt.Errorf("%w: raw id is not base64url: %w", ErrInvalidCredential, err)
+	}
+
+	var stored domain.WebAuthnCredential
+	if stored, err = p.storage.GetByID(ctx, id); err != nil {
+		if errors.Is(err, domain.ErrWebAuthnCredentialNotFound) {
+			return "", fmt.Errorf("%w: %w", ErrInvalidCredential, err)
+		}
+
+		return "", err
+	}
+
+	// the user handle is set for discoverable credentials and must point to the owner of the credential
+	if credential.Response.UserHandle != "" && credential.Response.UserHandle != encoding.EncodeToString([]byte(stored.UserID)) {
+		return "", fmt.Errorf("%w: user handle does not match the credential owner", ErrInvalidCredential)
+	}
+
+	var signCount uint32
+	if signCount, err = p.rp.VerifyAssertion(challenge, stored.PublicKey, stored.SignCount, credential); err != nil {
+		return "", err
+	}
+
+	if err = p.storage.UpdateSignCount(ctx, stored.ID, signCount); err != nil {
+		return "", fmt.Errorf("failed to update sign count: %w", err)
+	}
+
+	return stored.UserID, nil
+}
+
+// begin generates a challenge and stores the ceremony under it.
+func (p *Passkeys) begin(ctx context.Context, state ceremony) ([]byte, error) {
+	challenge := make([]byte, challengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return nil, fmt.Errorf("failed to generate challenge: %w", err)
+	}
+
+	value, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal webauthn ceremony: %w", err)
+	}
+
+	if err = p.cache.Set(ctx, p.key(challenge), string(value), p.rp.Timeout()); err != nil {
+		return nil, err
+	}
+
+	return challenge, nil
+}
+
+// finish removes the ceremony started with the challenge from the client data and checks that it is
+// of the expected type and user. The ceremony is removed even if the response turns out to be invalid,
+// so each challenge can be answered only once.
+func (p *Passkeys) finish(ctx context.Context, clientDataJSON string, ceremonyType string, userID string) ([]byte, error) {
+	challenge, err := ClientDataChallenge(clientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var value string
+	if value, err = p.cache.GetDel(ctx, p.key(challenge)); err != nil {
+		if errors.Is(err, redis.ErrNotFound) {
+			return nil, ErrInvalidCeremony
+		}
+
+		return nil, err
+	}
+
+	var state ceremony
+	if err = json.Unmarshal([]byte(value), &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal webauthn ceremony: %w", err)
+	}
+
+	if state.Type != ceremonyType || state.UserID != userID {
+		return nil, ErrInvalidCeremony
+	}
+
+	return challenge, nil
+}
+
+// key returns the cache key for the challenge. Only the hash is stored.
+func (p *Passkeys) key(challenge []byte) string {
+	sum := sha256.Sum256(challenge)
+	return ceremonyKeyPrefix + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,67 @@
+package webauthn
+
+import (
+	"time"
+
+	"github.com/riabininkf/go-modules/config"
+	"github.com/riabininkf/go-modules/di"
+
+	"github.com/riabininkf/http-auth-example/internal/redis"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+const (
+	// DefPasskeysName is the name of the *Passkeys definition.
+	DefPasskeysName = "webauthn.passkeys"
+
+	configKeyRPID            = "auth.webauthn.rpID"
+	configKeyRPName          = "auth.webauthn.rpName"
+	configKeyOrigins         = "auth.webauthn.origins"
+	configKeyCeremonyTimeout = "auth.webauthn.ceremonyTimeout"
+)
+
+func init() {
+	di.Add(
+		di.Def[*Passkeys]{
+			Name: DefPasskeysName,
+			Build: func(ctn di.Container) (*Passkeys, error) {
+				var cfg *config.Config
+				if err := ctn.Fill(config.DefName, &cfg); err != nil {
+					return nil, err
+				}
+
+				var rpID string
+				if rpID = cfg.GetString(configKeyRPID); rpID == "" {
+					return nil, config.NewErrMissingKey(configKeyRPID)
+				}
+
+				var rpName string
+				if rpName = cfg.GetString(configKeyRPName); rpName == "" {
+					return nil, config.NewErrMissingKey(configKeyRPName)
+				}
+
+				var origins []string
+				if origins = cfg.GetStringSlice(configKeyOrigins); len(origins) == 0 {
+					return nil, config.NewErrMissingKey(configKeyOrigins)
+				}
+
+				var timeout time.Duration
+				if timeout = cfg.GetDuration(configKeyCeremonyTimeout); timeout == 0 {
+					return nil, config.NewErrMissingKey(configKeyCeremonyTimeout)
+				}
+
+				var cache *redis.Client
+				if err := ctn.Fill(redis.DefClientName, &cache); err != nil {
+					return nil, err
+				}
+
+				var storage *repository.WebAuthnCredentials
+				if err := ctn.Fill(repository.DefWebAuthnCredentialsName, &storage); err != nil {
+					return nil, err
+				}
+
+				return NewPasskeys(NewRelyingParty(rpID, rpName, origins, timeout), cache, storage), nil
+			},
+		},
+	)
+}
@@ -0,0 +1,287 @@
+package webauthn_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/redis"
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+	"github.com/riabininkf/http-auth-example/internal/webauthn/mocks"
+	"github.com/riabininkf/http-auth-example/internal/webauthn/webauthntest"
+)
+
+const (
+	rpID   = "localhost"
+	origin = "http://localhost:3000"
+)
+
+func TestPasskeys_Registration(t *testing.T) {
+	testCases := map[string]struct {
+		authenticator *webauthntest.Authenticator
+		finishUserID  string
+		tamper        func(credential *webauthn.RegistrationCredential)
+		onSave        func(storage *mocks.CredentialStorage)
+		expErr        error
+	}{
+		"registration started by another user": {
+			authenticator: webauthntest.NewAuthenticator(origin),
+			finishUserID:  "another_user_id",
+			expErr:        webauthn.ErrInvalidCeremony,
+		},
+		"origin is not allowed": {
+			authenticator: webauthntest.NewAuthenticator("https://evil.example.com"),
+			finishUserID:  "user_id",
+			expErr:        webauthn.ErrInvalidCredential,
+		},
+		"credential id does not match": {
+			authenticator: webauthntest.NewAuthenticator(origin),
+			finishUserID:  "user_id",
+			tamper: func(credential *webauthn.RegistrationCredential) {
+				credential.RawID = "AAAA"
+			},
+			expErr: webauthn.ErrInvalidCredential,
+		},
+		"credential already exists": {
+			authenticator: webauthntest.NewAuthenticator(origin),
+			finishUserID:  "user_id",
+			onSave: func(storage *mocks.CredentialStorage) {
+				storage.On("Save", t.Context(), mock.AnythingOfType("domain.WebAuthnCredential")).
+					Return(domain.ErrWebAuthnCredentialExists)
+			},
+			expErr: domain.ErrWebAuthnCredentialExists,
+		},
+		"positive case": {
+			authenticator: webauthntest.NewAuthenticator(origin),
+			finishUserID:  "user_id",
+			onSave: func(storage *mocks.CredentialStorage) {
+				storage.On("Save", t.Context(), mock.AnythingOfType("domain.WebAuthnCredential")).Return(nil)
+			},
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			storage := mocks.NewCredentialStorage(t)
+			storage.On("ListIDsByUserID", t.Context(), "user_id").Return([][]byte{}, nil)
+
+			if tc.onSave != nil {
+				tc.onSave(storage)
+			}
+
+			passkeys := newPasskeys(t, storage)
+
+			options, err := passkeys.BeginRegistration(t.Context(), "user_id", "user@example.com")
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			assert.Equal(t, rpID, options.RP.ID)
+			assert.Equal(t, "user@example.com", options.User.Name)
+			assert.Equal(t, int64(60000), options.Timeout)
+
+			var credential webauthn.RegistrationCredential
+			if credential, err = tc.authenticator.Register(options); err != nil {
+				t.Fatal(err)
+			}
+
+			if tc.tamper != nil {
+				tc.tamper(&credential)
+			}
+
+			var stored domain.WebAuthnCredential
+			stored, err = passkeys.FinishRegistration(t.Context(), tc.finishUserID, credential)
+			assert.ErrorIs(t, err, tc.expErr)
+
+			if tc.expErr == nil {
+				assert.Equal(t, "user_id", stored.UserID)
+				assert.NotEmpty(t, stored.ID)
+				assert.NotEmpty(t, stored.PublicKey)
+				assert.Equal(t, []string{"internal"}, stored.Transports)
+			}
+		})
+	}
+}
+
+func TestPasskeys_Login(t *testing.T) {
+	authenticator := webauthntest.NewAuthenticator(origin)
+
+	var registered domain.WebAuthnCredential
+
+	storage := mocks.NewCredentialStorage(t)
+	storage.On("ListIDsByUserID", t.Context(), "user_id").Return([][]byte{}, nil)
+	storage.On("Save", t.Context(), mock.AnythingOfType("domain.WebAuthnCredential")).
+		Run(func(args mock.Arguments) { registered = args.Get(1).(domain.WebAuthnCredential) }).
+		Return(nil)
+
+	passkeys := newPasskeys(t, storage)
+
+	creationOptions, err := passkeys.BeginRegistration(t.Context(), "user_id", "user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var registration webauthn.RegistrationCredential
+	if registration, err = authenticator.Register(creationOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = passkeys.FinishRegistration(t.Context(), "user_id", registration); err != nil {
+		t.Fatal(err)
+	}
+
+	// the clone lags behind the original once the original is used
+	clone := authenticator.Clone()
+
+	// the stranger holds a credential that was never registered
+	stranger := webauthntest.NewAuthenticator(origin)
+	if _, err = stranger.Register(creationOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := map[string]struct {
+		authenticator *webauthntest.Authenticator
+		onGetByID     func(storage *mocks.CredentialStorage)
+		expUserID     string
+		expErr        error
+	}{
+		"unknown credential": {
+			authenticator: stranger,
+			onGetByID: func(storage *mocks.CredentialStorage) {
+				storage.On("GetByID", t.Context(), mock.Anything).
+					Return(domain.WebAuthnCredential{}, domain.ErrWebAuthnCredentialNotFound)
+			},
+			expErr: webauthn.ErrInvalidCredential,
+		},
+		"failed to get credential": {
+			authenticator: authenticator,
+			onGetByID: func(storage *mocks.CredentialStorage) {
+				storage.On("GetByID", t.Context(), registered.ID).Return(domain.WebAuthnCredential{}, assert.AnError)
+			},
+			expErr: assert.AnError,
+		},
+		"positive case": {
+			authenticator: authenticator,
+			onGetByID: func(storage *mocks.CredentialStorage) {
+				storage.On("GetByID", t.Context(), registered.ID).Return(registered, nil)
+				storage.On("UpdateSignCount", t.Context(), registered.ID, mock.AnythingOfType("uint32")).Return(nil)
+			},
+			expUserID: "user_id",
+		},
+		"cloned authenticator": {
+			authenticator: clone,
+			onGetByID: func(storage *mocks.CredentialStorage) {
+				stored := registered
+				stored.SignCount = 1
+
+				storage.On("GetByID", t.Context(), registered.ID).Return(stored, nil)
+			},
+			expErr: webauthn.ErrInvalidCredential,
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			storage := mocks.NewCredentialStorage(t)
+			tc.onGetByID(storage)
+
+			passkeys := newPasskeys(t, storage)
+
+			options, err := passkeys.BeginLogin(t.Context())
+			if !assert.NoError(t, err) {
+				t.FailNow()
+			}
+
+			var credential webauthn.AuthenticationCredential
+			if credential, err = tc.authenticator.Login(options); err != nil {
+				t.Fatal(err)
+			}
+
+			var userID string
+			userID, err = passkeys.FinishLogin(t.Context(), credential)
+			assert.Equal(t, tc.expUserID, userID)
+			assert.ErrorIs(t, err, tc.expErr)
+		})
+	}
+
+	t.Run("challenge is single-use", func(t *testing.T) {
+		storage := mocks.NewCredentialStorage(t)
+		storage.On("GetByID", t.Context(), registered.ID).Return(registered, nil).Once()
+		storage.On("UpdateSignCount", t.Context(), registered.ID, mock.AnythingOfType("uint32")).Return(nil).Once()
+
+		passkeys := newPasskeys(t, storage)
+
+		options, err := passkeys.BeginLogin(t.Context())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var credential webauthn.AuthenticationCredential
+		if credential, err = authenticator.Login(options); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = passkeys.FinishLogin(t.Context(), credential)
+		assert.NoError(t, err)
+
+		_, err = passkeys.FinishLogin(t.Context(), credential)
+		assert.ErrorIs(t, err, webauthn.ErrInvalidCeremony)
+	})
+
+	t.Run("registration challenge cannot be used to login", func(t *testing.T) {
+		storage := mocks.NewCredentialStorage(t)
+		storage.On("ListIDsByUserID", t.Context(), "user_id").Return([][]byte{registered.ID}, nil)
+
+		passkeys := newPasskeys(t, storage)
+
+		options, err := passkeys.BeginRegistration(t.Context(), "user_id", "user@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assert.Len(t, options.ExcludeCredentials, 1)
+
+		var credential webauthn.AuthenticationCredential
+		if credential, err = authenticator.Login(webauthn.CredentialRequestOptions{
+			Challenge: options.Challenge,
+			RPID:      rpID,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = passkeys.FinishLogin(t.Context(), credential)
+		assert.ErrorIs(t, err, webauthn.ErrInvalidCeremony)
+	})
+}
+
+// newPasskeys creates *webauthn.Passkeys backed by a map-based cache mock.
+func newPasskeys(t *testing.T, storage *mocks.CredentialStorage) *webauthn.Passkeys {
+	values := make(map[string]string)
+
+	cache := mocks.NewCache(t)
+	cache.On("Set", t.Context(), mock.AnythingOfType("string"), mock.AnythingOfType("string"), time.Minute).
+		Maybe().
+		Run(func(args mock.Arguments) { values[args.String(1)] = args.Get(2).(string) }).
+		Return(nil)
+	cache.On("GetDel", t.Context(), mock.AnythingOfType("string")).
+		Maybe().
+		Return(func(_ context.Context, key string) (string, error) {
+			value, ok := values[key]
+			if !ok {
+				return "", redis.ErrNotFound
+			}
+
+			delete(values, key)
+			return value, nil
+		})
+
+	return webauthn.NewPasskeys(
+		webauthn.NewRelyingParty(rpID, "Auth Service", []string{origin}, time.Minute),
+		cache,
+		storage,
+	)
+}
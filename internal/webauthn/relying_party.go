@@ -0,0 +1,413 @@
+package webauthn
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Types of client data, one per ceremony.
+const (
+	clientDataTypeCreate = "webauthn.create"
+	clientDataTypeGet    = "webauthn.get"
+)
+
+// credentialTypePublicKey is the only credential type defined by WebAuthn.
+const credentialTypePublicKey = "public-key"
+
+// ErrInvalidCredential is returned when a registration or an assertion fails verification.
+var ErrInvalidCredential = errors.New("invalid webauthn credential")
+
+// encoding is the encoding of binary fields in the JSON serialization of WebAuthn structures.
+var encoding = base64.RawURLEncoding
+
+// NewRelyingParty creates a new *RelyingParty with the RP ID (the domain credentials are scoped to),
+// a human-readable name, the origins allowed to run ceremonies and the ceremony timeout.
+func NewRelyingParty(
+	id string,
+	name string,
+	origins []string,
+	timeout time.Duration,
+) *RelyingParty {
+	allowed := make(map[string]struct{}, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = struct{}{}
+	}
+
+	return &RelyingParty{
+		id:      id,
+		idHash:  sha256.Sum256([]byte(id)),
+		name:    name,
+		origins: allowed,
+		timeout: timeout,
+	}
+}
+
+type (
+	// RelyingParty builds WebAuthn ceremony options and verifies the responses of authenticators
+	// (WebAuthn Level 2, sections 7.1 and 7.2). Attestation statements are not verified: the service requests
+	// "none" attestation, as it does not restrict which authenticators users may register.
+	RelyingParty struct {
+		id      string
+		idHash  [sha256.Size]byte
+		name    string
+		origins map[string]struct{}
+		timeout time.Duration
+	}
+
+	// CredentialCreationOptions is passed to navigator.credentials.create() to register a new credential.
+	CredentialCreationOptions struct {
+		RP                     RelyingPartyEntity     `json:"rp"`
+		User                   UserEntity             `json:"user"`
+		Challenge              string                 `json:"challenge"`
+		PubKeyCredParams       []CredentialParameters `json:"pubKeyCredParams"`
+		Timeout                int64                  `json:"timeout"`
+		ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+		AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+		Attestation            string                 `json:"attestation"`
+	}
+
+	// CredentialRequestOptions is passed to navigator.credentials.get() to sign in with a passkey.
+	// No credentials are listed, so the authenticator offers discoverable credentials of the RP.
+	CredentialRequestOptions struct {
+		Challenge        string `json:"challenge"`
+		Timeout          int64  `json:"timeout"`
+		RPID             string `json:"rpId"`
+		UserVerification string `json:"userVerification"`
+	}
+
+	// RelyingPartyEntity describes the relying party to the authenticator.
+	RelyingPartyEntity struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	// UserEntity describes the user account the credential is created for.
+	UserEntity struct {
+		ID          string `json:"id"`
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	}
+
+	// CredentialParameters is a credential type and algorithm the relying party accepts.
+	CredentialParameters struct {
+		Type string `json:"type"`
+		Alg  int64  `json:"alg"`
+	}
+
+	// CredentialDescriptor identifies an existing credential.
+	CredentialDescriptor struct {
+		Type string `json:"type"`
+		ID   string `json:"id"`
+	}
+
+	// AuthenticatorSelection states the requirements for the authenticator.
+	AuthenticatorSelection struct {
+		ResidentKey      string `json:"residentKey"`
+		UserVerification string `json:"userVerification"`
+	}
+
+	// RegistrationCredential is the JSON serialization of the credential returned by navigator.credentials.create().
+	RegistrationCredential struct {
+		ID       string                           `json:"id"`
+		RawID    string                           `json:"rawId"`
+		Type     string                           `json:"type"`
+		Response AuthenticatorAttestationResponse `json:"response"`
+	}
+
+	// AuthenticatorAttestationResponse is the response of the authenticator to a registration.
+	AuthenticatorAttestationResponse struct {
+		ClientDataJSON    string   `json:"clientDataJSON"`
+		AttestationObject string   `json:"attestationObject"`
+		Transports        []string `json:"transports"`
+	}
+
+	// AuthenticationCredential is the JSON serialization of the credential returned by navigator.credentials.get().
+	AuthenticationCredential struct {
+		ID       string                         `json:"id"`
+		RawID    string                         `json:"rawId"`
+		Type     string                         `json:"type"`
+		Response AuthenticatorAssertionResponse `json:"response"`
+	}
+
+	// AuthenticatorAssertionResponse is the response of the authenticator to an authentication.
+	AuthenticatorAssertionResponse struct {
+		ClientDataJSON    string `json:"clientDataJSON"`
+		AuthenticatorData string `json:"authenticatorData"`
+		Signature         string `json:"signature"`
+		UserHandle        string `json:"userHandle"`
+	}
+
+	// Credential is a verified new credential.
+	Credential struct {
+		ID         []byte
+		PublicKey  []byte
+		SignCount  uint32
+		Transports []string
+	}
+
+	// collectedClientData is the client data signed by the authenticator.
+	collectedClientData struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+		Origin    string `json:"origin"`
+	}
+)
+
+// CreationOptions returns the options of a registration ceremony for the user. Existing credentials of the user
+// are excluded, so that the same authenticator is not registered twice.
+func (r *RelyingParty) CreationOptions(
+	challenge []byte,
+	userHandle []byte,
+	userName string,
+	excludeIDs [][]byte,
+) CredentialCreationOptions {
+	params := make([]CredentialParameters, 0, len(SupportedAlgorithms))
+	for _, alg := range SupportedAlgorithms {
+		params = append(params, CredentialParameters{Type: credentialTypePublicKey, Alg: alg})
+	}
+
+	exclude := make([]CredentialDescriptor, 0, len(excludeIDs))
+	for _, id := range excludeIDs {
+		exclude = append(exclude, CredentialDescriptor{Type: credentialTypePublicKey, ID: encoding.EncodeToString(id)})
+	}
+
+	return CredentialCreationOptions{
+		RP: RelyingPartyEntity{
+			ID:   r.id,
+			Name: r.name,
+		},
+		User: UserEntity{
+			ID:          encoding.EncodeToString(userHandle),
+			Name:        userName,
+			DisplayName: userName,
+		},
+		Challenge:          encoding.EncodeToString(challenge),
+		PubKeyCredParams:   params,
+		Timeout:            r.timeout.Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: AuthenticatorSelection{
+			ResidentKey:      "required",
+			UserVerification: "required",
+		},
+		Attestation: "none",
+	}
+}
+
+// RequestOptions returns the options of an authentication ceremony.
+func (r *RelyingParty) RequestOptions(challenge []byte) CredentialRequestOptions {
+	return CredentialRequestOptions{
+		Challenge:        encoding.EncodeToString(challenge),
+		Timeout:          r.timeout.Milliseconds(),
+		RPID:             r.id,
+		UserVerification: "required",
+	}
+}
+
+// Timeout returns the time a ceremony may take.
+func (r *RelyingParty) Timeout() time.Duration {
+	return r.timeout
+}
+
+// VerifyRegistration verifies the response to a registration ceremony started with the challenge
+// and returns the new credential.
+func (r *RelyingParty) VerifyRegistration(challenge []byte, credential RegistrationCredential) (Credential, error) {
+	if credential.Type != credentialTypePublicKey {
+		return Credential{}, fmt.Errorf("%w: unexpected credential type %q", ErrInvalidCredential, credential.Type)
+	}
+
+	if err := r.verifyClientData(credential.Response.ClientDataJSON, clientDataTypeCreate, challenge); err != nil {
+		return Credential{}, err
+	}
+
+	attestationObject, err := encoding.DecodeString(credential.Response.AttestationObject)
+	if err != nil {
+		return Credential{}, fmt.Errorf("%w: attestation object is not base64url: %w", ErrInvalidCredential, err)
+	}
+
+	var authData []byte
+	if authData, err = parseAttestationObject(attestationObject); err != nil {
+		return Credential{}, fmt.Errorf("%w: %w", ErrInvalidCredential, err)
+	}
+
+	var parsed authenticatorData
+	if parsed, err = r.verifyAuthenticatorData(authData); err != nil {
+		return Credential{}, err
+	}
+
+	if parsed.credentialID == nil {
+		return Credential{}, fmt.Errorf("%w: attested credential data is missing", ErrInvalidCredential)
+	}
+
+	if encoding.EncodeToString(parsed.credentialID) != credential.RawID {
+		return Credential{}, fmt.Errorf("%w: credential id does not match authenticator data", ErrInvalidCredential)
+	}
+
+	if _, err = parsePublicKey(parsed.publicKey); err != nil {
+		return Credential{}, fmt.Errorf("%w: %w", ErrInvalidCredential, err)
+	}
+
+	return Credential{
+		ID:         parsed.credentialID,
+		PublicKey:  parsed.publicKey,
+		SignCount:  parsed.signCount,
+		Transports: credential.Response.Transports,
+	}, nil
+}
+
+// VerifyAssertion verifies the response to an authentication ceremony started with the challenge against
+// the stored public key and sign counter of the credential. Returns the new sign counter.
+func (r *RelyingParty) VerifyAssertion(
+	challenge []byte,
+	storedPublicKey []byte,
+	storedSignCount uint32,
+	credential AuthenticationCredential,
+) (uint32, error) {
+	if credential.Type != credentialTypePublicKey {
+		return 0, fmt.Errorf("%w: unexpected credential type %q", ErrInvalidCredential, credential.Type)
+	}
+
+	if err := r.verifyClientData(credential.Response.ClientDataJSON, clientDataTypeGet, challenge); err != nil {
+		return 0, err
+	}
+
+	authData, err := encoding.DecodeString(credential.Response.AuthenticatorData)
+	if err != nil {
+		return 0, fmt.Errorf("%w: authenticator data is not base64url: %w", ErrInvalidCredential, err)
+	}
+
+	var parsed authenticatorData
+	if parsed, err = r.verifyAuthenticatorData(authData); err != nil {
+		return 0, err
+	}
+
+	var signature []byte
+	if signature, err = encoding.DecodeString(credential.Response.Signature); err != nil {
+		return 0, fmt.Errorf("%w: signature is not base64url: %w", ErrInvalidCredential, err)
+	}
+
+	var key publicKey
+	if key, err = parsePublicKey(storedPublicKey); err != nil {
+		return 0, fmt.Errorf("failed to parse stored public key: %w", err)
+	}
+
+	// the client data was checked above, so decoding cannot fail
+	clientData, _ := encoding.DecodeString(credential.Response.ClientDataJSON)
+	clientDataHash := sha256.Sum256(clientData)
+
+	if !key.verify(slices.Concat(authData, clientDataHash[:]), signature) {
+		return 0, fmt.Errorf("%w: invalid signature", ErrInvalidCredential)
+	}
+
+	// authenticators without a counter always report zero; otherwise it must grow, or the credential was cloned
+	if (parsed.signCount != 0 || storedSignCount != 0) && parsed.signCount <= storedSignCount {
+		return 0, fmt.Errorf("%w: sign counter did not increase", ErrInvalidCredential)
+	}
+
+	return parsed.signCount, nil
+}
+
+// verifyClientData checks the type, challenge and origin of the client data.
+func (r *RelyingParty) verifyClientData(clientDataJSON string, expectedType string, challenge []byte) error {
+	clientData, err := parseClientData(clientDataJSON)
+	if err != nil {
+		return err
+	}
+
+	if clientData.Type != expectedType {
+		return fmt.Errorf("%w: unexpected client data type %q", ErrInvalidCredential, clientData.Type)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(clientData.Challenge), []byte(encoding.EncodeToString(challenge))) != 1 {
+		return fmt.Errorf("%w: challenge does not match", ErrInvalidCredential)
+	}
+
+	if _, ok := r.origins[clientData.Origin]; !ok {
+		return fmt.Errorf("%w: origin %q is not allowed", ErrInvalidCredential, clientData.Origin)
+	}
+
+	return nil
+}
+
+// verifyAuthenticatorData parses the authenticator data and checks the RP ID hash and the user flags.
+func (r *RelyingParty) verifyAuthenticatorData(data []byte) (authenticatorData, error) {
+	parsed, err := parseAuthenticatorData(data)
+	if err != nil {
+		return authenticatorData{}, fmt.Errorf("%w: %w", ErrInvalidCredential, err)
+	}
+
+	if subtle.ConstantTimeCompare(parsed.rpIDHash, r.idHash[:]) != 1 {
+		return authenticatorData{}, fmt.Errorf("%w: rp id hash does not match", ErrInvalidCredential)
+	}
+
+	if !parsed.userPresent() {
+		return authenticatorData{}, fmt.Errorf("%w: user is not present", ErrInvalidCredential)
+	}
+
+	// passkeys replace both the password and the second factor, so the user must be verified
+	if !parsed.userVerified() {
+		return authenticatorData{}, fmt.Errorf("%w: user is not verified", ErrInvalidCredential)
+	}
+
+	return parsed, nil
+}
+
+// ClientDataChallenge returns the challenge from the client data of a response, which identifies the ceremony.
+// The client data is not verified yet.
+func ClientDataChallenge(clientDataJSON string) ([]byte, error) {
+	clientData, err := parseClientData(clientDataJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	var challenge []byte
+	if challenge, err = encoding.DecodeString(clientData.Challenge); err != nil {
+		return nil, fmt.Errorf("%w: challenge is not base64url: %w", ErrInvalidCredential, err)
+	}
+
+	return challenge, nil
+}
+
+// parseClientData decodes the base64url-encoded client data JSON.
+func parseClientData(clientDataJSON string) (collectedClientData, error) {
+	data, err := encoding.DecodeString(clientDataJSON)
+	if err != nil {
+		return collectedClientData{}, fmt.Errorf("%w: client data is not base64url: %w", ErrInvalidCredential, err)
+	}
+
+	var clientData collectedClientData
+	if err = json.Unmarshal(data, &clientData); err != nil {
+		return collectedClientData{}, fmt.Errorf("%w: malformed client data: %w", ErrInvalidCredential, err)
+	}
+
+	return clientData, nil
+}
+
+// parseAttestationObject returns the authenticator data from the CBOR-encoded attestation object.
+func parseAttestationObject(data []byte) ([]byte, error) {
+	value, n, err := decodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if n != len(data) {
+		return nil, fmt.Errorf("%w: trailing data after attestation object", errMalformedCBOR)
+	}
+
+	object, ok := value.(map[any]any)
+	if !ok {
+		return nil, fmt.Errorf("%w: attestation object is not a map", errMalformedCBOR)
+	}
+
+	var authData []byte
+	if authData, ok = object["authData"].([]byte); !ok {
+		return nil, fmt.Errorf("%w: authData is missing", errMalformedCBOR)
+	}
+
+	return authData, nil
+}
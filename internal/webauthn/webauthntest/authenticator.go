@@ -0,0 +1,214 @@
+// Package webauthntest provides a software WebAuthn authenticator, so that passkey ceremonies
+// can be run in tests without a browser or a security key.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+)
+
+// Authenticator flags, see webauthn.parseAuthenticatorData.
+const (
+	flagUserPresent            = 1 << 0
+	flagUserVerified           = 1 << 2
+	flagAttestedCredentialData = 1 << 6
+)
+
+const credentialIDSize = 16
+
+var encoding = base64.RawURLEncoding
+
+// NewAuthenticator creates a new *Authenticator acting as a browser on the given origin.
+func NewAuthenticator(origin string) *Authenticator {
+	return &Authenticator{origin: origin}
+}
+
+type (
+	// Authenticator is a software authenticator with discoverable ES256 credentials and user verification.
+	// It is not safe for concurrent use.
+	Authenticator struct {
+		origin      string
+		credentials []*credential
+	}
+
+	// credential is a key pair created by the authenticator.
+	credential struct {
+		id         []byte
+		rpID       string
+		userHandle []byte
+		key        *ecdsa.PrivateKey
+		signCount  uint32
+	}
+)
+
+// Register creates a new credential for the options returned by the relying party, like navigator.credentials.create().
+func (a *Authenticator) Register(options webauthn.CredentialCreationOptions) (webauthn.RegistrationCredential, error) {
+	if !slices.ContainsFunc(options.PubKeyCredParams, func(p webauthn.CredentialParameters) bool {
+		return p.Alg == webauthn.AlgES256
+	}) {
+		return webauthn.RegistrationCredential{}, errors.New("ES256 is not offered")
+	}
+
+	for _, excluded := range options.ExcludeCredentials {
+		if a.find(options.RP.ID, excluded.ID) != nil {
+			return webauthn.RegistrationCredential{}, errors.New("credential is already registered")
+		}
+	}
+
+	userHandle, err := encoding.DecodeString(options.User.ID)
+	if err != nil {
+		return webauthn.RegistrationCredential{}, fmt.Errorf("invalid user id: %w", err)
+	}
+
+	cred := &credential{
+		id:         make([]byte, credentialIDSize),
+		rpID:       options.RP.ID,
+		userHandle: userHandle,
+	}
+
+	if _, err = rand.Read(cred.id); err != nil {
+		return webauthn.RegistrationCredential{}, err
+	}
+
+	if cred.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader); err != nil {
+		return webauthn.RegistrationCredential{}, err
+	}
+
+	authData := authenticatorData(cred.rpID, flagUserPresent|flagUserVerified|flagAttestedCredentialData, cred.signCount)
+	authData = append(authData, make([]byte, 16)...) // AAGUID
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(cred.id)))
+	authData = append(authData, cred.id...)
+	authData = append(authData, cred.publicKey()...)
+
+	attestationObject := encodeCBOR([]pair{
+		{key: "fmt", value: "none"},
+		{key: "attStmt", value: []pair{}},
+		{key: "authData", value: authData},
+	})
+
+	var clientData []byte
+	if clientData, err = a.clientData("webauthn.create", options.Challenge); err != nil {
+		return webauthn.RegistrationCredential{}, err
+	}
+
+	a.credentials = append(a.credentials, cred)
+
+	return webauthn.RegistrationCredential{
+		ID:    encoding.EncodeToString(cred.id),
+		RawID: encoding.EncodeToString(cred.id),
+		Type:  "public-key",
+		Response: webauthn.AuthenticatorAttestationResponse{
+			ClientDataJSON:    encoding.EncodeToString(clientData),
+			AttestationObject: encoding.EncodeToString(attestationObject),
+			Transports:        []string{"internal"},
+		},
+	}, nil
+}
+
+// Login signs the challenge of the options returned by the relying party with the most recently created
+// credential of the relying party, like navigator.credentials.get() with a discoverable credential.
+func (a *Authenticator) Login(options webauthn.CredentialRequestOptions) (webauthn.AuthenticationCredential, error) {
+	var cred *credential
+	for i := len(a.credentials) - 1; i >= 0 && cred == nil; i-- {
+		if a.credentials[i].rpID == options.RPID {
+			cred = a.credentials[i]
+		}
+	}
+
+	if cred == nil {
+		return webauthn.AuthenticationCredential{}, errors.New("no credential for the relying party")
+	}
+
+	clientData, err := a.clientData("webauthn.get", options.Challenge)
+	if err != nil {
+		return webauthn.AuthenticationCredential{}, err
+	}
+
+	cred.signCount++
+	authData := authenticatorData(cred.rpID, flagUserPresent|flagUserVerified, cred.signCount)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(slices.Clone(authData), clientDataHash[:]...))
+
+	var signature []byte
+	if signature, err = ecdsa.SignASN1(rand.Reader, cred.key, digest[:]); err != nil {
+		return webauthn.AuthenticationCredential{}, err
+	}
+
+	return webauthn.AuthenticationCredential{
+		ID:    encoding.EncodeToString(cred.id),
+		RawID: encoding.EncodeToString(cred.id),
+		Type:  "public-key",
+		Response: webauthn.AuthenticatorAssertionResponse{
+			ClientDataJSON:    encoding.EncodeToString(clientData),
+			AuthenticatorData: encoding.EncodeToString(authData),
+			Signature:         encoding.EncodeToString(signature),
+			UserHandle:        encoding.EncodeToString(cred.userHandle),
+		},
+	}, nil
+}
+
+// Clone returns a copy of the authenticator with the same credentials and sign counters,
+// which lets tests simulate a cloned authenticator.
+func (a *Authenticator) Clone() *Authenticator {
+	clone := &Authenticator{origin: a.origin}
+	for _, cred := range a.credentials {
+		copied := *cred
+		clone.credentials = append(clone.credentials, &copied)
+	}
+
+	return clone
+}
+
+// find returns the credential of the relying party with the given base64url-encoded ID.
+func (a *Authenticator) find(rpID string, id string) *credential {
+	for _, cred := range a.credentials {
+		if cred.rpID == rpID && encoding.EncodeToString(cred.id) == id {
+			return cred
+		}
+	}
+
+	return nil
+}
+
+// clientData returns the client data JSON the browser would pass to the authenticator.
+func (a *Authenticator) clientData(ceremonyType string, challenge string) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"type":        ceremonyType,
+		"challenge":   challenge,
+		"origin":      a.origin,
+		"crossOrigin": false,
+	})
+}
+
+// publicKey returns the COSE_Key of the credential.
+func (c *credential) publicKey() []byte {
+	point, _ := c.key.PublicKey.ECDH()
+	uncompressed := point.Bytes()
+
+	return encodeCBOR([]pair{
+		{key: int64(1), value: int64(2)},            // kty: EC2
+		{key: int64(3), value: webauthn.AlgES256},   // alg
+		{key: int64(-1), value: int64(1)},           // crv: P-256
+		{key: int64(-2), value: uncompressed[1:33]}, // x
+		{key: int64(-3), value: uncompressed[33:]},  // y
+	})
+}
+
+// authenticatorData returns the authenticator data without attested credential data.
+func authenticatorData(rpID string, flags byte, signCount uint32) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, signCount)
+}
@@ -0,0 +1,63 @@
+package webauthntest
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// CBOR major types (RFC 8949, section 3.1).
+const (
+	majorUnsigned = 0
+	majorNegative = 1
+	majorBytes    = 2
+	majorText     = 3
+	majorMap      = 5
+)
+
+// pair is a map entry. Maps are encoded from slices of pairs, so that the key order is deterministic.
+type pair struct {
+	key   any
+	value any
+}
+
+// encodeCBOR encodes int64, []byte, string and []pair values, which is all authenticators need.
+func encodeCBOR(value any) []byte {
+	switch v := value.(type) {
+	case int64:
+		if v < 0 {
+			return encodeHead(majorNegative, uint64(-1-v))
+		}
+
+		return encodeHead(majorUnsigned, uint64(v))
+	case []byte:
+		return append(encodeHead(majorBytes, uint64(len(v))), v...)
+	case string:
+		return append(encodeHead(majorText, uint64(len(v))), v...)
+	case []pair:
+		out := encodeHead(majorMap, uint64(len(v)))
+		for _, p := range v {
+			out = append(out, encodeCBOR(p.key)...)
+			out = append(out, encodeCBOR(p.value)...)
+		}
+
+		return out
+	default:
+		panic(fmt.Sprintf("webauthntest: unsupported cbor type %T", value))
+	}
+}
+
+// encodeHead encodes the initial byte and the argument of a data item.
+func encodeHead(major byte, argument uint64) []byte {
+	switch {
+	case argument < 24:
+		return []byte{major<<5 | byte(argument)}
+	case argument <= 0xff:
+		return []byte{major<<5 | 24, byte(argument)}
+	case argument <= 0xffff:
+		return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(argument))
+	case argument <= 0xffffffff:
+		return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(argument))
+	default:
+		return binary.BigEndian.AppendUint64([]byte{major<<5 | 27}, argument)
+	}
+}
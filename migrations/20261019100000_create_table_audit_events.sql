@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.audit_events
+(
+    id         BIGSERIAL PRIMARY KEY NOT NULL,
+    type       VARCHAR               NOT NULL,
+    user_id    UUID,
+    actor_id   UUID,
+    details    JSONB                 NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ           NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS audit_events_user_id_idx ON public.audit_events (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.audit_events;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.user_totp
+(
+    user_id          UUID PRIMARY KEY NOT NULL REFERENCES public.users (id) ON DELETE CASCADE,
+    secret_encrypted VARCHAR          NOT NULL,
+    confirmed_at     TIMESTAMPTZ,
+    last_used_step   BIGINT           NOT NULL DEFAULT 0,
+    created_at       TIMESTAMPTZ      NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.user_totp;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.recovery_codes
+(
+    id         BIGSERIAL PRIMARY KEY NOT NULL,
+    user_id    UUID                  NOT NULL REFERENCES public.users (id) ON DELETE CASCADE,
+    code_hash  VARCHAR               NOT NULL,
+    used_at    TIMESTAMPTZ,
+    created_at TIMESTAMPTZ           NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS recovery_codes_user_id_code_hash_idx ON public.recovery_codes (user_id, code_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.recovery_codes;
+-- +goose StatementEnd
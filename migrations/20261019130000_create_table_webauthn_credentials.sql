@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.webauthn_credentials
+(
+    id           BYTEA PRIMARY KEY NOT NULL,
+    user_id      UUID              NOT NULL REFERENCES public.users (id) ON DELETE CASCADE,
+    public_key   BYTEA             NOT NULL,
+    sign_count   BIGINT            NOT NULL DEFAULT 0,
+    transports   VARCHAR[]         NOT NULL DEFAULT '{}',
+    created_at   TIMESTAMPTZ       NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS webauthn_credentials_user_id_idx ON public.webauthn_credentials (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.webauthn_credentials;
+-- +goose StatementEnd
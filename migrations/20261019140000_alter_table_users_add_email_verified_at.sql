@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+-- accounts created before email verification existed are treated as verified, so they are not locked out
+UPDATE public.users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.users DROP COLUMN IF EXISTS email_verified_at;
+-- +goose StatementEnd
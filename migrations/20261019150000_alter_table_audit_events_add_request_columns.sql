@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.audit_events
+    ADD COLUMN IF NOT EXISTS ip         VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS user_agent VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS session_id VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS outcome    VARCHAR NOT NULL DEFAULT 'success',
+    ADD COLUMN IF NOT EXISTS reason     VARCHAR NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.audit_events
+    DROP COLUMN IF EXISTS ip,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS session_id,
+    DROP COLUMN IF EXISTS outcome,
+    DROP COLUMN IF EXISTS reason;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.audit_events
+    ADD COLUMN IF NOT EXISTS prev_hash VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS hash      VARCHAR NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.audit_events
+    DROP COLUMN IF EXISTS prev_hash,
+    DROP COLUMN IF EXISTS hash;
+-- +goose StatementEnd
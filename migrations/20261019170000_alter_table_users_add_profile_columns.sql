@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.users
+    ADD COLUMN IF NOT EXISTS display_name VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS locale       VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS timezone     VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS avatar_url   VARCHAR NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS metadata     JSONB   NOT NULL DEFAULT '{}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.users
+    DROP COLUMN IF EXISTS display_name,
+    DROP COLUMN IF EXISTS locale,
+    DROP COLUMN IF EXISTS timezone,
+    DROP COLUMN IF EXISTS avatar_url,
+    DROP COLUMN IF EXISTS metadata;
+-- +goose StatementEnd
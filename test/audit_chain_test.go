@@ -0,0 +1,61 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/riabininkf/http-auth-example/internal/audit"
+	"github.com/riabininkf/http-auth-example/internal/repository"
+)
+
+func TestAuditChain(t *testing.T) {
+	email, password := gofakeit.Email(), generatePassword()
+	registerUserV1(t, email, password)
+
+	statusCode, _ := sendLoginV1Request(t, bytes.NewReader(
+		[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, generatePassword())),
+	))
+	assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+	loginUserV1(t, email, password)
+
+	var events *repository.AuditEvents
+	if err := ctn.Fill(repository.DefAuditEventsName, &events); err != nil {
+		t.Fatal(err)
+	}
+
+	var signer *audit.Signer
+	if err := ctn.Fill(audit.DefSignerName, &signer); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("events are chained", func(t *testing.T) {
+		chain := audit.NewChain()
+		if !assert.NoError(t, events.Each(t.Context(), chain.Append)) {
+			t.FailNow()
+		}
+
+		assert.GreaterOrEqual(t, chain.Events(), 2)
+		assert.NotEmpty(t, chain.Head())
+	})
+
+	t.Run("exported bundle is verified with the public key", func(t *testing.T) {
+		var buf bytes.Buffer
+		bundle := audit.NewBundleWriter(&buf, signer)
+		if !assert.NoError(t, events.Each(t.Context(), bundle.Write)) || !assert.NoError(t, bundle.Close()) {
+			t.FailNow()
+		}
+
+		chain, err := audit.VerifyBundle(&buf, signer.PublicKey())
+		if !assert.NoError(t, err) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, bundle.Chain().Head(), chain.Head())
+	})
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestChangeEmailV1(t *testing.T) {
+	t.Run("invalid password", func(t *testing.T) {
+		email, password := gofakeit.Email(), generatePassword()
+		registerUserV1(t, email, password)
+		accessToken := loginUserV1(t, email, password)
+
+		statusCode, resp := sendChangeEmailV1Request(t, accessToken, generatePassword(), gofakeit.Email())
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "invalid password", resp.Get("error.message").String())
+	})
+
+	t.Run("email is busy", func(t *testing.T) {
+		email, password, otherEmail := gofakeit.Email(), generatePassword(), gofakeit.Email()
+		registerUserV1(t, email, password)
+		registerUserV1(t, otherEmail, generatePassword())
+		accessToken := loginUserV1(t, email, password)
+
+		statusCode, resp := sendChangeEmailV1Request(t, accessToken, password, otherEmail)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "email is already in use", resp.Get("error.message").String())
+	})
+
+	t.Run("address taken before the confirmation", func(t *testing.T) {
+		email, password, newEmail := gofakeit.Email(), generatePassword(), gofakeit.Email()
+		registerUserV1(t, email, password)
+		accessToken := loginUserV1(t, email, password)
+
+		statusCode, _ := sendChangeEmailV1Request(t, accessToken, password, newEmail)
+		if !assert.Equal(t, http.StatusAccepted, statusCode) {
+			t.FailNow()
+		}
+
+		token := readMailToken(t, newEmail)
+		registerUserV1(t, newEmail, generatePassword())
+
+		var resp gjson.Result
+		statusCode, resp = sendConfirmEmailChangeV1Request(t, token)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "email is already in use", resp.Get("error.message").String())
+		assert.NotEmpty(t, loginUserV1(t, email, password))
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		email, password, newEmail := gofakeit.Email(), generatePassword(), gofakeit.Email()
+		registered := registerUserV1(t, email, password)
+		accessToken := loginUserV1(t, email, password)
+
+		statusCode, _ := sendChangeEmailV1Request(t, accessToken, password, newEmail)
+		if !assert.Equal(t, http.StatusAccepted, statusCode) {
+			t.FailNow()
+		}
+
+		// the email stays the same until the new address is confirmed
+		assert.NotEmpty(t, loginUserV1(t, email, password))
+
+		token := readMailToken(t, newEmail)
+
+		var resp gjson.Result
+		statusCode, resp = sendConfirmEmailChangeV1Request(t, token)
+		if !assert.Equal(t, http.StatusOK, statusCode) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, registered.UserID, resp.Get("user_id").String())
+		assert.Equal(t, newEmail, resp.Get("email").String())
+
+		// tokens are single-use
+		statusCode, _ = sendConfirmEmailChangeV1Request(t, token)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+
+		statusCode, resp = sendGetProfileV1Request(t, accessToken)
+		if assert.Equal(t, http.StatusOK, statusCode) {
+			assert.Equal(t, newEmail, resp.Get("email").String())
+			assert.True(t, resp.Get("email_verified").Bool())
+		}
+
+		statusCode, _ = sendLoginV1Request(t, bytes.NewReader(
+			[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, password)),
+		))
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+		assert.NotEmpty(t, loginUserV1(t, newEmail, password))
+
+		// the address cannot be changed again while the change can be undone
+		statusCode, _ = sendChangeEmailV1Request(t, accessToken, password, gofakeit.Email())
+		assert.Equal(t, http.StatusConflict, statusCode)
+
+		// the previous address can undo the change
+		undoToken := readMailToken(t, email)
+
+		statusCode, resp = sendUndoEmailChangeV1Request(t, undoToken)
+		if !assert.Equal(t, http.StatusOK, statusCode) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, registered.UserID, resp.Get("user_id").String())
+		assert.NotEmpty(t, loginUserV1(t, email, password))
+
+		// sessions started before the undo are revoked
+		statusCode, _ = sendRefreshV1Request(t, bytes.NewReader(
+			[]byte(fmt.Sprintf(`{"refresh_token":"%s"}`, registered.RefreshToken)),
+		))
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+		statusCode, _ = sendUndoEmailChangeV1Request(t, undoToken)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+	})
+}
+
+func sendChangeEmailV1Request(t *testing.T, accessToken string, password string, newEmail string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/user/email",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"password":"%s","new_email":"%s"}`, password, newEmail))), accessToken)
+}
+
+func sendConfirmEmailChangeV1Request(t *testing.T, token string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/auth/email/change/confirm",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"token":"%s"}`, token))), "")
+}
+
+func sendUndoEmailChangeV1Request(t *testing.T, token string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/auth/email/change/undo",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"token":"%s"}`, token))), "")
+}
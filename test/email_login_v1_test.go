@@ -0,0 +1,139 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestEmailLoginV1(t *testing.T) {
+	t.Run("unknown email", func(t *testing.T) {
+		email := gofakeit.Email()
+
+		statusCode, resp := sendStartEmailLoginV1Request(t, email)
+
+		assert.Equal(t, http.StatusAccepted, statusCode)
+		assert.NotEmpty(t, resp.Get("login_token").String())
+		assert.Empty(t, readMails(t, email))
+	})
+
+	t.Run("too many requests", func(t *testing.T) {
+		email := gofakeit.Email()
+		registerUserV1(t, email, generatePassword())
+
+		statusCode, _ := sendStartEmailLoginV1Request(t, email)
+		if !assert.Equal(t, http.StatusAccepted, statusCode) {
+			t.FailNow()
+		}
+
+		statusCode, resp := sendStartEmailLoginV1Request(t, email)
+
+		assert.Equal(t, http.StatusTooManyRequests, statusCode)
+		assert.Equal(t, "too many requests", resp.Get("error.message").String())
+	})
+
+	t.Run("login with code", func(t *testing.T) {
+		email := gofakeit.Email()
+		registered := registerUserV1(t, email, generatePassword())
+
+		loginToken := startEmailLogin(t, email)
+		code := readMailCode(t, email)
+
+		statusCode, resp := sendFinishEmailLoginV1Request(t, loginToken, code)
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, registered.UserID, resp.Get("user_id").String())
+		assert.NotEmpty(t, resp.Get("access_token").String())
+		assert.NotEmpty(t, resp.Get("refresh_token").String())
+
+		// codes are single-use
+		statusCode, _ = sendFinishEmailLoginV1Request(t, loginToken, code)
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+	})
+
+	t.Run("login with link", func(t *testing.T) {
+		email := gofakeit.Email()
+		registered := registerUserV1(t, email, generatePassword())
+
+		loginToken := startEmailLogin(t, email)
+
+		statusCode, resp := sendFinishEmailLoginV1Request(t, loginToken, readMailToken(t, email))
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, registered.UserID, resp.Get("user_id").String())
+	})
+
+	t.Run("code of another login", func(t *testing.T) {
+		email := gofakeit.Email()
+		registerUserV1(t, email, generatePassword())
+
+		startEmailLogin(t, email)
+		code := readMailCode(t, email)
+
+		statusCode, resp := sendFinishEmailLoginV1Request(t, startEmailLogin(t, gofakeit.Email()), code)
+
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+		assert.Equal(t, "invalid or expired login token", resp.Get("error.message").String())
+	})
+
+	t.Run("invalid code", func(t *testing.T) {
+		email := gofakeit.Email()
+		registerUserV1(t, email, generatePassword())
+
+		loginToken := startEmailLogin(t, email)
+		code := readMailCode(t, email)
+
+		// changing the first digit makes the code invalid
+		invalidCode := string('0'+(code[0]-'0'+1)%10) + code[1:]
+
+		statusCode, resp := sendFinishEmailLoginV1Request(t, loginToken, invalidCode)
+
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+		assert.Equal(t, "invalid code", resp.Get("error.message").String())
+
+		// the login survives a typo
+		statusCode, _ = sendFinishEmailLoginV1Request(t, loginToken, code)
+		assert.Equal(t, http.StatusOK, statusCode)
+	})
+}
+
+func sendStartEmailLoginV1Request(t *testing.T, email string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/auth/login/email",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"email":"%s"}`, email))), "")
+}
+
+func sendFinishEmailLoginV1Request(t *testing.T, loginToken string, code string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/auth/login/email/verify",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"login_token":"%s","code":"%s"}`, loginToken, code))), "")
+}
+
+// startEmailLogin starts an email login and returns the login token.
+func startEmailLogin(t *testing.T, email string) string {
+	statusCode, resp := sendStartEmailLoginV1Request(t, email)
+	if statusCode != http.StatusAccepted {
+		t.Fatalf("unexpected status code %d", statusCode)
+	}
+
+	return resp.Get("login_token").String()
+}
+
+// readMailCode returns the 6-digit code from the last message sent to the address.
+func readMailCode(t *testing.T, to string) string {
+	messages := readMails(t, to)
+	if len(messages) == 0 {
+		t.Fatalf("no mail sent to %s", to)
+	}
+
+	code := regexp.MustCompile(`\b\d{6}\b`).FindString(messages[len(messages)-1].Body)
+	if code == "" {
+		t.Fatalf("no code in the last mail to %s", to)
+	}
+
+	return code
+}
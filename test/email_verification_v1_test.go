@@ -0,0 +1,126 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/riabininkf/http-auth-example/internal/mail"
+)
+
+// mailDir is the directory the service writes sent messages into, mounted by docker-compose.yaml.
+const mailDir = "mail"
+
+func TestVerifyEmailV1(t *testing.T) {
+	email := gofakeit.Email()
+	registered := registerUserV1(t, email, generatePassword())
+
+	token := readMailToken(t, email)
+
+	t.Run("invalid token", func(t *testing.T) {
+		statusCode, resp := sendVerifyEmailV1Request(t, "unknown")
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "invalid or expired token", resp.Get("error.message").String())
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		statusCode, resp := sendVerifyEmailV1Request(t, token)
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, registered.UserID, resp.Get("user_id").String())
+
+		// tokens are single-use
+		statusCode, _ = sendVerifyEmailV1Request(t, token)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+	})
+
+	t.Run("resend to verified email", func(t *testing.T) {
+		messages := len(readMails(t, email))
+
+		statusCode, _ := sendResendEmailVerificationV1Request(t, email)
+
+		assert.Equal(t, http.StatusAccepted, statusCode)
+		assert.Len(t, readMails(t, email), messages)
+	})
+}
+
+func TestResendEmailVerificationV1(t *testing.T) {
+	t.Run("unknown email", func(t *testing.T) {
+		email := gofakeit.Email()
+
+		statusCode, _ := sendResendEmailVerificationV1Request(t, email)
+
+		assert.Equal(t, http.StatusAccepted, statusCode)
+		assert.Empty(t, readMails(t, email))
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		email := gofakeit.Email()
+		registerUserV1(t, email, generatePassword())
+
+		first := readMailToken(t, email)
+
+		statusCode, _ := sendResendEmailVerificationV1Request(t, email)
+		if !assert.Equal(t, http.StatusAccepted, statusCode) {
+			t.FailNow()
+		}
+
+		second := readMailToken(t, email)
+		assert.NotEqual(t, first, second)
+
+		statusCode, _ = sendVerifyEmailV1Request(t, second)
+		assert.Equal(t, http.StatusOK, statusCode)
+	})
+}
+
+func sendVerifyEmailV1Request(t *testing.T, token string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/auth/email/verify",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"token":"%s"}`, token))), "")
+}
+
+func sendResendEmailVerificationV1Request(t *testing.T, email string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/auth/email/verify/resend",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"email":"%s"}`, email))), "")
+}
+
+// readMails returns the messages sent to the address, oldest first.
+func readMails(t *testing.T, to string) []mail.Message {
+	messages, err := mail.ReadFiles(mailDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var received []mail.Message
+	for _, msg := range messages {
+		if msg.To == to {
+			received = append(received, msg)
+		}
+	}
+
+	return received
+}
+
+// readMailToken returns the token from the link in the last message sent to the address.
+func readMailToken(t *testing.T, to string) string {
+	messages := readMails(t, to)
+	if len(messages) == 0 {
+		t.Fatalf("no mail sent to %s", to)
+	}
+
+	for _, field := range strings.Fields(messages[len(messages)-1].Body) {
+		if link, err := url.Parse(field); err == nil && link.Query().Has("token") {
+			return link.Query().Get("token")
+		}
+	}
+
+	t.Fatalf("no link with a token in the last mail to %s", to)
+	return ""
+}
@@ -0,0 +1,210 @@
+package test
+
+import (
+	"bytes"
+	"encoding/base32"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/riabininkf/http-auth-example/internal/totp"
+)
+
+func TestLoginMFAV1(t *testing.T) {
+	email, password := gofakeit.Email(), generatePassword()
+	accessToken := registerUserV1(t, email, password).AccessToken
+
+	statusCode, resp := sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/user/mfa/totp",
+		bytes.NewReader([]byte(`{}`)), accessToken)
+	if !assert.Equal(t, http.StatusOK, statusCode) {
+		t.FailNow()
+	}
+
+	assert.Contains(t, resp.Get("otpauth_uri").String(), "otpauth://totp/")
+
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(resp.Get("secret").String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	step := totp.Step(time.Now())
+
+	t.Run("invalid confirmation code", func(t *testing.T) {
+		statusCode, resp := sendConfirmTOTPV1Request(t, accessToken, "0000000")
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "invalid code", resp.Get("error.message").String())
+	})
+
+	statusCode, resp = sendConfirmTOTPV1Request(t, accessToken, totp.Code(secret, step))
+	if !assert.Equal(t, http.StatusOK, statusCode) {
+		t.FailNow()
+	}
+
+	recoveryCodes := resp.Get("recovery_codes").Array()
+	if !assert.Len(t, recoveryCodes, 10) {
+		t.FailNow()
+	}
+
+	t.Run("already enabled", func(t *testing.T) {
+		statusCode, resp := sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/user/mfa/totp",
+			bytes.NewReader([]byte(`{}`)), accessToken)
+
+		assert.Equal(t, http.StatusConflict, statusCode)
+		assert.Equal(t, "totp is already enabled", resp.Get("error.message").String())
+	})
+
+	t.Run("invalid mfa token", func(t *testing.T) {
+		statusCode, resp := sendLoginMFAV1Request(t, "unknown", totp.Code(secret, step+1))
+
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+		assert.Equal(t, "invalid or expired mfa token", resp.Get("error.message").String())
+	})
+
+	t.Run("code used for confirmation is rejected", func(t *testing.T) {
+		statusCode, resp := sendLoginMFAV1Request(t, startMFALogin(t, email, password), totp.Code(secret, step))
+
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+		assert.Equal(t, "invalid code", resp.Get("error.message").String())
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		mfaToken := startMFALogin(t, email, password)
+
+		statusCode, resp := sendLoginMFAV1Request(t, mfaToken, totp.Code(secret, step+1))
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.True(t, resp.Get("access_token").Exists(), "access_token is missing")
+		assert.True(t, resp.Get("refresh_token").Exists(), "refresh_token is missing")
+
+		t.Run("mfa token is single-use", func(t *testing.T) {
+			statusCode, resp := sendLoginMFAV1Request(t, mfaToken, totp.Code(secret, step+1))
+
+			assert.Equal(t, http.StatusUnauthorized, statusCode)
+			assert.Equal(t, "invalid or expired mfa token", resp.Get("error.message").String())
+		})
+
+		t.Run("code is single-use", func(t *testing.T) {
+			statusCode, resp := sendLoginMFAV1Request(t, startMFALogin(t, email, password), totp.Code(secret, step+1))
+
+			assert.Equal(t, http.StatusUnauthorized, statusCode)
+			assert.Equal(t, "invalid code", resp.Get("error.message").String())
+		})
+	})
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	email, password := gofakeit.Email(), generatePassword()
+	accessToken := registerUserV1(t, email, password).AccessToken
+
+	t.Run("password and code are missing", func(t *testing.T) {
+		statusCode, resp := sendRegenerateRecoveryCodesV1Request(t, accessToken, "")
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "password or code is required", resp.Get("error.message").String())
+	})
+
+	t.Run("mfa is not enabled", func(t *testing.T) {
+		statusCode, resp := sendRegenerateRecoveryCodesV1Request(t, accessToken, password)
+
+		assert.Equal(t, http.StatusConflict, statusCode)
+		assert.Equal(t, "two-factor authentication is not enabled", resp.Get("error.message").String())
+	})
+
+	_, resp := sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/user/mfa/totp",
+		bytes.NewReader([]byte(`{}`)), accessToken)
+
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(resp.Get("secret").String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statusCode, resp := sendConfirmTOTPV1Request(t, accessToken, totp.Code(secret, totp.Step(time.Now())))
+	if !assert.Equal(t, http.StatusOK, statusCode) {
+		t.FailNow()
+	}
+
+	recoveryCode := resp.Get("recovery_codes.0").String()
+
+	t.Run("recovery code replaces the second factor once", func(t *testing.T) {
+		statusCode, resp := sendRecoveryCodeLoginRequest(t, startMFALogin(t, email, password), recoveryCode)
+
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.True(t, resp.Get("access_token").Exists(), "access_token is missing")
+
+		statusCode, resp = sendRecoveryCodeLoginRequest(t, startMFALogin(t, email, password), recoveryCode)
+
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+		assert.Equal(t, "invalid code", resp.Get("error.message").String())
+	})
+
+	t.Run("regeneration requires the password", func(t *testing.T) {
+		statusCode, resp := sendRegenerateRecoveryCodesV1Request(t, accessToken, generatePassword())
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "invalid password", resp.Get("error.message").String())
+	})
+
+	t.Run("regeneration invalidates old codes", func(t *testing.T) {
+		oldCode := resp.Get("recovery_codes.1").String()
+
+		statusCode, regenerated := sendRegenerateRecoveryCodesV1Request(t, accessToken, password)
+		if !assert.Equal(t, http.StatusOK, statusCode) {
+			t.FailNow()
+		}
+
+		assert.Len(t, regenerated.Get("recovery_codes").Array(), 10)
+
+		statusCode, resp := sendRecoveryCodeLoginRequest(t, startMFALogin(t, email, password), oldCode)
+
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+		assert.Equal(t, "invalid code", resp.Get("error.message").String())
+
+		statusCode, _ = sendRecoveryCodeLoginRequest(
+			t,
+			startMFALogin(t, email, password),
+			regenerated.Get("recovery_codes.0").String(),
+		)
+
+		assert.Equal(t, http.StatusOK, statusCode)
+	})
+}
+
+func sendRegenerateRecoveryCodesV1Request(t *testing.T, accessToken string, password string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/user/mfa/recovery-codes",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"password":"%s"}`, password))), accessToken)
+}
+
+func sendRecoveryCodeLoginRequest(t *testing.T, mfaToken string, recoveryCode string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/auth/login/mfa",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"mfa_token":"%s","recovery_code":"%s"}`, mfaToken, recoveryCode))), "")
+}
+
+func sendConfirmTOTPV1Request(t *testing.T, accessToken string, code string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/user/mfa/totp/confirm",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"code":"%s"}`, code))), accessToken)
+}
+
+func sendLoginMFAV1Request(t *testing.T, mfaToken string, code string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/auth/login/mfa",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"mfa_token":"%s","code":"%s"}`, mfaToken, code))), "")
+}
+
+// startMFALogin logs in with the password of a user with TOTP enabled and returns the MFA token.
+func startMFALogin(t *testing.T, email string, password string) string {
+	statusCode, resp := sendLoginV1Request(t, bytes.NewReader(
+		[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, password)),
+	))
+
+	assert.Equal(t, http.StatusAccepted, statusCode)
+	assert.False(t, resp.Get("access_token").Exists(), "access_token must not be issued before the second factor")
+	assert.Equal(t, "totp", resp.Get("mfa_methods.0").String())
+	assert.Equal(t, "recovery_code", resp.Get("mfa_methods.1").String())
+
+	return resp.Get("mfa_token").String()
+}
@@ -5,11 +5,17 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/brianvoe/gofakeit/v7"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/tidwall/gjson"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/riabininkf/http-auth-example/internal/domain"
+	"github.com/riabininkf/http-auth-example/internal/repository"
 )
 
 func TestLoginV1(t *testing.T) {
@@ -31,7 +37,7 @@ func TestLoginV1(t *testing.T) {
 
 	t.Run("invalid email", func(t *testing.T) {
 		statusCode, resp := sendLoginV1Request(t, bytes.NewReader(
-			[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, gofakeit.Email(), gofakeit.Name())),
+			[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, gofakeit.Email(), generatePassword())),
 		))
 
 		assert.Equal(t, http.StatusUnauthorized, statusCode)
@@ -39,19 +45,47 @@ func TestLoginV1(t *testing.T) {
 	})
 
 	t.Run("invalid password", func(t *testing.T) {
-		email, password := gofakeit.Email(), gofakeit.Name()
+		email, password := gofakeit.Email(), generatePassword()
 		registerUserV1(t, email, password)
 
 		statusCode, resp := sendLoginV1Request(t, bytes.NewReader(
-			[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, gofakeit.Name())),
+			[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, generatePassword())),
 		))
 
 		assert.Equal(t, http.StatusUnauthorized, statusCode)
 		assert.Equal(t, "invalid email or password", resp.Get("error.message").String())
 	})
 
+	t.Run("account is locked after repeated failed logins", func(t *testing.T) {
+		email, password := gofakeit.Email(), generatePassword()
+		registerUserV1(t, email, password)
+
+		wrongPassword := bytes.NewReader([]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, generatePassword())))
+		for range 4 {
+			_, _ = wrongPassword.Seek(0, io.SeekStart)
+			statusCode, _ := sendLoginV1Request(t, wrongPassword)
+			assert.Equal(t, http.StatusUnauthorized, statusCode)
+		}
+
+		// a locked account is answered like a wrong password, so that it does not reveal the email is registered
+		_, _ = wrongPassword.Seek(0, io.SeekStart)
+		statusCode, resp := sendLoginV1Request(t, wrongPassword)
+		assert.Equal(t, http.StatusUnauthorized, statusCode, "the fifth failure locks the account")
+		assert.Equal(t, "invalid email or password", resp.Get("error.message").String())
+
+		if messages := readMails(t, email); assert.NotEmpty(t, messages, "the owner is notified of the lockout") {
+			assert.Equal(t, "Your account has been locked", messages[len(messages)-1].Subject)
+		}
+
+		statusCode, resp = sendLoginV1Request(t, bytes.NewReader(
+			[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, password)),
+		))
+		assert.Equal(t, http.StatusUnauthorized, statusCode, "the right password does not work while locked")
+		assert.Equal(t, "invalid email or password", resp.Get("error.message").String())
+	})
+
 	t.Run("positive case", func(t *testing.T) {
-		email, password := gofakeit.Email(), gofakeit.Name()
+		email, password := gofakeit.Email(), generatePassword()
 
 		registrationResp := registerUserV1(t, email, password)
 
@@ -65,6 +99,49 @@ func TestLoginV1(t *testing.T) {
 		assert.True(t, resp.Get("access_token").Exists(), "access_token is missing")
 		assert.True(t, resp.Get("refresh_token").Exists(), "refresh_token is missing")
 	})
+
+	t.Run("outdated hashes are rehashed", func(t *testing.T) {
+		var users *repository.Users
+		if err := ctn.Fill(repository.DefUsersName, &users); err != nil {
+			t.Fatal(err)
+		}
+
+		bcryptHash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for name, hashedPassword := range map[string]string{
+			"bcrypt":    string(bcryptHash),
+			"pbkdf2":    "$pbkdf2-sha256$29000$c2FsdHNhbHRzYWx0c2FsdA$7xwbY5rCP.qJhnvJ80W3FI7hSRg8wNnl3S9rczjVuCk",
+			"scrypt":    "$scrypt$ln=14,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$GM/8plVTNY2Jr5.H.TMEUW0SMD0/pCcA0XgAgW7i7jw",
+			"sha-crypt": "$6$saltstring$adDbXsJjcDlq2662QPgd.tkSOVmnG9Tt3oXl4HR60SusC3AGjirnDenVZp3DGwLwqy6iYKCzannhaX9DR72nN1",
+		} {
+			t.Run(name, func(t *testing.T) {
+				email := gofakeit.Email()
+				if err := users.Save(t.Context(), domain.NewUser(uuid.NewString(), email, hashedPassword)); err != nil {
+					t.Fatal(err)
+				}
+
+				statusCode, _ := sendLoginV1Request(t, bytes.NewReader(
+					[]byte(fmt.Sprintf(`{"email":"%s","password":"password"}`, email)),
+				))
+				assert.Equal(t, http.StatusOK, statusCode)
+
+				user, err := users.GetByEmail(t.Context(), email)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				assert.True(t, strings.HasPrefix(user.HashedPassword(), "$argon2id$"), "password is not rehashed")
+
+				statusCode, _ = sendLoginV1Request(t, bytes.NewReader(
+					[]byte(fmt.Sprintf(`{"email":"%s","password":"password"}`, email)),
+				))
+				assert.Equal(t, http.StatusOK, statusCode)
+			})
+		}
+	})
 }
 
 func sendLoginV1Request(t *testing.T, body io.Reader) (int, gjson.Result) {
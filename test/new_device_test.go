@@ -0,0 +1,92 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestNewDevice(t *testing.T) {
+	email, password := gofakeit.Email(), generatePassword()
+	registerUserV1(t, email, password)
+
+	// the first login has no sessions to compare with
+	accessToken := loginUserV1(t, email, password)
+	assert.Empty(t, newDeviceNotices(t, email))
+
+	statusCode := sendLoginV1RequestWithUserAgent(t, email, password, "Go-http-client/1.1")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Empty(t, newDeviceNotices(t, email))
+
+	statusCode = sendLoginV1RequestWithUserAgent(t, email, password, "NewDevice/1.0")
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	notices := newDeviceNotices(t, email)
+	if !assert.Len(t, notices, 1) {
+		t.FailNow()
+	}
+
+	assert.Contains(t, notices[0], "Device: NewDevice/1.0\n")
+
+	// the device is known once it has had a session
+	statusCode = sendLoginV1RequestWithUserAgent(t, email, password, "NewDevice/1.0")
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Len(t, newDeviceNotices(t, email), 1)
+
+	var resp gjson.Result
+	statusCode, resp = sendSecurityEventsV1Request(t, accessToken)
+	if !assert.Equal(t, http.StatusOK, statusCode) {
+		t.FailNow()
+	}
+
+	var newDeviceEvents []gjson.Result
+	for _, event := range resp.Get("events").Array() {
+		if event.Get("type").String() == "new_device" {
+			newDeviceEvents = append(newDeviceEvents, event)
+		}
+	}
+
+	if assert.Len(t, newDeviceEvents, 1) {
+		assert.Equal(t, "NewDevice/1.0", newDeviceEvents[0].Get("user_agent").String())
+	}
+}
+
+// newDeviceNotices returns the bodies of the new device notices sent to the address.
+func newDeviceNotices(t *testing.T, to string) []string {
+	var bodies []string
+	for _, msg := range readMails(t, to) {
+		if msg.Subject == "New sign-in to your account" {
+			bodies = append(bodies, msg.Body)
+		}
+	}
+
+	return bodies
+}
+
+func sendLoginV1RequestWithUserAgent(t *testing.T, email string, password string, userAgent string) int {
+	req, err := http.NewRequest(http.MethodPost, "http://localhost:8080/v1/auth/login", bytes.NewReader(
+		[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, password)),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	return resp.StatusCode
+}
@@ -0,0 +1,191 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	oauthClientID    = "example-spa"
+	oauthRedirectURI = "http://localhost:3000/callback"
+)
+
+func TestOAuthAuthorizationCode(t *testing.T) {
+	codeVerifier := strings.Repeat(gofakeit.LetterN(16), 4)
+	sum := sha256.Sum256([]byte(codeVerifier))
+	codeChallenge := base64.RawURLEncoding.EncodeToString(sum[:])
+
+	authorizeParams := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {oauthClientID},
+		"redirect_uri":          {oauthRedirectURI},
+		"state":                 {"state"},
+		"code_challenge":        {codeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	t.Run("unregistered redirect uri", func(t *testing.T) {
+		params := url.Values{}
+		for key, values := range authorizeParams {
+			params[key] = values
+		}
+		params.Set("redirect_uri", "https://evil.example.com/callback")
+
+		statusCode, _ := sendAuthorizeRequest(t, http.MethodGet, params)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+	})
+
+	t.Run("login page", func(t *testing.T) {
+		statusCode, body := sendAuthorizeRequest(t, http.MethodGet, authorizeParams)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Contains(t, body, `name="password"`)
+	})
+
+	t.Run("invalid credentials", func(t *testing.T) {
+		params := url.Values{"email": {gofakeit.Email()}, "password": {generatePassword()}}
+		for key, values := range authorizeParams {
+			params[key] = values
+		}
+
+		statusCode, body := sendAuthorizeRequest(t, http.MethodPost, params)
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+		assert.Contains(t, body, "invalid email or password")
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		email, password := gofakeit.Email(), generatePassword()
+		registerUserV1(t, email, password)
+
+		params := url.Values{"email": {email}, "password": {password}}
+		for key, values := range authorizeParams {
+			params[key] = values
+		}
+
+		location := authorizeUser(t, params)
+		assert.Equal(t, "state", location.Query().Get("state"))
+
+		tokenParams := url.Values{
+			"grant_type":    {"authorization_code"},
+			"client_id":     {oauthClientID},
+			"redirect_uri":  {oauthRedirectURI},
+			"code":          {location.Query().Get("code")},
+			"code_verifier": {codeVerifier},
+		}
+
+		statusCode, resp := sendTokenV1Request(t, tokenParams)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, "Bearer", resp.Get("token_type").String())
+		assert.True(t, resp.Get("access_token").Exists(), "access_token is missing")
+		assert.True(t, resp.Get("refresh_token").Exists(), "refresh_token is missing")
+
+		// the access token belongs to the user who signed in
+		statusCode, _ = sendUpdatePasswordV1Request(t, resp.Get("access_token").String(), strings.NewReader(
+			`{"old_password":"`+password+`", "new_password":"`+generatePassword()+`"}`,
+		))
+		assert.Equal(t, http.StatusOK, statusCode)
+
+		// the same code cannot be redeemed twice
+		statusCode, resp = sendTokenV1Request(t, tokenParams)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "invalid_grant", resp.Get("error").String())
+	})
+}
+
+func sendAuthorizeRequest(t *testing.T, method string, params url.Values) (int, string) {
+	target, body := "http://localhost:8080/oauth/authorize?"+params.Encode(), io.Reader(nil)
+	if method == http.MethodPost {
+		target, body = "http://localhost:8080/oauth/authorize", strings.NewReader(params.Encode())
+	}
+
+	req, err := http.NewRequest(method, target, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp *http.Response
+	if resp, err = noRedirectClient().Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var respBytes []byte
+	if respBytes, err = io.ReadAll(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.StatusCode, string(respBytes)
+}
+
+func authorizeUser(t *testing.T, params url.Values) *url.URL {
+	req, err := http.NewRequest(http.MethodPost, "http://localhost:8080/oauth/authorize", strings.NewReader(params.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp *http.Response
+	if resp, err = noRedirectClient().Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	_ = resp.Body.Close()
+
+	if !assert.Equal(t, http.StatusFound, resp.StatusCode) {
+		t.FailNow()
+	}
+
+	var location *url.URL
+	if location, err = url.Parse(resp.Header.Get("Location")); err != nil {
+		t.Fatal(err)
+	}
+
+	return location
+}
+
+func sendTokenV1Request(t *testing.T, params url.Values) (int, gjson.Result) {
+	req, err := http.NewRequest(http.MethodPost, "http://localhost:8080/v1/oauth/token", strings.NewReader(params.Encode()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var resp *http.Response
+	if resp, err = http.DefaultClient.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var body []byte
+	if body, err = io.ReadAll(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.StatusCode, gjson.ParseBytes(body)
+}
+
+func noRedirectClient() *http.Client {
+	return &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
@@ -0,0 +1,154 @@
+package test
+
+import (
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+const (
+	oauthDeviceClientID = "example-cli"
+	grantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+)
+
+func TestOAuthDeviceCode(t *testing.T) {
+	t.Run("unknown client", func(t *testing.T) {
+		statusCode, resp := sendDeviceCodeV1Request(t, url.Values{"client_id": {gofakeit.Username()}})
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+		assert.Equal(t, "invalid_client", resp.Get("error").String())
+	})
+
+	t.Run("unknown user code", func(t *testing.T) {
+		email, password := gofakeit.Email(), generatePassword()
+		registerUserV1(t, email, password)
+
+		statusCode, body := sendDeviceVerificationRequest(t, url.Values{
+			"user_code": {"BBBB-BBBB"},
+			"email":     {email},
+			"password":  {password},
+			"action":    {"approve"},
+		})
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Contains(t, body, "invalid or expired code")
+	})
+
+	t.Run("denied", func(t *testing.T) {
+		email, password := gofakeit.Email(), generatePassword()
+		registerUserV1(t, email, password)
+
+		statusCode, resp := sendDeviceCodeV1Request(t, url.Values{"client_id": {oauthDeviceClientID}})
+		if !assert.Equal(t, http.StatusOK, statusCode) {
+			t.FailNow()
+		}
+
+		statusCode, _ = sendDeviceVerificationRequest(t, url.Values{
+			"user_code": {resp.Get("user_code").String()},
+			"email":     {email},
+			"password":  {password},
+			"action":    {"deny"},
+		})
+		assert.Equal(t, http.StatusOK, statusCode)
+
+		statusCode, tokenResp := sendTokenV1Request(t, url.Values{
+			"grant_type":  {grantTypeDeviceCode},
+			"client_id":   {oauthDeviceClientID},
+			"device_code": {resp.Get("device_code").String()},
+		})
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "access_denied", tokenResp.Get("error").String())
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		email, password := gofakeit.Email(), generatePassword()
+		registerUserV1(t, email, password)
+
+		statusCode, resp := sendDeviceCodeV1Request(t, url.Values{"client_id": {oauthDeviceClientID}})
+		if !assert.Equal(t, http.StatusOK, statusCode) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, "http://localhost:8080/oauth/device", resp.Get("verification_uri").String())
+		assert.Positive(t, resp.Get("interval").Int())
+
+		tokenParams := url.Values{
+			"grant_type":  {grantTypeDeviceCode},
+			"client_id":   {oauthDeviceClientID},
+			"device_code": {resp.Get("device_code").String()},
+		}
+
+		statusCode, tokenResp := sendTokenV1Request(t, tokenParams)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "authorization_pending", tokenResp.Get("error").String())
+
+		// polling again right away is faster than the allowed interval
+		statusCode, tokenResp = sendTokenV1Request(t, tokenParams)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "slow_down", tokenResp.Get("error").String())
+
+		// users may type the code in lower case and without the dash
+		userCode := strings.ToLower(strings.ReplaceAll(resp.Get("user_code").String(), "-", ""))
+
+		var body string
+		statusCode, body = sendDeviceVerificationRequest(t, url.Values{
+			"user_code": {userCode},
+			"email":     {email},
+			"password":  {password},
+			"action":    {"approve"},
+		})
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Contains(t, body, "Device approved.")
+
+		statusCode, tokenResp = sendTokenV1Request(t, tokenParams)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.Equal(t, "Bearer", tokenResp.Get("token_type").String())
+		assert.True(t, tokenResp.Get("access_token").Exists(), "access_token is missing")
+		assert.True(t, tokenResp.Get("refresh_token").Exists(), "refresh_token is missing")
+
+		// tokens are issued only once per device code
+		statusCode, tokenResp = sendTokenV1Request(t, tokenParams)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "expired_token", tokenResp.Get("error").String())
+	})
+}
+
+func sendDeviceCodeV1Request(t *testing.T, params url.Values) (int, gjson.Result) {
+	resp, err := http.PostForm("http://localhost:8080/v1/oauth/device/code", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var body []byte
+	if body, err = io.ReadAll(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.StatusCode, gjson.ParseBytes(body)
+}
+
+func sendDeviceVerificationRequest(t *testing.T, params url.Values) (int, string) {
+	resp, err := http.PostForm("http://localhost:8080/oauth/device", params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var body []byte
+	if body, err = io.ReadAll(resp.Body); err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.StatusCode, string(body)
+}
@@ -0,0 +1,41 @@
+package test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOAuthTokenExchange(t *testing.T) {
+	t.Run("invalid subject token", func(t *testing.T) {
+		target := registerUserV1(t, gofakeit.Email(), generatePassword())
+
+		statusCode, resp := sendTokenV1Request(t, url.Values{
+			"grant_type":         {"urn:ietf:params:oauth:grant-type:token-exchange"},
+			"subject_token":      {gofakeit.UUID()},
+			"subject_token_type": {"urn:ietf:params:oauth:token-type:access_token"},
+			"requested_subject":  {target.UserID},
+		})
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "invalid_grant", resp.Get("error").String())
+		assert.Equal(t, "invalid subject_token", resp.Get("error_description").String())
+	})
+
+	t.Run("subject is not an admin", func(t *testing.T) {
+		user := registerUserV1(t, gofakeit.Email(), generatePassword())
+		target := registerUserV1(t, gofakeit.Email(), generatePassword())
+
+		statusCode, resp := sendTokenV1Request(t, url.Values{
+			"grant_type":         {"urn:ietf:params:oauth:grant-type:token-exchange"},
+			"subject_token":      {user.AccessToken},
+			"subject_token_type": {"urn:ietf:params:oauth:token-type:access_token"},
+			"requested_subject":  {target.UserID},
+		})
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "invalid_grant", resp.Get("error").String())
+		assert.Equal(t, "not allowed to impersonate users", resp.Get("error_description").String())
+	})
+}
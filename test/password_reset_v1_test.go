@@ -0,0 +1,133 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestForgotPasswordV1(t *testing.T) {
+	t.Run("email is missing", func(t *testing.T) {
+		statusCode, resp := sendForgotPasswordV1Request(t, "")
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "email is required", resp.Get("error.message").String())
+	})
+
+	t.Run("unknown email", func(t *testing.T) {
+		email := gofakeit.Email()
+
+		statusCode, _ := sendForgotPasswordV1Request(t, email)
+
+		assert.Equal(t, http.StatusAccepted, statusCode)
+		assert.Never(t, func() bool { return len(readMails(t, email)) > 0 }, time.Second, 100*time.Millisecond)
+	})
+
+	t.Run("reset requested too recently", func(t *testing.T) {
+		email := gofakeit.Email()
+		registerUserV1(t, email, generatePassword())
+
+		statusCode, _ := sendForgotPasswordV1Request(t, email)
+		if !assert.Equal(t, http.StatusAccepted, statusCode) {
+			t.FailNow()
+		}
+
+		waitForMailToken(t, email)
+
+		// the answer is the same, but no second email is sent within auth.passwordReset.interval
+		statusCode, _ = sendForgotPasswordV1Request(t, email)
+		assert.Equal(t, http.StatusAccepted, statusCode)
+		assert.Never(t, func() bool { return len(readMails(t, email)) > 1 }, time.Second, 100*time.Millisecond)
+	})
+}
+
+func TestResetPasswordV1(t *testing.T) {
+	t.Run("invalid token", func(t *testing.T) {
+		statusCode, resp := sendResetPasswordV1Request(t, "unknown", generatePassword())
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "invalid or expired token", resp.Get("error.message").String())
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		email, oldPassword, newPassword := gofakeit.Email(), generatePassword(), generatePassword()
+		registered := registerUserV1(t, email, oldPassword)
+
+		statusCode, _ := sendForgotPasswordV1Request(t, email)
+		if !assert.Equal(t, http.StatusAccepted, statusCode) {
+			t.FailNow()
+		}
+
+		token := waitForMailToken(t, email)
+
+		statusCode, _ = sendResetPasswordV1Request(t, token, newPassword)
+		if !assert.Equal(t, http.StatusOK, statusCode) {
+			t.FailNow()
+		}
+
+		// tokens are single-use
+		statusCode, _ = sendResetPasswordV1Request(t, token, newPassword)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+
+		// sessions started before the reset are revoked
+		statusCode, _ = sendRefreshV1Request(t, bytes.NewReader(
+			[]byte(fmt.Sprintf(`{"refresh_token":"%s"}`, registered.RefreshToken)),
+		))
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+		statusCode, _ = sendLoginV1Request(t, bytes.NewReader(
+			[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, oldPassword)),
+		))
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+		assert.NotEmpty(t, loginUserV1(t, email, newPassword))
+	})
+
+	t.Run("password changed after the token was sent", func(t *testing.T) {
+		email, password := gofakeit.Email(), generatePassword()
+		registered := registerUserV1(t, email, password)
+
+		statusCode, _ := sendForgotPasswordV1Request(t, email)
+		if !assert.Equal(t, http.StatusAccepted, statusCode) {
+			t.FailNow()
+		}
+
+		token := waitForMailToken(t, email)
+
+		statusCode, _ = sendUpdatePasswordV1Request(t, registered.AccessToken, bytes.NewReader(
+			[]byte(fmt.Sprintf(`{"old_password":"%s","new_password":"%s"}`, password, generatePassword())),
+		))
+		if !assert.Equal(t, http.StatusOK, statusCode) {
+			t.FailNow()
+		}
+
+		statusCode, _ = sendResetPasswordV1Request(t, token, generatePassword())
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+	})
+}
+
+func sendForgotPasswordV1Request(t *testing.T, email string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/auth/password/forgot",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"email":"%s"}`, email))), "")
+}
+
+func sendResetPasswordV1Request(t *testing.T, token string, newPassword string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/auth/password/reset",
+		bytes.NewReader([]byte(fmt.Sprintf(`{"token":"%s","new_password":"%s"}`, token, newPassword))), "")
+}
+
+// waitForMailToken returns the token from the last message sent to the address, waiting for the message
+// because password resets are emailed in the background.
+func waitForMailToken(t *testing.T, to string) string {
+	if !assert.Eventually(t, func() bool { return len(readMails(t, to)) > 0 }, 5*time.Second, 50*time.Millisecond) {
+		t.FailNow()
+	}
+
+	return readMailToken(t, to)
+}
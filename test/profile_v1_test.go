@@ -0,0 +1,93 @@
+package test
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestProfileV1(t *testing.T) {
+	email, password := gofakeit.Email(), generatePassword()
+	registerUserV1(t, email, password)
+	accessToken := loginUserV1(t, email, password)
+
+	statusCode, resp := sendGetProfileV1Request(t, accessToken)
+	if !assert.Equal(t, http.StatusOK, statusCode) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, email, resp.Get("email").String())
+	assert.Empty(t, resp.Get("display_name").String())
+	assert.JSONEq(t, `{}`, resp.Get("metadata").Raw)
+
+	updatedAt := resp.Get("updated_at").Time()
+
+	// updated_at has a resolution of a microsecond
+	time.Sleep(10 * time.Millisecond)
+
+	statusCode, resp = sendUpdateProfileV1Request(t, accessToken, bytes.NewReader([]byte(
+		`{"display_name":"Jane Doe","locale":"en-GB","timezone":"Europe/London","metadata":{"theme":"dark"}}`,
+	)))
+	if !assert.Equal(t, http.StatusOK, statusCode) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "Jane Doe", resp.Get("display_name").String())
+	assert.Equal(t, "en-GB", resp.Get("locale").String())
+	assert.Equal(t, "Europe/London", resp.Get("timezone").String())
+	assert.JSONEq(t, `{"theme":"dark"}`, resp.Get("metadata").Raw)
+	assert.True(t, resp.Get("updated_at").Time().After(updatedAt), "updated_at is not bumped")
+
+	// absent fields are left unchanged and empty strings clear them
+	statusCode, resp = sendUpdateProfileV1Request(t, accessToken, bytes.NewReader([]byte(
+		`{"locale":""}`,
+	)))
+	if !assert.Equal(t, http.StatusOK, statusCode) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "Jane Doe", resp.Get("display_name").String())
+	assert.Empty(t, resp.Get("locale").String())
+	assert.Equal(t, "Europe/London", resp.Get("timezone").String())
+
+	statusCode, resp = sendUpdateProfileV1Request(t, accessToken, bytes.NewReader([]byte(
+		`{"timezone":"Mars/Olympus_Mons","avatar_url":"http://example.com/avatar.png"}`,
+	)))
+	if !assert.Equal(t, http.StatusBadRequest, statusCode) {
+		t.FailNow()
+	}
+
+	fields := make([]string, 0, 2)
+	for _, detail := range resp.Get("error.details").Array() {
+		fields = append(fields, detail.Get("field").String())
+	}
+
+	assert.ElementsMatch(t, []string{"timezone", "avatar_url"}, fields)
+
+	statusCode, resp = sendGetProfileV1Request(t, accessToken)
+	if !assert.Equal(t, http.StatusOK, statusCode) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "Europe/London", resp.Get("timezone").String())
+	assert.Empty(t, resp.Get("avatar_url").String())
+
+	t.Run("unauthorized", func(t *testing.T) {
+		statusCode, _ = sendGetProfileV1Request(t, "")
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+	})
+}
+
+func sendGetProfileV1Request(t *testing.T, accessToken string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodGet, "http://localhost:8080/v1/user/me", nil, accessToken)
+}
+
+func sendUpdateProfileV1Request(t *testing.T, accessToken string, body io.Reader) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodPatch, "http://localhost:8080/v1/user/me", body, accessToken)
+}
@@ -0,0 +1,54 @@
+package test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	t.Run("logins are limited per email", func(t *testing.T) {
+		email := gofakeit.Email()
+
+		login := func() *http.Response {
+			resp, err := http.Post(
+				"http://localhost:8080/v1/auth/login",
+				"application/json",
+				strings.NewReader(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, generatePassword())),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_ = resp.Body.Close()
+
+			return resp
+		}
+
+		for i := range 10 {
+			resp := login()
+			assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			assert.Equal(t, "10", resp.Header.Get("RateLimit-Limit"))
+			assert.Equal(t, strconv.Itoa(9-i), resp.Header.Get("RateLimit-Remaining"))
+		}
+
+		resp := login()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "0", resp.Header.Get("RateLimit-Remaining"))
+
+		retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+		assert.NoError(t, err)
+		assert.Positive(t, retryAfter)
+
+		// the limit is per email, other users can still log in from the same address
+		statusCode, _ := sendLoginV1Request(t, strings.NewReader(
+			fmt.Sprintf(`{"email":"%s","password":"%s"}`, gofakeit.Email(), generatePassword()),
+		))
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+	})
+}
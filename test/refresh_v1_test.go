@@ -35,7 +35,7 @@ func TestRefreshV1(t *testing.T) {
 	})
 
 	t.Run("modified refresh token", func(t *testing.T) {
-		registrationResp := registerUserV1(t, gofakeit.Email(), gofakeit.Name())
+		registrationResp := registerUserV1(t, gofakeit.Email(), generatePassword())
 
 		// modify the refresh token to provoke a signature error
 		parts := strings.Split(registrationResp.RefreshToken, ".")
@@ -62,7 +62,7 @@ func TestRefreshV1(t *testing.T) {
 	})
 
 	t.Run("positive case", func(t *testing.T) {
-		registrationResp := registerUserV1(t, gofakeit.Email(), gofakeit.Name())
+		registrationResp := registerUserV1(t, gofakeit.Email(), generatePassword())
 
 		statusCode, resp := sendRefreshV1Request(t, bytes.NewReader(
 			[]byte(fmt.Sprintf(`{"refresh_token":"%s"}`, registrationResp.RefreshToken)),
@@ -33,7 +33,7 @@ func TestRegisterUserV1(t *testing.T) {
 
 	t.Run("positive case", func(t *testing.T) {
 		statusCode, resp := sendRegistrationV1Request(t, bytes.NewReader(
-			[]byte(fmt.Sprintf(`{"email":"%s", "password":"1234"}`, gofakeit.Email())),
+			[]byte(fmt.Sprintf(`{"email":"%s", "password":"%s"}`, gofakeit.Email(), generatePassword())),
 		))
 
 		assert.Equal(t, http.StatusCreated, statusCode)
@@ -43,11 +43,23 @@ func TestRegisterUserV1(t *testing.T) {
 		assert.True(t, resp.Get("refresh_token").Exists(), "refresh_token is missing")
 	})
 
+	t.Run("password does not meet the policy", func(t *testing.T) {
+		statusCode, resp := sendRegistrationV1Request(t, bytes.NewReader(
+			[]byte(fmt.Sprintf(`{"email":"%s", "password":"1234"}`, gofakeit.Email())),
+		))
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "password does not meet the requirements", resp.Get("error.message").String())
+		assert.Equal(t, "password", resp.Get("error.details.0.field").String())
+		assert.Equal(t, "too_short", resp.Get("error.details.0.code").String())
+		assert.Equal(t, int64(8), resp.Get("error.details.0.params.min").Int())
+	})
+
 	t.Run("user already exists", func(t *testing.T) {
-		email := gofakeit.Email()
+		email, password := gofakeit.Email(), generatePassword()
 
 		statusCode, resp := sendRegistrationV1Request(t, bytes.NewReader(
-			[]byte(fmt.Sprintf(`{"email":"%s", "password":"1234"}`, email)),
+			[]byte(fmt.Sprintf(`{"email":"%s", "password":"%s"}`, email, password)),
 		))
 
 		assert.Equal(t, http.StatusCreated, statusCode)
@@ -57,7 +69,7 @@ func TestRegisterUserV1(t *testing.T) {
 		assert.True(t, resp.Get("refresh_token").Exists(), "refresh_token is missing")
 
 		statusCode, resp = sendRegistrationV1Request(t, bytes.NewReader(
-			[]byte(fmt.Sprintf(`{"email":"%s", "password":"1234"}`, email)),
+			[]byte(fmt.Sprintf(`{"email":"%s", "password":"%s"}`, email, password)),
 		))
 
 		assert.Equal(t, http.StatusBadRequest, statusCode)
@@ -86,3 +98,8 @@ func registerUserV1(t *testing.T, email string, password string) *handlers.Regis
 		RefreshToken: resp.Get("refresh_token").String(),
 	}
 }
+
+// generatePassword returns a random password that meets the password policy.
+func generatePassword() string {
+	return gofakeit.Password(true, true, true, true, false, 16)
+}
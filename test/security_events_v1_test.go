@@ -0,0 +1,67 @@
+package test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+)
+
+func TestSecurityEventsV1(t *testing.T) {
+	t.Run("access token is missing", func(t *testing.T) {
+		statusCode, _ := sendSecurityEventsV1Request(t, "")
+
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		email, password := gofakeit.Email(), generatePassword()
+		registrationResp := registerUserV1(t, email, password)
+
+		statusCode, _ := sendLoginV1Request(t, bytes.NewReader(
+			[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, generatePassword())),
+		))
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+
+		accessToken := loginUserV1(t, email, password)
+
+		statusCode, _ = sendRefreshV1Request(t, bytes.NewReader(
+			[]byte(fmt.Sprintf(`{"refresh_token":"%s"}`, registrationResp.RefreshToken)),
+		))
+		assert.Equal(t, http.StatusOK, statusCode)
+
+		var resp gjson.Result
+		statusCode, resp = sendSecurityEventsV1Request(t, accessToken)
+		if !assert.Equal(t, http.StatusOK, statusCode) {
+			t.FailNow()
+		}
+
+		events := resp.Get("events").Array()
+		if !assert.Len(t, events, 3) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, "token_refresh", events[0].Get("type").String())
+		assert.Equal(t, "success", events[0].Get("outcome").String())
+		assert.NotEmpty(t, events[0].Get("session_id").String())
+		assert.NotEmpty(t, events[0].Get("details.previous_session_id").String())
+
+		assert.Equal(t, "login", events[1].Get("type").String())
+		assert.Equal(t, "success", events[1].Get("outcome").String())
+		assert.Equal(t, "password", events[1].Get("details.method").String())
+		assert.NotEmpty(t, events[1].Get("ip").String())
+		assert.Equal(t, "Go-http-client/1.1", events[1].Get("user_agent").String())
+
+		assert.Equal(t, "login", events[2].Get("type").String())
+		assert.Equal(t, "failure", events[2].Get("outcome").String())
+		assert.Equal(t, "invalid_credentials", events[2].Get("reason").String())
+	})
+}
+
+func sendSecurityEventsV1Request(t *testing.T, accessToken string) (int, gjson.Result) {
+	return sendHttpRequest(t, http.MethodGet, "http://localhost:8080/v1/user/security-events", nil, accessToken)
+}
@@ -21,7 +21,7 @@ func TestUpdatePasswordV1(t *testing.T) {
 	})
 
 	t.Run("old password is missing", func(t *testing.T) {
-		email, password := gofakeit.Email(), gofakeit.Name()
+		email, password := gofakeit.Email(), generatePassword()
 
 		registerUserV1(t, email, password)
 		accessToken := loginUserV1(t, email, password)
@@ -33,7 +33,7 @@ func TestUpdatePasswordV1(t *testing.T) {
 	})
 
 	t.Run("new password is missing", func(t *testing.T) {
-		email, password := gofakeit.Email(), gofakeit.Name()
+		email, password := gofakeit.Email(), generatePassword()
 
 		registerUserV1(t, email, password)
 		accessToken := loginUserV1(t, email, password)
@@ -47,7 +47,7 @@ func TestUpdatePasswordV1(t *testing.T) {
 	})
 
 	t.Run("old and new passwords are equal", func(t *testing.T) {
-		email, password := gofakeit.Email(), gofakeit.Name()
+		email, password := gofakeit.Email(), generatePassword()
 
 		registerUserV1(t, email, password)
 		accessToken := loginUserV1(t, email, password)
@@ -60,13 +60,13 @@ func TestUpdatePasswordV1(t *testing.T) {
 	})
 
 	t.Run("invalid old password", func(t *testing.T) {
-		email, password := gofakeit.Email(), gofakeit.Name()
+		email, password := gofakeit.Email(), generatePassword()
 
 		registerUserV1(t, email, password)
 		accessToken := loginUserV1(t, email, password)
 
 		statusCode, resp := sendUpdatePasswordV1Request(t, accessToken, bytes.NewReader(
-			[]byte(fmt.Sprintf(`{"old_password":"%s", "new_password":"%s"}`, gofakeit.Name(), gofakeit.Name())),
+			[]byte(fmt.Sprintf(`{"old_password":"%s", "new_password":"%s"}`, generatePassword(), generatePassword())),
 		))
 
 		assert.Equal(t, http.StatusBadRequest, statusCode)
@@ -74,12 +74,12 @@ func TestUpdatePasswordV1(t *testing.T) {
 	})
 
 	t.Run("positive case", func(t *testing.T) {
-		email, password := gofakeit.Email(), gofakeit.Name()
+		email, password := gofakeit.Email(), generatePassword()
 
 		registerUserV1(t, email, password)
 		accessToken := loginUserV1(t, email, password)
 
-		newPassword := gofakeit.Name()
+		newPassword := generatePassword()
 		statusCode, _ := sendUpdatePasswordV1Request(t, accessToken, bytes.NewReader(
 			[]byte(fmt.Sprintf(`{"old_password":"%s", "new_password":"%s"}`, password, newPassword)),
 		))
@@ -90,7 +90,7 @@ func TestUpdatePasswordV1(t *testing.T) {
 
 		var resp gjson.Result
 		statusCode, resp = sendLoginV1Request(t, bytes.NewReader(
-			[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, gofakeit.Name())),
+			[]byte(fmt.Sprintf(`{"email":"%s","password":"%s"}`, email, generatePassword())),
 		))
 
 		assert.Equal(t, http.StatusUnauthorized, statusCode)
@@ -0,0 +1,114 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/brianvoe/gofakeit/v7"
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+
+	"github.com/riabininkf/http-auth-example/internal/webauthn"
+	"github.com/riabininkf/http-auth-example/internal/webauthn/webauthntest"
+)
+
+func TestWebAuthnV1(t *testing.T) {
+	registered := registerUserV1(t, gofakeit.Email(), generatePassword())
+	authenticator := webauthntest.NewAuthenticator("http://localhost:8080")
+
+	statusCode, resp := sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/user/webauthn/register/begin",
+		bytes.NewReader([]byte(`{}`)), registered.AccessToken)
+	if !assert.Equal(t, http.StatusOK, statusCode) {
+		t.FailNow()
+	}
+
+	var creationOptions webauthn.CredentialCreationOptions
+	if err := json.Unmarshal([]byte(resp.Get("publicKey").Raw), &creationOptions); err != nil {
+		t.Fatal(err)
+	}
+
+	registration, err := authenticator.Register(creationOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	statusCode, resp = sendWebAuthnRequest(t, "http://localhost:8080/v1/user/webauthn/register/finish",
+		registration, registered.AccessToken)
+	if !assert.Equal(t, http.StatusCreated, statusCode) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, registration.RawID, resp.Get("credential_id").String())
+
+	t.Run("registration challenge is single-use", func(t *testing.T) {
+		statusCode, resp := sendWebAuthnRequest(t, "http://localhost:8080/v1/user/webauthn/register/finish",
+			registration, registered.AccessToken)
+
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.Equal(t, "invalid or expired challenge", resp.Get("error.message").String())
+	})
+
+	t.Run("positive case", func(t *testing.T) {
+		assertion := beginWebAuthnLogin(t, authenticator)
+
+		statusCode, resp := sendWebAuthnRequest(t, "http://localhost:8080/v1/auth/webauthn/login/finish", assertion, "")
+		if !assert.Equal(t, http.StatusOK, statusCode) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, registered.UserID, resp.Get("user_id").String())
+		assert.NotEmpty(t, resp.Get("access_token").String())
+		assert.NotEmpty(t, resp.Get("refresh_token").String())
+
+		// the assertion cannot be replayed
+		statusCode, resp = sendWebAuthnRequest(t, "http://localhost:8080/v1/auth/webauthn/login/finish", assertion, "")
+
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+		assert.Equal(t, "invalid or expired challenge", resp.Get("error.message").String())
+	})
+
+	t.Run("unknown credential", func(t *testing.T) {
+		stranger := webauthntest.NewAuthenticator("http://localhost:8080")
+		if _, err := stranger.Register(creationOptions); err != nil {
+			t.Fatal(err)
+		}
+
+		statusCode, resp := sendWebAuthnRequest(t, "http://localhost:8080/v1/auth/webauthn/login/finish",
+			beginWebAuthnLogin(t, stranger), "")
+
+		assert.Equal(t, http.StatusUnauthorized, statusCode)
+		assert.Equal(t, "invalid credential", resp.Get("error.message").String())
+	})
+}
+
+// beginWebAuthnLogin starts a passkey login and returns the assertion of the authenticator.
+func beginWebAuthnLogin(t *testing.T, authenticator *webauthntest.Authenticator) webauthn.AuthenticationCredential {
+	statusCode, resp := sendHttpRequest(t, http.MethodPost, "http://localhost:8080/v1/auth/webauthn/login/begin",
+		bytes.NewReader([]byte(`{}`)), "")
+	if !assert.Equal(t, http.StatusOK, statusCode) {
+		t.FailNow()
+	}
+
+	var options webauthn.CredentialRequestOptions
+	if err := json.Unmarshal([]byte(resp.Get("publicKey").Raw), &options); err != nil {
+		t.Fatal(err)
+	}
+
+	assertion, err := authenticator.Login(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return assertion
+}
+
+func sendWebAuthnRequest(t *testing.T, url string, credential any, accessToken string) (int, gjson.Result) {
+	body, err := json.Marshal(credential)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return sendHttpRequest(t, http.MethodPost, url, bytes.NewReader(body), accessToken)
+}